@@ -3,14 +3,32 @@
 // license that can be found in the LICENSE file.
 
 // arrow2root converts the content of an ARROW file to a ROOT TTree.
+//
+// arrow2root accepts ARROW data in the IPC file format (a.k.a. Feather V2)
+// as well as in the IPC streaming format.
+// Records of an ARROW stream are converted one at a time, and each record is
+// written out as a separate cluster of entries of the output ROOT tree.
+// If the input file name is "-", the ARROW stream is read from stdin.
+//
+// Usage: arrow2root [OPTIONS] file.arrow
+//
+// Example:
+//
+//  $> arrow2root -o out.root -t mytree ./file.arrow
+//  $> some-arrow-producer | arrow2root -o out.root -
 package main // import "go-hep.org/x/hep/cmd/arrow2root"
 
 import (
+	"bufio"
+	"bytes"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
 	"github.com/apache/arrow/go/arrow/arrio"
 	"github.com/apache/arrow/go/arrow/ipc"
 	"github.com/apache/arrow/go/arrow/memory"
@@ -41,18 +59,25 @@ func main() {
 }
 
 func process(oname, tname, fname string) error {
-	f, err := os.Open(fname)
-	if err != nil {
-		return fmt.Errorf("could not open ARROW file %q: %w", fname, err)
+	var f *os.File
+	switch fname {
+	case "-":
+		f = os.Stdin
+	default:
+		var err error
+		f, err = os.Open(fname)
+		if err != nil {
+			return fmt.Errorf("could not open ARROW file %q: %w", fname, err)
+		}
+		defer f.Close()
 	}
-	defer f.Close()
 
 	mem := memory.NewGoAllocator()
-	r, err := ipc.NewFileReader(f, ipc.WithAllocator(mem))
+	r, err := newReader(f, mem)
 	if err != nil {
 		return fmt.Errorf("could not create ARROW IPC reader from %q: %w", fname, err)
 	}
-	defer r.Close()
+	defer r.close()
 
 	o, err := groot.Create(oname)
 	if err != nil {
@@ -60,12 +85,12 @@ func process(oname, tname, fname string) error {
 	}
 	defer o.Close()
 
-	tree, err := rarrow.NewFlatTreeWriter(o, tname, r.Schema(), rtree.WithTitle(tname))
+	tree, err := rarrow.NewTreeWriter(o, tname, r.schema, rtree.WithTitle(tname))
 	if err != nil {
 		return fmt.Errorf("could not create output ROOT tree %q: %w", tname, err)
 	}
 
-	_, err = arrio.Copy(tree, r)
+	_, err = arrio.Copy(tree, r.r)
 	if err != nil {
 		return fmt.Errorf("could not convert ARROW file to ROOT tree: %w", err)
 	}
@@ -82,3 +107,70 @@ func process(oname, tname, fname string) error {
 
 	return nil
 }
+
+// reader reads ARROW records from an IPC file or an IPC stream.
+type reader struct {
+	r      arrio.Reader
+	schema *arrow.Schema
+	close  func()
+}
+
+// arrowMagic is the magic string at the beginning of ARROW IPC files.
+var arrowMagic = []byte("ARROW1")
+
+// newReader creates a reader of ARROW records from the provided file.
+// newReader detects whether f holds an ARROW IPC file, that can be randomly
+// accessed, or an ARROW IPC stream, that is read sequentially.
+func newReader(f *os.File, mem memory.Allocator) (*reader, error) {
+	var (
+		buf   = bufio.NewReader(f)
+		magic []byte
+		err   error
+	)
+
+	magic, err = buf.Peek(len(arrowMagic))
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("could not read ARROW magic: %w", err)
+	}
+
+	if bytes.Equal(magic, arrowMagic) {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("could not rewind ARROW file: %w", err)
+		}
+		r, err := ipc.NewFileReader(f, ipc.WithAllocator(mem))
+		if err != nil {
+			return nil, err
+		}
+		return &reader{
+			r:      r,
+			schema: r.Schema(),
+			close:  func() { r.Close() },
+		}, nil
+	}
+
+	r, err := ipc.NewReader(buf, ipc.WithAllocator(mem))
+	if err != nil {
+		return nil, err
+	}
+	return &reader{
+		r:      streamReader{r},
+		schema: r.Schema(),
+		close:  r.Release,
+	}, nil
+}
+
+// streamReader adapts an ARROW IPC stream reader to the arrio.Reader
+// interface.
+type streamReader struct {
+	r *ipc.Reader
+}
+
+func (r streamReader) Read() (array.Record, error) {
+	if !r.r.Next() {
+		if err := r.r.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return r.r.Record(), nil
+}
@@ -11,6 +11,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/apache/arrow/go/arrow/arrio"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
 	"go-hep.org/x/hep/groot/rcmd"
 )
 
@@ -41,22 +44,10 @@ func TestConvert(t *testing.T) {
 			name: "testdata/lists.file.data",
 		},
 		{
-			name:   "testdata/structs.file.data",
-			panics: "invalid ARROW data-type: *arrow.StructType", // FIXME(sbinet): needs non-flat-tree writer support
+			name: "testdata/structs.file.data",
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			if tc.panics != "" {
-				defer func() {
-					err := recover()
-					if err == nil {
-						t.Fatalf("expected a panic (%s)", tc.panics)
-					}
-					if got, want := err.(error).Error(), tc.panics; got != want {
-						t.Fatalf("invalid panic message:\ngot= %v\nwant=%v", got, want)
-					}
-				}()
-			}
 			oname := filepath.Join(tmp, filepath.Base(tc.name)+".root")
 			tname := "tree"
 			err := process(oname, tname, tc.name)
@@ -84,3 +75,81 @@ func TestConvert(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertStream(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "arrow2root-")
+	if err != nil {
+		t.Fatalf("could not create tmpdir: %+v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	for _, name := range []string{
+		"testdata/primitives.file.data",
+		"testdata/lists.file.data",
+		"testdata/structs.file.data",
+	} {
+		t.Run(name, func(t *testing.T) {
+			sname := filepath.Join(tmp, filepath.Base(name)+".stream")
+			err := toStream(sname, name)
+			if err != nil {
+				t.Fatalf("could not convert %q to an ARROW stream: %+v", name, err)
+			}
+
+			oname := sname + ".root"
+			err = process(oname, "tree", sname)
+			if err != nil {
+				t.Fatalf("could not convert %q: %+v", sname, err)
+			}
+
+			out := new(strings.Builder)
+			err = rcmd.Dump(out, oname, true, nil)
+			if err != nil {
+				t.Fatalf("could not dump ROOT file %q: %+v", oname, err)
+			}
+
+			want, err := ioutil.ReadFile(name + ".txt")
+			if err != nil {
+				t.Fatalf("could not load reference file %q: %+v", name, err)
+			}
+
+			if got, want := out.String(), string(want); got != want {
+				t.Fatalf("invalid root-dump output:\ngot:\n%s\nwant:\n%s\n", got, want)
+			}
+		})
+	}
+}
+
+// toStream converts the ARROW file fname to the ARROW stream oname.
+func toStream(oname, fname string) error {
+	f, err := os.Open(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	mem := memory.NewGoAllocator()
+	r, err := ipc.NewFileReader(f, ipc.WithAllocator(mem))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	o, err := os.Create(oname)
+	if err != nil {
+		return err
+	}
+	defer o.Close()
+
+	w := ipc.NewWriter(o, ipc.WithSchema(r.Schema()), ipc.WithAllocator(mem))
+	_, err = arrio.Copy(w, r)
+	if err != nil {
+		return err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	return o.Close()
+}
@@ -102,6 +102,17 @@ func main() {
 		weight := lhevt.XWGTUP
 		evt.Weights.Add("0", weight)
 
+		// LHEF-3 named weights (scale and PDF variations, ...)
+		for _, w := range lhevt.Weights {
+			if w.Name == "" || len(w.Weights) == 0 {
+				continue
+			}
+			err = evt.Weights.Add(w.Name, w.Weights[0])
+			if err != nil {
+				log.Fatalf("error at event #%d: %v", ievt, err)
+			}
+		}
+
 		xsecval := -1.0
 		xsecerr := -1.0
 		switch math.Abs(float64(dec.Run.IDWTUP)) {
@@ -128,7 +128,7 @@ func process(oname, tname, fname string) error {
 	}
 	defer dst.Close()
 
-	t, err := rarrow.NewFlatTreeWriter(dst, tname, rec.Schema(), rtree.WithTitle(tname))
+	t, err := rarrow.NewTreeWriter(dst, tname, rec.Schema(), rtree.WithTitle(tname))
 	if err != nil {
 		return fmt.Errorf("could not create output ROOT tree %q: %w", tname, err)
 	}
@@ -11,6 +11,7 @@ import (
 	"io"
 	"io/ioutil"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -26,7 +27,16 @@ type document struct {
 	DataTypes  yaml.Node `yaml:"datatypes"`
 }
 
+type component struct {
+	name string // C++ name
+	typ  string // Go name
+	doc  string
+	mbrs []member
+}
+
 type dtype struct {
+	name             string // C++ name
+	typ              string // Go name
 	descr            string
 	author           string `yaml:"Author"`
 	members          []member
@@ -43,6 +53,9 @@ type member struct {
 	Name string
 	Type string
 	Doc  string
+
+	Cxx     string // C++ name of the member
+	CxxType string // C++ type of the member
 }
 
 type extraCode struct {
@@ -52,6 +65,14 @@ type extraCode struct {
 	Impl      string `yaml:"implementation"`
 }
 
+// builtin describes how a C++ builtin type is handled by groot.
+type builtin struct {
+	rfunc  string // suffix of the rbytes.{R,W}Buffer methods
+	resize string // suffix of the rbytes.ResizeXXX function
+	enum   string // rmeta enum
+	size   int    // size in bytes of the C++ type
+}
+
 var (
 	builtins = map[string]string{
 		"bool":               "bool",
@@ -68,14 +89,38 @@ var (
 		"std::string":        "string",
 	}
 
+	// rbuiltins associates normalized C++ builtin types with their groot I/O.
+	rbuiltins = map[string]builtin{
+		"bool":          {"Bool", "Bool", "Bool", 1},
+		"short":         {"I16", "I16", "Short", 2},
+		"int":           {"I32", "I32", "Int", 4},
+		"long":          {"I64", "I64", "Long", 8},
+		"Long64_t":      {"I64", "I64", "Long64", 8},
+		"unsigned int":  {"U32", "U32", "UInt", 4},
+		"unsigned long": {"U64", "U64", "ULong", 8},
+		"ULong64_t":     {"U64", "U64", "ULong64", 8},
+		"float":         {"F32", "F32", "Float", 4},
+		"double":        {"F64", "F64", "Double", 8},
+		"string":        {"String", "Str", "STLstring", 32},
+	}
+
+	// cxxNames normalizes the names of C++ builtin types, as known to ROOT.
+	cxxNames = map[string]string{
+		"unsigned":           "unsigned int",
+		"long long":          "Long64_t",
+		"unsigned long long": "ULong64_t",
+		"std::string":        "string",
+	}
+
 	cxxMangle = strings.NewReplacer(
 		":", "_",
 		"<", "_",
 		">", "_",
 	)
 
-	stdArrayRe    = regexp.MustCompile(" *std::array *<([a-zA-Z0-9:]+) *, *([0-9]+)> *")
-	stdArrayDocRe = regexp.MustCompile(` *std::array *<([a-zA-Z0-9:]+) *, *([0-9]+)> *(\S+) *// *(.+)`)
+	stdArrayRe     = regexp.MustCompile(" *std::array *<([a-zA-Z0-9:]+) *, *([0-9]+)> *")
+	stdArrayDeclRe = regexp.MustCompile(`^std::array *<([a-zA-Z0-9: ]+?) *, *([0-9]+) *> *(\S+)$`)
+	stdArrayTypeRe = regexp.MustCompile(`^std::array *<([a-zA-Z0-9:_ ]+?) *, *([0-9]+) *>$`)
 )
 
 type generator struct {
@@ -85,9 +130,25 @@ type generator struct {
 	pkg string
 	doc document
 
-	comps map[string]string
-	types map[string]string
+	comps map[string]*component
+	types map[string]*dtype
 	rules map[string]string
+
+	ctypes []*component // components, in declaration order
+	dtypes []*dtype     // data types, in declaration order
+
+	vecs    []vecType       // std::vector types needed by the generated code
+	vecset  map[string]bool // set of std::vector types already declared
+	sinfos  []string        // code registering the streamers of the generated types
+	runtime []*component    // PODIO types defined by the runtime
+}
+
+// vecType describes a std::vector<T> type.
+type vecType struct {
+	name  string   // Go name of the vector type
+	class string   // C++ name of the vector type
+	elem  string   // Go name of the element type
+	rt    *builtin // I/O of builtin element types
 }
 
 func newGenerator(w io.Writer, pkg, fname, rules string) (*generator, error) {
@@ -98,9 +159,30 @@ func newGenerator(w io.Writer, pkg, fname, rules string) (*generator, error) {
 			buf: new(bytes.Buffer),
 			pkg: pkg,
 
-			comps: make(map[string]string),
-			types: make(map[string]string),
+			comps: make(map[string]*component),
+			types: make(map[string]*dtype),
 			rules: make(map[string]string),
+
+			vecset: make(map[string]bool),
+			runtime: []*component{
+				{
+					name: "podio::ObjectID",
+					typ:  "ObjectID",
+					mbrs: []member{
+						{Name: "Index", Type: "int32", Cxx: "index", CxxType: "int"},
+						{Name: "CollectionID", Type: "int32", Cxx: "collectionID", CxxType: "int"},
+					},
+				},
+				{
+					name: "tuple<int,string,bool>",
+					typ:  "collectionTypeInfo",
+					mbrs: []member{
+						{Name: "ID", Type: "int32", Cxx: "_0", CxxType: "int"},
+						{Name: "Type", Type: "string", Cxx: "_1", CxxType: "string"},
+						{Name: "Subset", Type: "bool", Cxx: "_2", CxxType: "bool"},
+					},
+				},
+			},
 		}
 	)
 
@@ -125,11 +207,24 @@ func newGenerator(w io.Writer, pkg, fname, rules string) (*generator, error) {
 		gen.rules[toks[0]] = toks[1]
 	}
 
+	for _, c := range gen.runtime {
+		gen.comps[c.name] = c
+	}
+
 	gen.printf(`// Automatically generated. DO NOT EDIT.
 
 package %s
 
+import (
 `, pkg)
+	for _, imp := range runtimeImports {
+		if imp == "" {
+			gen.printf("\n")
+			continue
+		}
+		gen.printf("\t%q\n", imp)
+	}
+	gen.printf(")\n\n")
 
 	return gen, nil
 }
@@ -140,22 +235,52 @@ func (g *generator) generate() error {
 	for i := 0; i < len(g.doc.Components.Content); i += 2 {
 		key := g.doc.Components.Content[i]
 		val := g.doc.Components.Content[i+1]
-		err = g.genComponent(key, val)
+		err = g.parseComponent(key, val)
 		if err != nil {
 			return fmt.Errorf("could not handle component %q: %w", key.Value, err)
 		}
-
 	}
 
 	for i := 0; i < len(g.doc.DataTypes.Content); i += 2 {
 		key := g.doc.DataTypes.Content[i]
 		val := g.doc.DataTypes.Content[i+1]
-		err = g.genDataType(key, val)
+		err = g.parseDataType(key, val)
 		if err != nil {
 			return fmt.Errorf("could not handle datatype %q: %w", key.Value, err)
 		}
 	}
 
+	for _, c := range g.ctypes {
+		err = g.genComponent(c)
+		if err != nil {
+			return fmt.Errorf("could not generate component %q: %w", c.name, err)
+		}
+	}
+
+	for _, dt := range g.dtypes {
+		err = g.genDataType(dt)
+		if err != nil {
+			return fmt.Errorf("could not generate datatype %q: %w", dt.name, err)
+		}
+	}
+
+	g.printf("%s\n", runtimeCode)
+	for _, c := range g.runtime {
+		err = g.genROOT(c.name, c.typ, c.mbrs)
+		if err != nil {
+			return fmt.Errorf("could not generate ROOT I/O for %q: %w", c.name, err)
+		}
+	}
+	_, _ = g.vecOf("podio::ObjectID")
+	_, _ = g.vecOf("tuple<int,string,bool>")
+
+	for _, v := range g.vecs {
+		g.genVec(v)
+	}
+
+	g.genPodioTypes()
+	g.genStreamers()
+
 	out, err := format.Source(g.buf.Bytes())
 	if err != nil {
 		return fmt.Errorf("could not go/format generated code: %w", err)
@@ -193,57 +318,74 @@ func (g *generator) cxx2go(typ string) string {
 	return cxxMangle.Replace(typ)
 }
 
-func (g *generator) genComponent(knode, vnode *yaml.Node) error {
+func (g *generator) parseComponent(knode, vnode *yaml.Node) error {
 	var (
-		err  error
 		name = knode.Value
-		typ  = g.genTypeName(name)
-		doc  = strings.TrimSpace(knode.HeadComment)
+		c    = &component{
+			name: name,
+			typ:  g.genTypeName(name),
+			doc:  strings.TrimSpace(knode.HeadComment),
+		}
 	)
 
 	handleDoc := func(doc string) string {
 		return strings.TrimSpace(strings.Replace(doc, "#", "", 1))
 	}
 
-	doc = handleDoc(doc)
-	if doc != "" {
-		doc = "\n// " + doc
-	}
+	c.doc = handleDoc(c.doc)
 
-	g.comps[name] = typ
-	g.printf(`// %s%s
-type %s struct {
-`, name, doc, typ,
-	)
 	for i := 0; i < len(vnode.Content); i += 2 {
 		key := vnode.Content[i]
 		val := vnode.Content[i+1]
 		if key.Value == "ExtraCode" {
 			continue
 		}
-		doc := handleDoc(val.LineComment)
+		c.mbrs = append(c.mbrs, member{
+			Name:    strings.Title(key.Value),
+			Type:    g.genTypeName(val.Value),
+			Doc:     handleDoc(val.LineComment),
+			Cxx:     key.Value,
+			CxxType: strings.TrimSpace(val.Value),
+		})
+	}
+
+	g.comps[name] = c
+	g.ctypes = append(g.ctypes, c)
+
+	return nil
+}
+
+func (g *generator) genComponent(c *component) error {
+	doc := c.doc
+	if doc != "" {
+		doc = "\n// " + doc
+	}
+
+	g.printf(`// %s%s
+type %s struct {
+`, c.name, doc, c.typ,
+	)
+	for _, m := range c.mbrs {
+		doc := m.Doc
 		if doc != "" {
 			doc = "// " + doc
 		}
-		g.printf("\t%s %s%s\n", strings.Title(key.Value), g.genTypeName(val.Value), doc)
+		g.printf("\t%s %s%s\n", m.Name, m.Type, doc)
 	}
 
 	g.printf("}\n\n")
 
-	return err
+	return g.genROOT(c.name, c.typ, c.mbrs)
 }
 
-func (g *generator) genDataType(knode, vnode *yaml.Node) error {
+func (g *generator) parseDataType(knode, vnode *yaml.Node) error {
 	var (
 		err  error
 		name = knode.Value
 		typ  = g.genTypeName(name)
+		dt   = &dtype{name: name, typ: typ}
 	)
 
-	g.types[name] = typ
-
-	var dt dtype
-
 	for i := 0; i < len(vnode.Content); i += 2 {
 		key := vnode.Content[i]
 		val := vnode.Content[i+1]
@@ -299,13 +441,20 @@ func (g *generator) genDataType(knode, vnode *yaml.Node) error {
 		}
 	}
 
+	g.types[name] = dt
+	g.dtypes = append(g.dtypes, dt)
+
+	return nil
+}
+
+func (g *generator) genDataType(dt *dtype) error {
 	doc := dt.descr
 	if doc != "" {
 		doc = "\n// " + dt.descr
 	}
 	g.printf(`// %s%s
 type %s struct {
-`, name, doc, typ,
+`, dt.name, doc, dt.typ,
 	)
 	for _, m := range dt.members {
 		doc := ""
@@ -340,66 +489,579 @@ type %s struct {
 	}
 	g.printf("}\n\n")
 
-	return err
+	for _, rels := range [][]member{dt.one2oneRels, dt.one2manyRels} {
+		for _, m := range rels {
+			if _, ok := g.types[m.CxxType]; !ok {
+				return fmt.Errorf("unknown data type %q for relation %q", m.CxxType, m.Cxx)
+			}
+		}
+	}
+
+	g.genCollection(dt)
+
+	err := g.genData(dt)
+	if err != nil {
+		return err
+	}
+
+	return g.genBuffers(dt)
 }
 
 func (g *generator) genMembers(node *yaml.Node) ([]member, error) {
 	mbrs := make([]member, 0, len(node.Content))
 	for _, elem := range node.Content {
-		mbr := g.genMember(strings.TrimSpace(elem.Value))
+		mbr, err := g.genMember(strings.TrimSpace(elem.Value))
+		if err != nil {
+			return nil, err
+		}
 		mbr.Name = strings.Title(mbr.Name)
 		mbrs = append(mbrs, mbr)
 	}
 	return mbrs, nil
 }
 
-func (g *generator) genMember(v string) member {
+func (g *generator) genMember(v string) (member, error) {
 	var mbr member
-	handleDoc := func(doc string) string {
-		return strings.TrimSpace(strings.Replace(doc, "//", "", 1))
-	}
-
-	// remove spaces coming from builtins...
-	for _, cxx := range []struct {
-		k, v string
-	}{
-		{"unsigned long long", "uint64"},
-		{"unsigned long", "uint64"},
-		{"long long", "int64"},
-		{"unsigned int", "uint32"},
-		{"unsigned", "uint32"},
-	} {
-		if !strings.HasPrefix(v, cxx.k) {
-			continue
+
+	decl := v
+	if idx := strings.Index(v, "//"); idx >= 0 {
+		decl = v[:idx]
+		mbr.Doc = strings.TrimSpace(v[idx+len("//"):])
+	}
+	decl = strings.TrimSpace(decl)
+
+	if grps := stdArrayDeclRe.FindStringSubmatch(decl); grps != nil {
+		elem := strings.Join(strings.Fields(grps[1]), " ")
+		mbr.Type = fmt.Sprintf("[%s]%s", grps[2], g.genTypeName(elem))
+		mbr.Name = grps[3]
+		mbr.Cxx = grps[3]
+		mbr.CxxType = fmt.Sprintf("std::array<%s, %s>", elem, grps[2])
+		return mbr, nil
+	}
+
+	idx := strings.LastIndexAny(decl, " \t")
+	if idx < 0 {
+		return mbr, fmt.Errorf("invalid member declaration %q", v)
+	}
+
+	mbr.CxxType = strings.Join(strings.Fields(decl[:idx]), " ")
+	mbr.Cxx = strings.TrimSpace(decl[idx:])
+	mbr.Name = mbr.Cxx
+	mbr.Type = g.genTypeName(mbr.CxxType)
+
+	return mbr, nil
+}
+
+// cxxName returns the normalized C++ name of the provided type.
+func cxxName(typ string) string {
+	typ = strings.Join(strings.Fields(typ), " ")
+	if v, ok := cxxNames[typ]; ok {
+		return v
+	}
+	return typ
+}
+
+// cxxArray returns the element type and the length of the provided
+// std::array type.
+func cxxArray(typ string) (string, int, bool) {
+	grps := stdArrayTypeRe.FindStringSubmatch(typ)
+	if grps == nil {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(grps[2])
+	if err != nil {
+		return "", 0, false
+	}
+	return cxxName(grps[1]), n, true
+}
+
+// sizeOf returns the size in bytes of the provided C++ type.
+func (g *generator) sizeOf(typ string) int {
+	typ = cxxName(typ)
+	if elem, n, ok := cxxArray(typ); ok {
+		return n * g.sizeOf(elem)
+	}
+	if rt, ok := rbuiltins[typ]; ok {
+		return rt.size
+	}
+	n := 0
+	if c, ok := g.comps[typ]; ok {
+		for _, m := range c.mbrs {
+			n += g.sizeOf(m.CxxType)
 		}
-		v = strings.Replace(v, cxx.k, cxx.v, 1)
 	}
+	return n
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
 
-	for k, cxx := range builtins {
-		if !strings.HasPrefix(v, k) {
-			continue
+// genROOT generates the ROOT streaming methods and the streamer of the
+// provided type.
+func (g *generator) genROOT(class, typ string, mbrs []member) error {
+	var (
+		wbuf = new(strings.Builder)
+		rbuf = new(strings.Builder)
+		sbuf = new(strings.Builder)
+	)
+
+	for _, m := range mbrs {
+		var (
+			cxx = cxxName(m.CxxType)
+			doc = m.Doc
+		)
+		switch elem, n, ok := cxxArray(cxx); {
+		case ok:
+			if rt, ok := rbuiltins[elem]; ok {
+				if elem == "string" {
+					return fmt.Errorf("arrays of strings not supported (member %q)", m.Cxx)
+				}
+				fmt.Fprintf(wbuf, "w.WriteFastArray%s(o.%s[:])\n", rt.rfunc, m.Name)
+				fmt.Fprintf(rbuf, "r.ReadArray%s(o.%s[:])\n", rt.rfunc, m.Name)
+				fmt.Fprintf(sbuf,
+					"&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed(%q, %q), Type: rmeta.OffsetL + rmeta.%s, Size: %d, ArrLen: %d, ArrDim: 1, MaxIdx: [5]int32{%d, 0, 0, 0, 0}, EName: %q}.New()},\n",
+					m.Cxx, doc, rt.enum, n*rt.size, n, n, elem,
+				)
+				continue
+			}
+			if _, ok := g.comps[elem]; !ok {
+				return fmt.Errorf("unknown type %q for member %q", elem, m.Cxx)
+			}
+			fmt.Fprintf(wbuf, "for i := range o.%[1]s {\no.%[1]s[i].MarshalROOT(w)\n}\n", m.Name)
+			fmt.Fprintf(rbuf, "for i := range o.%[1]s {\no.%[1]s[i].UnmarshalROOT(r)\n}\n", m.Name)
+			fmt.Fprintf(sbuf,
+				"&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed(%q, %q), Type: rmeta.OffsetL + rmeta.Any, Size: %d, ArrLen: %d, ArrDim: 1, MaxIdx: [5]int32{%d, 0, 0, 0, 0}, EName: %q}.New()},\n",
+				m.Cxx, doc, g.sizeOf(cxx), n, n, elem,
+			)
+
+		case cxx == "string":
+			fmt.Fprintf(wbuf, "w.WriteSTLString(o.%s)\n", m.Name)
+			fmt.Fprintf(rbuf, "o.%s = r.ReadSTLString()\n", m.Name)
+			fmt.Fprintf(sbuf,
+				"&rdict.StreamerSTLstring{StreamerSTL: *rdict.NewCxxStreamerSTL(rdict.Element{Name: *rbase.NewNamed(%q, %q), Type: rmeta.STLstring, Size: 32, EName: \"string\"}.New(), rmeta.STLany, rmeta.STLstring)},\n",
+				m.Cxx, doc,
+			)
+
+		default:
+			if rt, ok := rbuiltins[cxx]; ok {
+				fmt.Fprintf(wbuf, "w.Write%s(o.%s)\n", rt.rfunc, m.Name)
+				fmt.Fprintf(rbuf, "o.%s = r.Read%s()\n", m.Name, rt.rfunc)
+				fmt.Fprintf(sbuf,
+					"&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed(%q, %q), Type: rmeta.%s, Size: %d, EName: %q}.New()},\n",
+					m.Cxx, doc, rt.enum, rt.size, cxx,
+				)
+				continue
+			}
+			if _, ok := g.comps[cxx]; !ok {
+				return fmt.Errorf("unknown type %q for member %q", cxx, m.Cxx)
+			}
+			fmt.Fprintf(wbuf, "o.%s.MarshalROOT(w)\n", m.Name)
+			fmt.Fprintf(rbuf, "o.%s.UnmarshalROOT(r)\n", m.Name)
+			fmt.Fprintf(sbuf,
+				"&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed(%q, %q), Type: rmeta.Any, Size: %d, EName: %q}.New()},\n",
+				m.Cxx, doc, g.sizeOf(cxx), cxx,
+			)
 		}
-		mbr.Type = cxx
-		v = strings.TrimSpace(strings.Replace(v, k, "", 1))
-		idx := strings.Index(v, " ")
-		mbr.Name = strings.TrimSpace(v[:idx])
-		mbr.Doc = handleDoc(strings.TrimSpace(v[idx:]))
-		return mbr
 	}
 
-	if grps := stdArrayDocRe.FindStringSubmatch(v); grps != nil {
-		mbr.Type = fmt.Sprintf("[%s]%s", grps[2], g.genTypeName(grps[1]))
-		mbr.Name = grps[3]
-		mbr.Doc = handleDoc(grps[4])
-		return mbr
+	g.printf(`func (*%[1]s) Class() string   { return %[2]q }
+func (*%[1]s) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *%[1]s) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+%[3]s	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *%[1]s) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+%[4]s	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+`, typ, class, wbuf.String(), rbuf.String())
+
+	g.sinfos = append(g.sinfos, fmt.Sprintf(
+		"rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo(%q, 1, 0, []rbytes.StreamerElement{\n%s}))\n",
+		class, sbuf.String(),
+	))
+
+	return nil
+}
+
+// vecOf returns the Go name of the std::vector<T> type for the provided
+// C++ type T.
+func (g *generator) vecOf(typ string) (string, error) {
+	var (
+		cxx = cxxName(typ)
+		vt  vecType
+	)
+
+	if rt, ok := rbuiltins[cxx]; ok {
+		elem := g.genTypeName(strings.Join(strings.Fields(typ), " "))
+		g.addVec(vecType{
+			name:  elem + "Vec",
+			class: "vector<" + cxx + ">",
+			elem:  elem,
+			rt:    &rt,
+		})
+		return elem + "Vec", nil
+	}
+
+	switch c, ok := g.comps[cxx]; {
+	case ok:
+		vt = vecType{
+			name:  lowerFirst(c.typ) + "Vec",
+			class: "vector<" + c.name + ">",
+			elem:  c.typ,
+		}
+
+	default:
+		return "", fmt.Errorf("unknown vector element type %q", typ)
+	}
+
+	g.addVec(vt)
+	return vt.name, nil
+}
+
+func (g *generator) addVec(vt vecType) {
+	if g.vecset[vt.name] {
+		return
+	}
+	g.vecset[vt.name] = true
+	g.vecs = append(g.vecs, vt)
+}
+
+func (g *generator) genVec(vt vecType) {
+	g.printf(`// %[1]s is a %[2]s.
+type %[1]s []%[3]s
+
+func (*%[1]s) Class() string { return %[2]q }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *%[1]s) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+`, vt.name, vt.class, vt.elem)
+
+	if vt.rt != nil {
+		g.printf("w.WriteFastArray%s(*v)\n", vt.rt.rfunc)
+	} else {
+		g.printf("for i := range *v {\n(*v)[i].MarshalROOT(w)\n}\n")
+	}
+
+	g.printf(`	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *%[1]s) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+`, vt.name)
+
+	if vt.rt != nil {
+		g.printf("*v = rbytes.Resize%s(*v, int(r.ReadI32()))\n", vt.rt.resize)
+		g.printf("r.ReadArray%s(*v)\n", vt.rt.rfunc)
+	} else {
+		g.printf(`n := int(r.ReadI32())
+if n > cap(*v) {
+	*v = make([]%[1]s, n)
+}
+*v = (*v)[:n]
+for i := range *v {
+	(*v)[i].UnmarshalROOT(r)
+}
+`, vt.elem)
+	}
+
+	g.printf(`	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+`)
+}
+
+func (g *generator) genCollection(dt *dtype) {
+	g.printf(`// %[1]sCollection is a collection of %[1]s.
+type %[1]sCollection struct {
+	Elems  []*%[1]s
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*%[1]sCollection) podioType() string { return %[2]q }
+func (c *%[1]sCollection) isSubset() bool  { return c.Subset }
+
+`, dt.typ, dt.name)
+}
+
+// dataMembers returns the members of the POD data part of a data type.
+func dataMembers(dt *dtype) []member {
+	mbrs := append([]member(nil), dt.members...)
+	for _, rels := range [][]member{dt.one2manyRels, dt.vecmbrs} {
+		for _, m := range rels {
+			mbrs = append(mbrs,
+				member{Name: m.Name + "Begin", Type: "uint32", Cxx: m.Cxx + "_begin", CxxType: "unsigned int"},
+				member{Name: m.Name + "End", Type: "uint32", Cxx: m.Cxx + "_end", CxxType: "unsigned int"},
+			)
+		}
+	}
+	return mbrs
+}
+
+func (g *generator) genData(dt *dtype) error {
+	var (
+		name = dt.typ + "Data"
+		mbrs = dataMembers(dt)
+	)
+
+	g.printf(`// %[1]s is the POD data part of %[2]s, as stored in PODIO files.
+type %[1]s struct {
+`, name, dt.typ)
+	for _, m := range mbrs {
+		g.printf("\t%s %s `groot:%q`\n", m.Name, m.Type, m.Cxx)
+	}
+	g.printf("}\n\n")
+
+	err := g.genROOT(dt.name+"Data", name, mbrs)
+	if err != nil {
+		return err
+	}
+
+	g.addVec(vecType{
+		name:  lowerFirst(name) + "Vec",
+		class: "vector<" + dt.name + "Data>",
+		elem:  name,
+	})
+
+	return nil
+}
+
+func (g *generator) genBuffers(dt *dtype) error {
+	var (
+		name  = lowerFirst(dt.typ) + "Buffers"
+		coll  = dt.typ + "Collection"
+		nrefs = len(dt.one2manyRels) + len(dt.one2oneRels)
+		vecs  = make([]string, len(dt.vecmbrs))
+	)
+
+	for i, m := range dt.vecmbrs {
+		v, err := g.vecOf(m.CxxType)
+		if err != nil {
+			return fmt.Errorf("could not handle vector member %q: %w", m.Cxx, err)
+		}
+		vecs[i] = v
+	}
+
+	g.printf(`// %[1]s holds the ROOT I/O buffers of %[2]s values.
+type %[1]s struct {
+	data %[3]sDataVec
+`, name, coll, lowerFirst(dt.typ))
+	if nrefs > 0 {
+		g.printf("refs [%d]objectIDVec\n", nrefs)
+	}
+	for i, v := range vecs {
+		g.printf("vec%d %s\n", i, v)
+	}
+	g.printf("objs objectIDVec // ObjectIDs of the elements of a subset collection\n}\n\n")
+
+	// reset
+	g.printf("func (b *%s) reset() {\nb.data = b.data[:0]\n", name)
+	if nrefs > 0 {
+		g.printf("for i := range b.refs {\nb.refs[i] = b.refs[i][:0]\n}\n")
+	}
+	for i := range vecs {
+		g.printf("b.vec%[1]d = b.vec%[1]d[:0]\n", i)
+	}
+	g.printf("b.objs = b.objs[:0]\n}\n\n")
+
+	// branches
+	g.printf(`func (b *%s) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+`, name)
+	for i := 0; i < nrefs; i++ {
+		g.printf("{name + \"#%[1]d\", &b.refs[%[1]d]},\n", i)
 	}
+	for i := range vecs {
+		g.printf("{name + \"_%[1]d\", &b.vec%[1]d},\n", i)
+	}
+	g.printf("}\n}\n\n")
+
+	// index
+	g.printf(`func (b *%[1]s) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*%[2]s)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+`, name, coll)
 
-	idx := strings.Index(v, " ")
-	mbr.Type = g.genTypeName(strings.TrimSpace(v[:idx]))
-	v = strings.TrimSpace(v[idx:])
-	idx = strings.Index(v, "//")
-	mbr.Name = strings.TrimSpace(v[:idx])
-	mbr.Doc = handleDoc(strings.TrimSpace(v[idx:]))
+	// fill
+	g.printf(`func (b *%[1]s) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*%[2]s)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := %[3]sData{
+`, name, coll, dt.typ)
+	for _, m := range dt.members {
+		g.printf("%[1]s: o.%[1]s,\n", m.Name)
+	}
+	g.printf("}\n")
+	for i, m := range dt.one2manyRels {
+		g.printf(`d.%[1]sBegin = uint32(len(b.refs[%[2]d]))
+for _, v := range o.%[1]s {
+	b.refs[%[2]d] = append(b.refs[%[2]d], ids.get(v))
+}
+d.%[1]sEnd = uint32(len(b.refs[%[2]d]))
+`, m.Name, i)
+	}
+	for i, m := range dt.vecmbrs {
+		g.printf(`d.%[1]sBegin = uint32(len(b.vec%[2]d))
+b.vec%[2]d = append(b.vec%[2]d, o.%[1]s...)
+d.%[1]sEnd = uint32(len(b.vec%[2]d))
+`, m.Name, i)
+	}
+	for i, m := range dt.one2oneRels {
+		g.printf("b.refs[%[1]d] = append(b.refs[%[1]d], ids.get(o.%[2]s))\n", len(dt.one2manyRels)+i, m.Name)
+	}
+	g.printf("b.data = append(b.data, d)\n}\n}\n\n")
 
-	return mbr
+	// create
+	g.printf(`func (b *%[1]s) create(subset bool) (interface{}, error) {
+	if subset {
+		return &%[2]s{Elems: make([]*%[3]s, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]%[3]s, len(b.data))
+		coll = &%[2]s{Elems: make([]*%[3]s, len(objs))}
+	)
+	for i := range b.data {
+`, name, coll, dt.typ)
+	if len(dt.members)+len(dt.vecmbrs) > 0 {
+		g.printf("d := &b.data[i]\n")
+	}
+	g.printf("o := &objs[i]\n")
+	for _, m := range dt.members {
+		g.printf("o.%[1]s = d.%[1]s\n", m.Name)
+	}
+	for i, m := range dt.vecmbrs {
+		g.printf(`if !podioRange(d.%[1]sBegin, d.%[1]sEnd, len(b.vec%[2]d)) {
+	return nil, fmt.Errorf("podio: invalid %[3]s range [%%d, %%d) (n=%%d)", d.%[1]sBegin, d.%[1]sEnd, len(b.vec%[2]d))
+}
+o.%[1]s = append([]%[4]s(nil), b.vec%[2]d[d.%[1]sBegin:d.%[1]sEnd]...)
+`, m.Name, i, m.Cxx, m.Type)
+	}
+	g.printf("coll.Elems[i] = o\n}\nreturn coll, nil\n}\n\n")
+
+	// resolve
+	g.printf(`func (b *%[1]s) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*%[2]s)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*%[3]s)
+		}
+		return nil
+	}
+`, name, coll, dt.typ)
+	for i, m := range dt.one2oneRels {
+		g.printf(`if len(b.refs[%[1]d]) != len(b.data) {
+	return fmt.Errorf("podio: invalid number of %[2]s relations (got=%%d, want=%%d)", len(b.refs[%[1]d]), len(b.data))
+}
+`, len(dt.one2manyRels)+i, m.Cxx)
+	}
+	if nrefs > 0 {
+		g.printf("for i := range b.data {\n")
+		if len(dt.one2manyRels) > 0 {
+			g.printf("d := &b.data[i]\n")
+		}
+		g.printf("o := c.Elems[i]\n")
+		for i, m := range dt.one2manyRels {
+			g.printf(`if !podioRange(d.%[1]sBegin, d.%[1]sEnd, len(b.refs[%[2]d])) {
+	return fmt.Errorf("podio: invalid %[3]s range [%%d, %%d) (n=%%d)", d.%[1]sBegin, d.%[1]sEnd, len(b.refs[%[2]d]))
+}
+for _, id := range b.refs[%[2]d][d.%[1]sBegin:d.%[1]sEnd] {
+	if v, ok := get(id).(*%[4]s); ok {
+		o.%[1]s = append(o.%[1]s, v)
+	}
+}
+`, m.Name, i, m.Cxx, m.Type)
+		}
+		for i, m := range dt.one2oneRels {
+			g.printf("o.%[1]s, _ = get(b.refs[%[2]d][i]).(*%[3]s)\n", m.Name, len(dt.one2manyRels)+i, m.Type)
+		}
+		g.printf("}\n")
+	}
+	g.printf("return nil\n}\n\n")
+
+	// at
+	g.printf(`func (b *%[1]s) at(coll interface{}, i int32) interface{} {
+	c := coll.(*%[2]s)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+`, name, coll)
+
+	return nil
+}
+
+func (g *generator) genPodioTypes() {
+	g.printf(`// podioTypes associates the C++ name of each data type with a function
+// creating its ROOT I/O buffers.
+var podioTypes = map[string]func() podioBuffers{
+`)
+	for _, dt := range g.dtypes {
+		g.printf("%q: func() podioBuffers { return new(%sBuffers) },\n", dt.name, lowerFirst(dt.typ))
+	}
+	g.printf("}\n\n")
+}
+
+func (g *generator) genStreamers() {
+	g.printf(`func init() {
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("podio::CollectionIDTable", 1, 0, []rbytes.StreamerElement{
+		rdict.NewCxxStreamerSTL(rdict.Element{Name: *rbase.NewNamed("m_collectionIDs", ""), Type: rmeta.Streamer, Size: 24, EName: "vector<int>"}.New(), rmeta.STLvector, rmeta.Int),
+		rdict.NewCxxStreamerSTL(rdict.Element{Name: *rbase.NewNamed("m_names", ""), Type: rmeta.Streamer, Size: 24, EName: "vector<string>"}.New(), rmeta.STLvector, rmeta.Object),
+	}))
+`)
+	for _, s := range g.sinfos {
+		g.printf("%s", s)
+	}
+	g.printf("}\n")
 }
@@ -4,6 +4,9 @@
 
 // Command podio-gen generates a complete EDM from a PODIO YAML file definition.
 //
+// The generated code contains the EDM types, their collections and a ROOT
+// Reader and Writer handling the PODIO "events" and "podio_metadata" trees.
+//
 // Usage: podio-gen [OPTIONS] edm.yaml
 //
 // Example:
@@ -6,14 +6,22 @@ package main
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 )
 
+var (
+	regen = flag.Bool("regen", false, "regenerate reference files")
+)
+
 func TestGenerator(t *testing.T) {
 	for _, tc := range []struct {
 		name  string
@@ -36,6 +44,10 @@ func TestGenerator(t *testing.T) {
 				t.Fatalf("could not process %q: %+v", tc.name, err)
 			}
 
+			if *regen {
+				ioutil.WriteFile(tc.want, got.Bytes(), 0644)
+			}
+
 			want, err := ioutil.ReadFile(tc.want)
 			if err != nil {
 				t.Fatalf("could not read reference file %q: %+v", tc.want, err)
@@ -48,6 +60,247 @@ func TestGenerator(t *testing.T) {
 	}
 }
 
+func TestRW(t *testing.T) {
+	dir, err := ioutil.TempDir("", "podio-gen-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const fname = "testdata/datalayout.yaml"
+
+	o, err := os.Create(filepath.Join(dir, "podio.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer o.Close()
+
+	err = process(o, "main", fname, "ex2::->ex2_,ex42::->ex42_")
+	if err != nil {
+		t.Fatalf("could not process %q: %+v", fname, err)
+	}
+
+	err = o.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte(rwMain), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	cmd := exec.Command("go", "build",
+		"-o", filepath.Join(dir, "a.out"),
+		filepath.Join(dir, "main.go"),
+		filepath.Join(dir, "podio.go"),
+	)
+	cmd.Stdout = buf
+	cmd.Stderr = buf
+	err = cmd.Run()
+	if err != nil {
+		t.Fatalf("could not run command %v:\n%v\nerr=%v",
+			cmd.Args,
+			buf.String(), err)
+	}
+	buf.Reset()
+
+	cmd = exec.Command("./a.out")
+	cmd.Dir = dir
+	cmd.Stdout = buf
+	cmd.Stderr = buf
+	err = cmd.Run()
+	if err != nil {
+		t.Fatalf("could not run command %v:\n%v\nerr=%v",
+			cmd.Args,
+			buf.String(), err)
+	}
+}
+
+const rwMain = `package main
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+
+	"go-hep.org/x/hep/groot"
+	"go-hep.org/x/hep/groot/rtree"
+)
+
+func main() {
+	const fname = "out.root"
+
+	var (
+		hits = []*ExampleHit{
+			{CellID: 0xdeadbeef, X: 1, Y: 2, Z: 3, Energy: 10},
+			{CellID: 2, X: 4, Y: 5, Z: 6, Energy: 20},
+			{CellID: 3, X: 7, Y: 8, Z: 9, Energy: 30},
+		}
+		clus = []*ExampleCluster{
+			{Energy: 30, Hits: []*ExampleHit{hits[0], hits[1]}},
+			{Energy: 30, Hits: []*ExampleHit{hits[2]}},
+		}
+		mcs = []*ExampleMC{
+			{Energy: 100, PDG: 11},
+			{Energy: 40, PDG: 22},
+			{Energy: 60, PDG: 11},
+		}
+		nss = []*ex42_ExampleWithNamespace{
+			{Data: ex2_NamespaceStruct{X: 1, Y: 2}},
+			{Data: ex2_NamespaceStruct{X: 3, Y: 4}},
+		}
+	)
+	clus[1].Clusters = []*ExampleCluster{clus[0]}
+	mcs[0].Daughters = []*ExampleMC{mcs[1], mcs[2]}
+	mcs[1].Parents = []*ExampleMC{mcs[0]}
+	mcs[2].Parents = []*ExampleMC{mcs[0]}
+
+	evts := []*Event{new(Event), new(Event)}
+	evts[0].Add("info", &EventInfoCollection{Elems: []*EventInfo{{Number: 42}}})
+	evts[0].Add("hits", &ExampleHitCollection{Elems: hits})
+	evts[0].Add("clusters", &ExampleClusterCollection{Elems: clus})
+	evts[0].Add("mcs", &ExampleMCCollection{Elems: mcs})
+	evts[0].Add("refs", &ExampleReferencingTypeCollection{Elems: []*ExampleReferencingType{
+		{Clusters: []*ExampleCluster{clus[1], clus[0]}},
+	}})
+	evts[0].Add("vecs", &ExampleWithVectorMemberCollection{Elems: []*ExampleWithVectorMember{
+		{Count: []int32{1, 2, 3}},
+		{},
+		{Count: []int32{4}},
+	}})
+	evts[0].Add("one", &ExampleWithOneRelationCollection{Elems: []*ExampleWithOneRelation{
+		{Cluster: clus[1]},
+		{},
+	}})
+	evts[0].Add("strs", &ExampleWithStringCollection{Elems: []*ExampleWithString{
+		{TheString: "hello"},
+		{TheString: ""},
+		{TheString: "world"},
+	}})
+	evts[0].Add("nss", &ex42_ExampleWithNamespaceCollection{Elems: nss})
+	evts[0].Add("nsrels", &ex42_ExampleWithARelationCollection{Elems: []*ex42_ExampleWithARelation{
+		{Number: 42, Ref: nss[1], Refs: []*ex42_ExampleWithNamespace{nss[0], nss[1]}},
+	}})
+	evts[0].Add("arrs", &ExampleWithArrayCollection{Elems: []*ExampleWithArray{
+		{
+			ArrayStruct:  NotSoSimpleStruct{Data: SimpleStruct{X: 1, Y: 2, Z: 3, P: [4]int32{4, 5, 6, 7}}},
+			MyArray:      [4]int32{1, 2, 3, 4},
+			StructArray:  [4]ex2_NamespaceStruct{{X: 1}, {Y: 2}, {X: 3}, {Y: 4}},
+		},
+	}})
+	evts[0].Add("sub", &ExampleHitCollection{Elems: []*ExampleHit{hits[2], hits[0]}, Subset: true})
+
+	evts[1].Add("info", &EventInfoCollection{Elems: []*EventInfo{{Number: 43}}})
+	evts[1].Add("hits", &ExampleHitCollection{Elems: []*ExampleHit{{CellID: 4, Energy: 5}}})
+
+	f, err := groot.Create(fname)
+	if err != nil {
+		log.Fatalf("could not create ROOT file: %+v", err)
+	}
+	defer f.Close()
+
+	w := NewWriter(f)
+	for i, evt := range evts {
+		err = w.Write(evt)
+		if err != nil {
+			log.Fatalf("could not write event #%d: %+v", i, err)
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		log.Fatalf("could not close PODIO writer: %+v", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		log.Fatalf("could not close ROOT file: %+v", err)
+	}
+
+	// the second event only holds a subset of the collections:
+	// the missing ones are read back as empty collections.
+	for _, name := range evts[0].Names()[2:] {
+		coll := evts[0].Get(name)
+		switch coll := coll.(type) {
+		case *ExampleHitCollection:
+			evts[1].Add(name, &ExampleHitCollection{Elems: []*ExampleHit{}, Subset: coll.Subset})
+		default:
+			v := reflect.New(reflect.TypeOf(coll).Elem())
+			v.Elem().Field(0).Set(reflect.MakeSlice(v.Elem().Field(0).Type(), 0, 0))
+			evts[1].Add(name, v.Interface())
+		}
+	}
+
+	f, err = groot.Open(fname)
+	if err != nil {
+		log.Fatalf("could not open ROOT file: %+v", err)
+	}
+	defer f.Close()
+
+	o, err := f.Get("events")
+	if err != nil {
+		log.Fatalf("could not retrieve events tree: %+v", err)
+	}
+	for _, name := range []string{"hits", "clusters#0", "clusters#1", "vecs_0", "one#0", "nsrels#1", "sub_objIdx"} {
+		if o.(rtree.Tree).Branch(name) == nil {
+			log.Fatalf("could not find branch %q", name)
+		}
+	}
+
+	r, err := NewReader(f)
+	if err != nil {
+		log.Fatalf("could not create PODIO reader: %+v", err)
+	}
+
+	if got, want := r.Entries(), int64(len(evts)); got != want {
+		log.Fatalf("invalid number of events: got=%d, want=%d", got, want)
+	}
+
+	i := 0
+	err = r.Read(func(evt *Event) error {
+		want := evts[i]
+		i++
+		if got, want := evt.Names(), want.Names(); !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("invalid collection names:\ngot= %q\nwant=%q", got, want)
+		}
+		for _, name := range want.Names() {
+			if got, want := evt.Get(name), want.Get(name); !reflect.DeepEqual(got, want) {
+				return fmt.Errorf("invalid collection %q:\ngot= %+v\nwant=%+v", name, got, want)
+			}
+		}
+		if i > 1 {
+			return nil
+		}
+
+		var (
+			hits = evt.Get("hits").(*ExampleHitCollection)
+			clus = evt.Get("clusters").(*ExampleClusterCollection)
+			one  = evt.Get("one").(*ExampleWithOneRelationCollection)
+			sub  = evt.Get("sub").(*ExampleHitCollection)
+		)
+		if one.Elems[0].Cluster != clus.Elems[1] {
+			return fmt.Errorf("invalid one-to-one relation")
+		}
+		if clus.Elems[0].Hits[1] != hits.Elems[1] {
+			return fmt.Errorf("invalid one-to-many relation")
+		}
+		if sub.Elems[0] != hits.Elems[2] || sub.Elems[1] != hits.Elems[0] {
+			return fmt.Errorf("invalid subset collection")
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("could not read events: %+v", err)
+	}
+
+	if i != len(evts) {
+		log.Fatalf("invalid number of read events: got=%d, want=%d", i, len(evts))
+	}
+}
+`
+
 func txtDiff(got, want []byte) string {
 	diff := cmp.Diff(string(want), string(got))
 	var o strings.Builder
@@ -0,0 +1,523 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+// runtimeImports is the list of packages imported by the generated code.
+var runtimeImports = []string{
+	"fmt",
+	"strings",
+	"",
+	"go-hep.org/x/hep/groot/rbase",
+	"go-hep.org/x/hep/groot/rbytes",
+	"go-hep.org/x/hep/groot/rdict",
+	"go-hep.org/x/hep/groot/riofs",
+	"go-hep.org/x/hep/groot/rmeta",
+	"go-hep.org/x/hep/groot/rtree",
+	"go-hep.org/x/hep/groot/rvers",
+}
+
+// runtimeCode is the PODIO I/O machinery shared by all the generated
+// data types.
+const runtimeCode = `
+// ObjectID identifies an object stored in a PODIO collection.
+type ObjectID struct {
+	Index        int32 // index of the object in its collection
+	CollectionID int32 // ID of the collection holding the object
+}
+
+// invalidObjectID is the ObjectID of objects that are not stored in any
+// written collection.
+var invalidObjectID = ObjectID{Index: -2, CollectionID: -2}
+
+// CollectionIDTable associates the names of the collections of a PODIO file
+// with their IDs.
+type CollectionIDTable struct {
+	IDs   []int32
+	Names []string
+}
+
+func (*CollectionIDTable) Class() string   { return "podio::CollectionIDTable" }
+func (*CollectionIDTable) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *CollectionIDTable) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	o.WStreamROOT(w)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *CollectionIDTable) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.RStreamROOT(r)
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// WStreamROOT implements rbytes.WStreamer.
+// WStreamROOT writes the members of the table, without any version header,
+// as expected for top-level branches.
+func (o *CollectionIDTable) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.IDs)))
+		w.WriteFastArrayI32(o.IDs)
+		w.SetByteCount(pos, "vector<int>")
+	}
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.Names)))
+		for _, v := range o.Names {
+			w.WriteString(v)
+		}
+		w.SetByteCount(pos, "vector<string>")
+	}
+	return w.Err()
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (o *CollectionIDTable) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	{
+		start := r.Pos()
+		_, pos, bcnt := r.ReadVersion("vector<int>")
+		o.IDs = rbytes.ResizeI32(o.IDs, int(r.ReadI32()))
+		r.ReadArrayI32(o.IDs)
+		r.CheckByteCount(pos, bcnt, start, "vector<int>")
+	}
+	{
+		start := r.Pos()
+		_, pos, bcnt := r.ReadVersion("vector<string>")
+		o.Names = rbytes.ResizeStr(o.Names, int(r.ReadI32()))
+		for i := range o.Names {
+			o.Names[i] = r.ReadString()
+		}
+		r.CheckByteCount(pos, bcnt, start, "vector<string>")
+	}
+	return r.Err()
+}
+
+// collectionTypeInfo describes the type of a collection stored in a
+// PODIO file.
+type collectionTypeInfo struct {
+	ID     int32  // collection ID
+	Type   string // C++ type of the collection
+	Subset bool   // whether the collection is a subset collection
+}
+
+// podioBranch describes a ROOT branch holding (a part of) a collection.
+type podioBranch struct {
+	name string
+	ptr  interface{}
+}
+
+// podioIDs associates the objects of an event with their ObjectID.
+type podioIDs map[interface{}]ObjectID
+
+func (ids podioIDs) get(o interface{}) ObjectID {
+	id, ok := ids[o]
+	if !ok {
+		return invalidObjectID
+	}
+	return id
+}
+
+func podioRange(beg, end uint32, n int) bool {
+	return beg <= end && int(end) <= n
+}
+
+// podioCollection is the interface implemented by all generated collections.
+type podioCollection interface {
+	podioType() string
+	isSubset() bool
+}
+
+// podioBuffers is the interface implemented by the ROOT I/O buffers
+// of the generated collections.
+type podioBuffers interface {
+	// reset clears the buffers before an event is filled.
+	reset()
+
+	// branches returns the ROOT branches associated with the named collection.
+	branches(name string, subset bool) []podioBranch
+
+	// index records the ObjectIDs of the objects held by coll.
+	index(coll interface{}, id int32, ids podioIDs)
+
+	// fill fills the buffers with the content of coll.
+	fill(coll interface{}, ids podioIDs)
+
+	// create creates a new collection from the buffers.
+	create(subset bool) (interface{}, error)
+
+	// resolve resolves the relations of the objects held by coll.
+	resolve(coll interface{}, get func(id ObjectID) interface{}) error
+
+	// at returns the i-th object held by coll.
+	at(coll interface{}, i int32) interface{}
+}
+
+// podioColl describes a collection stored in a PODIO file.
+type podioColl struct {
+	name   string
+	id     int32
+	typ    string
+	subset bool
+	bufs   podioBuffers
+}
+
+// Event is a PODIO event: a set of named collections.
+type Event struct {
+	names []string
+	colls map[string]interface{}
+}
+
+// Names returns the names of the collections held by this event.
+func (evt *Event) Names() []string {
+	return evt.names
+}
+
+// Get returns the collection labelled name.
+func (evt *Event) Get(name string) interface{} {
+	return evt.colls[name]
+}
+
+// Has returns whether this event has a collection named name.
+func (evt *Event) Has(name string) bool {
+	_, ok := evt.colls[name]
+	return ok
+}
+
+// Add attaches the (pointer to the) collection coll to this event,
+// with the given name.
+// Add panics if there is already a collection labelled with the same name.
+func (evt *Event) Add(name string, coll interface{}) {
+	if _, dup := evt.colls[name]; dup {
+		panic(fmt.Errorf("podio: duplicate key %q", name))
+	}
+	evt.names = append(evt.names, name)
+	if evt.colls == nil {
+		evt.colls = make(map[string]interface{})
+	}
+	evt.colls[name] = coll
+}
+
+// Reader reads PODIO events from a ROOT file.
+type Reader struct {
+	tree  rtree.Tree
+	opts  []rtree.ReadOption
+	colls []*podioColl
+}
+
+// NewReader creates a new PODIO reader, reading events from the
+// "events" and "podio_metadata" trees stored in dir.
+// Collections with types unknown to this package are skipped.
+func NewReader(dir riofs.Directory, opts ...rtree.ReadOption) (*Reader, error) {
+	var (
+		table CollectionIDTable
+		infos collectionTypeInfoVec
+	)
+
+	meta, err := podioTree(dir, "podio_metadata")
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := rtree.NewReader(meta, []rtree.ReadVar{
+		{Name: "CollectionIDs", Value: &table},
+		{Name: "CollectionTypeInfo", Value: &infos},
+	}, rtree.WithRange(0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("podio: could not create metadata reader: %w", err)
+	}
+	defer r.Close()
+
+	err = r.Read(func(rtree.RCtx) error { return nil })
+	if err != nil {
+		return nil, fmt.Errorf("podio: could not read metadata: %w", err)
+	}
+
+	if len(table.IDs) != len(table.Names) {
+		return nil, fmt.Errorf(
+			"podio: invalid collection IDs table (ids=%d, names=%d)",
+			len(table.IDs), len(table.Names),
+		)
+	}
+
+	tree, err := podioTree(dir, "events")
+	if err != nil {
+		return nil, err
+	}
+
+	types := make(map[int32]collectionTypeInfo, len(infos))
+	for _, info := range infos {
+		types[info.ID] = info
+	}
+
+	rd := &Reader{tree: tree, opts: opts}
+	for i, name := range table.Names {
+		id := table.IDs[i]
+		info, ok := types[id]
+		if !ok {
+			return nil, fmt.Errorf("podio: no type information for collection %q", name)
+		}
+		typ := strings.TrimSuffix(info.Type, "Collection")
+		newBuffers, ok := podioTypes[typ]
+		if !ok {
+			continue
+		}
+		rd.colls = append(rd.colls, &podioColl{
+			name:   name,
+			id:     id,
+			typ:    typ,
+			subset: info.Subset,
+			bufs:   newBuffers(),
+		})
+	}
+
+	return rd, nil
+}
+
+func podioTree(dir riofs.Directory, name string) (rtree.Tree, error) {
+	o, err := dir.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("podio: could not retrieve tree %q: %w", name, err)
+	}
+
+	tree, ok := o.(rtree.Tree)
+	if !ok {
+		return nil, fmt.Errorf("podio: object %q is not a tree (type=%s)", name, o.Class())
+	}
+
+	return tree, nil
+}
+
+// Entries returns the number of events stored in the file.
+func (r *Reader) Entries() int64 {
+	return r.tree.Entries()
+}
+
+// Read reads the events selected by the reader options and calls f
+// for each of them.
+func (r *Reader) Read(f func(evt *Event) error) error {
+	var rvars []rtree.ReadVar
+	for _, c := range r.colls {
+		for _, b := range c.bufs.branches(c.name, c.subset) {
+			rvars = append(rvars, rtree.ReadVar{Name: b.name, Value: b.ptr})
+		}
+	}
+
+	rr, err := rtree.NewReader(r.tree, rvars, r.opts...)
+	if err != nil {
+		return fmt.Errorf("podio: could not create events reader: %w", err)
+	}
+	defer rr.Close()
+
+	var (
+		bufs  = make(map[int32]podioBuffers, len(r.colls))
+		colls = make(map[int32]interface{}, len(r.colls))
+		get   = func(id ObjectID) interface{} {
+			b, ok := bufs[id.CollectionID]
+			if !ok {
+				return nil
+			}
+			return b.at(colls[id.CollectionID], id.Index)
+		}
+	)
+	for _, c := range r.colls {
+		bufs[c.id] = c.bufs
+	}
+
+	err = rr.Read(func(ctx rtree.RCtx) error {
+		evt := new(Event)
+		for _, c := range r.colls {
+			coll, err := c.bufs.create(c.subset)
+			if err != nil {
+				return fmt.Errorf("podio: could not create collection %q: %w", c.name, err)
+			}
+			colls[c.id] = coll
+			evt.Add(c.name, coll)
+		}
+
+		for _, c := range r.colls {
+			err := c.bufs.resolve(colls[c.id], get)
+			if err != nil {
+				return fmt.Errorf("podio: could not resolve relations of collection %q: %w", c.name, err)
+			}
+		}
+
+		return f(evt)
+	})
+	if err != nil {
+		return fmt.Errorf("podio: could not read events: %w", err)
+	}
+
+	return nil
+}
+
+// Writer writes PODIO events to a ROOT file.
+type Writer struct {
+	dir   riofs.Directory
+	opts  []rtree.WriteOption
+	tree  rtree.Writer
+	colls []*podioColl
+	ids   podioIDs
+}
+
+// NewWriter creates a new PODIO writer, writing events to the
+// "events" and "podio_metadata" trees of dir.
+// The set of collections written to the file is defined by the first
+// written event.
+func NewWriter(dir riofs.Directory, opts ...rtree.WriteOption) *Writer {
+	return &Writer{
+		dir:  dir,
+		opts: opts,
+		ids:  make(podioIDs),
+	}
+}
+
+func (w *Writer) init(evt *Event) error {
+	var wvars []rtree.WriteVar
+	for i, name := range evt.Names() {
+		coll, ok := evt.Get(name).(podioCollection)
+		if !ok {
+			return fmt.Errorf("podio: invalid collection %q (type=%T)", name, evt.Get(name))
+		}
+		c := &podioColl{
+			name:   name,
+			id:     int32(i + 1),
+			typ:    coll.podioType(),
+			subset: coll.isSubset(),
+		}
+		c.bufs = podioTypes[c.typ]()
+		w.colls = append(w.colls, c)
+		for _, b := range c.bufs.branches(c.name, c.subset) {
+			wvars = append(wvars, rtree.WriteVar{Name: b.name, Value: b.ptr})
+		}
+	}
+
+	tree, err := rtree.NewWriter(w.dir, "events", wvars, w.opts...)
+	if err != nil {
+		return fmt.Errorf("podio: could not create events tree: %w", err)
+	}
+	w.tree = tree
+
+	return nil
+}
+
+// Write writes the provided event to the file.
+// Collections of the first event that are missing from evt are written
+// as empty collections.
+func (w *Writer) Write(evt *Event) error {
+	if w.tree == nil {
+		err := w.init(evt)
+		if err != nil {
+			return err
+		}
+	}
+
+	for k := range w.ids {
+		delete(w.ids, k)
+	}
+
+	known := make(map[string]struct{}, len(w.colls))
+	colls := make([]interface{}, len(w.colls))
+	for i, c := range w.colls {
+		c.bufs.reset()
+		known[c.name] = struct{}{}
+		if !evt.Has(c.name) {
+			continue
+		}
+		coll, ok := evt.Get(c.name).(podioCollection)
+		if !ok || coll.podioType() != c.typ || coll.isSubset() != c.subset {
+			return fmt.Errorf("podio: invalid collection %q (type=%T)", c.name, evt.Get(c.name))
+		}
+		colls[i] = coll
+		c.bufs.index(coll, c.id, w.ids)
+	}
+
+	for _, name := range evt.Names() {
+		if _, ok := known[name]; !ok {
+			return fmt.Errorf("podio: unknown collection %q", name)
+		}
+	}
+
+	for i, c := range w.colls {
+		if colls[i] == nil {
+			continue
+		}
+		c.bufs.fill(colls[i], w.ids)
+	}
+
+	_, err := w.tree.Write()
+	if err != nil {
+		return fmt.Errorf("podio: could not write event: %w", err)
+	}
+
+	return nil
+}
+
+// Close writes the PODIO metadata and closes the writer.
+// Close does not close the underlying ROOT file.
+func (w *Writer) Close() error {
+	if w.tree != nil {
+		err := w.tree.Close()
+		if err != nil {
+			return fmt.Errorf("podio: could not close events tree: %w", err)
+		}
+	}
+
+	var (
+		table CollectionIDTable
+		infos = make(collectionTypeInfoVec, 0, len(w.colls))
+	)
+	for _, c := range w.colls {
+		table.IDs = append(table.IDs, c.id)
+		table.Names = append(table.Names, c.name)
+		infos = append(infos, collectionTypeInfo{
+			ID:     c.id,
+			Type:   c.typ + "Collection",
+			Subset: c.subset,
+		})
+	}
+
+	meta, err := rtree.NewWriter(w.dir, "podio_metadata", []rtree.WriteVar{
+		{Name: "CollectionIDs", Value: &table},
+		{Name: "CollectionTypeInfo", Value: &infos},
+	})
+	if err != nil {
+		return fmt.Errorf("podio: could not create metadata tree: %w", err)
+	}
+	defer meta.Close()
+
+	_, err = meta.Write()
+	if err != nil {
+		return fmt.Errorf("podio: could not write metadata: %w", err)
+	}
+
+	err = meta.Close()
+	if err != nil {
+		return fmt.Errorf("podio: could not close metadata tree: %w", err)
+	}
+
+	return nil
+}
+`
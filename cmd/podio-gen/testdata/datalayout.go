@@ -2,6 +2,19 @@
 
 package podio
 
+import (
+	"fmt"
+	"strings"
+
+	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rdict"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/rmeta"
+	"go-hep.org/x/hep/groot/rtree"
+	"go-hep.org/x/hep/groot/rvers"
+)
+
 // SimpleStruct
 type SimpleStruct struct {
 	X int32
@@ -10,28 +23,267 @@ type SimpleStruct struct {
 	P [4]int32
 }
 
+func (*SimpleStruct) Class() string   { return "SimpleStruct" }
+func (*SimpleStruct) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *SimpleStruct) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.X)
+	w.WriteI32(o.Y)
+	w.WriteI32(o.Z)
+	w.WriteFastArrayI32(o.P[:])
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *SimpleStruct) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.X = r.ReadI32()
+	o.Y = r.ReadI32()
+	o.Z = r.ReadI32()
+	r.ReadArrayI32(o.P[:])
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
 // NotSoSimpleStruct
 type NotSoSimpleStruct struct {
 	Data SimpleStruct
 }
 
+func (*NotSoSimpleStruct) Class() string   { return "NotSoSimpleStruct" }
+func (*NotSoSimpleStruct) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *NotSoSimpleStruct) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	o.Data.MarshalROOT(w)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *NotSoSimpleStruct) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Data.UnmarshalROOT(r)
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
 // ex2::NamespaceStruct
 type ex2_NamespaceStruct struct {
 	X int32
 	Y int32
 }
 
+func (*ex2_NamespaceStruct) Class() string   { return "ex2::NamespaceStruct" }
+func (*ex2_NamespaceStruct) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ex2_NamespaceStruct) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.X)
+	w.WriteI32(o.Y)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ex2_NamespaceStruct) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.X = r.ReadI32()
+	o.Y = r.ReadI32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
 // ex2::NamespaceInNamespaceStruct
 type ex2_NamespaceInNamespaceStruct struct {
 	Data ex2_NamespaceStruct
 }
 
+func (*ex2_NamespaceInNamespaceStruct) Class() string   { return "ex2::NamespaceInNamespaceStruct" }
+func (*ex2_NamespaceInNamespaceStruct) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ex2_NamespaceInNamespaceStruct) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	o.Data.MarshalROOT(w)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ex2_NamespaceInNamespaceStruct) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Data.UnmarshalROOT(r)
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
 // EventInfo
 // Event info
 type EventInfo struct {
 	Number int32 // event number
 }
 
+// EventInfoCollection is a collection of EventInfo.
+type EventInfoCollection struct {
+	Elems  []*EventInfo
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*EventInfoCollection) podioType() string { return "EventInfo" }
+func (c *EventInfoCollection) isSubset() bool  { return c.Subset }
+
+// EventInfoData is the POD data part of EventInfo, as stored in PODIO files.
+type EventInfoData struct {
+	Number int32 `groot:"Number"`
+}
+
+func (*EventInfoData) Class() string   { return "EventInfoData" }
+func (*EventInfoData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *EventInfoData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.Number)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *EventInfoData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Number = r.ReadI32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// eventInfoBuffers holds the ROOT I/O buffers of EventInfoCollection values.
+type eventInfoBuffers struct {
+	data eventInfoDataVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *eventInfoBuffers) reset() {
+	b.data = b.data[:0]
+	b.objs = b.objs[:0]
+}
+
+func (b *eventInfoBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+	}
+}
+
+func (b *eventInfoBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*EventInfoCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *eventInfoBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*EventInfoCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := EventInfoData{
+			Number: o.Number,
+		}
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *eventInfoBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &EventInfoCollection{Elems: make([]*EventInfo, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]EventInfo, len(b.data))
+		coll = &EventInfoCollection{Elems: make([]*EventInfo, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.Number = d.Number
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *eventInfoBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*EventInfoCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*EventInfo)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (b *eventInfoBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*EventInfoCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
 // ExampleHit
 // Example Hit
 type ExampleHit struct {
@@ -42,6 +294,149 @@ type ExampleHit struct {
 	Energy float64 // measured energy deposit
 }
 
+// ExampleHitCollection is a collection of ExampleHit.
+type ExampleHitCollection struct {
+	Elems  []*ExampleHit
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*ExampleHitCollection) podioType() string { return "ExampleHit" }
+func (c *ExampleHitCollection) isSubset() bool  { return c.Subset }
+
+// ExampleHitData is the POD data part of ExampleHit, as stored in PODIO files.
+type ExampleHitData struct {
+	CellID uint64  `groot:"cellID"`
+	X      float64 `groot:"x"`
+	Y      float64 `groot:"y"`
+	Z      float64 `groot:"z"`
+	Energy float64 `groot:"energy"`
+}
+
+func (*ExampleHitData) Class() string   { return "ExampleHitData" }
+func (*ExampleHitData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ExampleHitData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteU64(o.CellID)
+	w.WriteF64(o.X)
+	w.WriteF64(o.Y)
+	w.WriteF64(o.Z)
+	w.WriteF64(o.Energy)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ExampleHitData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.CellID = r.ReadU64()
+	o.X = r.ReadF64()
+	o.Y = r.ReadF64()
+	o.Z = r.ReadF64()
+	o.Energy = r.ReadF64()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// exampleHitBuffers holds the ROOT I/O buffers of ExampleHitCollection values.
+type exampleHitBuffers struct {
+	data exampleHitDataVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *exampleHitBuffers) reset() {
+	b.data = b.data[:0]
+	b.objs = b.objs[:0]
+}
+
+func (b *exampleHitBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+	}
+}
+
+func (b *exampleHitBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*ExampleHitCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *exampleHitBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*ExampleHitCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := ExampleHitData{
+			CellID: o.CellID,
+			X:      o.X,
+			Y:      o.Y,
+			Z:      o.Z,
+			Energy: o.Energy,
+		}
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *exampleHitBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &ExampleHitCollection{Elems: make([]*ExampleHit, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]ExampleHit, len(b.data))
+		coll = &ExampleHitCollection{Elems: make([]*ExampleHit, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.CellID = d.CellID
+		o.X = d.X
+		o.Y = d.Y
+		o.Z = d.Z
+		o.Energy = d.Energy
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *exampleHitBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*ExampleHitCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*ExampleHit)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (b *exampleHitBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*ExampleHitCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
 // ExampleMC
 // Example MC-particle
 type ExampleMC struct {
@@ -51,6 +446,182 @@ type ExampleMC struct {
 	Daughters []*ExampleMC // daughters
 }
 
+// ExampleMCCollection is a collection of ExampleMC.
+type ExampleMCCollection struct {
+	Elems  []*ExampleMC
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*ExampleMCCollection) podioType() string { return "ExampleMC" }
+func (c *ExampleMCCollection) isSubset() bool  { return c.Subset }
+
+// ExampleMCData is the POD data part of ExampleMC, as stored in PODIO files.
+type ExampleMCData struct {
+	Energy         float64 `groot:"energy"`
+	PDG            int32   `groot:"PDG"`
+	ParentsBegin   uint32  `groot:"parents_begin"`
+	ParentsEnd     uint32  `groot:"parents_end"`
+	DaughtersBegin uint32  `groot:"daughters_begin"`
+	DaughtersEnd   uint32  `groot:"daughters_end"`
+}
+
+func (*ExampleMCData) Class() string   { return "ExampleMCData" }
+func (*ExampleMCData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ExampleMCData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteF64(o.Energy)
+	w.WriteI32(o.PDG)
+	w.WriteU32(o.ParentsBegin)
+	w.WriteU32(o.ParentsEnd)
+	w.WriteU32(o.DaughtersBegin)
+	w.WriteU32(o.DaughtersEnd)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ExampleMCData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Energy = r.ReadF64()
+	o.PDG = r.ReadI32()
+	o.ParentsBegin = r.ReadU32()
+	o.ParentsEnd = r.ReadU32()
+	o.DaughtersBegin = r.ReadU32()
+	o.DaughtersEnd = r.ReadU32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// exampleMCBuffers holds the ROOT I/O buffers of ExampleMCCollection values.
+type exampleMCBuffers struct {
+	data exampleMCDataVec
+	refs [2]objectIDVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *exampleMCBuffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.objs = b.objs[:0]
+}
+
+func (b *exampleMCBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+		{name + "#1", &b.refs[1]},
+	}
+}
+
+func (b *exampleMCBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*ExampleMCCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *exampleMCBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*ExampleMCCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := ExampleMCData{
+			Energy: o.Energy,
+			PDG:    o.PDG,
+		}
+		d.ParentsBegin = uint32(len(b.refs[0]))
+		for _, v := range o.Parents {
+			b.refs[0] = append(b.refs[0], ids.get(v))
+		}
+		d.ParentsEnd = uint32(len(b.refs[0]))
+		d.DaughtersBegin = uint32(len(b.refs[1]))
+		for _, v := range o.Daughters {
+			b.refs[1] = append(b.refs[1], ids.get(v))
+		}
+		d.DaughtersEnd = uint32(len(b.refs[1]))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *exampleMCBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &ExampleMCCollection{Elems: make([]*ExampleMC, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]ExampleMC, len(b.data))
+		coll = &ExampleMCCollection{Elems: make([]*ExampleMC, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.Energy = d.Energy
+		o.PDG = d.PDG
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *exampleMCBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*ExampleMCCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*ExampleMC)
+		}
+		return nil
+	}
+	for i := range b.data {
+		d := &b.data[i]
+		o := c.Elems[i]
+		if !podioRange(d.ParentsBegin, d.ParentsEnd, len(b.refs[0])) {
+			return fmt.Errorf("podio: invalid parents range [%d, %d) (n=%d)", d.ParentsBegin, d.ParentsEnd, len(b.refs[0]))
+		}
+		for _, id := range b.refs[0][d.ParentsBegin:d.ParentsEnd] {
+			if v, ok := get(id).(*ExampleMC); ok {
+				o.Parents = append(o.Parents, v)
+			}
+		}
+		if !podioRange(d.DaughtersBegin, d.DaughtersEnd, len(b.refs[1])) {
+			return fmt.Errorf("podio: invalid daughters range [%d, %d) (n=%d)", d.DaughtersBegin, d.DaughtersEnd, len(b.refs[1]))
+		}
+		for _, id := range b.refs[1][d.DaughtersBegin:d.DaughtersEnd] {
+			if v, ok := get(id).(*ExampleMC); ok {
+				o.Daughters = append(o.Daughters, v)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *exampleMCBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*ExampleMCCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
 // ExampleCluster
 // Cluster
 type ExampleCluster struct {
@@ -59,6 +630,177 @@ type ExampleCluster struct {
 	Clusters []*ExampleCluster // sub clusters used to create this cluster
 }
 
+// ExampleClusterCollection is a collection of ExampleCluster.
+type ExampleClusterCollection struct {
+	Elems  []*ExampleCluster
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*ExampleClusterCollection) podioType() string { return "ExampleCluster" }
+func (c *ExampleClusterCollection) isSubset() bool  { return c.Subset }
+
+// ExampleClusterData is the POD data part of ExampleCluster, as stored in PODIO files.
+type ExampleClusterData struct {
+	Energy        float64 `groot:"energy"`
+	HitsBegin     uint32  `groot:"Hits_begin"`
+	HitsEnd       uint32  `groot:"Hits_end"`
+	ClustersBegin uint32  `groot:"Clusters_begin"`
+	ClustersEnd   uint32  `groot:"Clusters_end"`
+}
+
+func (*ExampleClusterData) Class() string   { return "ExampleClusterData" }
+func (*ExampleClusterData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ExampleClusterData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteF64(o.Energy)
+	w.WriteU32(o.HitsBegin)
+	w.WriteU32(o.HitsEnd)
+	w.WriteU32(o.ClustersBegin)
+	w.WriteU32(o.ClustersEnd)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ExampleClusterData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Energy = r.ReadF64()
+	o.HitsBegin = r.ReadU32()
+	o.HitsEnd = r.ReadU32()
+	o.ClustersBegin = r.ReadU32()
+	o.ClustersEnd = r.ReadU32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// exampleClusterBuffers holds the ROOT I/O buffers of ExampleClusterCollection values.
+type exampleClusterBuffers struct {
+	data exampleClusterDataVec
+	refs [2]objectIDVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *exampleClusterBuffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.objs = b.objs[:0]
+}
+
+func (b *exampleClusterBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+		{name + "#1", &b.refs[1]},
+	}
+}
+
+func (b *exampleClusterBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*ExampleClusterCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *exampleClusterBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*ExampleClusterCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := ExampleClusterData{
+			Energy: o.Energy,
+		}
+		d.HitsBegin = uint32(len(b.refs[0]))
+		for _, v := range o.Hits {
+			b.refs[0] = append(b.refs[0], ids.get(v))
+		}
+		d.HitsEnd = uint32(len(b.refs[0]))
+		d.ClustersBegin = uint32(len(b.refs[1]))
+		for _, v := range o.Clusters {
+			b.refs[1] = append(b.refs[1], ids.get(v))
+		}
+		d.ClustersEnd = uint32(len(b.refs[1]))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *exampleClusterBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &ExampleClusterCollection{Elems: make([]*ExampleCluster, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]ExampleCluster, len(b.data))
+		coll = &ExampleClusterCollection{Elems: make([]*ExampleCluster, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.Energy = d.Energy
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *exampleClusterBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*ExampleClusterCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*ExampleCluster)
+		}
+		return nil
+	}
+	for i := range b.data {
+		d := &b.data[i]
+		o := c.Elems[i]
+		if !podioRange(d.HitsBegin, d.HitsEnd, len(b.refs[0])) {
+			return fmt.Errorf("podio: invalid Hits range [%d, %d) (n=%d)", d.HitsBegin, d.HitsEnd, len(b.refs[0]))
+		}
+		for _, id := range b.refs[0][d.HitsBegin:d.HitsEnd] {
+			if v, ok := get(id).(*ExampleHit); ok {
+				o.Hits = append(o.Hits, v)
+			}
+		}
+		if !podioRange(d.ClustersBegin, d.ClustersEnd, len(b.refs[1])) {
+			return fmt.Errorf("podio: invalid Clusters range [%d, %d) (n=%d)", d.ClustersBegin, d.ClustersEnd, len(b.refs[1]))
+		}
+		for _, id := range b.refs[1][d.ClustersBegin:d.ClustersEnd] {
+			if v, ok := get(id).(*ExampleCluster); ok {
+				o.Clusters = append(o.Clusters, v)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *exampleClusterBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*ExampleClusterCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
 // ExampleReferencingType
 // Referencing Type
 type ExampleReferencingType struct {
@@ -66,63 +808,2780 @@ type ExampleReferencingType struct {
 	Refs     []*ExampleReferencingType // refs into same type
 }
 
+// ExampleReferencingTypeCollection is a collection of ExampleReferencingType.
+type ExampleReferencingTypeCollection struct {
+	Elems  []*ExampleReferencingType
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*ExampleReferencingTypeCollection) podioType() string { return "ExampleReferencingType" }
+func (c *ExampleReferencingTypeCollection) isSubset() bool  { return c.Subset }
+
+// ExampleReferencingTypeData is the POD data part of ExampleReferencingType, as stored in PODIO files.
+type ExampleReferencingTypeData struct {
+	ClustersBegin uint32 `groot:"Clusters_begin"`
+	ClustersEnd   uint32 `groot:"Clusters_end"`
+	RefsBegin     uint32 `groot:"Refs_begin"`
+	RefsEnd       uint32 `groot:"Refs_end"`
+}
+
+func (*ExampleReferencingTypeData) Class() string   { return "ExampleReferencingTypeData" }
+func (*ExampleReferencingTypeData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ExampleReferencingTypeData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteU32(o.ClustersBegin)
+	w.WriteU32(o.ClustersEnd)
+	w.WriteU32(o.RefsBegin)
+	w.WriteU32(o.RefsEnd)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ExampleReferencingTypeData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.ClustersBegin = r.ReadU32()
+	o.ClustersEnd = r.ReadU32()
+	o.RefsBegin = r.ReadU32()
+	o.RefsEnd = r.ReadU32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// exampleReferencingTypeBuffers holds the ROOT I/O buffers of ExampleReferencingTypeCollection values.
+type exampleReferencingTypeBuffers struct {
+	data exampleReferencingTypeDataVec
+	refs [2]objectIDVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *exampleReferencingTypeBuffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.objs = b.objs[:0]
+}
+
+func (b *exampleReferencingTypeBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+		{name + "#1", &b.refs[1]},
+	}
+}
+
+func (b *exampleReferencingTypeBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*ExampleReferencingTypeCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *exampleReferencingTypeBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*ExampleReferencingTypeCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := ExampleReferencingTypeData{}
+		d.ClustersBegin = uint32(len(b.refs[0]))
+		for _, v := range o.Clusters {
+			b.refs[0] = append(b.refs[0], ids.get(v))
+		}
+		d.ClustersEnd = uint32(len(b.refs[0]))
+		d.RefsBegin = uint32(len(b.refs[1]))
+		for _, v := range o.Refs {
+			b.refs[1] = append(b.refs[1], ids.get(v))
+		}
+		d.RefsEnd = uint32(len(b.refs[1]))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *exampleReferencingTypeBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &ExampleReferencingTypeCollection{Elems: make([]*ExampleReferencingType, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]ExampleReferencingType, len(b.data))
+		coll = &ExampleReferencingTypeCollection{Elems: make([]*ExampleReferencingType, len(objs))}
+	)
+	for i := range b.data {
+		o := &objs[i]
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *exampleReferencingTypeBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*ExampleReferencingTypeCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*ExampleReferencingType)
+		}
+		return nil
+	}
+	for i := range b.data {
+		d := &b.data[i]
+		o := c.Elems[i]
+		if !podioRange(d.ClustersBegin, d.ClustersEnd, len(b.refs[0])) {
+			return fmt.Errorf("podio: invalid Clusters range [%d, %d) (n=%d)", d.ClustersBegin, d.ClustersEnd, len(b.refs[0]))
+		}
+		for _, id := range b.refs[0][d.ClustersBegin:d.ClustersEnd] {
+			if v, ok := get(id).(*ExampleCluster); ok {
+				o.Clusters = append(o.Clusters, v)
+			}
+		}
+		if !podioRange(d.RefsBegin, d.RefsEnd, len(b.refs[1])) {
+			return fmt.Errorf("podio: invalid Refs range [%d, %d) (n=%d)", d.RefsBegin, d.RefsEnd, len(b.refs[1]))
+		}
+		for _, id := range b.refs[1][d.RefsBegin:d.RefsEnd] {
+			if v, ok := get(id).(*ExampleReferencingType); ok {
+				o.Refs = append(o.Refs, v)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *exampleReferencingTypeBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*ExampleReferencingTypeCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
 // ExampleWithVectorMember
 // Type with a vector member
 type ExampleWithVectorMember struct {
 	Count []int32 // various ADC counts
 }
 
+// ExampleWithVectorMemberCollection is a collection of ExampleWithVectorMember.
+type ExampleWithVectorMemberCollection struct {
+	Elems  []*ExampleWithVectorMember
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*ExampleWithVectorMemberCollection) podioType() string { return "ExampleWithVectorMember" }
+func (c *ExampleWithVectorMemberCollection) isSubset() bool  { return c.Subset }
+
+// ExampleWithVectorMemberData is the POD data part of ExampleWithVectorMember, as stored in PODIO files.
+type ExampleWithVectorMemberData struct {
+	CountBegin uint32 `groot:"count_begin"`
+	CountEnd   uint32 `groot:"count_end"`
+}
+
+func (*ExampleWithVectorMemberData) Class() string   { return "ExampleWithVectorMemberData" }
+func (*ExampleWithVectorMemberData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ExampleWithVectorMemberData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteU32(o.CountBegin)
+	w.WriteU32(o.CountEnd)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ExampleWithVectorMemberData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.CountBegin = r.ReadU32()
+	o.CountEnd = r.ReadU32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// exampleWithVectorMemberBuffers holds the ROOT I/O buffers of ExampleWithVectorMemberCollection values.
+type exampleWithVectorMemberBuffers struct {
+	data exampleWithVectorMemberDataVec
+	vec0 int32Vec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *exampleWithVectorMemberBuffers) reset() {
+	b.data = b.data[:0]
+	b.vec0 = b.vec0[:0]
+	b.objs = b.objs[:0]
+}
+
+func (b *exampleWithVectorMemberBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "_0", &b.vec0},
+	}
+}
+
+func (b *exampleWithVectorMemberBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*ExampleWithVectorMemberCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *exampleWithVectorMemberBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*ExampleWithVectorMemberCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := ExampleWithVectorMemberData{}
+		d.CountBegin = uint32(len(b.vec0))
+		b.vec0 = append(b.vec0, o.Count...)
+		d.CountEnd = uint32(len(b.vec0))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *exampleWithVectorMemberBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &ExampleWithVectorMemberCollection{Elems: make([]*ExampleWithVectorMember, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]ExampleWithVectorMember, len(b.data))
+		coll = &ExampleWithVectorMemberCollection{Elems: make([]*ExampleWithVectorMember, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		if !podioRange(d.CountBegin, d.CountEnd, len(b.vec0)) {
+			return nil, fmt.Errorf("podio: invalid count range [%d, %d) (n=%d)", d.CountBegin, d.CountEnd, len(b.vec0))
+		}
+		o.Count = append([]int32(nil), b.vec0[d.CountBegin:d.CountEnd]...)
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *exampleWithVectorMemberBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*ExampleWithVectorMemberCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*ExampleWithVectorMember)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (b *exampleWithVectorMemberBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*ExampleWithVectorMemberCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
 // ExampleWithOneRelation
 // Type with one relation member
 type ExampleWithOneRelation struct {
 	Cluster *ExampleCluster // a particular cluster
 }
 
+// ExampleWithOneRelationCollection is a collection of ExampleWithOneRelation.
+type ExampleWithOneRelationCollection struct {
+	Elems  []*ExampleWithOneRelation
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*ExampleWithOneRelationCollection) podioType() string { return "ExampleWithOneRelation" }
+func (c *ExampleWithOneRelationCollection) isSubset() bool  { return c.Subset }
+
+// ExampleWithOneRelationData is the POD data part of ExampleWithOneRelation, as stored in PODIO files.
+type ExampleWithOneRelationData struct {
+}
+
+func (*ExampleWithOneRelationData) Class() string   { return "ExampleWithOneRelationData" }
+func (*ExampleWithOneRelationData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ExampleWithOneRelationData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ExampleWithOneRelationData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// exampleWithOneRelationBuffers holds the ROOT I/O buffers of ExampleWithOneRelationCollection values.
+type exampleWithOneRelationBuffers struct {
+	data exampleWithOneRelationDataVec
+	refs [1]objectIDVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *exampleWithOneRelationBuffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.objs = b.objs[:0]
+}
+
+func (b *exampleWithOneRelationBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+	}
+}
+
+func (b *exampleWithOneRelationBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*ExampleWithOneRelationCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *exampleWithOneRelationBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*ExampleWithOneRelationCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := ExampleWithOneRelationData{}
+		b.refs[0] = append(b.refs[0], ids.get(o.Cluster))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *exampleWithOneRelationBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &ExampleWithOneRelationCollection{Elems: make([]*ExampleWithOneRelation, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]ExampleWithOneRelation, len(b.data))
+		coll = &ExampleWithOneRelationCollection{Elems: make([]*ExampleWithOneRelation, len(objs))}
+	)
+	for i := range b.data {
+		o := &objs[i]
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *exampleWithOneRelationBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*ExampleWithOneRelationCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*ExampleWithOneRelation)
+		}
+		return nil
+	}
+	if len(b.refs[0]) != len(b.data) {
+		return fmt.Errorf("podio: invalid number of cluster relations (got=%d, want=%d)", len(b.refs[0]), len(b.data))
+	}
+	for i := range b.data {
+		o := c.Elems[i]
+		o.Cluster, _ = get(b.refs[0][i]).(*ExampleCluster)
+	}
+	return nil
+}
+
+func (b *exampleWithOneRelationBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*ExampleWithOneRelationCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
 // ExampleWithComponent
 // Type with one component
 type ExampleWithComponent struct {
 	Component NotSoSimpleStruct // a component
 }
 
-// ExampleForCyclicDependency1
-// Type for cyclic dependency
-type ExampleForCyclicDependency1 struct {
-	Ref *ExampleForCyclicDependency2 // a ref
+// ExampleWithComponentCollection is a collection of ExampleWithComponent.
+type ExampleWithComponentCollection struct {
+	Elems  []*ExampleWithComponent
+	Subset bool // whether the collection only references objects owned by other collections
 }
 
-// ExampleForCyclicDependency2
-// Type for cyclic dependency
-type ExampleForCyclicDependency2 struct {
-	Ref *ExampleForCyclicDependency1 // a ref
+func (*ExampleWithComponentCollection) podioType() string { return "ExampleWithComponent" }
+func (c *ExampleWithComponentCollection) isSubset() bool  { return c.Subset }
+
+// ExampleWithComponentData is the POD data part of ExampleWithComponent, as stored in PODIO files.
+type ExampleWithComponentData struct {
+	Component NotSoSimpleStruct `groot:"component"`
 }
 
-// ExampleWithString
-// Type with a string
-type ExampleWithString struct {
-	TheString string // the string
+func (*ExampleWithComponentData) Class() string   { return "ExampleWithComponentData" }
+func (*ExampleWithComponentData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ExampleWithComponentData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	o.Component.MarshalROOT(w)
+	return w.SetByteCount(pos, o.Class())
 }
 
-// ex42::ExampleWithNamespace
-// Type with namespace and namespaced member
-type ex42_ExampleWithNamespace struct {
-	Data ex2_NamespaceStruct // a component
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ExampleWithComponentData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Component.UnmarshalROOT(r)
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
 }
 
-// ex42::ExampleWithARelation
-// Type with namespace and namespaced relation
-type ex42_ExampleWithARelation struct {
-	Number float32                      // just a number
-	Ref    *ex42_ExampleWithNamespace   // a ref in a namespace
-	Refs   []*ex42_ExampleWithNamespace // multiple refs in a namespace
+// exampleWithComponentBuffers holds the ROOT I/O buffers of ExampleWithComponentCollection values.
+type exampleWithComponentBuffers struct {
+	data exampleWithComponentDataVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
 }
 
-// ExampleWithArray
-// Datatype with an array member
-type ExampleWithArray struct {
-	ArrayStruct       NotSoSimpleStruct      // component that contains an array
+func (b *exampleWithComponentBuffers) reset() {
+	b.data = b.data[:0]
+	b.objs = b.objs[:0]
+}
+
+func (b *exampleWithComponentBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+	}
+}
+
+func (b *exampleWithComponentBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*ExampleWithComponentCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *exampleWithComponentBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*ExampleWithComponentCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := ExampleWithComponentData{
+			Component: o.Component,
+		}
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *exampleWithComponentBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &ExampleWithComponentCollection{Elems: make([]*ExampleWithComponent, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]ExampleWithComponent, len(b.data))
+		coll = &ExampleWithComponentCollection{Elems: make([]*ExampleWithComponent, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.Component = d.Component
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *exampleWithComponentBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*ExampleWithComponentCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*ExampleWithComponent)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (b *exampleWithComponentBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*ExampleWithComponentCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// ExampleForCyclicDependency1
+// Type for cyclic dependency
+type ExampleForCyclicDependency1 struct {
+	Ref *ExampleForCyclicDependency2 // a ref
+}
+
+// ExampleForCyclicDependency1Collection is a collection of ExampleForCyclicDependency1.
+type ExampleForCyclicDependency1Collection struct {
+	Elems  []*ExampleForCyclicDependency1
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*ExampleForCyclicDependency1Collection) podioType() string {
+	return "ExampleForCyclicDependency1"
+}
+func (c *ExampleForCyclicDependency1Collection) isSubset() bool { return c.Subset }
+
+// ExampleForCyclicDependency1Data is the POD data part of ExampleForCyclicDependency1, as stored in PODIO files.
+type ExampleForCyclicDependency1Data struct {
+}
+
+func (*ExampleForCyclicDependency1Data) Class() string   { return "ExampleForCyclicDependency1Data" }
+func (*ExampleForCyclicDependency1Data) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ExampleForCyclicDependency1Data) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ExampleForCyclicDependency1Data) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// exampleForCyclicDependency1Buffers holds the ROOT I/O buffers of ExampleForCyclicDependency1Collection values.
+type exampleForCyclicDependency1Buffers struct {
+	data exampleForCyclicDependency1DataVec
+	refs [1]objectIDVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *exampleForCyclicDependency1Buffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.objs = b.objs[:0]
+}
+
+func (b *exampleForCyclicDependency1Buffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+	}
+}
+
+func (b *exampleForCyclicDependency1Buffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*ExampleForCyclicDependency1Collection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *exampleForCyclicDependency1Buffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*ExampleForCyclicDependency1Collection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := ExampleForCyclicDependency1Data{}
+		b.refs[0] = append(b.refs[0], ids.get(o.Ref))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *exampleForCyclicDependency1Buffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &ExampleForCyclicDependency1Collection{Elems: make([]*ExampleForCyclicDependency1, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]ExampleForCyclicDependency1, len(b.data))
+		coll = &ExampleForCyclicDependency1Collection{Elems: make([]*ExampleForCyclicDependency1, len(objs))}
+	)
+	for i := range b.data {
+		o := &objs[i]
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *exampleForCyclicDependency1Buffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*ExampleForCyclicDependency1Collection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*ExampleForCyclicDependency1)
+		}
+		return nil
+	}
+	if len(b.refs[0]) != len(b.data) {
+		return fmt.Errorf("podio: invalid number of ref relations (got=%d, want=%d)", len(b.refs[0]), len(b.data))
+	}
+	for i := range b.data {
+		o := c.Elems[i]
+		o.Ref, _ = get(b.refs[0][i]).(*ExampleForCyclicDependency2)
+	}
+	return nil
+}
+
+func (b *exampleForCyclicDependency1Buffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*ExampleForCyclicDependency1Collection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// ExampleForCyclicDependency2
+// Type for cyclic dependency
+type ExampleForCyclicDependency2 struct {
+	Ref *ExampleForCyclicDependency1 // a ref
+}
+
+// ExampleForCyclicDependency2Collection is a collection of ExampleForCyclicDependency2.
+type ExampleForCyclicDependency2Collection struct {
+	Elems  []*ExampleForCyclicDependency2
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*ExampleForCyclicDependency2Collection) podioType() string {
+	return "ExampleForCyclicDependency2"
+}
+func (c *ExampleForCyclicDependency2Collection) isSubset() bool { return c.Subset }
+
+// ExampleForCyclicDependency2Data is the POD data part of ExampleForCyclicDependency2, as stored in PODIO files.
+type ExampleForCyclicDependency2Data struct {
+}
+
+func (*ExampleForCyclicDependency2Data) Class() string   { return "ExampleForCyclicDependency2Data" }
+func (*ExampleForCyclicDependency2Data) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ExampleForCyclicDependency2Data) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ExampleForCyclicDependency2Data) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// exampleForCyclicDependency2Buffers holds the ROOT I/O buffers of ExampleForCyclicDependency2Collection values.
+type exampleForCyclicDependency2Buffers struct {
+	data exampleForCyclicDependency2DataVec
+	refs [1]objectIDVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *exampleForCyclicDependency2Buffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.objs = b.objs[:0]
+}
+
+func (b *exampleForCyclicDependency2Buffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+	}
+}
+
+func (b *exampleForCyclicDependency2Buffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*ExampleForCyclicDependency2Collection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *exampleForCyclicDependency2Buffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*ExampleForCyclicDependency2Collection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := ExampleForCyclicDependency2Data{}
+		b.refs[0] = append(b.refs[0], ids.get(o.Ref))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *exampleForCyclicDependency2Buffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &ExampleForCyclicDependency2Collection{Elems: make([]*ExampleForCyclicDependency2, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]ExampleForCyclicDependency2, len(b.data))
+		coll = &ExampleForCyclicDependency2Collection{Elems: make([]*ExampleForCyclicDependency2, len(objs))}
+	)
+	for i := range b.data {
+		o := &objs[i]
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *exampleForCyclicDependency2Buffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*ExampleForCyclicDependency2Collection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*ExampleForCyclicDependency2)
+		}
+		return nil
+	}
+	if len(b.refs[0]) != len(b.data) {
+		return fmt.Errorf("podio: invalid number of ref relations (got=%d, want=%d)", len(b.refs[0]), len(b.data))
+	}
+	for i := range b.data {
+		o := c.Elems[i]
+		o.Ref, _ = get(b.refs[0][i]).(*ExampleForCyclicDependency1)
+	}
+	return nil
+}
+
+func (b *exampleForCyclicDependency2Buffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*ExampleForCyclicDependency2Collection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// ExampleWithString
+// Type with a string
+type ExampleWithString struct {
+	TheString string // the string
+}
+
+// ExampleWithStringCollection is a collection of ExampleWithString.
+type ExampleWithStringCollection struct {
+	Elems  []*ExampleWithString
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*ExampleWithStringCollection) podioType() string { return "ExampleWithString" }
+func (c *ExampleWithStringCollection) isSubset() bool  { return c.Subset }
+
+// ExampleWithStringData is the POD data part of ExampleWithString, as stored in PODIO files.
+type ExampleWithStringData struct {
+	TheString string `groot:"theString"`
+}
+
+func (*ExampleWithStringData) Class() string   { return "ExampleWithStringData" }
+func (*ExampleWithStringData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ExampleWithStringData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteSTLString(o.TheString)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ExampleWithStringData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.TheString = r.ReadSTLString()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// exampleWithStringBuffers holds the ROOT I/O buffers of ExampleWithStringCollection values.
+type exampleWithStringBuffers struct {
+	data exampleWithStringDataVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *exampleWithStringBuffers) reset() {
+	b.data = b.data[:0]
+	b.objs = b.objs[:0]
+}
+
+func (b *exampleWithStringBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+	}
+}
+
+func (b *exampleWithStringBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*ExampleWithStringCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *exampleWithStringBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*ExampleWithStringCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := ExampleWithStringData{
+			TheString: o.TheString,
+		}
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *exampleWithStringBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &ExampleWithStringCollection{Elems: make([]*ExampleWithString, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]ExampleWithString, len(b.data))
+		coll = &ExampleWithStringCollection{Elems: make([]*ExampleWithString, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.TheString = d.TheString
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *exampleWithStringBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*ExampleWithStringCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*ExampleWithString)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (b *exampleWithStringBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*ExampleWithStringCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// ex42::ExampleWithNamespace
+// Type with namespace and namespaced member
+type ex42_ExampleWithNamespace struct {
+	Data ex2_NamespaceStruct // a component
+}
+
+// ex42_ExampleWithNamespaceCollection is a collection of ex42_ExampleWithNamespace.
+type ex42_ExampleWithNamespaceCollection struct {
+	Elems  []*ex42_ExampleWithNamespace
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*ex42_ExampleWithNamespaceCollection) podioType() string { return "ex42::ExampleWithNamespace" }
+func (c *ex42_ExampleWithNamespaceCollection) isSubset() bool  { return c.Subset }
+
+// ex42_ExampleWithNamespaceData is the POD data part of ex42_ExampleWithNamespace, as stored in PODIO files.
+type ex42_ExampleWithNamespaceData struct {
+	Data ex2_NamespaceStruct `groot:"data"`
+}
+
+func (*ex42_ExampleWithNamespaceData) Class() string   { return "ex42::ExampleWithNamespaceData" }
+func (*ex42_ExampleWithNamespaceData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ex42_ExampleWithNamespaceData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	o.Data.MarshalROOT(w)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ex42_ExampleWithNamespaceData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Data.UnmarshalROOT(r)
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// ex42_ExampleWithNamespaceBuffers holds the ROOT I/O buffers of ex42_ExampleWithNamespaceCollection values.
+type ex42_ExampleWithNamespaceBuffers struct {
+	data ex42_ExampleWithNamespaceDataVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *ex42_ExampleWithNamespaceBuffers) reset() {
+	b.data = b.data[:0]
+	b.objs = b.objs[:0]
+}
+
+func (b *ex42_ExampleWithNamespaceBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+	}
+}
+
+func (b *ex42_ExampleWithNamespaceBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*ex42_ExampleWithNamespaceCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *ex42_ExampleWithNamespaceBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*ex42_ExampleWithNamespaceCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := ex42_ExampleWithNamespaceData{
+			Data: o.Data,
+		}
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *ex42_ExampleWithNamespaceBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &ex42_ExampleWithNamespaceCollection{Elems: make([]*ex42_ExampleWithNamespace, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]ex42_ExampleWithNamespace, len(b.data))
+		coll = &ex42_ExampleWithNamespaceCollection{Elems: make([]*ex42_ExampleWithNamespace, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.Data = d.Data
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *ex42_ExampleWithNamespaceBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*ex42_ExampleWithNamespaceCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*ex42_ExampleWithNamespace)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (b *ex42_ExampleWithNamespaceBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*ex42_ExampleWithNamespaceCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// ex42::ExampleWithARelation
+// Type with namespace and namespaced relation
+type ex42_ExampleWithARelation struct {
+	Number float32                      // just a number
+	Ref    *ex42_ExampleWithNamespace   // a ref in a namespace
+	Refs   []*ex42_ExampleWithNamespace // multiple refs in a namespace
+}
+
+// ex42_ExampleWithARelationCollection is a collection of ex42_ExampleWithARelation.
+type ex42_ExampleWithARelationCollection struct {
+	Elems  []*ex42_ExampleWithARelation
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*ex42_ExampleWithARelationCollection) podioType() string { return "ex42::ExampleWithARelation" }
+func (c *ex42_ExampleWithARelationCollection) isSubset() bool  { return c.Subset }
+
+// ex42_ExampleWithARelationData is the POD data part of ex42_ExampleWithARelation, as stored in PODIO files.
+type ex42_ExampleWithARelationData struct {
+	Number    float32 `groot:"number"`
+	RefsBegin uint32  `groot:"refs_begin"`
+	RefsEnd   uint32  `groot:"refs_end"`
+}
+
+func (*ex42_ExampleWithARelationData) Class() string   { return "ex42::ExampleWithARelationData" }
+func (*ex42_ExampleWithARelationData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ex42_ExampleWithARelationData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteF32(o.Number)
+	w.WriteU32(o.RefsBegin)
+	w.WriteU32(o.RefsEnd)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ex42_ExampleWithARelationData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Number = r.ReadF32()
+	o.RefsBegin = r.ReadU32()
+	o.RefsEnd = r.ReadU32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// ex42_ExampleWithARelationBuffers holds the ROOT I/O buffers of ex42_ExampleWithARelationCollection values.
+type ex42_ExampleWithARelationBuffers struct {
+	data ex42_ExampleWithARelationDataVec
+	refs [2]objectIDVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *ex42_ExampleWithARelationBuffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.objs = b.objs[:0]
+}
+
+func (b *ex42_ExampleWithARelationBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+		{name + "#1", &b.refs[1]},
+	}
+}
+
+func (b *ex42_ExampleWithARelationBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*ex42_ExampleWithARelationCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *ex42_ExampleWithARelationBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*ex42_ExampleWithARelationCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := ex42_ExampleWithARelationData{
+			Number: o.Number,
+		}
+		d.RefsBegin = uint32(len(b.refs[0]))
+		for _, v := range o.Refs {
+			b.refs[0] = append(b.refs[0], ids.get(v))
+		}
+		d.RefsEnd = uint32(len(b.refs[0]))
+		b.refs[1] = append(b.refs[1], ids.get(o.Ref))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *ex42_ExampleWithARelationBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &ex42_ExampleWithARelationCollection{Elems: make([]*ex42_ExampleWithARelation, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]ex42_ExampleWithARelation, len(b.data))
+		coll = &ex42_ExampleWithARelationCollection{Elems: make([]*ex42_ExampleWithARelation, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.Number = d.Number
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *ex42_ExampleWithARelationBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*ex42_ExampleWithARelationCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*ex42_ExampleWithARelation)
+		}
+		return nil
+	}
+	if len(b.refs[1]) != len(b.data) {
+		return fmt.Errorf("podio: invalid number of ref relations (got=%d, want=%d)", len(b.refs[1]), len(b.data))
+	}
+	for i := range b.data {
+		d := &b.data[i]
+		o := c.Elems[i]
+		if !podioRange(d.RefsBegin, d.RefsEnd, len(b.refs[0])) {
+			return fmt.Errorf("podio: invalid refs range [%d, %d) (n=%d)", d.RefsBegin, d.RefsEnd, len(b.refs[0]))
+		}
+		for _, id := range b.refs[0][d.RefsBegin:d.RefsEnd] {
+			if v, ok := get(id).(*ex42_ExampleWithNamespace); ok {
+				o.Refs = append(o.Refs, v)
+			}
+		}
+		o.Ref, _ = get(b.refs[1][i]).(*ex42_ExampleWithNamespace)
+	}
+	return nil
+}
+
+func (b *ex42_ExampleWithARelationBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*ex42_ExampleWithARelationCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// ExampleWithArray
+// Datatype with an array member
+type ExampleWithArray struct {
+	ArrayStruct       NotSoSimpleStruct      // component that contains an array
 	MyArray           [4]int32               // array-member without space to test regex
 	AnotherArray2     [4]int32               // array-member with space to test regex
 	Snail_case_array  [4]int32               // snail case to test regex
 	Snail_case_Array3 [4]int32               // mixing things up for regex
 	StructArray       [4]ex2_NamespaceStruct // an array containing structs
 }
+
+// ExampleWithArrayCollection is a collection of ExampleWithArray.
+type ExampleWithArrayCollection struct {
+	Elems  []*ExampleWithArray
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*ExampleWithArrayCollection) podioType() string { return "ExampleWithArray" }
+func (c *ExampleWithArrayCollection) isSubset() bool  { return c.Subset }
+
+// ExampleWithArrayData is the POD data part of ExampleWithArray, as stored in PODIO files.
+type ExampleWithArrayData struct {
+	ArrayStruct       NotSoSimpleStruct      `groot:"arrayStruct"`
+	MyArray           [4]int32               `groot:"myArray"`
+	AnotherArray2     [4]int32               `groot:"anotherArray2"`
+	Snail_case_array  [4]int32               `groot:"snail_case_array"`
+	Snail_case_Array3 [4]int32               `groot:"snail_case_Array3"`
+	StructArray       [4]ex2_NamespaceStruct `groot:"structArray"`
+}
+
+func (*ExampleWithArrayData) Class() string   { return "ExampleWithArrayData" }
+func (*ExampleWithArrayData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ExampleWithArrayData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	o.ArrayStruct.MarshalROOT(w)
+	w.WriteFastArrayI32(o.MyArray[:])
+	w.WriteFastArrayI32(o.AnotherArray2[:])
+	w.WriteFastArrayI32(o.Snail_case_array[:])
+	w.WriteFastArrayI32(o.Snail_case_Array3[:])
+	for i := range o.StructArray {
+		o.StructArray[i].MarshalROOT(w)
+	}
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ExampleWithArrayData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.ArrayStruct.UnmarshalROOT(r)
+	r.ReadArrayI32(o.MyArray[:])
+	r.ReadArrayI32(o.AnotherArray2[:])
+	r.ReadArrayI32(o.Snail_case_array[:])
+	r.ReadArrayI32(o.Snail_case_Array3[:])
+	for i := range o.StructArray {
+		o.StructArray[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// exampleWithArrayBuffers holds the ROOT I/O buffers of ExampleWithArrayCollection values.
+type exampleWithArrayBuffers struct {
+	data exampleWithArrayDataVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *exampleWithArrayBuffers) reset() {
+	b.data = b.data[:0]
+	b.objs = b.objs[:0]
+}
+
+func (b *exampleWithArrayBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+	}
+}
+
+func (b *exampleWithArrayBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*ExampleWithArrayCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *exampleWithArrayBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*ExampleWithArrayCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := ExampleWithArrayData{
+			ArrayStruct:       o.ArrayStruct,
+			MyArray:           o.MyArray,
+			AnotherArray2:     o.AnotherArray2,
+			Snail_case_array:  o.Snail_case_array,
+			Snail_case_Array3: o.Snail_case_Array3,
+			StructArray:       o.StructArray,
+		}
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *exampleWithArrayBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &ExampleWithArrayCollection{Elems: make([]*ExampleWithArray, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]ExampleWithArray, len(b.data))
+		coll = &ExampleWithArrayCollection{Elems: make([]*ExampleWithArray, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.ArrayStruct = d.ArrayStruct
+		o.MyArray = d.MyArray
+		o.AnotherArray2 = d.AnotherArray2
+		o.Snail_case_array = d.Snail_case_array
+		o.Snail_case_Array3 = d.Snail_case_Array3
+		o.StructArray = d.StructArray
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *exampleWithArrayBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*ExampleWithArrayCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*ExampleWithArray)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (b *exampleWithArrayBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*ExampleWithArrayCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// ObjectID identifies an object stored in a PODIO collection.
+type ObjectID struct {
+	Index        int32 // index of the object in its collection
+	CollectionID int32 // ID of the collection holding the object
+}
+
+// invalidObjectID is the ObjectID of objects that are not stored in any
+// written collection.
+var invalidObjectID = ObjectID{Index: -2, CollectionID: -2}
+
+// CollectionIDTable associates the names of the collections of a PODIO file
+// with their IDs.
+type CollectionIDTable struct {
+	IDs   []int32
+	Names []string
+}
+
+func (*CollectionIDTable) Class() string   { return "podio::CollectionIDTable" }
+func (*CollectionIDTable) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *CollectionIDTable) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	o.WStreamROOT(w)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *CollectionIDTable) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.RStreamROOT(r)
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// WStreamROOT implements rbytes.WStreamer.
+// WStreamROOT writes the members of the table, without any version header,
+// as expected for top-level branches.
+func (o *CollectionIDTable) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.IDs)))
+		w.WriteFastArrayI32(o.IDs)
+		w.SetByteCount(pos, "vector<int>")
+	}
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.Names)))
+		for _, v := range o.Names {
+			w.WriteString(v)
+		}
+		w.SetByteCount(pos, "vector<string>")
+	}
+	return w.Err()
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (o *CollectionIDTable) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	{
+		start := r.Pos()
+		_, pos, bcnt := r.ReadVersion("vector<int>")
+		o.IDs = rbytes.ResizeI32(o.IDs, int(r.ReadI32()))
+		r.ReadArrayI32(o.IDs)
+		r.CheckByteCount(pos, bcnt, start, "vector<int>")
+	}
+	{
+		start := r.Pos()
+		_, pos, bcnt := r.ReadVersion("vector<string>")
+		o.Names = rbytes.ResizeStr(o.Names, int(r.ReadI32()))
+		for i := range o.Names {
+			o.Names[i] = r.ReadString()
+		}
+		r.CheckByteCount(pos, bcnt, start, "vector<string>")
+	}
+	return r.Err()
+}
+
+// collectionTypeInfo describes the type of a collection stored in a
+// PODIO file.
+type collectionTypeInfo struct {
+	ID     int32  // collection ID
+	Type   string // C++ type of the collection
+	Subset bool   // whether the collection is a subset collection
+}
+
+// podioBranch describes a ROOT branch holding (a part of) a collection.
+type podioBranch struct {
+	name string
+	ptr  interface{}
+}
+
+// podioIDs associates the objects of an event with their ObjectID.
+type podioIDs map[interface{}]ObjectID
+
+func (ids podioIDs) get(o interface{}) ObjectID {
+	id, ok := ids[o]
+	if !ok {
+		return invalidObjectID
+	}
+	return id
+}
+
+func podioRange(beg, end uint32, n int) bool {
+	return beg <= end && int(end) <= n
+}
+
+// podioCollection is the interface implemented by all generated collections.
+type podioCollection interface {
+	podioType() string
+	isSubset() bool
+}
+
+// podioBuffers is the interface implemented by the ROOT I/O buffers
+// of the generated collections.
+type podioBuffers interface {
+	// reset clears the buffers before an event is filled.
+	reset()
+
+	// branches returns the ROOT branches associated with the named collection.
+	branches(name string, subset bool) []podioBranch
+
+	// index records the ObjectIDs of the objects held by coll.
+	index(coll interface{}, id int32, ids podioIDs)
+
+	// fill fills the buffers with the content of coll.
+	fill(coll interface{}, ids podioIDs)
+
+	// create creates a new collection from the buffers.
+	create(subset bool) (interface{}, error)
+
+	// resolve resolves the relations of the objects held by coll.
+	resolve(coll interface{}, get func(id ObjectID) interface{}) error
+
+	// at returns the i-th object held by coll.
+	at(coll interface{}, i int32) interface{}
+}
+
+// podioColl describes a collection stored in a PODIO file.
+type podioColl struct {
+	name   string
+	id     int32
+	typ    string
+	subset bool
+	bufs   podioBuffers
+}
+
+// Event is a PODIO event: a set of named collections.
+type Event struct {
+	names []string
+	colls map[string]interface{}
+}
+
+// Names returns the names of the collections held by this event.
+func (evt *Event) Names() []string {
+	return evt.names
+}
+
+// Get returns the collection labelled name.
+func (evt *Event) Get(name string) interface{} {
+	return evt.colls[name]
+}
+
+// Has returns whether this event has a collection named name.
+func (evt *Event) Has(name string) bool {
+	_, ok := evt.colls[name]
+	return ok
+}
+
+// Add attaches the (pointer to the) collection coll to this event,
+// with the given name.
+// Add panics if there is already a collection labelled with the same name.
+func (evt *Event) Add(name string, coll interface{}) {
+	if _, dup := evt.colls[name]; dup {
+		panic(fmt.Errorf("podio: duplicate key %q", name))
+	}
+	evt.names = append(evt.names, name)
+	if evt.colls == nil {
+		evt.colls = make(map[string]interface{})
+	}
+	evt.colls[name] = coll
+}
+
+// Reader reads PODIO events from a ROOT file.
+type Reader struct {
+	tree  rtree.Tree
+	opts  []rtree.ReadOption
+	colls []*podioColl
+}
+
+// NewReader creates a new PODIO reader, reading events from the
+// "events" and "podio_metadata" trees stored in dir.
+// Collections with types unknown to this package are skipped.
+func NewReader(dir riofs.Directory, opts ...rtree.ReadOption) (*Reader, error) {
+	var (
+		table CollectionIDTable
+		infos collectionTypeInfoVec
+	)
+
+	meta, err := podioTree(dir, "podio_metadata")
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := rtree.NewReader(meta, []rtree.ReadVar{
+		{Name: "CollectionIDs", Value: &table},
+		{Name: "CollectionTypeInfo", Value: &infos},
+	}, rtree.WithRange(0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("podio: could not create metadata reader: %w", err)
+	}
+	defer r.Close()
+
+	err = r.Read(func(rtree.RCtx) error { return nil })
+	if err != nil {
+		return nil, fmt.Errorf("podio: could not read metadata: %w", err)
+	}
+
+	if len(table.IDs) != len(table.Names) {
+		return nil, fmt.Errorf(
+			"podio: invalid collection IDs table (ids=%d, names=%d)",
+			len(table.IDs), len(table.Names),
+		)
+	}
+
+	tree, err := podioTree(dir, "events")
+	if err != nil {
+		return nil, err
+	}
+
+	types := make(map[int32]collectionTypeInfo, len(infos))
+	for _, info := range infos {
+		types[info.ID] = info
+	}
+
+	rd := &Reader{tree: tree, opts: opts}
+	for i, name := range table.Names {
+		id := table.IDs[i]
+		info, ok := types[id]
+		if !ok {
+			return nil, fmt.Errorf("podio: no type information for collection %q", name)
+		}
+		typ := strings.TrimSuffix(info.Type, "Collection")
+		newBuffers, ok := podioTypes[typ]
+		if !ok {
+			continue
+		}
+		rd.colls = append(rd.colls, &podioColl{
+			name:   name,
+			id:     id,
+			typ:    typ,
+			subset: info.Subset,
+			bufs:   newBuffers(),
+		})
+	}
+
+	return rd, nil
+}
+
+func podioTree(dir riofs.Directory, name string) (rtree.Tree, error) {
+	o, err := dir.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("podio: could not retrieve tree %q: %w", name, err)
+	}
+
+	tree, ok := o.(rtree.Tree)
+	if !ok {
+		return nil, fmt.Errorf("podio: object %q is not a tree (type=%s)", name, o.Class())
+	}
+
+	return tree, nil
+}
+
+// Entries returns the number of events stored in the file.
+func (r *Reader) Entries() int64 {
+	return r.tree.Entries()
+}
+
+// Read reads the events selected by the reader options and calls f
+// for each of them.
+func (r *Reader) Read(f func(evt *Event) error) error {
+	var rvars []rtree.ReadVar
+	for _, c := range r.colls {
+		for _, b := range c.bufs.branches(c.name, c.subset) {
+			rvars = append(rvars, rtree.ReadVar{Name: b.name, Value: b.ptr})
+		}
+	}
+
+	rr, err := rtree.NewReader(r.tree, rvars, r.opts...)
+	if err != nil {
+		return fmt.Errorf("podio: could not create events reader: %w", err)
+	}
+	defer rr.Close()
+
+	var (
+		bufs  = make(map[int32]podioBuffers, len(r.colls))
+		colls = make(map[int32]interface{}, len(r.colls))
+		get   = func(id ObjectID) interface{} {
+			b, ok := bufs[id.CollectionID]
+			if !ok {
+				return nil
+			}
+			return b.at(colls[id.CollectionID], id.Index)
+		}
+	)
+	for _, c := range r.colls {
+		bufs[c.id] = c.bufs
+	}
+
+	err = rr.Read(func(ctx rtree.RCtx) error {
+		evt := new(Event)
+		for _, c := range r.colls {
+			coll, err := c.bufs.create(c.subset)
+			if err != nil {
+				return fmt.Errorf("podio: could not create collection %q: %w", c.name, err)
+			}
+			colls[c.id] = coll
+			evt.Add(c.name, coll)
+		}
+
+		for _, c := range r.colls {
+			err := c.bufs.resolve(colls[c.id], get)
+			if err != nil {
+				return fmt.Errorf("podio: could not resolve relations of collection %q: %w", c.name, err)
+			}
+		}
+
+		return f(evt)
+	})
+	if err != nil {
+		return fmt.Errorf("podio: could not read events: %w", err)
+	}
+
+	return nil
+}
+
+// Writer writes PODIO events to a ROOT file.
+type Writer struct {
+	dir   riofs.Directory
+	opts  []rtree.WriteOption
+	tree  rtree.Writer
+	colls []*podioColl
+	ids   podioIDs
+}
+
+// NewWriter creates a new PODIO writer, writing events to the
+// "events" and "podio_metadata" trees of dir.
+// The set of collections written to the file is defined by the first
+// written event.
+func NewWriter(dir riofs.Directory, opts ...rtree.WriteOption) *Writer {
+	return &Writer{
+		dir:  dir,
+		opts: opts,
+		ids:  make(podioIDs),
+	}
+}
+
+func (w *Writer) init(evt *Event) error {
+	var wvars []rtree.WriteVar
+	for i, name := range evt.Names() {
+		coll, ok := evt.Get(name).(podioCollection)
+		if !ok {
+			return fmt.Errorf("podio: invalid collection %q (type=%T)", name, evt.Get(name))
+		}
+		c := &podioColl{
+			name:   name,
+			id:     int32(i + 1),
+			typ:    coll.podioType(),
+			subset: coll.isSubset(),
+		}
+		c.bufs = podioTypes[c.typ]()
+		w.colls = append(w.colls, c)
+		for _, b := range c.bufs.branches(c.name, c.subset) {
+			wvars = append(wvars, rtree.WriteVar{Name: b.name, Value: b.ptr})
+		}
+	}
+
+	tree, err := rtree.NewWriter(w.dir, "events", wvars, w.opts...)
+	if err != nil {
+		return fmt.Errorf("podio: could not create events tree: %w", err)
+	}
+	w.tree = tree
+
+	return nil
+}
+
+// Write writes the provided event to the file.
+// Collections of the first event that are missing from evt are written
+// as empty collections.
+func (w *Writer) Write(evt *Event) error {
+	if w.tree == nil {
+		err := w.init(evt)
+		if err != nil {
+			return err
+		}
+	}
+
+	for k := range w.ids {
+		delete(w.ids, k)
+	}
+
+	known := make(map[string]struct{}, len(w.colls))
+	colls := make([]interface{}, len(w.colls))
+	for i, c := range w.colls {
+		c.bufs.reset()
+		known[c.name] = struct{}{}
+		if !evt.Has(c.name) {
+			continue
+		}
+		coll, ok := evt.Get(c.name).(podioCollection)
+		if !ok || coll.podioType() != c.typ || coll.isSubset() != c.subset {
+			return fmt.Errorf("podio: invalid collection %q (type=%T)", c.name, evt.Get(c.name))
+		}
+		colls[i] = coll
+		c.bufs.index(coll, c.id, w.ids)
+	}
+
+	for _, name := range evt.Names() {
+		if _, ok := known[name]; !ok {
+			return fmt.Errorf("podio: unknown collection %q", name)
+		}
+	}
+
+	for i, c := range w.colls {
+		if colls[i] == nil {
+			continue
+		}
+		c.bufs.fill(colls[i], w.ids)
+	}
+
+	_, err := w.tree.Write()
+	if err != nil {
+		return fmt.Errorf("podio: could not write event: %w", err)
+	}
+
+	return nil
+}
+
+// Close writes the PODIO metadata and closes the writer.
+// Close does not close the underlying ROOT file.
+func (w *Writer) Close() error {
+	if w.tree != nil {
+		err := w.tree.Close()
+		if err != nil {
+			return fmt.Errorf("podio: could not close events tree: %w", err)
+		}
+	}
+
+	var (
+		table CollectionIDTable
+		infos = make(collectionTypeInfoVec, 0, len(w.colls))
+	)
+	for _, c := range w.colls {
+		table.IDs = append(table.IDs, c.id)
+		table.Names = append(table.Names, c.name)
+		infos = append(infos, collectionTypeInfo{
+			ID:     c.id,
+			Type:   c.typ + "Collection",
+			Subset: c.subset,
+		})
+	}
+
+	meta, err := rtree.NewWriter(w.dir, "podio_metadata", []rtree.WriteVar{
+		{Name: "CollectionIDs", Value: &table},
+		{Name: "CollectionTypeInfo", Value: &infos},
+	})
+	if err != nil {
+		return fmt.Errorf("podio: could not create metadata tree: %w", err)
+	}
+	defer meta.Close()
+
+	_, err = meta.Write()
+	if err != nil {
+		return fmt.Errorf("podio: could not write metadata: %w", err)
+	}
+
+	err = meta.Close()
+	if err != nil {
+		return fmt.Errorf("podio: could not close metadata tree: %w", err)
+	}
+
+	return nil
+}
+
+func (*ObjectID) Class() string   { return "podio::ObjectID" }
+func (*ObjectID) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ObjectID) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.Index)
+	w.WriteI32(o.CollectionID)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ObjectID) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Index = r.ReadI32()
+	o.CollectionID = r.ReadI32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+func (*collectionTypeInfo) Class() string   { return "tuple<int,string,bool>" }
+func (*collectionTypeInfo) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *collectionTypeInfo) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.ID)
+	w.WriteSTLString(o.Type)
+	w.WriteBool(o.Subset)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *collectionTypeInfo) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.ID = r.ReadI32()
+	o.Type = r.ReadSTLString()
+	o.Subset = r.ReadBool()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// eventInfoDataVec is a vector<EventInfoData>.
+type eventInfoDataVec []EventInfoData
+
+func (*eventInfoDataVec) Class() string { return "vector<EventInfoData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *eventInfoDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *eventInfoDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]EventInfoData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// exampleHitDataVec is a vector<ExampleHitData>.
+type exampleHitDataVec []ExampleHitData
+
+func (*exampleHitDataVec) Class() string { return "vector<ExampleHitData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *exampleHitDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *exampleHitDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ExampleHitData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// exampleMCDataVec is a vector<ExampleMCData>.
+type exampleMCDataVec []ExampleMCData
+
+func (*exampleMCDataVec) Class() string { return "vector<ExampleMCData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *exampleMCDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *exampleMCDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ExampleMCData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// exampleClusterDataVec is a vector<ExampleClusterData>.
+type exampleClusterDataVec []ExampleClusterData
+
+func (*exampleClusterDataVec) Class() string { return "vector<ExampleClusterData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *exampleClusterDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *exampleClusterDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ExampleClusterData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// exampleReferencingTypeDataVec is a vector<ExampleReferencingTypeData>.
+type exampleReferencingTypeDataVec []ExampleReferencingTypeData
+
+func (*exampleReferencingTypeDataVec) Class() string { return "vector<ExampleReferencingTypeData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *exampleReferencingTypeDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *exampleReferencingTypeDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ExampleReferencingTypeData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// exampleWithVectorMemberDataVec is a vector<ExampleWithVectorMemberData>.
+type exampleWithVectorMemberDataVec []ExampleWithVectorMemberData
+
+func (*exampleWithVectorMemberDataVec) Class() string { return "vector<ExampleWithVectorMemberData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *exampleWithVectorMemberDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *exampleWithVectorMemberDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ExampleWithVectorMemberData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// int32Vec is a vector<int>.
+type int32Vec []int32
+
+func (*int32Vec) Class() string { return "vector<int>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *int32Vec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	w.WriteFastArrayI32(*v)
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *int32Vec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	*v = rbytes.ResizeI32(*v, int(r.ReadI32()))
+	r.ReadArrayI32(*v)
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// exampleWithOneRelationDataVec is a vector<ExampleWithOneRelationData>.
+type exampleWithOneRelationDataVec []ExampleWithOneRelationData
+
+func (*exampleWithOneRelationDataVec) Class() string { return "vector<ExampleWithOneRelationData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *exampleWithOneRelationDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *exampleWithOneRelationDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ExampleWithOneRelationData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// exampleWithComponentDataVec is a vector<ExampleWithComponentData>.
+type exampleWithComponentDataVec []ExampleWithComponentData
+
+func (*exampleWithComponentDataVec) Class() string { return "vector<ExampleWithComponentData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *exampleWithComponentDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *exampleWithComponentDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ExampleWithComponentData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// exampleForCyclicDependency1DataVec is a vector<ExampleForCyclicDependency1Data>.
+type exampleForCyclicDependency1DataVec []ExampleForCyclicDependency1Data
+
+func (*exampleForCyclicDependency1DataVec) Class() string {
+	return "vector<ExampleForCyclicDependency1Data>"
+}
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *exampleForCyclicDependency1DataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *exampleForCyclicDependency1DataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ExampleForCyclicDependency1Data, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// exampleForCyclicDependency2DataVec is a vector<ExampleForCyclicDependency2Data>.
+type exampleForCyclicDependency2DataVec []ExampleForCyclicDependency2Data
+
+func (*exampleForCyclicDependency2DataVec) Class() string {
+	return "vector<ExampleForCyclicDependency2Data>"
+}
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *exampleForCyclicDependency2DataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *exampleForCyclicDependency2DataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ExampleForCyclicDependency2Data, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// exampleWithStringDataVec is a vector<ExampleWithStringData>.
+type exampleWithStringDataVec []ExampleWithStringData
+
+func (*exampleWithStringDataVec) Class() string { return "vector<ExampleWithStringData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *exampleWithStringDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *exampleWithStringDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ExampleWithStringData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// ex42_ExampleWithNamespaceDataVec is a vector<ex42::ExampleWithNamespaceData>.
+type ex42_ExampleWithNamespaceDataVec []ex42_ExampleWithNamespaceData
+
+func (*ex42_ExampleWithNamespaceDataVec) Class() string {
+	return "vector<ex42::ExampleWithNamespaceData>"
+}
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *ex42_ExampleWithNamespaceDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *ex42_ExampleWithNamespaceDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ex42_ExampleWithNamespaceData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// ex42_ExampleWithARelationDataVec is a vector<ex42::ExampleWithARelationData>.
+type ex42_ExampleWithARelationDataVec []ex42_ExampleWithARelationData
+
+func (*ex42_ExampleWithARelationDataVec) Class() string {
+	return "vector<ex42::ExampleWithARelationData>"
+}
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *ex42_ExampleWithARelationDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *ex42_ExampleWithARelationDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ex42_ExampleWithARelationData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// exampleWithArrayDataVec is a vector<ExampleWithArrayData>.
+type exampleWithArrayDataVec []ExampleWithArrayData
+
+func (*exampleWithArrayDataVec) Class() string { return "vector<ExampleWithArrayData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *exampleWithArrayDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *exampleWithArrayDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ExampleWithArrayData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// objectIDVec is a vector<podio::ObjectID>.
+type objectIDVec []ObjectID
+
+func (*objectIDVec) Class() string { return "vector<podio::ObjectID>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *objectIDVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *objectIDVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ObjectID, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// collectionTypeInfoVec is a vector<tuple<int,string,bool>>.
+type collectionTypeInfoVec []collectionTypeInfo
+
+func (*collectionTypeInfoVec) Class() string { return "vector<tuple<int,string,bool>>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *collectionTypeInfoVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *collectionTypeInfoVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]collectionTypeInfo, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// podioTypes associates the C++ name of each data type with a function
+// creating its ROOT I/O buffers.
+var podioTypes = map[string]func() podioBuffers{
+	"EventInfo":                   func() podioBuffers { return new(eventInfoBuffers) },
+	"ExampleHit":                  func() podioBuffers { return new(exampleHitBuffers) },
+	"ExampleMC":                   func() podioBuffers { return new(exampleMCBuffers) },
+	"ExampleCluster":              func() podioBuffers { return new(exampleClusterBuffers) },
+	"ExampleReferencingType":      func() podioBuffers { return new(exampleReferencingTypeBuffers) },
+	"ExampleWithVectorMember":     func() podioBuffers { return new(exampleWithVectorMemberBuffers) },
+	"ExampleWithOneRelation":      func() podioBuffers { return new(exampleWithOneRelationBuffers) },
+	"ExampleWithComponent":        func() podioBuffers { return new(exampleWithComponentBuffers) },
+	"ExampleForCyclicDependency1": func() podioBuffers { return new(exampleForCyclicDependency1Buffers) },
+	"ExampleForCyclicDependency2": func() podioBuffers { return new(exampleForCyclicDependency2Buffers) },
+	"ExampleWithString":           func() podioBuffers { return new(exampleWithStringBuffers) },
+	"ex42::ExampleWithNamespace":  func() podioBuffers { return new(ex42_ExampleWithNamespaceBuffers) },
+	"ex42::ExampleWithARelation":  func() podioBuffers { return new(ex42_ExampleWithARelationBuffers) },
+	"ExampleWithArray":            func() podioBuffers { return new(exampleWithArrayBuffers) },
+}
+
+func init() {
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("podio::CollectionIDTable", 1, 0, []rbytes.StreamerElement{
+		rdict.NewCxxStreamerSTL(rdict.Element{Name: *rbase.NewNamed("m_collectionIDs", ""), Type: rmeta.Streamer, Size: 24, EName: "vector<int>"}.New(), rmeta.STLvector, rmeta.Int),
+		rdict.NewCxxStreamerSTL(rdict.Element{Name: *rbase.NewNamed("m_names", ""), Type: rmeta.Streamer, Size: 24, EName: "vector<string>"}.New(), rmeta.STLvector, rmeta.Object),
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("SimpleStruct", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("x", ""), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("y", ""), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("z", ""), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("p", ""), Type: rmeta.OffsetL + rmeta.Int, Size: 16, ArrLen: 4, ArrDim: 1, MaxIdx: [5]int32{4, 0, 0, 0, 0}, EName: "int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("NotSoSimpleStruct", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("data", ""), Type: rmeta.Any, Size: 28, EName: "SimpleStruct"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("ex2::NamespaceStruct", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("x", ""), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("y", ""), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("ex2::NamespaceInNamespaceStruct", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("data", ""), Type: rmeta.Any, Size: 8, EName: "ex2::NamespaceStruct"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("EventInfoData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("Number", "event number"), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("ExampleHitData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("cellID", "cellID"), Type: rmeta.ULong64, Size: 8, EName: "ULong64_t"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("x", "x-coordinate"), Type: rmeta.Double, Size: 8, EName: "double"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("y", "y-coordinate"), Type: rmeta.Double, Size: 8, EName: "double"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("z", "z-coordinate"), Type: rmeta.Double, Size: 8, EName: "double"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("energy", "measured energy deposit"), Type: rmeta.Double, Size: 8, EName: "double"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("ExampleMCData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("energy", "energy"), Type: rmeta.Double, Size: 8, EName: "double"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("PDG", "PDG code"), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("parents_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("parents_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("daughters_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("daughters_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("ExampleClusterData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("energy", "cluster energy"), Type: rmeta.Double, Size: 8, EName: "double"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("Hits_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("Hits_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("Clusters_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("Clusters_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("ExampleReferencingTypeData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("Clusters_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("Clusters_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("Refs_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("Refs_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("ExampleWithVectorMemberData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("count_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("count_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("ExampleWithOneRelationData", 1, 0, []rbytes.StreamerElement{}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("ExampleWithComponentData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("component", "a component"), Type: rmeta.Any, Size: 28, EName: "NotSoSimpleStruct"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("ExampleForCyclicDependency1Data", 1, 0, []rbytes.StreamerElement{}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("ExampleForCyclicDependency2Data", 1, 0, []rbytes.StreamerElement{}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("ExampleWithStringData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerSTLstring{StreamerSTL: *rdict.NewCxxStreamerSTL(rdict.Element{Name: *rbase.NewNamed("theString", "the string"), Type: rmeta.STLstring, Size: 32, EName: "string"}.New(), rmeta.STLany, rmeta.STLstring)},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("ex42::ExampleWithNamespaceData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("data", "a component"), Type: rmeta.Any, Size: 8, EName: "ex2::NamespaceStruct"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("ex42::ExampleWithARelationData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("number", "just a number"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("refs_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("refs_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("ExampleWithArrayData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("arrayStruct", "component that contains an array"), Type: rmeta.Any, Size: 28, EName: "NotSoSimpleStruct"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("myArray", "array-member without space to test regex"), Type: rmeta.OffsetL + rmeta.Int, Size: 16, ArrLen: 4, ArrDim: 1, MaxIdx: [5]int32{4, 0, 0, 0, 0}, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("anotherArray2", "array-member with space to test regex"), Type: rmeta.OffsetL + rmeta.Int, Size: 16, ArrLen: 4, ArrDim: 1, MaxIdx: [5]int32{4, 0, 0, 0, 0}, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("snail_case_array", "snail case to test regex"), Type: rmeta.OffsetL + rmeta.Int, Size: 16, ArrLen: 4, ArrDim: 1, MaxIdx: [5]int32{4, 0, 0, 0, 0}, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("snail_case_Array3", "mixing things up for regex"), Type: rmeta.OffsetL + rmeta.Int, Size: 16, ArrLen: 4, ArrDim: 1, MaxIdx: [5]int32{4, 0, 0, 0, 0}, EName: "int"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("structArray", "an array containing structs"), Type: rmeta.OffsetL + rmeta.Any, Size: 32, ArrLen: 4, ArrDim: 1, MaxIdx: [5]int32{4, 0, 0, 0, 0}, EName: "ex2::NamespaceStruct"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("podio::ObjectID", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("index", ""), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("collectionID", ""), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("tuple<int,string,bool>", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("_0", ""), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerSTLstring{StreamerSTL: *rdict.NewCxxStreamerSTL(rdict.Element{Name: *rbase.NewNamed("_1", ""), Type: rmeta.STLstring, Size: 32, EName: "string"}.New(), rmeta.STLany, rmeta.STLstring)},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("_2", ""), Type: rmeta.Bool, Size: 1, EName: "bool"}.New()},
+	}))
+}
@@ -0,0 +1,670 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fads
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Card is a Delphes detector card.
+//
+// Delphes cards are Tcl scripts declaring the modules of the detector
+// simulation, their parameters and the order in which they are run:
+//
+//  set ExecutionPath {
+//    ParticlePropagator
+//    ChargedHadronTrackingEfficiency
+//  }
+//
+//  module ParticlePropagator ParticlePropagator {
+//    set InputArray Delphes/stableParticles
+//    set Radius 1.15
+//  }
+//
+// Only the subset of Tcl used by Delphes cards is supported:
+// the set, add, module, expr, for, foreach, incr and list commands,
+// variable and command substitutions, comments and line continuations.
+type Card struct {
+	ExecutionPath []string          // names of the modules to run, in order
+	Params        map[string]string // global parameters (e.g. MaxEvents, RandomSeed)
+	Modules       []CardModule      // modules declared in the card
+}
+
+// CardModule is a module declared in a Delphes detector card.
+type CardModule struct {
+	Type   string            // Delphes module type (e.g. "Efficiency")
+	Name   string            // module name (e.g. "ElectronEfficiency")
+	Params map[string]string // module parameters, as Tcl values
+}
+
+// ReadCard reads a Delphes detector card from r.
+func ReadCard(r io.Reader) (*Card, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("fads: could not read card: %w", err)
+	}
+
+	card := &Card{
+		Params: make(map[string]string),
+	}
+	tcl := tclInterp{card: card, mod: -1}
+	_, err = tcl.eval(string(raw))
+	if err != nil {
+		return nil, fmt.Errorf("fads: could not interpret card: %w", err)
+	}
+
+	if v, ok := card.Params["ExecutionPath"]; ok {
+		card.ExecutionPath, err = tclList(v)
+		if err != nil {
+			return nil, fmt.Errorf("fads: invalid card execution path: %w", err)
+		}
+	}
+
+	return card, nil
+}
+
+// Module returns the module with the provided name, or nil if
+// no such module was declared.
+func (card *Card) Module(name string) *CardModule {
+	for i := range card.Modules {
+		if card.Modules[i].Name == name {
+			return &card.Modules[i]
+		}
+	}
+	return nil
+}
+
+func (m *CardModule) str(key, def string) string {
+	v, ok := m.Params[key]
+	if !ok {
+		return def
+	}
+	return strings.TrimSpace(v)
+}
+
+func (m *CardModule) float(key string) (float64, error) {
+	v, err := strconv.ParseFloat(m.str(key, ""), 64)
+	if err != nil {
+		return 0, fmt.Errorf("fads: invalid parameter %q of module %q: %w", key, m.Name, err)
+	}
+	return v, nil
+}
+
+func (m *CardModule) integer(key string) (int, error) {
+	v, err := strconv.Atoi(m.str(key, ""))
+	if err != nil {
+		return 0, fmt.Errorf("fads: invalid parameter %q of module %q: %w", key, m.Name, err)
+	}
+	return v, nil
+}
+
+func (m *CardModule) bool(key string) (bool, error) {
+	switch strings.ToLower(m.str(key, "")) {
+	case "1", "true", "yes", "on":
+		return true, nil
+	case "0", "false", "no", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("fads: invalid boolean parameter %q of module %q", key, m.Name)
+}
+
+func (m *CardModule) list(key string) ([]string, error) {
+	vs, err := tclList(m.str(key, ""))
+	if err != nil {
+		return nil, fmt.Errorf("fads: invalid parameter %q of module %q: %w", key, m.Name, err)
+	}
+	return vs, nil
+}
+
+// tclInterp interprets the subset of Tcl used by Delphes cards.
+type tclInterp struct {
+	card  *Card
+	mod   int // index of the module being declared, -1 at top-level
+	depth int
+}
+
+func (tcl *tclInterp) vars() map[string]string {
+	if tcl.mod < 0 {
+		return tcl.card.Params
+	}
+	return tcl.card.Modules[tcl.mod].Params
+}
+
+func (tcl *tclInterp) get(name string) (string, error) {
+	if v, ok := tcl.vars()[name]; ok {
+		return v, nil
+	}
+	if v, ok := tcl.card.Params[name]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("can't read %q: no such variable", name)
+}
+
+func (tcl *tclInterp) set(name, v string) {
+	tcl.vars()[name] = v
+}
+
+// eval evaluates a Tcl script and returns the result of its last command.
+func (tcl *tclInterp) eval(script string) (string, error) {
+	const maxDepth = 100
+	if tcl.depth >= maxDepth {
+		return "", fmt.Errorf("too many nested evaluations")
+	}
+	tcl.depth++
+	defer func() { tcl.depth-- }()
+
+	var res string
+	for i := 0; i < len(script); {
+		words, next, err := tcl.parseCommand(script, i)
+		if err != nil {
+			return "", err
+		}
+		if len(words) > 0 {
+			res, err = tcl.call(words)
+			if err != nil {
+				return "", err
+			}
+		}
+		i = next
+	}
+	return res, nil
+}
+
+// parseCommand parses, with substitutions, the words of the command
+// starting at s[i:].
+// parseCommand returns the index of the beginning of the next command.
+func (tcl *tclInterp) parseCommand(s string, i int) ([]string, int, error) {
+	// skip blank lines and separators.
+	for i < len(s) {
+		switch {
+		case s[i] == ' ', s[i] == '\t', s[i] == '\r', s[i] == '\n', s[i] == ';':
+			i++
+			continue
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == '\n':
+			i += 2
+			continue
+		}
+		break
+	}
+
+	if i < len(s) && s[i] == '#' {
+		for i < len(s) && s[i] != '\n' {
+			if s[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		return nil, i, nil
+	}
+
+	var words []string
+	for {
+		// skip blanks between words.
+		for i < len(s) {
+			if s[i] == ' ' || s[i] == '\t' || s[i] == '\r' {
+				i++
+				continue
+			}
+			if s[i] == '\\' && i+1 < len(s) && s[i+1] == '\n' {
+				i += 2
+				continue
+			}
+			break
+		}
+
+		if i >= len(s) || s[i] == '\n' || s[i] == ';' {
+			return words, i + 1, nil
+		}
+
+		var (
+			word string
+			err  error
+		)
+		switch s[i] {
+		case '{':
+			end, err := tclMatchBrace(s, i)
+			if err != nil {
+				return nil, 0, err
+			}
+			word = tclJoinLines(s[i+1 : end])
+			i = end + 1
+		case '"':
+			word, i, err = tcl.subst(s, i+1, func(c byte) bool { return c == '"' })
+			if err != nil {
+				return nil, 0, err
+			}
+			if i >= len(s) {
+				return nil, 0, fmt.Errorf("missing close-quote")
+			}
+			i++
+		default:
+			word, i, err = tcl.subst(s, i, tclIsSep)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+		if i < len(s) && !tclIsSep(s[i]) && s[i] != '\\' {
+			return nil, 0, fmt.Errorf("extra characters after close-brace or close-quote")
+		}
+		words = append(words, word)
+	}
+}
+
+// subst performs the variable, command and backslash substitutions
+// of s[i:], until the stop predicate is true.
+// subst returns the substituted string and the index of the stop character.
+func (tcl *tclInterp) subst(s string, i int, stop func(c byte) bool) (string, int, error) {
+	var o strings.Builder
+	for i < len(s) && !stop(s[i]) {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 >= len(s) {
+				o.WriteByte(c)
+				i++
+				continue
+			}
+			switch n := s[i+1]; n {
+			case '\n':
+				if stop(' ') {
+					return o.String(), i, nil
+				}
+				o.WriteByte(' ')
+			case 'n':
+				o.WriteByte('\n')
+			case 't':
+				o.WriteByte('\t')
+			default:
+				o.WriteByte(n)
+			}
+			i += 2
+
+		case '$':
+			j := i + 1
+			var name string
+			if j < len(s) && s[j] == '{' {
+				end := strings.IndexByte(s[j:], '}')
+				if end < 0 {
+					return "", 0, fmt.Errorf("missing close-brace for variable name")
+				}
+				name = s[j+1 : j+end]
+				j += end + 1
+			} else {
+				for j < len(s) && tclIsVarChar(s[j]) {
+					j++
+				}
+				name = s[i+1 : j]
+			}
+			if name == "" {
+				o.WriteByte(c)
+				i++
+				continue
+			}
+			v, err := tcl.get(name)
+			if err != nil {
+				return "", 0, err
+			}
+			o.WriteString(v)
+			i = j
+
+		case '[':
+			end, err := tclMatchBracket(s, i)
+			if err != nil {
+				return "", 0, err
+			}
+			v, err := tcl.eval(s[i+1 : end])
+			if err != nil {
+				return "", 0, err
+			}
+			o.WriteString(v)
+			i = end + 1
+
+		default:
+			o.WriteByte(c)
+			i++
+		}
+	}
+	return o.String(), i, nil
+}
+
+func (tcl *tclInterp) call(words []string) (string, error) {
+	name, args := words[0], words[1:]
+	narg := func(ns ...int) error {
+		for _, n := range ns {
+			if len(args) == n {
+				return nil
+			}
+		}
+		return fmt.Errorf("wrong number of arguments for command %q", name)
+	}
+
+	switch name {
+	case "set":
+		if err := narg(1, 2); err != nil {
+			return "", err
+		}
+		if len(args) == 1 {
+			return tcl.get(args[0])
+		}
+		tcl.set(args[0], args[1])
+		return args[1], nil
+
+	case "add":
+		if len(args) < 1 {
+			return "", narg(1)
+		}
+		v := tclListAppend(tcl.vars()[args[0]], args[1:]...)
+		tcl.set(args[0], v)
+		return v, nil
+
+	case "list":
+		return tclListAppend("", args...), nil
+
+	case "module":
+		if err := narg(2, 3); err != nil {
+			return "", err
+		}
+		if tcl.mod >= 0 {
+			return "", fmt.Errorf("nested module %q", args[1])
+		}
+		return "", tcl.module(args[0], args[1], args[2:]...)
+
+	case "expr":
+		if len(args) < 1 {
+			return "", narg(1)
+		}
+		v, err := tcl.expr(strings.Join(args, " "))
+		if err != nil {
+			return "", err
+		}
+		return tclFormat(v), nil
+
+	case "incr":
+		if err := narg(1, 2); err != nil {
+			return "", err
+		}
+		inc := 1
+		if len(args) == 2 {
+			v, err := strconv.Atoi(args[1])
+			if err != nil {
+				return "", fmt.Errorf("invalid increment %q: %w", args[1], err)
+			}
+			inc = v
+		}
+		old, err := tcl.get(args[0])
+		if err != nil {
+			return "", err
+		}
+		v, err := strconv.Atoi(strings.TrimSpace(old))
+		if err != nil {
+			return "", fmt.Errorf("invalid integer variable %q: %w", args[0], err)
+		}
+		res := strconv.Itoa(v + inc)
+		tcl.set(args[0], res)
+		return res, nil
+
+	case "for":
+		if err := narg(4); err != nil {
+			return "", err
+		}
+		_, err := tcl.eval(args[0])
+		if err != nil {
+			return "", err
+		}
+		const maxIter = 1000000
+		for i := 0; ; i++ {
+			if i >= maxIter {
+				return "", fmt.Errorf("too many loop iterations")
+			}
+			cond, err := tcl.expr(args[1])
+			if err != nil {
+				return "", err
+			}
+			if cond == 0 {
+				break
+			}
+			_, err = tcl.eval(args[3])
+			if err != nil {
+				return "", err
+			}
+			_, err = tcl.eval(args[2])
+			if err != nil {
+				return "", err
+			}
+		}
+		return "", nil
+
+	case "foreach":
+		if err := narg(3); err != nil {
+			return "", err
+		}
+		vs, err := tclList(args[1])
+		if err != nil {
+			return "", err
+		}
+		for _, v := range vs {
+			tcl.set(args[0], v)
+			_, err = tcl.eval(args[2])
+			if err != nil {
+				return "", err
+			}
+		}
+		return "", nil
+	}
+
+	return "", fmt.Errorf("unknown command %q", name)
+}
+
+func (tcl *tclInterp) module(typ, name string, body ...string) error {
+	idx := -1
+	for i := range tcl.card.Modules {
+		if tcl.card.Modules[i].Name == name {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		tcl.card.Modules = append(tcl.card.Modules, CardModule{
+			Type:   typ,
+			Name:   name,
+			Params: make(map[string]string),
+		})
+		idx = len(tcl.card.Modules) - 1
+	}
+	if len(body) == 0 {
+		return nil
+	}
+
+	tcl.mod = idx
+	defer func() { tcl.mod = -1 }()
+
+	_, err := tcl.eval(body[0])
+	if err != nil {
+		return fmt.Errorf("module %q: %w", name, err)
+	}
+	return nil
+}
+
+// expr evaluates the Tcl expression, after substitution.
+func (tcl *tclInterp) expr(s string) (float64, error) {
+	s, _, err := tcl.subst(s, 0, func(byte) bool { return false })
+	if err != nil {
+		return 0, err
+	}
+	expr, _, err := compileFormula(s, false)
+	if err != nil {
+		return 0, err
+	}
+	return expr(nil), nil
+}
+
+func tclIsSep(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', ';':
+		return true
+	}
+	return false
+}
+
+func tclIsSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n':
+		return true
+	}
+	return false
+}
+
+func tclIsVarChar(c byte) bool {
+	return c == '_' || c == ':' ||
+		('a' <= c && c <= 'z') ||
+		('A' <= c && c <= 'Z') ||
+		('0' <= c && c <= '9')
+}
+
+// tclMatchBrace returns the index of the brace closing the one at s[i].
+func tclMatchBrace(s string, i int) (int, error) {
+	depth := 0
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("missing close-brace")
+}
+
+// tclMatchBracket returns the index of the bracket closing the one at s[i].
+func tclMatchBracket(s string, i int) (int, error) {
+	depth := 0
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '{':
+			end, err := tclMatchBrace(s, i)
+			if err != nil {
+				return 0, err
+			}
+			i = end
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("missing close-bracket")
+}
+
+// tclJoinLines replaces the backslash-newline sequences (and the
+// whitespace that follows them) of a braced word with a single space.
+func tclJoinLines(s string) string {
+	if !strings.Contains(s, "\\\n") {
+		return s
+	}
+	var o strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && s[i+1] == '\n' {
+			i += 2
+			for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+				i++
+			}
+			i--
+			o.WriteByte(' ')
+			continue
+		}
+		o.WriteByte(s[i])
+	}
+	return o.String()
+}
+
+// tclList splits a Tcl list into its elements.
+func tclList(s string) ([]string, error) {
+	var (
+		vs []string
+		i  = 0
+	)
+	for {
+		for i < len(s) && tclIsSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) {
+			return vs, nil
+		}
+
+		switch s[i] {
+		case '{':
+			end, err := tclMatchBrace(s, i)
+			if err != nil {
+				return nil, err
+			}
+			vs = append(vs, s[i+1:end])
+			i = end + 1
+		case '"':
+			end := i + 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, fmt.Errorf("unmatched open quote in list")
+			}
+			vs = append(vs, s[i+1:end])
+			i = end + 1
+		default:
+			end := i
+			for end < len(s) && !tclIsSpace(s[end]) {
+				end++
+			}
+			vs = append(vs, s[i:end])
+			i = end
+		}
+		if i < len(s) && !tclIsSpace(s[i]) {
+			return nil, fmt.Errorf("list element in braces followed by %q instead of space", s[i])
+		}
+	}
+}
+
+// tclListAppend appends the provided elements to the Tcl list.
+func tclListAppend(list string, vs ...string) string {
+	var o strings.Builder
+	o.WriteString(list)
+	for _, v := range vs {
+		if o.Len() > 0 {
+			o.WriteByte(' ')
+		}
+		switch {
+		case v == "":
+			o.WriteString("{}")
+		case strings.ContainsAny(v, " \t\r\n;{}[]$\"\\"):
+			o.WriteString("{" + v + "}")
+		default:
+			o.WriteString(v)
+		}
+	}
+	return o.String()
+}
+
+func tclFormat(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return strconv.FormatInt(int64(v), 10)
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,589 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fads
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+
+	"go-hep.org/x/hep/fastjet"
+	"go-hep.org/x/hep/fwk"
+)
+
+// Load creates and configures, in the provided application, the fads
+// tasks corresponding to the modules of the execution path of the card.
+//
+// Data ports are named after the Delphes arrays: the output array "jets"
+// of the module "FastJetFinder" is exported as "/fads/FastJetFinder/jets".
+// The Delphes input arrays "Delphes/allParticles", "Delphes/stableParticles"
+// and "Delphes/partons" are mapped to the outputs of the HepMcReader task.
+//
+// Delphes modules modifying their input jets in place (BTagging, TauTagging
+// and JetFlavorAssociation) create a new collection of jets, that replaces
+// the input one for the subsequent modules.
+//
+// Delphes formulas are evaluated with the variables available to the
+// corresponding fads task: energies are computed from pt and eta (and
+// vice versa) assuming massless candidates. Formulas depending on phi are
+// not supported and Load returns an error.
+//
+// Modules without a fads equivalent (e.g. TreeWriter) are skipped.
+func (card *Card) Load(app fwk.App) error {
+	ld := cardLoader{
+		app:   app,
+		alias: make(map[string]string),
+	}
+
+	var seed uint64
+	if v, ok := card.Params["RandomSeed"]; ok {
+		s, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("fads: invalid card random seed %q: %w", v, err)
+		}
+		seed = s
+	}
+
+	for _, name := range card.ExecutionPath {
+		m := card.Module(name)
+		if m == nil {
+			return fmt.Errorf("fads: module %q of execution path not declared in card", name)
+		}
+
+		cnv, ok := cardModules[m.Type]
+		if !ok {
+			app.Msg().Warnf("fads: no task for Delphes module %s (%s). skipping.\n", m.Type, m.Name)
+			continue
+		}
+
+		ps := cardProps{ld: &ld, m: m, props: make(map[string]interface{})}
+		cnv.props(&ps)
+		if ps.err != nil {
+			return ps.err
+		}
+		if _, ok := ps.props["Seed"]; !ok && seed != 0 {
+			ps.props["Seed"] = seed
+		}
+
+		c, err := app.New("go-hep.org/x/hep/fads."+cnv.task, m.Name)
+		if err != nil {
+			return fmt.Errorf("fads: could not create task for module %q: %w", m.Name, err)
+		}
+
+		keys := make([]string, 0, len(ps.props))
+		for k := range ps.props {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if !app.HasProp(c, k) {
+				if k == "Seed" {
+					continue
+				}
+				return fmt.Errorf("fads: task %q has no property %q", m.Name, k)
+			}
+			err = app.SetProp(c, k, ps.props[k])
+			if err != nil {
+				return fmt.Errorf("fads: could not configure task %q: %w", m.Name, err)
+			}
+		}
+
+		for k, v := range ps.aliases {
+			ld.alias[k] = v
+		}
+	}
+
+	return nil
+}
+
+type cardLoader struct {
+	app   fwk.App
+	alias map[string]string // ports replaced by the output of in-place modules
+}
+
+// port returns the name of the fads port corresponding to a Delphes array.
+func (ld *cardLoader) port(array string) string {
+	switch array {
+	case "Delphes/allParticles":
+		return "/fads/AllParticles"
+	case "Delphes/stableParticles":
+		return "/fads/StableParticles"
+	case "Delphes/partons":
+		return "/fads/Partons"
+	}
+	port := "/fads/" + array
+	if v, ok := ld.alias[port]; ok {
+		return v
+	}
+	return port
+}
+
+// cardProps collects the properties of a fads task from the parameters
+// of a Delphes module.
+type cardProps struct {
+	ld      *cardLoader
+	m       *CardModule
+	props   map[string]interface{}
+	aliases map[string]string
+	err     error
+}
+
+func (ps *cardProps) has(key string) bool {
+	_, ok := ps.m.Params[key]
+	return ok && ps.err == nil
+}
+
+func (ps *cardProps) input(prop, key, def string) {
+	ps.props[prop] = ps.ld.port(ps.m.str(key, def))
+}
+
+func (ps *cardProps) inputs(prop, key string) {
+	if !ps.has(key) {
+		return
+	}
+	vs, err := ps.m.list(key)
+	if err != nil {
+		ps.err = err
+		return
+	}
+	ports := make([]string, len(vs))
+	for i, v := range vs {
+		ports[i] = ps.ld.port(v)
+	}
+	ps.props[prop] = ports
+}
+
+func (ps *cardProps) output(prop, key, def string) {
+	ps.props[prop] = "/fads/" + ps.m.Name + "/" + ps.m.str(key, def)
+}
+
+// inplace declares the input and output ports of a collection that is
+// modified in place by the Delphes module.
+func (ps *cardProps) inplace(prop, key, def string) {
+	var (
+		array = ps.m.str(key, def)
+		in    = ps.ld.port(array)
+		out   = "/fads/" + ps.m.Name + "/" + path.Base(array)
+	)
+	ps.props[prop] = in
+	ps.props["Output"] = out
+
+	if ps.aliases == nil {
+		ps.aliases = make(map[string]string)
+	}
+	ps.aliases["/fads/"+array] = out
+	for k, v := range ps.ld.alias {
+		if v == in {
+			ps.aliases[k] = out
+		}
+	}
+}
+
+func (ps *cardProps) float(prop, key string) {
+	if !ps.has(key) {
+		return
+	}
+	ps.props[prop], ps.err = ps.m.float(key)
+}
+
+func (ps *cardProps) integer(prop, key string) {
+	if !ps.has(key) {
+		return
+	}
+	ps.props[prop], ps.err = ps.m.integer(key)
+}
+
+func (ps *cardProps) bool(prop, key string) {
+	if !ps.has(key) {
+		return
+	}
+	ps.props[prop], ps.err = ps.m.bool(key)
+}
+
+func (ps *cardProps) formula(key string) *Formula {
+	if !ps.has(key) {
+		return nil
+	}
+	f, err := newCardFormula(ps.m.str(key, ""))
+	if err != nil {
+		ps.err = fmt.Errorf("fads: invalid parameter %q of module %q: %w", key, ps.m.Name, err)
+		return nil
+	}
+	return f
+}
+
+// ptEtaFormula declares the formula key as a function of pt and eta.
+func (ps *cardProps) ptEtaFormula(prop, key string) {
+	f := ps.formula(key)
+	if f == nil {
+		return
+	}
+	ps.props[prop] = ptEtaFunc(f)
+}
+
+// etaEnergyFormula declares the formula key as a function of eta and energy.
+func (ps *cardProps) etaEnergyFormula(prop, key string) {
+	f := ps.formula(key)
+	if f == nil {
+		return
+	}
+	ps.props[prop] = func(eta, ene float64) float64 {
+		return f.Eval(ene/math.Cosh(eta), eta, 0, ene)
+	}
+}
+
+// effs declares the list of (PDG ID, formula) pairs key as a map of functions of pt and eta.
+func (ps *cardProps) effs(prop, key string) {
+	if !ps.has(key) {
+		return
+	}
+	vs, err := ps.m.list(key)
+	if err != nil {
+		ps.err = err
+		return
+	}
+	if len(vs)%2 != 0 {
+		ps.err = fmt.Errorf("fads: invalid parameter %q of module %q: odd number of elements", key, ps.m.Name)
+		return
+	}
+
+	effs := make(map[int]func(pt, eta float64) float64, len(vs)/2)
+	for i := 0; i < len(vs); i += 2 {
+		pdg, err := strconv.Atoi(vs[i])
+		if err != nil {
+			ps.err = fmt.Errorf("fads: invalid parameter %q of module %q: %w", key, ps.m.Name, err)
+			return
+		}
+		f, err := newCardFormula(vs[i+1])
+		if err != nil {
+			ps.err = fmt.Errorf("fads: invalid parameter %q of module %q: %w", key, ps.m.Name, err)
+			return
+		}
+		effs[pdg] = ptEtaFunc(f)
+	}
+	if _, ok := effs[0]; !ok {
+		// default efficiency (mis-identification rate)
+		effs[0] = func(pt, eta float64) float64 { return 0 }
+	}
+	ps.props[prop] = effs
+}
+
+// newCardFormula compiles a Delphes formula of a card.
+// fads tasks do not provide phi to their formulas.
+func newCardFormula(src string) (*Formula, error) {
+	f, err := NewFormula(src)
+	if err != nil {
+		return nil, err
+	}
+	if f.uses[2] {
+		return nil, fmt.Errorf("fads: formula %q depends on phi, which is not supported", src)
+	}
+	return f, nil
+}
+
+func ptEtaFunc(f *Formula) func(pt, eta float64) float64 {
+	return func(pt, eta float64) float64 {
+		return f.Eval(pt, eta, 0, pt*math.Cosh(eta))
+	}
+}
+
+// cardModules associates Delphes module types with fads tasks.
+var cardModules = map[string]struct {
+	task  string
+	props func(ps *cardProps)
+}{
+	"ParticlePropagator": {"Propagator", func(ps *cardProps) {
+		ps.input("Input", "InputArray", "Delphes/stableParticles")
+		ps.output("Output", "OutputArray", "stableParticles")
+		ps.output("ChargedHadrons", "ChargedHadronOutputArray", "chargedHadrons")
+		ps.output("Electrons", "ElectronOutputArray", "electrons")
+		ps.output("Muons", "MuonOutputArray", "muons")
+		ps.float("Radius", "Radius")
+		ps.float("HalfLength", "HalfLength")
+		ps.float("Bz", "Bz")
+	}},
+
+	"Efficiency": {"Efficiency", func(ps *cardProps) {
+		ps.input("Input", "InputArray", "ParticlePropagator/stableParticles")
+		ps.output("Output", "OutputArray", "stableParticles")
+		ps.ptEtaFormula("Eff", "EfficiencyFormula")
+	}},
+
+	"MomentumSmearing": {"MomentumSmearing", func(ps *cardProps) {
+		ps.input("Input", "InputArray", "ParticlePropagator/stableParticles")
+		ps.output("Output", "OutputArray", "stableParticles")
+		ps.ptEtaFormula("Resolution", "ResolutionFormula")
+	}},
+
+	"EnergySmearing": {"EnergySmearing", func(ps *cardProps) {
+		ps.input("Input", "InputArray", "ParticlePropagator/stableParticles")
+		ps.output("Output", "OutputArray", "stableParticles")
+		ps.etaEnergyFormula("Resolution", "ResolutionFormula")
+	}},
+
+	"EnergyScale": {"EnergyScale", func(ps *cardProps) {
+		ps.input("Input", "InputArray", "FastJetFinder/jets")
+		ps.output("Output", "OutputArray", "jets")
+		ps.ptEtaFormula("Scale", "ScaleFormula")
+	}},
+
+	"TimeSmearing": {"TimeSmearing", func(ps *cardProps) {
+		ps.input("Input", "InputArray", "ParticlePropagator/stableParticles")
+		ps.output("Output", "OutputArray", "stableParticles")
+		ps.float("TimeResolution", "TimeResolution")
+	}},
+
+	"PhotonConversions": {"PhotonConversions", func(ps *cardProps) {
+		ps.input("Input", "InputArray", "Delphes/stableParticles")
+		ps.output("Output", "OutputArray", "stableParticles")
+		ps.float("Radius", "Radius")
+		ps.float("HalfLength", "HalfLength")
+		ps.float("Step", "Step")
+		ps.ptEtaFormula("Probability", "Probability")
+	}},
+
+	"Merger": {"Merger", func(ps *cardProps) {
+		ps.inputs("Inputs", "InputArray")
+		ps.output("Output", "OutputArray", "candidates")
+		ps.output("MomentumOutput", "MomentumOutputArray", "momentum")
+		ps.output("EnergyOutput", "EnergyOutputArray", "energy")
+	}},
+
+	"Calorimeter": {"Calorimeter", cardCalorimeter},
+
+	"Isolation": {"Isolation", func(ps *cardProps) {
+		ps.input("Candidates", "CandidateInputArray", "Calorimeter/photons")
+		ps.input("Isolations", "IsolationInputArray", "Delphes/partons")
+		if ps.has("RhoInputArray") {
+			ps.input("Rhos", "RhoInputArray", "")
+		}
+		ps.output("Output", "OutputArray", "photons")
+		ps.float("DeltaRMax", "DeltaRMax")
+		ps.float("PtRatioMax", "PTRatioMax")
+		ps.float("PtSumMax", "PTSumMax")
+		ps.bool("UsePtSum", "UsePTSum")
+		ps.float("PtMin", "PTMin")
+	}},
+
+	"FastJetFinder": {"FastJetFinder", cardFastJetFinder},
+
+	"BTagging": {"BTagging", func(ps *cardProps) {
+		ps.input("Partons", "PartonInputArray", "Delphes/partons")
+		ps.inplace("Jets", "JetInputArray", "FastJetFinder/jets")
+		if ps.has("BitNumber") {
+			bit, err := ps.m.integer("BitNumber")
+			if err != nil || bit < 0 {
+				ps.err = fmt.Errorf("fads: invalid parameter %q of module %q", "BitNumber", ps.m.Name)
+				return
+			}
+			ps.props["BitNumber"] = uint(bit)
+		}
+		ps.float("DeltaR", "DeltaR")
+		ps.float("PartonPtMin", "PartonPTMin")
+		ps.float("PartonEtaMax", "PartonEtaMax")
+		ps.effs("Eff", "EfficiencyFormula")
+	}},
+
+	"TauTagging": {"TauTagging", func(ps *cardProps) {
+		ps.input("Particles", "ParticleInputArray", "Delphes/allParticles")
+		ps.input("Partons", "PartonInputArray", "Delphes/partons")
+		ps.inplace("Jets", "JetInputArray", "FastJetFinder/jets")
+		ps.float("DeltaR", "DeltaR")
+		ps.float("TauPtMin", "TauPTMin")
+		ps.float("TauEtaMax", "TauEtaMax")
+		ps.effs("Eff", "EfficiencyFormula")
+	}},
+
+	"JetFlavorAssociation": {"JetFlavorAssociation", func(ps *cardProps) {
+		ps.input("Partons", "PartonInputArray", "Delphes/partons")
+		ps.inplace("Jets", "JetInputArray", "FastJetFinder/jets")
+		ps.float("DeltaR", "DeltaR")
+		ps.float("PartonPtMin", "PartonPTMin")
+		ps.float("PartonEtaMax", "PartonEtaMax")
+	}},
+
+	"UniqueObjectFinder": {"UniqueObjectFinder", func(ps *cardProps) {
+		vs, err := ps.m.list("InputArray")
+		if err != nil {
+			ps.err = err
+			return
+		}
+		if len(vs)%2 != 0 {
+			ps.err = fmt.Errorf("fads: invalid parameter %q of module %q: odd number of elements", "InputArray", ps.m.Name)
+			return
+		}
+		keys := make([]ObjPair, 0, len(vs)/2)
+		for i := 0; i < len(vs); i += 2 {
+			keys = append(keys, ObjPair{
+				In:  ps.ld.port(vs[i]),
+				Out: "/fads/" + ps.m.Name + "/" + vs[i+1],
+			})
+		}
+		ps.props["Keys"] = keys
+	}},
+}
+
+func cardCalorimeter(ps *cardProps) {
+	ps.input("Particles", "ParticleInputArray", "ParticlePropagator/stableParticles")
+	ps.input("Tracks", "TrackInputArray", "ParticlePropagator/tracks")
+	ps.output("Towers", "TowerOutputArray", "towers")
+	ps.output("Photons", "PhotonOutputArray", "photons")
+	ps.output("EFlowTracks", "EFlowTrackOutputArray", "eflowTracks")
+	ps.output("EFlowTowers", "EFlowTowerOutputArray", "eflowTowers")
+	ps.etaEnergyFormula("ECalResolution", "ECalResolutionFormula")
+	ps.etaEnergyFormula("HCalResolution", "HCalResolutionFormula")
+
+	invalid := func(key string, err error) {
+		ps.err = fmt.Errorf("fads: invalid parameter %q of module %q: %w", key, ps.m.Name, err)
+	}
+
+	if ps.has("EtaPhiBins") {
+		vs, err := ps.m.list("EtaPhiBins")
+		if err != nil {
+			ps.err = err
+			return
+		}
+		if len(vs)%2 != 0 {
+			invalid("EtaPhiBins", fmt.Errorf("odd number of elements"))
+			return
+		}
+		bins := make([]EtaPhiBin, 0, len(vs)/2)
+		for i := 0; i < len(vs); i += 2 {
+			eta, err := strconv.ParseFloat(vs[i], 64)
+			if err != nil {
+				invalid("EtaPhiBins", err)
+				return
+			}
+			phis, err := parseFloats(vs[i+1])
+			if err != nil {
+				invalid("EtaPhiBins", err)
+				return
+			}
+			bins = append(bins, EtaPhiBin{
+				EtaBins: []float64{eta},
+				PhiBins: phis,
+			})
+		}
+		ps.props["EtaPhiBins"] = NewEtaPhiGrid(bins)
+	}
+
+	if ps.has("EnergyFraction") {
+		vs, err := ps.m.list("EnergyFraction")
+		if err != nil {
+			ps.err = err
+			return
+		}
+		if len(vs)%2 != 0 {
+			invalid("EnergyFraction", fmt.Errorf("odd number of elements"))
+			return
+		}
+		efrac := make(map[int]EneFrac, len(vs)/2)
+		for i := 0; i < len(vs); i += 2 {
+			pdg, err := strconv.Atoi(vs[i])
+			if err != nil {
+				invalid("EnergyFraction", err)
+				return
+			}
+			fs, err := parseFloats(vs[i+1])
+			if err != nil {
+				invalid("EnergyFraction", err)
+				return
+			}
+			if len(fs) < 2 {
+				invalid("EnergyFraction", fmt.Errorf("missing energy fractions for PDG ID %d", pdg))
+				return
+			}
+			efrac[pdg] = EneFrac{ECal: fs[0], HCal: fs[1]}
+		}
+		ps.props["EnergyFraction"] = efrac
+	}
+}
+
+func cardFastJetFinder(ps *cardProps) {
+	ps.input("Input", "InputArray", "Calorimeter/towers")
+	ps.output("Output", "OutputArray", "jets")
+	ps.output("Rho", "RhoOutputArray", "rho")
+
+	if ps.has("JetAlgorithm") {
+		alg, err := ps.m.integer("JetAlgorithm")
+		if err != nil {
+			ps.err = err
+			return
+		}
+		// Delphes jet algorithms: 1: CDFJetClu, 2: MidPoint, 3: SIScone,
+		// 4: kt, 5: Cambridge/Aachen, 6: anti-kt.
+		switch alg {
+		case 4:
+			ps.props["JetAlgorithm"] = fastjet.KtAlgorithm
+		case 5:
+			ps.props["JetAlgorithm"] = fastjet.CambridgeAlgorithm
+		case 6:
+			ps.props["JetAlgorithm"] = fastjet.AntiKtAlgorithm
+		default:
+			ps.err = fmt.Errorf("fads: unsupported jet algorithm %d in module %q", alg, ps.m.Name)
+			return
+		}
+	}
+
+	ps.float("ParameterR", "ParameterR")
+	ps.float("JetPtMin", "JetPTMin")
+	ps.float("ConeRadius", "ConeRadius")
+	ps.float("SeedThreshold", "SeedThreshold")
+	ps.float("ConeAreaFraction", "ConeAreaFraction")
+	ps.integer("MaxIterations", "MaxIterations")
+	ps.integer("MaxPairSize", "MaxPairSize")
+	ps.integer("Iratch", "Iratch")
+	ps.integer("AdjacencyCut", "AdjacencyCut")
+	ps.float("OverlapThreshold", "OverlapThreshold")
+	ps.integer("AreaAlgorithm", "AreaAlgorithm")
+	ps.bool("ComputeRho", "ComputeRho")
+	ps.float("GhostEtaMax", "GhostEtaMax")
+	ps.integer("Repeat", "Repeat")
+	ps.float("GhostArea", "GhostArea")
+	ps.float("GridScatter", "GridScatter")
+	ps.float("PtScatter", "PtScatter")
+	ps.float("MeanGhostPt", "MeanGhostPt")
+	ps.float("EffectiveRfact", "EffectiveRfact")
+
+	if ps.has("RhoEtaRange") {
+		vs, err := ps.m.list("RhoEtaRange")
+		if err == nil && len(vs)%2 != 0 {
+			err = fmt.Errorf("odd number of elements")
+		}
+		if err != nil {
+			ps.err = fmt.Errorf("fads: invalid parameter %q of module %q: %w", "RhoEtaRange", ps.m.Name, err)
+			return
+		}
+		rng := make(map[float64]float64, len(vs)/2)
+		for i := 0; i < len(vs); i += 2 {
+			fs, err := parseFloats(vs[i] + " " + vs[i+1])
+			if err != nil {
+				ps.err = fmt.Errorf("fads: invalid parameter %q of module %q: %w", "RhoEtaRange", ps.m.Name, err)
+				return
+			}
+			rng[fs[0]] = fs[1]
+		}
+		ps.props["RhoEtaRange"] = rng
+	}
+}
+
+// parseFloats parses a Tcl list of numbers.
+func parseFloats(list string) ([]float64, error) {
+	vs, err := tclList(list)
+	if err != nil {
+		return nil, err
+	}
+	fs := make([]float64, len(vs))
+	for i, v := range vs {
+		fs[i], err = strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fs, nil
+}
@@ -0,0 +1,580 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fads
+
+import (
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-hep.org/x/hep/fastjet"
+	"go-hep.org/x/hep/fwk"
+	"go-hep.org/x/hep/fwk/job"
+	"go-hep.org/x/hep/hepmc"
+)
+
+func readTestCard(t *testing.T, fname string) *Card {
+	t.Helper()
+
+	f, err := os.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open card: %+v", err)
+	}
+	defer f.Close()
+
+	card, err := ReadCard(f)
+	if err != nil {
+		t.Fatalf("could not read card: %+v", err)
+	}
+	return card
+}
+
+func TestReadCard(t *testing.T) {
+	card := readTestCard(t, "testdata/delphes_card_ATLAS.tcl")
+
+	if got, want := len(card.ExecutionPath), 27; got != want {
+		t.Fatalf("invalid execution path length: got=%d, want=%d", got, want)
+	}
+	if got, want := len(card.Modules), 27; got != want {
+		t.Fatalf("invalid number of modules: got=%d, want=%d", got, want)
+	}
+	for i, want := range map[int]string{
+		0:  "ParticlePropagator",
+		10: "PhotonConversions",
+		11: "TimeSmearing",
+		22: "JetFlavorAssociation",
+		26: "TreeWriter",
+	} {
+		if got := card.ExecutionPath[i]; got != want {
+			t.Fatalf("invalid execution path[%d]: got=%q, want=%q", i, got, want)
+		}
+	}
+
+	if card.Module("NotThere") != nil {
+		t.Fatalf("expected no module NotThere")
+	}
+
+	prop := card.Module("ParticlePropagator")
+	if got, want := prop.Type, "ParticlePropagator"; got != want {
+		t.Fatalf("invalid module type: got=%q, want=%q", got, want)
+	}
+	for k, want := range map[string]string{
+		"InputArray":  "Delphes/stableParticles",
+		"OutputArray": "stableParticles",
+		"Radius":      "1.15",
+		"HalfLength":  "3.51",
+		"Bz":          "2.0",
+	} {
+		if got := prop.Params[k]; got != want {
+			t.Fatalf("invalid ParticlePropagator parameter %q: got=%q, want=%q", k, got, want)
+		}
+	}
+
+	merger := card.Module("TrackMerger")
+	inputs, err := merger.list("InputArray")
+	if err != nil {
+		t.Fatalf("could not read TrackMerger inputs: %+v", err)
+	}
+	if got, want := inputs, []string{
+		"ChargedHadronMomentumSmearing/chargedHadrons",
+		"ElectronEnergySmearing/electrons",
+		"MuonMomentumSmearing/muons",
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid TrackMerger inputs:\ngot= %q\nwant=%q", got, want)
+	}
+
+	// the calorimeter bins are computed with expr, for and foreach.
+	calo := card.Module("Calorimeter")
+	if got, want := calo.Params["pi"], "3.141592653589793"; got != want {
+		t.Fatalf("invalid pi: got=%q, want=%q", got, want)
+	}
+	bins, err := calo.list("EtaPhiBins")
+	if err != nil {
+		t.Fatalf("could not read EtaPhiBins: %+v", err)
+	}
+	if got, want := len(bins), 2*(54+23); got != want {
+		t.Fatalf("invalid number of EtaPhiBins elements: got=%d, want=%d", got, want)
+	}
+	if got, want := bins[0], "-3.2"; got != want {
+		t.Fatalf("invalid first eta bin: got=%q, want=%q", got, want)
+	}
+	phis, err := parseFloats(bins[1])
+	if err != nil {
+		t.Fatalf("could not parse phi bins: %+v", err)
+	}
+	if got, want := len(phis), 37; got != want {
+		t.Fatalf("invalid number of phi bins: got=%d, want=%d", got, want)
+	}
+	if got, want := phis[0], -math.Pi; got != want {
+		t.Fatalf("invalid first phi bin: got=%v, want=%v", got, want)
+	}
+	phis, err = parseFloats(bins[len(bins)-1])
+	if err != nil {
+		t.Fatalf("could not parse phi bins: %+v", err)
+	}
+	if got, want := len(phis), 19; got != want {
+		t.Fatalf("invalid number of phi bins: got=%d, want=%d", got, want)
+	}
+
+	// multi-line formulas are joined.
+	effs, err := card.Module("BTagging").list("EfficiencyFormula")
+	if err != nil {
+		t.Fatalf("could not read BTagging efficiencies: %+v", err)
+	}
+	if got, want := len(effs), 6; got != want {
+		t.Fatalf("invalid number of BTagging efficiency elements: got=%d, want=%d", got, want)
+	}
+	if strings.Contains(effs[5], "\\") {
+		t.Fatalf("unexpected line continuation in formula %q", effs[5])
+	}
+	f, err := NewFormula(effs[5])
+	if err != nil {
+		t.Fatalf("could not compile b-tagging efficiency: %+v", err)
+	}
+	if got, want := f.Eval(50, 0, 0, 50), 0.5*math.Tanh(50*0.03-0.4); got != want {
+		t.Fatalf("invalid b-tagging efficiency: got=%v, want=%v", got, want)
+	}
+}
+
+func TestReadCardTcl(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		src  string
+		want map[string]string
+	}{
+		{
+			name: "set",
+			src:  "set a 1; set b $a\nset c ${b}2 ;# comment\nset d \"$c 3\"\nset e {$d}",
+			want: map[string]string{"a": "1", "b": "1", "c": "12", "d": "12 3", "e": "$d"},
+		},
+		{
+			name: "expr",
+			src:  "set a [expr {2*3}]\nset b [expr $a / 4]\nset c [expr {acos(-1) > 3}]",
+			want: map[string]string{"a": "6", "b": "1.5", "c": "1"},
+		},
+		{
+			name: "list",
+			src:  "set a [list x {y z} {}]\nadd a w\nadd b 1 2",
+			want: map[string]string{"a": "x {y z} {} w", "b": "1 2"},
+		},
+		{
+			name: "loops",
+			src:  "set s 0\nfor {set i 0} {$i < 4} {incr i} {\n  set s [expr {$s + $i}]\n}\nforeach v {1 2 3} { add l [expr {$v*$v}] }\nincr i 10",
+			want: map[string]string{"s": "6", "i": "14", "v": "3", "l": "1 4 9"},
+		},
+		{
+			name: "continuation",
+			src:  "set a {1 + \\\n    2}\nset b 3 \\\n\n# comment \\\n set c 4\n",
+			want: map[string]string{"a": "1 +  2", "b": "3"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			card, err := ReadCard(strings.NewReader(tc.src))
+			if err != nil {
+				t.Fatalf("could not read card: %+v", err)
+			}
+			if got, want := card.Params, tc.want; !reflect.DeepEqual(got, want) {
+				t.Fatalf("invalid card parameters:\ngot= %q\nwant=%q", got, want)
+			}
+		})
+	}
+
+	card, err := ReadCard(strings.NewReader(`
+set ExecutionPath {
+	A
+	B
+}
+set R 0.4
+module Efficiency A {
+	set OutputArray out
+	set R [expr {$R * 2}]
+}
+module Merger B
+module Efficiency A {
+	add InputArray B/out
+}
+`))
+	if err != nil {
+		t.Fatalf("could not read card: %+v", err)
+	}
+	if got, want := card.ExecutionPath, []string{"A", "B"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid execution path: got=%q, want=%q", got, want)
+	}
+	if got, want := card.Modules, []CardModule{
+		{
+			Type:   "Efficiency",
+			Name:   "A",
+			Params: map[string]string{"OutputArray": "out", "R": "0.8", "InputArray": "B/out"},
+		},
+		{
+			Type:   "Merger",
+			Name:   "B",
+			Params: map[string]string{},
+		},
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid modules:\ngot= %#v\nwant=%#v", got, want)
+	}
+	if got, want := card.Params["R"], "0.4"; got != want {
+		t.Fatalf("invalid global parameter: got=%q, want=%q", got, want)
+	}
+}
+
+func TestReadCardErrors(t *testing.T) {
+	for _, tc := range []struct {
+		src string
+		err string
+	}{
+		{"puts hello", `unknown command "puts"`},
+		{"set a {1 2", "missing close-brace"},
+		{"set a [expr 1", "missing close-bracket"},
+		{`set a "1 2`, "missing close-quote"},
+		{"set a {1}2", "extra characters after close-brace"},
+		{"set b $a", `can't read "a": no such variable`},
+		{"set a 1 2", `wrong number of arguments for command "set"`},
+		{"module A B { module C D }", `module "B": nested module "D"`},
+		{"set a [expr {1 +}]", "unexpected end of formula"},
+		{"set a [expr {pt}]", `unknown variable "pt"`},
+		{"set a x; incr a", `invalid integer variable "a"`},
+		{"set ExecutionPath {A {B}C}", "invalid card execution path"},
+	} {
+		t.Run(tc.src, func(t *testing.T) {
+			_, err := ReadCard(strings.NewReader(tc.src))
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("invalid error:\ngot= %v\nwant=%v", err, tc.err)
+			}
+		})
+	}
+}
+
+func newCardTestApp(t *testing.T, card *Card) *job.Job {
+	t.Helper()
+
+	app := job.New(job.P{
+		"EvtMax":   int64(-1),
+		"NProcs":   0,
+		"MsgLevel": job.MsgLevel("ERROR"),
+	})
+
+	app.Create(job.C{
+		Type: "go-hep.org/x/hep/fwk.InputStream",
+		Name: "hepmc-streamer",
+		Props: job.P{
+			"Ports": []fwk.Port{
+				{
+					Name: "/fads/McEvent",
+					Type: reflect.TypeOf(hepmc.Event{}),
+				},
+			},
+			"Streamer": &HepMcStreamer{
+				Name: "testdata/hepmc.data",
+			},
+		},
+	})
+
+	app.Create(job.C{
+		Type: "go-hep.org/x/hep/fads.HepMcReader",
+		Name: "hepmcreader",
+		Props: job.P{
+			"Input": "/fads/McEvent",
+		},
+	})
+
+	err := card.Load(app.App())
+	if err != nil {
+		t.Fatalf("could not load card: %+v", err)
+	}
+	return app
+}
+
+func TestCardLoad(t *testing.T) {
+	card := readTestCard(t, "testdata/delphes_card_ATLAS.tcl")
+	// the test HepMC events are too soft to produce 20 GeV jets.
+	card.Module("FastJetFinder").Params["JetPTMin"] = "2.0"
+	app := newCardTestApp(t, card)
+	mgr := app.App()
+
+	for _, name := range card.ExecutionPath {
+		if got, want := mgr.HasComponent(name), name != "TreeWriter"; got != want {
+			t.Fatalf("invalid component %q: got=%v, want=%v", name, got, want)
+		}
+	}
+
+	prop := func(c, name string) interface{} {
+		t.Helper()
+		v, err := mgr.GetProp(mgr.Component(c), name)
+		if err != nil {
+			t.Fatalf("could not get property %q of %q: %+v", name, c, err)
+		}
+		return v
+	}
+
+	for _, tc := range []struct {
+		c    string
+		prop string
+		want interface{}
+	}{
+		{"ParticlePropagator", "Input", "/fads/StableParticles"},
+		{"ParticlePropagator", "ChargedHadrons", "/fads/ParticlePropagator/chargedHadrons"},
+		{"ParticlePropagator", "Radius", 1.15},
+		{"ParticlePropagator", "Bz", 2.0},
+		{"TrackMerger", "Inputs", []string{
+			"/fads/ChargedHadronMomentumSmearing/chargedHadrons",
+			"/fads/ElectronEnergySmearing/electrons",
+			"/fads/MuonMomentumSmearing/muons",
+		}},
+		{"Calorimeter", "Tracks", "/fads/TrackMerger/tracks"},
+		{"PhotonConversions", "Input", "/fads/Calorimeter/photons"},
+		{"PhotonConversions", "Output", "/fads/PhotonConversions/photons"},
+		{"PhotonConversions", "Radius", 1.15},
+		{"PhotonConversions", "Step", 0.1},
+		{"TimeSmearing", "Input", "/fads/PhotonConversions/photons"},
+		{"TimeSmearing", "TimeResolution", 30e-12},
+		{"GenJetFinder", "Input", "/fads/StableParticles"},
+		{"FastJetFinder", "JetAlgorithm", fastjet.AntiKtAlgorithm},
+		{"FastJetFinder", "ParameterR", 0.6},
+		{"FastJetFinder", "JetPtMin", 2.0},
+		{"JetEnergyScale", "Input", "/fads/FastJetFinder/jets"},
+
+		// in-place modules: each one replaces the jets of the previous one.
+		{"JetFlavorAssociation", "Partons", "/fads/Partons"},
+		{"JetFlavorAssociation", "Jets", "/fads/JetEnergyScale/jets"},
+		{"JetFlavorAssociation", "Output", "/fads/JetFlavorAssociation/jets"},
+		{"JetFlavorAssociation", "DeltaR", 0.5},
+		{"JetFlavorAssociation", "PartonPtMin", 1.0},
+		{"BTagging", "Jets", "/fads/JetFlavorAssociation/jets"},
+		{"BTagging", "Output", "/fads/BTagging/jets"},
+		{"BTagging", "BitNumber", uint(0)},
+		{"TauTagging", "Particles", "/fads/AllParticles"},
+		{"TauTagging", "Jets", "/fads/BTagging/jets"},
+		{"TauTagging", "Output", "/fads/TauTagging/jets"},
+		{"UniqueObjectFinder", "Keys", []ObjPair{
+			{In: "/fads/PhotonIsolation/photons", Out: "/fads/UniqueObjectFinder/photons"},
+			{In: "/fads/ElectronIsolation/electrons", Out: "/fads/UniqueObjectFinder/electrons"},
+			{In: "/fads/TauTagging/jets", Out: "/fads/UniqueObjectFinder/jets"},
+		}},
+	} {
+		if got := prop(tc.c, tc.prop); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("invalid property %q of %q:\ngot= %#v\nwant=%#v", tc.prop, tc.c, got, tc.want)
+		}
+	}
+
+	// formulas of (pt, eta) or of (eta, energy).
+	for _, tc := range []struct {
+		c, prop string
+		x, y    float64
+		want    float64
+	}{
+		{"PhotonConversions", "Probability", 10, 1, 0.02},
+		{"PhotonConversions", "Probability", 10, 3, 0},
+		{"JetEnergyScale", "Scale", 50, 0, 1.08},
+		{"ChargedHadronTrackingEfficiency", "Eff", 10, 0, 0.95},
+		{"Calorimeter", "ECalResolution", 0, 50, math.Sqrt(50*50*0.0017*0.0017 + 50*0.101*0.101)},
+	} {
+		v := prop(tc.c, tc.prop)
+		fct, ok := v.(func(x, y float64) float64)
+		if !ok {
+			t.Fatalf("invalid type %T for property %q of %q", v, tc.prop, tc.c)
+		}
+		if got := fct(tc.x, tc.y); math.Abs(got-tc.want) > 1e-12 {
+			t.Fatalf("invalid %s.%s(%v, %v): got=%v, want=%v", tc.c, tc.prop, tc.x, tc.y, got, tc.want)
+		}
+	}
+
+	effs := prop("BTagging", "Eff").(map[int]func(pt, eta float64) float64)
+	for pdg, want := range map[int]float64{
+		0: 0.001,
+		4: 0.2 * math.Tanh(50*0.03-0.4),
+		5: 0.5 * math.Tanh(50*0.03-0.4),
+	} {
+		if got := effs[pdg](50, 0); math.Abs(got-want) > 1e-12 {
+			t.Fatalf("invalid b-tagging efficiency for %d: got=%v, want=%v", pdg, got, want)
+		}
+	}
+
+	var sink cardSink
+	app.Create(job.C{
+		Type: "go-hep.org/x/hep/fads.cardSink",
+		Name: "sink",
+		Props: job.P{
+			"Jets":    "/fads/TauTagging/jets",
+			"Photons": "/fads/TimeSmearing/photons",
+			"Results": &sink,
+		},
+	})
+
+	err := mgr.Run()
+	if err != nil {
+		t.Fatalf("could not run application: %+v", err)
+	}
+
+	if got, want := sink.nevts, 5; got != want {
+		t.Fatalf("invalid number of events: got=%d, want=%d", got, want)
+	}
+	if sink.njets == 0 {
+		t.Fatalf("no jets reconstructed")
+	}
+	if sink.nflavor == 0 {
+		t.Fatalf("no jet associated with a parton")
+	}
+}
+
+func TestCardLoadErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{
+			name: "missing-module",
+			src:  "set ExecutionPath {A}",
+			err:  `fads: module "A" of execution path not declared in card`,
+		},
+		{
+			name: "invalid-seed",
+			src:  "set RandomSeed x\nset ExecutionPath {}",
+			err:  `fads: invalid card random seed "x"`,
+		},
+		{
+			name: "invalid-jet-algorithm",
+			src:  "set ExecutionPath {J}\nmodule FastJetFinder J {\n  set JetAlgorithm 3\n}",
+			err:  `fads: unsupported jet algorithm 3 in module "J"`,
+		},
+		{
+			name: "invalid-float",
+			src:  "set ExecutionPath {T}\nmodule TimeSmearing T {\n  set TimeResolution abc\n}",
+			err:  `fads: invalid parameter "TimeResolution" of module "T"`,
+		},
+		{
+			name: "invalid-formula",
+			src:  "set ExecutionPath {E}\nmodule Efficiency E {\n  set EfficiencyFormula {pt >}\n}",
+			err:  `fads: invalid parameter "EfficiencyFormula" of module "E"`,
+		},
+		{
+			name: "phi-formula",
+			src:  "set ExecutionPath {E}\nmodule Efficiency E {\n  set EfficiencyFormula {(abs(phi) < 1) * 0.9}\n}",
+			err:  `fads: formula "(abs(phi) < 1) * 0.9" depends on phi, which is not supported`,
+		},
+		{
+			name: "phi-efficiencies",
+			src:  "set ExecutionPath {B}\nmodule BTagging B {\n  add EfficiencyFormula {5} {z > 0}\n}",
+			err:  `fads: formula "z > 0" depends on phi`,
+		},
+		{
+			name: "invalid-efficiencies",
+			src:  "set ExecutionPath {B}\nmodule BTagging B {\n  add EfficiencyFormula {0}\n}",
+			err:  "odd number of elements",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			card, err := ReadCard(strings.NewReader(tc.src))
+			if err != nil {
+				t.Fatalf("could not read card: %+v", err)
+			}
+			app := job.New(job.P{"MsgLevel": job.MsgLevel("ERROR")})
+			err = card.Load(app.App())
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("invalid error:\ngot= %v\nwant=%v", err, tc.err)
+			}
+		})
+	}
+}
+
+// cardSink collects statistics about the jets and photons produced
+// by a card.
+type cardSink struct {
+	fwk.TaskBase
+
+	jets    string
+	photons string
+	res     *cardSink
+
+	nevts   int
+	njets   int
+	nflavor int
+}
+
+func (tsk *cardSink) Configure(ctx fwk.Context) error {
+	var err error
+
+	err = tsk.DeclInPort(tsk.jets, reflect.TypeOf([]Candidate{}))
+	if err != nil {
+		return err
+	}
+
+	err = tsk.DeclInPort(tsk.photons, reflect.TypeOf([]Candidate{}))
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func (tsk *cardSink) StartTask(ctx fwk.Context) error {
+	return nil
+}
+
+func (tsk *cardSink) StopTask(ctx fwk.Context) error {
+	return nil
+}
+
+func (tsk *cardSink) Process(ctx fwk.Context) error {
+	store := ctx.Store()
+
+	v, err := store.Get(tsk.jets)
+	if err != nil {
+		return err
+	}
+	jets := v.([]Candidate)
+
+	_, err = store.Get(tsk.photons)
+	if err != nil {
+		return err
+	}
+
+	tsk.res.nevts++
+	tsk.res.njets += len(jets)
+	for i := range jets {
+		if jets[i].Flavor != 0 {
+			tsk.res.nflavor++
+		}
+	}
+	return nil
+}
+
+func init() {
+	fwk.Register(reflect.TypeOf(cardSink{}),
+		func(typ, name string, mgr fwk.App) (fwk.Component, error) {
+			var err error
+			tsk := &cardSink{
+				TaskBase: fwk.NewTask(typ, name, mgr),
+			}
+
+			err = tsk.DeclProp("Jets", &tsk.jets)
+			if err != nil {
+				return nil, err
+			}
+
+			err = tsk.DeclProp("Photons", &tsk.photons)
+			if err != nil {
+				return nil, err
+			}
+
+			err = tsk.DeclProp("Results", &tsk.res)
+			if err != nil {
+				return nil, err
+			}
+
+			return tsk, err
+		},
+	)
+}
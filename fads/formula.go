@@ -0,0 +1,462 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fads
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Formula is a compiled Delphes formula.
+//
+// Delphes formulas are ROOT TFormula expressions of the pt, eta, phi and
+// energy variables (or, equivalently, x, y, z and t), such as:
+//
+//  (abs(eta) <= 1.5) * (pt > 1.0) * sqrt(0.01^2 + pt^2*1.0e-4^2)
+//
+// Formulas support the usual arithmetic operators (with ^ as the power
+// operator), comparison and logical operators (evaluating to 0 or 1),
+// numbers, the pi constant and the common mathematical functions
+// (optionally prefixed with TMath::).
+type Formula struct {
+	src  string
+	expr formulaExpr
+	uses [4]bool // variables referenced by the formula
+}
+
+// formulaVars holds the values of the pt, eta, phi and energy variables.
+type formulaVars [4]float64
+
+type formulaExpr func(vs *formulaVars) float64
+
+// NewFormula compiles the provided Delphes formula.
+func NewFormula(src string) (*Formula, error) {
+	expr, uses, err := compileFormula(src, true)
+	if err != nil {
+		return nil, err
+	}
+	return &Formula{src: src, expr: expr, uses: uses}, nil
+}
+
+// String returns the source of the formula.
+func (f *Formula) String() string {
+	return f.src
+}
+
+// Eval evaluates the formula with the provided values of the variables.
+func (f *Formula) Eval(pt, eta, phi, energy float64) float64 {
+	vs := formulaVars{pt, eta, phi, energy}
+	return f.expr(&vs)
+}
+
+func compileFormula(src string, vars bool) (formulaExpr, [4]bool, error) {
+	toks, err := formulaTokens(src)
+	if err != nil {
+		return nil, [4]bool{}, fmt.Errorf("fads: could not tokenize formula %q: %w", src, err)
+	}
+
+	p := formulaParser{toks: toks, vars: vars}
+	expr, err := p.parseOr()
+	if err == nil && p.pos < len(p.toks) {
+		err = fmt.Errorf("unexpected token %q", p.toks[p.pos].text)
+	}
+	if err != nil {
+		return nil, [4]bool{}, fmt.Errorf("fads: could not parse formula %q: %w", src, err)
+	}
+	return expr, p.uses, nil
+}
+
+type formulaTokKind int
+
+const (
+	formulaNum formulaTokKind = iota
+	formulaIdent
+	formulaOp
+)
+
+type formulaTok struct {
+	kind formulaTokKind
+	text string
+	num  float64
+}
+
+func formulaTokens(src string) ([]formulaTok, error) {
+	var (
+		toks []formulaTok
+		rs   = []rune(src)
+	)
+
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(rs) && unicode.IsDigit(rs[i+1])):
+			j := i
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			if j < len(rs) && (rs[j] == 'e' || rs[j] == 'E') {
+				k := j + 1
+				if k < len(rs) && (rs[k] == '+' || rs[k] == '-') {
+					k++
+				}
+				if k < len(rs) && unicode.IsDigit(rs[k]) {
+					for k < len(rs) && unicode.IsDigit(rs[k]) {
+						k++
+					}
+					j = k
+				}
+			}
+			txt := string(rs[i:j])
+			v, err := strconv.ParseFloat(txt, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", txt)
+			}
+			toks = append(toks, formulaTok{kind: formulaNum, text: txt, num: v})
+			i = j
+
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+		ident:
+			for j < len(rs) {
+				switch {
+				case unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_':
+					j++
+				case rs[j] == ':' && j+1 < len(rs) && rs[j+1] == ':':
+					j += 2
+				default:
+					break ident
+				}
+			}
+			toks = append(toks, formulaTok{kind: formulaIdent, text: string(rs[i:j])})
+			i = j
+
+		default:
+			op := string(r)
+			if i+1 < len(rs) {
+				switch two := string(rs[i : i+2]); two {
+				case "<=", ">=", "==", "!=", "&&", "||", "**":
+					op = two
+				}
+			}
+			switch op {
+			case "+", "-", "*", "/", "^", "**", "(", ")", ",",
+				"<", ">", "<=", ">=", "==", "!=", "&&", "||", "!":
+			default:
+				return nil, fmt.Errorf("invalid character %q", r)
+			}
+			i += len(op)
+			if op == "**" {
+				op = "^"
+			}
+			toks = append(toks, formulaTok{kind: formulaOp, text: op})
+		}
+	}
+
+	return toks, nil
+}
+
+// formulaParser is a recursive descent parser for Delphes formulas.
+// Operators, from lowest to highest precedence:
+//  ||
+//  &&
+//  == !=
+//  < <= > >=
+//  + -
+//  * /
+//  unary - + !
+//  ^ (right associative)
+type formulaParser struct {
+	toks []formulaTok
+	pos  int
+	vars bool    // whether the pt, eta, phi and energy variables are allowed
+	uses [4]bool // variables referenced by the formula
+}
+
+func (p *formulaParser) peek(ops ...string) (string, bool) {
+	if p.pos >= len(p.toks) {
+		return "", false
+	}
+	tok := p.toks[p.pos]
+	if tok.kind != formulaOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *formulaParser) expect(op string) error {
+	if _, ok := p.peek(op); !ok {
+		if p.pos >= len(p.toks) {
+			return fmt.Errorf("expected %q, got end of formula", op)
+		}
+		return fmt.Errorf("expected %q, got %q", op, p.toks[p.pos].text)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *formulaParser) binary(next func() (formulaExpr, error), ops ...string) (formulaExpr, error) {
+	lhs, err := next()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.peek(ops...)
+		if !ok {
+			return lhs, nil
+		}
+		p.pos++
+		rhs, err := next()
+		if err != nil {
+			return nil, err
+		}
+		lhs = formulaBinary(op, lhs, rhs)
+	}
+}
+
+func (p *formulaParser) parseOr() (formulaExpr, error) {
+	return p.binary(p.parseAnd, "||")
+}
+
+func (p *formulaParser) parseAnd() (formulaExpr, error) {
+	return p.binary(p.parseEq, "&&")
+}
+
+func (p *formulaParser) parseEq() (formulaExpr, error) {
+	return p.binary(p.parseRel, "==", "!=")
+}
+
+func (p *formulaParser) parseRel() (formulaExpr, error) {
+	return p.binary(p.parseAdd, "<", "<=", ">", ">=")
+}
+
+func (p *formulaParser) parseAdd() (formulaExpr, error) {
+	return p.binary(p.parseMul, "+", "-")
+}
+
+func (p *formulaParser) parseMul() (formulaExpr, error) {
+	return p.binary(p.parseUnary, "*", "/")
+}
+
+func (p *formulaParser) parseUnary() (formulaExpr, error) {
+	op, ok := p.peek("-", "+", "!")
+	if !ok {
+		return p.parsePow()
+	}
+	p.pos++
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case "-":
+		return func(vs *formulaVars) float64 { return -x(vs) }, nil
+	case "!":
+		return func(vs *formulaVars) float64 { return b2f(x(vs) == 0) }, nil
+	}
+	return x, nil
+}
+
+func (p *formulaParser) parsePow() (formulaExpr, error) {
+	lhs, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.peek("^"); !ok {
+		return lhs, nil
+	}
+	p.pos++
+	rhs, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return formulaBinary("^", lhs, rhs), nil
+}
+
+func (p *formulaParser) parsePrimary() (formulaExpr, error) {
+	if p.pos >= len(p.toks) {
+		return nil, fmt.Errorf("unexpected end of formula")
+	}
+
+	tok := p.toks[p.pos]
+	p.pos++
+	switch tok.kind {
+	case formulaNum:
+		v := tok.num
+		return func(*formulaVars) float64 { return v }, nil
+
+	case formulaIdent:
+		if _, ok := p.peek("("); ok {
+			return p.parseCall(tok.text)
+		}
+		return p.ident(tok.text)
+
+	default:
+		if tok.text != "(" {
+			return nil, fmt.Errorf("unexpected token %q", tok.text)
+		}
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		err = p.expect(")")
+		if err != nil {
+			return nil, err
+		}
+		return x, nil
+	}
+}
+
+func (p *formulaParser) ident(name string) (formulaExpr, error) {
+	idx := -1
+	switch name {
+	case "pi", "TMath::Pi":
+		return func(*formulaVars) float64 { return math.Pi }, nil
+	case "pt", "x":
+		idx = 0
+	case "eta", "y":
+		idx = 1
+	case "phi", "z":
+		idx = 2
+	case "energy", "t":
+		idx = 3
+	}
+	if idx < 0 || !p.vars {
+		return nil, fmt.Errorf("unknown variable %q", name)
+	}
+	p.uses[idx] = true
+	return func(vs *formulaVars) float64 { return vs[idx] }, nil
+}
+
+func (p *formulaParser) parseCall(name string) (formulaExpr, error) {
+	err := p.expect("(")
+	if err != nil {
+		return nil, err
+	}
+
+	var args []formulaExpr
+	if _, ok := p.peek(")"); !ok {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if _, ok := p.peek(","); !ok {
+				break
+			}
+			p.pos++
+		}
+	}
+	err = p.expect(")")
+	if err != nil {
+		return nil, err
+	}
+
+	fname := strings.ToLower(strings.TrimPrefix(name, "TMath::"))
+	if fname == "pi" && len(args) == 0 {
+		return func(*formulaVars) float64 { return math.Pi }, nil
+	}
+
+	if fct, ok := formulaFuncs1[fname]; ok {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("function %q takes 1 argument, got %d", name, len(args))
+		}
+		x := args[0]
+		return func(vs *formulaVars) float64 { return fct(x(vs)) }, nil
+	}
+
+	if fct, ok := formulaFuncs2[fname]; ok {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("function %q takes 2 arguments, got %d", name, len(args))
+		}
+		x, y := args[0], args[1]
+		return func(vs *formulaVars) float64 { return fct(x(vs), y(vs)) }, nil
+	}
+
+	return nil, fmt.Errorf("unknown function %q", name)
+}
+
+var formulaFuncs1 = map[string]func(float64) float64{
+	"abs":   math.Abs,
+	"fabs":  math.Abs,
+	"sqrt":  math.Sqrt,
+	"exp":   math.Exp,
+	"log":   math.Log,
+	"log10": math.Log10,
+	"sin":   math.Sin,
+	"cos":   math.Cos,
+	"tan":   math.Tan,
+	"asin":  math.Asin,
+	"acos":  math.Acos,
+	"atan":  math.Atan,
+	"sinh":  math.Sinh,
+	"cosh":  math.Cosh,
+	"tanh":  math.Tanh,
+	"floor": math.Floor,
+	"ceil":  math.Ceil,
+	"erf":   math.Erf,
+	"erfc":  math.Erfc,
+	"sq":    func(x float64) float64 { return x * x },
+}
+
+var formulaFuncs2 = map[string]func(x, y float64) float64{
+	"pow":   math.Pow,
+	"power": math.Pow,
+	"atan2": math.Atan2,
+	"min":   math.Min,
+	"max":   math.Max,
+	"hypot": math.Hypot,
+	"fmod":  math.Mod,
+}
+
+func formulaBinary(op string, x, y formulaExpr) formulaExpr {
+	switch op {
+	case "+":
+		return func(vs *formulaVars) float64 { return x(vs) + y(vs) }
+	case "-":
+		return func(vs *formulaVars) float64 { return x(vs) - y(vs) }
+	case "*":
+		return func(vs *formulaVars) float64 { return x(vs) * y(vs) }
+	case "/":
+		return func(vs *formulaVars) float64 { return x(vs) / y(vs) }
+	case "^":
+		return func(vs *formulaVars) float64 { return math.Pow(x(vs), y(vs)) }
+	case "<":
+		return func(vs *formulaVars) float64 { return b2f(x(vs) < y(vs)) }
+	case "<=":
+		return func(vs *formulaVars) float64 { return b2f(x(vs) <= y(vs)) }
+	case ">":
+		return func(vs *formulaVars) float64 { return b2f(x(vs) > y(vs)) }
+	case ">=":
+		return func(vs *formulaVars) float64 { return b2f(x(vs) >= y(vs)) }
+	case "==":
+		return func(vs *formulaVars) float64 { return b2f(x(vs) == y(vs)) }
+	case "!=":
+		return func(vs *formulaVars) float64 { return b2f(x(vs) != y(vs)) }
+	case "&&":
+		return func(vs *formulaVars) float64 { return b2f(x(vs) != 0 && y(vs) != 0) }
+	case "||":
+		return func(vs *formulaVars) float64 { return b2f(x(vs) != 0 || y(vs) != 0) }
+	}
+	panic(fmt.Errorf("fads: invalid formula operator %q", op))
+}
+
+func b2f(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
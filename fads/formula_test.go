@@ -0,0 +1,106 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fads
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFormula(t *testing.T) {
+	const (
+		pt  = 20.0
+		eta = -1.2
+		phi = 0.5
+		ene = 50.0
+	)
+
+	for _, tc := range []struct {
+		src  string
+		want float64
+	}{
+		{"1.08", 1.08},
+		{".5e-1", 0.05},
+		{"2E+2", 200},
+		{"pt", pt},
+		{"x + y + z + t", pt + eta + phi + ene},
+		{"energy - phi", ene - phi},
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"8 / 4 / 2", 1},
+		{"2 - 3 - 4", -5},
+		{"2^3^2", 512},
+		{"2**3", 8},
+		{"-2^2", -4},
+		{"(-2)^2", 4},
+		{"2 * -3", -6},
+		{"!0 + !1", 1},
+		{"abs(eta) <= 1.5", 1},
+		{"(abs(eta) > 1.5) * 42", 0},
+		{"pt > 10 && pt < 30", 1},
+		{"pt > 30 || eta > 0", 0},
+		{"1 == 1.0", 1},
+		{"1 != 1", 0},
+		{"1 < 2 == 1", 1},
+		{"sqrt(pt^2*1.0e-4^2 + 0.01^2)", math.Sqrt(pt*pt*1e-8 + 1e-4)},
+		{"TMath::Sqrt(4)", 2},
+		{"pi", math.Pi},
+		{"TMath::Pi()", math.Pi},
+		{"acos(-1)", math.Pi},
+		{"pow(2, 10)", 1024},
+		{"max(pt, energy) - min(1, 2)", ene - 1},
+		{"atan2(1, 1)", math.Pi / 4},
+		{"0.5*tanh(pt*0.03 - 0.4)", 0.5 * math.Tanh(pt*0.03-0.4)},
+		{"exp(log(3)) + log10(100) + sq(3)", 3 + 2 + 9},
+		{
+			"(abs(eta) <= 3.2) * sqrt(energy^2*0.0017^2 + energy*0.101^2) + (abs(eta) > 3.2 && abs(eta) <= 4.9) * sqrt(energy^2*0.0350^2 + energy*0.285^2)",
+			math.Sqrt(ene*ene*0.0017*0.0017 + ene*0.101*0.101),
+		},
+	} {
+		t.Run(tc.src, func(t *testing.T) {
+			f, err := NewFormula(tc.src)
+			if err != nil {
+				t.Fatalf("could not compile formula: %+v", err)
+			}
+			if got, want := f.String(), tc.src; got != want {
+				t.Fatalf("invalid formula source: got=%q, want=%q", got, want)
+			}
+			got := f.Eval(pt, eta, phi, ene)
+			if math.Abs(got-tc.want) > 1e-12*math.Max(1, math.Abs(tc.want)) {
+				t.Fatalf("invalid value: got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormulaErrors(t *testing.T) {
+	for _, tc := range []struct {
+		src string
+		err string
+	}{
+		{"", "unexpected end of formula"},
+		{"1 +", "unexpected end of formula"},
+		{"(1 + 2", `expected ")", got end of formula`},
+		{"1 + 2)", `unexpected token ")"`},
+		{"1 2", `unexpected token "2"`},
+		{"foo", `unknown variable "foo"`},
+		{"foo(1)", `unknown function "foo"`},
+		{"sqrt(1, 2)", `function "sqrt" takes 1 argument, got 2`},
+		{"pow(2)", `function "pow" takes 2 arguments, got 1`},
+		{"1 % 2", `invalid character '%'`},
+		{"pt = 2", `invalid character '='`},
+	} {
+		t.Run(tc.src, func(t *testing.T) {
+			_, err := NewFormula(tc.src)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("invalid error:\ngot= %v\nwant=%v", err, tc.err)
+			}
+		})
+	}
+}
@@ -9,32 +9,52 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 
 	"go-hep.org/x/hep/fmom"
 	"go-hep.org/x/hep/fwk"
+	"go-hep.org/x/hep/groot"
 	"go-hep.org/x/hep/hepmc"
 	"go-hep.org/x/hep/heppdt"
 )
 
 type HepMcStreamer struct {
-	Name string // input filename
-	r    io.ReadCloser
-	dec  *hepmc.Decoder
+	Name string // input filename (HepMC ASCII file or HepMC3 ROOT file)
+	r    io.Closer
+	dec  hepmcDecoder
 
 	mcevt string // hepmc event key
 }
 
+type hepmcDecoder interface {
+	Decode(evt *hepmc.Event) error
+}
+
 func (s *HepMcStreamer) Connect(ports []fwk.Port) error {
 	var err error
-	s.r, err = os.Open(s.Name)
-	if err != nil {
-		return err
+	switch filepath.Ext(s.Name) {
+	case ".root":
+		f, err := groot.Open(s.Name)
+		if err != nil {
+			return err
+		}
+		s.r = f
+		s.dec, err = hepmc.NewRootDecoder(f)
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+	default:
+		f, err := os.Open(s.Name)
+		if err != nil {
+			return err
+		}
+		s.r = f
+		s.dec = hepmc.NewDecoder(bufio.NewReader(f))
 	}
 
-	s.dec = hepmc.NewDecoder(bufio.NewReader(s.r))
-
 	port := ports[0]
 	if port.Type != reflect.TypeOf(hepmc.Event{}) {
 		err = fmt.Errorf("fads: invalid port. expected type=hepmc.Event. got=%v", port.Type)
@@ -61,6 +81,13 @@ func (s *HepMcStreamer) Read(ctx fwk.Context) error {
 }
 
 func (s *HepMcStreamer) Disconnect() error {
+	if dec, ok := s.dec.(io.Closer); ok {
+		err := dec.Close()
+		if err != nil {
+			_ = s.r.Close()
+			return err
+		}
+	}
 	return s.r.Close()
 }
 
@@ -0,0 +1,219 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fads
+
+import (
+	"math"
+	"reflect"
+
+	"go-hep.org/x/hep/fmom"
+	"go-hep.org/x/hep/fwk"
+)
+
+type flavorclassifier struct {
+	PtMin  float64
+	EtaMax float64
+}
+
+func (cls flavorclassifier) Category(parton *Candidate) int {
+	if parton.Mom.Pt() <= cls.PtMin || math.Abs(parton.Mom.Eta()) > cls.EtaMax {
+		return -1
+	}
+	pdg := parton.Pid
+	if pdg < 0 {
+		pdg = -pdg
+	}
+
+	if pdg != 21 && pdg > 5 {
+		return -1
+	}
+
+	return 0
+}
+
+// JetFlavorAssociation associates a flavor to the input jets, from the
+// partons found inside a DeltaR-cone around the jet axis.
+//
+// The flavor of a jet is (the absolute value of) the PDG ID of:
+//  - a b-quark, if any is found inside the cone,
+//  - a c-quark, if any is found inside the cone,
+//  - the hardest light quark or gluon, otherwise.
+// Jets without any matching parton have a flavor of 0.
+type JetFlavorAssociation struct {
+	fwk.TaskBase
+
+	partons string
+	jets    string
+	output  string
+
+	dR  float64
+	cls flavorclassifier
+}
+
+func (tsk *JetFlavorAssociation) Configure(ctx fwk.Context) error {
+	var err error
+
+	err = tsk.DeclInPort(tsk.partons, reflect.TypeOf([]Candidate{}))
+	if err != nil {
+		return err
+	}
+
+	err = tsk.DeclInPort(tsk.jets, reflect.TypeOf([]Candidate{}))
+	if err != nil {
+		return err
+	}
+
+	err = tsk.DeclOutPort(tsk.output, reflect.TypeOf([]Candidate{}))
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func (tsk *JetFlavorAssociation) StartTask(ctx fwk.Context) error {
+	var err error
+
+	return err
+}
+
+func (tsk *JetFlavorAssociation) StopTask(ctx fwk.Context) error {
+	var err error
+
+	return err
+}
+
+func (tsk *JetFlavorAssociation) Process(ctx fwk.Context) error {
+	var err error
+
+	store := ctx.Store()
+	msg := ctx.Msg()
+
+	v, err := store.Get(tsk.partons)
+	if err != nil {
+		return err
+	}
+	allpartons := v.([]Candidate)
+
+	v, err = store.Get(tsk.jets)
+	if err != nil {
+		return err
+	}
+	jets := v.([]Candidate)
+
+	output := make([]Candidate, 0, len(jets))
+	defer func() {
+		err = store.Put(tsk.output, output)
+	}()
+
+	msg.Debugf("partons: %d\n", len(allpartons))
+	msg.Debugf("jets:    %d\n", len(jets))
+
+	partons := make([]Candidate, 0, len(allpartons))
+	for i := range allpartons {
+		cand := &allpartons[i]
+		if tsk.cls.Category(cand) < 0 {
+			continue
+		}
+		partons = append(partons, *cand)
+	}
+
+	for i := range jets {
+		jet := jets[i].Clone()
+		jet.Flavor = tsk.flavor(jet, partons)
+		output = append(output, *jet)
+	}
+
+	msg.Debugf("output:  %d\n", len(output))
+	return err
+}
+
+func (tsk *JetFlavorAssociation) flavor(jet *Candidate, partons []Candidate) int32 {
+	var (
+		heavy int32
+		light int32
+		ptmax = -1.0
+	)
+
+	for i := range partons {
+		p := &partons[i]
+		if fmom.DeltaR(&jet.Mom, &p.Mom) >= tsk.dR {
+			continue
+		}
+		pdg := p.Pid
+		if pdg < 0 {
+			pdg = -pdg
+		}
+		switch pdg {
+		case 4, 5:
+			if pdg > heavy {
+				heavy = pdg
+			}
+		default:
+			if pt := p.Mom.Pt(); pt > ptmax {
+				ptmax = pt
+				light = pdg
+			}
+		}
+	}
+
+	if heavy != 0 {
+		return heavy
+	}
+	return light
+}
+
+func newJetFlavorAssociation(typ, name string, mgr fwk.App) (fwk.Component, error) {
+	var err error
+
+	tsk := &JetFlavorAssociation{
+		TaskBase: fwk.NewTask(typ, name, mgr),
+		partons:  "InputPartons",
+		jets:     "InputJets",
+		output:   "OutputJets",
+
+		dR: 0.5,
+		cls: flavorclassifier{
+			PtMin:  0.0,
+			EtaMax: 2.5,
+		},
+	}
+
+	err = tsk.DeclProp("Partons", &tsk.partons)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tsk.DeclProp("Jets", &tsk.jets)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tsk.DeclProp("Output", &tsk.output)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tsk.DeclProp("DeltaR", &tsk.dR)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tsk.DeclProp("PartonPtMin", &tsk.cls.PtMin)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tsk.DeclProp("PartonEtaMax", &tsk.cls.EtaMax)
+	if err != nil {
+		return nil, err
+	}
+
+	return tsk, err
+}
+
+func init() {
+	fwk.Register(reflect.TypeOf(JetFlavorAssociation{}), newJetFlavorAssociation)
+}
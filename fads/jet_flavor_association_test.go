@@ -0,0 +1,81 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fads
+
+import (
+	"math"
+	"testing"
+)
+
+func TestJetFlavorAssociation(t *testing.T) {
+	cand := func(pid int32, pt, eta, phi float64) Candidate {
+		return Candidate{
+			Pid: pid,
+			Mom: newPtEtaPhiE(pt, eta, phi, pt*math.Cosh(eta)),
+		}
+	}
+
+	partons := []Candidate{
+		// partons around the jet at phi=0
+		cand(21, 30, 0.1, 0.1),
+		cand(2, 10, -0.1, 0.1),
+		cand(-5, 5, 0.2, -0.2),
+
+		// partons around the jet at phi=1
+		cand(1, 20, 0.0, 1.1),
+		cand(4, 2, 0.1, 0.9),
+
+		// partons around the jet at phi=2
+		cand(21, 30, 0.0, 2.2),
+		cand(3, 40, 0.0, 1.9),
+		cand(5, 0.5, 0.0, 2.0),  // below PartonPtMin
+		cand(5, 0.5, 0.0, 2.3),  // outside the cone
+		cand(6, 100, 0.0, 2.0),  // not a light quark, c-quark, b-quark nor gluon
+		cand(4, 10, 3.0, 2.0),   // above PartonEtaMax
+		cand(-4, 10, 0.0, 2.55), // outside the cone
+
+		// parton near the jet at phi=3, at eta=2.6
+		cand(5, 50, 2.4, 3.0),
+	}
+
+	jets := []Candidate{
+		cand(0, 100, 0, 0),
+		cand(0, 100, 0, 1),
+		cand(0, 100, 0, 2),
+		cand(0, 100, 2.6, 3),
+		cand(0, 100, -2, -2),
+	}
+	jets[0].BTag = 1
+
+	tsk := newTestTask(t, "JetFlavorAssociation", map[string]interface{}{
+		"Partons":      "/fads/partons",
+		"Jets":         "/fads/jets",
+		"Output":       "/fads/output",
+		"DeltaR":       0.5,
+		"PartonPtMin":  1.0,
+		"PartonEtaMax": 2.5,
+	})
+	output := runTestTask(t, tsk, "/fads/output", map[string][]Candidate{
+		"/fads/partons": partons,
+		"/fads/jets":    jets,
+	})
+
+	if got, want := len(output), len(jets); got != want {
+		t.Fatalf("invalid number of jets: got=%d, want=%d", got, want)
+	}
+
+	for i, want := range []int32{5, 4, 3, 5, 0} {
+		jet := &output[i]
+		if got := jet.Flavor; got != want {
+			t.Errorf("jet %d: invalid flavor: got=%d, want=%d", i, got, want)
+		}
+		if jet.Mom != jets[i].Mom || jet.BTag != jets[i].BTag {
+			t.Errorf("jet %d: invalid jet: got=%v, want=%v", i, jet, jets[i])
+		}
+		if jets[i].Flavor != 0 {
+			t.Errorf("jet %d: input jet modified", i)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fads
+
+import (
+	"math"
+	"reflect"
+	"sync"
+
+	"go-hep.org/x/hep/fmom"
+	"go-hep.org/x/hep/fwk"
+	"golang.org/x/exp/rand"
+)
+
+// PhotonConversions converts photons into electron-positron pairs,
+// while they fly through the tracker volume.
+//
+// The tracker volume is a cylinder of radius Radius and half-length
+// HalfLength (in meters).
+// The flight path of each photon inside that volume is divided in steps
+// of (at most) Step meters.
+// For each step, the photon converts with a probability Probability(pt, eta)*step,
+// where Probability is the conversion probability per meter.
+// The energy of the photon is shared between the electron and the positron,
+// following the Bethe-Heitler differential cross-section.
+//
+// Candidates that are not photons are passed through unmodified.
+type PhotonConversions struct {
+	fwk.TaskBase
+
+	input  string
+	output string
+
+	radius  float64
+	radius2 float64
+	halflen float64
+	step    float64
+
+	prob  func(pt, eta float64) float64
+	seed  uint64
+	src   *rand.Rand
+	srcmu sync.Mutex
+}
+
+func (tsk *PhotonConversions) Configure(ctx fwk.Context) error {
+	var err error
+
+	err = tsk.DeclInPort(tsk.input, reflect.TypeOf([]Candidate{}))
+	if err != nil {
+		return err
+	}
+
+	err = tsk.DeclOutPort(tsk.output, reflect.TypeOf([]Candidate{}))
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func (tsk *PhotonConversions) StartTask(ctx fwk.Context) error {
+	var err error
+	tsk.radius2 = tsk.radius * tsk.radius
+	tsk.src = rand.New(rand.NewSource(tsk.seed))
+	return err
+}
+
+func (tsk *PhotonConversions) StopTask(ctx fwk.Context) error {
+	var err error
+
+	return err
+}
+
+func (tsk *PhotonConversions) Process(ctx fwk.Context) error {
+	var err error
+	store := ctx.Store()
+	msg := ctx.Msg()
+
+	v, err := store.Get(tsk.input)
+	if err != nil {
+		return err
+	}
+
+	input := v.([]Candidate)
+	msg.Debugf(">>> input: %v\n", len(input))
+
+	output := make([]Candidate, 0, len(input))
+	defer func() {
+		err = store.Put(tsk.output, output)
+	}()
+
+	nconv := 0
+	for i := range input {
+		cand := &input[i]
+		if cand.Pid != 22 {
+			output = append(output, *cand)
+			continue
+		}
+
+		ee, ok := tsk.convert(cand)
+		if !ok {
+			output = append(output, *cand)
+			continue
+		}
+		nconv++
+		output = append(output, ee[:]...)
+	}
+
+	msg.Debugf(">>> conversions: %v\n", nconv)
+	msg.Debugf(">>> output: %v\n", len(output))
+
+	return err
+}
+
+// convert tries to convert the photon into an electron-positron pair.
+func (tsk *PhotonConversions) convert(cand *Candidate) ([2]Candidate, bool) {
+	var ee [2]Candidate
+
+	x := cand.Pos.X() * 1e-3
+	y := cand.Pos.Y() * 1e-3
+	z := cand.Pos.Z() * 1e-3
+
+	// is photon inside cylinder ?
+	if math.Hypot(x, y) > tsk.radius || math.Abs(z) > tsk.halflen {
+		return ee, false
+	}
+
+	px := cand.Mom.Px()
+	py := cand.Mom.Py()
+	pz := cand.Mom.Pz()
+	pt2 := px*px + py*py
+	if pt2 < 1e-9 {
+		return ee, false
+	}
+
+	// solve pt2*t^2 + 2(px.x + py.y)*t + (x.x + y.y - radius2) = 0
+	v := px*y - py*x
+	discr2 := pt2*tsk.radius2 - v*v
+	if discr2 < 0 {
+		return ee, false
+	}
+	v = px*x + py*y
+	discr := math.Sqrt(discr2)
+	t := (-v + discr) / pt2
+	if zt := z + pz*t; math.Abs(zt) > tsk.halflen {
+		t = (+tsk.halflen - z) / pz
+		if t < 0 {
+			t = (-tsk.halflen - z) / pz
+		}
+	}
+
+	p := math.Sqrt(pt2 + pz*pz)
+	length := t * p // flight distance inside the cylinder, in meters
+	if length <= 0 {
+		return ee, false
+	}
+
+	nsteps := int(math.Ceil(length / tsk.step))
+	if nsteps < 1 {
+		nsteps = 1
+	}
+	dl := length / float64(nsteps)
+	prob := tsk.prob(cand.Mom.Pt(), cand.Mom.Eta()) * dl
+
+	tsk.srcmu.Lock()
+	defer tsk.srcmu.Unlock()
+
+	for i := 0; i < nsteps; i++ {
+		if tsk.src.Float64() >= prob {
+			continue
+		}
+
+		// conversion point.
+		l := (float64(i) + 0.5) * dl
+		pos := fmom.NewPxPyPzE(
+			(x+px/p*l)*1e3,
+			(y+py/p*l)*1e3,
+			(z+pz/p*l)*1e3,
+			cand.Pos.T()+l*1e3,
+		)
+
+		frac := tsk.energyFraction()
+		for j, sign := range []int32{+1, -1} {
+			f := frac
+			if j == 1 {
+				f = 1 - frac
+			}
+			c := cand.Clone()
+			c.Pid = sign * 11
+			c.CandCharge = -sign
+			c.CandMass = 0.51099895e-3
+			c.Mom = fmom.NewPxPyPzE(f*px, f*py, f*pz, f*cand.Mom.E())
+			c.Pos = pos
+			c.Add(cand)
+			ee[j] = *c
+		}
+		return ee, true
+	}
+
+	return ee, false
+}
+
+// energyFraction returns the fraction of the photon energy carried by
+// the electron, distributed as 1 - 4/3 x(1-x).
+func (tsk *PhotonConversions) energyFraction() float64 {
+	for {
+		x := tsk.src.Float64()
+		if tsk.src.Float64() <= 1-4.0/3.0*x*(1-x) {
+			return x
+		}
+	}
+}
+
+func init() {
+	fwk.Register(reflect.TypeOf(PhotonConversions{}),
+		func(typ, name string, mgr fwk.App) (fwk.Component, error) {
+			var err error
+			tsk := &PhotonConversions{
+				TaskBase: fwk.NewTask(typ, name, mgr),
+				input:    "InputParticles",
+				output:   "OutputParticles",
+				radius:   1.0,
+				halflen:  3.0,
+				step:     0.1,
+				prob:     func(pt, eta float64) float64 { return 0 },
+				seed:     1234,
+			}
+
+			err = tsk.DeclProp("Input", &tsk.input)
+			if err != nil {
+				return nil, err
+			}
+
+			err = tsk.DeclProp("Output", &tsk.output)
+			if err != nil {
+				return nil, err
+			}
+
+			// radius of the tracker volume, in meters
+			err = tsk.DeclProp("Radius", &tsk.radius)
+			if err != nil {
+				return nil, err
+			}
+
+			// half-length of the tracker volume, in meters
+			err = tsk.DeclProp("HalfLength", &tsk.halflen)
+			if err != nil {
+				return nil, err
+			}
+
+			// maximal step length, in meters
+			err = tsk.DeclProp("Step", &tsk.step)
+			if err != nil {
+				return nil, err
+			}
+
+			// conversion probability per meter
+			err = tsk.DeclProp("Probability", &tsk.prob)
+			if err != nil {
+				return nil, err
+			}
+
+			err = tsk.DeclProp("Seed", &tsk.seed)
+			if err != nil {
+				return nil, err
+			}
+
+			return tsk, err
+		},
+	)
+}
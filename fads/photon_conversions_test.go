@@ -0,0 +1,124 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fads
+
+import (
+	"math"
+	"testing"
+
+	"go-hep.org/x/hep/fmom"
+)
+
+func TestPhotonConversions(t *testing.T) {
+	var (
+		photon = Candidate{
+			Pid: 22,
+			Mom: newPtEtaPhiE(20, 0, 0, 20),
+		}
+		outside = Candidate{
+			Pid: 22,
+			Mom: newPtEtaPhiE(20, 0, 0, 20),
+			Pos: fmom.NewPxPyPzE(2000, 0, 0, 0),
+		}
+		pion = Candidate{
+			Pid:        211,
+			CandCharge: +1,
+			Mom:        newPtEtaPhiE(20, 0, 0, 20),
+		}
+	)
+
+	props := func(prob float64) map[string]interface{} {
+		return map[string]interface{}{
+			"Input":      "/fads/input",
+			"Output":     "/fads/output",
+			"Radius":     1.0,
+			"HalfLength": 3.0,
+			"Step":       0.1,
+			"Probability": func(pt, eta float64) float64 {
+				return prob
+			},
+		}
+	}
+
+	t.Run("no-conversion", func(t *testing.T) {
+		tsk := newTestTask(t, "PhotonConversions", props(0))
+		output := runTestTask(t, tsk, "/fads/output", map[string][]Candidate{
+			"/fads/input": {photon, pion},
+		})
+		if got, want := len(output), 2; got != want {
+			t.Fatalf("invalid number of candidates: got=%d, want=%d", got, want)
+		}
+		if output[0].Pid != 22 || output[1].Pid != 211 {
+			t.Fatalf("invalid candidates: got=(%d, %d), want=(22, 211)", output[0].Pid, output[1].Pid)
+		}
+	})
+
+	t.Run("conversion", func(t *testing.T) {
+		// a probability per step larger than 1 converts all the photons
+		// inside the tracker volume at their first step.
+		tsk := newTestTask(t, "PhotonConversions", props(100))
+		output := runTestTask(t, tsk, "/fads/output", map[string][]Candidate{
+			"/fads/input": {pion, photon, outside},
+		})
+		if got, want := len(output), 4; got != want {
+			t.Fatalf("invalid number of candidates: got=%d, want=%d", got, want)
+		}
+		if output[0].Pid != 211 || output[3].Pid != 22 {
+			t.Fatalf("invalid unconverted candidates: got=(%d, %d), want=(211, 22)", output[0].Pid, output[3].Pid)
+		}
+
+		var (
+			ee  = output[1:3]
+			sum fmom.PxPyPzE
+		)
+		for i, want := range []int32{+11, -11} {
+			e := &ee[i]
+			if e.Pid != want || e.CandCharge != -want/11 {
+				t.Fatalf("invalid lepton %d: pid=%d, charge=%d", i, e.Pid, e.CandCharge)
+			}
+			if len(e.Candidates) != 1 || e.Candidates[0].Pid != 22 {
+				t.Fatalf("invalid lepton %d mother", i)
+			}
+			// conversion in the middle of the first 10cm-step along the x-axis.
+			if got, want := e.Pos, fmom.NewPxPyPzE(50, 0, 0, 50); got != want {
+				t.Fatalf("invalid conversion point: got=%v, want=%v", got, want)
+			}
+			if e.Mom.E() < 0 || e.Mom.E() > photon.Mom.E() {
+				t.Fatalf("invalid lepton %d energy: %v", i, e.Mom.E())
+			}
+			sum = fmom.NewPxPyPzE(
+				sum.Px()+e.Mom.Px(), sum.Py()+e.Mom.Py(),
+				sum.Pz()+e.Mom.Pz(), sum.E()+e.Mom.E(),
+			)
+		}
+		if !fmom.Equal(&sum, &photon.Mom) {
+			t.Fatalf("invalid e+e- momentum: got=%v, want=%v", sum, photon.Mom)
+		}
+	})
+
+	t.Run("probability", func(t *testing.T) {
+		const (
+			n    = 20000
+			prob = 0.5 // per meter
+		)
+		// photons cross 1m of tracker, in 10 steps of 10cm.
+		want := 1 - math.Pow(1-prob*0.1, 10)
+
+		input := make([]Candidate, n)
+		for i := range input {
+			input[i] = photon
+		}
+		tsk := newTestTask(t, "PhotonConversions", props(prob))
+		output := runTestTask(t, tsk, "/fads/output", map[string][]Candidate{
+			"/fads/input": input,
+		})
+
+		nconv := len(output) - n
+		got := float64(nconv) / n
+		if sigma := math.Sqrt(want * (1 - want) / n); math.Abs(got-want) > 4*sigma {
+			t.Fatalf("invalid conversion rate: got=%v, want=%v", got, want)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fads
+
+import (
+	"reflect"
+	"sync"
+
+	"go-hep.org/x/hep/fmom"
+	"go-hep.org/x/hep/fwk"
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// TimeSmearing smears the arrival time of the input candidates,
+// with a gaussian resolution.
+//
+// The time component of the candidates position is expressed in mm/c,
+// the TimeResolution property in seconds.
+type TimeSmearing struct {
+	fwk.TaskBase
+
+	input  string
+	output string
+
+	res   float64 // time resolution, in seconds
+	seed  uint64
+	src   *rand.Rand
+	srcmu sync.Mutex
+}
+
+func (tsk *TimeSmearing) Configure(ctx fwk.Context) error {
+	var err error
+
+	err = tsk.DeclInPort(tsk.input, reflect.TypeOf([]Candidate{}))
+	if err != nil {
+		return err
+	}
+
+	err = tsk.DeclOutPort(tsk.output, reflect.TypeOf([]Candidate{}))
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+func (tsk *TimeSmearing) StartTask(ctx fwk.Context) error {
+	var err error
+	tsk.src = rand.New(rand.NewSource(tsk.seed))
+	return err
+}
+
+func (tsk *TimeSmearing) StopTask(ctx fwk.Context) error {
+	var err error
+
+	return err
+}
+
+func (tsk *TimeSmearing) Process(ctx fwk.Context) error {
+	var err error
+	store := ctx.Store()
+	msg := ctx.Msg()
+
+	v, err := store.Get(tsk.input)
+	if err != nil {
+		return err
+	}
+
+	input := v.([]Candidate)
+	msg.Debugf(">>> input: %v\n", len(input))
+
+	output := make([]Candidate, 0, len(input))
+	defer func() {
+		err = store.Put(tsk.output, output)
+	}()
+
+	const cLight = 2.99792458e8
+
+	for i := range input {
+		cand := &input[i]
+		t := cand.Pos.T() * 1e-3 / cLight
+
+		// apply smearing
+		tsk.srcmu.Lock()
+		smearT := distuv.Normal{Mu: t, Sigma: tsk.res, Src: tsk.src}
+		t = smearT.Rand()
+		tsk.srcmu.Unlock()
+
+		mother := cand
+		c := cand.Clone()
+		c.Pos = fmom.NewPxPyPzE(cand.Pos.X(), cand.Pos.Y(), cand.Pos.Z(), t*1e3*cLight)
+		c.Add(mother)
+
+		output = append(output, *c)
+	}
+
+	msg.Debugf(">>> smeared: %v\n", len(output))
+
+	return err
+}
+
+func init() {
+	fwk.Register(reflect.TypeOf(TimeSmearing{}),
+		func(typ, name string, mgr fwk.App) (fwk.Component, error) {
+			var err error
+			tsk := &TimeSmearing{
+				TaskBase: fwk.NewTask(typ, name, mgr),
+				input:    "InputParticles",
+				output:   "OutputParticles",
+				res:      1e-10,
+				seed:     1234,
+			}
+
+			err = tsk.DeclProp("Input", &tsk.input)
+			if err != nil {
+				return nil, err
+			}
+
+			err = tsk.DeclProp("Output", &tsk.output)
+			if err != nil {
+				return nil, err
+			}
+
+			err = tsk.DeclProp("TimeResolution", &tsk.res)
+			if err != nil {
+				return nil, err
+			}
+
+			err = tsk.DeclProp("Seed", &tsk.seed)
+			if err != nil {
+				return nil, err
+			}
+
+			return tsk, err
+		},
+	)
+}
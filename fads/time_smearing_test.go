@@ -0,0 +1,69 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fads
+
+import (
+	"math"
+	"testing"
+
+	"go-hep.org/x/hep/fmom"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestTimeSmearing(t *testing.T) {
+	const (
+		n      = 10000
+		cLight = 2.99792458e8
+		res    = 30e-12 // in s
+		t0     = 300.0  // in mm/c
+		sigma  = res * cLight * 1e3
+	)
+
+	input := make([]Candidate, n)
+	for i := range input {
+		input[i] = Candidate{
+			Pid: 22,
+			Mom: newPtEtaPhiE(10, 0.5, 1, 10*math.Cosh(0.5)),
+			Pos: fmom.NewPxPyPzE(1, 2, 3, t0),
+		}
+	}
+
+	tsk := newTestTask(t, "TimeSmearing", map[string]interface{}{
+		"Input":          "/fads/input",
+		"Output":         "/fads/output",
+		"TimeResolution": res,
+		"Seed":           uint64(42),
+	})
+	output := runTestTask(t, tsk, "/fads/output", map[string][]Candidate{
+		"/fads/input": input,
+	})
+
+	if got, want := len(output), n; got != want {
+		t.Fatalf("invalid number of candidates: got=%d, want=%d", got, want)
+	}
+
+	ts := make([]float64, n)
+	for i := range output {
+		c := &output[i]
+		if c.Mom != input[i].Mom {
+			t.Fatalf("candidate %d: invalid momentum: got=%v, want=%v", i, c.Mom, input[i].Mom)
+		}
+		if c.Pos.X() != 1 || c.Pos.Y() != 2 || c.Pos.Z() != 3 {
+			t.Fatalf("candidate %d: invalid position: %v", i, c.Pos)
+		}
+		if len(c.Candidates) != 1 {
+			t.Fatalf("candidate %d: invalid number of mothers: %d", i, len(c.Candidates))
+		}
+		ts[i] = c.Pos.T()
+	}
+
+	mean, std := stat.MeanStdDev(ts, nil)
+	if math.Abs(mean-t0) > 4*sigma/math.Sqrt(n) {
+		t.Fatalf("invalid mean time: got=%v, want=%v", mean, t0)
+	}
+	if math.Abs(std-sigma) > 0.05*sigma {
+		t.Fatalf("invalid time resolution: got=%v, want=%v", std, sigma)
+	}
+}
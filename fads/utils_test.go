@@ -0,0 +1,105 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fads
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"go-hep.org/x/hep/fwk"
+)
+
+// testCtx is a minimal fwk.Context to run a single task outside of an application.
+type testCtx struct {
+	store testStore
+	msg   fwk.MsgStream
+}
+
+func newTestCtx() *testCtx {
+	return &testCtx{
+		store: make(testStore),
+		msg:   fwk.NewMsgStream("test", fwk.LvlError, os.Stderr),
+	}
+}
+
+func (ctx *testCtx) ID() int64          { return 0 }
+func (ctx *testCtx) Slot() int          { return 0 }
+func (ctx *testCtx) Store() fwk.Store   { return ctx.store }
+func (ctx *testCtx) Msg() fwk.MsgStream { return ctx.msg }
+
+func (ctx *testCtx) Svc(n string) (fwk.Svc, error) {
+	return nil, fmt.Errorf("no such service %q", n)
+}
+
+type testStore map[string]interface{}
+
+func (s testStore) Get(k string) (interface{}, error) {
+	v, ok := s[k]
+	if !ok {
+		return nil, fmt.Errorf("no such key %q", k)
+	}
+	return v, nil
+}
+
+func (s testStore) Put(k string, v interface{}) error {
+	s[k] = v
+	return nil
+}
+
+func (s testStore) Has(k string) bool {
+	_, ok := s[k]
+	return ok
+}
+
+// newTestTask creates and configures the fads task typ with the provided properties.
+func newTestTask(t *testing.T, typ string, props map[string]interface{}) fwk.Task {
+	t.Helper()
+
+	app := fwk.NewApp()
+	c, err := app.New("go-hep.org/x/hep/fads."+typ, typ)
+	if err != nil {
+		t.Fatalf("could not create task %q: %+v", typ, err)
+	}
+	for k, v := range props {
+		err = app.SetProp(c, k, v)
+		if err != nil {
+			t.Fatalf("could not set property %q of task %q: %+v", k, typ, err)
+		}
+	}
+	return c.(fwk.Task)
+}
+
+// runTestTask processes the provided input collections with the task and
+// returns the output collection.
+func runTestTask(t *testing.T, tsk fwk.Task, output string, inputs map[string][]Candidate) []Candidate {
+	t.Helper()
+
+	ctx := newTestCtx()
+	for k, v := range inputs {
+		ctx.store[k] = v
+	}
+
+	err := tsk.StartTask(ctx)
+	if err != nil {
+		t.Fatalf("could not start task: %+v", err)
+	}
+
+	err = tsk.Process(ctx)
+	if err != nil {
+		t.Fatalf("could not process task: %+v", err)
+	}
+
+	err = tsk.StopTask(ctx)
+	if err != nil {
+		t.Fatalf("could not stop task: %+v", err)
+	}
+
+	v, err := ctx.store.Get(output)
+	if err != nil {
+		t.Fatalf("could not retrieve output: %+v", err)
+	}
+	return v.([]Candidate)
+}
@@ -13,17 +13,25 @@ import (
 func Curve1D(f Func1D, settings *optimize.Settings, m optimize.Method) (*optimize.Result, error) {
 	f.init()
 
-	p := optimize.Problem{
-		Func: f.fct,
-		Grad: f.grad,
-		Hess: f.hess,
-	}
+	p := newProblem(&f, f.fct)
+	return p.minimize(settings, m)
+}
+
+// LeastSquares returns the result of a non-linear least squares to fit
+// a function f to the underlying data with method m, together with the
+// uncertainties on the fitted parameters.
+//
+// The parameter uncertainties are only meaningful if the errors on the
+// data points (f.Err) have been provided.
+func LeastSquares(f Func1D, settings *optimize.Settings, m optimize.Method) (*Result, error) {
+	f.init()
 
-	if m == nil {
-		m = &optimize.NelderMead{}
+	res, err := fitFunc(&f, f.fct, settings, m)
+	if err != nil {
+		return nil, err
 	}
 
-	p0 := make([]float64, len(f.Ps))
-	copy(p0, f.Ps)
-	return optimize.Minimize(p, p0, settings, m)
+	res.Chi2 = 2 * res.F
+	res.NDF = len(f.X) - len(res.prob.free)
+	return res, nil
 }
@@ -6,8 +6,7 @@
 package fit // import "go-hep.org/x/hep/fit"
 
 import (
-	"gonum.org/v1/gonum/diff/fd"
-	"gonum.org/v1/gonum/mat"
+	"math"
 )
 
 //go:generate go get github.com/campoy/embedmd
@@ -28,15 +27,103 @@ type Func1D struct {
 	// length N filled with zeros.
 	Ps []float64
 
+	// Fixed holds the indices of the parameters that are kept constant
+	// (at their initial value) during the fit.
+	Fixed []int
+
+	// Bounds holds the optional limits of each parameter.
+	// If Bounds is not nil, its length must be the number of parameters.
+	Bounds []Bound
+
 	X   []float64
 	Y   []float64
 	Err []float64
 
 	sig2 []float64 // inverse of squares of measurement errors along Y.
 
-	fct  func(ps []float64) float64 // cost function (objective function)
-	grad func(grad, ps []float64)
-	hess func(hess *mat.SymDense, x []float64)
+	fct func(ps []float64) float64 // cost function (objective function)
+}
+
+// Bound describes the allowed range of a parameter.
+//
+// One-sided limits can be described with an infinite Min or Max.
+// The zero value describes an unbounded parameter.
+type Bound struct {
+	Min float64
+	Max float64
+}
+
+func (b Bound) isZero() bool {
+	return b.Min == 0 && b.Max == 0
+}
+
+// ext converts an internal, unbounded, parameter value into the external
+// bounded one, following the MINUIT transformations.
+func (b Bound) ext(u float64) float64 {
+	lo := !math.IsInf(b.Min, -1)
+	hi := !math.IsInf(b.Max, +1)
+	switch {
+	case b.isZero():
+		return u
+	case lo && hi:
+		return b.Min + 0.5*(b.Max-b.Min)*(math.Sin(u)+1)
+	case lo:
+		return b.Min - 1 + math.Sqrt(u*u+1)
+	case hi:
+		return b.Max + 1 - math.Sqrt(u*u+1)
+	}
+	return u
+}
+
+// int converts an external, bounded, parameter value into the internal
+// unbounded one.
+func (b Bound) int(x float64) float64 {
+	lo := !math.IsInf(b.Min, -1)
+	hi := !math.IsInf(b.Max, +1)
+	switch {
+	case b.isZero():
+		return x
+	case lo && hi:
+		x = math.Max(b.Min, math.Min(b.Max, x))
+		return math.Asin(2*(x-b.Min)/(b.Max-b.Min) - 1)
+	case lo:
+		x = math.Max(b.Min, x)
+		v := x - b.Min + 1
+		return math.Sqrt(v*v - 1)
+	case hi:
+		x = math.Min(b.Max, x)
+		v := b.Max - x + 1
+		return math.Sqrt(v*v - 1)
+	}
+	return x
+}
+
+// params checks and initializes the parameters of the function.
+func (f *Func1D) params() {
+	if f.Ps == nil {
+		f.Ps = make([]float64, f.N)
+	}
+
+	if len(f.Ps) == 0 {
+		panic("fit: invalid number of initial parameters")
+	}
+
+	for _, i := range f.Fixed {
+		if i < 0 || i >= len(f.Ps) {
+			panic("fit: invalid fixed parameter index")
+		}
+	}
+
+	if f.Bounds != nil {
+		if len(f.Bounds) != len(f.Ps) {
+			panic("fit: mismatch length")
+		}
+		for _, b := range f.Bounds {
+			if !b.isZero() && b.Min >= b.Max {
+				panic("fit: invalid parameter bounds")
+			}
+		}
+	}
 }
 
 func (f *Func1D) init() {
@@ -53,13 +140,7 @@ func (f *Func1D) init() {
 		}
 	}
 
-	if f.Ps == nil {
-		f.Ps = make([]float64, f.N)
-	}
-
-	if len(f.Ps) == 0 {
-		panic("fit: invalid number of initial parameters")
-	}
+	f.params()
 
 	if len(f.X) != len(f.Y) {
 		panic("fit: mismatch length")
@@ -77,12 +158,4 @@ func (f *Func1D) init() {
 		}
 		return 0.5 * chi2
 	}
-
-	f.grad = func(grad, ps []float64) {
-		fd.Gradient(grad, f.fct, ps, nil)
-	}
-
-	f.hess = func(hess *mat.SymDense, x []float64) {
-		fd.Hessian(hess, f.fct, x, nil)
-	}
 }
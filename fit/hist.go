@@ -15,6 +15,24 @@ import (
 // In case settings is nil, the optimize.DefaultSettingsLocal is used.
 // In case m is nil, the same default optimization method than for Curve1D is used.
 func H1D(h *hbook.H1D, f Func1D, settings *optimize.Settings, m optimize.Method) (*optimize.Result, error) {
+	h1dData(h, &f)
+	return Curve1D(f, settings, m)
+}
+
+// H1DLeastSquares returns the least squares fit of histogram h with function f
+// and optimization method m, together with the uncertainties on the fitted
+// parameters.
+//
+// As for H1D, only bins with at least an entry are considered for the fit,
+// and the χ² and the number of degrees of freedom of the result are computed
+// over these bins.
+func H1DLeastSquares(h *hbook.H1D, f Func1D, settings *optimize.Settings, m optimize.Method) (*Result, error) {
+	h1dData(h, &f)
+	return LeastSquares(f, settings, m)
+}
+
+// h1dData sets the data of f to the content of the filled bins of h.
+func h1dData(h *hbook.H1D, f *Func1D) {
 	var (
 		n     = h.Len()
 		xdata = make([]float64, 0, n)
@@ -35,6 +53,4 @@ func H1D(h *hbook.H1D, f Func1D, settings *optimize.Settings, m optimize.Method)
 	f.X = xdata
 	f.Y = ydata
 	f.Err = yerrs
-
-	return Curve1D(f, settings, m)
 }
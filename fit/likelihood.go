@@ -0,0 +1,111 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fit
+
+import (
+	"fmt"
+	"math"
+
+	"go-hep.org/x/hep/hbook"
+	"gonum.org/v1/gonum/integrate/quad"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// BinnedLikelihood returns the Poisson binned maximum likelihood fit of
+// histogram h with function f and optimization method m.
+//
+// The function f gives the expected number of entries in a bin, evaluated
+// at the center of that bin.
+// All the bins, including the empty ones, are considered for the fit.
+// The χ² of the result is the Baker-Cousins likelihood ratio.
+func BinnedLikelihood(h *hbook.H1D, f Func1D, settings *optimize.Settings, m optimize.Method) (*Result, error) {
+	f.params()
+
+	var (
+		bins = h.Binning.Bins
+		xs   = make([]float64, len(bins))
+		ns   = make([]float64, len(bins))
+	)
+
+	for i, bin := range bins {
+		xs[i] = bin.XMid()
+		ns[i] = bin.SumW()
+	}
+
+	cost := func(ps []float64) float64 {
+		var nll float64
+		for i, x := range xs {
+			mu := f.F(x, ps)
+			if mu <= 0 {
+				return math.Inf(+1)
+			}
+			n := ns[i]
+			nll += mu - n
+			if n > 0 {
+				nll += n * math.Log(n/mu)
+			}
+		}
+		return nll
+	}
+
+	res, err := fitFunc(&f, cost, settings, m)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Chi2 = 2 * res.F
+	res.NDF = len(bins) - len(res.prob.free)
+	return res, nil
+}
+
+// UnbinnedLikelihood returns the extended unbinned maximum likelihood fit
+// of the samples xs with function f and optimization method m.
+//
+// The function f is the probability density function of the samples,
+// multiplied by the expected number of samples in the range rng.
+// f is numerically integrated over rng.
+//
+// The χ² of the result is NaN as there is no goodness of fit measure for
+// unbinned fits.
+func UnbinnedLikelihood(xs []float64, rng hbook.Range, f Func1D, settings *optimize.Settings, m optimize.Method) (*Result, error) {
+	f.params()
+
+	if math.IsInf(rng.Min, 0) || math.IsInf(rng.Max, 0) || rng.Min >= rng.Max {
+		return nil, fmt.Errorf("fit: invalid range [%v, %v]", rng.Min, rng.Max)
+	}
+
+	for _, x := range xs {
+		if x < rng.Min || rng.Max < x {
+			return nil, fmt.Errorf("fit: sample %v outside of range [%v, %v]", x, rng.Min, rng.Max)
+		}
+	}
+
+	const npts = 100 // number of points for the numerical integration.
+
+	cost := func(ps []float64) float64 {
+		nu := quad.Fixed(func(x float64) float64 {
+			return f.F(x, ps)
+		}, rng.Min, rng.Max, npts, nil, 0)
+
+		nll := nu
+		for _, x := range xs {
+			v := f.F(x, ps)
+			if v <= 0 {
+				return math.Inf(+1)
+			}
+			nll -= math.Log(v)
+		}
+		return nll
+	}
+
+	res, err := fitFunc(&f, cost, settings, m)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Chi2 = math.NaN()
+	res.NDF = len(xs) - len(res.prob.free)
+	return res, nil
+}
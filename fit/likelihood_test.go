@@ -0,0 +1,428 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fit_test
+
+import (
+	"math"
+	"testing"
+
+	"go-hep.org/x/hep/fit"
+	"go-hep.org/x/hep/hbook"
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestLeastSquares(t *testing.T) {
+	var (
+		xs   = []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		ys   = []float64{1.2, 2.8, 5.3, 6.9, 9.1, 11.2, 12.7, 15.1, 17.2, 18.8}
+		errs = make([]float64, len(xs))
+	)
+	for i := range errs {
+		errs[i] = 0.5
+	}
+
+	res, err := fit.LeastSquares(
+		fit.Func1D{
+			F: func(x float64, ps []float64) float64 {
+				return ps[0] + ps[1]*x
+			},
+			N:   2,
+			X:   xs,
+			Y:   ys,
+			Err: errs,
+		},
+		nil, &optimize.NelderMead{},
+	)
+	if err != nil {
+		t.Fatalf("could not fit: %+v", err)
+	}
+
+	// analytic solution of the weighted linear least squares.
+	var (
+		a = mat.NewDense(len(xs), 2, nil)
+		b = mat.NewVecDense(len(ys), ys)
+		w = 1 / (errs[0] * errs[0])
+	)
+	for i, x := range xs {
+		a.Set(i, 0, 1)
+		a.Set(i, 1, x)
+	}
+	var ata, cov mat.Dense
+	ata.Mul(a.T(), a)
+	ata.Scale(w, &ata)
+	err = cov.Inverse(&ata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var atb, want mat.VecDense
+	atb.MulVec(a.T(), b)
+	atb.ScaleVec(w, &atb)
+	want.MulVec(&cov, &atb)
+
+	for i := 0; i < 2; i++ {
+		if got, want := res.X[i], want.AtVec(i); math.Abs(got-want) > 1e-4 {
+			t.Fatalf("par[%d]: got=%v, want=%v", i, got, want)
+		}
+		for j := 0; j < 2; j++ {
+			if got, want := res.Cov.At(i, j), cov.At(i, j); math.Abs(got-want) > 1e-4 {
+				t.Fatalf("cov[%d,%d]: got=%v, want=%v", i, j, got, want)
+			}
+		}
+		if got, want := res.Errs[i], math.Sqrt(cov.At(i, i)); math.Abs(got-want) > 1e-4 {
+			t.Fatalf("err[%d]: got=%v, want=%v", i, got, want)
+		}
+	}
+
+	corr := cov.At(0, 1) / math.Sqrt(cov.At(0, 0)*cov.At(1, 1))
+	if got, want := res.Corr.At(0, 1), corr; math.Abs(got-want) > 1e-3 {
+		t.Fatalf("invalid correlation: got=%v, want=%v", got, want)
+	}
+	if got, want := res.Corr.At(1, 1), 1.0; math.Abs(got-want) > 1e-12 {
+		t.Fatalf("invalid correlation: got=%v, want=%v", got, want)
+	}
+
+	var chi2 float64
+	for i, x := range xs {
+		v := (res.X[0] + res.X[1]*x - ys[i]) / errs[i]
+		chi2 += v * v
+	}
+	if got, want := res.Chi2, chi2; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("invalid chi2: got=%v, want=%v", got, want)
+	}
+	if got, want := res.NDF, 8; got != want {
+		t.Fatalf("invalid ndf: got=%d, want=%d", got, want)
+	}
+	if got, want := res.Chi2NDF(), chi2/8; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("invalid chi2/ndf: got=%v, want=%v", got, want)
+	}
+
+	// for a linear model, MINOS errors are the parabolic errors.
+	for i := 0; i < 2; i++ {
+		lo, hi, err := res.Minos(i)
+		if err != nil {
+			t.Fatalf("could not run minos on par[%d]: %+v", i, err)
+		}
+		if want := res.Errs[i]; math.Abs(lo+want) > 1e-2*want || math.Abs(hi-want) > 1e-2*want {
+			t.Fatalf("par[%d]: invalid minos errors: got=[%v, %v], want=±%v", i, lo, hi, want)
+		}
+	}
+}
+
+func TestFixedBounds(t *testing.T) {
+	var (
+		xs   = []float64{0, 1, 2, 3, 4}
+		ys   = []float64{1, 3, 5, 7, 9}
+		errs = []float64{1, 1, 1, 1, 1}
+		line = func(x float64, ps []float64) float64 {
+			return ps[0] + ps[1]*x
+		}
+	)
+
+	t.Run("fixed", func(t *testing.T) {
+		res, err := fit.LeastSquares(
+			fit.Func1D{
+				F:     line,
+				Ps:    []float64{0, 1},
+				Fixed: []int{0},
+				X:     xs,
+				Y:     ys,
+				Err:   errs,
+			},
+			nil, nil,
+		)
+		if err != nil {
+			t.Fatalf("could not fit: %+v", err)
+		}
+		if got, want := res.X[0], 0.0; got != want {
+			t.Fatalf("fixed parameter changed: got=%v, want=%v", got, want)
+		}
+		// slope of the least squares line through the origin.
+		if got, want := res.X[1], 70.0/30.0; math.Abs(got-want) > 1e-4 {
+			t.Fatalf("invalid slope: got=%v, want=%v", got, want)
+		}
+		if got, want := res.Errs[0], 0.0; got != want {
+			t.Fatalf("invalid error on fixed parameter: got=%v, want=%v", got, want)
+		}
+		if got, want := res.NDF, 4; got != want {
+			t.Fatalf("invalid ndf: got=%d, want=%d", got, want)
+		}
+		_, _, err = res.Minos(0)
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+	})
+
+	t.Run("bounds", func(t *testing.T) {
+		res, err := fit.LeastSquares(
+			fit.Func1D{
+				F:      line,
+				Ps:     []float64{2, 1},
+				Bounds: []fit.Bound{{Min: 1.5, Max: 3}, {}},
+				X:      xs,
+				Y:      ys,
+				Err:    errs,
+			},
+			nil, nil,
+		)
+		if err != nil {
+			t.Fatalf("could not fit: %+v", err)
+		}
+		if got, want := res.X[0], 1.5; math.Abs(got-want) > 1e-3 {
+			t.Fatalf("parameter not at its bound: got=%v, want=%v", got, want)
+		}
+
+		lo, _, err := res.Minos(0)
+		if err != nil {
+			t.Fatalf("could not run minos: %+v", err)
+		}
+		if got, want := lo, 1.5-res.X[0]; math.Abs(got-want) > 1e-12 {
+			t.Fatalf("invalid lower minos error at bound: got=%v, want=%v", got, want)
+		}
+	})
+
+	t.Run("one-sided", func(t *testing.T) {
+		res, err := fit.LeastSquares(
+			fit.Func1D{
+				F:      line,
+				Ps:     []float64{0, 1},
+				Bounds: []fit.Bound{{}, {Min: math.Inf(-1), Max: 1.5}},
+				X:      xs,
+				Y:      ys,
+				Err:    errs,
+			},
+			nil, nil,
+		)
+		if err != nil {
+			t.Fatalf("could not fit: %+v", err)
+		}
+		if got := res.X[1]; got > 1.5 {
+			t.Fatalf("parameter out of its bound: got=%v", got)
+		}
+	})
+}
+
+func TestBinnedLikelihood(t *testing.T) {
+	dist := distuv.Normal{
+		Mu:    2,
+		Sigma: 4,
+		Src:   rand.New(rand.NewSource(1234)),
+	}
+
+	h := hbook.NewH1D(40, -20, +20)
+	for i := 0; i < 1000; i++ {
+		h.Fill(dist.Rand(), 1)
+	}
+
+	gauss := func(x float64, ps []float64) float64 {
+		v := (x - ps[1]) / ps[2]
+		return ps[0] * math.Exp(-0.5*v*v)
+	}
+
+	res, err := fit.BinnedLikelihood(
+		h,
+		fit.Func1D{
+			F:  gauss,
+			Ps: []float64{50, 0, 3},
+		},
+		nil, &optimize.NelderMead{},
+	)
+	if err != nil {
+		t.Fatalf("could not fit: %+v", err)
+	}
+
+	// the Poisson likelihood conserves the number of entries.
+	var sum float64
+	for _, bin := range h.Binning.Bins {
+		sum += gauss(bin.XMid(), res.X)
+	}
+	if got, want := sum, h.SumW(); math.Abs(got-want) > 1e-2 {
+		t.Fatalf("invalid number of fitted entries: got=%v, want=%v", got, want)
+	}
+
+	for i, want := range []float64{dist.Mu, dist.Sigma} {
+		if got, err := res.X[i+1], res.Errs[i+1]; math.Abs(got-want) > 3*err {
+			t.Fatalf("par[%d]: got=%v±%v, want=%v", i+1, got, err, want)
+		}
+	}
+
+	// the error on the mean is close to sigma/sqrt(N).
+	if got, want := res.Errs[1], res.X[2]/math.Sqrt(h.SumW()); math.Abs(got-want) > 0.05*want {
+		t.Fatalf("invalid error on mean: got=%v, want=%v", got, want)
+	}
+
+	if got, want := res.NDF, 40-3; got != want {
+		t.Fatalf("invalid ndf: got=%d, want=%d", got, want)
+	}
+	if res.Chi2 <= 0 || math.IsNaN(res.Chi2) {
+		t.Fatalf("invalid chi2: %v", res.Chi2)
+	}
+
+	lo, hi, err := res.Minos(2)
+	if err != nil {
+		t.Fatalf("could not run minos: %+v", err)
+	}
+	if want := res.Errs[2]; math.Abs(lo+want) > 0.1*want || math.Abs(hi-want) > 0.1*want {
+		t.Fatalf("invalid minos errors: got=[%v, %v], want≈±%v", lo, hi, want)
+	}
+}
+
+func TestH1DLeastSquares(t *testing.T) {
+	dist := distuv.Normal{
+		Mu:    2,
+		Sigma: 4,
+		Src:   rand.New(rand.NewSource(1234)),
+	}
+
+	// the tails of the histogram hold empty bins.
+	h := hbook.NewH1D(60, -30, +30)
+	for i := 0; i < 1000; i++ {
+		h.Fill(dist.Rand(), 1)
+	}
+
+	gauss := func(x float64, ps []float64) float64 {
+		v := (x - ps[1]) / ps[2]
+		return ps[0] * math.Exp(-0.5*v*v)
+	}
+
+	res, err := fit.H1DLeastSquares(
+		h,
+		fit.Func1D{
+			F:  gauss,
+			Ps: []float64{50, 0, 3},
+		},
+		nil, &optimize.NelderMead{},
+	)
+	if err != nil {
+		t.Fatalf("could not fit: %+v", err)
+	}
+
+	for i, want := range []float64{dist.Mu, dist.Sigma} {
+		if got, err := res.X[i+1], res.Errs[i+1]; math.Abs(got-want) > 3*err {
+			t.Fatalf("par[%d]: got=%v±%v, want=%v", i+1, got, err, want)
+		}
+	}
+
+	var (
+		chi2  float64
+		nbins int
+	)
+	for _, bin := range h.Binning.Bins {
+		if bin.Entries() <= 0 {
+			continue
+		}
+		v := (gauss(bin.XMid(), res.X) - bin.SumW()) / bin.ErrW()
+		chi2 += v * v
+		nbins++
+	}
+	if nbins == h.Len() {
+		t.Fatalf("no empty bin in histogram")
+	}
+	if got, want := res.Chi2, chi2; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("invalid chi2: got=%v, want=%v", got, want)
+	}
+	if got, want := res.NDF, nbins-3; got != want {
+		t.Fatalf("invalid ndf: got=%d, want=%d", got, want)
+	}
+	if got := res.Chi2NDF(); got > 2 {
+		t.Fatalf("invalid chi2/ndf: %v", got)
+	}
+
+	// H1D and H1DLeastSquares agree on the best-fit parameters.
+	ref, err := fit.H1D(
+		h,
+		fit.Func1D{
+			F:  gauss,
+			Ps: []float64{50, 0, 3},
+		},
+		nil, &optimize.NelderMead{},
+	)
+	if err != nil {
+		t.Fatalf("could not fit: %+v", err)
+	}
+	for i := range ref.X {
+		if got, want := res.X[i], ref.X[i]; math.Abs(got-want) > 1e-6*math.Max(1, math.Abs(want)) {
+			t.Fatalf("par[%d]: got=%v, want=%v", i, got, want)
+		}
+	}
+}
+
+func TestUnbinnedLikelihood(t *testing.T) {
+	const (
+		tau = 2.0
+		n   = 500
+	)
+
+	var (
+		src = rand.New(rand.NewSource(1234))
+		rng = hbook.Range{Min: 0, Max: 10}
+		xs  = make([]float64, 0, n)
+	)
+	for len(xs) < n {
+		x := src.ExpFloat64() * tau
+		if x > rng.Max {
+			continue
+		}
+		xs = append(xs, x)
+	}
+
+	expo := func(x float64, ps []float64) float64 {
+		norm := ps[1] * (1 - math.Exp(-rng.Max/ps[1]))
+		return ps[0] * math.Exp(-x/ps[1]) / norm
+	}
+
+	res, err := fit.UnbinnedLikelihood(
+		xs, rng,
+		fit.Func1D{
+			F:      expo,
+			Ps:     []float64{400, 1},
+			Bounds: []fit.Bound{{}, {Min: 0.1, Max: math.Inf(+1)}},
+		},
+		nil, &optimize.NelderMead{},
+	)
+	if err != nil {
+		t.Fatalf("could not fit: %+v", err)
+	}
+
+	// the extended likelihood fits the number of samples.
+	if got, want := res.X[0], float64(n); math.Abs(got-want) > 1e-2*want {
+		t.Fatalf("invalid yield: got=%v, want=%v", got, want)
+	}
+	if got, want := res.Errs[0], math.Sqrt(n); math.Abs(got-want) > 0.05*want {
+		t.Fatalf("invalid error on yield: got=%v, want=%v", got, want)
+	}
+	if got, err := res.X[1], res.Errs[1]; math.Abs(got-tau) > 3*err {
+		t.Fatalf("invalid lifetime: got=%v±%v, want=%v", got, err, tau)
+	}
+	if math.Abs(res.Corr.At(0, 1)) > 0.1 {
+		t.Fatalf("invalid correlation: %v", res.Corr.At(0, 1))
+	}
+	if !math.IsNaN(res.Chi2) {
+		t.Fatalf("invalid chi2: %v", res.Chi2)
+	}
+
+	lo, hi, err := res.Minos(1)
+	if err != nil {
+		t.Fatalf("could not run minos: %+v", err)
+	}
+	if !(lo < 0 && hi > 0) {
+		t.Fatalf("invalid minos errors: [%v, %v]", lo, hi)
+	}
+	// the profile likelihood of a lifetime is skewed towards large values.
+	if !(hi > -lo) {
+		t.Fatalf("invalid minos asymmetry: [%v, %v]", lo, hi)
+	}
+
+	_, err = fit.UnbinnedLikelihood(
+		[]float64{-1}, rng,
+		fit.Func1D{F: expo, Ps: []float64{1, 1}},
+		nil, nil,
+	)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
@@ -0,0 +1,384 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fit
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// Result holds the result of a fit, together with the uncertainties
+// on the fitted parameters.
+//
+// All the cost functions minimized by this package are normalized such
+// that a change of 0.5 units corresponds to a one standard deviation
+// change of a parameter (as a negative log-likelihood.)
+type Result struct {
+	// Result is the result of the minimization.
+	// X holds the best-fit values of all the parameters, including the
+	// fixed ones.
+	optimize.Result
+
+	Errs []float64     // symmetric (parabolic) errors of the parameters
+	Cov  *mat.SymDense // covariance matrix of the parameters
+	Corr *mat.SymDense // correlation matrix of the parameters
+
+	Chi2 float64 // χ² at the minimum
+	NDF  int     // number of degrees of freedom
+
+	prob     *problem
+	settings *optimize.Settings
+	method   optimize.Method
+}
+
+// Chi2NDF returns the reduced χ² of the fit.
+func (res *Result) Chi2NDF() float64 {
+	return res.Chi2 / float64(res.NDF)
+}
+
+// Minos returns the asymmetric errors of the i-th parameter, computed
+// from the scan of the profile of the cost function, as MINOS does.
+//
+// The lower error lo is negative and the upper error hi is positive.
+// If a parameter bound is reached before the profile crosses the
+// 1 standard deviation threshold, the distance to that bound is returned.
+func (res *Result) Minos(i int) (lo, hi float64, err error) {
+	if i < 0 || i >= len(res.X) {
+		return 0, 0, fmt.Errorf("fit: invalid parameter index %d", i)
+	}
+	if res.prob.isFixed(i) {
+		return 0, 0, fmt.Errorf("fit: parameter %d is fixed", i)
+	}
+
+	lo, err = res.minos(i, -1)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	hi, err = res.minos(i, +1)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return lo, hi, nil
+}
+
+func (res *Result) minos(i int, dir float64) (float64, error) {
+	const (
+		up    = 0.5
+		niter = 64
+	)
+
+	var (
+		x0   = res.X[i]
+		step = res.Errs[i]
+		lim  = math.Inf(int(dir))
+	)
+
+	if step <= 0 || math.IsNaN(step) {
+		step = 1e-3 * math.Max(1, math.Abs(x0))
+	}
+	if b := res.prob.bound(i); !b.isZero() {
+		switch {
+		case dir < 0:
+			lim = b.Min
+		default:
+			lim = b.Max
+		}
+	}
+	tol := 1e-3 * step
+
+	profile := func(v float64) (float64, error) {
+		p := res.prob.profile(res.X, i, v)
+		o, err := p.minimize(res.settings, res.method)
+		if err != nil {
+			return 0, fmt.Errorf("fit: could not minimize profile of parameter %d: %w", i, err)
+		}
+		return o.F - res.F - up, nil
+	}
+
+	// bracket the crossing point.
+	var (
+		a = x0
+		b = x0 + dir*step
+	)
+	for iter := 0; ; iter++ {
+		if iter >= niter {
+			return 0, fmt.Errorf("fit: could not bracket MINOS error of parameter %d", i)
+		}
+		if (b-lim)*dir >= 0 {
+			b = lim
+		}
+		v, err := profile(b)
+		if err != nil {
+			return 0, err
+		}
+		if v >= 0 {
+			break
+		}
+		if b == lim {
+			return lim - x0, nil
+		}
+		a = b
+		step *= 2
+		b = x0 + dir*step
+	}
+
+	// bisect the crossing point.
+	for iter := 0; iter < niter && math.Abs(b-a) > tol; iter++ {
+		mid := 0.5 * (a + b)
+		v, err := profile(mid)
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case v < 0:
+			a = mid
+		default:
+			b = mid
+		}
+	}
+
+	return 0.5*(a+b) - x0, nil
+}
+
+// covariance computes the covariance and correlation matrices from the
+// Hessian of the cost function at the minimum.
+func (res *Result) covariance() error {
+	var (
+		n     = len(res.X)
+		free  = res.prob.free
+		hess  = mat.NewSymDense(len(free), nil)
+		scale = make([]float64, len(free))
+	)
+
+	// the Hessian is computed with steps relative to the magnitude
+	// of each parameter.
+	for j, i := range free {
+		scale[j] = math.Abs(res.X[i])
+		if scale[j] == 0 {
+			scale[j] = 1
+		}
+	}
+
+	fct := func(z []float64) float64 {
+		ps := make([]float64, n)
+		copy(ps, res.X)
+		for j, i := range free {
+			ps[i] += scale[j] * z[j]
+		}
+		return res.prob.cost(ps)
+	}
+	fd.Hessian(hess, fct, make([]float64, len(free)), &fd.Settings{
+		Formula: fd.Central,
+		Step:    1e-3,
+	})
+	for j := range free {
+		for l := j; l < len(free); l++ {
+			hess.SetSym(j, l, hess.At(j, l)/(scale[j]*scale[l]))
+		}
+	}
+
+	var chol mat.Cholesky
+	if ok := chol.Factorize(hess); !ok {
+		return fmt.Errorf("fit: Hessian matrix is not positive definite")
+	}
+
+	var inv mat.SymDense
+	err := chol.InverseTo(&inv)
+	if err != nil {
+		return fmt.Errorf("fit: could not invert Hessian matrix: %w", err)
+	}
+
+	res.Errs = make([]float64, n)
+	res.Cov = mat.NewSymDense(n, nil)
+	res.Corr = mat.NewSymDense(n, nil)
+	for j, i := range free {
+		res.Errs[i] = math.Sqrt(inv.At(j, j))
+		for l, k := range free {
+			res.Cov.SetSym(i, k, inv.At(j, l))
+		}
+	}
+	for _, i := range free {
+		for _, k := range free {
+			res.Corr.SetSym(i, k, res.Cov.At(i, k)/(res.Errs[i]*res.Errs[k]))
+		}
+	}
+
+	return nil
+}
+
+// problem describes the minimization of a cost function with respect
+// to a set of free, possibly bounded, parameters.
+//
+// The minimizer works with internal unbounded parameters that are
+// converted into the external parameters of the cost function.
+type problem struct {
+	cost   func(ps []float64) float64 // cost function of the external parameters
+	ps     []float64                  // initial values of all the external parameters
+	free   []int                      // indices of the free parameters
+	bounds []Bound                    // limits of the parameters
+}
+
+func newProblem(f *Func1D, cost func(ps []float64) float64) *problem {
+	p := &problem{
+		cost:   cost,
+		ps:     make([]float64, len(f.Ps)),
+		free:   make([]int, 0, len(f.Ps)),
+		bounds: f.Bounds,
+	}
+	copy(p.ps, f.Ps)
+
+	fixed := make(map[int]bool, len(f.Fixed))
+	for _, i := range f.Fixed {
+		fixed[i] = true
+	}
+	for i := range p.ps {
+		if fixed[i] {
+			continue
+		}
+		p.free = append(p.free, i)
+	}
+
+	return p
+}
+
+// profile returns the problem where the i-th parameter is fixed to v and
+// the other parameters start from ps.
+func (p *problem) profile(ps []float64, i int, v float64) *problem {
+	o := &problem{
+		cost:   p.cost,
+		ps:     make([]float64, len(ps)),
+		free:   make([]int, 0, len(p.free)),
+		bounds: p.bounds,
+	}
+	copy(o.ps, ps)
+	o.ps[i] = v
+	for _, j := range p.free {
+		if j == i {
+			continue
+		}
+		o.free = append(o.free, j)
+	}
+	return o
+}
+
+func (p *problem) isFixed(i int) bool {
+	for _, j := range p.free {
+		if j == i {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *problem) bound(i int) Bound {
+	if p.bounds == nil {
+		return Bound{}
+	}
+	return p.bounds[i]
+}
+
+// identity returns whether internal and external parameters are the same.
+func (p *problem) identity() bool {
+	if len(p.free) != len(p.ps) {
+		return false
+	}
+	for _, b := range p.bounds {
+		if !b.isZero() {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *problem) ext(u []float64) []float64 {
+	ps := make([]float64, len(p.ps))
+	copy(ps, p.ps)
+	for j, i := range p.free {
+		ps[i] = p.bound(i).ext(u[j])
+	}
+	return ps
+}
+
+func (p *problem) fct(u []float64) float64 {
+	return p.cost(p.ext(u))
+}
+
+// minimize minimizes the cost function with the optimization method m.
+// The returned result holds the external parameters.
+func (p *problem) minimize(settings *optimize.Settings, m optimize.Method) (*optimize.Result, error) {
+	if len(p.free) == 0 {
+		ps := make([]float64, len(p.ps))
+		copy(ps, p.ps)
+		return &optimize.Result{
+			Location: optimize.Location{X: ps, F: p.cost(ps)},
+			Status:   optimize.Success,
+		}, nil
+	}
+
+	if m == nil {
+		m = &optimize.NelderMead{}
+	}
+
+	prob := optimize.Problem{
+		Func: p.fct,
+		Grad: func(grad, u []float64) {
+			fd.Gradient(grad, p.fct, u, nil)
+		},
+		Hess: func(hess *mat.SymDense, u []float64) {
+			fd.Hessian(hess, p.fct, u, nil)
+		},
+	}
+
+	u0 := make([]float64, len(p.free))
+	for j, i := range p.free {
+		u0[j] = p.bound(i).int(p.ps[i])
+	}
+
+	res, err := optimize.Minimize(prob, u0, settings, m)
+	if res == nil {
+		return res, err
+	}
+
+	if !p.identity() {
+		res.X = p.ext(res.X)
+		res.Gradient = nil
+		res.Hessian = nil
+	}
+	return res, err
+}
+
+// fitFunc minimizes the cost function of f and computes the uncertainties
+// on the fitted parameters.
+func fitFunc(f *Func1D, cost func(ps []float64) float64, settings *optimize.Settings, m optimize.Method) (*Result, error) {
+	p := newProblem(f, cost)
+	if len(p.free) == 0 {
+		return nil, fmt.Errorf("fit: no free parameter to fit")
+	}
+
+	o, err := p.minimize(settings, m)
+	if err != nil {
+		return nil, fmt.Errorf("fit: could not minimize cost function: %w", err)
+	}
+
+	res := &Result{
+		Result:   *o,
+		prob:     p,
+		settings: settings,
+		method:   m,
+	}
+
+	err = res.covariance()
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
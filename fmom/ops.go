@@ -195,3 +195,31 @@ func Boost(p P4, vec r3.Vec) P4 {
 func vecDot(u, v r3.Vec) float64 {
 	return u.Dot(v)
 }
+
+// RestFrame returns copies of the provided four-vectors, boosted
+// into the center-of-mass frame of the subset of four-vectors
+// whose indices are given.
+// If no index is given, the center-of-mass frame of all the four-vectors is used.
+// RestFrame panics if the sum of the subset isn't a timelike four-vector.
+func RestFrame(ps []P4, subset ...int) []P4 {
+	var sum PxPyPzE
+	switch len(subset) {
+	case 0:
+		for _, p := range ps {
+			IAdd(&sum, p)
+		}
+	default:
+		for _, i := range subset {
+			IAdd(&sum, ps[i])
+		}
+	}
+
+	var (
+		vec = BoostOf(&sum).Scale(-1)
+		out = make([]P4, len(ps))
+	)
+	for i, p := range ps {
+		out[i] = Boost(p, vec)
+	}
+	return out
+}
@@ -390,3 +390,52 @@ func TestBoostOf(t *testing.T) {
 		})
 	}
 }
+
+func TestRestFrame(t *testing.T) {
+	ps := []P4{
+		newPxPyPzE(NewPxPyPzE(10, 20, 30, 50)),
+		newPtEtaPhiM(NewPxPyPzE(-5, 2, 40, 60)),
+		newPxPyPzE(NewPxPyPzE(1, 2, 3, 10)),
+	}
+
+	for _, tc := range []struct {
+		name   string
+		subset []int
+	}{
+		{name: "all"},
+		{name: "subset", subset: []int{0, 1}},
+		{name: "single", subset: []int{2}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			subset := tc.subset
+			if len(subset) == 0 {
+				subset = []int{0, 1, 2}
+			}
+
+			var sum PxPyPzE
+			for _, i := range subset {
+				IAdd(&sum, ps[i])
+			}
+
+			out := RestFrame(ps, tc.subset...)
+			if len(out) != len(ps) {
+				t.Fatalf("invalid number of 4-vectors: got=%d, want=%d", len(out), len(ps))
+			}
+
+			var cm PxPyPzE
+			for _, i := range subset {
+				IAdd(&cm, out[i])
+			}
+			want := NewPxPyPzE(0, 0, 0, sum.M())
+			if !p4equal(&cm, &want, 1e-12) {
+				t.Fatalf("invalid center-of-mass 4-vector: got=%v, want=%v", cm, want)
+			}
+
+			for i := range ps {
+				if got, want := out[i].M(), ps[i].M(); !cmpeq(got, want, 1e-10) {
+					t.Fatalf("invalid mass[%d]: got=%v, want=%v", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,411 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmom
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// Thrust returns the thrust of the provided set of 4-vectors,
+// together with the (unit) thrust axis.
+//
+//  T = max_n Σ|p_i·n| / Σ|p_i|
+//
+// The maximization is performed exactly: the optimal axis is the sum of
+// the momenta of one of the hemispheres delimited by a plane, and each
+// of these partitions is obtained by rotating a plane containing two
+// non-collinear momenta.
+// Momenta lying in such a plane (e.g. for planar events) are assigned
+// to hemispheres by solving the corresponding 2D problem exactly.
+// This is an O(n^3) algorithm.
+// The sign of the returned axis is arbitrary.
+func Thrust(ps []P4) (float64, r3.Vec) {
+	var (
+		vs   = make([]r3.Vec, 0, len(ps))
+		norm = 0.0
+	)
+	for _, p := range ps {
+		v := vecOf(p)
+		mag := vecNorm(v)
+		if mag == 0 {
+			continue
+		}
+		vs = append(vs, v)
+		norm += mag
+	}
+
+	if norm == 0 {
+		return 0, r3.Vec{}
+	}
+
+	var (
+		tmax  = 0.0
+		axis  r3.Vec
+		plane = make([]int, 0, len(vs))
+	)
+	for i := range vs {
+		for j := i + 1; j < len(vs); j++ {
+			n := vs[i].Cross(vs[j])
+			if isZero(n, vs[i], vs[j]) {
+				continue
+			}
+
+			// sum the momenta on each side of the (vi,vj) plane, and
+			// collect the ones lying in that plane.
+			var sum r3.Vec
+			plane = plane[:0]
+			for k, vk := range vs {
+				switch d := vk.Dot(n); {
+				case isZero(r3.Vec{X: d}, vk, n):
+					plane = append(plane, k)
+				case d > 0:
+					sum = sum.Add(vk)
+				default:
+					sum = sum.Sub(vk)
+				}
+			}
+
+			if !isFirstPair(vs, plane, i, j) {
+				// this plane has already been considered.
+				continue
+			}
+
+			if t, v := thrustPlane(vs, plane, n, sum); t > tmax {
+				tmax = t
+				axis = v
+			}
+		}
+	}
+
+	if tmax == 0 {
+		// all momenta are collinear.
+		var sum r3.Vec
+		for _, v := range vs {
+			if v.Dot(vs[0]) >= 0 {
+				sum = sum.Add(v)
+			} else {
+				sum = sum.Sub(v)
+			}
+		}
+		tmax = vecNorm(sum)
+		axis = sum
+	}
+
+	return tmax / norm, vecUnit(axis)
+}
+
+// thrustPlane returns the norm of the largest sum of momenta for
+// all the partitions of the momenta lying in the plane of normal n,
+// the momenta outside of that plane being already summed in out,
+// together with that sum.
+//
+// The in-plane momenta are partitioned by all the lines of the plane
+// going through one of them, the momenta collinear to that line being
+// put on either side.
+func thrustPlane(vs []r3.Vec, plane []int, n, out r3.Vec) (float64, r3.Vec) {
+	var (
+		tmax = 0.0
+		axis r3.Vec
+	)
+	for _, l := range plane {
+		var (
+			vl  = vs[l]
+			u   = n.Cross(vl) // in-plane direction, orthogonal to vl.
+			sum r3.Vec        // in-plane momenta, on each side of the line.
+			col r3.Vec        // in-plane momenta, collinear to the line.
+		)
+		for _, k := range plane {
+			vk := vs[k]
+			switch d := vk.Dot(u); {
+			case isZero(r3.Vec{X: d}, vk, u):
+				if vk.Dot(vl) >= 0 {
+					col = col.Add(vk)
+				} else {
+					col = col.Sub(vk)
+				}
+			case d > 0:
+				sum = sum.Add(vk)
+			default:
+				sum = sum.Sub(vk)
+			}
+		}
+
+		for _, v := range []r3.Vec{
+			out.Add(sum).Add(col),
+			out.Add(sum).Sub(col),
+			out.Sub(sum).Add(col),
+			out.Sub(sum).Sub(col),
+		} {
+			if t := vecNorm(v); t > tmax {
+				tmax = t
+				axis = v
+			}
+		}
+	}
+	return tmax, axis
+}
+
+// isFirstPair returns whether (i,j) is the first pair of non-collinear
+// momenta, in index order, spanning the plane holding the momenta
+// indexed by plane.
+func isFirstPair(vs []r3.Vec, plane []int, i, j int) bool {
+	if plane[0] != i {
+		return false
+	}
+	for _, k := range plane[1:] {
+		if k >= j {
+			return true
+		}
+		if !isZero(vs[i].Cross(vs[k]), vs[i], vs[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isZero returns whether the product v of the vectors a and b is
+// compatible with zero, given the floating point precision.
+func isZero(v, a, b r3.Vec) bool {
+	const eps = 1e-12
+	return vecNorm(v) <= eps*vecNorm(a)*vecNorm(b)
+}
+
+// MomentumTensor is the generalized (r-)momentum tensor of a set of 4-vectors:
+//
+//  S^{ab} = Σ |p_i|^(r-2) p_i^a p_i^b / Σ |p_i|^r
+//
+// The sphericity tensor corresponds to r=2, the linearized
+// (infrared safe) tensor used for the C and D parameters to r=1.
+type MomentumTensor struct {
+	Lambdas [3]float64 // eigenvalues, in decreasing order
+	Axes    [3]r3.Vec  // eigenvectors, ordered as the eigenvalues
+}
+
+// NewMomentumTensor computes the r-momentum tensor of the provided
+// set of 4-vectors.
+// NewMomentumTensor panics if the eigen-decomposition of the tensor fails.
+func NewMomentumTensor(ps []P4, r float64) MomentumTensor {
+	var (
+		t    MomentumTensor
+		norm = 0.0
+		data = make([]float64, 9)
+	)
+
+	for _, p := range ps {
+		v := vecOf(p)
+		mag := vecNorm(v)
+		if mag == 0 {
+			continue
+		}
+		w := math.Pow(mag, r-2)
+		vs := [3]float64{v.X, v.Y, v.Z}
+		for i := range vs {
+			for j := range vs {
+				data[3*i+j] += w * vs[i] * vs[j]
+			}
+		}
+		norm += math.Pow(mag, r)
+	}
+
+	if norm == 0 {
+		return t
+	}
+
+	for i := range data {
+		data[i] /= norm
+	}
+
+	var eig mat.EigenSym
+	if !eig.Factorize(mat.NewSymDense(3, data), true) {
+		panic("fmom: could not eigen-decompose momentum tensor")
+	}
+
+	var (
+		vals = eig.Values(nil)
+		vecs mat.Dense
+		idx  = []int{0, 1, 2}
+	)
+	eig.VectorsTo(&vecs)
+	sort.Slice(idx, func(i, j int) bool {
+		return vals[idx[i]] > vals[idx[j]]
+	})
+
+	for i, j := range idx {
+		t.Lambdas[i] = vals[j]
+		t.Axes[i] = r3.Vec{X: vecs.At(0, j), Y: vecs.At(1, j), Z: vecs.At(2, j)}
+	}
+
+	return t
+}
+
+// Sphericity returns 3/2 (λ2+λ3).
+func (t MomentumTensor) Sphericity() float64 {
+	return 1.5 * (t.Lambdas[1] + t.Lambdas[2])
+}
+
+// Aplanarity returns 3/2 λ3.
+func (t MomentumTensor) Aplanarity() float64 {
+	return 1.5 * t.Lambdas[2]
+}
+
+// Planarity returns λ2-λ3.
+func (t MomentumTensor) Planarity() float64 {
+	return t.Lambdas[1] - t.Lambdas[2]
+}
+
+// C returns the C parameter, 3 (λ1λ2 + λ1λ3 + λ2λ3).
+// C is conventionally computed from the linearized (r=1) tensor.
+func (t MomentumTensor) C() float64 {
+	l := t.Lambdas
+	return 3 * (l[0]*l[1] + l[0]*l[2] + l[1]*l[2])
+}
+
+// D returns the D parameter, 27 λ1λ2λ3.
+// D is conventionally computed from the linearized (r=1) tensor.
+func (t MomentumTensor) D() float64 {
+	l := t.Lambdas
+	return 27 * l[0] * l[1] * l[2]
+}
+
+// FoxWolfram returns the Fox-Wolfram moments H_0...H_lmax of the
+// provided set of 4-vectors:
+//
+//  H_l = Σ_ij |p_i| |p_j| P_l(cos θ_ij) / E_vis^2
+//
+// where P_l is the Legendre polynomial of order l and E_vis is the
+// sum of the energies of the 4-vectors.
+func FoxWolfram(ps []P4, lmax int) []float64 {
+	var (
+		hs   = make([]float64, lmax+1)
+		evis = 0.0
+		vs   = make([]r3.Vec, 0, len(ps))
+		ms   = make([]float64, 0, len(ps))
+		pl   = make([]float64, lmax+1)
+	)
+
+	for _, p := range ps {
+		evis += p.E()
+		v := vecOf(p)
+		mag := vecNorm(v)
+		if mag == 0 {
+			continue
+		}
+		vs = append(vs, v.Scale(1/mag))
+		ms = append(ms, mag)
+	}
+
+	if evis == 0 {
+		return hs
+	}
+
+	for i := range vs {
+		for j := range vs {
+			legendre(pl, vs[i].Dot(vs[j]))
+			w := ms[i] * ms[j]
+			for l := range hs {
+				hs[l] += w * pl[l]
+			}
+		}
+	}
+
+	for l := range hs {
+		hs[l] /= evis * evis
+	}
+
+	return hs
+}
+
+// legendre fills dst with the Legendre polynomials P_0(x)...P_n(x).
+func legendre(dst []float64, x float64) {
+	if len(dst) == 0 {
+		return
+	}
+	dst[0] = 1
+	if len(dst) == 1 {
+		return
+	}
+	dst[1] = x
+	for l := 2; l < len(dst); l++ {
+		fl := float64(l)
+		dst[l] = ((2*fl-1)*x*dst[l-1] - (fl-1)*dst[l-2]) / fl
+	}
+}
+
+// Broadenings returns the wide and narrow jet broadenings of the
+// provided set of 4-vectors, with respect to the given axis
+// (usually the thrust axis).
+// The event is split in two hemispheres by the plane orthogonal to the axis
+// and, for each hemisphere H:
+//
+//  B_H = Σ_{i∈H} |p_i × n| / (2 Σ_i |p_i|)
+//
+// The total broadening is the sum of the wide and narrow ones.
+func Broadenings(ps []P4, axis r3.Vec) (wide, narrow float64) {
+	var (
+		n    = vecUnit(axis)
+		norm = 0.0
+		bpos = 0.0
+		bneg = 0.0
+	)
+
+	for _, p := range ps {
+		v := vecOf(p)
+		norm += vecNorm(v)
+		b := vecNorm(v.Cross(n))
+		if v.Dot(n) >= 0 {
+			bpos += b
+		} else {
+			bneg += b
+		}
+	}
+
+	if norm == 0 {
+		return 0, 0
+	}
+
+	bpos /= 2 * norm
+	bneg /= 2 * norm
+	return math.Max(bpos, bneg), math.Min(bpos, bneg)
+}
+
+// HemisphereMasses returns the invariant masses of the heavy and light
+// hemispheres of the provided set of 4-vectors.
+// The event is split in two hemispheres by the plane orthogonal to the
+// given axis (usually the thrust axis).
+func HemisphereMasses(ps []P4, axis r3.Vec) (heavy, light float64) {
+	var (
+		n    = vecUnit(axis)
+		ppos PxPyPzE
+		pneg PxPyPzE
+	)
+
+	for _, p := range ps {
+		if vecOf(p).Dot(n) >= 0 {
+			IAdd(&ppos, p)
+		} else {
+			IAdd(&pneg, p)
+		}
+	}
+
+	mpos := ppos.M()
+	mneg := pneg.M()
+	return math.Max(mpos, mneg), math.Min(mpos, mneg)
+}
+
+func vecNorm(v r3.Vec) float64 {
+	return math.Sqrt(v.Dot(v))
+}
+
+func vecUnit(v r3.Vec) r3.Vec {
+	norm := vecNorm(v)
+	if norm == 0 {
+		return v
+	}
+	return v.Scale(1 / norm)
+}
@@ -0,0 +1,276 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmom
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func newP4s(vs ...[3]float64) []P4 {
+	ps := make([]P4, len(vs))
+	for i, v := range vs {
+		e := math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+		ps[i] = newPxPyPzE(NewPxPyPzE(v[0], v[1], v[2], e))
+	}
+	return ps
+}
+
+func TestThrust(t *testing.T) {
+	c := math.Cos(2 * math.Pi / 3)
+	s := math.Sin(2 * math.Pi / 3)
+
+	for _, tc := range []struct {
+		name string
+		ps   []P4
+		want float64
+		axis r3.Vec
+	}{
+		{
+			name: "empty",
+			want: 0,
+		},
+		{
+			name: "single",
+			ps:   newP4s([3]float64{0, 0, 2}),
+			want: 1,
+			axis: r3.Vec{Z: 1},
+		},
+		{
+			name: "back-to-back",
+			ps:   newP4s([3]float64{10, 0, 0}, [3]float64{-10, 0, 0}),
+			want: 1,
+			axis: r3.Vec{X: 1},
+		},
+		{
+			name: "mercedes",
+			ps:   newP4s([3]float64{1, 0, 0}, [3]float64{c, s, 0}, [3]float64{c, -s, 0}),
+			want: 2.0 / 3.0,
+			axis: r3.Vec{X: 1},
+		},
+		{
+			name: "isotropic",
+			ps: newP4s(
+				[3]float64{1, 0, 0}, [3]float64{-1, 0, 0},
+				[3]float64{0, 1, 0}, [3]float64{0, -1, 0},
+				[3]float64{0, 0, 1}, [3]float64{0, 0, -1},
+			),
+			want: 1 / math.Sqrt(3),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, axis := Thrust(tc.ps)
+			if !floats.EqualWithinAbs(got, tc.want, 1e-12) {
+				t.Fatalf("invalid thrust: got=%v, want=%v", got, tc.want)
+			}
+			if tc.axis == (r3.Vec{}) {
+				return
+			}
+			if dot := math.Abs(axis.Dot(tc.axis)); !floats.EqualWithinAbs(dot, 1, 1e-12) {
+				t.Fatalf("invalid thrust axis: got=%v, want=%v", axis, tc.axis)
+			}
+		})
+	}
+}
+
+// thrustBruteForce computes the thrust by maximising |sum_k e_k p_k| over
+// all the 2^n sign combinations e_k = ±1.
+func thrustBruteForce(ps []P4) float64 {
+	var norm float64
+	for _, p := range ps {
+		norm += p.P()
+	}
+	if norm == 0 {
+		return 0
+	}
+	var tmax float64
+	for mask := 0; mask < 1<<len(ps); mask++ {
+		var sum r3.Vec
+		for k, p := range ps {
+			v := r3.Vec{X: p.Px(), Y: p.Py(), Z: p.Pz()}
+			if mask&(1<<k) != 0 {
+				v = v.Scale(-1)
+			}
+			sum = sum.Add(v)
+		}
+		tmax = math.Max(tmax, math.Sqrt(sum.Dot(sum)))
+	}
+	return tmax / norm
+}
+
+func TestThrustBruteForce(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1234))
+	gen := func(n int, vec func() [3]float64) []P4 {
+		vs := make([][3]float64, n)
+		for i := range vs {
+			vs[i] = vec()
+		}
+		return newP4s(vs...)
+	}
+	uniform := func() float64 { return 2*rnd.Float64() - 1 }
+
+	for _, tc := range []struct {
+		name string
+		vec  func() [3]float64
+	}{
+		{
+			name: "planar-xy",
+			vec: func() [3]float64 {
+				return [3]float64{uniform(), uniform(), 0}
+			},
+		},
+		{
+			name: "planar-tilted",
+			vec: func() [3]float64 {
+				// plane spanned by (1,0,1) and (0,1,0).
+				a, b := uniform(), uniform()
+				return [3]float64{a, b, a}
+			},
+		},
+		{
+			name: "planar-collinear",
+			vec: func() [3]float64 {
+				// only a few distinct directions in the xy plane.
+				phi := 2 * math.Pi * float64(rnd.Intn(3)) / 3
+				r := uniform()
+				return [3]float64{r * math.Cos(phi), r * math.Sin(phi), 0}
+			},
+		},
+		{
+			name: "3d",
+			vec: func() [3]float64 {
+				return [3]float64{uniform(), uniform(), uniform()}
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				ps := gen(2+rnd.Intn(9), tc.vec)
+				want := thrustBruteForce(ps)
+				got, axis := Thrust(ps)
+				if !floats.EqualWithinAbs(got, want, 1e-12) {
+					t.Fatalf("event %d: invalid thrust: got=%v, want=%v", i, got, want)
+				}
+
+				var num, den float64
+				for _, p := range ps {
+					v := r3.Vec{X: p.Px(), Y: p.Py(), Z: p.Pz()}
+					num += math.Abs(v.Dot(axis))
+					den += math.Sqrt(v.Dot(v))
+				}
+				if !floats.EqualWithinAbs(num/den, want, 1e-12) {
+					t.Fatalf("event %d: invalid thrust axis %v: got=%v, want=%v", i, axis, num/den, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMomentumTensor(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		ps   []P4
+		r    float64
+		want [5]float64 // sphericity, aplanarity, planarity, C, D
+	}{
+		{
+			name: "back-to-back",
+			ps:   newP4s([3]float64{0, 0, 10}, [3]float64{0, 0, -10}),
+			r:    2,
+			want: [5]float64{0, 0, 0, 0, 0},
+		},
+		{
+			name: "isotropic",
+			ps: newP4s(
+				[3]float64{1, 0, 0}, [3]float64{-1, 0, 0},
+				[3]float64{0, 1, 0}, [3]float64{0, -1, 0},
+				[3]float64{0, 0, 1}, [3]float64{0, 0, -1},
+			),
+			r:    1,
+			want: [5]float64{1, 0.5, 0, 1, 1},
+		},
+		{
+			name: "linearized",
+			ps: newP4s(
+				[3]float64{1, 0, 0}, [3]float64{-1, 0, 0},
+				[3]float64{0, 2, 0}, [3]float64{0, -2, 0},
+				[3]float64{0, 0, 3}, [3]float64{0, 0, -3},
+			),
+			r:    1,
+			want: [5]float64{0.75, 0.25, 1.0 / 6.0, 11.0 / 12.0, 0.75},
+		},
+		{
+			name: "planar",
+			ps: newP4s(
+				[3]float64{1, 0, 0}, [3]float64{-1, 0, 0},
+				[3]float64{0, 1, 0}, [3]float64{0, -1, 0},
+			),
+			r:    2,
+			want: [5]float64{0.75, 0, 0.5, 0.75, 0},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := NewMomentumTensor(tc.ps, tc.r)
+			got := [5]float64{mt.Sphericity(), mt.Aplanarity(), mt.Planarity(), mt.C(), mt.D()}
+			if !floats.EqualApprox(got[:], tc.want[:], 1e-12) {
+				t.Fatalf("invalid event shapes:\ngot= %v\nwant=%v", got, tc.want)
+			}
+			if !(mt.Lambdas[0] >= mt.Lambdas[1] && mt.Lambdas[1] >= mt.Lambdas[2]) {
+				t.Fatalf("eigenvalues not sorted: %v", mt.Lambdas)
+			}
+		})
+	}
+
+	mt := NewMomentumTensor(newP4s([3]float64{0, 0, 10}, [3]float64{0, 0, -10}), 2)
+	if got := math.Abs(mt.Axes[0].Z); !floats.EqualWithinAbs(got, 1, 1e-12) {
+		t.Fatalf("invalid sphericity axis: %v", mt.Axes[0])
+	}
+}
+
+func TestFoxWolfram(t *testing.T) {
+	ps := newP4s([3]float64{0, 0, 10}, [3]float64{0, 0, -10})
+	got := FoxWolfram(ps, 4)
+	want := []float64{1, 0, 1, 0, 1}
+	if !floats.EqualApprox(got, want, 1e-12) {
+		t.Fatalf("invalid Fox-Wolfram moments:\ngot= %v\nwant=%v", got, want)
+	}
+
+	ps = newP4s(
+		[3]float64{1, 0, 0}, [3]float64{-1, 0, 0},
+		[3]float64{0, 1, 0}, [3]float64{0, -1, 0},
+		[3]float64{0, 0, 1}, [3]float64{0, 0, -1},
+	)
+	got = FoxWolfram(ps, 2)
+	want = []float64{1, 0, 0}
+	if !floats.EqualApprox(got, want, 1e-12) {
+		t.Fatalf("invalid Fox-Wolfram moments:\ngot= %v\nwant=%v", got, want)
+	}
+}
+
+func TestBroadenings(t *testing.T) {
+	ps := newP4s([3]float64{1, 0, 0}, [3]float64{-1, 0, 0}, [3]float64{0, 1, 0})
+	wide, narrow := Broadenings(ps, r3.Vec{X: 2})
+	if want := 1.0 / 6.0; !floats.EqualWithinAbs(wide, want, 1e-12) {
+		t.Fatalf("invalid wide broadening: got=%v, want=%v", wide, want)
+	}
+	if want := 0.0; !floats.EqualWithinAbs(narrow, want, 1e-12) {
+		t.Fatalf("invalid narrow broadening: got=%v, want=%v", narrow, want)
+	}
+}
+
+func TestHemisphereMasses(t *testing.T) {
+	ps := newP4s([3]float64{1, 1, 0}, [3]float64{1, -1, 0}, [3]float64{-1, 0, 0})
+	heavy, light := HemisphereMasses(ps, r3.Vec{X: -1})
+	if want := 2.0; !floats.EqualWithinAbs(heavy, want, 1e-12) {
+		t.Fatalf("invalid heavy hemisphere mass: got=%v, want=%v", heavy, want)
+	}
+	if want := 0.0; !floats.EqualWithinAbs(light, want, 1e-12) {
+		t.Fatalf("invalid light hemisphere mass: got=%v, want=%v", light, want)
+	}
+}
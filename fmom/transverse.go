@@ -0,0 +1,392 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmom
+
+import (
+	"math"
+)
+
+// MT returns the transverse mass of the system made of p1 and p2:
+//
+//  mT^2 = (ET_1 + ET_2)^2 - |pT_1 + pT_2|^2
+//
+// where ET_i = sqrt(m_i^2 + pT_i^2).
+// When p2 is the missing transverse momentum, it should be massless.
+func MT(p1, p2 P4) float64 {
+	var (
+		et1 = transverseEnergy(p1)
+		et2 = transverseEnergy(p2)
+		px  = p1.Px() + p2.Px()
+		py  = p1.Py() + p2.Py()
+		mt2 = (et1+et2)*(et1+et2) - px*px - py*py
+	)
+	return math.Sqrt(math.Max(0, mt2))
+}
+
+// MT2 returns the stransverse mass of a system with two visible
+// 4-vectors p1 and p2, the missing transverse momentum met and
+// two invisible particles of mass minv.
+//
+//  mT2 = min_{q1+q2=met} max(mT(p1,q1), mT(p2,q2))
+//
+// The value is computed by bisection: a trial mass M is accepted when the
+// two convex regions of the invisible momenta compatible with
+// mT(p1,q1) <= M and mT(p2,met-q1) <= M overlap.
+// These regions are unbounded for massless visible particles, so the
+// minimum may only be reached asymptotically: the infimum is returned.
+func MT2(p1, p2, met P4, minv float64) float64 {
+	var (
+		v1 = mt2Vis{
+			m:  math.Max(0, p1.M()),
+			px: p1.Px(), py: p1.Py(),
+		}
+		v2 = mt2Vis{
+			m:  math.Max(0, p2.M()),
+			px: p2.Px(), py: p2.Py(),
+		}
+		mx = met.Px()
+		my = met.Py()
+	)
+	v1.et = math.Sqrt(v1.m*v1.m + v1.px*v1.px + v1.py*v1.py)
+	v2.et = math.Sqrt(v2.m*v2.m + v2.px*v2.px + v2.py*v2.py)
+
+	lo := math.Max(v1.m, v2.m) + minv
+	if mt2Overlap(v1, v2, mx, my, minv, lo) {
+		return lo
+	}
+
+	// equal sharing of the missing momentum provides an upper bound.
+	hi := math.Max(
+		v1.mt(0.5*mx, 0.5*my, minv),
+		v2.mt(0.5*mx, 0.5*my, minv),
+	)
+
+	const (
+		maxIter = 200
+		relTol  = 1e-10
+	)
+	for i := 0; i < maxIter && hi-lo > relTol*hi; i++ {
+		mid := 0.5 * (lo + hi)
+		if mt2Overlap(v1, v2, mx, my, minv, mid) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	return hi
+}
+
+// mt2Vis holds the transverse quantities of a visible particle.
+type mt2Vis struct {
+	m      float64 // mass
+	et     float64 // transverse energy
+	px, py float64 // transverse momentum
+}
+
+// mt returns the transverse mass of the visible particle and an
+// invisible particle of mass m and transverse momentum (qx,qy).
+func (v mt2Vis) mt(qx, qy, m float64) float64 {
+	eq := math.Sqrt(m*m + qx*qx + qy*qy)
+	mt2 := v.m*v.m + m*m + 2*(v.et*eq-v.px*qx-v.py*qy)
+	return math.Sqrt(math.Max(0, mt2))
+}
+
+// mt2Quad is a quadratic form q^T A q + b·q + c in the transverse plane.
+type mt2Quad struct {
+	axx, axy, ayy float64
+	bx, by        float64
+	c             float64
+}
+
+// mt2HalfPlane is the half-plane a·q <= d of the transverse plane.
+type mt2HalfPlane struct {
+	ax, ay float64
+	d      float64
+}
+
+// mt2Region is the set of invisible momenta q such that Q(q) <= 0 and
+// q belongs to the half-plane H.
+type mt2Region struct {
+	Q mt2Quad
+	H mt2HalfPlane
+}
+
+// region returns the set of invisible momenta q such that mT(v, k) <= mass,
+// with k = sgn*q + k0.
+func (v mt2Vis) region(sgn, k0x, k0y, m, mass float64) mt2Region {
+	// mT(v,k) <= mass  <=>  et*sqrt(m^2+k^2) <= p·k + c
+	//                  <=>  k^T (et^2 I - p p^T) k - 2c p·k + et^2 m^2 - c^2 <= 0
+	//                       and p·k + c >= 0
+	// Squaring the inequality adds the spurious p·k + c < 0 branch of the
+	// conic (e.g. the mirror parabola for a massless visible particle),
+	// which is removed by the half-plane.
+	var (
+		c   = 0.5 * (mass*mass - v.m*v.m - m*m)
+		et2 = v.et * v.et
+		axx = et2 - v.px*v.px
+		axy = -v.px * v.py
+		ayy = et2 - v.py*v.py
+		bx  = -2 * c * v.px
+		by  = -2 * c * v.py
+		cc  = et2*m*m - c*c
+	)
+
+	// substitute k = sgn*q + k0.
+	return mt2Region{
+		Q: mt2Quad{
+			axx: axx,
+			axy: axy,
+			ayy: ayy,
+			bx:  sgn * (2*(axx*k0x+axy*k0y) + bx),
+			by:  sgn * (2*(axy*k0x+ayy*k0y) + by),
+			c:   k0x*(axx*k0x+axy*k0y) + k0y*(axy*k0x+ayy*k0y) + bx*k0x + by*k0y + cc,
+		},
+		H: mt2HalfPlane{
+			ax: -sgn * v.px,
+			ay: -sgn * v.py,
+			d:  v.px*k0x + v.py*k0y + c,
+		},
+	}
+}
+
+func (q mt2Quad) eval(x, y float64) float64 {
+	return x*(q.axx*x+q.axy*y) + y*(q.axy*x+q.ayy*y) + q.bx*x + q.by*y + q.c
+}
+
+// mt2Eps is the relative precision used to detect degenerate
+// quadratic forms and constraints.
+const mt2Eps = 1e-12
+
+// minOver returns the minimum of the (convex) quadratic form over the
+// intersection of the provided half-planes, -Inf if it is unbounded from
+// below and +Inf if the intersection is empty.
+func (q mt2Quad) minOver(hs []mt2HalfPlane) float64 {
+	cs := make([]mt2HalfPlane, 0, len(hs))
+	for _, h := range hs {
+		if h.ax == 0 && h.ay == 0 {
+			if h.d < 0 {
+				return math.Inf(+1)
+			}
+			continue
+		}
+		cs = append(cs, h)
+	}
+
+	var (
+		tr  = q.axx + q.ayy
+		det = q.axx*q.ayy - q.axy*q.axy
+	)
+	switch {
+	case det > mt2Eps*tr*tr:
+		// unique unconstrained minimum: x = -A^-1 b / 2
+		x := -0.5 * (q.ayy*q.bx - q.axy*q.by) / det
+		y := -0.5 * (q.axx*q.by - q.axy*q.bx) / det
+		if mt2Feasible(cs, x, y) {
+			return q.eval(x, y)
+		}
+
+	default:
+		// A = tr e e^T: the form is linear along z, orthogonal to e.
+		ex, ey := q.axx, q.axy
+		if q.ayy > q.axx {
+			ex, ey = q.axy, q.ayy
+		}
+		n := math.Hypot(ex, ey)
+		if n == 0 {
+			ex, ey, n = 1, 0, 1
+		}
+		ex, ey = ex/n, ey/n
+		var (
+			zx, zy = -ey, ex
+			bz     = q.bx*zx + q.by*zy
+			be     = q.bx*ex + q.by*ey
+		)
+		if math.Abs(bz) > mt2Eps*math.Hypot(q.bx, q.by) {
+			dir := -math.Copysign(1, bz)
+			if mt2Recession(cs, dir*zx, dir*zy) {
+				return math.Inf(-1)
+			}
+			break
+		}
+		if tr > 0 {
+			// line of unconstrained minima.
+			x0, y0 := -0.5*be/tr*ex, -0.5*be/tr*ey
+			if _, _, ok := mt2Line(cs, x0, y0, zx, zy); ok {
+				return q.eval(x0, y0)
+			}
+		}
+	}
+
+	// the minimum lies on the boundary of the feasible region.
+	min := math.Inf(+1)
+	others := make([]mt2HalfPlane, 0, len(cs))
+	for i, h := range cs {
+		others = append(others[:0], cs[:i]...)
+		others = append(others, cs[i+1:]...)
+
+		var (
+			n      = math.Hypot(h.ax, h.ay)
+			wx, wy = -h.ay / n, h.ax / n
+			x0, y0 = h.d * h.ax / (n * n), h.d * h.ay / (n * n)
+		)
+		lo, hi, ok := mt2Line(others, x0, y0, wx, wy)
+		if !ok {
+			continue
+		}
+
+		// Q(q0 + s w) = alpha s^2 + beta s + Q(q0)
+		var (
+			alpha = wx*(q.axx*wx+q.axy*wy) + wy*(q.axy*wx+q.ayy*wy)
+			beta  = 2*(wx*(q.axx*x0+q.axy*y0)+wy*(q.axy*x0+q.ayy*y0)) + q.bx*wx + q.by*wy
+			scale = mt2Eps * (math.Abs(q.bx) + math.Abs(q.by) + tr*(math.Abs(x0)+math.Abs(y0)))
+			s     float64
+		)
+		switch {
+		case alpha > mt2Eps*tr:
+			s = -0.5 * beta / alpha
+		case beta > scale:
+			s = lo
+		case beta < -scale:
+			s = hi
+		}
+		s = math.Max(lo, math.Min(hi, s))
+		if math.IsInf(s, 0) {
+			return math.Inf(-1)
+		}
+		min = math.Min(min, q.eval(x0+s*wx, y0+s*wy))
+	}
+
+	return min
+}
+
+// mt2Feasible returns whether (x,y) belongs to all the half-planes.
+func mt2Feasible(hs []mt2HalfPlane, x, y float64) bool {
+	for _, h := range hs {
+		v := h.ax*x + h.ay*y
+		if v-h.d > mt2Tol(h, x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// mt2Recession returns whether (dx,dy) is a recession direction of the
+// intersection of the half-planes.
+func mt2Recession(hs []mt2HalfPlane, dx, dy float64) bool {
+	for _, h := range hs {
+		if h.ax*dx+h.ay*dy > mt2Eps*math.Hypot(h.ax, h.ay) {
+			return false
+		}
+	}
+	return true
+}
+
+// mt2Line returns the range [lo,hi] of s for which q0 + s w belongs to
+// all the half-planes, and whether that range is not empty.
+func mt2Line(hs []mt2HalfPlane, x0, y0, wx, wy float64) (lo, hi float64, ok bool) {
+	lo = math.Inf(-1)
+	hi = math.Inf(+1)
+	for _, h := range hs {
+		var (
+			aw = h.ax*wx + h.ay*wy
+			r  = h.d - (h.ax*x0 + h.ay*y0)
+		)
+		if math.Abs(aw) <= mt2Eps*math.Hypot(h.ax, h.ay) {
+			if r < -mt2Tol(h, x0, y0) {
+				return lo, hi, false
+			}
+			continue
+		}
+		s := r / aw
+		if aw > 0 {
+			hi = math.Min(hi, s)
+		} else {
+			lo = math.Max(lo, s)
+		}
+	}
+	if lo > hi {
+		if lo-hi > 1e-9*(math.Abs(lo)+math.Abs(hi)) {
+			return lo, hi, false
+		}
+		lo, hi = hi, lo
+	}
+	return lo, hi, true
+}
+
+// mt2Tol returns the tolerance on the constraint h evaluated at (x,y).
+func mt2Tol(h mt2HalfPlane, x, y float64) float64 {
+	return 1e-9 * (math.Abs(h.d) + math.Hypot(h.ax, h.ay)*math.Hypot(x, y))
+}
+
+// mt2Overlap reports whether the regions of invisible momenta compatible
+// with mT(v1,q) <= mass and mT(v2,met-q) <= mass overlap.
+//
+// Each region is the intersection of a convex quadratic region Q_i(q) <= 0
+// with a half-plane H_i, so the regions overlap iff
+//
+//  min_{q∈H} max(Q1(q), Q2(q)) = max_{t∈[0,1]} min_{q∈H} (t Q1(q) + (1-t) Q2(q)) <= 0,
+//
+// with H the intersection of H_1 and H_2.
+// The right-hand side is a concave function of t, maximized by
+// golden-section search.
+func mt2Overlap(v1, v2 mt2Vis, mx, my, minv, mass float64) bool {
+	var (
+		r1 = v1.region(+1, 0, 0, minv, mass)
+		r2 = v2.region(-1, mx, my, minv, mass)
+		q1 = r1.Q
+		q2 = r2.Q
+		hs = []mt2HalfPlane{r1.H, r2.H}
+	)
+
+	dual := func(t float64) float64 {
+		u := 1 - t
+		q := mt2Quad{
+			axx: t*q1.axx + u*q2.axx,
+			axy: t*q1.axy + u*q2.axy,
+			ayy: t*q1.ayy + u*q2.ayy,
+			bx:  t*q1.bx + u*q2.bx,
+			by:  t*q1.by + u*q2.by,
+			c:   t*q1.c + u*q2.c,
+		}
+		return q.minOver(hs)
+	}
+
+	const (
+		gr      = 0.6180339887498949 // (sqrt(5)-1)/2
+		maxIter = 100
+	)
+
+	var (
+		a  = 0.0
+		b  = 1.0
+		x1 = b - gr*(b-a)
+		x2 = a + gr*(b-a)
+		f1 = dual(x1)
+		f2 = dual(x2)
+	)
+	for i := 0; i < maxIter; i++ {
+		if f1 > 0 || f2 > 0 {
+			return false
+		}
+		if f1 < f2 {
+			a = x1
+			x1, f1 = x2, f2
+			x2 = a + gr*(b-a)
+			f2 = dual(x2)
+		} else {
+			b = x2
+			x2, f2 = x1, f1
+			x1 = b - gr*(b-a)
+			f1 = dual(x1)
+		}
+	}
+	return f1 <= 0 && f2 <= 0
+}
+
+func transverseEnergy(p P4) float64 {
+	pt := p.Pt()
+	return math.Sqrt(math.Max(0, p.M2()) + pt*pt)
+}
@@ -0,0 +1,193 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmom
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestMT(t *testing.T) {
+	for _, tc := range []struct {
+		p1, p2 P4
+		want   float64
+	}{
+		{
+			p1:   newPxPyPzE(NewPxPyPzE(40, 0, 30, 50)),
+			p2:   newPxPyPzE(NewPxPyPzE(-40, 0, 0, 40)),
+			want: 80,
+		},
+		{
+			p1:   newPxPyPzE(NewPxPyPzE(40, 0, 30, 50)),
+			p2:   newPxPyPzE(NewPxPyPzE(40, 0, 0, 40)),
+			want: 0,
+		},
+		{
+			p1:   newPxPyPzE(NewPxPyPzE(30, 0, 0, 50)),
+			p2:   newPxPyPzE(NewPxPyPzE(0, 0, 0, 0)),
+			want: 40,
+		},
+		{
+			p1:   newPxPyPzE(NewPxPyPzE(30, 0, 0, 50)),
+			p2:   newPtEtaPhiM(NewPxPyPzE(0, 30, 0, 30)),
+			want: math.Sqrt(80*80 - 2*30*30),
+		},
+	} {
+		t.Run(fmt.Sprintf("%v-%v", tc.p1, tc.p2), func(t *testing.T) {
+			got := MT(tc.p1, tc.p2)
+			if !floats.EqualWithinAbs(got, tc.want, 1e-12) {
+				t.Fatalf("invalid mT: got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMT2(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		p1, p2 P4
+		met    P4
+		minv   float64
+	}{
+		{
+			name: "massless",
+			p1:   newPxPyPzE(NewPxPyPzE(30, 10, 5, math.Sqrt(30*30+10*10+5*5))),
+			p2:   newPxPyPzE(NewPxPyPzE(-20, 25, -8, math.Sqrt(20*20+25*25+8*8))),
+			met:  newPxPyPzE(NewPxPyPzE(-10, -35, 0, math.Hypot(10, 35))),
+		},
+		{
+			name: "massive",
+			p1:   newPtEtaPhiM(NewPxPyPzE(50, 20, 10, 100)),
+			p2:   newPtEtaPhiM(NewPxPyPzE(-30, 40, -20, 90)),
+			met:  newPxPyPzE(NewPxPyPzE(25, -60, 0, math.Hypot(25, 60))),
+			minv: 50,
+		},
+		{
+			name: "unbalanced",
+			p1:   newPxPyPzE(NewPxPyPzE(100, 0, 0, 120)),
+			p2:   newPxPyPzE(NewPxPyPzE(1, 0, 0, 10)),
+			met:  newPxPyPzE(NewPxPyPzE(0, 0, 0, 0)),
+			minv: 10,
+		},
+		{
+			name: "massless-visible-1",
+			p1:   newP4s([3]float64{15.024638046228233, 51.33607617709488, 58.21451973157532})[0],
+			p2:   newP4s([3]float64{55.69317000887416, 75.95886192767561, -42.877728249353474})[0],
+			met:  newP4s([3]float64{-55.117666472645645, -76.63889015736709, 0})[0],
+		},
+		{
+			name: "massless-visible-2",
+			p1:   newP4s([3]float64{-20.14295485887571, 96.67109836882216, -31.503140112543466})[0],
+			p2:   newP4s([3]float64{8.829860279791394, -22.878981337727584, 49.5595345898677})[0],
+			met:  newP4s([3]float64{-36.952238280060506, 63.74540799457227, 0})[0],
+		},
+		{
+			name: "massless-visible-3",
+			p1:   newP4s([3]float64{6.525741500765111, 61.405446053514076, -10.088447138064183})[0],
+			p2:   newP4s([3]float64{-1.600168615176669, 24.817660871398722, 44.243458335797904})[0],
+			met:  newP4s([3]float64{-36.05791413967218, -44.113151662124885, 0})[0],
+		},
+		{
+			name: "massless-visible-4",
+			p1:   newP4s([3]float64{-39.29023048064304, 108.76754007968292, -63.80402669051389})[0],
+			p2:   newP4s([3]float64{107.52321402040398, 11.40290996383827, 14.500305822736609})[0],
+			met:  newP4s([3]float64{-13.038248629634728, 2.360133845272763, 0})[0],
+		},
+		{
+			name: "back-to-back-massive",
+			p1:   newPxPyPzE(NewPxPyPzE(50, 0, 10, math.Sqrt(10*10+50*50+10*10))),
+			p2:   newPxPyPzE(NewPxPyPzE(-40, 0, -5, math.Sqrt(5*5+40*40+5*5))),
+			met:  newP4s([3]float64{0, 30, 0})[0],
+		},
+		{
+			name: "back-to-back-massive-minv",
+			p1:   newPxPyPzE(NewPxPyPzE(50, 0, 10, math.Sqrt(10*10+50*50+10*10))),
+			p2:   newPxPyPzE(NewPxPyPzE(-40, 0, -5, math.Sqrt(5*5+40*40+5*5))),
+			met:  newP4s([3]float64{-20, 30, 0})[0],
+			minv: 10,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := MT2(tc.p1, tc.p2, tc.met, tc.minv)
+			want := mt2BruteForce(tc.p1, tc.p2, tc.met, tc.minv)
+			if !floats.EqualWithinRel(got, want, 1e-6) {
+				t.Fatalf("invalid mT2: got=%v, want=%v", got, want)
+			}
+		})
+	}
+
+	// massless visible and invisible particles, no upstream momentum:
+	//  mT2^2 = 2 (pT1 pT2 + pT1·pT2)
+	p1 := newPxPyPzE(NewPxPyPzE(30, 10, 0, math.Hypot(30, 10)))
+	p2 := newPxPyPzE(NewPxPyPzE(-20, 25, 0, math.Hypot(20, 25)))
+	met := newPxPyPzE(NewPxPyPzE(-10, -35, 0, math.Hypot(10, 35)))
+	want := math.Sqrt(2 * (p1.Pt()*p2.Pt() + p1.Px()*p2.Px() + p1.Py()*p2.Py()))
+	if got := MT2(p1, p2, met, 0); !floats.EqualWithinRel(got, want, 1e-6) {
+		t.Fatalf("invalid mT2: got=%v, want=%v", got, want)
+	}
+
+	// back-to-back massless visible particles: each invisible particle can
+	// be (asymptotically) collinear with its visible partner, so that
+	//  mT2 = minv
+	// whatever the missing transverse momentum.
+	for _, minv := range []float64{0, 10} {
+		for _, met := range []P4{
+			newP4s([3]float64{0, 30, 0})[0],
+			newP4s([3]float64{-20, -30, 0})[0],
+			newP4s([3]float64{0, 0, 0})[0],
+		} {
+			var (
+				p1 = newP4s([3]float64{50, 0, 10})[0]
+				p2 = newP4s([3]float64{-30, 0, -20})[0]
+			)
+			got := MT2(p1, p2, met, minv)
+			if !floats.EqualWithinAbs(got, minv, 1e-3) {
+				t.Fatalf("invalid back-to-back mT2 (met=%v, minv=%v): got=%v, want=%v", met, minv, got, minv)
+			}
+		}
+	}
+}
+
+// mt2BruteForce minimizes max(mT(p1,q), mT(p2,met-q)) over q,
+// using nested golden-section searches on the (convex) objective.
+func mt2BruteForce(p1, p2, met P4, minv float64) float64 {
+	mt := func(p P4, qx, qy float64) float64 {
+		q := NewPxPyPzE(qx, qy, 0, math.Sqrt(minv*minv+qx*qx+qy*qy))
+		return MT(p, &q)
+	}
+	obj := func(qx, qy float64) float64 {
+		return math.Max(
+			mt(p1, qx, qy),
+			mt(p2, met.Px()-qx, met.Py()-qy),
+		)
+	}
+
+	const gr = 0.6180339887498949
+	golden := func(a, b float64, f func(float64) float64) (float64, float64) {
+		for i := 0; i < 200; i++ {
+			x1 := b - gr*(b-a)
+			x2 := a + gr*(b-a)
+			if f(x1) < f(x2) {
+				b = x2
+			} else {
+				a = x1
+			}
+		}
+		x := 0.5 * (a + b)
+		return x, f(x)
+	}
+
+	const scale = 1e3
+	_, v := golden(-scale, +scale, func(qx float64) float64 {
+		_, v := golden(-scale, +scale, func(qy float64) float64 {
+			return obj(qx, qy)
+		})
+		return v
+	})
+	return v
+}
@@ -32,6 +32,7 @@ type appmgr struct {
 	comps   map[string]Component
 	tsks    []Task
 	svcs    []Svc
+	mons    monitors
 	istream Task
 	ctxs    [2][]ctxType
 }
@@ -489,12 +490,16 @@ func (app *appmgr) start(ctx Context) error {
 	var err error
 	defer app.msg.flush()
 	app.state = fsm.Starting
+	app.mons = nil
 	for i, svc := range app.svcs {
 		app.msg.Debugf("starting [%s]...\n", svc.Name())
 		err = svc.StartSvc(app.ctxs[1][i])
 		if err != nil {
 			return err
 		}
+		if mon, ok := svc.(Monitor); ok {
+			app.mons = append(app.mons, mon)
+		}
 	}
 
 	for i, tsk := range app.tsks {
@@ -569,8 +574,12 @@ func (app *appmgr) runSequential(ctx Context) error {
 			evtCancel()
 			return err
 		}
-		err = app.istream.Process(ctxs[0])
+		ictx := ctxs[0]
+		ictx.id = ievt
+		app.mons.beginEvent(ictx)
+		err = app.mons.process(ictx, app.istream)
 		if err != nil {
+			app.mons.endEvent(ictx, err)
 			evtCancel()
 			store.close()
 			app.msg.flush()
@@ -580,6 +589,7 @@ func (app *appmgr) runSequential(ctx Context) error {
 			ievt:   ievt,
 			errc:   make(chan error, len(app.tsks)),
 			evtctx: evtctx,
+			mons:   app.mons,
 		}
 		for i, tsk := range app.tsks {
 			go run.run(i, ctxs[i], tsk)
@@ -589,6 +599,7 @@ func (app *appmgr) runSequential(ctx Context) error {
 		for err = range run.errc {
 			ndone++
 			if err != nil {
+				app.mons.endEvent(ictx, err)
 				evtCancel()
 				store.close()
 				app.msg.flush()
@@ -598,6 +609,7 @@ func (app *appmgr) runSequential(ctx Context) error {
 				break errloop
 			}
 		}
+		app.mons.endEvent(ictx, nil)
 		evtCancel()
 		store.close()
 		app.msg.flush()
@@ -659,8 +671,10 @@ func (app *appmgr) runConcurrent(ctx Context) error {
 				ctx:   evtctx,
 			}
 
-			err = app.istream.Process(ctx)
+			app.mons.beginEvent(ctx)
+			err = app.mons.process(ctx, app.istream)
 			if err != nil {
+				app.mons.endEvent(ctx, err)
 				if err != io.EOF {
 					ctrl.errc <- err
 				}
@@ -0,0 +1,39 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package groot provides fwk input and output streamers for ROOT trees.
+//
+// The InputStreamer reads the branches of a ROOT Tree into the fwk ports
+// associated with them, while the OutputStreamer writes the content of fwk ports
+// into the branches of a new ROOT Tree.
+//
+// As fwk port names may contain characters that are not valid in ROOT branch
+// names (e.g. "/fads/jets"), ports are mapped to branches with BranchName.
+package groot // import "go-hep.org/x/hep/fwk/groot"
+
+import "strings"
+
+// BranchName returns the name of the ROOT branch associated with the
+// provided fwk port name.
+//
+// Leading slashes are removed and the characters that have a special
+// meaning in ROOT branch names and leaf lists ('/', ':', '.', '[', ']' and
+// blanks) are replaced with underscores:
+//  "/fads/jets" -> "fads_jets"
+func BranchName(port string) string {
+	name := strings.TrimLeft(port, "/")
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', ':', '.', '[', ']', ' ', '\t':
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// countName returns the name of the branch holding the number of elements
+// of the provided slice branch.
+func countName(name string) string {
+	return "fwk_n_" + name
+}
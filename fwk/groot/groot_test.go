@@ -0,0 +1,410 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package groot
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go-hep.org/x/hep/fwk"
+	"go-hep.org/x/hep/fwk/job"
+	"go-hep.org/x/hep/fwk/testdata"
+	"go-hep.org/x/hep/groot"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/rtree"
+)
+
+func newapp(evtmax int64, nprocs int) *job.Job {
+	return job.NewJob(nil, job.P{
+		"EvtMax":   evtmax,
+		"NProcs":   nprocs,
+		"MsgLevel": job.MsgLevel("ERROR"),
+	})
+}
+
+func newTestReader(max int) io.Reader {
+	buf := new(bytes.Buffer)
+	for i := 0; i < max; i++ {
+		fmt.Fprintf(buf, "%d\n", int64(i))
+	}
+	return buf
+}
+
+func getsumsq(n int64) int64 {
+	sum := int64(0)
+	for i := int64(0); i < n; i++ {
+		sum += i * i
+	}
+	return sum
+}
+
+func TestStreamers(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "fwk-groot-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	const max = 100
+	for _, nprocs := range []int{0, 1, 2, 4} {
+		t.Run(fmt.Sprintf("nprocs=%d", nprocs), func(t *testing.T) {
+			fname := filepath.Join(tmp, fmt.Sprintf("out-%d.root", nprocs))
+
+			app := newapp(-1, nprocs)
+			app.Create(job.C{
+				Type: "go-hep.org/x/hep/fwk.InputStream",
+				Name: "input",
+				Props: job.P{
+					"Ports": []fwk.Port{
+						{Name: "ints", Type: reflect.TypeOf(int64(0))},
+					},
+					"Streamer": &testdata.InputStream{
+						R: newTestReader(max),
+					},
+				},
+			})
+			app.Create(job.C{
+				Type: "go-hep.org/x/hep/fwk/testdata.task2",
+				Name: "t2",
+				Props: job.P{
+					"Input":  "ints",
+					"Output": "ints-sq",
+				},
+			})
+			app.Create(job.C{
+				Type: "go-hep.org/x/hep/fwk/groot.slicer",
+				Name: "slicer",
+				Props: job.P{
+					"Input":  "ints",
+					"Output": "f64s",
+				},
+			})
+			app.Create(job.C{
+				Type: "go-hep.org/x/hep/fwk.OutputStream",
+				Name: "output",
+				Props: job.P{
+					"Ports": []fwk.Port{
+						{Name: "ints", Type: reflect.TypeOf(int64(0))},
+						{Name: "ints-sq", Type: reflect.TypeOf(int64(0))},
+						{Name: "f64s", Type: reflect.TypeOf([]float64(nil))},
+					},
+					"Streamer": &OutputStreamer{
+						Name: fname,
+						Tree: "tree",
+					},
+				},
+			})
+
+			err := app.App().Run()
+			if err != nil {
+				t.Fatalf("could not run write-job: %+v", err)
+			}
+
+			checkTree(t, fname, max)
+
+			app = newapp(-1, nprocs)
+			app.Create(job.C{
+				Type: "go-hep.org/x/hep/fwk.InputStream",
+				Name: "input",
+				Props: job.P{
+					"Ports": []fwk.Port{
+						{Name: "ints-sq", Type: reflect.TypeOf(int64(0))},
+						{Name: "f64s", Type: reflect.TypeOf([]float64(nil))},
+					},
+					"Streamer": &InputStreamer{
+						Names: []string{fname},
+						Tree:  "tree",
+					},
+				},
+			})
+			app.Create(job.C{
+				Type: "go-hep.org/x/hep/fwk/testdata.reducer",
+				Name: "reducer",
+				Props: job.P{
+					"Input": "ints-sq",
+					"Sum":   getsumsq(max),
+				},
+			})
+
+			err = app.App().Run()
+			if err != nil {
+				t.Fatalf("could not run read-job: %+v", err)
+			}
+		})
+	}
+}
+
+func checkTree(t *testing.T, fname string, n int64) {
+	t.Helper()
+
+	f, err := groot.Open(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	o, err := riofs.Dir(f).Get("tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := o.(rtree.Tree)
+	if got, want := tree.Entries(), n; got != want {
+		t.Fatalf("invalid number of entries: got=%d, want=%d", got, want)
+	}
+
+	// entries may be written out of order when running concurrently.
+	var (
+		i    int64
+		sq   int64
+		f64s []float64
+		sum  int64
+	)
+	r, err := rtree.NewReader(tree, []rtree.ReadVar{
+		{Name: "ints", Value: &i},
+		{Name: "ints-sq", Value: &sq},
+		{Name: "f64s", Value: &f64s},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	err = r.Read(func(ctx rtree.RCtx) error {
+		sum += i
+		if sq != i*i {
+			return fmt.Errorf("invalid ints-sq value: got=%d, want=%d", sq, i*i)
+		}
+		if got, want := f64s, makeSlice(i); !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("invalid f64s value: got=%v, want=%v", got, want)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := sum, n*(n-1)/2; got != want {
+		t.Fatalf("invalid sum: got=%d, want=%d", got, want)
+	}
+}
+
+func makeSlice(i int64) []float64 {
+	sli := make([]float64, i%5)
+	for j := range sli {
+		sli[j] = float64(i)
+	}
+	return sli
+}
+
+type slicer struct {
+	fwk.TaskBase
+
+	input  string
+	output string
+}
+
+func (tsk *slicer) Configure(ctx fwk.Context) error {
+	err := tsk.DeclInPort(tsk.input, reflect.TypeOf(int64(0)))
+	if err != nil {
+		return err
+	}
+
+	return tsk.DeclOutPort(tsk.output, reflect.TypeOf([]float64(nil)))
+}
+
+func (tsk *slicer) StartTask(ctx fwk.Context) error { return nil }
+func (tsk *slicer) StopTask(ctx fwk.Context) error  { return nil }
+
+func (tsk *slicer) Process(ctx fwk.Context) error {
+	store := ctx.Store()
+	v, err := store.Get(tsk.input)
+	if err != nil {
+		return err
+	}
+	return store.Put(tsk.output, makeSlice(v.(int64)))
+}
+
+func init() {
+	fwk.Register(reflect.TypeOf(slicer{}),
+		func(typ, name string, mgr fwk.App) (fwk.Component, error) {
+			tsk := &slicer{
+				TaskBase: fwk.NewTask(typ, name, mgr),
+				input:    "Input",
+				output:   "Output",
+			}
+
+			err := tsk.DeclProp("Input", &tsk.input)
+			if err != nil {
+				return nil, err
+			}
+
+			err = tsk.DeclProp("Output", &tsk.output)
+			if err != nil {
+				return nil, err
+			}
+			return tsk, nil
+		},
+	)
+}
+
+func TestBranchName(t *testing.T) {
+	for _, tc := range []struct {
+		port string
+		want string
+	}{
+		{"ints", "ints"},
+		{"ints-sq", "ints-sq"},
+		{"/fads/jets", "fads_jets"},
+		{"//fads/a.b", "fads_a_b"},
+		{"evt/trk[2]:x", "evt_trk_2__x"},
+	} {
+		t.Run(tc.port, func(t *testing.T) {
+			if got, want := BranchName(tc.port), tc.want; got != want {
+				t.Fatalf("invalid branch name: got=%q, want=%q", got, want)
+			}
+		})
+	}
+}
+
+func TestStreamersPortNames(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "fwk-groot-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	const max = 10
+	fname := filepath.Join(tmp, "out.root")
+
+	app := newapp(-1, 0)
+	app.Create(job.C{
+		Type: "go-hep.org/x/hep/fwk.InputStream",
+		Name: "input",
+		Props: job.P{
+			"Ports": []fwk.Port{
+				{Name: "/fwk/ints", Type: reflect.TypeOf(int64(0))},
+			},
+			"Streamer": &testdata.InputStream{
+				R: newTestReader(max),
+			},
+		},
+	})
+	app.Create(job.C{
+		Type: "go-hep.org/x/hep/fwk/testdata.task2",
+		Name: "t2",
+		Props: job.P{
+			"Input":  "/fwk/ints",
+			"Output": "/fwk/ints:sq",
+		},
+	})
+	app.Create(job.C{
+		Type: "go-hep.org/x/hep/fwk/groot.slicer",
+		Name: "slicer",
+		Props: job.P{
+			"Input":  "/fwk/ints",
+			"Output": "/fwk/f64s",
+		},
+	})
+	app.Create(job.C{
+		Type: "go-hep.org/x/hep/fwk.OutputStream",
+		Name: "output",
+		Props: job.P{
+			"Ports": []fwk.Port{
+				{Name: "/fwk/ints", Type: reflect.TypeOf(int64(0))},
+				{Name: "/fwk/ints:sq", Type: reflect.TypeOf(int64(0))},
+				{Name: "/fwk/f64s", Type: reflect.TypeOf([]float64(nil))},
+			},
+			"Streamer": &OutputStreamer{
+				Name: fname,
+				Tree: "tree",
+			},
+		},
+	})
+
+	err = app.App().Run()
+	if err != nil {
+		t.Fatalf("could not run write-job: %+v", err)
+	}
+
+	f, err := groot.Open(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, err := riofs.Dir(f).Get("tree")
+	if err != nil {
+		f.Close()
+		t.Fatal(err)
+	}
+	tree := o.(rtree.Tree)
+	var names []string
+	for _, b := range tree.Branches() {
+		names = append(names, b.Name())
+	}
+	f.Close()
+
+	want := []string{"fwk_n_fwk_f64s", "fwk_ints", "fwk_ints_sq", "fwk_f64s"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("invalid branch names:\ngot= %q\nwant=%q", names, want)
+	}
+
+	app = newapp(-1, 0)
+	app.Create(job.C{
+		Type: "go-hep.org/x/hep/fwk.InputStream",
+		Name: "input",
+		Props: job.P{
+			"Ports": []fwk.Port{
+				{Name: "/fwk/ints:sq", Type: reflect.TypeOf(int64(0))},
+				{Name: "/fwk/f64s", Type: reflect.TypeOf([]float64(nil))},
+			},
+			"Streamer": &InputStreamer{
+				Names: []string{fname},
+				Tree:  "tree",
+			},
+		},
+	})
+	app.Create(job.C{
+		Type: "go-hep.org/x/hep/fwk/testdata.reducer",
+		Name: "reducer",
+		Props: job.P{
+			"Input": "/fwk/ints:sq",
+			"Sum":   getsumsq(max),
+		},
+	})
+
+	err = app.App().Run()
+	if err != nil {
+		t.Fatalf("could not run read-job: %+v", err)
+	}
+}
+
+func TestOutputStreamerDuplicateBranch(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "fwk-groot-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	o := &OutputStreamer{
+		Name: filepath.Join(tmp, "out.root"),
+		Tree: "tree",
+	}
+	err = o.Connect([]fwk.Port{
+		{Name: "/fwk/ints", Type: reflect.TypeOf(int64(0))},
+		{Name: "fwk/ints", Type: reflect.TypeOf(int64(0))},
+	})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	const want = `fwk/groot: ports "/fwk/ints" and "fwk/ints" map to the same branch "fwk_ints"`
+	if got := err.Error(); got != want {
+		t.Fatalf("invalid error:\ngot= %s\nwant=%s", got, want)
+	}
+}
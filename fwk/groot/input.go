@@ -0,0 +1,108 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package groot
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"go-hep.org/x/hep/fwk"
+	"go-hep.org/x/hep/groot/rtree"
+)
+
+// InputStreamer reads data from a (set of) ROOT tree(s).
+//
+// Each fwk port is populated from the tree branch named after it
+// (see BranchName).
+type InputStreamer struct {
+	Names []string // input filenames
+	Tree  string   // name of the tree to read
+
+	tree  rtree.Tree     // input tree (or chain of trees)
+	close func() error   // closes the underlying file(s)
+	scan  *rtree.Scanner // input entries-scanner
+	ports []fwk.Port     // input ports to read/populate
+	vals  []reflect.Value
+}
+
+func (input *InputStreamer) Connect(ports []fwk.Port) error {
+	var err error
+
+	if len(input.Names) == 0 {
+		return fmt.Errorf("fwk/groot: no input file")
+	}
+
+	input.tree, input.close, err = rtree.ChainOf(input.Tree, input.Names...)
+	if err != nil {
+		return fmt.Errorf("fwk/groot: could not open tree %q: %w", input.Tree, err)
+	}
+
+	input.ports = make([]fwk.Port, len(ports))
+	copy(input.ports, ports)
+
+	input.vals = make([]reflect.Value, len(ports))
+	rvars := make([]rtree.ReadVar, len(ports))
+	for i, port := range ports {
+		input.vals[i] = reflect.New(port.Type)
+		rvars[i] = rtree.ReadVar{
+			Name:  BranchName(port.Name),
+			Value: input.vals[i].Interface(),
+		}
+	}
+
+	input.scan, err = rtree.NewScannerVars(input.tree, rvars...)
+	if err != nil {
+		_ = input.close()
+		return fmt.Errorf("fwk/groot: could not create tree scanner: %w", err)
+	}
+
+	return err
+}
+
+func (input *InputStreamer) Read(ctx fwk.Context) error {
+	if !input.scan.Next() {
+		err := input.scan.Err()
+		if err == nil {
+			return io.EOF
+		}
+		return err
+	}
+
+	// reset values so slices are re-allocated for each entry:
+	// values put into the store may be used concurrently
+	// while the next entry is read.
+	for _, v := range input.vals {
+		v.Elem().Set(reflect.Zero(v.Elem().Type()))
+	}
+
+	err := input.scan.Scan()
+	if err != nil {
+		return fmt.Errorf("fwk/groot: could not read entry %d: %w", input.scan.Entry(), err)
+	}
+
+	store := ctx.Store()
+	for i, port := range input.ports {
+		err = store.Put(port.Name, input.vals[i].Elem().Interface())
+		if err != nil {
+			return fmt.Errorf("store-put error: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (input *InputStreamer) Disconnect() error {
+	err := input.scan.Close()
+
+	// make sure we don't leak filedescriptors, even if the scanner
+	// could not be closed.
+	errc := input.close()
+	if err != nil {
+		return err
+	}
+
+	return errc
+}
@@ -0,0 +1,134 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package groot
+
+import (
+	"fmt"
+	"reflect"
+
+	"go-hep.org/x/hep/fwk"
+	"go-hep.org/x/hep/groot"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/rtree"
+)
+
+// OutputStreamer writes data to a ROOT tree.
+//
+// Each fwk port is written to a tree branch named after it (see BranchName).
+// Slices are written as variable-length arrays, whose number of elements
+// is stored in an additional branch named "fwk_n_" + branch-name.
+type OutputStreamer struct {
+	Name string // output filename
+	Tree string // name of the output tree
+
+	// Options configures the output tree (compression, basket size, ...)
+	Options []rtree.WriteOption
+
+	f     *riofs.File  // underlying output file
+	w     rtree.Writer // output tree
+	ports []fwk.Port
+	vals  []reflect.Value
+	cnts  map[int]*int32 // slice-port index to count value
+}
+
+func (o *OutputStreamer) Connect(ports []fwk.Port) error {
+	var err error
+
+	o.ports = make([]fwk.Port, len(ports))
+	copy(o.ports, ports)
+
+	// FIXME(sbinet): handle local/remote files, protocols
+	o.f, err = groot.Create(o.Name)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wvars = make([]rtree.WriteVar, 0, len(ports))
+		cvars []rtree.WriteVar
+	)
+	o.vals = make([]reflect.Value, len(ports))
+	o.cnts = make(map[int]*int32)
+	names := make(map[string]string, len(ports))
+	for i, port := range o.ports {
+		name := BranchName(port.Name)
+		if prev, dup := names[name]; dup {
+			_ = o.f.Close()
+			return fmt.Errorf(
+				"fwk/groot: ports %q and %q map to the same branch %q",
+				prev, port.Name, name,
+			)
+		}
+		names[name] = port.Name
+
+		o.vals[i] = reflect.New(port.Type)
+		wvar := rtree.WriteVar{
+			Name:  name,
+			Value: o.vals[i].Interface(),
+		}
+		if port.Type.Kind() == reflect.Slice {
+			n := new(int32)
+			o.cnts[i] = n
+			wvar.Count = countName(name)
+			cvars = append(cvars, rtree.WriteVar{Name: wvar.Count, Value: n})
+		}
+		wvars = append(wvars, wvar)
+	}
+	// count branches need to be declared before the slices they describe.
+	wvars = append(cvars, wvars...)
+
+	o.w, err = rtree.NewWriter(o.f, o.Tree, wvars, o.Options...)
+	if err != nil {
+		_ = o.f.Close()
+		return fmt.Errorf("fwk/groot: could not create tree writer: %w", err)
+	}
+
+	return err
+}
+
+func (o *OutputStreamer) Disconnect() error {
+	err := o.w.Close()
+
+	// make sure we don't leak filedescriptors, even if the tree
+	// could not be closed.
+	errc := o.f.Close()
+	if err != nil {
+		return err
+	}
+
+	return errc
+}
+
+func (o *OutputStreamer) Write(ctx fwk.Context) error {
+	store := ctx.Store()
+
+	for i, port := range o.ports {
+		obj, err := store.Get(port.Name)
+		if err != nil {
+			return err
+		}
+
+		rv := reflect.ValueOf(obj)
+		if rv.Type() != port.Type {
+			return fmt.Errorf("branch[%s]: got type=%q. want type=%q.",
+				port.Name,
+				rv.Type(),
+				port.Type,
+			)
+		}
+
+		o.vals[i].Elem().Set(rv)
+		if n, ok := o.cnts[i]; ok {
+			*n = int32(rv.Len())
+		}
+	}
+
+	_, err := o.w.Write()
+	if err != nil {
+		return fmt.Errorf("fwk/groot: could not write entry: %w", err)
+	}
+
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 
 	"go-hep.org/x/hep/fwk"
 	"go-hep.org/x/hep/fwk/fsm"
+	"go-hep.org/x/hep/groot"
 	"go-hep.org/x/hep/hbook"
 	"go-hep.org/x/hep/rio"
 )
@@ -66,6 +67,19 @@ func (svc *hsvc) StartSvc(ctx fwk.Context) error {
 			if dup {
 				return fmt.Errorf("%s: duplicate read-stream %q", svc.Name(), name)
 			}
+			if isROOT(stream.Name) {
+				f, err := groot.Open(stream.Name)
+				if err != nil {
+					return fmt.Errorf("error opening ROOT file [%s]: %w", stream.Name, err)
+				}
+				svc.r[name] = istream{
+					name:  name,
+					fname: stream.Name,
+					rf:    f,
+				}
+				continue
+			}
+
 			// FIXME(sbinet): handle remote/local files + protocols
 			f, err := os.Open(stream.Name)
 			if err != nil {
@@ -88,6 +102,19 @@ func (svc *hsvc) StartSvc(ctx fwk.Context) error {
 			if dup {
 				return fmt.Errorf("%s: duplicate write-stream %q", svc.Name(), name)
 			}
+			if isROOT(stream.Name) {
+				f, err := groot.Create(stream.Name)
+				if err != nil {
+					return fmt.Errorf("error creating ROOT file [%s]: %w", stream.Name, err)
+				}
+				svc.w[name] = ostream{
+					name:  name,
+					fname: stream.Name,
+					wf:    f,
+				}
+				continue
+			}
+
 			// FIXME(sbinet): handle remote/local files + protocols
 			f, err := os.Create(stream.Name)
 			if err != nil {
@@ -282,6 +309,9 @@ func (svc *hsvc) BookP1D(name string, nbins int, low, high float64) (fwk.P1D, er
 		if !ok {
 			return h, fmt.Errorf("fwk: no stream [%s] declared", sname)
 		}
+		if isROOT(str.Name) {
+			return h, fmt.Errorf("fwk: P1D not supported by ROOT stream [%s]", sname)
+		}
 		switch str.Mode {
 		case Read:
 			r, ok := svc.r[sname]
@@ -6,13 +6,17 @@ package hbooksvc
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
 
 	"go-hep.org/x/hep/fwk"
 	"go-hep.org/x/hep/fwk/job"
+	"go-hep.org/x/hep/groot"
+	"go-hep.org/x/hep/hbook"
 )
 
 const (
@@ -90,6 +94,73 @@ func TestHbookSvcConc(t *testing.T) {
 	}
 }
 
+func TestHbookSvcROOT(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "fwk-hbooksvc-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	fname := filepath.Join(tmp, "hist.root")
+
+	app := newapp(nentries, 2)
+	for i := 0; i < nhists; i++ {
+		app.Create(job.C{
+			Type: "go-hep.org/x/hep/fwk/hbooksvc.testhsvc",
+			Name: fmt.Sprintf("t%03d", i),
+			Props: job.P{
+				"Stream": "/my-hist",
+			},
+		})
+	}
+
+	app.Create(job.C{
+		Type: "go-hep.org/x/hep/fwk/hbooksvc.hsvc",
+		Name: "histsvc",
+		Props: job.P{
+			"Streams": map[string]Stream{
+				"/my-hist": {
+					Name: fname,
+					Mode: Write,
+				},
+			},
+		},
+	})
+
+	err = app.App().Run()
+	if err != nil {
+		t.Fatalf("could not run job: %+v", err)
+	}
+
+	f, err := groot.Open(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	r := istream{name: "/my-hist", fname: fname, rf: f}
+	for i := 0; i < nhists; i++ {
+		name := fmt.Sprintf("h1d-t%03d", i)
+		var h hbook.H1D
+		err := r.read(name, &h)
+		if err != nil {
+			t.Fatalf("could not read %q: %+v", name, err)
+		}
+		if got, want := h.Entries(), int64(nentries); got != want {
+			t.Fatalf("%s: invalid number of entries: got=%d, want=%d", name, got, want)
+		}
+		if got, want := h.XMean(), 49.5; got != want {
+			t.Fatalf("%s: invalid mean: got=%v, want=%v", name, got, want)
+		}
+	}
+
+	var h hbook.H2D
+	err = r.read("h1d-t000", &h)
+	if err == nil {
+		t.Fatalf("expected an error reading a 1-dim histogram as a 2-dim one")
+	}
+}
+
 func TestHbookStreamName(t *testing.T) {
 	var svc hsvc
 	for _, test := range []struct {
@@ -8,8 +8,15 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"go-hep.org/x/hep/fwk"
+	"go-hep.org/x/hep/groot/rhist"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/root"
+	"go-hep.org/x/hep/hbook"
+	"go-hep.org/x/hep/hbook/rootcnv"
 	"go-hep.org/x/hep/rio"
 )
 
@@ -21,21 +28,34 @@ const (
 	Write Mode = Mode(os.O_WRONLY)
 )
 
-// Stream defines an input or output hbook stream
+// Stream defines an input or output hbook stream.
+//
+// Streams whose file name has a ".root" extension are read from
+// or written to ROOT files. All other streams use the rio format.
 type Stream struct {
 	Name string // input|output file name
 	Mode Mode   // read|write
 }
 
+// isROOT returns whether the provided file name is a ROOT file.
+func isROOT(fname string) bool {
+	return strings.ToLower(filepath.Ext(fname)) == ".root"
+}
+
 type istream struct {
 	name  string // stream name
 	fname string // file name
 	f     io.ReadCloser
 	r     *rio.Reader
+	rf    *riofs.File // ROOT file, for ROOT streams
 	objs  []fwk.Hist
 }
 
 func (stream *istream) close() error {
+	if stream.rf != nil {
+		return stream.rf.Close()
+	}
+
 	defer stream.f.Close() // do not leak file descriptors
 	err := stream.r.Close()
 	if err != nil {
@@ -53,6 +73,10 @@ func (stream *istream) close() error {
 func (stream *istream) read(name string, ptr interface{}) error {
 	var err error
 
+	if stream.rf != nil {
+		return stream.readROOT(name, ptr)
+	}
+
 	seekr, ok := stream.f.(io.Seeker)
 	if !ok {
 		return fmt.Errorf("hbooksvc: input stream [%s] is not seek-able", stream.name)
@@ -102,15 +126,55 @@ func (stream *istream) read(name string, ptr interface{}) error {
 	return err
 }
 
+func (stream *istream) readROOT(name string, ptr interface{}) error {
+	obj, err := riofs.Dir(stream.rf).Get(name)
+	if err != nil {
+		return fmt.Errorf(
+			"hbooksvc: could not find object [%s] in stream [%s]: %w",
+			name, stream.name, err,
+		)
+	}
+
+	switch ptr := ptr.(type) {
+	case *hbook.H1D:
+		h, ok := obj.(rhist.H1)
+		if !ok {
+			return fmt.Errorf("hbooksvc: object [%s] in stream [%s] is not a 1-dim histogram (type=%s)", name, stream.name, obj.Class())
+		}
+		*ptr = *rootcnv.H1D(h)
+	case *hbook.H2D:
+		h, ok := obj.(rhist.H2)
+		if !ok {
+			return fmt.Errorf("hbooksvc: object [%s] in stream [%s] is not a 2-dim histogram (type=%s)", name, stream.name, obj.Class())
+		}
+		*ptr = *rootcnv.H2D(h)
+	case *hbook.S2D:
+		g, ok := obj.(rhist.Graph)
+		if !ok {
+			return fmt.Errorf("hbooksvc: object [%s] in stream [%s] is not a graph (type=%s)", name, stream.name, obj.Class())
+		}
+		*ptr = *rootcnv.S2D(g)
+	default:
+		return fmt.Errorf("hbooksvc: can not read object [%s] of type %T from ROOT stream [%s]", name, ptr, stream.name)
+	}
+
+	return nil
+}
+
 type ostream struct {
 	name  string // stream name
 	fname string // file name
 	f     io.WriteCloser
 	w     *rio.Writer
+	wf    *riofs.File // ROOT file, for ROOT streams
 	objs  []fwk.Hist
 }
 
 func (stream *ostream) write() error {
+	if stream.wf != nil {
+		return stream.writeROOT()
+	}
+
 	for i := range stream.objs {
 		obj := stream.objs[i]
 		name := string(obj.Name())
@@ -144,7 +208,42 @@ func (stream *ostream) write() error {
 	return nil
 }
 
+func (stream *ostream) writeROOT() error {
+	for _, obj := range stream.objs {
+		name := string(obj.Name())
+
+		var robj root.Object
+		switch v := obj.Value().(type) {
+		case *hbook.H1D:
+			robj = rhist.NewH1DFrom(v)
+		case *hbook.H2D:
+			robj = rhist.NewH2DFrom(v)
+		case *hbook.S2D:
+			robj = rhist.NewGraphAsymmErrorsFrom(v)
+		default:
+			return fmt.Errorf(
+				"error writing object [%s] to stream [%s]: unsupported ROOT type %T",
+				name, stream.name, v,
+			)
+		}
+
+		err := riofs.Dir(stream.wf).Put(name, robj)
+		if err != nil {
+			return fmt.Errorf(
+				"error writing object [%s] to stream [%s]: %w",
+				name, stream.name, err,
+			)
+		}
+	}
+
+	return nil
+}
+
 func (stream *ostream) close() error {
+	if stream.wf != nil {
+		return stream.wf.Close()
+	}
+
 	defer stream.f.Close() // do not leak file descriptors
 	err := stream.w.Close()
 	if err != nil {
@@ -0,0 +1,88 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fwk
+
+import (
+	"time"
+)
+
+// Monitor is a service notified of the progress of the event loop.
+// Monitors can be used to profile a fwk application.
+//
+// Monitor methods may be called concurrently and must be concurrency-safe.
+// BeginTask and EndTask are called from the goroutine running the task.
+type Monitor interface {
+	Svc
+
+	// BeginEvent is called when the processing of the event ctx.ID() starts.
+	BeginEvent(ctx Context)
+	// EndEvent is called when the processing of the event ctx.ID() ends.
+	// EndEvent is called with io.EOF when the input stream was exhausted:
+	// no event was then read nor processed.
+	EndEvent(ctx Context, err error)
+
+	// BeginTask is called just before tsk processes the event ctx.ID().
+	BeginTask(ctx Context, tsk Task)
+	// EndTask is called just after tsk processed the event ctx.ID().
+	// EndTask is called with io.EOF when tsk is the input stream and
+	// it was exhausted.
+	EndTask(ctx Context, tsk Task, err error)
+
+	// Stall is called when tsk retrieved the data item key from
+	// the event store, with the duration d spent waiting for that
+	// data item to be available.
+	Stall(ctx Context, tsk Task, key string, d time.Duration)
+}
+
+// monitors dispatches event-loop notifications to a set of Monitors.
+type monitors []Monitor
+
+func (mons monitors) beginEvent(ctx Context) {
+	for _, mon := range mons {
+		mon.BeginEvent(ctx)
+	}
+}
+
+func (mons monitors) endEvent(ctx Context, err error) {
+	for _, mon := range mons {
+		mon.EndEvent(ctx, err)
+	}
+}
+
+// process runs tsk on the event ctx, notifying all monitors.
+func (mons monitors) process(ctx ctxType, tsk Task) error {
+	if len(mons) == 0 {
+		return tsk.Process(ctx)
+	}
+
+	ctx.store = &monstore{Store: ctx.store, ctx: ctx, tsk: tsk, mons: mons}
+	for _, mon := range mons {
+		mon.BeginTask(ctx, tsk)
+	}
+	err := tsk.Process(ctx)
+	for i := len(mons) - 1; i >= 0; i-- {
+		mons[i].EndTask(ctx, tsk, err)
+	}
+	return err
+}
+
+// monstore is an event store recording the time tasks spend
+// waiting for their inputs.
+type monstore struct {
+	Store
+	ctx  Context
+	tsk  Task
+	mons monitors
+}
+
+func (ms *monstore) Get(k string) (interface{}, error) {
+	start := time.Now()
+	v, err := ms.Store.Get(k)
+	d := time.Since(start)
+	for _, mon := range ms.mons {
+		mon.Stall(ms.ctx, ms.tsk, k, d)
+	}
+	return v, err
+}
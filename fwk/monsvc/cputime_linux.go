@@ -0,0 +1,36 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package monsvc
+
+import (
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// threadCPUBegin locks the calling goroutine to its OS thread and
+// returns the CPU time consumed so far by that thread.
+// threadCPUBegin must be paired with a call to threadCPUEnd, from the
+// same goroutine.
+func threadCPUBegin() time.Duration {
+	runtime.LockOSThread()
+	return threadCPU()
+}
+
+// threadCPUEnd returns the CPU time consumed so far by the thread
+// of the calling goroutine and unlocks that goroutine from its OS thread.
+func threadCPUEnd() time.Duration {
+	defer runtime.UnlockOSThread()
+	return threadCPU()
+}
+
+func threadCPU() time.Duration {
+	var ru syscall.Rusage
+	err := syscall.Getrusage(syscall.RUSAGE_THREAD, &ru)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano())
+}
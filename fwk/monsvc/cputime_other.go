@@ -0,0 +1,20 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package monsvc
+
+import (
+	"time"
+)
+
+// threadCPUBegin returns the CPU time consumed by the current thread.
+// Per-thread CPU time is only available on Linux.
+func threadCPUBegin() time.Duration { return 0 }
+
+// threadCPUEnd returns the CPU time consumed by the current thread.
+// Per-thread CPU time is only available on Linux.
+func threadCPUEnd() time.Duration { return 0 }
@@ -0,0 +1,20 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package monsvc
+
+// memStats holds process-wide memory statistics.
+type memStats struct {
+	allocBytes uint64 // cumulative bytes allocated on the heap
+	allocObjs  uint64 // cumulative number of heap allocations
+	gcCycles   uint64 // number of completed GC cycles
+}
+
+func (m memStats) sub(o memStats) memStats {
+	return memStats{
+		allocBytes: m.allocBytes - o.allocBytes,
+		allocObjs:  m.allocObjs - o.allocObjs,
+		gcCycles:   m.gcCycles - o.gcCycles,
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !go1.16
+// +build !go1.16
+
+package monsvc
+
+import (
+	"runtime"
+)
+
+func readMemStats() memStats {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return memStats{
+		allocBytes: ms.TotalAlloc,
+		allocObjs:  ms.Mallocs,
+		gcCycles:   uint64(ms.NumGC),
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.16
+// +build go1.16
+
+package monsvc
+
+import (
+	"runtime/metrics"
+)
+
+var memSamples = []string{
+	"/gc/heap/allocs:bytes",
+	"/gc/heap/allocs:objects",
+	"/gc/cycles/total:gc-cycles",
+}
+
+func readMemStats() memStats {
+	samples := make([]metrics.Sample, len(memSamples))
+	for i, name := range memSamples {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+
+	value := func(i int) uint64 {
+		if samples[i].Value.Kind() != metrics.KindUint64 {
+			return 0
+		}
+		return samples[i].Value.Uint64()
+	}
+
+	return memStats{
+		allocBytes: value(0),
+		allocObjs:  value(1),
+		gcCycles:   value(2),
+	}
+}
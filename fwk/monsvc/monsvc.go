@@ -0,0 +1,328 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package monsvc provides a fwk service monitoring the event loop of
+// a fwk application.
+//
+// The monitoring service records, for each task, the wall time and the
+// CPU time spent processing events, the time spent waiting for inputs
+// (data-flow stalls) and the task throughput.
+// It also records the number of events concurrently in flight and the
+// memory allocations performed during the event loop.
+//
+// A summary table is printed when the service is stopped.
+// If the 'Trace' property is set, a trace of the concurrent run is written
+// to that file, in the Chrome trace-event JSON format (which can be
+// displayed with chrome://tracing or https://ui.perfetto.dev.)
+package monsvc // import "go-hep.org/x/hep/fwk/monsvc"
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"go-hep.org/x/hep/fwk"
+)
+
+// taskStats holds the monitoring data of a task.
+type taskStats struct {
+	name  string
+	n     int64         // number of processed events
+	nerrs int64         // number of events processed with an error
+	wall  time.Duration // total wall time
+	cpu   time.Duration // total CPU time
+	stall time.Duration // total time spent waiting for inputs
+	min   time.Duration // minimum wall time per event
+	max   time.Duration // maximum wall time per event
+}
+
+func (st *taskStats) add(wall, cpu time.Duration, err error) {
+	if st.n == 0 || wall < st.min {
+		st.min = wall
+	}
+	if wall > st.max {
+		st.max = wall
+	}
+	st.n++
+	st.wall += wall
+	st.cpu += cpu
+	if err != nil {
+		st.nerrs++
+	}
+}
+
+// callKey identifies a task processing a given event.
+type callKey struct {
+	evt  int64
+	slot int
+	tsk  string
+}
+
+type call struct {
+	beg time.Time
+	cpu time.Duration
+}
+
+type monsvc struct {
+	fwk.SvcBase
+
+	trace string // name of the Chrome trace-event output file
+
+	mu    sync.Mutex
+	start time.Time // start of the monitoring
+	mem   memStats  // memory statistics at start of the monitoring
+
+	tsks  map[string]*taskStats
+	calls map[callKey]call
+
+	nevts    int64         // number of processed events
+	inflight int           // number of events currently in flight
+	maxfly   int           // maximum number of events concurrently in flight
+	flyint   time.Duration // time integral of the number of events in flight
+	flybeg   time.Time     // time of the first event
+	flyend   time.Time     // time of the last event
+	flylast  time.Time     // time of the last change of the number of events in flight
+
+	tr *tracer
+}
+
+func (svc *monsvc) Configure(ctx fwk.Context) error {
+	return nil
+}
+
+func (svc *monsvc) StartSvc(ctx fwk.Context) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	svc.tsks = make(map[string]*taskStats)
+	svc.calls = make(map[callKey]call)
+	svc.nevts = 0
+	svc.inflight = 0
+	svc.maxfly = 0
+	svc.flyint = 0
+	svc.flybeg = time.Time{}
+	svc.flyend = time.Time{}
+	svc.tr = nil
+
+	svc.start = time.Now()
+	svc.mem = readMemStats()
+	if svc.trace != "" {
+		svc.tr = newTracer(svc.start)
+	}
+	return nil
+}
+
+func (svc *monsvc) StopSvc(ctx fwk.Context) error {
+	mem := readMemStats().sub(svc.mem)
+
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	msg := ctx.Msg()
+	for _, line := range strings.Split(svc.summary(mem), "\n") {
+		if line == "" {
+			continue
+		}
+		msg.Infof("%s\n", line)
+	}
+
+	if svc.tr != nil {
+		err := svc.tr.writeFile(svc.trace)
+		if err != nil {
+			return fmt.Errorf("%s: could not write trace file %q: %w", svc.Name(), svc.trace, err)
+		}
+	}
+
+	return nil
+}
+
+func (svc *monsvc) BeginEvent(ctx fwk.Context) {
+	now := time.Now()
+
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	if svc.flybeg.IsZero() {
+		svc.flybeg = now
+		svc.flylast = now
+	}
+	svc.updateInflight(now, +1)
+}
+
+func (svc *monsvc) EndEvent(ctx fwk.Context, err error) {
+	now := time.Now()
+
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	if err == io.EOF {
+		// the input stream was exhausted: this was not an event.
+		svc.inflight--
+		svc.updateInflight(now, 0)
+		return
+	}
+
+	svc.nevts++
+	svc.flyend = now
+	svc.updateInflight(now, -1)
+}
+
+func (svc *monsvc) updateInflight(now time.Time, delta int) {
+	svc.flyint += time.Duration(svc.inflight) * now.Sub(svc.flylast)
+	svc.flylast = now
+	svc.inflight += delta
+	if svc.inflight > svc.maxfly {
+		svc.maxfly = svc.inflight
+	}
+	if svc.tr != nil {
+		svc.tr.counter("events in flight", now, svc.inflight)
+	}
+}
+
+func (svc *monsvc) BeginTask(ctx fwk.Context, tsk fwk.Task) {
+	cpu := threadCPUBegin()
+	now := time.Now()
+
+	key := callKey{evt: ctx.ID(), slot: ctx.Slot(), tsk: tsk.Name()}
+	svc.mu.Lock()
+	svc.calls[key] = call{beg: now, cpu: cpu}
+	svc.mu.Unlock()
+}
+
+func (svc *monsvc) EndTask(ctx fwk.Context, tsk fwk.Task, err error) {
+	now := time.Now()
+	cpu := threadCPUEnd()
+
+	key := callKey{evt: ctx.ID(), slot: ctx.Slot(), tsk: tsk.Name()}
+
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	beg, ok := svc.calls[key]
+	if !ok {
+		return
+	}
+	delete(svc.calls, key)
+
+	if err == io.EOF {
+		// the input stream was exhausted: no event was read.
+		return
+	}
+
+	st := svc.stats(key.tsk)
+	st.add(now.Sub(beg.beg), cpu-beg.cpu, err)
+
+	if svc.tr != nil {
+		svc.tr.task(key, beg.beg, now)
+	}
+}
+
+func (svc *monsvc) Stall(ctx fwk.Context, tsk fwk.Task, key string, d time.Duration) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	svc.stats(tsk.Name()).stall += d
+}
+
+func (svc *monsvc) stats(name string) *taskStats {
+	st, ok := svc.tsks[name]
+	if !ok {
+		st = &taskStats{name: name}
+		svc.tsks[name] = st
+	}
+	return st
+}
+
+// summary returns the summary table of the monitoring data.
+func (svc *monsvc) summary(mem memStats) string {
+	var (
+		o    = new(strings.Builder)
+		tw   = tabwriter.NewWriter(o, 0, 8, 1, ' ', tabwriter.AlignRight)
+		tsks = make([]*taskStats, 0, len(svc.tsks))
+		loop = svc.flyend.Sub(svc.flybeg)
+	)
+
+	for _, st := range svc.tsks {
+		tsks = append(tsks, st)
+	}
+	sort.Slice(tsks, func(i, j int) bool {
+		if tsks[i].wall == tsks[j].wall {
+			return tsks[i].name < tsks[j].name
+		}
+		return tsks[i].wall > tsks[j].wall
+	})
+
+	fmt.Fprintf(tw, "task\tevts\terrs\twall\twall/evt\tmin\tmax\tcpu\tstall\tevt/s\t\n")
+	for _, st := range tsks {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%v\t%v\t%v\t%v\t%v\t%v\t%.1f\t\n",
+			st.name, st.n, st.nerrs,
+			round(st.wall), round(mean(st.wall, st.n)),
+			round(st.min), round(st.max),
+			round(st.cpu), round(st.stall),
+			rate(st.n, st.wall),
+		)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(o, "events:     %d\n", svc.nevts)
+	fmt.Fprintf(o, "event-loop: %v\n", round(loop))
+	fmt.Fprintf(o, "throughput: %.1f evt/s\n", rate(svc.nevts, loop))
+	fmt.Fprintf(o, "in-flight:  max=%d mean=%.2f\n", svc.maxfly, ratio(svc.flyint, loop))
+	fmt.Fprintf(o, "mem: allocs:    %10d kB\n", mem.allocBytes/1024)
+	fmt.Fprintf(o, "mem: n-allocs:  %10d\n", mem.allocObjs)
+	fmt.Fprintf(o, "mem: gc-cycles: %10d\n", mem.gcCycles)
+	return o.String()
+}
+
+func mean(d time.Duration, n int64) time.Duration {
+	if n == 0 {
+		return 0
+	}
+	return d / time.Duration(n)
+}
+
+func rate(n int64, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(n) / d.Seconds()
+}
+
+func ratio(a, b time.Duration) float64 {
+	if b <= 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Microsecond)
+}
+
+func newmonsvc(typ, name string, mgr fwk.App) (fwk.Component, error) {
+	var err error
+	svc := &monsvc{
+		SvcBase: fwk.NewSvc(typ, name, mgr),
+		tsks:    make(map[string]*taskStats),
+		calls:   make(map[callKey]call),
+	}
+
+	err = svc.DeclProp("Trace", &svc.trace)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc, err
+}
+
+func init() {
+	fwk.Register(reflect.TypeOf(monsvc{}), newmonsvc)
+}
+
+var _ fwk.Monitor = (*monsvc)(nil)
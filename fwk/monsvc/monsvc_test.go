@@ -0,0 +1,193 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package monsvc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-hep.org/x/hep/fwk"
+	"go-hep.org/x/hep/fwk/job"
+	"go-hep.org/x/hep/fwk/testdata"
+)
+
+func newapp(evtmax int64, nprocs int) *job.Job {
+	return job.NewJob(nil, job.P{
+		"EvtMax":   evtmax,
+		"NProcs":   nprocs,
+		"MsgLevel": job.MsgLevel("ERROR"),
+	})
+}
+
+func TestMonSvc(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "fwk-monsvc-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	const evtmax = 20
+	for _, nprocs := range []int{0, 1, 4} {
+		t.Run(fmt.Sprintf("nprocs=%d", nprocs), func(t *testing.T) {
+			fname := filepath.Join(tmp, fmt.Sprintf("trace-%d.json", nprocs))
+
+			app := newapp(evtmax, nprocs)
+			app.Create(job.C{
+				Type: "go-hep.org/x/hep/fwk/testdata.task1",
+				Name: "t1",
+				Props: job.P{
+					"Ints1": "t1-ints1",
+					"Ints2": "t1-ints2",
+				},
+			})
+			app.Create(job.C{
+				Type: "go-hep.org/x/hep/fwk/testdata.task2",
+				Name: "t2",
+				Props: job.P{
+					"Input":  "t1-ints1",
+					"Output": "t1-ints1-massaged",
+				},
+			})
+			svc := app.Create(job.C{
+				Type: "go-hep.org/x/hep/fwk/monsvc.monsvc",
+				Name: "monsvc",
+				Props: job.P{
+					"Trace": fname,
+				},
+			}).(*monsvc)
+
+			err := app.App().Run()
+			if err != nil {
+				t.Fatalf("could not run job: %+v", err)
+			}
+
+			if got, want := svc.nevts, int64(evtmax); got != want {
+				t.Fatalf("invalid number of events: got=%d, want=%d", got, want)
+			}
+
+			if svc.maxfly < 1 {
+				t.Fatalf("invalid number of in-flight events: %d", svc.maxfly)
+			}
+
+			if svc.inflight != 0 {
+				t.Fatalf("invalid number of remaining in-flight events: %d", svc.inflight)
+			}
+
+			if len(svc.calls) != 0 {
+				t.Fatalf("invalid number of pending task calls: %d", len(svc.calls))
+			}
+
+			for _, name := range []string{"t1", "t2", "app-evtloop"} {
+				st, ok := svc.tsks[name]
+				if !ok {
+					t.Fatalf("no monitoring data for task %q", name)
+				}
+				if got, want := st.n, int64(evtmax); got != want {
+					t.Fatalf("invalid number of events for task %q: got=%d, want=%d", name, got, want)
+				}
+				if st.min > st.max || st.wall < st.max {
+					t.Fatalf("invalid timings for task %q: %+v", name, *st)
+				}
+			}
+
+			summary := svc.summary(memStats{})
+			for _, want := range []string{"task", "t1", "t2", "throughput:", "in-flight:", "mem: allocs:"} {
+				if !strings.Contains(summary, want) {
+					t.Fatalf("summary is missing %q:\n%s", want, summary)
+				}
+			}
+
+			raw, err := ioutil.ReadFile(fname)
+			if err != nil {
+				t.Fatalf("could not read trace file: %+v", err)
+			}
+
+			var tr traceFile
+			err = json.Unmarshal(raw, &tr)
+			if err != nil {
+				t.Fatalf("could not decode trace file: %+v", err)
+			}
+
+			ntsks := 0
+			for _, evt := range tr.Events {
+				if evt.Ph == "X" {
+					ntsks++
+				}
+			}
+			if got, want := ntsks, 3*evtmax; got != want {
+				t.Fatalf("invalid number of task trace events: got=%d, want=%d", got, want)
+			}
+		})
+	}
+}
+
+func TestMonSvcInputStream(t *testing.T) {
+	const nevts = 10
+	for _, nprocs := range []int{0, 1, 4} {
+		t.Run(fmt.Sprintf("nprocs=%d", nprocs), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			for i := 0; i < nevts; i++ {
+				fmt.Fprintf(buf, "%d\n", i)
+			}
+
+			app := newapp(-1, nprocs)
+			app.Create(job.C{
+				Type: "go-hep.org/x/hep/fwk.InputStream",
+				Name: "input",
+				Props: job.P{
+					"Ports": []fwk.Port{
+						{Name: "ints", Type: reflect.TypeOf(int64(0))},
+					},
+					"Streamer": &testdata.InputStream{R: buf},
+				},
+			})
+			app.Create(job.C{
+				Type: "go-hep.org/x/hep/fwk/testdata.task2",
+				Name: "t2",
+				Props: job.P{
+					"Input":  "ints",
+					"Output": "ints-sq",
+				},
+			})
+			svc := app.Create(job.C{
+				Type: "go-hep.org/x/hep/fwk/monsvc.monsvc",
+				Name: "monsvc",
+			}).(*monsvc)
+
+			err := app.App().Run()
+			if err != nil {
+				t.Fatalf("could not run job: %+v", err)
+			}
+
+			if got, want := svc.nevts, int64(nevts); got != want {
+				t.Fatalf("invalid number of events: got=%d, want=%d", got, want)
+			}
+
+			if svc.inflight != 0 {
+				t.Fatalf("invalid number of remaining in-flight events: %d", svc.inflight)
+			}
+
+			for _, name := range []string{"input", "t2"} {
+				st, ok := svc.tsks[name]
+				if !ok {
+					t.Fatalf("no monitoring data for task %q", name)
+				}
+				if got, want := st.n, int64(nevts); got != want {
+					t.Fatalf("invalid number of events for task %q: got=%d, want=%d", name, got, want)
+				}
+				if st.nerrs != 0 {
+					t.Fatalf("invalid number of errors for task %q: %d", name, st.nerrs)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package monsvc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// traceEvent is an event in the Chrome trace-event format.
+//
+// See https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+type traceEvent struct {
+	Name string                 `json:"name"`
+	Cat  string                 `json:"cat,omitempty"`
+	Ph   string                 `json:"ph"`
+	Ts   float64                `json:"ts"`            // timestamp, in microseconds
+	Dur  float64                `json:"dur,omitempty"` // duration, in microseconds
+	Pid  int                    `json:"pid"`
+	Tid  int                    `json:"tid"`
+	Args map[string]interface{} `json:"args,omitempty"`
+}
+
+type traceFile struct {
+	Events []traceEvent `json:"traceEvents"`
+	Unit   string       `json:"displayTimeUnit"`
+}
+
+// tracer collects trace events of a concurrent run.
+// tracer is not concurrency-safe.
+type tracer struct {
+	start time.Time
+	tids  map[tidKey]int
+	evts  []traceEvent
+}
+
+// tidKey identifies a trace "thread", ie: a task running on
+// a given event slot.
+type tidKey struct {
+	slot int
+	tsk  string
+}
+
+func newTracer(start time.Time) *tracer {
+	return &tracer{
+		start: start,
+		tids:  make(map[tidKey]int),
+	}
+}
+
+func (tr *tracer) ts(t time.Time) float64 {
+	return float64(t.Sub(tr.start).Nanoseconds()) / 1e3
+}
+
+func (tr *tracer) tid(key tidKey) int {
+	tid, ok := tr.tids[key]
+	if ok {
+		return tid
+	}
+	tid = len(tr.tids) + 1
+	tr.tids[key] = tid
+	tr.evts = append(tr.evts, traceEvent{
+		Name: "thread_name",
+		Ph:   "M",
+		Tid:  tid,
+		Args: map[string]interface{}{
+			"name": fmt.Sprintf("slot-%03d/%s", key.slot, key.tsk),
+		},
+	})
+	return tid
+}
+
+func (tr *tracer) task(key callKey, beg, end time.Time) {
+	tr.evts = append(tr.evts, traceEvent{
+		Name: key.tsk,
+		Cat:  "task",
+		Ph:   "X",
+		Ts:   tr.ts(beg),
+		Dur:  float64(end.Sub(beg).Nanoseconds()) / 1e3,
+		Tid:  tr.tid(tidKey{slot: key.slot, tsk: key.tsk}),
+		Args: map[string]interface{}{"evt": key.evt},
+	})
+}
+
+func (tr *tracer) counter(name string, t time.Time, v int) {
+	tr.evts = append(tr.evts, traceEvent{
+		Name: name,
+		Ph:   "C",
+		Ts:   tr.ts(t),
+		Args: map[string]interface{}{"n": v},
+	})
+}
+
+func (tr *tracer) writeFile(fname string) error {
+	f, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = json.NewEncoder(f).Encode(traceFile{
+		Events: tr.evts,
+		Unit:   "ms",
+	})
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
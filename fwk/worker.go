@@ -22,6 +22,7 @@ type worker struct {
 	//store datastore
 	ctxs []ctxType
 	msg  msgstream
+	mons monitors
 
 	evts   <-chan ctxType
 	done   chan<- struct{}
@@ -35,6 +36,7 @@ func newWorker(i int, app *appmgr, ctrl *workercontrol) *worker {
 		keys:   app.dflow.keys(),
 		ctxs:   make([]ctxType, len(app.tsks)),
 		msg:    newMsgStream(fmt.Sprintf("%s-worker-%03d", app.name, i), app.msg.lvl, nil),
+		mons:   app.mons,
 		evts:   ctrl.evts,
 		done:   ctrl.done,
 		errc:   ctrl.errc,
@@ -85,6 +87,7 @@ func (wrk *worker) runTask(ctx context.Context, ievt ctxType, tsks []Task) {
 		ievt:   ievt.ID(),
 		errc:   make(chan error, len(tsks)),
 		evtctx: evtctx,
+		mons:   wrk.mons,
 	}
 	for i, tsk := range tsks {
 		ctx := wrk.ctxs[i]
@@ -102,6 +105,7 @@ errloop:
 			}
 			ndone++
 			if err != nil {
+				wrk.mons.endEvent(ievt, err)
 				evtstore.close()
 				wrk.msg.flush()
 
@@ -112,11 +116,13 @@ errloop:
 				break errloop
 			}
 		case <-evtctx.Done():
+			wrk.mons.endEvent(ievt, evtctx.Err())
 			evtstore.close()
 			wrk.msg.flush()
 			return
 		}
 	}
+	wrk.mons.endEvent(ievt, nil)
 	err := evtstore.reset(wrk.keys)
 	evtstore.close()
 	wrk.msg.flush()
@@ -132,12 +138,13 @@ type taskrunner struct {
 	evtctx context.Context
 
 	ievt int64
+	mons monitors
 }
 
 func (run taskrunner) run(i int, ctx ctxType, tsk Task) {
 	ctx.id = run.ievt
 	select {
-	case run.errc <- tsk.Process(ctx):
+	case run.errc <- run.mons.process(ctx, tsk):
 		// FIXME(sbinet) dont be so eager to flush...
 		ctx.msg.flush()
 	case <-run.evtctx.Done():
@@ -31,6 +31,7 @@ func main() {
 		typeNames = flag.String("t", ".*", "comma-separated list of (regexp) type names")
 		pkgPath   = flag.String("p", "", "package import path")
 		output    = flag.String("o", "", "output file name")
+		export    = flag.Bool("export", false, "export C++ members with an unexported name, prefixing them with ROOT_")
 		verbose   = flag.Bool("v", false, "enable verbose mode")
 	)
 
@@ -78,7 +79,7 @@ options:
 		defer out.Close()
 	}
 
-	err = generate(out, *pkgPath, types, flag.Arg(0), *verbose)
+	err = generate(out, *pkgPath, types, flag.Arg(0), *export, *verbose)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -89,7 +90,7 @@ options:
 	}
 }
 
-func generate(w io.Writer, pkg string, types []string, fname string, verbose bool) error {
+func generate(w io.Writer, pkg string, types []string, fname string, export, verbose bool) error {
 	f, err := groot.Open(fname)
 	if err != nil {
 		return err
@@ -99,6 +100,7 @@ func generate(w io.Writer, pkg string, types []string, fname string, verbose boo
 	if err != nil {
 		return err
 	}
+	g.ExportMembers(export)
 
 	filters := make([]*regexp.Regexp, len(types))
 	for i, t := range types {
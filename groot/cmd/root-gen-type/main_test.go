@@ -31,6 +31,7 @@ func TestGenerate(t *testing.T) {
 		fname   string
 		want    string
 		types   []string
+		export  bool
 		verbose bool
 	}{
 		{
@@ -38,6 +39,12 @@ func TestGenerate(t *testing.T) {
 			want:  "testdata/small-evnt-tree-fullsplit.txt",
 			types: []string{"Event", "P3"},
 		},
+		{
+			fname:  "../../../hepmc/testdata/small.hepmc3.root",
+			want:   "testdata/small.hepmc3.txt",
+			types:  []string{"HepMC3::.*"},
+			export: true,
+		},
 	} {
 		t.Run(tc.fname, func(t *testing.T) {
 			oname := filepath.Base(tc.fname) + ".go"
@@ -47,7 +54,7 @@ func TestGenerate(t *testing.T) {
 			}
 			defer o.Close()
 
-			err = generate(o, "main", tc.types, tc.fname, tc.verbose)
+			err = generate(o, "main", tc.types, tc.fname, tc.export, tc.verbose)
 			if err != nil {
 				t.Fatalf("could not generate types: %v", err)
 			}
@@ -78,6 +85,17 @@ func TestGenerate(t *testing.T) {
 	}
 }
 
+func TestGenerateMissingElemType(t *testing.T) {
+	err := generate(ioutil.Discard, "main", []string{"HepMC3::GenEventData"}, "../../../hepmc/testdata/small.hepmc3.root", true, false)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	want := `rdict: type "HepMC3::GenParticleData" of the elements of HepMC3::GenEventData::particles is not generated`
+	if got := err.Error(); got != want {
+		t.Fatalf("invalid error:\ngot= %v\nwant=%v", got, want)
+	}
+}
+
 func TestRW(t *testing.T) {
 	dir, err := ioutil.TempDir("", "groot-gen-type-")
 	if err != nil {
@@ -209,7 +227,7 @@ func main() {
 			}
 			defer o.Close()
 
-			err = generate(o, "main", tc.types, tc.fname, tc.verbose)
+			err = generate(o, "main", tc.types, tc.fname, false, tc.verbose)
 			if err != nil {
 				t.Fatalf("could not generate types: %v", err)
 			}
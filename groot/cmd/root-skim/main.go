@@ -0,0 +1,111 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// root-skim selects entries and branches of a tree (or a chain of trees
+// spread over multiple input files) and writes them into an output ROOT file.
+//
+// The selection is a Go-like boolean expression over the branches of the
+// tree, e.g.:
+//  pt > 20 && abs(eta) < 2.5 && len(jets_pt) >= 2
+//
+// The supported functions are abs, sqrt, exp, log, pow, min, max and len.
+//
+// Branches to keep or drop are selected with comma-separated glob patterns.
+// Count branches of kept slice branches are always kept.
+//
+// Usage: root-skim [options] file1.root [file2.root [...]]
+//
+// ex:
+//  $> root-skim -o out.root -t tree -sel "F64 > 2 && I32 < 8" ./testdata/chain.flat.1.root ./testdata/chain.flat.2.root
+//  $> root-skim -o out.root -t tree -keep "*F64*,N" -drop "Arr*" ./testdata/chain.flat.1.root
+//
+// options:
+//   -drop string
+//     	comma-separated list of glob patterns of branches to drop
+//   -j int
+//     	number of concurrent workers evaluating the selection (default: number of CPUs)
+//   -keep string
+//     	comma-separated list of glob patterns of branches to keep (default: all)
+//   -o string
+//     	path to output ROOT file (default "out.root")
+//   -sel string
+//     	selection expression (default: all entries)
+//   -t string
+//     	input tree name to skim (default "tree")
+//   -v	enable verbose mode
+package main // import "go-hep.org/x/hep/groot/cmd/root-skim"
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"go-hep.org/x/hep/groot/rcmd"
+	_ "go-hep.org/x/hep/groot/riofs/plugin/http"
+	_ "go-hep.org/x/hep/groot/riofs/plugin/xrootd"
+)
+
+func main() {
+	log.SetPrefix("root-skim: ")
+	log.SetFlags(0)
+
+	var (
+		oname    = flag.String("o", "out.root", "path to output ROOT file")
+		tname    = flag.String("t", "tree", "input tree name to skim")
+		sel      = flag.String("sel", "", "selection expression (default: all entries)")
+		keep     = flag.String("keep", "", "comma-separated list of glob patterns of branches to keep (default: all)")
+		drop     = flag.String("drop", "", "comma-separated list of glob patterns of branches to drop")
+		nworkers = flag.Int("j", 0, "number of concurrent workers evaluating the selection (default: number of CPUs)")
+		verbose  = flag.Bool("v", false, "enable verbose mode")
+	)
+
+	flag.Usage = func() {
+		fmt.Fprintf(
+			os.Stderr,
+			`Usage: root-skim [options] file1.root [file2.root [...]]
+
+ex:
+ $> root-skim -o out.root -t tree -sel "F64 > 2 && I32 < 8" ./testdata/chain.flat.1.root ./testdata/chain.flat.2.root
+ $> root-skim -o out.root -t tree -keep "*F64*,N" -drop "Arr*" ./testdata/chain.flat.1.root
+
+options:
+`,
+		)
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		log.Fatalf("missing input file(s)")
+	}
+
+	n, err := rcmd.Skim(
+		*oname, *tname, flag.Args(), *sel,
+		patterns(*keep), patterns(*drop),
+		*nworkers, *verbose,
+	)
+	if err != nil {
+		log.Fatalf("could not skim ROOT file(s): %+v", err)
+	}
+
+	if *verbose {
+		log.Printf("wrote %d entries to %q", n, *oname)
+	}
+}
+
+func patterns(s string) []string {
+	var ps []string
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		ps = append(ps, p)
+	}
+	return ps
+}
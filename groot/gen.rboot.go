@@ -64,6 +64,7 @@ var (
 
 		// rhist
 		"TAxis",
+		"TEfficiency",
 		"TGraph", "TGraphErrors", "TGraphAsymmErrors",
 		"TH1", "TH1C", "TH1D", "TH1F", "TH1I", "TH1K", "TH1S",
 		"TH2", "TH2C", "TH2D", "TH2F", "TH2I", "TH2Poly", "TH2PolyBin", "TH2S",
@@ -87,6 +88,8 @@ var (
 		"TBasket",
 		"TBranch", "TBranchElement", "TBranchRef",
 		"TChain",
+		"TEntryList", "TEntryListBlock",
+		"TFriendElement",
 		"TLeaf", "TLeafElement",
 		"TLeafO",
 		"TLeafB", "TLeafS", "TLeafI", "TLeafL",
@@ -96,6 +99,8 @@ var (
 		"TNtuple",
 		"TRefTable",
 		"TTree",
+		"TTreeIndex",
+		"TVirtualIndex",
 	}
 )
 
@@ -347,8 +347,8 @@ func (leaf *{{.Name}}) MarshalROOT(w *rbytes.WBuffer) (int, error) {
 	pos := w.WriteVersion(leaf.rvers)
 	leaf.tleaf.MarshalROOT(w)
 {{- if .WithStreamerElement}}
-	{{.WRangeFunc}}(leaf.min, leaf.elm)
-	{{.WRangeFunc}}(leaf.max, leaf.elm)
+	{{.WRangeFunc}}(leaf.min, nil)
+	{{.WRangeFunc}}(leaf.max, nil)
 {{- else}}
 	{{.WRangeFunc}}(leaf.min)
 	{{.WRangeFunc}}(leaf.max)
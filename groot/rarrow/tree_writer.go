@@ -15,46 +15,73 @@ import (
 	"go-hep.org/x/hep/groot/rtree"
 )
 
-// flatTreeWriter writes ARROW data as a ROOT flat-tree.
-type flatTreeWriter struct {
+// TreeWriter writes ARROW data as a ROOT tree.
+//
+// ARROW data types are mapped to ROOT branches as follows:
+//  - primitive types, strings and binaries are written as scalar branches,
+//  - fixed-size binaries and fixed-size lists are written as arrays,
+//  - lists of primitives, strings or arrays are written as variable-size
+//    slices, with an associated "rarrow_n_<name>" count branch,
+//  - structs are split into one branch per field, named "<name>.<field>",
+//  - lists of structs are split into one variable-size slice branch per
+//    field of the struct, named "<name>.<field>", all sharing the same
+//    "rarrow_n_<name>" count branch.
+//
+// Null lists are written out as empty slices.
+// Other null values are written out as stored in the ARROW data buffers.
+// Other ARROW data types are not supported.
+//
+// Each ARROW record is written out as a separate cluster of entries.
+type TreeWriter struct {
 	w      rtree.Writer
 	schema *arrow.Schema
 	ctx    contextWriter
 }
 
-// NewFlatTreeWriter creates an arrio.Writer that writes ARROW data as a ROOT
-// flat-tree under the provided dir directory.
-func NewFlatTreeWriter(dir riofs.Directory, name string, schema *arrow.Schema, opts ...rtree.WriteOption) (*flatTreeWriter, error) {
-	var (
-		ctx   = newContextWriter(schema)
-		wvars = make([]rtree.WriteVar, 0, len(ctx.wvars)+len(ctx.count))
-	)
-
-	for _, wvar := range ctx.count {
-		wvars = append(wvars, wvar)
+// NewTreeWriter creates an arrio.Writer that writes ARROW data as a ROOT
+// tree under the provided dir directory.
+//
+// The baskets of the tree are flushed after each written ARROW record, so
+// the clusters of the tree are aligned with the records.
+func NewTreeWriter(dir riofs.Directory, name string, schema *arrow.Schema, opts ...rtree.WriteOption) (*TreeWriter, error) {
+	ctx, err := newContextWriter(schema)
+	if err != nil {
+		return nil, fmt.Errorf("rarrow: could not create tree writer %q: %w", name, err)
 	}
+
+	wvars := make([]rtree.WriteVar, 0, len(ctx.count)+len(ctx.wvars))
+	wvars = append(wvars, ctx.count...)
 	wvars = append(wvars, ctx.wvars...)
 
+	opts = append([]rtree.WriteOption{rtree.WithAutoFlush(0)}, opts...)
 	tree, err := rtree.NewWriter(dir, name, wvars, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("rarrow: could not create flat-tree writer %q: %w", name, err)
+		return nil, fmt.Errorf("rarrow: could not create tree writer %q: %w", name, err)
 	}
-	return &flatTreeWriter{w: tree, schema: schema, ctx: ctx}, nil
+	return &TreeWriter{w: tree, schema: schema, ctx: ctx}, nil
+}
+
+// NewFlatTreeWriter creates an arrio.Writer that writes ARROW data as a ROOT
+// flat-tree under the provided dir directory.
+//
+// Deprecated: use NewTreeWriter.
+func NewFlatTreeWriter(dir riofs.Directory, name string, schema *arrow.Schema, opts ...rtree.WriteOption) (*TreeWriter, error) {
+	return NewTreeWriter(dir, name, schema, opts...)
 }
 
 // Close closes the underlying ROOT tree writer.
-func (fw *flatTreeWriter) Close() error {
-	return fw.w.Close()
+func (tw *TreeWriter) Close() error {
+	return tw.w.Close()
 }
 
-// Write writes the provided ARROW record to the underlying ROOT flat-tree.
+// Write writes the provided ARROW record to the underlying ROOT tree.
 // Write implements arrio.Writer.
-func (fw *flatTreeWriter) Write(rec array.Record) error {
-	if src := rec.Schema(); !fw.schema.Equal(src) {
-		return fmt.Errorf("rarrow: invalid input record schema:\n - got= %v\n - want=%v", src, fw.schema)
+func (tw *TreeWriter) Write(rec array.Record) error {
+	if src := rec.Schema(); !tw.schema.Equal(src) {
+		return fmt.Errorf("rarrow: invalid input record schema:\n - got= %v\n - want=%v", src, tw.schema)
 	}
 
-	nrows := rec.Column(0).Len()
+	nrows := int(rec.NumRows())
 	for icol, col := range rec.Columns() {
 		if col.Len() != nrows {
 			return fmt.Errorf(
@@ -66,8 +93,7 @@ func (fw *flatTreeWriter) Write(rec array.Record) error {
 
 	for irow := 0; irow < nrows; irow++ {
 		for icol, col := range rec.Columns() {
-			wvar := &fw.ctx.wvars[icol]
-			err := fw.ctx.readFrom(wvar, irow, col)
+			err := tw.ctx.fills[icol](col, irow)
 			if err != nil {
 				return fmt.Errorf(
 					"rarrow: could not read row=%d from column[%d](name=%s): %w",
@@ -75,255 +101,286 @@ func (fw *flatTreeWriter) Write(rec array.Record) error {
 				)
 			}
 		}
-		_, err := fw.w.Write()
+		_, err := tw.w.Write()
 		if err != nil {
 			return fmt.Errorf("rarrow: could not write row=%d to tree: %w", irow, err)
 		}
 	}
 
+	if nrows > 0 {
+		err := tw.w.Flush()
+		if err != nil {
+			return fmt.Errorf("rarrow: could not flush tree: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// WriteRecords writes all the ARROW records from the provided reader to the
+// underlying ROOT tree.
+// WriteRecords returns the number of written entries.
+func (tw *TreeWriter) WriteRecords(r array.RecordReader) (int64, error) {
+	var n int64
+	for r.Next() {
+		rec := r.Record()
+		err := tw.Write(rec)
+		if err != nil {
+			return n, err
+		}
+		n += rec.NumRows()
+	}
+	return n, nil
+}
+
+// fillFunc fills the write-variables associated with an ARROW column
+// with the irow-th value of that column.
+type fillFunc func(arr array.Interface, irow int) error
+
 type contextWriter struct {
 	wvars []rtree.WriteVar
-	count map[string]rtree.WriteVar
+	count []rtree.WriteVar
+	fills []fillFunc
 }
 
-func newContextWriter(schema *arrow.Schema) contextWriter {
+func newContextWriter(schema *arrow.Schema) (contextWriter, error) {
 	ctx := contextWriter{
-		wvars: make([]rtree.WriteVar, len(schema.Fields())),
-		count: make(map[string]rtree.WriteVar),
+		fills: make([]fillFunc, len(schema.Fields())),
 	}
 	for i, field := range schema.Fields() {
-		ctx.wvars[i] = ctx.writeVarFrom(field)
+		fill, err := ctx.add(field.Name, field.Type)
+		if err != nil {
+			return ctx, fmt.Errorf("could not handle field %q: %w", field.Name, err)
+		}
+		ctx.fills[i] = fill
 	}
-	return ctx
+	return ctx, nil
 }
 
-func (ctx *contextWriter) writeVarFrom(field arrow.Field) rtree.WriteVar {
-	switch dt := field.Type.(type) {
-	case *arrow.BooleanType:
-		return rtree.WriteVar{
-			Name:  field.Name,
-			Value: new(bool),
+// add creates the write-variables associated with the named ARROW data type
+// and returns the function filling them.
+func (ctx *contextWriter) add(name string, dt arrow.DataType) (fillFunc, error) {
+	switch dt := dt.(type) {
+	case *arrow.StructType:
+		fills := make([]fillFunc, len(dt.Fields()))
+		for i, ft := range dt.Fields() {
+			fill, err := ctx.add(name+"."+ft.Name, ft.Type)
+			if err != nil {
+				return nil, err
+			}
+			fills[i] = fill
 		}
+		return func(arr array.Interface, irow int) error {
+			sa := arr.(*array.Struct)
+			for i, fill := range fills {
+				err := fill(sa.Field(i), irow)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}, nil
 
-	case *arrow.Int8Type:
-		return rtree.WriteVar{
-			Name:  field.Name,
-			Value: new(int8),
-		}
+	case *arrow.ListType:
+		return ctx.addList(name, dt)
 
-	case *arrow.Int16Type:
-		return rtree.WriteVar{
-			Name:  field.Name,
-			Value: new(int16),
+	default:
+		rt, err := typeFrom(dt)
+		if err != nil {
+			return nil, err
 		}
+		ptr := reflect.New(rt)
+		ctx.wvars = append(ctx.wvars, rtree.WriteVar{
+			Name:  name,
+			Value: ptr.Interface(),
+		})
+		rv := ptr.Elem()
+		return func(arr array.Interface, irow int) error {
+			return setValue(rv, arr, irow)
+		}, nil
+	}
+}
 
-	case *arrow.Int32Type:
-		return rtree.WriteVar{
-			Name:  field.Name,
-			Value: new(int32),
-		}
+// addList creates the write-variables associated with the named ARROW list
+// and returns the function filling them.
+// Lists of structs are split into one slice per field of the struct.
+func (ctx *contextWriter) addList(name string, dt *arrow.ListType) (fillFunc, error) {
+	var (
+		fields []arrow.Field
+		split  bool
+		count  = "rarrow_n_" + name
+	)
 
-	case *arrow.Int64Type:
-		return rtree.WriteVar{
-			Name:  field.Name,
-			Value: new(int64),
-		}
+	switch elem := dt.Elem().(type) {
+	case *arrow.StructType:
+		fields = elem.Fields()
+		split = true
+	default:
+		fields = []arrow.Field{{Name: name, Type: elem}}
+	}
 
-	case *arrow.Uint8Type:
-		return rtree.WriteVar{
-			Name:  field.Name,
-			Value: new(uint8),
+	slices := make([]reflect.Value, len(fields))
+	for i, ft := range fields {
+		rt, err := typeFrom(ft.Type)
+		if err != nil {
+			return nil, fmt.Errorf("invalid list element: %w", err)
 		}
-
-	case *arrow.Uint16Type:
-		return rtree.WriteVar{
-			Name:  field.Name,
-			Value: new(uint16),
+		bname := ft.Name
+		if split {
+			bname = name + "." + ft.Name
 		}
+		ptr := reflect.New(reflect.SliceOf(rt))
+		ctx.wvars = append(ctx.wvars, rtree.WriteVar{
+			Name:  bname,
+			Value: ptr.Interface(),
+			Count: count,
+		})
+		slices[i] = ptr.Elem()
+	}
 
-	case *arrow.Uint32Type:
-		return rtree.WriteVar{
-			Name:  field.Name,
-			Value: new(uint32),
+	n := new(int32)
+	ctx.count = append(ctx.count, rtree.WriteVar{
+		Name:  count,
+		Value: n,
+	})
+
+	return func(arr array.Interface, irow int) error {
+		la := arr.(*array.List)
+		if !la.IsValid(irow) {
+			*n = 0
+			for _, rv := range slices {
+				rv.SetLen(0)
+			}
+			return nil
 		}
 
-	case *arrow.Uint64Type:
-		return rtree.WriteVar{
-			Name:  field.Name,
-			Value: new(uint64),
-		}
+		var (
+			j   = irow + la.Data().Offset()
+			beg = int(la.Offsets()[j])
+			end = int(la.Offsets()[j+1])
+			sz  = end - beg
+		)
+		*n = int32(sz)
+
+		for i, rv := range slices {
+			if sz > rv.Cap() {
+				rv.Set(reflect.MakeSlice(rv.Type(), sz, sz))
+			}
+			rv.SetLen(sz)
 
-	case *arrow.Float32Type:
-		return rtree.WriteVar{
-			Name:  field.Name,
-			Value: new(float32),
+			elems := la.ListValues()
+			if split {
+				elems = elems.(*array.Struct).Field(i)
+			}
+			for k := 0; k < sz; k++ {
+				err := setValue(rv.Index(k), elems, beg+k)
+				if err != nil {
+					return err
+				}
+			}
 		}
+		return nil
+	}, nil
+}
 
+// typeFrom returns the Go type used to store values of the provided
+// ARROW data type.
+func typeFrom(dt arrow.DataType) (reflect.Type, error) {
+	switch dt := dt.(type) {
+	case *arrow.BooleanType:
+		return reflect.TypeOf(false), nil
+	case *arrow.Int8Type:
+		return reflect.TypeOf(int8(0)), nil
+	case *arrow.Int16Type:
+		return reflect.TypeOf(int16(0)), nil
+	case *arrow.Int32Type:
+		return reflect.TypeOf(int32(0)), nil
+	case *arrow.Int64Type:
+		return reflect.TypeOf(int64(0)), nil
+	case *arrow.Uint8Type:
+		return reflect.TypeOf(uint8(0)), nil
+	case *arrow.Uint16Type:
+		return reflect.TypeOf(uint16(0)), nil
+	case *arrow.Uint32Type:
+		return reflect.TypeOf(uint32(0)), nil
+	case *arrow.Uint64Type:
+		return reflect.TypeOf(uint64(0)), nil
+	case *arrow.Float32Type:
+		return reflect.TypeOf(float32(0)), nil
 	case *arrow.Float64Type:
-		return rtree.WriteVar{
-			Name:  field.Name,
-			Value: new(float64),
-		}
-
+		return reflect.TypeOf(float64(0)), nil
 	case *arrow.StringType:
-		return rtree.WriteVar{
-			Name:  field.Name,
-			Value: new(string),
-		}
+		return reflect.TypeOf(""), nil
 	case *arrow.BinaryType:
 		// FIXME(sbinet): differentiate the 2 (Binary/String) ?
-		return rtree.WriteVar{
-			Name:  field.Name,
-			Value: new(string),
-		}
-
-	case *arrow.FixedSizeListType:
-		wv := ctx.writeVarFrom(arrow.Field{Type: dt.Elem(), Name: "elem"})
-		rt := reflect.ArrayOf(int(dt.Len()), reflect.TypeOf(wv.Value).Elem())
-		return rtree.WriteVar{
-			Name:  field.Name,
-			Value: reflect.New(rt).Interface(),
-		}
-
+		return reflect.TypeOf(""), nil
 	case *arrow.FixedSizeBinaryType:
-		rt := reflect.ArrayOf(dt.ByteWidth, reflect.TypeOf(byte(0)))
-		return rtree.WriteVar{
-			Name:  field.Name,
-			Value: reflect.New(rt).Interface(),
-		}
-
-	case *arrow.ListType:
-		wv := ctx.writeVarFrom(arrow.Field{Type: dt.Elem(), Name: "elem"})
-		rt := reflect.SliceOf(reflect.TypeOf(wv.Value).Elem())
-		nn := "rarrow_n_" + field.Name
-		ctx.count[field.Name] = rtree.WriteVar{
-			Name:  nn,
-			Value: new(int32),
-		}
-		return rtree.WriteVar{
-			Name:  field.Name,
-			Value: reflect.New(rt).Interface(),
-			Count: nn,
+		return reflect.ArrayOf(dt.ByteWidth, reflect.TypeOf(byte(0))), nil
+	case *arrow.FixedSizeListType:
+		elem, err := typeFrom(dt.Elem())
+		if err != nil {
+			return nil, err
 		}
-
-		//	case *arrow.StructType:
-		//		fields := make([]reflect.StructField, len(dt.Fields()))
-		//		for i, ft := range dt.Fields() {
-		//			wv := writeVarFrom(ft)
-		//			fields[i] = reflect.StructField{
-		//				Name: "ROOT_" + ft.Name,
-		//				Type: reflect.TypeOf(wv.Value).Elem(),
-		//				Tag:  reflect.StructTag(fmt.Sprintf("groot:%q", ft.Name)),
-		//			}
-		//		}
-		//		rt := reflect.StructOf(fields)
-		//		return rtree.WriteVar{
-		//			Name:  field.Name,
-		//			Value: reflect.New(rt).Interface(),
-		//		}
-
+		return reflect.ArrayOf(int(dt.Len()), elem), nil
 	default:
-		panic(fmt.Errorf("invalid ARROW data-type: %T", dt))
+		// TODO: write dictionary-encoded strings as std::string once the
+		// ARROW dependency provides dictionary arrays.
+		return nil, fmt.Errorf("unsupported ARROW data type %v (%T)", dt, dt)
 	}
 }
 
-func (ctx *contextWriter) readFrom(wvar *rtree.WriteVar, irow int, arr array.Interface) error {
-	ptr := wvar.Value
+// setValue sets rv with the i-th value of the provided ARROW array.
+func setValue(rv reflect.Value, arr array.Interface, i int) error {
 	switch arr := arr.(type) {
 	case *array.Boolean:
-		*ptr.(*bool) = arr.Value(irow)
+		rv.SetBool(arr.Value(i))
 	case *array.Int8:
-		*ptr.(*int8) = arr.Value(irow)
+		rv.SetInt(int64(arr.Value(i)))
 	case *array.Int16:
-		*ptr.(*int16) = arr.Value(irow)
+		rv.SetInt(int64(arr.Value(i)))
 	case *array.Int32:
-		*ptr.(*int32) = arr.Value(irow)
+		rv.SetInt(int64(arr.Value(i)))
 	case *array.Int64:
-		*ptr.(*int64) = arr.Value(irow)
+		rv.SetInt(arr.Value(i))
 	case *array.Uint8:
-		*ptr.(*uint8) = arr.Value(irow)
+		rv.SetUint(uint64(arr.Value(i)))
 	case *array.Uint16:
-		*ptr.(*uint16) = arr.Value(irow)
+		rv.SetUint(uint64(arr.Value(i)))
 	case *array.Uint32:
-		*ptr.(*uint32) = arr.Value(irow)
+		rv.SetUint(uint64(arr.Value(i)))
 	case *array.Uint64:
-		*ptr.(*uint64) = arr.Value(irow)
+		rv.SetUint(arr.Value(i))
 	case *array.Float32:
-		*ptr.(*float32) = arr.Value(irow)
+		rv.SetFloat(float64(arr.Value(i)))
 	case *array.Float64:
-		*ptr.(*float64) = arr.Value(irow)
+		rv.SetFloat(arr.Value(i))
 	case *array.String:
-		*ptr.(*string) = arr.Value(irow)
+		rv.SetString(arr.Value(i))
 	case *array.Binary:
-		*ptr.(*string) = string(arr.Value(irow))
-
-	case *array.FixedSizeList:
-		rv := reflect.ValueOf(ptr).Elem()
-		n := int64(rv.Len())
-		off := int64(arr.Offset())
-		beg := (off + int64(irow)) * n
-		end := (off + int64(irow+1)) * n
-		ra := array.NewSlice(arr.ListValues(), beg, end)
-		defer ra.Release()
-		ptr := &rtree.WriteVar{
-			Name: "_rarrow_elem_" + wvar.Name,
-		}
-		for i := 0; i < rv.Len(); i++ {
-			ptr.Value = rv.Index(i).Addr().Interface()
-			err := ctx.readFrom(ptr, i, ra)
-			if err != nil {
-				return err
-			}
-		}
+		rv.SetString(string(arr.Value(i)))
 
 	case *array.FixedSizeBinary:
-		rv := reflect.ValueOf(ptr).Elem()
-		sli := rv.Slice(0, rv.Len()).Interface().([]byte)
-		copy(sli, arr.Value(irow))
-
-	case *array.List:
-		rv := reflect.ValueOf(ptr).Elem()
-		rc := reflect.ValueOf(ctx.count[wvar.Name].Value).Elem()
-		if !arr.IsValid(irow) {
-			rc.SetInt(0)
-			rv.SetLen(0)
-			return nil
-		}
+		reflect.Copy(rv, reflect.ValueOf(arr.Value(i)))
 
-		j := irow + arr.Data().Offset()
-		beg := int64(arr.Offsets()[j])
-		end := int64(arr.Offsets()[j+1])
-		sli := array.NewSlice(arr.ListValues(), beg, end)
-		defer sli.Release()
-
-		sz := sli.Len()
-		rc.SetInt(int64(sz))
-
-		if src, dst := sz, rv.Len(); src > dst {
-			rv.Set(reflect.MakeSlice(rv.Type(), src, src))
-		}
-		rv.SetLen(sz)
-
-		ptr := &rtree.WriteVar{
-			Name: "_rarrow_elem_" + wvar.Name,
-		}
-		for i := 0; i < sli.Len(); i++ {
-			ptr.Value = rv.Index(i).Addr().Interface()
-			err := ctx.readFrom(ptr, i, sli)
+	case *array.FixedSizeList:
+		var (
+			n    = rv.Len()
+			beg  = (arr.Offset() + i) * n
+			vals = arr.ListValues()
+		)
+		for j := 0; j < n; j++ {
+			err := setValue(rv.Index(j), vals, beg+j)
 			if err != nil {
 				return err
 			}
 		}
 
 	default:
-		panic(fmt.Errorf("invalid array type %T", arr))
+		return fmt.Errorf("invalid array type %T", arr)
 	}
 	return nil
 }
 
 var (
-	_ arrio.Writer = (*flatTreeWriter)(nil)
+	_ arrio.Writer = (*TreeWriter)(nil)
 )
@@ -0,0 +1,246 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rarrow // import "go-hep.org/x/hep/groot/rarrow"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+	"go-hep.org/x/hep/groot"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/rtree"
+)
+
+type twEvent struct {
+	I32  int32
+	Arr  [3]float64
+	E    float64
+	N    int32
+	Pt   []float32
+	Eta  []float32
+	IDs  []int64
+	Null bool // whether the list of IDs is null
+}
+
+func TestTreeWriter(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rarrow-")
+	if err != nil {
+		t.Fatalf("could not create tmpdir: %+v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	var (
+		fname = filepath.Join(tmp, "tree.root")
+		mem   = memory.NewCheckedAllocator(memory.NewGoAllocator())
+		jets  = arrow.StructOf([]arrow.Field{
+			{Name: "pt", Type: arrow.PrimitiveTypes.Float32},
+			{Name: "eta", Type: arrow.PrimitiveTypes.Float32},
+		}...)
+		schema = arrow.NewSchema([]arrow.Field{
+			{Name: "i32", Type: arrow.PrimitiveTypes.Int32},
+			{Name: "arr", Type: arrow.FixedSizeListOf(3, arrow.PrimitiveTypes.Float64)},
+			{Name: "evt", Type: arrow.StructOf([]arrow.Field{
+				{Name: "e", Type: arrow.PrimitiveTypes.Float64},
+				{Name: "n", Type: arrow.PrimitiveTypes.Int32},
+			}...)},
+			{Name: "jets", Type: arrow.ListOf(jets)},
+			{Name: "ids", Type: arrow.ListOf(arrow.PrimitiveTypes.Int64), Nullable: true},
+		}, nil)
+	)
+	defer mem.AssertSize(t, 0)
+
+	want := []twEvent{
+		{I32: 1, Arr: [3]float64{1, 2, 3}, E: 10, N: 2, Pt: []float32{11, 12}, Eta: []float32{-1, -2}, IDs: []int64{1}},
+		{I32: 2, Arr: [3]float64{4, 5, 6}, E: 20, N: 0, Pt: []float32{}, Eta: []float32{}, IDs: []int64{}, Null: true},
+		{I32: 3, Arr: [3]float64{7, 8, 9}, E: 30, N: 1, Pt: []float32{31}, Eta: []float32{3}, IDs: []int64{2, 3}},
+		{I32: 4, Arr: [3]float64{0, 1, 2}, E: 40, N: 3, Pt: []float32{41, 42, 43}, Eta: []float32{4, 5, 6}, IDs: []int64{4, 5, 6}},
+		{I32: 5, Arr: [3]float64{3, 4, 5}, E: 50, N: 0, Pt: []float32{}, Eta: []float32{}, IDs: []int64{}},
+	}
+
+	newRecord := func(evts []twEvent) array.Record {
+		bldr := array.NewRecordBuilder(mem, schema)
+		defer bldr.Release()
+
+		var (
+			bi32 = bldr.Field(0).(*array.Int32Builder)
+			barr = bldr.Field(1).(*array.FixedSizeListBuilder)
+			bevt = bldr.Field(2).(*array.StructBuilder)
+			bjet = bldr.Field(3).(*array.ListBuilder)
+			bids = bldr.Field(4).(*array.ListBuilder)
+			bjs  = bjet.ValueBuilder().(*array.StructBuilder)
+		)
+
+		for _, evt := range evts {
+			bi32.Append(evt.I32)
+
+			barr.Append(true)
+			barr.ValueBuilder().(*array.Float64Builder).AppendValues(evt.Arr[:], nil)
+
+			bevt.Append(true)
+			bevt.FieldBuilder(0).(*array.Float64Builder).Append(evt.E)
+			bevt.FieldBuilder(1).(*array.Int32Builder).Append(evt.N)
+
+			bjet.Append(true)
+			for i := range evt.Pt {
+				bjs.Append(true)
+				bjs.FieldBuilder(0).(*array.Float32Builder).Append(evt.Pt[i])
+				bjs.FieldBuilder(1).(*array.Float32Builder).Append(evt.Eta[i])
+			}
+
+			if evt.Null {
+				bids.AppendNull()
+				continue
+			}
+			bids.Append(true)
+			bids.ValueBuilder().(*array.Int64Builder).AppendValues(evt.IDs, nil)
+		}
+
+		return bldr.NewRecord()
+	}
+
+	func() {
+		f, err := groot.Create(fname)
+		if err != nil {
+			t.Fatalf("could not create ROOT file: %+v", err)
+		}
+		defer f.Close()
+
+		w, err := NewTreeWriter(f, "tree", schema, rtree.WithTitle("arrow tree"))
+		if err != nil {
+			t.Fatalf("could not create tree writer: %+v", err)
+		}
+		defer w.Close()
+
+		var recs []array.Record
+		for _, evts := range [][]twEvent{want[:2], want[2:2], want[2:]} {
+			rec := newRecord(evts)
+			defer rec.Release()
+			recs = append(recs, rec)
+		}
+
+		itr, err := array.NewRecordReader(schema, recs)
+		if err != nil {
+			t.Fatalf("could not create record reader: %+v", err)
+		}
+		defer itr.Release()
+
+		n, err := w.WriteRecords(itr)
+		if err != nil {
+			t.Fatalf("could not write records: %+v", err)
+		}
+		if got, want := n, int64(len(want)); got != want {
+			t.Fatalf("invalid number of written entries: got=%d, want=%d", got, want)
+		}
+
+		err = w.Close()
+		if err != nil {
+			t.Fatalf("could not close tree writer: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close ROOT file: %+v", err)
+		}
+	}()
+
+	f, err := groot.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open ROOT file: %+v", err)
+	}
+	defer f.Close()
+
+	o, err := riofs.Dir(f).Get("tree")
+	if err != nil {
+		t.Fatalf("could not get tree: %+v", err)
+	}
+	tree := o.(rtree.Tree)
+
+	if got, want := tree.Entries(), int64(len(want)); got != want {
+		t.Fatalf("invalid number of entries: got=%d, want=%d", got, want)
+	}
+
+	var names []string
+	for _, b := range tree.Branches() {
+		names = append(names, b.Name())
+	}
+	if got, want := strings.Join(names, " "), "rarrow_n_jets rarrow_n_ids i32 arr evt.e evt.n jets.pt jets.eta ids"; got != want {
+		t.Fatalf("invalid branches:\ngot= %s\nwant=%s", got, want)
+	}
+
+	var (
+		evt   twEvent
+		njets int32
+		nids  int32
+	)
+	rvars := []rtree.ReadVar{
+		{Name: "rarrow_n_jets", Value: &njets},
+		{Name: "rarrow_n_ids", Value: &nids},
+		{Name: "i32", Value: &evt.I32},
+		{Name: "arr", Value: &evt.Arr},
+		{Name: "evt.e", Value: &evt.E},
+		{Name: "evt.n", Value: &evt.N},
+		{Name: "jets.pt", Value: &evt.Pt},
+		{Name: "jets.eta", Value: &evt.Eta},
+		{Name: "ids", Value: &evt.IDs},
+	}
+	r, err := rtree.NewReader(tree, rvars)
+	if err != nil {
+		t.Fatalf("could not create tree reader: %+v", err)
+	}
+	defer r.Close()
+
+	err = r.Read(func(ctx rtree.RCtx) error {
+		want := want[ctx.Entry]
+		want.Null = false
+		if got, want := int(njets), len(want.Pt); got != want {
+			t.Fatalf("entry %d: invalid jets count: got=%d, want=%d", ctx.Entry, got, want)
+		}
+		if got, want := int(nids), len(want.IDs); got != want {
+			t.Fatalf("entry %d: invalid ids count: got=%d, want=%d", ctx.Entry, got, want)
+		}
+		if !reflect.DeepEqual(evt, want) {
+			t.Fatalf("entry %d: invalid event:\ngot= %#v\nwant=%#v", ctx.Entry, evt, want)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not read tree: %+v", err)
+	}
+}
+
+func TestTreeWriterInvalidType(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rarrow-")
+	if err != nil {
+		t.Fatalf("could not create tmpdir: %+v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	f, err := groot.Create(filepath.Join(tmp, "tree.root"))
+	if err != nil {
+		t.Fatalf("could not create ROOT file: %+v", err)
+	}
+	defer f.Close()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "lists", Type: arrow.ListOf(arrow.ListOf(arrow.PrimitiveTypes.Int32))},
+	}, nil)
+
+	_, err = NewTreeWriter(f, "tree", schema)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	const want = `rarrow: could not create tree writer "tree": could not handle field "lists": invalid list element: unsupported ARROW data type list<item: int32> (*arrow.ListType)`
+	if got := err.Error(); got != want {
+		t.Fatalf("invalid error:\ngot= %s\nwant=%s", got, want)
+	}
+}
@@ -50,6 +50,10 @@ func (n *Named) Title() string {
 func (n *Named) SetName(name string)   { n.name = name }
 func (n *Named) SetTitle(title string) { n.title = title }
 
+func (n *Named) SetBit(bit uint32)         { n.obj.SetBit(bit) }
+func (n *Named) ResetBit(bit uint32)       { n.obj.ResetBit(bit) }
+func (n *Named) TestBits(bits uint32) bool { return n.obj.TestBits(bits) }
+
 func (*Named) Class() string {
 	return "TNamed"
 }
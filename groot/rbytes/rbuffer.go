@@ -87,6 +87,12 @@ func (r *RBuffer) StreamerInfo(name string, version int) (StreamerInfo, error) {
 	return r.sictx.StreamerInfo(name, version)
 }
 
+// StreamerInfoContext returns the StreamerInfoContext the buffer was
+// created with.
+func (r *RBuffer) StreamerInfoContext() StreamerInfoContext {
+	return r.sictx
+}
+
 func (r *RBuffer) Pos() int64 {
 	return int64(r.r.c) + int64(r.offset)
 }
@@ -465,8 +471,16 @@ func (r *RBuffer) ReadVersion(class string) (vers int16, pos, n int32) {
 			si, err := r.sictx.StreamerInfo(class, -1)
 			if err == nil && si.ClassVersion() != int(vers) {
 				chksum := r.ReadU32()
-				if si.CheckSum() == int(chksum) {
+				switch {
+				case si.CheckSum() == int(chksum):
 					vers = int16(si.ClassVersion())
+				default:
+					if ctx, ok := r.sictx.(StreamerInfoSumContext); ok {
+						si, err := ctx.StreamerInfoBySum(class, int(chksum))
+						if err == nil {
+							vers = int16(si.ClassVersion())
+						}
+					}
 				}
 			}
 		}
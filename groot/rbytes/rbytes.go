@@ -54,6 +54,18 @@ type StreamerInfoContext interface {
 	StreamerInfo(name string, version int) (StreamerInfo, error)
 }
 
+// StreamerInfoSumContext is a StreamerInfoContext that can also retrieve
+// a ROOT StreamerInfo by its checksum.
+//
+// StreamerInfoSumContext is used to select the StreamerInfo of a
+// (foreign) class when its version is not written to the ROOT buffer.
+type StreamerInfoSumContext interface {
+	StreamerInfoContext
+
+	// StreamerInfoBySum returns the named StreamerInfo with the provided checksum.
+	StreamerInfoBySum(name string, chksum int) (StreamerInfo, error)
+}
+
 // Unmarshaler is the interface implemented by an object that can
 // unmarshal itself from a ROOT buffer
 type Unmarshaler interface {
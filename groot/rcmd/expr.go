@@ -0,0 +1,470 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rcmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"go-hep.org/x/hep/groot/rtree"
+)
+
+// selection is a boolean expression over the branches of a tree.
+//
+// Selections use the Go syntax for expressions, where identifiers are
+// names of branches (possibly of the form "alias.branch"), e.g.:
+//
+//  pt > 20 && abs(eta) < 2.5
+//  len(jets_pt) >= 2 && jets_pt[0] > 50
+//
+// The supported functions are abs, sqrt, exp, log, pow, min, max and len.
+type selection struct {
+	src   string
+	expr  ast.Expr
+	names []string // names of the branches needed to evaluate the selection
+}
+
+func newSelection(src string) (*selection, error) {
+	expr, err := parser.ParseExpr(src)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse selection %q: %w", src, err)
+	}
+
+	sel := &selection{src: src, expr: expr}
+	set := make(map[string]struct{})
+	ast.Inspect(expr, func(n ast.Node) bool {
+		return sel.collect(set, n)
+	})
+
+	sel.names = make([]string, 0, len(set))
+	for name := range set {
+		sel.names = append(sel.names, name)
+	}
+	sort.Strings(sel.names)
+
+	return sel, nil
+}
+
+func (sel *selection) collect(set map[string]struct{}, n ast.Node) bool {
+	switch n := n.(type) {
+	case *ast.CallExpr:
+		// function names are not branch names.
+		for _, arg := range n.Args {
+			ast.Inspect(arg, func(n ast.Node) bool {
+				return sel.collect(set, n)
+			})
+		}
+		return false
+	case *ast.Ident:
+		switch n.Name {
+		case "true", "false":
+		default:
+			set[n.Name] = struct{}{}
+		}
+		return false
+	case *ast.SelectorExpr:
+		if name, ok := selectorName(n); ok {
+			set[name] = struct{}{}
+			return false
+		}
+	}
+	return true
+}
+
+// vars returns the read-variables needed to evaluate the selection on
+// the provided tree.
+func (sel *selection) vars(t rtree.Tree) ([]rtree.ReadVar, error) {
+	all := rtree.NewReadVars(t)
+	rvars := make([]rtree.ReadVar, 0, len(sel.names))
+	for _, name := range sel.names {
+		i := indexOfVar(all, name)
+		if i < 0 {
+			return nil, fmt.Errorf("unknown branch %q in selection %q", name, sel.src)
+		}
+		rvars = append(rvars, all[i])
+	}
+	return rvars, nil
+}
+
+// compile compiles the selection into a function evaluating the selection
+// with the values bound to the provided read-variables.
+func (sel *selection) compile(rvars []rtree.ReadVar) (func() bool, error) {
+	vals := make(map[string]reflect.Value, len(rvars))
+	for _, rvar := range rvars {
+		vals[rvar.Name] = reflect.ValueOf(rvar.Value).Elem()
+	}
+
+	c := exprCompiler{vals: vals}
+	v, err := c.compile(sel.expr)
+	if err != nil {
+		return nil, fmt.Errorf("could not compile selection %q: %w", sel.src, err)
+	}
+	if v.kind != boolExpr {
+		return nil, fmt.Errorf("selection %q is not a boolean expression", sel.src)
+	}
+	return v.b, nil
+}
+
+// indexOfVar returns the index of the read-variable with the provided name,
+// or -1 if there is none.
+func indexOfVar(rvars []rtree.ReadVar, name string) int {
+	for i, rvar := range rvars {
+		if rvar.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// selectorName returns the dotted name of a selector expression made
+// only of identifiers.
+func selectorName(expr *ast.SelectorExpr) (string, bool) {
+	switch x := expr.X.(type) {
+	case *ast.Ident:
+		return x.Name + "." + expr.Sel.Name, true
+	case *ast.SelectorExpr:
+		name, ok := selectorName(x)
+		if !ok {
+			return "", false
+		}
+		return name + "." + expr.Sel.Name, true
+	}
+	return "", false
+}
+
+type exprKind int
+
+const (
+	numExpr exprKind = iota
+	boolExpr
+	arrayExpr
+)
+
+// exprValue is a compiled expression.
+type exprValue struct {
+	kind exprKind
+	f    func() float64       // value of a numerical expression
+	b    func() bool          // value of a boolean expression
+	a    func() reflect.Value // value of an array expression
+}
+
+type exprCompiler struct {
+	vals map[string]reflect.Value
+}
+
+func (c *exprCompiler) compile(expr ast.Expr) (exprValue, error) {
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return c.compile(expr.X)
+
+	case *ast.BasicLit:
+		switch expr.Kind {
+		case token.INT, token.FLOAT:
+			v, err := strconv.ParseFloat(expr.Value, 64)
+			if err != nil {
+				return exprValue{}, fmt.Errorf("invalid number %q: %w", expr.Value, err)
+			}
+			return exprValue{kind: numExpr, f: func() float64 { return v }}, nil
+		}
+		return exprValue{}, fmt.Errorf("invalid literal %q", expr.Value)
+
+	case *ast.Ident:
+		switch expr.Name {
+		case "true":
+			return exprValue{kind: boolExpr, b: func() bool { return true }}, nil
+		case "false":
+			return exprValue{kind: boolExpr, b: func() bool { return false }}, nil
+		}
+		return c.variable(expr.Name)
+
+	case *ast.SelectorExpr:
+		name, ok := selectorName(expr)
+		if !ok {
+			return exprValue{}, fmt.Errorf("invalid selector expression")
+		}
+		return c.variable(name)
+
+	case *ast.IndexExpr:
+		return c.index(expr)
+
+	case *ast.UnaryExpr:
+		return c.unary(expr)
+
+	case *ast.BinaryExpr:
+		return c.binary(expr)
+
+	case *ast.CallExpr:
+		return c.call(expr)
+	}
+
+	return exprValue{}, fmt.Errorf("unsupported expression of type %T", expr)
+}
+
+func (c *exprCompiler) variable(name string) (exprValue, error) {
+	rv, ok := c.vals[name]
+	if !ok {
+		return exprValue{}, fmt.Errorf("unknown branch %q", name)
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return exprValue{kind: boolExpr, b: rv.Bool}, nil
+	case reflect.Array, reflect.Slice:
+		if _, err := toFloat(rv.Type().Elem()); err != nil {
+			return exprValue{}, fmt.Errorf("invalid element type for branch %q: %w", name, err)
+		}
+		return exprValue{kind: arrayExpr, a: func() reflect.Value { return rv }}, nil
+	}
+
+	f, err := toFloat(rv.Type())
+	if err != nil {
+		return exprValue{}, fmt.Errorf("invalid type for branch %q: %w", name, err)
+	}
+	return exprValue{kind: numExpr, f: func() float64 { return f(rv) }}, nil
+}
+
+func (c *exprCompiler) index(expr *ast.IndexExpr) (exprValue, error) {
+	x, err := c.compile(expr.X)
+	if err != nil {
+		return x, err
+	}
+	if x.kind != arrayExpr {
+		return exprValue{}, fmt.Errorf("invalid index of non-array expression")
+	}
+
+	i, err := c.number(expr.Index)
+	if err != nil {
+		return exprValue{}, err
+	}
+
+	rv := x.a()
+	f, err := toFloat(rv.Type().Elem())
+	if err != nil {
+		return exprValue{}, err
+	}
+
+	return exprValue{kind: numExpr, f: func() float64 {
+		var (
+			rv = x.a()
+			j  = int(i())
+		)
+		if j < 0 || j >= rv.Len() {
+			return math.NaN()
+		}
+		return f(rv.Index(j))
+	}}, nil
+}
+
+func (c *exprCompiler) unary(expr *ast.UnaryExpr) (exprValue, error) {
+	switch expr.Op {
+	case token.NOT:
+		x, err := c.boolean(expr.X)
+		if err != nil {
+			return exprValue{}, err
+		}
+		return exprValue{kind: boolExpr, b: func() bool { return !x() }}, nil
+	case token.SUB:
+		x, err := c.number(expr.X)
+		if err != nil {
+			return exprValue{}, err
+		}
+		return exprValue{kind: numExpr, f: func() float64 { return -x() }}, nil
+	case token.ADD:
+		x, err := c.number(expr.X)
+		if err != nil {
+			return exprValue{}, err
+		}
+		return exprValue{kind: numExpr, f: x}, nil
+	}
+	return exprValue{}, fmt.Errorf("unsupported unary operator %q", expr.Op)
+}
+
+func (c *exprCompiler) binary(expr *ast.BinaryExpr) (exprValue, error) {
+	switch expr.Op {
+	case token.LAND, token.LOR:
+		x, err := c.boolean(expr.X)
+		if err != nil {
+			return exprValue{}, err
+		}
+		y, err := c.boolean(expr.Y)
+		if err != nil {
+			return exprValue{}, err
+		}
+		if expr.Op == token.LAND {
+			return exprValue{kind: boolExpr, b: func() bool { return x() && y() }}, nil
+		}
+		return exprValue{kind: boolExpr, b: func() bool { return x() || y() }}, nil
+	}
+
+	x, err := c.compile(expr.X)
+	if err != nil {
+		return exprValue{}, err
+	}
+	y, err := c.compile(expr.Y)
+	if err != nil {
+		return exprValue{}, err
+	}
+
+	if x.kind == boolExpr && y.kind == boolExpr {
+		switch expr.Op {
+		case token.EQL:
+			return exprValue{kind: boolExpr, b: func() bool { return x.b() == y.b() }}, nil
+		case token.NEQ:
+			return exprValue{kind: boolExpr, b: func() bool { return x.b() != y.b() }}, nil
+		}
+		return exprValue{}, fmt.Errorf("invalid operator %q for boolean operands", expr.Op)
+	}
+
+	if x.kind != numExpr || y.kind != numExpr {
+		return exprValue{}, fmt.Errorf("invalid operands for operator %q", expr.Op)
+	}
+
+	var (
+		fx = x.f
+		fy = y.f
+	)
+
+	switch expr.Op {
+	case token.ADD:
+		return exprValue{kind: numExpr, f: func() float64 { return fx() + fy() }}, nil
+	case token.SUB:
+		return exprValue{kind: numExpr, f: func() float64 { return fx() - fy() }}, nil
+	case token.MUL:
+		return exprValue{kind: numExpr, f: func() float64 { return fx() * fy() }}, nil
+	case token.QUO:
+		return exprValue{kind: numExpr, f: func() float64 { return fx() / fy() }}, nil
+	case token.REM:
+		return exprValue{kind: numExpr, f: func() float64 { return math.Mod(fx(), fy()) }}, nil
+	case token.EQL:
+		return exprValue{kind: boolExpr, b: func() bool { return fx() == fy() }}, nil
+	case token.NEQ:
+		return exprValue{kind: boolExpr, b: func() bool { return fx() != fy() }}, nil
+	case token.LSS:
+		return exprValue{kind: boolExpr, b: func() bool { return fx() < fy() }}, nil
+	case token.LEQ:
+		return exprValue{kind: boolExpr, b: func() bool { return fx() <= fy() }}, nil
+	case token.GTR:
+		return exprValue{kind: boolExpr, b: func() bool { return fx() > fy() }}, nil
+	case token.GEQ:
+		return exprValue{kind: boolExpr, b: func() bool { return fx() >= fy() }}, nil
+	}
+
+	return exprValue{}, fmt.Errorf("unsupported binary operator %q", expr.Op)
+}
+
+func (c *exprCompiler) call(expr *ast.CallExpr) (exprValue, error) {
+	fct, ok := expr.Fun.(*ast.Ident)
+	if !ok {
+		return exprValue{}, fmt.Errorf("invalid function call")
+	}
+
+	nargs := func(n int) error {
+		if len(expr.Args) != n {
+			return fmt.Errorf("invalid number of arguments to %s (got=%d, want=%d)", fct.Name, len(expr.Args), n)
+		}
+		return nil
+	}
+
+	switch fct.Name {
+	case "len":
+		if err := nargs(1); err != nil {
+			return exprValue{}, err
+		}
+		x, err := c.compile(expr.Args[0])
+		if err != nil {
+			return exprValue{}, err
+		}
+		if x.kind != arrayExpr {
+			return exprValue{}, fmt.Errorf("invalid argument to len: not an array")
+		}
+		return exprValue{kind: numExpr, f: func() float64 { return float64(x.a().Len()) }}, nil
+
+	case "abs", "sqrt", "exp", "log":
+		if err := nargs(1); err != nil {
+			return exprValue{}, err
+		}
+		x, err := c.number(expr.Args[0])
+		if err != nil {
+			return exprValue{}, err
+		}
+		f := map[string]func(float64) float64{
+			"abs":  math.Abs,
+			"sqrt": math.Sqrt,
+			"exp":  math.Exp,
+			"log":  math.Log,
+		}[fct.Name]
+		return exprValue{kind: numExpr, f: func() float64 { return f(x()) }}, nil
+
+	case "pow", "min", "max":
+		if err := nargs(2); err != nil {
+			return exprValue{}, err
+		}
+		x, err := c.number(expr.Args[0])
+		if err != nil {
+			return exprValue{}, err
+		}
+		y, err := c.number(expr.Args[1])
+		if err != nil {
+			return exprValue{}, err
+		}
+		f := map[string]func(x, y float64) float64{
+			"pow": math.Pow,
+			"min": math.Min,
+			"max": math.Max,
+		}[fct.Name]
+		return exprValue{kind: numExpr, f: func() float64 { return f(x(), y()) }}, nil
+	}
+
+	return exprValue{}, fmt.Errorf("unknown function %q", fct.Name)
+}
+
+func (c *exprCompiler) number(expr ast.Expr) (func() float64, error) {
+	v, err := c.compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	if v.kind != numExpr {
+		return nil, fmt.Errorf("invalid non-numerical expression")
+	}
+	return v.f, nil
+}
+
+func (c *exprCompiler) boolean(expr ast.Expr) (func() bool, error) {
+	v, err := c.compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	if v.kind != boolExpr {
+		return nil, fmt.Errorf("invalid non-boolean expression")
+	}
+	return v.b, nil
+}
+
+// toFloat returns a function converting values of the provided type
+// to float64.
+func toFloat(rt reflect.Type) (func(rv reflect.Value) float64, error) {
+	switch rt.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(rv reflect.Value) float64 { return float64(rv.Int()) }, nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(rv reflect.Value) float64 { return float64(rv.Uint()) }, nil
+	case reflect.Float32, reflect.Float64:
+		return func(rv reflect.Value) float64 { return rv.Float() }, nil
+	case reflect.Bool:
+		return func(rv reflect.Value) float64 {
+			if rv.Bool() {
+				return 1
+			}
+			return 0
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %v", rt)
+}
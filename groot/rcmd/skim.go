@@ -0,0 +1,396 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rcmd
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"runtime"
+	"sync"
+
+	"go-hep.org/x/hep/groot"
+	"go-hep.org/x/hep/groot/internal/rcompress"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/rtree"
+)
+
+// Skim writes the entries of the tree tname, read from the input files,
+// that pass the selection expression sel into the output file oname.
+//
+// Only the branches matching at least one of the keep glob patterns (all
+// the branches if keep is empty) and none of the drop glob patterns are
+// written out. Count branches of kept slice branches are always kept.
+// An empty selection expression selects all the entries.
+//
+// The selection is evaluated concurrently over the input files, using
+// nworkers goroutines (runtime.NumCPU() if nworkers <= 0).
+// The output file uses the compression settings of the first input file.
+//
+// Skim returns the number of entries written to the output tree.
+func Skim(oname, tname string, fnames []string, sel string, keep, drop []string, nworkers int, verbose bool) (int64, error) {
+	if len(fnames) == 0 {
+		return 0, fmt.Errorf("no input files")
+	}
+
+	for _, patterns := range [][]string{keep, drop} {
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return 0, fmt.Errorf("invalid branch pattern %q: %w", p, err)
+			}
+		}
+	}
+
+	cmd := skimCmd{
+		tname:   tname,
+		keep:    keep,
+		drop:    drop,
+		verbose: verbose,
+	}
+
+	if sel != "" {
+		var err error
+		cmd.sel, err = newSelection(sel)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if nworkers <= 0 {
+		nworkers = runtime.NumCPU()
+	}
+
+	entries, err := cmd.selectAll(fnames, nworkers)
+	if err != nil {
+		return 0, err
+	}
+
+	return cmd.write(oname, fnames, entries)
+}
+
+type skimCmd struct {
+	tname   string
+	sel     *selection
+	keep    []string
+	drop    []string
+	verbose bool
+}
+
+// openTree opens the input file and retrieves the tree to skim.
+func (cmd skimCmd) openTree(fname string) (*riofs.File, rtree.Tree, error) {
+	f, err := groot.Open(fname)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not open input file %q: %w", fname, err)
+	}
+
+	o, err := riofs.Dir(f).Get(cmd.tname)
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("could not get tree %q from %q: %w", cmd.tname, fname, err)
+	}
+
+	tree, ok := o.(rtree.Tree)
+	if !ok {
+		f.Close()
+		return nil, nil, fmt.Errorf("object %q from %q is not a Tree", cmd.tname, fname)
+	}
+
+	return f, tree, nil
+}
+
+// selectAll evaluates the selection over all the input files, using
+// nworkers concurrent workers.
+// selectAll returns the selected entries of each input file, or nil if
+// there is no selection to apply.
+func (cmd skimCmd) selectAll(fnames []string, nworkers int) ([][]int64, error) {
+	if cmd.sel == nil {
+		return nil, nil
+	}
+
+	var (
+		wg      sync.WaitGroup
+		entries = make([][]int64, len(fnames))
+		errs    = make([]error, len(fnames))
+		jobs    = make(chan int)
+	)
+
+	if nworkers > len(fnames) {
+		nworkers = len(fnames)
+	}
+
+	wg.Add(nworkers)
+	for i := 0; i < nworkers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				entries[j], errs[j] = cmd.selectFrom(fnames[j])
+			}
+		}()
+	}
+
+	for i := range fnames {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("could not apply selection to %q: %w", fnames[i], err)
+		}
+	}
+
+	return entries, nil
+}
+
+// selectFrom returns the entries of the input file passing the selection.
+func (cmd skimCmd) selectFrom(fname string) ([]int64, error) {
+	f, tree, err := cmd.openTree(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rvars, err := cmd.sel.vars(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	pass, err := cmd.sel.compile(rvars)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := rtree.NewReader(tree, rvars)
+	if err != nil {
+		return nil, fmt.Errorf("could not create reader: %w", err)
+	}
+	defer r.Close()
+
+	var entries []int64
+	err = r.Read(func(ctx rtree.RCtx) error {
+		if pass() {
+			entries = append(entries, ctx.Entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not evaluate selection: %w", err)
+	}
+
+	if cmd.verbose {
+		log.Printf("selected %d/%d entries from %q", len(entries), tree.Entries(), fname)
+	}
+
+	return entries, nil
+}
+
+// wvars returns the write-variables of the branches to keep.
+func (cmd skimCmd) wvars(tree rtree.Tree) ([]rtree.WriteVar, error) {
+	var (
+		all  = rtree.WriteVarsFromTree(tree)
+		kept = make(map[string]bool, len(all))
+	)
+
+	match := func(name string, patterns []string) bool {
+		for _, p := range patterns {
+			if ok, _ := path.Match(p, name); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, wvar := range all {
+		if len(cmd.keep) > 0 && !match(wvar.Name, cmd.keep) {
+			continue
+		}
+		if match(wvar.Name, cmd.drop) {
+			continue
+		}
+		kept[wvar.Name] = true
+	}
+
+	// slices need their count branch.
+	for _, wvar := range all {
+		if kept[wvar.Name] && wvar.Count != "" {
+			kept[wvar.Count] = true
+		}
+	}
+
+	wvars := make([]rtree.WriteVar, 0, len(kept))
+	for _, wvar := range all {
+		if kept[wvar.Name] {
+			wvars = append(wvars, wvar)
+		}
+	}
+
+	if len(wvars) == 0 {
+		return nil, fmt.Errorf("no branch left to write out")
+	}
+
+	return wvars, nil
+}
+
+// write writes the selected entries of all the input files to the output
+// file.
+func (cmd skimCmd) write(oname string, fnames []string, entries [][]int64) (int64, error) {
+	f, tree, err := cmd.openTree(fnames[0])
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	wvars, err := cmd.wvars(tree)
+	if err != nil {
+		return 0, err
+	}
+
+	o, err := groot.Create(oname, compressionOf(f))
+	if err != nil {
+		return 0, fmt.Errorf("could not create output file: %w", err)
+	}
+	defer o.Close()
+
+	var (
+		dirName = path.Dir(cmd.tname)
+		objName = path.Base(cmd.tname)
+		dir     = riofs.Directory(o)
+	)
+	if dirName != "/" && dirName != "" && dirName != "." {
+		_, err = riofs.Dir(o).Mkdir(dirName)
+		if err != nil {
+			return 0, fmt.Errorf("could not create output directory %q: %w", dirName, err)
+		}
+		odir, err := riofs.Dir(o).Get(dirName)
+		if err != nil {
+			return 0, fmt.Errorf("could not fetch output directory %q: %w", dirName, err)
+		}
+		dir = odir.(riofs.Directory)
+	}
+
+	w, err := rtree.NewWriter(
+		dir, objName,
+		wvars,
+		rtree.WithTitle(tree.Title()),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("could not create tree writer: %w", err)
+	}
+	defer w.Close()
+
+	rvars := make([]rtree.ReadVar, len(wvars))
+	for i, wvar := range wvars {
+		rvars[i] = rtree.ReadVar{
+			Name:  wvar.Name,
+			Value: wvar.Value,
+		}
+	}
+
+	var tot int64
+	for i, fname := range fnames {
+		var sel []int64
+		if entries != nil {
+			sel = entries[i]
+			if len(sel) == 0 {
+				continue
+			}
+		}
+
+		n, err := cmd.copy(w, fname, rvars, sel, entries != nil)
+		tot += n
+		if err != nil {
+			return tot, fmt.Errorf("could not skim %q: %w", fname, err)
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		return tot, fmt.Errorf("could not close tree writer: %w", err)
+	}
+
+	err = o.Close()
+	if err != nil {
+		return tot, fmt.Errorf("could not close output file: %w", err)
+	}
+
+	return tot, nil
+}
+
+// copy copies the selected entries of the input file to the output tree.
+// All the entries are copied when there is no selection.
+func (cmd skimCmd) copy(w rtree.Writer, fname string, rvars []rtree.ReadVar, entries []int64, selected bool) (int64, error) {
+	f, tree, err := cmd.openTree(fname)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var opts []rtree.ReadOption
+	if selected {
+		elist := rtree.NewEntryList("", "", tree)
+		for _, entry := range entries {
+			err := elist.Enter(entry)
+			if err != nil {
+				return 0, fmt.Errorf("could not select entry %d: %w", entry, err)
+			}
+		}
+		opts = append(opts, rtree.WithEntryList(elist))
+	}
+
+	r, err := rtree.NewReader(tree, rvars, opts...)
+	if err != nil {
+		return 0, fmt.Errorf("could not create reader: %w", err)
+	}
+	defer r.Close()
+
+	if cmd.verbose {
+		log.Printf("skimming %q...", fname)
+	}
+
+	var tot int64
+	err = r.Read(func(ctx rtree.RCtx) error {
+		_, err := w.Write()
+		if err != nil {
+			return fmt.Errorf("could not write entry %d: %w", ctx.Entry, err)
+		}
+		tot++
+		return nil
+	})
+	if err != nil {
+		return tot, err
+	}
+
+	if cmd.verbose {
+		log.Printf("skimming %q... [ok] (%d entries)", fname, tot)
+	}
+
+	return tot, nil
+}
+
+// compressionOf returns the file option reproducing the compression
+// settings of the provided file.
+func compressionOf(f *riofs.File) riofs.FileOption {
+	var (
+		v   = f.Compression()
+		alg = rcompress.Kind(v / 100)
+		lvl = int(v % 100)
+	)
+
+	if lvl == 0 {
+		return riofs.WithoutCompression()
+	}
+
+	switch alg {
+	case rcompress.LZ4:
+		return riofs.WithLZ4(lvl)
+	case rcompress.LZMA:
+		return riofs.WithLZMA(lvl)
+	case rcompress.ZSTD:
+		return riofs.WithZstd(lvl)
+	default:
+		return riofs.WithZlib(lvl)
+	}
+}
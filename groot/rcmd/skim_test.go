@@ -0,0 +1,265 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rcmd_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-hep.org/x/hep/groot"
+	"go-hep.org/x/hep/groot/rcmd"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/rtree"
+)
+
+func TestSkim(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-root-skim-")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	fnames := []string{
+		filepath.Join(tmp, "in-1.root"),
+		filepath.Join(tmp, "in-2.root"),
+		filepath.Join(tmp, "in-3.root"),
+	}
+	for i, fname := range fnames {
+		_ = makeSplitFlatTree(10*i, 10*(i+1))(t, fname)
+	}
+
+	const tname = "dir-1/dir-11/mytree"
+
+	var compr int32
+	{
+		f, err := groot.Open(fnames[0])
+		if err != nil {
+			t.Fatalf("could not open input file: %+v", err)
+		}
+		compr = f.Compression()
+		f.Close()
+	}
+
+	for _, tc := range []struct {
+		name     string
+		sel      string
+		keep     []string
+		drop     []string
+		branches []string
+		entries  []int32
+	}{
+		{
+			name:     "all",
+			branches: []string{"I32", "F64", "Str", "ArrF64", "N", "SliF64"},
+			entries:  seq(0, 30),
+		},
+		{
+			name:     "sel",
+			sel:      "I32 >= 8 && (F64 < 12 || I32 == 25)",
+			branches: []string{"I32", "F64", "Str", "ArrF64", "N", "SliF64"},
+			entries:  []int32{8, 9, 10, 11, 25},
+		},
+		{
+			name:     "sel-arrays",
+			sel:      "len(SliF64) >= 3 && SliF64[2] >= 20 && ArrF64[4] < 25",
+			branches: []string{"I32", "F64", "Str", "ArrF64", "N", "SliF64"},
+			entries:  []int32{18, 19},
+		},
+		{
+			name:     "sel-funcs",
+			sel:      "abs(-F64) < sqrt(9) || max(F64, 28) == F64",
+			branches: []string{"I32", "F64", "Str", "ArrF64", "N", "SliF64"},
+			entries:  []int32{0, 1, 2, 28, 29},
+		},
+		{
+			name:     "keep",
+			keep:     []string{"I32", "Sli*"},
+			branches: []string{"I32", "N", "SliF64"},
+			entries:  seq(0, 30),
+		},
+		{
+			name:     "drop",
+			sel:      "N == 4",
+			drop:     []string{"*F64", "Str"},
+			branches: []string{"I32", "N"},
+			entries:  []int32{4, 9, 14, 19, 24, 29},
+		},
+		{
+			name:     "keep-drop",
+			sel:      "I32 > 27",
+			keep:     []string{"*F64"},
+			drop:     []string{"Arr*"},
+			branches: []string{"F64", "N", "SliF64"},
+			entries:  []int32{28, 29},
+		},
+		{
+			name:     "empty",
+			sel:      "I32 < 0",
+			branches: []string{"I32", "F64", "Str", "ArrF64", "N", "SliF64"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			oname := filepath.Join(tmp, tc.name+".root")
+			n, err := rcmd.Skim(oname, tname, fnames, tc.sel, tc.keep, tc.drop, 2, false)
+			if err != nil {
+				t.Fatalf("could not skim: %+v", err)
+			}
+
+			if got, want := n, int64(len(tc.entries)); got != want {
+				t.Fatalf("invalid number of skimmed entries: got=%d, want=%d", got, want)
+			}
+
+			f, err := groot.Open(oname)
+			if err != nil {
+				t.Fatalf("could not open output file: %+v", err)
+			}
+			defer f.Close()
+
+			if got, want := f.Compression(), compr; got != want {
+				t.Fatalf("invalid output compression: got=%d, want=%d", got, want)
+			}
+
+			o, err := riofs.Dir(f).Get(tname)
+			if err != nil {
+				t.Fatalf("could not get output tree: %+v", err)
+			}
+			tree := o.(rtree.Tree)
+
+			var branches []string
+			for _, b := range tree.Branches() {
+				branches = append(branches, b.Name())
+			}
+			if !reflect.DeepEqual(branches, tc.branches) {
+				t.Fatalf("invalid branches:\ngot= %q\nwant=%q", branches, tc.branches)
+			}
+
+			if got, want := tree.Entries(), int64(len(tc.entries)); got != want {
+				t.Fatalf("invalid number of entries: got=%d, want=%d", got, want)
+			}
+			if len(tc.entries) == 0 {
+				return
+			}
+
+			rvars := rtree.NewReadVars(tree)
+			r, err := rtree.NewReader(tree, rvars)
+			if err != nil {
+				t.Fatalf("could not create reader: %+v", err)
+			}
+			defer r.Close()
+
+			err = r.Read(func(ctx rtree.RCtx) error {
+				i := tc.entries[ctx.Entry]
+				for _, rvar := range rvars {
+					var (
+						got  = reflect.ValueOf(rvar.Value).Elem().Interface()
+						want interface{}
+					)
+					switch rvar.Name {
+					case "I32":
+						want = i
+					case "F64":
+						want = float64(i)
+					case "Str":
+						want = fmt.Sprintf("evt-%0d", i)
+					case "N":
+						want = i % 5
+					case "SliF64":
+						want = []float64{float64(i), float64(i + 1), float64(i + 2), float64(i + 3), float64(i + 4)}[:i%5]
+					default:
+						return nil
+					}
+					if !reflect.DeepEqual(got, want) {
+						return fmt.Errorf("entry %d: invalid value for %q: got=%v, want=%v", ctx.Entry, rvar.Name, got, want)
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("could not read output tree: %+v", err)
+			}
+		})
+	}
+}
+
+func TestSkimErrors(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-root-skim-")
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	fname := filepath.Join(tmp, "in.root")
+	_ = makeSplitFlatTree(0, 10)(t, fname)
+
+	const tname = "dir-1/dir-11/mytree"
+
+	for _, tc := range []struct {
+		name string
+		sel  string
+		keep []string
+		drop []string
+		err  string
+	}{
+		{
+			name: "invalid-syntax",
+			sel:  "I32 >",
+			err:  "could not parse selection",
+		},
+		{
+			name: "unknown-branch",
+			sel:  "NotThere > 2",
+			err:  `unknown branch "NotThere"`,
+		},
+		{
+			name: "not-boolean",
+			sel:  "I32 + 2",
+			err:  "is not a boolean expression",
+		},
+		{
+			name: "string-branch",
+			sel:  "Str == 2",
+			err:  `invalid type for branch "Str"`,
+		},
+		{
+			name: "unknown-func",
+			sel:  "foo(I32) > 2",
+			err:  `unknown function "foo"`,
+		},
+		{
+			name: "invalid-pattern",
+			keep: []string{"[I32"},
+			err:  `invalid branch pattern "[I32"`,
+		},
+		{
+			name: "no-branches",
+			drop: []string{"*"},
+			err:  "no branch left to write out",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			oname := filepath.Join(tmp, tc.name+".root")
+			_, err := rcmd.Skim(oname, tname, []string{fname}, tc.sel, tc.keep, tc.drop, 1, false)
+			switch {
+			case err == nil:
+				t.Fatalf("expected an error")
+			case !strings.Contains(err.Error(), tc.err):
+				t.Fatalf("invalid error:\ngot= %v\nwant=%v", err, tc.err)
+			}
+		})
+	}
+}
+
+func seq(beg, end int32) []int32 {
+	vs := make([]int32, 0, end-beg)
+	for i := beg; i < end; i++ {
+		vs = append(vs, i)
+	}
+	return vs
+}
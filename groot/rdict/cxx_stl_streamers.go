@@ -0,0 +1,46 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rdict
+
+import (
+	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rmeta"
+)
+
+// streamers for C++ standard library types used as elements of
+// containers in ROOT classes.
+// these are not generated from C++/ROOT along with the ROOT classes ones.
+func init() {
+	// needed by TEfficiency.
+	StreamerInfos.Add(NewCxxStreamerInfo("pair<double,double>", 1, 0xd7bed2, []rbytes.StreamerElement{
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("first", ""),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("second", ""),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+	}))
+}
@@ -75,6 +75,18 @@ func (db *streamerDb) Get(class string, vers int) (rbytes.StreamerInfo, bool) {
 	}
 }
 
+// StreamerInfoBySum returns the named StreamerInfo with the provided checksum.
+func (db *streamerDb) StreamerInfoBySum(name string, chksum int) (rbytes.StreamerInfo, error) {
+	db.RLock()
+	defer db.RUnlock()
+	for k, v := range db.db {
+		if k.class == name && v.CheckSum() == chksum {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("rdict: no streamer for %q (checksum=0x%x)", name, chksum)
+}
+
 // FIXME(sbinet): ROOT changed its checksum behaviour at some point.
 // our reference ROOT files have been caught in the middle of this migration.
 // disable the check for duplicate streamers with different checksums for now.
@@ -127,5 +139,6 @@ func (db *streamerDb) Values() []rbytes.StreamerInfo {
 }
 
 var (
-	_ rbytes.StreamerInfoContext = (*streamerDb)(nil)
+	_ rbytes.StreamerInfoContext    = (*streamerDb)(nil)
+	_ rbytes.StreamerInfoSumContext = (*streamerDb)(nil)
 )
@@ -0,0 +1,331 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rdict
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rmeta"
+)
+
+// UnmarshalEvolved reads the members of an object of the named class,
+// streamed with the class version vers, into the struct pointed at by recv.
+//
+// UnmarshalEvolved implements the schema evolution rules used when the
+// version of the streamed object differs from the one the Go type of recv
+// was generated for.
+// Members are matched by name, using the groot struct tag or the field name.
+// Members missing from the Go type are skipped, members missing from the
+// streamed object are left to their zero value and numerical members are
+// converted to the type of the Go field.
+//
+// The StreamerInfo describing the streamed object is retrieved from the
+// StreamerInfoContext of r, falling back to StreamerInfos.
+//
+// UnmarshalEvolved only reads the members of the object: the version
+// header must have been consumed by the caller.
+func UnmarshalEvolved(r *rbytes.RBuffer, recv interface{}, class string, vers int16) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	rv := reflect.ValueOf(recv)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		r.SetErr(fmt.Errorf("rdict: invalid receiver type %T for %q (want pointer to struct)", recv, class))
+		return r.Err()
+	}
+
+	return unmarshalEvolved(r, rv.Elem(), class, vers)
+}
+
+func unmarshalEvolved(r *rbytes.RBuffer, rv reflect.Value, class string, vers int16) error {
+	si, err := streamerInfoOf(r, class, int(vers))
+	if err != nil {
+		r.SetErr(err)
+		return r.Err()
+	}
+
+	efs, err := evolverOf(sictxOf(r), si, rv.Type())
+	if err != nil {
+		r.SetErr(err)
+		return r.Err()
+	}
+
+	rv.Set(reflect.Zero(rv.Type()))
+	for _, ef := range efs {
+		err := ef(rv, r)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.Err()
+}
+
+// sictxOf returns the StreamerInfoContext the buffer r was created with,
+// or the global StreamerInfos database if there is none.
+func sictxOf(r *rbytes.RBuffer) rbytes.StreamerInfoContext {
+	if sictx := r.StreamerInfoContext(); sictx != nil {
+		return sictx
+	}
+	return StreamerInfos
+}
+
+// streamerInfoOf returns the StreamerInfo of the provided class and version.
+func streamerInfoOf(r *rbytes.RBuffer, class string, vers int) (rbytes.StreamerInfo, error) {
+	si, err := r.StreamerInfo(class, vers)
+	if err == nil && si.ClassVersion() == vers {
+		return si, nil
+	}
+
+	si, ok := StreamerInfos.Get(class, vers)
+	if !ok {
+		return nil, fmt.Errorf("rdict: no streamer for %q (version=%d)", class, vers)
+	}
+	return si, nil
+}
+
+// efunc reads a member of an object into the struct recv.
+type efunc func(recv reflect.Value, r *rbytes.RBuffer) error
+
+// evolverKey identifies a list of evolution functions.
+// The functions are bound to the StreamerInfoContext they were generated
+// with, as the StreamerInfos of nested members are retrieved from it.
+type evolverKey struct {
+	si    rbytes.StreamerInfo
+	rt    reflect.Type
+	sictx rbytes.StreamerInfoContext
+}
+
+var evolvers sync.Map // map[evolverKey][]efunc
+
+// evolverOf returns the list of functions reading an object described by
+// si into a value of type rt.
+func evolverOf(sictx rbytes.StreamerInfoContext, si rbytes.StreamerInfo, rt reflect.Type) ([]efunc, error) {
+	key := evolverKey{si: si, rt: rt, sictx: sictx}
+	if v, ok := evolvers.Load(key); ok {
+		return v.([]efunc), nil
+	}
+
+	efs := make([]efunc, 0, len(si.Elements()))
+	for _, se := range si.Elements() {
+		ef, err := genEvolverFromSE(sictx, se, rt)
+		if err != nil {
+			return nil, fmt.Errorf("rdict: could not evolve class %q (version=%d): %w",
+				si.Name(), si.ClassVersion(), err,
+			)
+		}
+		efs = append(efs, ef)
+	}
+
+	v, _ := evolvers.LoadOrStore(key, efs)
+	return v.([]efunc), nil
+}
+
+func genEvolverFromSE(sictx rbytes.StreamerInfoContext, se rbytes.StreamerElement, rt reflect.Type) (efunc, error) {
+	i := fieldIndex(rt, se)
+	if i < 0 {
+		// member removed from the Go type: read and discard it.
+		return genSkipper(sictx, se), nil
+	}
+	ft := rt.Field(i).Type
+
+	switch se := se.(type) {
+	case *StreamerBase, *StreamerObject, *StreamerObjectAny:
+		if ft.Kind() != reflect.Struct {
+			break
+		}
+		if reflect.PtrTo(ft).Implements(unmarshalerType) {
+			return func(recv reflect.Value, r *rbytes.RBuffer) error {
+				return recv.Field(i).Addr().Interface().(rbytes.Unmarshaler).UnmarshalROOT(r)
+			}, nil
+		}
+		typename := se.TypeName()
+		if _, ok := se.(*StreamerBase); ok {
+			typename = se.Name()
+		}
+		return func(recv reflect.Value, r *rbytes.RBuffer) error {
+			beg := r.Pos()
+			vers, pos, bcnt := r.ReadVersion(typename)
+			err := unmarshalEvolved(r, recv.Field(i), typename, vers)
+			if err != nil {
+				return err
+			}
+			r.CheckByteCount(pos, bcnt, beg, typename)
+			return r.Err()
+		}, nil
+
+	case *StreamerBasicType:
+		switch se.Type() {
+		case rmeta.Float16, rmeta.Double32:
+			if !isFloat(ft.Kind()) {
+				return nil, fmt.Errorf("could not convert member %q from %q to %v", se.Name(), se.TypeName(), ft)
+			}
+			return func(recv reflect.Value, r *rbytes.RBuffer) error {
+				var v float64
+				switch se.Type() {
+				case rmeta.Float16:
+					v = float64(r.ReadF16(se))
+				default:
+					v = float64(r.ReadD32(se))
+				}
+				recv.Field(i).SetFloat(v)
+				return r.Err()
+			}, nil
+		}
+	}
+
+	st := genTypeFromSE(sictx, se)
+	if st == ft {
+		rfunc := genRStreamerFromSE(sictx, se, reflect.New(st))
+		return func(recv reflect.Value, r *rbytes.RBuffer) error {
+			return rfunc(fieldOf(recv, i), r)
+		}, nil
+	}
+
+	if !convertible(st, ft) {
+		return nil, fmt.Errorf("could not convert member %q from %v to %v", se.Name(), st, ft)
+	}
+
+	rfunc := genRStreamerFromSE(sictx, se, reflect.New(st))
+	return func(recv reflect.Value, r *rbytes.RBuffer) error {
+		tmp := reflect.New(st)
+		err := rfunc(argOf(tmp), r)
+		if err != nil {
+			return err
+		}
+		convert(recv.Field(i), tmp.Elem())
+		return nil
+	}, nil
+}
+
+// genSkipper returns a function reading and discarding a member.
+func genSkipper(sictx rbytes.StreamerInfoContext, se rbytes.StreamerElement) efunc {
+	if se, ok := se.(*StreamerBasicType); ok {
+		switch se.Type() {
+		case rmeta.Float16:
+			return func(_ reflect.Value, r *rbytes.RBuffer) error {
+				_ = r.ReadF16(se)
+				return r.Err()
+			}
+		case rmeta.Double32:
+			return func(_ reflect.Value, r *rbytes.RBuffer) error {
+				_ = r.ReadD32(se)
+				return r.Err()
+			}
+		}
+	}
+
+	var (
+		st    = genTypeFromSE(sictx, se)
+		rfunc = genRStreamerFromSE(sictx, se, reflect.New(st))
+	)
+	return func(_ reflect.Value, r *rbytes.RBuffer) error {
+		return rfunc(argOf(reflect.New(st)), r)
+	}
+}
+
+// fieldIndex returns the index of the field of the struct rt that
+// corresponds to the streamer element se, or -1.
+func fieldIndex(rt reflect.Type, se rbytes.StreamerElement) int {
+	name := se.Name()
+	_, base := se.(*StreamerBase)
+	for i := 0; i < rt.NumField(); i++ {
+		ft := rt.Field(i)
+		tag := ft.Tag.Get("groot")
+		if j := strings.Index(tag, ","); j >= 0 {
+			tag = tag[:j]
+		}
+		if j := strings.Index(tag, "["); j >= 0 {
+			tag = tag[:j]
+		}
+		switch {
+		case base && tag == "BASE-"+name:
+			return i
+		case tag == name:
+			return i
+		case ft.Name == "ROOT_"+cxxNameSanitizer.Replace(name):
+			// type generated from a StreamerInfo.
+			return i
+		case tag == "" && ft.Name == name:
+			return i
+		}
+	}
+	return -1
+}
+
+// argOf returns the value pointed at by ptr, in the form expected by
+// the read-streamer functions.
+func argOf(ptr reflect.Value) interface{} {
+	if ptr.Elem().Kind() == reflect.Array {
+		return ptr.Elem().Slice(0, ptr.Elem().Len()).Interface()
+	}
+	return ptr.Interface()
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+func isNumber(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// convertible returns whether values of type src can be converted to
+// values of type dst, following the schema evolution rules.
+func convertible(src, dst reflect.Type) bool {
+	switch sk, dk := src.Kind(), dst.Kind(); {
+	case src.AssignableTo(dst):
+		return true
+	case isNumber(sk) && isNumber(dk):
+		return true
+	case sk == reflect.Bool && dk == reflect.Bool:
+		return true
+	case sk == reflect.String && dk == reflect.String:
+		return true
+	case (sk == reflect.Slice || sk == reflect.Array) && (dk == reflect.Slice || dk == reflect.Array):
+		return convertible(src.Elem(), dst.Elem())
+	}
+	return false
+}
+
+// convert sets dst to the value of src, converted to the type of dst.
+// Arrays are truncated or zero-padded to the length of dst.
+func convert(dst, src reflect.Value) {
+	switch dt := dst.Type(); dt.Kind() {
+	case reflect.Slice:
+		n := src.Len()
+		dst.Set(reflect.MakeSlice(dt, n, n))
+		for i := 0; i < n; i++ {
+			convert(dst.Index(i), src.Index(i))
+		}
+	case reflect.Array:
+		n := src.Len()
+		if n > dst.Len() {
+			n = dst.Len()
+		}
+		dst.Set(reflect.Zero(dt))
+		for i := 0; i < n; i++ {
+			convert(dst.Index(i), src.Index(i))
+		}
+	default:
+		dst.Set(src.Convert(dt))
+	}
+}
+
+var (
+	marshalerType   = reflect.TypeOf((*rbytes.Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*rbytes.Unmarshaler)(nil)).Elem()
+)
@@ -0,0 +1,267 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rdict
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rmeta"
+)
+
+func init() {
+	// version 1: fX is a float, fOld has been removed in version 2.
+	StreamerInfos.Add(NewCxxStreamerInfo("EvoTestPoint", 1, 0x101, []rbytes.StreamerElement{
+		&StreamerBasicType{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fX", ""),
+			Type:  rmeta.Float,
+			Size:  4,
+			EName: "float",
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fOld", ""),
+			Type:  rmeta.Int,
+			Size:  4,
+			EName: "int",
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fN", ""),
+			Type:  rmeta.Int,
+			Size:  4,
+			EName: "int",
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fArr", ""),
+			Type:   rmeta.OffsetL + rmeta.Float,
+			Size:   8,
+			ArrLen: 2,
+			ArrDim: 1,
+			MaxIdx: [5]int32{2, 0, 0, 0, 0},
+			EName:  "float",
+		}.New()},
+	}))
+
+	// version 2: fX is a double, fN is a Long64_t, fZ has been added.
+	StreamerInfos.Add(NewCxxStreamerInfo("EvoTestPoint", 2, 0x102, []rbytes.StreamerElement{
+		&StreamerBasicType{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fX", ""),
+			Type:  rmeta.Double,
+			Size:  8,
+			EName: "double",
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fZ", ""),
+			Type:  rmeta.Double,
+			Size:  8,
+			EName: "double",
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fN", ""),
+			Type:  rmeta.Long64,
+			Size:  8,
+			EName: "Long64_t",
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fArr", ""),
+			Type:   rmeta.OffsetL + rmeta.Double,
+			Size:   24,
+			ArrLen: 3,
+			ArrDim: 1,
+			MaxIdx: [5]int32{3, 0, 0, 0, 0},
+			EName:  "double",
+		}.New()},
+	}))
+
+	StreamerInfos.Add(NewCxxStreamerInfo("EvoTestEvent", 1, 0x201, []rbytes.StreamerElement{
+		&StreamerBasicType{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fRun", ""),
+			Type:  rmeta.Int,
+			Size:  4,
+			EName: "int",
+		}.New()},
+		&StreamerObjectAny{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fPos", ""),
+			Type:  rmeta.Any,
+			Size:  40,
+			EName: "EvoTestPoint",
+		}.New()},
+	}))
+}
+
+type evoPoint struct {
+	X   float64    `groot:"fX"`
+	Z   float64    `groot:"fZ"`
+	N   int64      `groot:"fN"`
+	Arr [3]float64 `groot:"fArr[3]"`
+}
+
+type evoEvent struct {
+	Run int64    `groot:"fRun"`
+	Pos evoPoint `groot:"fPos"`
+}
+
+// writeEvoPointV1 writes the body of a version 1 EvoTestPoint.
+func writeEvoPointV1(w *rbytes.WBuffer) {
+	w.WriteF32(1.5)
+	w.WriteI32(-1)
+	w.WriteI32(42)
+	w.WriteFastArrayF32([]float32{1, 2})
+}
+
+func TestObjectEvolution(t *testing.T) {
+	si1, ok := StreamerInfos.Get("EvoTestPoint", 1)
+	if !ok {
+		t.Fatalf("could not find EvoTestPoint v1")
+	}
+
+	old := ObjectFrom(si1, StreamerInfos)
+	{
+		rv := reflect.ValueOf(old.v).Elem()
+		rv.Field(0).SetFloat(1.5)
+		rv.Field(1).SetInt(-1)
+		rv.Field(2).SetInt(42)
+		reflect.Copy(rv.Field(3), reflect.ValueOf([]float32{1, 2}))
+	}
+
+	wbuf := rbytes.NewWBuffer(nil, nil, 0, StreamerInfos)
+	_, err := old.MarshalROOT(wbuf)
+	if err != nil {
+		t.Fatalf("could not marshal object: %+v", err)
+	}
+
+	obj := ObjectFrom(loadTestSI(t, "EvoTestPoint"), StreamerInfos)
+	if got, want := obj.RVersion(), int16(2); got != want {
+		t.Fatalf("invalid version: got=%d, want=%d", got, want)
+	}
+
+	rbuf := rbytes.NewRBuffer(wbuf.Bytes(), nil, 0, StreamerInfos)
+	err = obj.UnmarshalROOT(rbuf)
+	if err != nil {
+		t.Fatalf("could not unmarshal evolved object: %+v", err)
+	}
+
+	rv := reflect.ValueOf(obj.v).Elem()
+	if got, want := rv.Field(0).Interface(), float64(1.5); got != want {
+		t.Fatalf("invalid fX: got=%v, want=%v", got, want)
+	}
+	if got, want := rv.Field(1).Interface(), float64(0); got != want {
+		t.Fatalf("invalid fZ: got=%v, want=%v", got, want)
+	}
+	if got, want := rv.Field(2).Interface(), int64(42); got != want {
+		t.Fatalf("invalid fN: got=%v, want=%v", got, want)
+	}
+	if got, want := rv.Field(3).Interface(), [3]float64{1, 2, 0}; got != want {
+		t.Fatalf("invalid fArr: got=%v, want=%v", got, want)
+	}
+}
+
+func TestUnmarshalEvolved(t *testing.T) {
+	wbuf := rbytes.NewWBuffer(nil, nil, 0, StreamerInfos)
+	pos := wbuf.WriteVersion(1)
+	wbuf.WriteI32(7)
+	{
+		pos := wbuf.WriteVersion(1)
+		writeEvoPointV1(wbuf)
+		_, err := wbuf.SetByteCount(pos, "EvoTestPoint")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := wbuf.SetByteCount(pos, "EvoTestEvent")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rbuf := rbytes.NewRBuffer(wbuf.Bytes(), nil, 0, StreamerInfos)
+	beg := rbuf.Pos()
+	vers, rpos, bcnt := rbuf.ReadVersion("EvoTestEvent")
+	if vers != 1 {
+		t.Fatalf("invalid version: got=%d, want=1", vers)
+	}
+
+	got := evoEvent{Run: -1, Pos: evoPoint{Z: 42}}
+	err = UnmarshalEvolved(rbuf, &got, "EvoTestEvent", vers)
+	if err != nil {
+		t.Fatalf("could not unmarshal evolved object: %+v", err)
+	}
+	rbuf.CheckByteCount(rpos, bcnt, beg, "EvoTestEvent")
+	if err := rbuf.Err(); err != nil {
+		t.Fatalf("invalid byte count: %+v", err)
+	}
+
+	want := evoEvent{
+		Run: 7,
+		Pos: evoPoint{X: 1.5, N: 42, Arr: [3]float64{1, 2, 0}},
+	}
+	if got != want {
+		t.Fatalf("invalid evolved object:\ngot= %+v\nwant=%+v", got, want)
+	}
+
+	err = UnmarshalEvolved(rbytes.NewRBuffer(nil, nil, 0, StreamerInfos), &got, "EvoTestEvent", 42)
+	if err == nil {
+		t.Fatalf("expected an error for an unknown version")
+	}
+
+	type invalid struct {
+		X string `groot:"fX"`
+	}
+	rbuf = rbytes.NewRBuffer(wbuf.Bytes(), nil, 0, StreamerInfos)
+	err = UnmarshalEvolved(rbuf, &invalid{}, "EvoTestPoint", 1)
+	if err == nil {
+		t.Fatalf("expected an error for a non-convertible member")
+	}
+}
+
+func TestUnmarshalEvolvedConcurrent(t *testing.T) {
+	wbuf := rbytes.NewWBuffer(nil, nil, 0, StreamerInfos)
+	writeEvoPointV1(wbuf)
+	raw := wbuf.Bytes()
+
+	const n = 8
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, n)
+		pts  = make([]evoPoint, n)
+	)
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				rbuf := rbytes.NewRBuffer(raw, nil, 0, StreamerInfos)
+				errs[i] = UnmarshalEvolved(rbuf, &pts[i], "EvoTestPoint", 1)
+				if errs[i] != nil {
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	want := evoPoint{X: 1.5, N: 42, Arr: [3]float64{1, 2, 0}}
+	for i := range pts {
+		if errs[i] != nil {
+			t.Fatalf("goroutine %d: could not unmarshal evolved object: %+v", i, errs[i])
+		}
+		if pts[i] != want {
+			t.Fatalf("goroutine %d: invalid evolved object:\ngot= %+v\nwant=%+v", i, pts[i], want)
+		}
+	}
+}
+
+func TestReadVersionBySum(t *testing.T) {
+	wbuf := rbytes.NewWBuffer(nil, nil, 0, nil)
+	wbuf.WriteU16(0)
+	wbuf.WriteU32(0x101)
+
+	rbuf := rbytes.NewRBuffer(wbuf.Bytes(), nil, 0, StreamerInfos)
+	vers, _, _ := rbuf.ReadVersion("EvoTestPoint")
+	if got, want := vers, int16(1); got != want {
+		t.Fatalf("invalid version: got=%d, want=%d", got, want)
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"go-hep.org/x/hep/groot/rbytes"
 	"go-hep.org/x/hep/groot/rmeta"
@@ -25,6 +27,10 @@ type genGoType struct {
 	ctx rbytes.StreamerInfoContext
 
 	verbose bool
+	export  bool // whether to export members with an unexported name
+
+	types map[string]struct{} // set of generated types
+	elems map[string]string   // class types of std::vector elements, with the member holding them
 
 	// set of imported packages.
 	// usually: "go-hep.org/x/hep/groot/rbase", ".../rcont
@@ -67,6 +73,8 @@ func NewGenGoType(pkg string, sictx rbytes.StreamerInfoContext, verbose bool) (*
 		buf:     new(bytes.Buffer),
 		ctx:     sictx,
 		verbose: verbose,
+		types:   make(map[string]struct{}),
+		elems:   make(map[string]string),
 		imps: map[string]int{
 			"reflect":                       1,
 			"go-hep.org/x/hep/groot/rbytes": 1,
@@ -77,6 +85,15 @@ func NewGenGoType(pkg string, sictx rbytes.StreamerInfoContext, verbose bool) (*
 	}, nil
 }
 
+// ExportMembers configures whether the generated types export the Go fields
+// of the C++ members with an unexported name (e.g. "pid"), by prefixing them
+// with "ROOT_" (e.g. "ROOT_pid"), as for the types created from a StreamerInfo
+// at runtime.
+// Exported fields are needed to read and write these types with rtree.
+func (g *genGoType) ExportMembers(v bool) {
+	g.export = v
+}
+
 // Generate implements rdict.Generator
 func (g *genGoType) Generate(name string) error {
 	if g.verbose {
@@ -92,6 +109,7 @@ func (g *genGoType) Generate(name string) error {
 
 func (g *genGoType) genType(si rbytes.StreamerInfo) error {
 	name := si.Name()
+	g.types[name] = struct{}{}
 	if title := si.Title(); title != "" {
 		g.printf("// %s has been automatically generated.\n", name)
 		g.printf("// %s\n", title)
@@ -149,7 +167,7 @@ func (g *genGoType) genField(si rbytes.StreamerInfo, i int, se rbytes.StreamerEl
 		g.printf(docFmt, fmt.Sprintf("base%d", i), g.typename(se), g.stag(i, se), "// base class")
 
 	case *StreamerBasicPointer:
-		g.printf(docFmt, se.Name(), g.typename(se), g.stag(i, se), doc)
+		g.printf(docFmt, g.fieldname(se.Name()), g.typename(se), g.stag(i, se), doc)
 
 	case *StreamerBasicType:
 		tname := g.typename(se)
@@ -158,11 +176,11 @@ func (g *genGoType) genField(si rbytes.StreamerInfo, i int, se rbytes.StreamerEl
 		default:
 			tname = fmt.Sprintf("[%d]%s", se.ArrayLen(), tname)
 		}
-		g.printf(docFmt, se.Name(), tname, g.stag(i, se), doc)
+		g.printf(docFmt, g.fieldname(se.Name()), tname, g.stag(i, se), doc)
 
 	case *StreamerLoop:
 		tname := g.typename(se)
-		g.printf(docFmt, se.Name(), tname, g.stag(i, se), doc)
+		g.printf(docFmt, g.fieldname(se.Name()), tname, g.stag(i, se), doc)
 
 	case *StreamerObject:
 		tname := g.typename(se)
@@ -171,7 +189,7 @@ func (g *genGoType) genField(si rbytes.StreamerInfo, i int, se rbytes.StreamerEl
 		default:
 			tname = fmt.Sprintf("[%d]%s", se.ArrayLen(), tname)
 		}
-		g.printf(docFmt, se.Name(), tname, g.stag(i, se), doc)
+		g.printf(docFmt, g.fieldname(se.Name()), tname, g.stag(i, se), doc)
 
 	case *StreamerObjectAny:
 		tname := g.typename(se)
@@ -180,29 +198,29 @@ func (g *genGoType) genField(si rbytes.StreamerInfo, i int, se rbytes.StreamerEl
 		default:
 			tname = fmt.Sprintf("[%d]%s", se.ArrayLen(), tname)
 		}
-		g.printf(docFmt, se.Name(), tname, g.stag(i, se), doc)
+		g.printf(docFmt, g.fieldname(se.Name()), tname, g.stag(i, se), doc)
 
 	case *StreamerObjectAnyPointer:
 		tname := g.typename(se)
-		g.printf(docFmt, se.Name(), tname, g.stag(i, se), doc)
+		g.printf(docFmt, g.fieldname(se.Name()), tname, g.stag(i, se), doc)
 
 	case *StreamerObjectPointer:
 		tname := g.typename(se)
-		g.printf(docFmt, se.Name(), tname, g.stag(i, se), doc)
+		g.printf(docFmt, g.fieldname(se.Name()), tname, g.stag(i, se), doc)
 
 	case *StreamerString, *StreamerSTLstring:
-		g.printf(docFmt, se.Name(), "string", g.stag(i, se), doc)
+		g.printf(docFmt, g.fieldname(se.Name()), "string", g.stag(i, se), doc)
 
 	case *StreamerSTL:
 		switch se.STLType() {
 		case rmeta.STLvector, rmeta.STLmap:
 			tname := g.typename(se)
-			g.printf(docFmt, se.Name(), tname, g.stag(i, se), doc)
+			g.printf(docFmt, g.fieldname(se.Name()), tname, g.stag(i, se), doc)
 		default:
 			panic(fmt.Errorf("STL-type not implemented %#v", se))
 		}
 	default:
-		g.printf("\t%s\t%s // %T -- %s\n", se.Name(), g.typename(se), se, doc)
+		g.printf("\t%s\t%s // %T -- %s\n", g.fieldname(se.Name()), g.typename(se), se, doc)
 	}
 }
 
@@ -218,6 +236,34 @@ func (g *genGoType) stag(i int, se rbytes.StreamerElement) string {
 	return fmt.Sprintf("`groot:%q`", se.Name())
 }
 
+// fieldname returns the name of the Go field holding the named member.
+// When exporting members, members whose name would not be exported are
+// prefixed with "ROOT_".
+func (g *genGoType) fieldname(name string) string {
+	if !g.export {
+		return name
+	}
+	r, _ := utf8.DecodeRuneInString(name)
+	if unicode.IsUpper(r) {
+		return name
+	}
+	return "ROOT_" + cxxNameSanitizer.Replace(name)
+}
+
+// classElem reports whether the named element type of the std::vector
+// member se of si is a class with a StreamerInfo, and records it as a type
+// that must be generated as well.
+func (g *genGoType) classElem(si rbytes.StreamerInfo, se rbytes.StreamerElement, name string) bool {
+	if g.ctx == nil || strings.HasPrefix(name, "vector<") {
+		return false
+	}
+	if _, err := g.ctx.StreamerInfo(name, -1); err != nil {
+		return false
+	}
+	g.elems[name] = si.Name() + "::" + se.Name()
+	return true
+}
+
 func (g *genGoType) doc(title string) string {
 	_, doc := g.rcomment(title)
 	return doc
@@ -267,6 +313,12 @@ func (g *genGoType) typename(se rbytes.StreamerElement) string {
 		}
 		t, ok := rmeta.CxxBuiltins[tname]
 		if !ok {
+			switch se.Type() {
+			case rmeta.Int32:
+				// enums are streamed as basic types, with the name of
+				// the enum as type name.
+				return genType(nil, se.Type(), 0).Name()
+			}
 			panic(fmt.Errorf("gen-type: unknown C++ builtin %q", tname))
 		}
 		return t.Name()
@@ -462,7 +514,7 @@ func (g *genGoType) genMarshalField(si rbytes.StreamerInfo, i int, se rbytes.Str
 			default:
 				panic(fmt.Errorf("invalid element type: %v", se.Type()))
 			}
-			g.printf("w.%s(o.%s[:o.%s])\n", wfunc, se.Name(), n)
+			g.printf("w.%s(o.%s[:o.%s])\n", wfunc, g.fieldname(se.Name()), g.fieldname(n))
 		default:
 			panic("not implemented")
 		}
@@ -472,48 +524,48 @@ func (g *genGoType) genMarshalField(si rbytes.StreamerInfo, i int, se rbytes.Str
 		case 0:
 			switch se.Type() {
 			case rmeta.Bool:
-				g.printf("w.WriteBool(o.%s)\n", se.Name())
+				g.printf("w.WriteBool(o.%s)\n", g.fieldname(se.Name()))
 
 			case rmeta.Counter:
 				switch se.Size() {
 				case 4:
-					g.printf("w.WriteI32(int32(o.%s))\n", se.Name())
+					g.printf("w.WriteI32(int32(o.%s))\n", g.fieldname(se.Name()))
 				case 8:
-					g.printf("w.WriteI64(int64(o.%s))\n", se.Name())
+					g.printf("w.WriteI64(int64(o.%s))\n", g.fieldname(se.Name()))
 				default:
 					panic(fmt.Errorf("invalid counter size %d for %s.%s", se.Size(), si.Name(), se.Name()))
 				}
 
 			case rmeta.Bits:
-				g.printf("w.WriteI32(int32(o.%s))\n", se.Name())
+				g.printf("w.WriteI32(int32(o.%s))\n", g.fieldname(se.Name()))
 
 			case rmeta.Int8:
-				g.printf("w.WriteI8(o.%s)\n", se.Name())
+				g.printf("w.WriteI8(o.%s)\n", g.fieldname(se.Name()))
 			case rmeta.Int16:
-				g.printf("w.WriteI16(o.%s)\n", se.Name())
+				g.printf("w.WriteI16(o.%s)\n", g.fieldname(se.Name()))
 			case rmeta.Int32:
-				g.printf("w.WriteI32(o.%s)\n", se.Name())
+				g.printf("w.WriteI32(o.%s)\n", g.fieldname(se.Name()))
 			case rmeta.Int64, rmeta.Long64:
-				g.printf("w.WriteI64(o.%s)\n", se.Name())
+				g.printf("w.WriteI64(o.%s)\n", g.fieldname(se.Name()))
 
 			case rmeta.Uint8:
-				g.printf("w.WriteU8(o.%s)\n", se.Name())
+				g.printf("w.WriteU8(o.%s)\n", g.fieldname(se.Name()))
 			case rmeta.Uint16:
-				g.printf("w.WriteU16(o.%s)\n", se.Name())
+				g.printf("w.WriteU16(o.%s)\n", g.fieldname(se.Name()))
 			case rmeta.Uint32:
-				g.printf("w.WriteU32(o.%s)\n", se.Name())
+				g.printf("w.WriteU32(o.%s)\n", g.fieldname(se.Name()))
 			case rmeta.Uint64:
-				g.printf("w.WriteU64(o.%s)\n", se.Name())
+				g.printf("w.WriteU64(o.%s)\n", g.fieldname(se.Name()))
 
 			case rmeta.Float32:
-				g.printf("w.WriteF32(o.%s)\n", se.Name())
+				g.printf("w.WriteF32(o.%s)\n", g.fieldname(se.Name()))
 			case rmeta.Float64:
-				g.printf("w.WriteF64(o.%s)\n", se.Name())
+				g.printf("w.WriteF64(o.%s)\n", g.fieldname(se.Name()))
 
 			case rmeta.Float16:
-				g.printf("w.WriteF32(float32(o.%s)) // FIXME(sbinet)\n", se.Name()) // FIXME(sbinet): handle compression
+				g.printf("w.WriteF32(float32(o.%s)) // FIXME(sbinet)\n", g.fieldname(se.Name())) // FIXME(sbinet): handle compression
 			case rmeta.Double32:
-				g.printf("w.WriteF32(float32(o.%s)) // FIXME(sbinet)\n", se.Name()) // FIXME(sbinet): handle compression
+				g.printf("w.WriteF32(float32(o.%s)) // FIXME(sbinet)\n", g.fieldname(se.Name())) // FIXME(sbinet): handle compression
 
 			default:
 				panic(fmt.Errorf("invalid basic type %v (%d) for %s.%s", se.Type(), se.Type(), si.Name(), se.Name()))
@@ -547,21 +599,21 @@ func (g *genGoType) genMarshalField(si rbytes.StreamerInfo, i int, se rbytes.Str
 			default:
 				panic(fmt.Errorf("invalid array element type: %v", se.Type()))
 			}
-			g.printf("w.%s(o.%s[:%d])\n", wfunc, se.Name(), n)
+			g.printf("w.%s(o.%s[:%d])\n", wfunc, g.fieldname(se.Name()), n)
 		}
 
 	case *StreamerLoop:
 		// FIXME(sbinet): implement. handle mbr-wise
-		g.printf("panic(\"o.%s: not implemented (TStreamerLoop)\")\n", se.Name())
+		g.printf("panic(\"o.%s: not implemented (TStreamerLoop)\")\n", g.fieldname(se.Name()))
 
 	case *StreamerObject:
 		// FIXME(sbinet): check semantics
 		switch se.ArrayLen() {
 		case 0:
-			g.printf("o.%s.MarshalROOT(w) // obj\n", se.Name())
+			g.printf("o.%s.MarshalROOT(w) // obj\n", g.fieldname(se.Name()))
 		default:
-			g.printf("for i := range o.%s {\n", se.Name())
-			g.printf("o.%s[i].MarshalROOT(w) // obj\n", se.Name())
+			g.printf("for i := range o.%s {\n", g.fieldname(se.Name()))
+			g.printf("o.%s[i].MarshalROOT(w) // obj\n", g.fieldname(se.Name()))
 			g.printf("}\n")
 		}
 
@@ -569,26 +621,26 @@ func (g *genGoType) genMarshalField(si rbytes.StreamerInfo, i int, se rbytes.Str
 		// FIXME(sbinet): check semantics
 		switch se.ArrayLen() {
 		case 0:
-			g.printf("o.%s.MarshalROOT(w) // obj-any\n", se.Name())
+			g.printf("o.%s.MarshalROOT(w) // obj-any\n", g.fieldname(se.Name()))
 		default:
-			g.printf("for i := range o.%s {\n", se.Name())
-			g.printf("o.%s[i].MarshalROOT(w) // obj-any\n", se.Name())
+			g.printf("for i := range o.%s {\n", g.fieldname(se.Name()))
+			g.printf("o.%s[i].MarshalROOT(w) // obj-any\n", g.fieldname(se.Name()))
 			g.printf("}\n")
 		}
 
 	case *StreamerObjectAnyPointer:
 		// FIXME(sbinet): check semantics
-		g.printf("w.WriteObjectAny(o.%s) // obj-any-ptr\n", se.Name())
+		g.printf("w.WriteObjectAny(o.%s) // obj-any-ptr\n", g.fieldname(se.Name()))
 
 	case *StreamerObjectPointer:
 		// FIXME(sbinet): check semantics
-		g.printf("w.WriteObjectAny(o.%s) // obj-ptr \n", se.Name())
+		g.printf("w.WriteObjectAny(o.%s) // obj-ptr \n", g.fieldname(se.Name()))
 
 	case *StreamerString:
-		g.printf("w.WriteString(o.%s)\n", se.Name())
+		g.printf("w.WriteString(o.%s)\n", g.fieldname(se.Name()))
 
 	case *StreamerSTLstring:
-		g.printf("w.WriteSTLString(o.%s)\n", se.Name())
+		g.printf("w.WriteSTLString(o.%s)\n", g.fieldname(se.Name()))
 
 	case *StreamerSTL:
 		switch se.STLType() {
@@ -623,7 +675,9 @@ func (g *genGoType) genMarshalField(si rbytes.StreamerInfo, i int, se rbytes.Str
 				case "string":
 					wfunc = "WriteFastArrayString"
 				default:
-					panic(fmt.Errorf("invalid stl-vector element type: %v", se.ContainedType()))
+					if !g.classElem(si, se, etn[0]) {
+						panic(fmt.Errorf("invalid stl-vector element type: %v", se.ContainedType()))
+					}
 				}
 			default:
 				panic(fmt.Errorf("invalid stl-vector element type: %v", se.ContainedType()))
@@ -631,8 +685,15 @@ func (g *genGoType) genMarshalField(si rbytes.StreamerInfo, i int, se rbytes.Str
 			g.imps["go-hep.org/x/hep/groot/rvers"] = 1
 			g.printf("{\n")
 			g.printf("pos := w.WriteVersion(rvers.StreamerInfo)\n")
-			g.printf("w.WriteI32(int32(len(o.%s)))\n", se.Name())
-			g.printf("w.%s(o.%s)\n", wfunc, se.Name())
+			g.printf("w.WriteI32(int32(len(o.%s)))\n", g.fieldname(se.Name()))
+			switch wfunc {
+			case "":
+				g.printf("for i := range o.%s {\n", g.fieldname(se.Name()))
+				g.printf("o.%s[i].MarshalROOT(w) // obj\n", g.fieldname(se.Name()))
+				g.printf("}\n")
+			default:
+				g.printf("w.%s(o.%s)\n", wfunc, g.fieldname(se.Name()))
+			}
 			g.printf("if _, err := w.SetByteCount(pos, %q); err != nil {\n", se.TypeName())
 			g.printf("w.SetErr(err)\n")
 			g.printf("return 0, w.Err()\n")
@@ -656,11 +717,23 @@ func (o *%[1]s) UnmarshalROOT(r *rbytes.RBuffer) error {
 	}
 	
 	start := r.Pos()
-	/*vers*/ _, pos, bcnt := r.ReadVersion(o.Class())
+	vers, pos, bcnt := r.ReadVersion(o.Class())
+	if vers != o.RVersion() {
+		err := %[2]sUnmarshalEvolved(r, o, o.Class(), vers)
+		if err != nil {
+			return err
+		}
+		r.CheckByteCount(pos, bcnt, start, o.Class())
+		return r.Err()
+	}
 
 `,
 		g.cxx2go(si.Name(), qualNone),
+		g.rdict,
 	)
+	if g.rdict != "" {
+		g.imps["go-hep.org/x/hep/groot/rdict"] = 1
+	}
 
 	for i, se := range si.Elements() {
 		g.genUnmarshalField(si, i, se)
@@ -719,8 +792,8 @@ func (g *genGoType) genUnmarshalField(si rbytes.StreamerInfo, i int, se rbytes.S
 			default:
 				panic(fmt.Errorf("invalid element type: %v", se.Type()))
 			}
-			g.printf("o.%s = rbytes.%s(nil, int(o.%s))\n", se.Name(), rsize, n)
-			g.printf("r.%s(o.%s)\n", rfunc, se.Name())
+			g.printf("o.%s = rbytes.%s(nil, int(o.%s))\n", g.fieldname(se.Name()), rsize, g.fieldname(n))
+			g.printf("r.%s(o.%s)\n", rfunc, g.fieldname(se.Name()))
 		default:
 			panic("not implemented")
 		}
@@ -730,48 +803,48 @@ func (g *genGoType) genUnmarshalField(si rbytes.StreamerInfo, i int, se rbytes.S
 		case 0:
 			switch se.Type() {
 			case rmeta.Bool:
-				g.printf("o.%s = r.ReadBool()\n", se.Name())
+				g.printf("o.%s = r.ReadBool()\n", g.fieldname(se.Name()))
 
 			case rmeta.Counter:
 				switch se.Size() {
 				case 4:
-					g.printf("o.%s = r.ReadI32()\n", se.Name())
+					g.printf("o.%s = r.ReadI32()\n", g.fieldname(se.Name()))
 				case 8:
-					g.printf("o.%s = r.ReadI64()\n", se.Name())
+					g.printf("o.%s = r.ReadI64()\n", g.fieldname(se.Name()))
 				default:
 					panic(fmt.Errorf("invalid counter size %d for %s.%s", se.Size(), si.Name(), se.Name()))
 				}
 
 			case rmeta.Bits:
-				g.printf("o.%s = r.ReadI32()\n", se.Name())
+				g.printf("o.%s = r.ReadI32()\n", g.fieldname(se.Name()))
 
 			case rmeta.Int8:
-				g.printf("o.%s = r.ReadI8()\n", se.Name())
+				g.printf("o.%s = r.ReadI8()\n", g.fieldname(se.Name()))
 			case rmeta.Int16:
-				g.printf("o.%s = r.ReadI16()\n", se.Name())
+				g.printf("o.%s = r.ReadI16()\n", g.fieldname(se.Name()))
 			case rmeta.Int32:
-				g.printf("o.%s = r.ReadI32()\n", se.Name())
+				g.printf("o.%s = r.ReadI32()\n", g.fieldname(se.Name()))
 			case rmeta.Int64, rmeta.Long64:
-				g.printf("o.%s = r.ReadI64()\n", se.Name())
+				g.printf("o.%s = r.ReadI64()\n", g.fieldname(se.Name()))
 
 			case rmeta.Uint8:
-				g.printf("o.%s = r.ReadU8()\n", se.Name())
+				g.printf("o.%s = r.ReadU8()\n", g.fieldname(se.Name()))
 			case rmeta.Uint16:
-				g.printf("o.%s = r.ReadU16()\n", se.Name())
+				g.printf("o.%s = r.ReadU16()\n", g.fieldname(se.Name()))
 			case rmeta.Uint32:
-				g.printf("o.%s = r.ReadU32()\n", se.Name())
+				g.printf("o.%s = r.ReadU32()\n", g.fieldname(se.Name()))
 			case rmeta.Uint64:
-				g.printf("o.%s = r.ReadU64()\n", se.Name())
+				g.printf("o.%s = r.ReadU64()\n", g.fieldname(se.Name()))
 
 			case rmeta.Float32:
-				g.printf("o.%s = r.ReadF32()\n", se.Name())
+				g.printf("o.%s = r.ReadF32()\n", g.fieldname(se.Name()))
 			case rmeta.Float64:
-				g.printf("o.%s = r.ReadF64()\n", se.Name())
+				g.printf("o.%s = r.ReadF64()\n", g.fieldname(se.Name()))
 
 			case rmeta.Float16:
-				g.printf("o.%s = root.Float16(r.ReadF32()) // FIXME(sbinet)\n", se.Name()) // FIXME(sbinet): handle compression,factor
+				g.printf("o.%s = root.Float16(r.ReadF32()) // FIXME(sbinet)\n", g.fieldname(se.Name())) // FIXME(sbinet): handle compression,factor
 			case rmeta.Double32:
-				g.printf("o.%s = root.Double32(r.ReadF32()) // FIXME(sbinet)\n", se.Name()) // FIXME(sbinet): handle compression,factor
+				g.printf("o.%s = root.Double32(r.ReadF32()) // FIXME(sbinet)\n", g.fieldname(se.Name())) // FIXME(sbinet): handle compression,factor
 
 			default:
 				panic(fmt.Errorf("invalid basic type %v (%d) for %s.%s", se.Type(), se.Type(), si.Name(), se.Name()))
@@ -804,21 +877,21 @@ func (g *genGoType) genUnmarshalField(si rbytes.StreamerInfo, i int, se rbytes.S
 			default:
 				panic(fmt.Errorf("invalid array element type: %v", se.Type()))
 			}
-			g.printf("r.%s(o.%s[:])\n", rfunc, se.Name())
+			g.printf("r.%s(o.%s[:])\n", rfunc, g.fieldname(se.Name()))
 		}
 
 	case *StreamerLoop:
 		// FIXME(sbinet): implement. handle mbr-wise
-		g.printf("panic(\"o.%s: not implemented (TStreamerLoop)\")\n", se.Name())
+		g.printf("panic(\"o.%s: not implemented (TStreamerLoop)\")\n", g.fieldname(se.Name()))
 
 	case *StreamerObject:
 		// FIXME(sbinet): check semantics
 		switch se.ArrayLen() {
 		case 0:
-			g.printf("o.%s.UnmarshalROOT(r) // obj\n", se.Name())
+			g.printf("o.%s.UnmarshalROOT(r) // obj\n", g.fieldname(se.Name()))
 		default:
-			g.printf("for i := range o.%s {\n", se.Name())
-			g.printf("o.%s[i].UnmarshalROOT(r) // obj\n", se.Name())
+			g.printf("for i := range o.%s {\n", g.fieldname(se.Name()))
+			g.printf("o.%s[i].UnmarshalROOT(r) // obj\n", g.fieldname(se.Name()))
 			g.printf("}\n")
 		}
 
@@ -826,34 +899,34 @@ func (g *genGoType) genUnmarshalField(si rbytes.StreamerInfo, i int, se rbytes.S
 		// FIXME(sbinet): check semantics
 		switch se.ArrayLen() {
 		case 0:
-			g.printf("o.%s.UnmarshalROOT(r) // obj-any\n", se.Name())
+			g.printf("o.%s.UnmarshalROOT(r) // obj-any\n", g.fieldname(se.Name()))
 		default:
-			g.printf("for i := range o.%s {\n", se.Name())
-			g.printf("o.%s[i].UnmarshalROOT(r) // obj-any\n", se.Name())
+			g.printf("for i := range o.%s {\n", g.fieldname(se.Name()))
+			g.printf("o.%s[i].UnmarshalROOT(r) // obj-any\n", g.fieldname(se.Name()))
 			g.printf("}\n")
 		}
 
 	case *StreamerObjectAnyPointer:
 		// FIXME(sbinet): check semantics
 		g.printf("{\n")
-		g.printf("o.%s = nil\n", se.Name())
+		g.printf("o.%s = nil\n", g.fieldname(se.Name()))
 		g.printf("if oo := r.ReadObjectAny(); oo != nil {  // obj-any-ptr\n")
-		g.printf("o.%s = oo.(%s)\n", se.Name(), g.typename(se))
+		g.printf("o.%s = oo.(%s)\n", g.fieldname(se.Name()), g.typename(se))
 		g.printf("}\n}\n")
 
 	case *StreamerObjectPointer:
 		// FIXME(sbinet): check semantics
 		g.printf("{\n")
-		g.printf("o.%s = nil\n", se.Name())
+		g.printf("o.%s = nil\n", g.fieldname(se.Name()))
 		g.printf("if oo := r.ReadObjectAny(); oo != nil {  // obj-ptr\n")
-		g.printf("o.%s = oo.(%s)\n", se.Name(), g.typename(se))
+		g.printf("o.%s = oo.(%s)\n", g.fieldname(se.Name()), g.typename(se))
 		g.printf("}\n}\n")
 
 	case *StreamerString:
-		g.printf("o.%s = r.ReadString()\n", se.Name())
+		g.printf("o.%s = r.ReadString()\n", g.fieldname(se.Name()))
 
 	case *StreamerSTLstring:
-		g.printf("o.%s = r.ReadSTLString()\n", se.Name())
+		g.printf("o.%s = r.ReadSTLString()\n", g.fieldname(se.Name()))
 
 	case *StreamerSTL:
 		switch se.STLType() {
@@ -901,7 +974,9 @@ func (g *genGoType) genUnmarshalField(si rbytes.StreamerInfo, i int, se rbytes.S
 					rfunc = "ReadArrayString"
 					rsize = "ResizeStr"
 				default:
-					panic(fmt.Errorf("invalid stl-vector element type: %v", se.ContainedType()))
+					if !g.classElem(si, se, etn[0]) {
+						panic(fmt.Errorf("invalid stl-vector element type: %v", se.ContainedType()))
+					}
 				}
 			default:
 				panic(fmt.Errorf("invalid stl-vector element type: %v", se.ContainedType()))
@@ -914,8 +989,17 @@ func (g *genGoType) genUnmarshalField(si rbytes.StreamerInfo, i int, se rbytes.S
 			g.printf("r.SetErr(fmt.Errorf(\"rbytes: invalid version for \\\"%s\\\". got=%%v, want=%%v\", vers, rvers.StreamerInfo))\n", se.TypeName())
 			g.printf("return r.Err()\n")
 			g.printf("}\n")
-			g.printf("o.%s = rbytes.%s(nil, int(r.ReadI32()))\n", se.Name(), rsize)
-			g.printf("r.%s(o.%s)\n", rfunc, se.Name())
+			switch rfunc {
+			case "":
+				// FIXME(sbinet): handle member-wise streaming.
+				g.printf("o.%s = make(%s, int(r.ReadI32()))\n", g.fieldname(se.Name()), g.typename(se))
+				g.printf("for i := range o.%s {\n", g.fieldname(se.Name()))
+				g.printf("o.%s[i].UnmarshalROOT(r) // obj\n", g.fieldname(se.Name()))
+				g.printf("}\n")
+			default:
+				g.printf("o.%s = rbytes.%s(nil, int(r.ReadI32()))\n", g.fieldname(se.Name()), rsize)
+				g.printf("r.%s(o.%s)\n", rfunc, g.fieldname(se.Name()))
+			}
 			g.printf("r.CheckByteCount(pos, bcnt, start, %q)\n", se.TypeName())
 			g.printf("}\n")
 
@@ -1295,6 +1379,20 @@ func (g *genGoType) printf(format string, args ...interface{}) {
 
 // Generate implements rdict.Generator
 func (g *genGoType) Format() ([]byte, error) {
+	elems := make([]string, 0, len(g.elems))
+	for name := range g.elems {
+		elems = append(elems, name)
+	}
+	sort.Strings(elems)
+	for _, name := range elems {
+		if _, ok := g.types[name]; !ok {
+			return nil, fmt.Errorf(
+				"rdict: type %q of the elements of %s is not generated",
+				name, g.elems[name],
+			)
+		}
+	}
+
 	buf := new(bytes.Buffer)
 
 	buf.WriteString(fmt.Sprintf(`// DO NOT EDIT; automatically generated by %[1]s
@@ -7,12 +7,14 @@ package rdict
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"go-hep.org/x/hep/groot/rbytes"
 	"go-hep.org/x/hep/groot/rmeta"
 	"go-hep.org/x/hep/groot/root"
 	"go-hep.org/x/hep/groot/rtypes"
+	"go-hep.org/x/hep/groot/rvers"
 )
 
 var (
@@ -45,8 +47,8 @@ type Object struct {
 	rvers int16
 	class string
 
-	rfuncs  []rfunc
-	marshal wfunc
+	rfuncs []rfunc
+	wfuncs []wfunc
 }
 
 func (obj *Object) Class() string {
@@ -76,22 +78,18 @@ func (obj *Object) UnmarshalROOT(r *rbytes.RBuffer) error {
 
 	beg := r.Pos()
 	vers, pos, bcnt := r.ReadVersion(obj.Class())
+	rv := reflect.Indirect(reflect.ValueOf(obj.v))
 	if vers != obj.rvers {
-		r.SetErr(fmt.Errorf("rdict: inconsistent ROOT version (got=%d, want=%d)", vers, obj.rvers))
+		err := unmarshalEvolved(r, rv, obj.Class(), vers)
+		if err != nil {
+			return err
+		}
+		r.CheckByteCount(pos, bcnt, beg, obj.Class())
 		return r.Err()
 	}
 
-	rv := reflect.Indirect(reflect.ValueOf(obj.v))
-	for i, rfunc := range obj.rfuncs {
-		rf := rv.Field(i)
-		switch rf.Kind() {
-		case reflect.Array:
-			rf = rf.Slice(0, rf.Len())
-		default:
-			rf = rf.Addr()
-		}
-		recv := rf.Interface()
-		err := rfunc(recv, r)
+	for _, rfunc := range obj.rfuncs {
+		err := rfunc(obj.v, r)
 		if err != nil {
 			return err
 		}
@@ -102,7 +100,20 @@ func (obj *Object) UnmarshalROOT(r *rbytes.RBuffer) error {
 }
 
 func (obj *Object) MarshalROOT(w *rbytes.WBuffer) (int, error) {
-	return obj.marshal(obj.v, w)
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(obj.rvers)
+
+	for _, wfunc := range obj.wfuncs {
+		_, err := wfunc(obj.v, w)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return w.SetByteCount(pos, obj.Class())
 }
 
 func newObjectFrom(si rbytes.StreamerInfo, sictx rbytes.StreamerInfoContext) *Object {
@@ -115,9 +126,24 @@ func newObjectFrom(si rbytes.StreamerInfo, sictx rbytes.StreamerInfoContext) *Ob
 		class: si.Name(),
 	}
 	obj.rfuncs = genRStreamerFromSI(sictx, si, recv)
+	obj.wfuncs = genWStreamerFromSI(sictx, si, recv)
 	return obj
 }
 
+// fieldOf returns the i-th field of the struct rv, in the form expected
+// by the read and write streamer functions: arrays are passed as slices
+// and all the other fields are passed by pointer.
+func fieldOf(rv reflect.Value, i int) interface{} {
+	rf := rv.Field(i)
+	switch rf.Kind() {
+	case reflect.Array:
+		rf = rf.Slice(0, rf.Len())
+	default:
+		rf = rf.Addr()
+	}
+	return rf.Interface()
+}
+
 type counter interface {
 	ivalue() int
 	imax() int
@@ -183,7 +209,7 @@ func genTypeFromSE(sictx rbytes.StreamerInfoContext, se rbytes.StreamerElement)
 			panic(err)
 		}
 		return genTypeFromSI(sictx, si)
-	case *StreamerObjectPointer:
+	case *StreamerObjectPointer, *StreamerObjectAnyPointer:
 		name := se.TypeName()
 		name = name[:len(name)-1] // drop final '*'
 		si, err := sictx.StreamerInfo(name, -1)
@@ -194,7 +220,14 @@ func genTypeFromSE(sictx rbytes.StreamerInfoContext, se rbytes.StreamerElement)
 	case *StreamerSTL:
 		switch se.STLType() {
 		case rmeta.STLvector:
-			return genType(sictx, se.ContainedType(), -1)
+			if etn, ok := stlElemClass(se); ok {
+				si, err := sictx.StreamerInfo(etn, -1)
+				if err != nil {
+					panic(err)
+				}
+				return reflect.SliceOf(genTypeFromSI(sictx, si))
+			}
+			return reflect.SliceOf(genType(sictx, stlElemType(se), -1))
 		case rmeta.STLmap:
 			types := rmeta.CxxTemplateArgsOf(se.TypeName())
 			if len(types) != 2 {
@@ -250,12 +283,71 @@ func genRStreamerFromSI(sictx rbytes.StreamerInfoContext, si rbytes.StreamerInfo
 
 	for i, se := range si.Elements() {
 		sub := reflect.Indirect(recv).Field(i).Addr()
-		rfunc := genRStreamerFromSE(sictx, se, sub)
-		funcs = append(funcs, rfunc)
+		switch se := se.(type) {
+		case *StreamerBasicPointer:
+			rfunc := genRStreamer(sictx, se, se.Type(), -1, sub)
+			funcs = append(funcs, readBasicPointer(i, countIndex(si, se), rfunc))
+		default:
+			rfunc := genRStreamerFromSE(sictx, se, sub)
+			funcs = append(funcs, readField(i, rfunc))
+		}
 	}
 	return funcs
 }
 
+// readField returns the read-streamer of the i-th field of a struct, from
+// the read-streamer rfunc of that field.
+func readField(i int, rfunc rfunc) rfunc {
+	return func(recv interface{}, r *rbytes.RBuffer) error {
+		return rfunc(fieldOf(reflect.ValueOf(recv).Elem(), i), r)
+	}
+}
+
+// readBasicPointer returns the read-streamer of the i-th field of a struct,
+// a 'T* fArr //[fN]' array whose length is held by the ic-th field.
+func readBasicPointer(i, ic int, rfunc rfunc) rfunc {
+	return func(recv interface{}, r *rbytes.RBuffer) error {
+		var (
+			rv = reflect.ValueOf(recv).Elem()
+			fv = rv.Field(i)
+		)
+		if isArray := r.ReadI8(); isArray == 0 {
+			fv.Set(reflect.Zero(fv.Type()))
+			return r.Err()
+		}
+
+		n := countOf(rv.Field(ic))
+		if fv.Cap() < n {
+			fv.Set(reflect.MakeSlice(fv.Type(), n, n))
+		}
+		fv.SetLen(n)
+		return rfunc(fv.Interface(), r)
+	}
+}
+
+// countIndex returns the index of the element of si holding the number of
+// elements of the provided 'T* fArr //[fN]' array.
+func countIndex(si rbytes.StreamerInfo, se *StreamerBasicPointer) int {
+	for i, elt := range si.Elements() {
+		if elt.Name() == se.CountName() {
+			return i
+		}
+	}
+	panic(fmt.Errorf(
+		"rdict: could not find count %q of %q in %q",
+		se.CountName(), se.Name(), si.Name(),
+	))
+}
+
+// countOf returns the value of the provided integer count field.
+func countOf(rv reflect.Value) int {
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(rv.Uint())
+	}
+	return int(rv.Int())
+}
+
 func genRStreamerFromSE(sictx rbytes.StreamerInfoContext, se rbytes.StreamerElement, recv reflect.Value) rfunc {
 	if _, ok := recv.Interface().(rbytes.Unmarshaler); ok {
 		return func(recv interface{}, r *rbytes.RBuffer) error {
@@ -274,38 +366,18 @@ func genRStreamerFromSE(sictx rbytes.StreamerInfoContext, se rbytes.StreamerElem
 		}
 		typevers := int16(si.ClassVersion())
 		fs := genRStreamerFromSI(sictx, si, recv)
+		return readObject(typename, typevers, fs)
+	case *StreamerBasicType:
+		return genRStreamer(sictx, se, se.Type(), se.ArrayLen(), recv)
+	case *StreamerString:
+		return genRStreamer(sictx, se, se.Type(), se.ArrayLen(), recv)
+	case *StreamerSTLstring:
+		return readSTLStr
+	case *StreamerBasicPointer:
 		return func(recv interface{}, r *rbytes.RBuffer) error {
-			rv := reflect.Indirect(reflect.ValueOf(recv))
-			beg := r.Pos()
-			vers, pos, bcnt := r.ReadVersion(typename)
-			if vers != typevers {
-				r.SetErr(fmt.Errorf("rdict: inconsistent ROOT version type=%q (got=%d, want=%d)", typename, vers, typevers))
-				return r.Err()
-			}
-
-			for i, ff := range fs {
-				rf := rv.Field(i)
-				switch rf.Kind() {
-				case reflect.Array:
-					rf = rf.Slice(0, rf.Len())
-				default:
-					rf = rf.Addr()
-				}
-				err := ff(rf.Interface(), r)
-				if err != nil {
-					return err
-				}
-			}
-
-			r.CheckByteCount(pos, bcnt, beg, typename)
+			r.SetErr(fmt.Errorf("rdict: could not read %q outside of its enclosing object", se.Name()))
 			return r.Err()
 		}
-	case *StreamerBasicType:
-		return genRStreamer(sictx, se.Type(), se.ArrayLen(), recv)
-	case *StreamerString, *StreamerSTLstring:
-		return genRStreamer(sictx, se.Type(), se.ArrayLen(), recv)
-	case *StreamerBasicPointer:
-		return genRStreamer(sictx, se.Type(), -1, recv)
 
 	case *StreamerObjectAny:
 		typename := se.TypeName()
@@ -315,32 +387,7 @@ func genRStreamerFromSE(sictx rbytes.StreamerInfoContext, se rbytes.StreamerElem
 		}
 		typevers := int16(si.ClassVersion())
 		fs := genRStreamerFromSI(sictx, si, recv)
-		return func(recv interface{}, r *rbytes.RBuffer) error {
-			rv := reflect.Indirect(reflect.ValueOf(recv))
-			beg := r.Pos()
-			vers, pos, bcnt := r.ReadVersion(typename)
-			if vers != typevers {
-				r.SetErr(fmt.Errorf("rdict: inconsistent ROOT version type=%q (got=%d, want=%d)", typename, vers, typevers))
-				return r.Err()
-			}
-
-			for i, ff := range fs {
-				rf := rv.Field(i)
-				switch rf.Kind() {
-				case reflect.Array:
-					rf = rf.Slice(0, rf.Len())
-				default:
-					rf = rf.Addr()
-				}
-				err := ff(rf.Interface(), r)
-				if err != nil {
-					return err
-				}
-			}
-
-			r.CheckByteCount(pos, bcnt, beg, typename)
-			return r.Err()
-		}
+		return readObject(typename, typevers, fs)
 
 	case *StreamerObject:
 		typename := se.TypeName()
@@ -350,72 +397,270 @@ func genRStreamerFromSE(sictx rbytes.StreamerInfoContext, se rbytes.StreamerElem
 		}
 		typevers := int16(si.ClassVersion())
 		fs := genRStreamerFromSI(sictx, si, recv)
-		return func(recv interface{}, r *rbytes.RBuffer) error {
-			rv := reflect.Indirect(reflect.ValueOf(recv))
-			beg := r.Pos()
-			vers, pos, bcnt := r.ReadVersion(typename)
-			if vers != typevers {
-				r.SetErr(fmt.Errorf("rdict: inconsistent ROOT version type=%q (got=%d, want=%d)", typename, vers, typevers))
-				return r.Err()
-			}
+		return readObject(typename, typevers, fs)
+
+	case *StreamerObjectPointer, *StreamerObjectAnyPointer:
+		// FIXME(sbinet): a TObject* or MyClass*, in C++/ROOT speak, usually means
+		// (or implies that) we are dealing with some amount of polymorphism.
+		// In Go this should be translated into some kind of interface.
+		return readObjectPtr
 
-			for i, ff := range fs {
-				rf := rv.Field(i)
-				switch rf.Kind() {
-				case reflect.Array:
-					rf = rf.Slice(0, rf.Len())
-				default:
-					rf = rf.Addr()
+	case *StreamerSTL:
+		switch se.STLType() {
+		case rmeta.STLvector:
+			typename := se.TypeName()
+			if etn, ok := stlElemClass(se); ok {
+				return readSTLVectorOf(typename, genRStreamerFromType(sictx, etn, recv.Type().Elem().Elem()))
+			}
+			rfunc := genRStreamer(sictx, se, rmeta.OffsetL+stlElemType(se), -1, recv)
+			return func(recv interface{}, r *rbytes.RBuffer) error {
+				beg := r.Pos()
+				vers, pos, bcnt := r.ReadVersion(typename)
+				if vers != rvers.StreamerInfo {
+					r.SetErr(fmt.Errorf("rdict: invalid version for %q (got=%d, want=%d)", typename, vers, rvers.StreamerInfo))
+					return r.Err()
 				}
-				err := ff(rf.Interface(), r)
+				n := int(r.ReadI32())
+				rv := reflect.ValueOf(recv).Elem()
+				if rv.Cap() < n {
+					rv.Set(reflect.MakeSlice(rv.Type(), n, n))
+				}
+				rv.SetLen(n)
+				err := rfunc(rv.Interface(), r)
 				if err != nil {
 					return err
 				}
+				r.CheckByteCount(pos, bcnt, beg, typename)
+				return r.Err()
 			}
 
-			r.CheckByteCount(pos, bcnt, beg, typename)
-			return r.Err()
+		case rmeta.STLmap:
+			var (
+				typename = se.TypeName()
+				types    = rmeta.CxxTemplateArgsOf(typename)
+				rt       = recv.Type().Elem()
+				kfunc    = genRStreamerFromType(sictx, types[0], rt.Key())
+				vfunc    = genRStreamerFromType(sictx, types[1], rt.Elem())
+			)
+			return func(recv interface{}, r *rbytes.RBuffer) error {
+				beg := r.Pos()
+				vers, pos, bcnt := r.ReadVersion(typename)
+				if vers&^rbytes.StreamedMemberWise != rvers.StreamerInfo {
+					r.SetErr(fmt.Errorf("rdict: invalid version for %q (got=%d, want=%d)", typename, vers, rvers.StreamerInfo))
+					return r.Err()
+				}
+				mbrwise := vers&rbytes.StreamedMemberWise != 0
+				if mbrwise {
+					_ = r.ReadI16() // version of std::pair<K,V>
+					_ = r.ReadU32() // checksum of std::pair<K,V>
+				}
+
+				var (
+					n    = int(r.ReadI32())
+					rv   = reflect.ValueOf(recv).Elem()
+					keys = reflect.MakeSlice(reflect.SliceOf(rt.Key()), n, n)
+					vals = reflect.MakeSlice(reflect.SliceOf(rt.Elem()), n, n)
+				)
+				for i := 0; i < n; i++ {
+					err := kfunc(keys.Index(i).Addr().Interface(), r)
+					if err != nil {
+						return err
+					}
+					if mbrwise {
+						continue
+					}
+					err = vfunc(vals.Index(i).Addr().Interface(), r)
+					if err != nil {
+						return err
+					}
+				}
+				if mbrwise {
+					for i := 0; i < n; i++ {
+						err := vfunc(vals.Index(i).Addr().Interface(), r)
+						if err != nil {
+							return err
+						}
+					}
+				}
+
+				m := reflect.MakeMapWithSize(rt, n)
+				for i := 0; i < n; i++ {
+					m.SetMapIndex(keys.Index(i), vals.Index(i))
+				}
+				rv.Set(m)
+
+				r.CheckByteCount(pos, bcnt, beg, typename)
+				return r.Err()
+			}
 		}
+		panic(fmt.Errorf("rdict: STL container not implemented: %#v", se))
+	}
+}
 
-	case *StreamerObjectPointer:
-		// FIXME(sbinet): a TObject* or MyClass*, in C++/ROOT speak, usually means
-		// (or implies that) we are dealing with some amount of polymorphism.
-		// In Go this should be translated into some kind of interface.
-		typename := se.TypeName()
-		typename = typename[:len(typename)-1] // drop '*' suffix
-		si, err := sictx.StreamerInfo(typename, -1)
-		if err != nil {
-			panic(err)
+// genRStreamerFromType returns the read-streamer of a value of type rt,
+// corresponding to the C++ type named cxx.
+func genRStreamerFromType(sictx rbytes.StreamerInfoContext, cxx string, rt reflect.Type) rfunc {
+	if _, ok := rmeta.CxxBuiltins[cxx]; ok {
+		switch rt {
+		case gotypes[reflect.Bool]:
+			return readBool
+		case gotypes[reflect.Uint8]:
+			return readU8
+		case gotypes[reflect.Uint16]:
+			return readU16
+		case gotypes[reflect.Uint32]:
+			return readU32
+		case gotypes[reflect.Uint64]:
+			return readU64
+		case gotypes[reflect.Uint]:
+			return readUint
+		case gotypes[reflect.Int8]:
+			return readI8
+		case gotypes[reflect.Int16]:
+			return readI16
+		case gotypes[reflect.Int32]:
+			return readI32
+		case gotypes[reflect.Int64]:
+			return readI64
+		case gotypes[reflect.Int]:
+			return readInt
+		case gotypes[reflect.Float32]:
+			return readF32
+		case gotypes[reflect.Float64]:
+			return readF64
+		case gotypes[reflect.String]:
+			return readSTLStr
 		}
-		typevers := int16(si.ClassVersion())
-		fs := genRStreamerFromSI(sictx, si, recv)
+		panic(fmt.Errorf("rdict: gen-rstreamer not implemented for C++ type %q (%v)", cxx, rt))
+	}
+
+	if reflect.PtrTo(rt).Implements(unmarshalerType) {
+		// the value reads its own version header.
 		return func(recv interface{}, r *rbytes.RBuffer) error {
-			rv := reflect.Indirect(reflect.ValueOf(recv))
-			beg := r.Pos()
-			vers, pos, bcnt := r.ReadVersion(typename)
-			if vers != typevers {
-				r.SetErr(fmt.Errorf("rdict: inconsistent ROOT version type=%q (got=%d, want=%d)", typename, vers, typevers))
-				return r.Err()
+			return recv.(rbytes.Unmarshaler).UnmarshalROOT(r)
+		}
+	}
+
+	si, err := sictx.StreamerInfo(cxx, -1)
+	if err != nil {
+		panic(err)
+	}
+	typevers := int16(si.ClassVersion())
+	fs := genRStreamerFromSI(sictx, si, reflect.New(rt))
+	return readObject(cxx, typevers, fs)
+}
+
+// readObject returns the read-streamer of a versioned object, made of
+// the read-streamers of each of its fields.
+func readObject(typename string, typevers int16, fs []rfunc) rfunc {
+	return func(recv interface{}, r *rbytes.RBuffer) error {
+		rv := reflect.Indirect(reflect.ValueOf(recv))
+		beg := r.Pos()
+		vers, pos, bcnt := r.ReadVersion(typename)
+		if vers != typevers {
+			err := unmarshalEvolved(r, rv, typename, vers)
+			if err != nil {
+				return err
 			}
+			r.CheckByteCount(pos, bcnt, beg, typename)
+			return r.Err()
+		}
 
-			for i, ff := range fs {
-				rf := rv.Field(i)
-				switch rf.Kind() {
-				case reflect.Array:
-					rf = rf.Slice(0, rf.Len())
-				default:
-					rf = rf.Addr()
-				}
-				err := ff(rf.Interface(), r)
-				if err != nil {
-					return err
-				}
+		for _, ff := range fs {
+			err := ff(recv, r)
+			if err != nil {
+				return err
 			}
+		}
 
-			r.CheckByteCount(pos, bcnt, beg, typename)
+		r.CheckByteCount(pos, bcnt, beg, typename)
+		return r.Err()
+	}
+}
+
+func readObjectPtr(recv interface{}, r *rbytes.RBuffer) error {
+	rv := reflect.ValueOf(recv).Elem()
+	rv.Set(reflect.Zero(rv.Type()))
+
+	obj := r.ReadObjectAny()
+	if obj == nil || r.Err() != nil {
+		return r.Err()
+	}
+
+	v := reflect.ValueOf(obj)
+	if o, ok := obj.(*Object); ok {
+		v = reflect.ValueOf(o.v)
+	}
+	if !v.Type().AssignableTo(rv.Type()) {
+		r.SetErr(fmt.Errorf("rdict: could not assign object of type %T to %v", obj, rv.Type()))
+		return r.Err()
+	}
+	rv.Set(v)
+	return r.Err()
+}
+
+// stlElemClass returns the name of the class of the elements of a STL
+// container, if these elements are neither builtins nor strings.
+func stlElemClass(se *StreamerSTL) (string, bool) {
+	if se.ContainedType() != rmeta.Object {
+		return "", false
+	}
+	etn := se.ElemTypeName()
+	if len(etn) != 1 {
+		return "", false
+	}
+	switch etn[0] {
+	case "string", "std::string":
+		return "", false
+	}
+	return etn[0], true
+}
+
+// readSTLVectorOf returns the read-streamer of a std::vector of objects,
+// streamed object-wise.
+func readSTLVectorOf(typename string, rfunc rfunc) rfunc {
+	return func(recv interface{}, r *rbytes.RBuffer) error {
+		beg := r.Pos()
+		vers, pos, bcnt := r.ReadVersion(typename)
+		if vers&rbytes.StreamedMemberWise != 0 {
+			r.SetErr(fmt.Errorf("rdict: member-wise streaming of %q not supported", typename))
+			return r.Err()
+		}
+		if vers != rvers.StreamerInfo {
+			r.SetErr(fmt.Errorf("rdict: invalid version for %q (got=%d, want=%d)", typename, vers, rvers.StreamerInfo))
 			return r.Err()
 		}
+		n := int(r.ReadI32())
+		rv := reflect.ValueOf(recv).Elem()
+		if rv.Cap() < n {
+			rv.Set(reflect.MakeSlice(rv.Type(), n, n))
+		}
+		rv.SetLen(n)
+		for i := 0; i < n; i++ {
+			err := rfunc(rv.Index(i).Addr().Interface(), r)
+			if err != nil {
+				return err
+			}
+		}
+		r.CheckByteCount(pos, bcnt, beg, typename)
+		return r.Err()
+	}
+}
+
+// stlElemType returns the type of the elements of a STL container.
+func stlElemType(se *StreamerSTL) rmeta.Enum {
+	ctype := se.ContainedType()
+	switch ctype {
+	case rmeta.Object:
+		if etn := se.ElemTypeName(); len(etn) == 1 {
+			switch etn[0] {
+			case "string", "std::string":
+				return rmeta.STLstring
+			}
+		}
+		panic(fmt.Errorf("rdict: STL container element type not implemented: %#v", se))
 	}
+	return ctype
 }
 
 func genType(sictx rbytes.StreamerInfoContext, enum rmeta.Enum, n int) reflect.Type {
@@ -428,7 +673,7 @@ func genType(sictx rbytes.StreamerInfoContext, enum rmeta.Enum, n int) reflect.T
 		return gotypes[reflect.Uint16]
 	case rmeta.Uint32, rmeta.Bits:
 		return gotypes[reflect.Uint32]
-	case rmeta.Uint64:
+	case rmeta.Uint64, rmeta.ULong64:
 		return gotypes[reflect.Uint64]
 	case rmeta.Int8:
 		return gotypes[reflect.Int8]
@@ -436,12 +681,16 @@ func genType(sictx rbytes.StreamerInfoContext, enum rmeta.Enum, n int) reflect.T
 		return gotypes[reflect.Int16]
 	case rmeta.Int32:
 		return gotypes[reflect.Int32]
-	case rmeta.Int64:
+	case rmeta.Int64, rmeta.Long64:
 		return gotypes[reflect.Int64]
 	case rmeta.Float32:
 		return gotypes[reflect.Float32]
 	case rmeta.Float64:
 		return gotypes[reflect.Float64]
+	case rmeta.Float16:
+		return f16Type
+	case rmeta.Double32:
+		return d32Type
 	case rmeta.TString, rmeta.STLstring:
 		return gotypes[reflect.String]
 
@@ -456,7 +705,7 @@ func genType(sictx rbytes.StreamerInfoContext, enum rmeta.Enum, n int) reflect.T
 		return reflect.ArrayOf(n, gotypes[reflect.Uint16])
 	case rmeta.OffsetL + rmeta.Uint32:
 		return reflect.ArrayOf(n, gotypes[reflect.Uint32])
-	case rmeta.OffsetL + rmeta.Uint64:
+	case rmeta.OffsetL + rmeta.Uint64, rmeta.OffsetL + rmeta.ULong64:
 		return reflect.ArrayOf(n, gotypes[reflect.Uint64])
 	case rmeta.OffsetL + rmeta.Int8:
 		return reflect.ArrayOf(n, gotypes[reflect.Int8])
@@ -464,12 +713,16 @@ func genType(sictx rbytes.StreamerInfoContext, enum rmeta.Enum, n int) reflect.T
 		return reflect.ArrayOf(n, gotypes[reflect.Int16])
 	case rmeta.OffsetL + rmeta.Int32:
 		return reflect.ArrayOf(n, gotypes[reflect.Int32])
-	case rmeta.OffsetL + rmeta.Int64:
+	case rmeta.OffsetL + rmeta.Int64, rmeta.OffsetL + rmeta.Long64:
 		return reflect.ArrayOf(n, gotypes[reflect.Int64])
 	case rmeta.OffsetL + rmeta.Float32:
 		return reflect.ArrayOf(n, gotypes[reflect.Float32])
 	case rmeta.OffsetL + rmeta.Float64:
 		return reflect.ArrayOf(n, gotypes[reflect.Float64])
+	case rmeta.OffsetL + rmeta.Float16:
+		return reflect.ArrayOf(n, f16Type)
+	case rmeta.OffsetL + rmeta.Double32:
+		return reflect.ArrayOf(n, d32Type)
 	case rmeta.OffsetL + rmeta.TString, rmeta.OffsetL + rmeta.STLstring:
 		return reflect.ArrayOf(n, gotypes[reflect.String])
 
@@ -481,7 +734,7 @@ func genType(sictx rbytes.StreamerInfoContext, enum rmeta.Enum, n int) reflect.T
 		return reflect.SliceOf(gotypes[reflect.Uint16])
 	case rmeta.OffsetP + rmeta.Uint32:
 		return reflect.SliceOf(gotypes[reflect.Uint32])
-	case rmeta.OffsetP + rmeta.Uint64:
+	case rmeta.OffsetP + rmeta.Uint64, rmeta.OffsetP + rmeta.ULong64:
 		return reflect.SliceOf(gotypes[reflect.Uint64])
 	case rmeta.OffsetP + rmeta.Int8:
 		return reflect.SliceOf(gotypes[reflect.Int8])
@@ -489,18 +742,27 @@ func genType(sictx rbytes.StreamerInfoContext, enum rmeta.Enum, n int) reflect.T
 		return reflect.SliceOf(gotypes[reflect.Int16])
 	case rmeta.OffsetP + rmeta.Int32:
 		return reflect.SliceOf(gotypes[reflect.Int32])
-	case rmeta.OffsetP + rmeta.Int64:
+	case rmeta.OffsetP + rmeta.Int64, rmeta.OffsetP + rmeta.Long64:
 		return reflect.SliceOf(gotypes[reflect.Int64])
 	case rmeta.OffsetP + rmeta.Float32:
 		return reflect.SliceOf(gotypes[reflect.Float32])
 	case rmeta.OffsetP + rmeta.Float64:
 		return reflect.SliceOf(gotypes[reflect.Float64])
+	case rmeta.OffsetP + rmeta.Float16:
+		return reflect.SliceOf(f16Type)
+	case rmeta.OffsetP + rmeta.Double32:
+		return reflect.SliceOf(d32Type)
 
 	}
 	panic(fmt.Errorf("rmeta=%d (%v) not implemented (n=%v)", enum, enum, n))
 }
 
-func genRStreamer(sictx rbytes.StreamerInfoContext, enum rmeta.Enum, n int, recv reflect.Value) rfunc {
+func genRStreamer(sictx rbytes.StreamerInfoContext, se rbytes.StreamerElement, enum rmeta.Enum, n int, recv reflect.Value) rfunc {
+	if enum > rmeta.OffsetP && enum < rmeta.OffsetP+rmeta.OffsetL {
+		// 'T* fArr //[fN]' arrays are streamed as their first fN elements.
+		enum += rmeta.OffsetL - rmeta.OffsetP
+	}
+
 	switch enum {
 	case rmeta.Bool:
 		return readBool
@@ -510,7 +772,7 @@ func genRStreamer(sictx rbytes.StreamerInfoContext, enum rmeta.Enum, n int, recv
 		return readU16
 	case rmeta.Uint32, rmeta.Bits:
 		return readU32
-	case rmeta.Uint64:
+	case rmeta.Uint64, rmeta.ULong64:
 		return readU64
 	case rmeta.Int8:
 		return readI8
@@ -518,14 +780,26 @@ func genRStreamer(sictx rbytes.StreamerInfoContext, enum rmeta.Enum, n int, recv
 		return readI16
 	case rmeta.Int32:
 		return readI32
-	case rmeta.Int64:
+	case rmeta.Int64, rmeta.Long64:
 		return readI64
 	case rmeta.Float32:
 		return readF32
 	case rmeta.Float64:
 		return readF64
-	case rmeta.TString, rmeta.STLstring:
+	case rmeta.Float16:
+		return func(recv interface{}, r *rbytes.RBuffer) error {
+			*(recv.(*root.Float16)) = r.ReadF16(se)
+			return r.Err()
+		}
+	case rmeta.Double32:
+		return func(recv interface{}, r *rbytes.RBuffer) error {
+			*(recv.(*root.Double32)) = r.ReadD32(se)
+			return r.Err()
+		}
+	case rmeta.TString:
 		return readStr
+	case rmeta.STLstring:
+		return readSTLStr
 
 	case rmeta.Counter:
 		return readInt
@@ -538,7 +812,7 @@ func genRStreamer(sictx rbytes.StreamerInfoContext, enum rmeta.Enum, n int, recv
 		return readU16s
 	case rmeta.OffsetL + rmeta.Uint32:
 		return readU32s
-	case rmeta.OffsetL + rmeta.Uint64:
+	case rmeta.OffsetL + rmeta.Uint64, rmeta.OffsetL + rmeta.ULong64:
 		return readU64s
 	case rmeta.OffsetL + rmeta.Int8:
 		return readI8s
@@ -546,12 +820,22 @@ func genRStreamer(sictx rbytes.StreamerInfoContext, enum rmeta.Enum, n int, recv
 		return readI16s
 	case rmeta.OffsetL + rmeta.Int32:
 		return readI32s
-	case rmeta.OffsetL + rmeta.Int64:
+	case rmeta.OffsetL + rmeta.Int64, rmeta.OffsetL + rmeta.Long64:
 		return readI64s
 	case rmeta.OffsetL + rmeta.Float32:
 		return readF32s
 	case rmeta.OffsetL + rmeta.Float64:
 		return readF64s
+	case rmeta.OffsetL + rmeta.Float16:
+		return func(recv interface{}, r *rbytes.RBuffer) error {
+			r.ReadArrayF16(recv.([]root.Float16), se)
+			return r.Err()
+		}
+	case rmeta.OffsetL + rmeta.Double32:
+		return func(recv interface{}, r *rbytes.RBuffer) error {
+			r.ReadArrayD32(recv.([]root.Double32), se)
+			return r.Err()
+		}
 	case rmeta.OffsetL + rmeta.TString, rmeta.OffsetL + rmeta.STLstring:
 		return readStrs
 
@@ -619,10 +903,19 @@ func readStr(recv interface{}, r *rbytes.RBuffer) error {
 	return r.Err()
 }
 
+func readSTLStr(recv interface{}, r *rbytes.RBuffer) error {
+	*(recv.(*string)) = r.ReadSTLString()
+	return r.Err()
+}
+
+func readUint(recv interface{}, r *rbytes.RBuffer) error {
+	*(recv.(*uint)) = uint(r.ReadU32())
+	return r.Err()
+}
+
 func readInt(recv interface{}, r *rbytes.RBuffer) error {
-	panic("not implemented")
-	//	*(recv.(*int)) = int(r.ReadI64())
-	//	return r.Err()
+	*(recv.(*int)) = int(r.ReadI32())
+	return r.Err()
 }
 
 func readBools(recv interface{}, r *rbytes.RBuffer) error {
@@ -697,6 +990,588 @@ func readStrs(recv interface{}, r *rbytes.RBuffer) error {
 	return r.Err()
 }
 
+// WStreamerOf returns a write-streamer writing the members of the struct
+// pointed at by ptr, as described by si, without any version header.
+// The fields of the struct must follow the order of the elements of si,
+// as is the case for the types generated by root-gen-type.
+func WStreamerOf(sictx rbytes.StreamerInfoContext, si rbytes.StreamerInfo, ptr interface{}) rbytes.WStreamer {
+	return &wstreamer{
+		recv:  ptr,
+		funcs: genWStreamerFromElements(sictx, si, reflect.ValueOf(ptr)),
+	}
+}
+
+type wstreamer struct {
+	recv  interface{}
+	funcs []wfunc
+}
+
+func (ws *wstreamer) WStreamROOT(w *rbytes.WBuffer) error {
+	for _, wfunc := range ws.funcs {
+		_, err := wfunc(ws.recv, w)
+		if err != nil {
+			return err
+		}
+	}
+	return w.Err()
+}
+
+func genWStreamerFromSI(sictx rbytes.StreamerInfoContext, si rbytes.StreamerInfo, recv reflect.Value) []wfunc {
+	if _, ok := recv.Interface().(rbytes.Marshaler); ok {
+		var funcs []wfunc
+		funcs = append(funcs, func(recv interface{}, w *rbytes.WBuffer) (int, error) {
+			return recv.(rbytes.Marshaler).MarshalROOT(w)
+		})
+		return funcs
+	}
+
+	return genWStreamerFromElements(sictx, si, recv)
+}
+
+// genWStreamerFromElements returns the write-streamers of the members of
+// the struct pointed at by recv.
+func genWStreamerFromElements(sictx rbytes.StreamerInfoContext, si rbytes.StreamerInfo, recv reflect.Value) []wfunc {
+	var funcs = make([]wfunc, 0, len(si.Elements()))
+
+	for i, se := range si.Elements() {
+		sub := reflect.Indirect(recv).Field(i).Addr()
+		switch se := se.(type) {
+		case *StreamerBasicPointer:
+			wfunc := genWStreamer(sictx, se, se.Type(), -1, sub)
+			funcs = append(funcs, writeBasicPointer(i, countIndex(si, se), wfunc))
+		default:
+			wfunc := genWStreamerFromSE(sictx, se, sub)
+			funcs = append(funcs, writeField(i, wfunc))
+		}
+	}
+	return funcs
+}
+
+// writeField returns the write-streamer of the i-th field of a struct, from
+// the write-streamer wfunc of that field.
+func writeField(i int, wfunc wfunc) wfunc {
+	return func(recv interface{}, w *rbytes.WBuffer) (int, error) {
+		return wfunc(fieldOf(reflect.ValueOf(recv).Elem(), i), w)
+	}
+}
+
+// writeBasicPointer returns the write-streamer of the i-th field of a struct,
+// a 'T* fArr //[fN]' array whose length is held by the ic-th field.
+func writeBasicPointer(i, ic int, wfunc wfunc) wfunc {
+	return func(recv interface{}, w *rbytes.WBuffer) (int, error) {
+		var (
+			rv = reflect.ValueOf(recv).Elem()
+			fv = rv.Field(i)
+		)
+		if fv.IsNil() {
+			w.WriteI8(0) // is-array
+			return 1, w.Err()
+		}
+
+		n := countOf(rv.Field(ic))
+		if n > fv.Len() {
+			w.SetErr(fmt.Errorf(
+				"rdict: invalid array length for field %q (len=%d, count=%d)",
+				rv.Type().Field(i).Name, fv.Len(), n,
+			))
+			return 0, w.Err()
+		}
+		w.WriteI8(1) // is-array
+		nn, err := wfunc(fv.Slice(0, n).Interface(), w)
+		return 1 + nn, err
+	}
+}
+
+func genWStreamerFromSE(sictx rbytes.StreamerInfoContext, se rbytes.StreamerElement, recv reflect.Value) wfunc {
+	if _, ok := recv.Interface().(rbytes.Marshaler); ok {
+		return func(recv interface{}, w *rbytes.WBuffer) (int, error) {
+			return recv.(rbytes.Marshaler).MarshalROOT(w)
+		}
+	}
+
+	switch se := se.(type) {
+	default:
+		panic(fmt.Errorf("rdict: unknown write-streamer element: %#v (%T)", se, se))
+	case *StreamerBase:
+		typename := se.Name()
+		si, err := sictx.StreamerInfo(typename, -1)
+		if err != nil {
+			panic(err)
+		}
+		typevers := int16(si.ClassVersion())
+		fs := genWStreamerFromSI(sictx, si, recv)
+		return writeObject(typename, typevers, fs)
+	case *StreamerBasicType:
+		return genWStreamer(sictx, se, se.Type(), se.ArrayLen(), recv)
+	case *StreamerString:
+		return genWStreamer(sictx, se, se.Type(), se.ArrayLen(), recv)
+	case *StreamerSTLstring:
+		return writeSTLStr
+	case *StreamerBasicPointer:
+		return func(recv interface{}, w *rbytes.WBuffer) (int, error) {
+			w.SetErr(fmt.Errorf("rdict: could not write %q outside of its enclosing object", se.Name()))
+			return 0, w.Err()
+		}
+
+	case *StreamerObjectAny:
+		typename := se.TypeName()
+		si, err := sictx.StreamerInfo(typename, -1)
+		if err != nil {
+			panic(err)
+		}
+		typevers := int16(si.ClassVersion())
+		fs := genWStreamerFromSI(sictx, si, recv)
+		return writeObject(typename, typevers, fs)
+
+	case *StreamerObject:
+		typename := se.TypeName()
+		si, err := sictx.StreamerInfo(typename, -1)
+		if err != nil {
+			panic(err)
+		}
+		typevers := int16(si.ClassVersion())
+		fs := genWStreamerFromSI(sictx, si, recv)
+		return writeObject(typename, typevers, fs)
+
+	case *StreamerObjectPointer, *StreamerObjectAnyPointer:
+		typename := se.TypeName()
+		typename = typename[:len(typename)-1] // drop '*' suffix
+		si, err := sictx.StreamerInfo(typename, -1)
+		if err != nil {
+			panic(err)
+		}
+		var (
+			elem     = recv.Type().Elem().Elem()
+			typevers = int16(si.ClassVersion())
+			fs       = genWStreamerFromSI(sictx, si, reflect.New(elem))
+		)
+		return func(recv interface{}, w *rbytes.WBuffer) (int, error) {
+			beg := w.Pos()
+			rv := reflect.ValueOf(recv).Elem()
+			switch {
+			case rv.IsNil():
+				w.WriteObjectAny(nil)
+			default:
+				obj, ok := rv.Interface().(root.Object)
+				if !ok {
+					// wrap values of generated types into an Object.
+					obj = &Object{
+						v:      rv.Interface(),
+						si:     si,
+						rvers:  typevers,
+						class:  typename,
+						wfuncs: fs,
+					}
+				}
+				w.WriteObjectAny(obj)
+			}
+			return int(w.Pos() - beg), w.Err()
+		}
+
+	case *StreamerSTL:
+		switch se.STLType() {
+		case rmeta.STLvector:
+			typename := se.TypeName()
+			if etn, ok := stlElemClass(se); ok {
+				return writeSTLVectorOf(typename, genWStreamerFromType(sictx, etn, recv.Type().Elem().Elem()))
+			}
+			wfunc := genWStreamer(sictx, se, rmeta.OffsetL+stlElemType(se), -1, recv)
+			return func(recv interface{}, w *rbytes.WBuffer) (int, error) {
+				rv := reflect.ValueOf(recv).Elem()
+				pos := w.WriteVersion(rvers.StreamerInfo)
+				w.WriteI32(int32(rv.Len()))
+				_, err := wfunc(rv.Interface(), w)
+				if err != nil {
+					return 0, err
+				}
+				return w.SetByteCount(pos, typename)
+			}
+
+		case rmeta.STLmap:
+			var (
+				typename = se.TypeName()
+				types    = rmeta.CxxTemplateArgsOf(typename)
+				rt       = recv.Type().Elem()
+				kfunc    = genWStreamerFromType(sictx, types[0], rt.Key())
+				vfunc    = genWStreamerFromType(sictx, types[1], rt.Elem())
+			)
+			return func(recv interface{}, w *rbytes.WBuffer) (int, error) {
+				var (
+					rv   = reflect.ValueOf(recv).Elem()
+					keys = sortedKeys(rv)
+					pos  = w.WriteVersion(rvers.StreamerInfo)
+				)
+				w.WriteI32(int32(len(keys)))
+				for _, key := range keys {
+					k := reflect.New(rt.Key())
+					k.Elem().Set(key)
+					_, err := kfunc(k.Interface(), w)
+					if err != nil {
+						return 0, err
+					}
+					v := reflect.New(rt.Elem())
+					v.Elem().Set(rv.MapIndex(key))
+					_, err = vfunc(v.Interface(), w)
+					if err != nil {
+						return 0, err
+					}
+				}
+				return w.SetByteCount(pos, typename)
+			}
+		}
+		panic(fmt.Errorf("rdict: STL container not implemented: %#v", se))
+	}
+}
+
+// genWStreamerFromType returns the write-streamer of a value of type rt,
+// corresponding to the C++ type named cxx.
+func genWStreamerFromType(sictx rbytes.StreamerInfoContext, cxx string, rt reflect.Type) wfunc {
+	if _, ok := rmeta.CxxBuiltins[cxx]; ok {
+		switch rt {
+		case gotypes[reflect.Bool]:
+			return writeBool
+		case gotypes[reflect.Uint8]:
+			return writeU8
+		case gotypes[reflect.Uint16]:
+			return writeU16
+		case gotypes[reflect.Uint32]:
+			return writeU32
+		case gotypes[reflect.Uint64]:
+			return writeU64
+		case gotypes[reflect.Uint]:
+			return writeUint
+		case gotypes[reflect.Int8]:
+			return writeI8
+		case gotypes[reflect.Int16]:
+			return writeI16
+		case gotypes[reflect.Int32]:
+			return writeI32
+		case gotypes[reflect.Int64]:
+			return writeI64
+		case gotypes[reflect.Int]:
+			return writeInt
+		case gotypes[reflect.Float32]:
+			return writeF32
+		case gotypes[reflect.Float64]:
+			return writeF64
+		case gotypes[reflect.String]:
+			return writeSTLStr
+		}
+		panic(fmt.Errorf("rdict: gen-wstreamer not implemented for C++ type %q (%v)", cxx, rt))
+	}
+
+	if reflect.PtrTo(rt).Implements(marshalerType) {
+		// the value writes its own version header.
+		return func(recv interface{}, w *rbytes.WBuffer) (int, error) {
+			return recv.(rbytes.Marshaler).MarshalROOT(w)
+		}
+	}
+
+	si, err := sictx.StreamerInfo(cxx, -1)
+	if err != nil {
+		panic(err)
+	}
+	typevers := int16(si.ClassVersion())
+	fs := genWStreamerFromSI(sictx, si, reflect.New(rt))
+	return writeObject(cxx, typevers, fs)
+}
+
+// sortedKeys returns the keys of the map rv, sorted when they are of a
+// builtin type, so maps are streamed in a reproducible order.
+func sortedKeys(rv reflect.Value) []reflect.Value {
+	keys := rv.MapKeys()
+	var less func(i, j int) bool
+	switch rv.Type().Key().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		less = func(i, j int) bool { return keys[i].Int() < keys[j].Int() }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		less = func(i, j int) bool { return keys[i].Uint() < keys[j].Uint() }
+	case reflect.Float32, reflect.Float64:
+		less = func(i, j int) bool { return keys[i].Float() < keys[j].Float() }
+	case reflect.String:
+		less = func(i, j int) bool { return keys[i].String() < keys[j].String() }
+	case reflect.Bool:
+		less = func(i, j int) bool { return !keys[i].Bool() && keys[j].Bool() }
+	default:
+		return keys
+	}
+	sort.Slice(keys, less)
+	return keys
+}
+
+// writeSTLVectorOf returns the write-streamer of a std::vector of objects,
+// streamed object-wise.
+func writeSTLVectorOf(typename string, wfunc wfunc) wfunc {
+	return func(recv interface{}, w *rbytes.WBuffer) (int, error) {
+		rv := reflect.ValueOf(recv).Elem()
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(rv.Len()))
+		for i := 0; i < rv.Len(); i++ {
+			_, err := wfunc(rv.Index(i).Addr().Interface(), w)
+			if err != nil {
+				return 0, err
+			}
+		}
+		return w.SetByteCount(pos, typename)
+	}
+}
+
+// writeObject returns the write-streamer of a versioned object, made of
+// the write-streamers of each of its fields.
+func writeObject(typename string, typevers int16, fs []wfunc) wfunc {
+	return func(recv interface{}, w *rbytes.WBuffer) (int, error) {
+		pos := w.WriteVersion(typevers)
+
+		for _, ff := range fs {
+			_, err := ff(recv, w)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		return w.SetByteCount(pos, typename)
+	}
+}
+
+func genWStreamer(sictx rbytes.StreamerInfoContext, se rbytes.StreamerElement, enum rmeta.Enum, n int, recv reflect.Value) wfunc {
+	if enum > rmeta.OffsetP && enum < rmeta.OffsetP+rmeta.OffsetL {
+		// 'T* fArr //[fN]' arrays are streamed as their first fN elements.
+		enum += rmeta.OffsetL - rmeta.OffsetP
+	}
+
+	switch enum {
+	case rmeta.Bool:
+		return writeBool
+	case rmeta.Uint8:
+		return writeU8
+	case rmeta.Uint16:
+		return writeU16
+	case rmeta.Uint32, rmeta.Bits:
+		return writeU32
+	case rmeta.Uint64, rmeta.ULong64:
+		return writeU64
+	case rmeta.Int8:
+		return writeI8
+	case rmeta.Int16:
+		return writeI16
+	case rmeta.Int32:
+		return writeI32
+	case rmeta.Int64, rmeta.Long64:
+		return writeI64
+	case rmeta.Float32:
+		return writeF32
+	case rmeta.Float64:
+		return writeF64
+	case rmeta.Float16:
+		return func(recv interface{}, w *rbytes.WBuffer) (int, error) {
+			beg := w.Pos()
+			w.WriteF16(*(recv.(*root.Float16)), se)
+			return int(w.Pos() - beg), w.Err()
+		}
+	case rmeta.Double32:
+		return func(recv interface{}, w *rbytes.WBuffer) (int, error) {
+			beg := w.Pos()
+			w.WriteD32(*(recv.(*root.Double32)), se)
+			return int(w.Pos() - beg), w.Err()
+		}
+	case rmeta.TString:
+		return writeStr
+	case rmeta.STLstring:
+		return writeSTLStr
+
+	case rmeta.Counter:
+		return writeInt
+
+	case rmeta.OffsetL + rmeta.Bool:
+		return writeBools
+	case rmeta.OffsetL + rmeta.Uint8:
+		return writeU8s
+	case rmeta.OffsetL + rmeta.Uint16:
+		return writeU16s
+	case rmeta.OffsetL + rmeta.Uint32:
+		return writeU32s
+	case rmeta.OffsetL + rmeta.Uint64, rmeta.OffsetL + rmeta.ULong64:
+		return writeU64s
+	case rmeta.OffsetL + rmeta.Int8:
+		return writeI8s
+	case rmeta.OffsetL + rmeta.Int16:
+		return writeI16s
+	case rmeta.OffsetL + rmeta.Int32:
+		return writeI32s
+	case rmeta.OffsetL + rmeta.Int64, rmeta.OffsetL + rmeta.Long64:
+		return writeI64s
+	case rmeta.OffsetL + rmeta.Float32:
+		return writeF32s
+	case rmeta.OffsetL + rmeta.Float64:
+		return writeF64s
+	case rmeta.OffsetL + rmeta.Float16:
+		return func(recv interface{}, w *rbytes.WBuffer) (int, error) {
+			beg := w.Pos()
+			w.WriteFastArrayF16(recv.([]root.Float16), se)
+			return int(w.Pos() - beg), w.Err()
+		}
+	case rmeta.OffsetL + rmeta.Double32:
+		return func(recv interface{}, w *rbytes.WBuffer) (int, error) {
+			beg := w.Pos()
+			w.WriteFastArrayD32(recv.([]root.Double32), se)
+			return int(w.Pos() - beg), w.Err()
+		}
+	case rmeta.OffsetL + rmeta.TString, rmeta.OffsetL + rmeta.STLstring:
+		return writeStrs
+
+	}
+	panic(fmt.Errorf("rdict: gen-wstreamer not implemented for rmeta=%v,n=%d", enum, n))
+}
+
+func writeBool(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	w.WriteBool(*(recv.(*bool)))
+	return 1, w.Err()
+}
+
+func writeU8(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	w.WriteU8(*(recv.(*uint8)))
+	return 1, w.Err()
+}
+
+func writeU16(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	w.WriteU16(*(recv.(*uint16)))
+	return 2, w.Err()
+}
+
+func writeU32(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	w.WriteU32(*(recv.(*uint32)))
+	return 4, w.Err()
+}
+
+func writeU64(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	w.WriteU64(*(recv.(*uint64)))
+	return 8, w.Err()
+}
+
+func writeI8(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	w.WriteI8(*(recv.(*int8)))
+	return 1, w.Err()
+}
+
+func writeI16(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	w.WriteI16(*(recv.(*int16)))
+	return 2, w.Err()
+}
+
+func writeI32(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	w.WriteI32(*(recv.(*int32)))
+	return 4, w.Err()
+}
+
+func writeI64(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	w.WriteI64(*(recv.(*int64)))
+	return 8, w.Err()
+}
+
+func writeF32(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	w.WriteF32(*(recv.(*float32)))
+	return 4, w.Err()
+}
+
+func writeF64(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	w.WriteF64(*(recv.(*float64)))
+	return 8, w.Err()
+}
+
+func writeStr(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	beg := w.Pos()
+	w.WriteString(*(recv.(*string)))
+	return int(w.Pos() - beg), w.Err()
+}
+
+func writeSTLStr(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	beg := w.Pos()
+	w.WriteSTLString(*(recv.(*string)))
+	return int(w.Pos() - beg), w.Err()
+}
+
+func writeUint(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	w.WriteU32(uint32(*(recv.(*uint))))
+	return 4, w.Err()
+}
+
+func writeInt(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	w.WriteI32(int32(*(recv.(*int))))
+	return 4, w.Err()
+}
+
+func writeBools(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	slice := recv.([]bool)
+	w.WriteFastArrayBool(slice)
+	return len(slice), w.Err()
+}
+
+func writeU8s(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	slice := recv.([]uint8)
+	w.WriteFastArrayU8(slice)
+	return len(slice), w.Err()
+}
+
+func writeU16s(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	slice := recv.([]uint16)
+	w.WriteFastArrayU16(slice)
+	return 2 * len(slice), w.Err()
+}
+
+func writeU32s(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	slice := recv.([]uint32)
+	w.WriteFastArrayU32(slice)
+	return 4 * len(slice), w.Err()
+}
+
+func writeU64s(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	slice := recv.([]uint64)
+	w.WriteFastArrayU64(slice)
+	return 8 * len(slice), w.Err()
+}
+
+func writeI8s(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	slice := recv.([]int8)
+	w.WriteFastArrayI8(slice)
+	return len(slice), w.Err()
+}
+
+func writeI16s(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	slice := recv.([]int16)
+	w.WriteFastArrayI16(slice)
+	return 2 * len(slice), w.Err()
+}
+
+func writeI32s(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	slice := recv.([]int32)
+	w.WriteFastArrayI32(slice)
+	return 4 * len(slice), w.Err()
+}
+
+func writeI64s(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	slice := recv.([]int64)
+	w.WriteFastArrayI64(slice)
+	return 8 * len(slice), w.Err()
+}
+
+func writeF32s(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	slice := recv.([]float32)
+	w.WriteFastArrayF32(slice)
+	return 4 * len(slice), w.Err()
+}
+
+func writeF64s(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	slice := recv.([]float64)
+	w.WriteFastArrayF64(slice)
+	return 8 * len(slice), w.Err()
+}
+
+func writeStrs(recv interface{}, w *rbytes.WBuffer) (int, error) {
+	beg := w.Pos()
+	w.WriteFastArrayString(recv.([]string))
+	return int(w.Pos() - beg), w.Err()
+}
+
 var (
 	gotypes = map[reflect.Kind]reflect.Type{
 		reflect.Bool:    reflect.TypeOf(false),
@@ -714,6 +1589,9 @@ var (
 		reflect.Float64: reflect.TypeOf(float64(0)),
 		reflect.String:  reflect.TypeOf(""),
 	}
+
+	f16Type = reflect.TypeOf(root.Float16(0))
+	d32Type = reflect.TypeOf(root.Double32(0))
 )
 
 var (
@@ -0,0 +1,444 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rdict
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rmeta"
+	"go-hep.org/x/hep/groot/root"
+	"go-hep.org/x/hep/groot/rvers"
+)
+
+func init() {
+	StreamerInfos.Add(NewCxxStreamerInfo("ObjTestPoint", 2, 0x1, []rbytes.StreamerElement{
+		&StreamerBasicType{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fX", ""),
+			Type:  rmeta.Double,
+			Size:  8,
+			EName: "double",
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fY", ""),
+			Type:  rmeta.Double,
+			Size:  8,
+			EName: "double",
+		}.New()},
+	}))
+
+	StreamerInfos.Add(NewCxxStreamerInfo("ObjTestEvent", 3, 0x2, []rbytes.StreamerElement{
+		NewStreamerBase(Element{
+			Name:  *rbase.NewNamed("TObject", "Basic ROOT object"),
+			Type:  rmeta.Base,
+			EName: "BASE",
+		}.New(), 1),
+		&StreamerBasicType{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fRun", ""),
+			Type:  rmeta.Int,
+			Size:  4,
+			EName: "int",
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fEvent", ""),
+			Type:  rmeta.Long64,
+			Size:  8,
+			EName: "Long64_t",
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fArr", ""),
+			Type:   rmeta.OffsetL + rmeta.Float,
+			Size:   12,
+			ArrLen: 3,
+			ArrDim: 1,
+			MaxIdx: [5]int32{3, 0, 0, 0, 0},
+			EName:  "float",
+		}.New()},
+		&StreamerString{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fName", ""),
+			Type:  rmeta.TString,
+			Size:  24,
+			EName: "TString",
+		}.New()},
+		&StreamerSTLstring{*NewCxxStreamerSTL(Element{
+			Name:  *rbase.NewNamed("fLabel", ""),
+			Type:  rmeta.STLstring,
+			Size:  32,
+			EName: "string",
+		}.New(), 365, rmeta.Char)},
+		NewCxxStreamerSTL(Element{
+			Name:  *rbase.NewNamed("fHits", ""),
+			Type:  rmeta.Streamer,
+			Size:  24,
+			EName: "vector<double>",
+		}.New(), rmeta.STLvector, rmeta.Double),
+		NewCxxStreamerSTL(Element{
+			Name:  *rbase.NewNamed("fTags", ""),
+			Type:  rmeta.Streamer,
+			Size:  24,
+			EName: "vector<string>",
+		}.New(), rmeta.STLvector, rmeta.Object),
+		&StreamerObjectAny{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fPos", ""),
+			Type:  rmeta.Any,
+			Size:  16,
+			EName: "ObjTestPoint",
+		}.New()},
+		&StreamerObjectAnyPointer{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fVtx", ""),
+			Type:  rmeta.AnyP,
+			Size:  8,
+			EName: "ObjTestPoint*",
+		}.New()},
+		&StreamerObjectAnyPointer{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fNil", ""),
+			Type:  rmeta.AnyP,
+			Size:  8,
+			EName: "ObjTestPoint*",
+		}.New()},
+	}))
+
+	StreamerInfos.Add(NewCxxStreamerInfo("ObjTestExtra", 1, 0x3, []rbytes.StreamerElement{
+		&StreamerBasicType{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fN", ""),
+			Type:  rmeta.Counter,
+			Size:  4,
+			EName: "int",
+		}.New()},
+		NewStreamerBasicPointer(Element{
+			Name:  *rbase.NewNamed("fArr", "[fN]"),
+			Type:  rmeta.OffsetP + rmeta.Double,
+			Size:  8,
+			EName: "double*",
+		}.New(), 1, "fN", "ObjTestExtra"),
+		NewStreamerBasicPointer(Element{
+			Name:  *rbase.NewNamed("fNil", "[fN]"),
+			Type:  rmeta.OffsetP + rmeta.Int,
+			Size:  4,
+			EName: "int*",
+		}.New(), 1, "fN", "ObjTestExtra"),
+		NewStreamerBasicPointer(Element{
+			Name:  *rbase.NewNamed("fD32s", "[fN][0,10,20]"),
+			Type:  rmeta.OffsetP + rmeta.Double32,
+			Size:  8,
+			EName: "Double32_t*",
+		}.New(), 1, "fN", "ObjTestExtra"),
+		&StreamerBasicType{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fF16", "[0,10,12]"),
+			Type:  rmeta.Float16,
+			Size:  4,
+			EName: "Float16_t",
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:  *rbase.NewNamed("fD32", ""),
+			Type:  rmeta.Double32,
+			Size:  8,
+			EName: "Double32_t",
+		}.New()},
+		&StreamerBasicType{StreamerElement: Element{
+			Name:   *rbase.NewNamed("fF16s", ""),
+			Type:   rmeta.OffsetL + rmeta.Float16,
+			Size:   8,
+			ArrLen: 2,
+			ArrDim: 1,
+			MaxIdx: [5]int32{2, 0, 0, 0, 0},
+			EName:  "Float16_t",
+		}.New()},
+		NewCxxStreamerSTL(Element{
+			Name:  *rbase.NewNamed("fMap", ""),
+			Type:  rmeta.Streamer,
+			Size:  48,
+			EName: "map<int,double>",
+		}.New(), rmeta.STLmap, rmeta.Object),
+		NewCxxStreamerSTL(Element{
+			Name:  *rbase.NewNamed("fPts", ""),
+			Type:  rmeta.Streamer,
+			Size:  48,
+			EName: "map<string,ObjTestPoint>",
+		}.New(), rmeta.STLmap, rmeta.Object),
+	}))
+}
+
+func TestObjectRW(t *testing.T) {
+	si, err := StreamerInfos.StreamerInfo("ObjTestEvent", -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj := ObjectFrom(si, StreamerInfos)
+	if got, want := obj.Class(), "ObjTestEvent"; got != want {
+		t.Fatalf("invalid class: got=%q, want=%q", got, want)
+	}
+
+	pt := ObjectFrom(loadTestSI(t, "ObjTestPoint"), StreamerInfos)
+	{
+		rv := reflect.ValueOf(pt.v).Elem()
+		rv.Field(0).SetFloat(1)
+		rv.Field(1).SetFloat(2)
+	}
+
+	rv := reflect.ValueOf(obj.v).Elem()
+	rv.Field(0).Set(reflect.ValueOf(rbase.Object{Bits: 1 << 24}))
+	rv.Field(1).SetInt(42)
+	rv.Field(2).SetInt(1 << 40)
+	reflect.Copy(rv.Field(3), reflect.ValueOf([]float32{1, 2, 3}))
+	rv.Field(4).SetString("evt")
+	rv.Field(5).SetString("label")
+	rv.Field(6).Set(reflect.ValueOf([]float64{1, 2, 3, 4}))
+	rv.Field(7).Set(reflect.ValueOf([]string{"a", "bb", ""}))
+	rv.Field(8).Field(0).SetFloat(3)
+	rv.Field(8).Field(1).SetFloat(4)
+	rv.Field(9).Set(reflect.ValueOf(pt.v))
+
+	wbuf := rbytes.NewWBuffer(nil, nil, 0, StreamerInfos)
+	_, err = obj.MarshalROOT(wbuf)
+	if err != nil {
+		t.Fatalf("could not marshal object: %+v", err)
+	}
+
+	rbuf := rbytes.NewRBuffer(wbuf.Bytes(), nil, 0, StreamerInfos)
+	got := ObjectFrom(si, StreamerInfos)
+	err = got.UnmarshalROOT(rbuf)
+	if err != nil {
+		t.Fatalf("could not unmarshal object: %+v", err)
+	}
+
+	if !reflect.DeepEqual(got.v, obj.v) {
+		t.Fatalf("round-trip failed:\ngot= %+v\nwant=%+v", got.v, obj.v)
+	}
+
+	// re-writing the read object gives the same bytes.
+	wbuf2 := rbytes.NewWBuffer(nil, nil, 0, StreamerInfos)
+	_, err = got.MarshalROOT(wbuf2)
+	if err != nil {
+		t.Fatalf("could not re-marshal object: %+v", err)
+	}
+	if !bytes.Equal(wbuf.Bytes(), wbuf2.Bytes()) {
+		t.Fatalf("re-marshaled object differs")
+	}
+}
+
+func TestObjectRWExtra(t *testing.T) {
+	si := loadTestSI(t, "ObjTestExtra")
+	obj := ObjectFrom(si, StreamerInfos)
+
+	pts := reflect.MakeMap(reflect.ValueOf(obj.v).Elem().Field(8).Type())
+	for i, name := range []string{"b", "a", "c"} {
+		pt := reflect.New(pts.Type().Elem()).Elem()
+		pt.Field(0).SetFloat(float64(i))
+		pt.Field(1).SetFloat(float64(-i))
+		pts.SetMapIndex(reflect.ValueOf(name), pt)
+	}
+
+	rv := reflect.ValueOf(obj.v).Elem()
+	rv.Field(0).SetInt(3)
+	rv.Field(1).Set(reflect.ValueOf([]float64{1, 2, 3, 4}))
+	rv.Field(3).Set(reflect.ValueOf([]root.Double32{2.5, 5, 7.5}))
+	rv.Field(4).Set(reflect.ValueOf(root.Float16(5)))
+	rv.Field(5).Set(reflect.ValueOf(root.Double32(1.5)))
+	reflect.Copy(rv.Field(6), reflect.ValueOf([]root.Float16{0.5, 2.25}))
+	rv.Field(7).Set(reflect.ValueOf(map[int32]float64{3: 30, 1: 10, 2: 20}))
+	rv.Field(8).Set(pts)
+
+	wbuf := rbytes.NewWBuffer(nil, nil, 0, StreamerInfos)
+	_, err := obj.MarshalROOT(wbuf)
+	if err != nil {
+		t.Fatalf("could not marshal object: %+v", err)
+	}
+
+	rbuf := rbytes.NewRBuffer(wbuf.Bytes(), nil, 0, StreamerInfos)
+	got := ObjectFrom(si, StreamerInfos)
+	err = got.UnmarshalROOT(rbuf)
+	if err != nil {
+		t.Fatalf("could not unmarshal object: %+v", err)
+	}
+
+	// only the first fN elements of fArr are streamed.
+	rv.Field(1).Set(rv.Field(1).Slice(0, 3))
+
+	if !reflect.DeepEqual(got.v, obj.v) {
+		t.Fatalf("round-trip failed:\ngot= %+v\nwant=%+v", got.v, obj.v)
+	}
+}
+
+func TestObjectMapLayout(t *testing.T) {
+	si := loadTestSI(t, "ObjTestExtra")
+	se := si.Elements()[7].(*StreamerSTL)
+
+	recv := reflect.New(reflect.TypeOf(map[int32]float64(nil)))
+	recv.Elem().Set(reflect.ValueOf(map[int32]float64{2: 20, 1: 10}))
+
+	got := rbytes.NewWBuffer(nil, nil, 0, nil)
+	_, err := genWStreamerFromSE(StreamerInfos, se, recv)(recv.Interface(), got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := rbytes.NewWBuffer(nil, nil, 0, nil)
+	pos := want.WriteVersion(rvers.StreamerInfo)
+	want.WriteI32(2)
+	want.WriteI32(1)
+	want.WriteF64(10)
+	want.WriteI32(2)
+	want.WriteF64(20)
+	_, err = want.SetByteCount(pos, "map<int,double>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t.Fatalf("invalid layout:\ngot= %v\nwant=%v", got.Bytes(), want.Bytes())
+	}
+
+	// member-wise streamed maps.
+	wbuf := rbytes.NewWBuffer(nil, nil, 0, nil)
+	pos = wbuf.WriteVersion(rvers.StreamerInfo | rbytes.StreamedMemberWise)
+	wbuf.WriteI16(1)
+	wbuf.WriteU32(0)
+	wbuf.WriteI32(2)
+	wbuf.WriteI32(1)
+	wbuf.WriteI32(2)
+	wbuf.WriteF64(10)
+	wbuf.WriteF64(20)
+	_, err = wbuf.SetByteCount(pos, "map<int,double>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := reflect.New(recv.Type().Elem())
+	err = genRStreamerFromSE(StreamerInfos, se, m)(m.Interface(), rbytes.NewRBuffer(wbuf.Bytes(), nil, 0, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m.Elem().Interface(), recv.Elem().Interface(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid member-wise map:\ngot= %v\nwant=%v", got, want)
+	}
+}
+
+func TestObjectMarshalLayout(t *testing.T) {
+	obj := ObjectFrom(loadTestSI(t, "ObjTestPoint"), StreamerInfos)
+	rv := reflect.ValueOf(obj.v).Elem()
+	rv.Field(0).SetFloat(1)
+	rv.Field(1).SetFloat(2)
+
+	got := rbytes.NewWBuffer(nil, nil, 0, nil)
+	_, err := obj.MarshalROOT(got)
+	if err != nil {
+		t.Fatalf("could not marshal object: %+v", err)
+	}
+
+	want := rbytes.NewWBuffer(nil, nil, 0, nil)
+	pos := want.WriteVersion(2)
+	want.WriteF64(1)
+	want.WriteF64(2)
+	_, err = want.SetByteCount(pos, "ObjTestPoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t.Fatalf("invalid layout:\ngot= %v\nwant=%v", got.Bytes(), want.Bytes())
+	}
+}
+
+func TestObjectSTLLayout(t *testing.T) {
+	si, err := StreamerInfos.StreamerInfo("ObjTestEvent", -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	se := si.Elements()[6].(*StreamerSTL)
+
+	recv := reflect.New(reflect.TypeOf([]float64(nil)))
+	recv.Elem().Set(reflect.ValueOf([]float64{1, 2}))
+
+	got := rbytes.NewWBuffer(nil, nil, 0, nil)
+	_, err = genWStreamerFromSE(StreamerInfos, se, recv)(recv.Interface(), got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := rbytes.NewWBuffer(nil, nil, 0, nil)
+	pos := want.WriteVersion(rvers.StreamerInfo)
+	want.WriteI32(2)
+	want.WriteFastArrayF64([]float64{1, 2})
+	_, err = want.SetByteCount(pos, "vector<double>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t.Fatalf("invalid layout:\ngot= %v\nwant=%v", got.Bytes(), want.Bytes())
+	}
+}
+
+func TestObjectSTLVectorOfObjects(t *testing.T) {
+	se := NewCxxStreamerSTL(Element{
+		Name:  *rbase.NewNamed("fPts", ""),
+		Type:  rmeta.Streamer,
+		Size:  24,
+		EName: "vector<ObjTestPoint>",
+	}.New(), rmeta.STLvector, rmeta.Object)
+
+	rt := genTypeFromSE(StreamerInfos, se)
+	if got, want := rt.Kind(), reflect.Slice; got != want {
+		t.Fatalf("invalid type kind: got=%v, want=%v", got, want)
+	}
+
+	recv := reflect.New(rt)
+	recv.Elem().Set(reflect.MakeSlice(rt, 2, 2))
+	for i := 0; i < 2; i++ {
+		pt := recv.Elem().Index(i)
+		pt.Field(0).SetFloat(float64(2*i + 1))
+		pt.Field(1).SetFloat(float64(2*i + 2))
+	}
+
+	got := rbytes.NewWBuffer(nil, nil, 0, nil)
+	_, err := genWStreamerFromSE(StreamerInfos, se, recv)(recv.Interface(), got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := rbytes.NewWBuffer(nil, nil, 0, nil)
+	pos := want.WriteVersion(rvers.StreamerInfo)
+	want.WriteI32(2)
+	for i := 0; i < 2; i++ {
+		beg := want.WriteVersion(2)
+		want.WriteF64(float64(2*i + 1))
+		want.WriteF64(float64(2*i + 2))
+		_, err = want.SetByteCount(beg, "ObjTestPoint")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = want.SetByteCount(pos, "vector<ObjTestPoint>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t.Fatalf("invalid layout:\ngot= %v\nwant=%v", got.Bytes(), want.Bytes())
+	}
+
+	v := reflect.New(rt)
+	err = genRStreamerFromSE(StreamerInfos, se, v)(v.Interface(), rbytes.NewRBuffer(got.Bytes(), nil, 0, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := v.Elem().Interface(), recv.Elem().Interface(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("round-trip failed:\ngot= %+v\nwant=%+v", got, want)
+	}
+}
+
+func loadTestSI(t *testing.T, name string) rbytes.StreamerInfo {
+	t.Helper()
+	si, err := StreamerInfos.StreamerInfo(name, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return si
+}
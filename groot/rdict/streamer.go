@@ -7,6 +7,7 @@ package rdict
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
 	"go-hep.org/x/hep/groot/rbase"
 	"go-hep.org/x/hep/groot/rbytes"
@@ -143,25 +144,25 @@ func (bld *streamerBuilder) genField(field reflect.StructField) rbytes.StreamerE
 			},
 		}
 	case reflect.Float32:
-		return &StreamerBasicType{
-			StreamerElement{
-				named:  *rbase.NewNamed(nameOf(field), ""),
-				etype:  rmeta.GoType2ROOTEnum[field.Type],
-				esize:  int32(field.Type.Size()),
-				offset: offsetOf(field),
-				ename:  "golang::float32",
-			},
+		se := StreamerElement{
+			named:  *rbase.NewNamed(nameOf(field), ""),
+			etype:  rmeta.GoType2ROOTEnum[field.Type],
+			esize:  int32(field.Type.Size()),
+			offset: offsetOf(field),
+			ename:  "golang::float32",
 		}
+		setRange(&se)
+		return &StreamerBasicType{se}
 	case reflect.Float64:
-		return &StreamerBasicType{
-			StreamerElement{
-				named:  *rbase.NewNamed(nameOf(field), ""),
-				etype:  rmeta.GoType2ROOTEnum[field.Type],
-				esize:  int32(field.Type.Size()),
-				offset: offsetOf(field),
-				ename:  "golang::float64",
-			},
+		se := StreamerElement{
+			named:  *rbase.NewNamed(nameOf(field), ""),
+			etype:  rmeta.GoType2ROOTEnum[field.Type],
+			esize:  int32(field.Type.Size()),
+			offset: offsetOf(field),
+			ename:  "golang::float64",
 		}
+		setRange(&se)
+		return &StreamerBasicType{se}
 	case reflect.String:
 		return &StreamerString{
 			StreamerElement{
@@ -277,25 +278,25 @@ func (bld *streamerBuilder) genField(field reflect.StructField) rbytes.StreamerE
 				},
 			}
 		case reflect.Float32:
-			return &StreamerBasicType{
-				StreamerElement{
-					named:  *rbase.NewNamed(nameOf(field), ""),
-					etype:  rmeta.OffsetL + rmeta.GoType2ROOTEnum[et],
-					esize:  int32(field.Type.Size()),
-					offset: offsetOf(field),
-					ename:  "golang::float32",
-				},
+			se := StreamerElement{
+				named:  *rbase.NewNamed(nameOf(field), ""),
+				etype:  rmeta.OffsetL + rmeta.GoType2ROOTEnum[et],
+				esize:  int32(field.Type.Size()),
+				offset: offsetOf(field),
+				ename:  "golang::float32",
 			}
+			setRange(&se)
+			return &StreamerBasicType{se}
 		case reflect.Float64:
-			return &StreamerBasicType{
-				StreamerElement{
-					named:  *rbase.NewNamed(nameOf(field), ""),
-					etype:  rmeta.OffsetL + rmeta.GoType2ROOTEnum[et],
-					esize:  int32(field.Type.Size()),
-					offset: offsetOf(field),
-					ename:  "golang::float64",
-				},
+			se := StreamerElement{
+				named:  *rbase.NewNamed(nameOf(field), ""),
+				etype:  rmeta.OffsetL + rmeta.GoType2ROOTEnum[et],
+				esize:  int32(field.Type.Size()),
+				offset: offsetOf(field),
+				ename:  "golang::float64",
 			}
+			setRange(&se)
+			return &StreamerBasicType{se}
 		case reflect.String:
 			return &StreamerBasicType{
 				StreamerElement{
@@ -497,6 +498,26 @@ func nameOf(field reflect.StructField) string {
 	return field.Name
 }
 
+// setRange moves the optional "[xmin,xmax,nbits]" range specification
+// from the name of a Float16 or Double32 element to its title.
+func setRange(se *StreamerElement) {
+	switch se.etype {
+	case rmeta.Float16, rmeta.Double32,
+		rmeta.OffsetL + rmeta.Float16, rmeta.OffsetL + rmeta.Double32:
+	default:
+		return
+	}
+
+	name := se.named.Name()
+	beg := strings.LastIndex(name, "[")
+	if beg < 0 || !strings.HasSuffix(name, "]") || !strings.Contains(name[beg:], ",") {
+		return
+	}
+	se.named.SetName(name[:beg])
+	se.named.SetTitle(name[beg:])
+	se.xmin, se.xmax, se.factor = Element{}.getRange(se.named.Title())
+}
+
 func offsetOf(field reflect.StructField) int32 {
 	// return int32(field.Offset)
 	// FIXME(sbinet): it seems ROOT expects 0 here...
@@ -0,0 +1,342 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rhist
+
+import (
+	"fmt"
+	"reflect"
+
+	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rcont"
+	"go-hep.org/x/hep/groot/root"
+	"go-hep.org/x/hep/groot/rtypes"
+	"go-hep.org/x/hep/groot/rvers"
+	"go-hep.org/x/hep/hbook"
+)
+
+// statistic options of TEfficiency.
+const (
+	effStatFCP      = 0 // Clopper-Pearson
+	effStatFNormal  = 1 // normal approximation
+	effStatFWilson  = 2 // Wilson
+	effStatFAC      = 3 // Agresti-Coull
+	effStatFFC      = 4 // Feldman-Cousins
+	effStatBJeffrey = 5 // Jeffrey prior
+	effStatBUniform = 6 // uniform prior
+	effStatBBayes   = 7 // custom Beta prior
+	effStatMidP     = 8 // mid-P Lancaster
+)
+
+// status bits of TEfficiency.
+const (
+	effIsBayesian = 1 << 14
+	effUseWeights = 1 << 18
+)
+
+// Efficiency is a ROOT efficiency, computed from a histogram of passed
+// events and a histogram of total events.
+//
+// Efficiency does not support per-bin Beta priors: these are discarded
+// when reading a TEfficiency.
+type Efficiency struct {
+	named     rbase.Named
+	attline   rbase.AttLine
+	attfill   rbase.AttFill
+	attmarker rbase.AttMarker
+
+	alpha   float64     // global parameter for prior beta distribution
+	beta    float64     // global parameter for prior beta distribution
+	conflvl float64     // confidence level
+	funcs   rcont.List  // list of functions
+	passed  root.Object // histogram of passed events
+	stat    int32       // statistic option
+	total   root.Object // histogram of total events
+	weight  float64     // weight for all events
+}
+
+func newEfficiency() *Efficiency {
+	return &Efficiency{
+		named:     *rbase.NewNamed("", ""),
+		attline:   *rbase.NewAttLine(),
+		attfill:   *rbase.NewAttFill(),
+		attmarker: *rbase.NewAttMarker(),
+		alpha:     1,
+		beta:      1,
+		conflvl:   hbook.EffDefaultCL,
+		funcs:     *rcont.NewList("", nil),
+		weight:    1,
+	}
+}
+
+// NewEfficiency1DFrom creates a new 1-dim efficiency from hbook.
+func NewEfficiency1DFrom(eff *hbook.Efficiency1D) *Efficiency {
+	o := newEfficiency()
+	o.setConfig(eff.Config, eff.Total.SumW2() != eff.Total.SumW())
+	o.setNames(eff.Name(), eff.Ann)
+
+	passed := NewH1DFrom(eff.Passed)
+	passed.SetName(o.Name() + "_passed")
+	o.passed = passed
+
+	total := NewH1DFrom(eff.Total)
+	total.SetName(o.Name() + "_total")
+	o.total = total
+
+	return o
+}
+
+// NewEfficiency2DFrom creates a new 2-dim efficiency from hbook.
+func NewEfficiency2DFrom(eff *hbook.Efficiency2D) *Efficiency {
+	o := newEfficiency()
+	o.setConfig(eff.Config, eff.Total.SumW2() != eff.Total.SumW())
+	o.setNames(eff.Name(), eff.Ann)
+
+	passed := NewH2DFrom(eff.Passed)
+	passed.SetName(o.Name() + "_passed")
+	o.passed = passed
+
+	total := NewH2DFrom(eff.Total)
+	total.SetName(o.Name() + "_total")
+	o.total = total
+
+	return o
+}
+
+func (eff *Efficiency) setConfig(cfg hbook.EffConfig, weighted bool) {
+	eff.stat = int32(cfg.Stat)
+	eff.conflvl = cfg.CL
+	eff.alpha = cfg.Alpha
+	eff.beta = cfg.Beta
+	if cfg.Stat == hbook.EffBayesian {
+		eff.named.SetBit(effIsBayesian)
+	}
+	if weighted {
+		eff.named.SetBit(effUseWeights)
+	}
+}
+
+func (eff *Efficiency) setNames(name string, ann hbook.Annotation) {
+	eff.named.SetName(name)
+	if v, ok := ann["title"]; ok {
+		eff.named.SetTitle(v.(string))
+	}
+}
+
+func (*Efficiency) RVersion() int16 {
+	return rvers.Efficiency
+}
+
+// Class returns the ROOT class name.
+func (*Efficiency) Class() string {
+	return "TEfficiency"
+}
+
+// Name returns the name of the instance
+func (eff *Efficiency) Name() string {
+	return eff.named.Name()
+}
+
+// Title returns the title of the instance
+func (eff *Efficiency) Title() string {
+	return eff.named.Title()
+}
+
+// Rank returns the number of dimensions of this efficiency.
+func (eff *Efficiency) Rank() int {
+	switch eff.total.(type) {
+	case H2:
+		return 2
+	case H1:
+		return 1
+	}
+	return 0
+}
+
+// Passed returns the histogram of passed events.
+func (eff *Efficiency) Passed() root.Object {
+	return eff.passed
+}
+
+// Total returns the histogram of total events.
+func (eff *Efficiency) Total() root.Object {
+	return eff.total
+}
+
+// AsEfficiency1D converts this efficiency into a 1-dim hbook efficiency.
+func (eff *Efficiency) AsEfficiency1D() (*hbook.Efficiency1D, error) {
+	type h1der interface {
+		AsH1D() *hbook.H1D
+	}
+
+	passed, ok := eff.passed.(h1der)
+	if !ok {
+		return nil, fmt.Errorf("rhist: efficiency %q is not 1-dim (passed=%T)", eff.Name(), eff.passed)
+	}
+	total, ok := eff.total.(h1der)
+	if !ok {
+		return nil, fmt.Errorf("rhist: efficiency %q is not 1-dim (total=%T)", eff.Name(), eff.total)
+	}
+
+	cfg, err := eff.config()
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := hbook.NewEfficiency1DFromH1D(passed.AsH1D(), total.AsH1D())
+	if err != nil {
+		return nil, fmt.Errorf("rhist: could not convert efficiency %q: %w", eff.Name(), err)
+	}
+	o.Config = cfg
+	o.Ann = hbook.Annotation{"name": eff.Name(), "title": eff.Title()}
+	return o, nil
+}
+
+// AsEfficiency2D converts this efficiency into a 2-dim hbook efficiency.
+func (eff *Efficiency) AsEfficiency2D() (*hbook.Efficiency2D, error) {
+	type h2der interface {
+		AsH2D() *hbook.H2D
+	}
+
+	passed, ok := eff.passed.(h2der)
+	if !ok {
+		return nil, fmt.Errorf("rhist: efficiency %q is not 2-dim (passed=%T)", eff.Name(), eff.passed)
+	}
+	total, ok := eff.total.(h2der)
+	if !ok {
+		return nil, fmt.Errorf("rhist: efficiency %q is not 2-dim (total=%T)", eff.Name(), eff.total)
+	}
+
+	cfg, err := eff.config()
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := hbook.NewEfficiency2DFromH2D(passed.AsH2D(), total.AsH2D())
+	if err != nil {
+		return nil, fmt.Errorf("rhist: could not convert efficiency %q: %w", eff.Name(), err)
+	}
+	o.Config = cfg
+	o.Ann = hbook.Annotation{"name": eff.Name(), "title": eff.Title()}
+	return o, nil
+}
+
+func (eff *Efficiency) config() (hbook.EffConfig, error) {
+	cfg := hbook.EffConfig{
+		CL:    eff.conflvl,
+		Alpha: eff.alpha,
+		Beta:  eff.beta,
+	}
+	switch eff.stat {
+	case effStatFCP, effStatFNormal, effStatFWilson, effStatFAC, effStatFFC:
+		cfg.Stat = hbook.EffStat(eff.stat)
+	case effStatBJeffrey, effStatBUniform, effStatBBayes:
+		cfg.Stat = hbook.EffBayesian
+	default:
+		return cfg, fmt.Errorf("rhist: efficiency %q has an unsupported statistic option %d", eff.Name(), eff.stat)
+	}
+	return cfg, nil
+}
+
+func (eff *Efficiency) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(eff.RVersion())
+
+	for _, v := range []rbytes.Marshaler{
+		&eff.named,
+		&eff.attline,
+		&eff.attfill,
+		&eff.attmarker,
+	} {
+		if _, err := v.MarshalROOT(w); err != nil {
+			return 0, err
+		}
+	}
+
+	w.WriteF64(eff.alpha)
+	w.WriteF64(eff.beta)
+	{
+		// per-bin Beta priors are not supported.
+		const typename = "vector<pair<double,double> >"
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(0)
+		if _, err := w.SetByteCount(pos, typename); err != nil {
+			return 0, err
+		}
+	}
+	w.WriteF64(eff.conflvl)
+	if _, err := eff.funcs.MarshalROOT(w); err != nil {
+		return 0, err
+	}
+	w.WriteObjectAny(eff.passed)
+	w.WriteI32(eff.stat)
+	w.WriteObjectAny(eff.total)
+	w.WriteF64(eff.weight)
+
+	return w.SetByteCount(pos, eff.Class())
+}
+
+func (eff *Efficiency) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	beg := r.Pos()
+	vers, pos, bcnt := r.ReadVersion(eff.Class())
+	if vers < 2 {
+		return fmt.Errorf("rhist: invalid TEfficiency version=%d < 2", vers)
+	}
+
+	for _, v := range []rbytes.Unmarshaler{
+		&eff.named,
+		&eff.attline,
+		&eff.attfill,
+		&eff.attmarker,
+	} {
+		if err := v.UnmarshalROOT(r); err != nil {
+			return err
+		}
+	}
+
+	eff.alpha = r.ReadF64()
+	eff.beta = r.ReadF64()
+	{
+		// per-bin Beta priors are not supported: skip them.
+		const typename = "vector<pair<double,double> >"
+		beg := r.Pos()
+		_, pos, bcnt := r.ReadVersion(typename)
+		r.SetPos(int64(pos) + int64(bcnt) + 4)
+		r.CheckByteCount(pos, bcnt, beg, typename)
+	}
+	eff.conflvl = r.ReadF64()
+	if err := eff.funcs.UnmarshalROOT(r); err != nil {
+		return err
+	}
+	eff.passed = r.ReadObjectAny()
+	eff.stat = r.ReadI32()
+	eff.total = r.ReadObjectAny()
+	eff.weight = r.ReadF64()
+
+	r.CheckByteCount(pos, bcnt, beg, eff.Class())
+	return r.Err()
+}
+
+func init() {
+	f := func() reflect.Value {
+		o := newEfficiency()
+		return reflect.ValueOf(o)
+	}
+	rtypes.Factory.Add("TEfficiency", f)
+}
+
+var (
+	_ root.Object        = (*Efficiency)(nil)
+	_ root.Named         = (*Efficiency)(nil)
+	_ rbytes.Marshaler   = (*Efficiency)(nil)
+	_ rbytes.Unmarshaler = (*Efficiency)(nil)
+)
@@ -479,6 +479,44 @@ func (dir *tdirectoryFile) Put(name string, obj root.Object) error {
 	return nil
 }
 
+// Delete removes the object identified by namecycle from the directory and
+// releases its space on file.
+//   namecycle has the format name;cycle
+//   if no cycle is given, the key with the highest cycle is removed.
+func (dir *tdirectoryFile) Delete(namecycle string) error {
+	if dir.file.w == nil {
+		return fmt.Errorf("could not delete %q from directory %q: %w", namecycle, dir.dir.Name(), ErrReadOnly)
+	}
+
+	name, cycle := decodeNameCycle(namecycle)
+	idx := -1
+	for i := range dir.keys {
+		key := &dir.keys[i]
+		if key.name != name {
+			continue
+		}
+		if cycle != 9999 && key.cycle != cycle {
+			continue
+		}
+		if idx < 0 || key.cycle > dir.keys[idx].cycle {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return noKeyError{key: namecycle, obj: dir}
+	}
+
+	key := dir.keys[idx]
+	if key.class == "TDirectory" {
+		return fmt.Errorf("riofs: could not delete %q: deleting directories is not supported", namecycle)
+	}
+
+	dir.file.markFree(key.seekkey, key.seekkey+int64(key.nbytes)-1)
+	dir.keys = append(dir.keys[:idx], dir.keys[idx+1:]...)
+
+	return nil
+}
+
 // Keys returns the list of keys being held by this directory.
 func (dir *tdirectoryFile) Keys() []Key {
 	return dir.keys
@@ -622,6 +660,11 @@ func (dir *tdirectoryFile) writeKeys() error {
 		nbytes += key.keylen
 	}
 
+	if dir.seekkeys != 0 {
+		// release the previous list of keys.
+		dir.file.markFree(dir.seekkeys, dir.seekkeys+int64(dir.nbyteskeys)-1)
+	}
+
 	hdr := newKey(dir, dir.Name(), dir.Title(), "TDirectory", nbytes, dir.file)
 
 	buf := rbytes.NewWBuffer(make([]byte, nbytes), nil, 0, nil)
@@ -5,9 +5,12 @@
 package riofs_test
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
 	"go-hep.org/x/hep/groot"
@@ -116,3 +119,112 @@ func TestDirs(t *testing.T) {
 		}
 	}
 }
+
+func TestDirDelete(t *testing.T) {
+	rootdir, err := ioutil.TempDir("", "groot-dir-delete-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootdir)
+
+	fname := filepath.Join(rootdir, "delete.root")
+
+	var seek int64
+	{
+		f, err := riofs.Create(fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		rdir := riofs.Dir(f)
+		for _, v := range []struct{ name, value string }{
+			{"o1", "v1-1"},
+			{"o1", "v1-2"},
+			{"o2", "v2"},
+			{"dir1/o3", "v3"},
+		} {
+			err = rdir.Put(v.name, rbase.NewObjString(v.value))
+			if err != nil {
+				t.Fatalf("could not put %q: %+v", v.name, err)
+			}
+		}
+
+		for _, k := range f.Keys() {
+			if k.Name() == "o1" && k.Cycle() == 1 {
+				seek = k.SeekKey()
+			}
+		}
+
+		err = f.Delete("o1;1")
+		if err != nil {
+			t.Fatalf("could not delete o1;1: %+v", err)
+		}
+
+		err = f.Delete("o1;1")
+		if err == nil {
+			t.Fatalf("expected an error deleting o1;1 twice")
+		}
+
+		err = f.Delete("dir1")
+		if err == nil {
+			t.Fatalf("expected an error deleting a directory")
+		}
+
+		err = rdir.(riofs.Deleter).Delete("dir1/o3")
+		if err != nil {
+			t.Fatalf("could not delete dir1/o3: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+	}
+
+	f, err := riofs.Open(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var keys []string
+	for _, k := range f.Keys() {
+		keys = append(keys, fmt.Sprintf("%s;%d", k.Name(), k.Cycle()))
+	}
+	if got, want := strings.Join(keys, " "), "o1;2 o2;1 dir1;1"; got != want {
+		t.Fatalf("invalid keys: got=%q, want=%q", got, want)
+	}
+
+	o, err := f.Get("o1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := o.(root.ObjString).String(), "v1-2"; got != want {
+		t.Fatalf("invalid o1: got=%q, want=%q", got, want)
+	}
+
+	o, err = riofs.Dir(f).Get("dir1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(o.(riofs.Directory).Keys()); got != 0 {
+		t.Fatalf("invalid number of keys in dir1: got=%d, want=0", got)
+	}
+
+	// the space of the deleted key must have been released.
+	smap := new(strings.Builder)
+	err = f.SegmentMap(smap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gap := regexp.MustCompile(fmt.Sprintf(`At:%d\s+N=-\d+\s+=== \[GAP\] ===`, seek))
+	if !gap.MatchString(smap.String()) {
+		t.Fatalf("no gap at deleted key position %d:\n%s", seek, smap.String())
+	}
+
+	err = f.Delete("o2")
+	if err == nil {
+		t.Fatalf("expected an error deleting from a read-only file")
+	}
+}
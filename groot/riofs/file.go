@@ -116,6 +116,8 @@ type File struct {
 	simap  map[rbytes.StreamerInfo]struct{} // local set of streamers, when writing
 
 	spans freeList // list of free spans on file
+
+	deps []io.Closer // resources closed together with this file
 }
 
 // Open opens the named ROOT file for reading. If successful, methods on the
@@ -160,6 +162,7 @@ func NewReader(r Reader) (*File, error) {
 }
 
 // Create creates the named ROOT file for writing.
+// Objects written to the file can be read back before the file is closed.
 func Create(name string, opts ...FileOption) (*File, error) {
 	fd, err := os.Create(name)
 	if err != nil {
@@ -167,6 +170,7 @@ func Create(name string, opts ...FileOption) (*File, error) {
 	}
 
 	f := &File{
+		r:           fd,
 		w:           fd,
 		closer:      fd,
 		id:          name,
@@ -428,29 +432,18 @@ func (f *File) Close() error {
 		return nil
 	}
 
-	var err error
-
-	err = f.dir.close()
+	err := f.Flush()
 	if err != nil {
 		return err
 	}
 
-	if f.w != nil {
-		err = f.writeStreamerInfo()
-		if err != nil {
-			return err
-		}
-
-		err = f.writeFreeSegments()
-		if err != nil {
-			return err
-		}
-
-		err = f.writeHeader()
-		if err != nil {
-			return err
+	for _, dep := range f.deps {
+		e := dep.Close()
+		if e != nil && err == nil {
+			err = e
 		}
 	}
+	f.deps = nil
 
 	for i := range f.dir.keys {
 		k := &f.dir.keys[i]
@@ -459,11 +452,56 @@ func (f *File) Close() error {
 	f.dir.keys = nil
 	f.dir.file = nil
 
-	err = f.closer.Close()
+	e := f.closer.Close()
+	if e != nil && err == nil {
+		err = e
+	}
 	f.closer = nil
 	return err
 }
 
+// CloseWith registers c to be closed when the file is closed.
+// CloseWith is used to tie the lifetime of resources opened on behalf of
+// objects read from the file (e.g. the files holding friend trees) to the
+// lifetime of the file.
+func (f *File) CloseWith(c io.Closer) {
+	f.deps = append(f.deps, c)
+}
+
+// Flush writes the metadata of the File (the list of keys of its
+// directories, the streamers, the list of free segments and the file header)
+// to storage.
+// A File that has been flushed can be read back, even if it has not been
+// properly closed.
+// Flush is a no-op for read-only files.
+func (f *File) Flush() error {
+	if f.w == nil {
+		return nil
+	}
+
+	err := f.dir.close()
+	if err != nil {
+		return err
+	}
+
+	err = f.writeStreamerInfo()
+	if err != nil {
+		return err
+	}
+
+	err = f.writeFreeSegments()
+	if err != nil {
+		return err
+	}
+
+	err = f.writeHeader()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // Keys returns the list of keys this File contains
 func (f *File) Keys() []Key {
 	return f.dir.Keys()
@@ -796,6 +834,20 @@ func (f *File) StreamerInfo(name string, version int) (rbytes.StreamerInfo, erro
 	return nil, fmt.Errorf("riofs: no streamer for %q", name)
 }
 
+// StreamerInfoBySum returns the named StreamerInfo with the provided checksum.
+func (f *File) StreamerInfoBySum(name string, chksum int) (rbytes.StreamerInfo, error) {
+	for _, si := range f.sinfos {
+		if si.Name() == name && si.CheckSum() == chksum {
+			return si, nil
+		}
+	}
+	si, err := rdict.StreamerInfos.StreamerInfoBySum(name, chksum)
+	if err != nil {
+		return nil, fmt.Errorf("riofs: no streamer for %q (checksum=0x%x)", name, chksum)
+	}
+	return si, nil
+}
+
 // RegisterStreamer adds the given streamer info to the list of streamers
 // that will be stored in the ROOT file.
 func (f *File) RegisterStreamer(streamer rbytes.StreamerInfo) {
@@ -837,6 +889,15 @@ func (f *File) Put(name string, v root.Object) error {
 	return f.dir.Put(name, v)
 }
 
+// Delete removes the object identified by namecycle from the file and
+// releases its space.
+func (f *File) Delete(namecycle string) error {
+	if f.w == nil {
+		return fmt.Errorf("could not delete %q from file %q: %w", namecycle, f.Name(), ErrReadOnly)
+	}
+	return f.dir.Delete(namecycle)
+}
+
 // Mkdir creates a new subdirectory
 func (f *File) Mkdir(name string) (Directory, error) {
 	if f.w == nil {
@@ -959,11 +1020,12 @@ func (f *File) Records(w io.Writer) error {
 }
 
 var (
-	_ root.Object                = (*File)(nil)
-	_ root.Named                 = (*File)(nil)
-	_ Directory                  = (*File)(nil)
-	_ rbytes.StreamerInfoContext = (*File)(nil)
-	_ streamerInfoStore          = (*File)(nil)
+	_ root.Object                   = (*File)(nil)
+	_ root.Named                    = (*File)(nil)
+	_ Directory                     = (*File)(nil)
+	_ rbytes.StreamerInfoContext    = (*File)(nil)
+	_ rbytes.StreamerInfoSumContext = (*File)(nil)
+	_ streamerInfoStore             = (*File)(nil)
 
 	_ io.Reader   = (*File)(nil)
 	_ io.ReaderAt = (*File)(nil)
@@ -47,6 +47,14 @@ type Directory interface {
 	Parent() Directory
 }
 
+// Deleter is a simple interface to remove objects from a Directory.
+type Deleter interface {
+	// Delete removes the object identified by namecycle.
+	//   namecycle has the format name;cycle
+	//   if no cycle is given, the object with the highest cycle is removed.
+	Delete(namecycle string) error
+}
+
 // SetFiler is a simple interface to establish File ownership.
 type SetFiler interface {
 	SetFile(f *File)
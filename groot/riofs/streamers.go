@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"strings"
 
+	"go-hep.org/x/hep/groot/rbase"
 	"go-hep.org/x/hep/groot/rbytes"
 	"go-hep.org/x/hep/groot/rdict"
 	"go-hep.org/x/hep/groot/rmeta"
@@ -35,7 +36,12 @@ func stdvecSIFrom(name, ename string, ctx rbytes.StreamerInfoContext) rbytes.Str
 	}
 
 	si := rdict.NewStreamerInfo(name, 1, []rbytes.StreamerElement{
-		rdict.NewStreamerSTL(name, rmeta.STLvector, rmeta.Object),
+		rdict.NewCxxStreamerSTL(rdict.Element{
+			Name:  *rbase.NewNamed(name, ""),
+			Type:  rmeta.Streamer,
+			Size:  24,
+			EName: name,
+		}.New(), rmeta.STLvector, rmeta.Object),
 	})
 	return si
 }
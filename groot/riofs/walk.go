@@ -99,6 +99,7 @@ type recDir struct {
 
 func (dir *recDir) Get(namecycle string) (root.Object, error) { return dir.get(namecycle) }
 func (dir *recDir) Put(name string, v root.Object) error      { return dir.put(name, v) }
+func (dir *recDir) Delete(namecycle string) error             { return dir.del(namecycle) }
 func (dir *recDir) Keys() []Key                               { return dir.dir.Keys() }
 func (dir *recDir) Mkdir(name string) (Directory, error)      { return dir.mkdir(name) }
 func (dir *recDir) Parent() Directory                         { return dir.dir.Parent() }
@@ -131,6 +132,29 @@ func (dir *recDir) put(name string, v root.Object) error {
 	}
 }
 
+func (dir *recDir) del(namecycle string) error {
+	pdir, n := stdpath.Split(namecycle)
+	pdir = strings.TrimRight(pdir, "/")
+	p := dir.dir
+	if pdir != "" {
+		o, err := dir.get(pdir)
+		if err != nil {
+			return fmt.Errorf("riofs: could not find parent directory %q for %q: %w", pdir, namecycle, err)
+		}
+		d, ok := o.(Directory)
+		if !ok {
+			return fmt.Errorf("riofs: %q is not a directory", pdir)
+		}
+		p = d
+	}
+
+	del, ok := p.(Deleter)
+	if !ok {
+		return fmt.Errorf("riofs: directory %T does not support deleting %q", p, namecycle)
+	}
+	return del.Delete(n)
+}
+
 func (dir *recDir) mkdir(path string) (Directory, error) {
 	if path == "" || path == "/" {
 		return nil, fmt.Errorf("riofs: invalid path %q to Mkdir", path)
@@ -0,0 +1,354 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rsqldrv // import "go-hep.org/x/hep/groot/rsql/rsqldrv"
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// aggExpr is an aggregate function expression, such as COUNT(x) or SUM(x).
+//
+// The value of an aggregate expression is computed by an aggregator,
+// accumulating values over the rows of a group.
+// Once all the rows of a group have been processed, the final value of
+// the aggregate is stored in the evaluation context, under the aggExpr key.
+type aggExpr struct {
+	expr     *sqlparser.FuncExpr
+	name     string     // lower-cased name of the aggregate function
+	arg      expression // argument of the aggregate function. nil for COUNT(*).
+	distinct bool
+}
+
+func newAggExpr(expr *sqlparser.FuncExpr, args []driver.Value) (*aggExpr, error) {
+	name := expr.Name.Lowered()
+	switch name {
+	case "count", "sum", "avg", "min", "max",
+		"std", "stddev", "stddev_pop", "stddev_samp",
+		"variance", "var_pop", "var_samp":
+	default:
+		return nil, fmt.Errorf("rsqldrv: aggregate function %q not supported", name)
+	}
+
+	if len(expr.Exprs) != 1 {
+		return nil, fmt.Errorf(
+			"rsqldrv: invalid number of arguments to %s (got=%d, want=1)",
+			name, len(expr.Exprs),
+		)
+	}
+
+	agg := &aggExpr{
+		expr:     expr,
+		name:     name,
+		distinct: expr.Distinct,
+	}
+
+	switch arg := expr.Exprs[0].(type) {
+	case *sqlparser.StarExpr:
+		if name != "count" {
+			return nil, fmt.Errorf("rsqldrv: invalid argument '*' to %s", name)
+		}
+		if agg.distinct {
+			return nil, fmt.Errorf("rsqldrv: invalid argument '*' to count(distinct)")
+		}
+	case *sqlparser.AliasedExpr:
+		v, err := newExprFrom(arg.Expr, args)
+		if err != nil {
+			return nil, err
+		}
+		if len(collectAggs(v)) != 0 {
+			return nil, fmt.Errorf("rsqldrv: nested aggregate functions in %s", sqlparser.String(expr))
+		}
+		agg.arg = v
+	default:
+		return nil, fmt.Errorf("rsqldrv: invalid argument to %s: %s", name, sqlparser.String(arg))
+	}
+
+	return agg, nil
+}
+
+func (expr *aggExpr) sql() sqlparser.Expr { return expr.expr }
+func (expr *aggExpr) isStatic() bool      { return false }
+
+func (expr *aggExpr) eval(ectx *execCtx, vctx map[interface{}]interface{}) (interface{}, error) {
+	v, ok := vctx[expr]
+	if !ok {
+		return nil, fmt.Errorf("rsqldrv: aggregate %s used outside of an aggregation", sqlparser.String(expr.expr))
+	}
+	return v, nil
+}
+
+// aggregator accumulates the values of an aggregate expression over
+// the rows of a group.
+type aggregator struct {
+	expr *aggExpr
+	seen map[string]struct{} // values already accumulated, for DISTINCT aggregates
+
+	n    int64       // number of accumulated values
+	sum  interface{} // sum of accumulated values (int64, uint64 or float64)
+	mean float64     // running mean, for std-dev and variance
+	m2   float64     // running sum of squares of differences from the mean
+	ext  interface{} // current minimum or maximum
+}
+
+func newAggregator(expr *aggExpr) *aggregator {
+	agg := &aggregator{expr: expr}
+	if expr.distinct {
+		agg.seen = make(map[string]struct{})
+	}
+	return agg
+}
+
+// accumulate updates the state of the aggregator with the values of the
+// current row.
+func (agg *aggregator) accumulate(ectx *execCtx, vctx map[interface{}]interface{}) error {
+	if agg.expr.arg == nil {
+		// COUNT(*)
+		agg.n++
+		return nil
+	}
+
+	v, err := agg.expr.arg.eval(ectx, vctx)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+
+	if agg.seen != nil {
+		key := fmt.Sprintf("%#v", v)
+		if _, dup := agg.seen[key]; dup {
+			return nil
+		}
+		agg.seen[key] = struct{}{}
+	}
+
+	switch agg.expr.name {
+	case "count":
+		agg.n++
+
+	case "min", "max":
+		if agg.ext == nil {
+			agg.ext = v
+			break
+		}
+		less, err := lessThan(v, agg.ext)
+		if err != nil {
+			return fmt.Errorf("rsqldrv: could not compare values in %s: %w", agg.expr.name, err)
+		}
+		if agg.expr.name == "max" {
+			less, err = lessThan(agg.ext, v)
+			if err != nil {
+				return fmt.Errorf("rsqldrv: could not compare values in %s: %w", agg.expr.name, err)
+			}
+		}
+		if less {
+			agg.ext = v
+		}
+
+	case "sum":
+		agg.n++
+		agg.sum, err = addValues(agg.sum, v)
+		if err != nil {
+			return fmt.Errorf("rsqldrv: could not sum values: %w", err)
+		}
+
+	default:
+		// avg, std-dev and variance.
+		x, err := toFloat64(v)
+		if err != nil {
+			return fmt.Errorf("rsqldrv: invalid argument to %s: %w", agg.expr.name, err)
+		}
+		// Welford's online algorithm.
+		agg.n++
+		delta := x - agg.mean
+		agg.mean += delta / float64(agg.n)
+		agg.m2 += delta * (x - agg.mean)
+	}
+
+	return nil
+}
+
+// value returns the final value of the aggregate.
+// Aggregates over an empty set of values (except COUNT) are NULL.
+func (agg *aggregator) value() interface{} {
+	switch agg.expr.name {
+	case "count":
+		return agg.n
+	case "min", "max":
+		return agg.ext
+	case "sum":
+		return agg.sum
+	}
+
+	switch agg.expr.name {
+	case "avg":
+		if agg.n == 0 {
+			return nil
+		}
+		return agg.mean
+	case "std", "stddev", "stddev_pop":
+		if agg.n == 0 {
+			return nil
+		}
+		return math.Sqrt(agg.m2 / float64(agg.n))
+	case "variance", "var_pop":
+		if agg.n == 0 {
+			return nil
+		}
+		return agg.m2 / float64(agg.n)
+	case "stddev_samp":
+		if agg.n < 2 {
+			return nil
+		}
+		return math.Sqrt(agg.m2 / float64(agg.n-1))
+	case "var_samp":
+		if agg.n < 2 {
+			return nil
+		}
+		return agg.m2 / float64(agg.n-1)
+	}
+	panic("impossible")
+}
+
+// collectAggs returns the aggregate expressions contained in the provided
+// expression.
+func collectAggs(expr expression) []*aggExpr {
+	switch expr := expr.(type) {
+	case *aggExpr:
+		return []*aggExpr{expr}
+	case *binExpr:
+		return append(collectAggs(expr.l), collectAggs(expr.r)...)
+	case *unaryExpr:
+		return collectAggs(expr.v)
+	case *tupleExpr:
+		var aggs []*aggExpr
+		for _, e := range expr.exprs {
+			aggs = append(aggs, collectAggs(e)...)
+		}
+		return aggs
+	}
+	return nil
+}
+
+// toFloat64 converts a numerical value to a float64.
+func toFloat64(v interface{}) (float64, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	}
+	return 0, fmt.Errorf("non-numerical value %#v (%T)", v, v)
+}
+
+// addValues adds the numerical value v to the sum.
+// Sums of signed (resp. unsigned) integers are int64 (resp. uint64) values,
+// sums involving floating point values are float64 values.
+func addValues(sum, v interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch s := sum.(type) {
+		case nil:
+			return rv.Int(), nil
+		case int64:
+			return s + rv.Int(), nil
+		case uint64:
+			return int64(s) + rv.Int(), nil
+		case float64:
+			return s + float64(rv.Int()), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch s := sum.(type) {
+		case nil:
+			return rv.Uint(), nil
+		case int64:
+			return s + int64(rv.Uint()), nil
+		case uint64:
+			return s + rv.Uint(), nil
+		case float64:
+			return s + float64(rv.Uint()), nil
+		}
+	case reflect.Float32, reflect.Float64:
+		switch s := sum.(type) {
+		case nil:
+			return rv.Float(), nil
+		case int64:
+			return float64(s) + rv.Float(), nil
+		case uint64:
+			return float64(s) + rv.Float(), nil
+		case float64:
+			return s + rv.Float(), nil
+		}
+	}
+	return nil, fmt.Errorf("non-numerical value %#v (%T)", v, v)
+}
+
+// lessThan reports whether a sorts before b.
+// NULL values sort before any other value.
+func lessThan(a, b interface{}) (bool, error) {
+	switch {
+	case a == nil:
+		return b != nil, nil
+	case b == nil:
+		return false, nil
+	}
+
+	var (
+		ra = reflect.ValueOf(a)
+		rb = reflect.ValueOf(b)
+	)
+
+	switch {
+	case ra.Kind() == reflect.String && rb.Kind() == reflect.String:
+		return strings.Compare(ra.String(), rb.String()) < 0, nil
+	case isInt(ra) && isInt(rb):
+		return ra.Int() < rb.Int(), nil
+	case isUint(ra) && isUint(rb):
+		return ra.Uint() < rb.Uint(), nil
+	case ra.Kind() == reflect.Bool && rb.Kind() == reflect.Bool:
+		return !ra.Bool() && rb.Bool(), nil
+	}
+
+	fa, err := toFloat64(a)
+	if err != nil {
+		return false, fmt.Errorf("could not compare %T and %T", a, b)
+	}
+	fb, err := toFloat64(b)
+	if err != nil {
+		return false, fmt.Errorf("could not compare %T and %T", a, b)
+	}
+	return fa < fb, nil
+}
+
+func isInt(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUint(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+var (
+	_ expression = (*aggExpr)(nil)
+)
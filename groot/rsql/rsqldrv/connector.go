@@ -24,6 +24,7 @@ func Connector(file *riofs.File) driver.Connector {
 type rootConnector struct {
 	drv  rootDriver
 	file *riofs.File
+	owns bool // whether the connector owns the ROOT file (and needs to close it)
 }
 
 // Connect returns a connection to the database.
@@ -38,7 +39,7 @@ type rootConnector struct {
 // The returned connection is only used by one goroutine at a
 // time.
 func (c *rootConnector) Connect(ctx context.Context) (driver.Conn, error) {
-	return c.drv.connect(c.file), nil
+	return c.drv.connect(c.file, c.owns), nil
 }
 
 // Driver returns the underlying Driver of the Connector,
@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"sort"
+	"strconv"
 	"sync"
 
 	"github.com/xwb1989/sqlparser"
@@ -34,8 +36,17 @@ func Open(name string) (*sql.DB, error) {
 // Create is a ROOT/SQL-driver helper function for sql.Open.
 //
 // It creates a new ROOT file, connected via the ROOT/SQL driver.
+// Tables can then be created with CREATE TABLE statements and filled with
+// INSERT INTO statements.
+// The ROOT file is closed (and the trees backing the tables written out)
+// when the returned database is closed.
 func Create(name string) (*sql.DB, error) {
-	panic("not implemented") // FIXME(sbinet)
+	f, err := riofs.Create(name)
+	if err != nil {
+		return nil, fmt.Errorf("rsqldrv: could not create file: %w", err)
+	}
+
+	return sql.OpenDB(&rootConnector{file: f, owns: true}), nil
 }
 
 // rootDriver implements the interface required by database/sql/driver.
@@ -78,7 +89,7 @@ func (drv *rootDriver) open(fname string) (driver.Conn, error) {
 	return conn, nil
 }
 
-func (drv *rootDriver) connect(f *riofs.File) driver.Conn {
+func (drv *rootDriver) connect(f *riofs.File, owns bool) driver.Conn {
 	drv.mu.Lock()
 	defer drv.mu.Unlock()
 	if drv.dbs == nil {
@@ -98,7 +109,7 @@ func (drv *rootDriver) connect(f *riofs.File) driver.Conn {
 			refs: 0,
 		}
 		drv.dbs[f.Name()] = conn
-		drv.owns[f.Name()] = false
+		drv.owns[f.Name()] = owns
 	}
 	conn.refs++
 
@@ -124,6 +135,9 @@ type driverConn struct {
 	stop map[*driverStmt]struct{}
 	refs int
 
+	mu     sync.Mutex
+	tables []*table // tables created with CREATE TABLE
+
 	tx driver.Tx
 }
 
@@ -163,6 +177,14 @@ func (conn *driverConn) Close() error {
 		}
 	}
 
+	for _, t := range conn.tables {
+		err := t.close()
+		if err != nil {
+			return fmt.Errorf("rsqldrv: could not close table %q: %w", t.name, err)
+		}
+	}
+	conn.tables = nil
+
 	var err error
 	if conn.drv.owns[conn.f.Name()] {
 		err = conn.f.Close()
@@ -202,7 +224,13 @@ func (conn *driverConn) Exec(query string, args []driver.Value) (driver.Result,
 }
 
 func (conn *driverConn) exec(stmt sqlparser.Statement, args []driver.Value) (driver.Result, error) {
-	panic("not implemented")
+	switch stmt := stmt.(type) {
+	case *sqlparser.DDL:
+		return conn.execDDL(stmt)
+	case *sqlparser.Insert:
+		return conn.execInsert(stmt, args)
+	}
+	return nil, fmt.Errorf("rsqldrv: statement %T not supported", stmt)
 }
 
 func (conn *driverConn) Query(query string, args []driver.Value) (driver.Rows, error) {
@@ -219,7 +247,7 @@ func (conn *driverConn) query(stmt sqlparser.Statement, args []driver.Value) (dr
 		rows, err := newDriverRows(conn, stmt, args)
 		return rows, err
 	}
-	panic("not implemented")
+	return nil, fmt.Errorf("rsqldrv: query %T not supported", stmt)
 }
 
 type driverResult struct {
@@ -235,13 +263,34 @@ type driverRows struct {
 	conn  *driverConn
 	args  []driver.Value
 	cols  []string
-	types []colDescr    // types of the columns
-	deps  []string      // names of the columns to be read
-	vars  []interface{} // values of the columns that were read
+	types []colDescr // types of the columns
 
-	cursor *rtree.TreeScanner
+	src    rowSource // rows read from the tables of the query
 	eval   expression
 	filter expression
+
+	exprs  []expression // expressions of the result columns
+	alias  []string     // aliases of the result columns
+	groups []expression // GROUP BY expressions
+	having expression   // HAVING filter expression
+	aggs   []*aggExpr   // aggregate expressions
+	order  []orderExpr  // ORDER BY expressions
+
+	offset int64 // number of result rows to skip
+	limit  int64 // maximum number of result rows (-1 if no limit)
+	nrows  int64 // number of result rows returned so far
+	nskip  int64 // number of result rows skipped so far
+
+	buffered bool            // whether results are computed before being returned
+	loaded   bool            // whether buffered results have been computed
+	results  [][]interface{} // buffered results
+}
+
+// orderExpr describes an ORDER BY expression.
+type orderExpr struct {
+	expr expression
+	col  int // index of the result column to order by, -1 if none
+	desc bool
 }
 
 type colDescr struct {
@@ -252,43 +301,14 @@ type colDescr struct {
 }
 
 func newDriverRows(conn *driverConn, stmt *sqlparser.Select, args []driver.Value) (*driverRows, error) {
-	var (
-		name = ""
-		f    = conn.f
-	)
-
-	switch len(stmt.From) {
-	case 1:
-		switch from := stmt.From[0].(type) {
-		case *sqlparser.AliasedTableExpr:
-			switch expr := from.Expr.(type) {
-			case sqlparser.TableName:
-				name = expr.Name.CompliantName()
-			default:
-				panic(fmt.Errorf("unknown FROM expression type: %#v", expr))
-			}
-
-		default:
-			panic(fmt.Errorf("unknown table expression: %#v", from))
-		}
-
-	default:
-		return nil, fmt.Errorf("rsqldrv: invalid number of tables (got=%d, want=1)", len(stmt.From))
-	}
-
-	obj, err := riofs.Dir(f).Get(name)
+	refs, err := conn.tablesFrom(stmt.From)
 	if err != nil {
 		return nil, err
 	}
 
-	tree, ok := obj.(rtree.Tree)
-	if !ok {
-		return nil, fmt.Errorf("rsqldrv: object %q is not a Tree", name)
-	}
-
-	rows := &driverRows{conn: conn, args: args}
+	rows := &driverRows{conn: conn, args: args, limit: -1}
 
-	rows.cols, err = rows.extractColsFromSelect(tree, stmt, args)
+	rows.cols, err = rows.extractColsFromSelect(refs, stmt, args)
 	if err != nil {
 		return nil, fmt.Errorf("could not extract columns: %w", err)
 	}
@@ -299,7 +319,7 @@ func newDriverRows(conn *driverConn, stmt *sqlparser.Select, args []driver.Value
 			continue
 		}
 		rows.types[i].Name = name
-		branch := tree.Branch(name)
+		branch := refs.branch(name)
 		if branch == nil {
 			rows.types[i].Type = reflect.TypeOf(new(interface{})).Elem()
 			continue
@@ -307,36 +327,87 @@ func newDriverRows(conn *driverConn, stmt *sqlparser.Select, args []driver.Value
 		rows.types[i] = colDescrFromLeaf(branch.Leaves()[0]) // FIXME(sbinet): multi-leaves' branches
 	}
 
-	vars, err := rows.extractDepsFromSelect(tree, stmt, args)
+	vars, err := rows.extractDepsFromSelect(refs, stmt, args)
 	if err != nil {
 		return nil, fmt.Errorf("could not extract read-vars: %w", err)
 	}
-	rows.vars = varsFrom(vars)
-	for _, v := range vars {
-		rows.deps = append(rows.deps, v.Name)
-	}
 
-	rows.cursor, err = rtree.NewTreeScannerVars(tree, vars...)
-	if err != nil {
-		return nil, err
-	}
+	switch {
+	case refs.joined:
+		var (
+			lhs = refs.tables[0]
+			rhs = refs.tables[1]
+			src = &joinSource{outer: refs.kind == sqlparser.LeftJoinStr}
+		)
+		for i := range refs.lkeys {
+			src.lkeys = append(src.lkeys, newIdentExpr(refs.lkeys[i]))
+			src.rkeys = append(src.rkeys, newIdentExpr(refs.rkeys[i]))
+		}
+		src.left, err = newTreeSource(lhs.tree, vars[0], lhs.name)
+		if err != nil {
+			return nil, err
+		}
+		src.right, err = newTreeSource(rhs.tree, vars[1], rhs.name)
+		if err != nil {
+			_ = src.left.close()
+			return nil, err
+		}
+		rows.src = src
 
-	switch expr := stmt.SelectExprs[0].(type) { // FIXME(sbinet): handle multiple select-expressions
-	case *sqlparser.AliasedExpr:
-		rows.eval, err = newExprFrom(expr.Expr, args)
+	default:
+		rows.src, err = newTreeSource(refs.tables[0].tree, vars[0], "")
 		if err != nil {
-			return nil, fmt.Errorf("could not generate row expression: %w", err)
+			return nil, err
 		}
-	case *sqlparser.StarExpr:
-		tuple := make(sqlparser.ValTuple, len(rows.cols))
-		for i, name := range rows.cols {
-			tuple[i] = &sqlparser.ColName{Name: sqlparser.NewColIdent(name)}
+	}
+
+	var tuple sqlparser.ValTuple
+	for _, expr := range stmt.SelectExprs {
+		switch expr := expr.(type) {
+		case *sqlparser.AliasedExpr:
+			exprs := sqlparser.Exprs{expr.Expr}
+			if tup, ok := unparen(expr.Expr).(sqlparser.ValTuple); ok {
+				exprs = sqlparser.Exprs(tup)
+			}
+			for _, expr := range exprs {
+				tuple = append(tuple, expr)
+			}
+			alias := ""
+			if len(exprs) == 1 {
+				alias = expr.As.CompliantName()
+			}
+			for range exprs {
+				rows.alias = append(rows.alias, alias)
+			}
+		case *sqlparser.StarExpr:
+			tables, err := refs.starTables(expr)
+			if err != nil {
+				return nil, err
+			}
+			for _, i := range tables {
+				for _, b := range refs.tables[i].tree.Branches() {
+					col := &sqlparser.ColName{Name: sqlparser.NewColIdent(b.Name())}
+					if refs.joined {
+						col = refs.qualified(i, b.Name())
+					}
+					tuple = append(tuple, col)
+					rows.alias = append(rows.alias, "")
+				}
+			}
+		default:
+			return nil, fmt.Errorf("rsqldrv: invalid select-expr type %#v", expr)
 		}
-		rows.eval, err = newExprFrom(tuple, args)
+	}
+
+	rows.exprs = make([]expression, len(tuple))
+	for i, expr := range tuple {
+		rows.exprs[i], err = newExprFrom(expr, args)
 		if err != nil {
-			return nil, fmt.Errorf("could not generate row expression from 'select *': %w", err)
+			return nil, fmt.Errorf("could not generate row expression: %w", err)
 		}
+		rows.aggs = append(rows.aggs, collectAggs(rows.exprs[i])...)
 	}
+	rows.eval = &tupleExpr{expr: tuple, exprs: rows.exprs}
 
 	if stmt.Where != nil {
 		switch stmt.Where.Type {
@@ -345,14 +416,157 @@ func newDriverRows(conn *driverConn, stmt *sqlparser.Select, args []driver.Value
 			if err != nil {
 				return nil, err
 			}
+			if len(collectAggs(rows.filter)) != 0 {
+				return nil, fmt.Errorf("rsqldrv: invalid use of aggregate function in WHERE clause")
+			}
 		default:
 			panic(fmt.Errorf("unknown 'where' type: %q", stmt.Where.Type))
 		}
 	}
 
+	for _, expr := range stmt.GroupBy {
+		v, err := rows.resolve(refs, expr, args)
+		if err != nil {
+			return nil, fmt.Errorf("could not generate GROUP BY expression: %w", err)
+		}
+		if len(collectAggs(v)) != 0 {
+			return nil, fmt.Errorf("rsqldrv: invalid use of aggregate function in GROUP BY clause")
+		}
+		rows.groups = append(rows.groups, v)
+	}
+
+	if stmt.Having != nil {
+		rows.having, err = newExprFrom(stmt.Having.Expr, args)
+		if err != nil {
+			return nil, fmt.Errorf("could not generate HAVING expression: %w", err)
+		}
+		rows.aggs = append(rows.aggs, collectAggs(rows.having)...)
+	}
+
+	for _, order := range stmt.OrderBy {
+		o := orderExpr{col: -1, desc: order.Direction == sqlparser.DescScr}
+		switch i, ok := position(order.Expr); {
+		case ok:
+			if i < 0 || i >= len(rows.exprs) {
+				return nil, fmt.Errorf("rsqldrv: invalid ORDER BY column position %d", i+1)
+			}
+			o.col = i
+		default:
+			o.expr, err = newExprFrom(order.Expr, args)
+			if err != nil {
+				return nil, fmt.Errorf("could not generate ORDER BY expression: %w", err)
+			}
+			rows.aggs = append(rows.aggs, collectAggs(o.expr)...)
+		}
+		rows.order = append(rows.order, o)
+	}
+
+	if stmt.Limit != nil {
+		if stmt.Limit.Offset != nil {
+			rows.offset, err = limitFrom(stmt.Limit.Offset, args)
+			if err != nil {
+				return nil, fmt.Errorf("could not evaluate LIMIT offset: %w", err)
+			}
+		}
+		rows.limit, err = limitFrom(stmt.Limit.Rowcount, args)
+		if err != nil {
+			return nil, fmt.Errorf("could not evaluate LIMIT row count: %w", err)
+		}
+	}
+
+	if len(rows.order) == 1 && !refs.joined && len(rows.groups) == 0 && len(rows.aggs) == 0 {
+		if _, ok := rows.order[0].expr.(*idExpr); ok && !rows.order[0].desc {
+			// entries are already read in order.
+			rows.order = nil
+		}
+	}
+
+	if rows.having != nil && len(rows.groups) == 0 && len(rows.aggs) == 0 {
+		return nil, fmt.Errorf("rsqldrv: HAVING clause without aggregation")
+	}
+
+	rows.buffered = len(rows.aggs) > 0 || len(rows.groups) > 0 || len(rows.order) > 0
+
 	return rows, nil
 }
 
+// resolve returns the expression of a GROUP BY clause.
+// GROUP BY clauses may refer to result columns by position or by alias.
+func (rows *driverRows) resolve(refs *tableRefs, expr sqlparser.Expr, args []driver.Value) (expression, error) {
+	if i, ok := position(expr); ok {
+		if i < 0 || i >= len(rows.exprs) {
+			return nil, fmt.Errorf("rsqldrv: invalid column position %d", i+1)
+		}
+		return rows.exprs[i], nil
+	}
+
+	if col, ok := expr.(*sqlparser.ColName); ok && col.Qualifier.IsEmpty() {
+		name := col.Name.CompliantName()
+		if !refs.has(name) {
+			for i, alias := range rows.alias {
+				if alias == name {
+					return rows.exprs[i], nil
+				}
+			}
+		}
+	}
+
+	return newExprFrom(expr, args)
+}
+
+// unparen returns the expression enclosed in parentheses, if any.
+func unparen(expr sqlparser.Expr) sqlparser.Expr {
+	for {
+		p, ok := expr.(*sqlparser.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = p.Expr
+	}
+}
+
+// position returns the 0-based column index of a positional reference
+// to a result column, as used in GROUP BY and ORDER BY clauses.
+func position(expr sqlparser.Expr) (int, bool) {
+	v, ok := expr.(*sqlparser.SQLVal)
+	if !ok || v.Type != sqlparser.IntVal {
+		return 0, false
+	}
+	i, err := strconv.Atoi(string(v.Val))
+	if err != nil {
+		return 0, false
+	}
+	return i - 1, true
+}
+
+// limitFrom evaluates the offset or row count of a LIMIT clause.
+func limitFrom(expr sqlparser.Expr, args []driver.Value) (int64, error) {
+	v, err := newExprFrom(expr, args)
+	if err != nil {
+		return 0, err
+	}
+	if !v.isStatic() {
+		return 0, fmt.Errorf("rsqldrv: invalid non-constant LIMIT expression %s", sqlparser.String(expr))
+	}
+	o, err := v.eval(nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	switch o := o.(type) {
+	case idealInt:
+		n = int64(o)
+	case idealUint:
+		n = int64(o)
+	default:
+		return 0, fmt.Errorf("rsqldrv: invalid LIMIT value %v (%T)", o, o)
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("rsqldrv: invalid negative LIMIT value %d", n)
+	}
+	return n, nil
+}
+
 func varsFrom(vars []rtree.ReadVar) []interface{} {
 	vs := make([]interface{}, len(vars))
 	for i, v := range vars {
@@ -363,42 +577,87 @@ func varsFrom(vars []rtree.ReadVar) []interface{} {
 
 // extractDepsFromSelect analyses the query and extracts the branches that need to be read
 // for the query to be properly executed.
-func (rows *driverRows) extractDepsFromSelect(tree rtree.Tree, stmt *sqlparser.Select, args []driver.Value) ([]rtree.ReadVar, error) {
+// extractDepsFromSelect returns the read-vars of each table of the query.
+func (rows *driverRows) extractDepsFromSelect(refs *tableRefs, stmt *sqlparser.Select, args []driver.Value) ([][]rtree.ReadVar, error) {
 	var (
-		vars []rtree.ReadVar
+		vars = make([][]rtree.ReadVar, len(refs.tables))
 
-		set  = make(map[string]struct{})
-		cols []string
+		set  = make([]map[string]struct{}, len(refs.tables))
+		cols = make([][]string, len(refs.tables))
+
+		aliases = make(map[string]struct{})
 	)
 
-	markBranch := func(name string) {
+	for i := range set {
+		set[i] = make(map[string]struct{})
+	}
+
+	for _, expr := range stmt.SelectExprs {
+		if expr, ok := expr.(*sqlparser.AliasedExpr); ok && !expr.As.IsEmpty() {
+			aliases[expr.As.CompliantName()] = struct{}{}
+		}
+	}
+
+	markBranch := func(i int, name string) {
 		if name != "" {
-			if _, dup := set[name]; !dup {
-				set[name] = struct{}{}
-				cols = append(cols, name)
+			if _, dup := set[i][name]; !dup {
+				set[i][name] = struct{}{}
+				cols[i] = append(cols[i], name)
 			}
 		}
 	}
 
-	collectCols := func(node sqlparser.SQLNode) (bool, error) {
+	markCol := func(col *sqlparser.ColName) error {
+		i, err := refs.lookup(col)
+		if err != nil {
+			return err
+		}
+		name := col.Name.CompliantName()
+		if i < 0 {
+			return fmt.Errorf("rsqldrv: could not find branch/leaf %q in joined trees", name)
+		}
+		markBranch(i, name)
+		return nil
+	}
+
+	var collectCols func(node sqlparser.SQLNode) (bool, error)
+	collectCols = func(node sqlparser.SQLNode) (bool, error) {
 		switch node := node.(type) {
 		case *sqlparser.StarExpr:
-			other := node.TableName.Name.CompliantName()
-			switch other {
-			case "", tree.Name():
-				for _, b := range tree.Branches() {
-					markBranch(b.Name())
+			tables, err := refs.starTables(node)
+			if err != nil {
+				return false, err
+			}
+			for _, i := range tables {
+				for _, b := range refs.tables[i].tree.Branches() {
+					markBranch(i, b.Name())
 				}
-			default:
-				panic(fmt.Errorf("rsqldrv: star-expression with other table name not supported"))
 			}
 			return false, nil
 
-		case sqlparser.ColIdent:
-			name := node.CompliantName()
-			markBranch(name)
+		case *sqlparser.AliasedExpr:
+			// do not consider aliases as branch names.
+			return false, sqlparser.Walk(collectCols, node.Expr)
+
+		case *sqlparser.FuncExpr:
+			// do not consider function names (nor COUNT(*)) as branch names.
+			for _, arg := range node.Exprs {
+				if arg, ok := arg.(*sqlparser.AliasedExpr); ok {
+					err := sqlparser.Walk(collectCols, arg.Expr)
+					if err != nil {
+						return false, err
+					}
+				}
+			}
 			return false, nil
 
+		case *sqlparser.ColName:
+			name := node.Name.CompliantName()
+			if _, ok := aliases[name]; ok && node.Qualifier.IsEmpty() && !refs.has(name) {
+				return false, nil
+			}
+			return false, markCol(node)
+
 		default:
 			return true, nil
 		}
@@ -413,53 +672,83 @@ func (rows *driverRows) extractDepsFromSelect(tree rtree.Tree, stmt *sqlparser.S
 		nodes = append(nodes, stmt.Where.Expr)
 	}
 
+	for _, expr := range stmt.GroupBy {
+		nodes = append(nodes, expr)
+	}
+
+	if stmt.Having != nil {
+		nodes = append(nodes, stmt.Having.Expr)
+	}
+
+	for _, order := range stmt.OrderBy {
+		nodes = append(nodes, order.Expr)
+	}
+
 	err := sqlparser.Walk(collectCols, nodes...)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, name := range cols {
-		branch := tree.Branch(name)
-		if branch == nil {
-			return nil, fmt.Errorf("rsqldrv: could not find branch/leaf %q in tree %q", name, tree.Name())
-		}
-		leaf := branch.Leaves()[0] // FIXME(sbinet): handle sub-leaves
-		etyp := leaf.Type()
-		switch etyp.Kind() {
-		case reflect.Int8:
-			if leaf.IsUnsigned() {
-				etyp = reflect.TypeOf(uint8(0))
-			}
-		case reflect.Int16:
-			if leaf.IsUnsigned() {
-				etyp = reflect.TypeOf(uint16(0))
-			}
-		case reflect.Int32:
-			if leaf.IsUnsigned() {
-				etyp = reflect.TypeOf(uint32(0))
-			}
-		case reflect.Int64:
-			if leaf.IsUnsigned() {
-				etyp = reflect.TypeOf(uint64(0))
-			}
-		}
-		switch {
-		case leaf.LeafCount() != nil:
-			etyp = reflect.SliceOf(etyp)
-		case leaf.Len() > 1 && leaf.Kind() != reflect.String:
-			etyp = reflect.ArrayOf(leaf.Len(), etyp)
-		}
-		vars = append(vars, rtree.ReadVar{
-			Name:  branch.Name(),
-			Leaf:  leaf.Name(),
-			Value: reflect.New(etyp).Interface(),
-		})
+	for i := range refs.lkeys {
+		for _, col := range []*sqlparser.ColName{refs.lkeys[i], refs.rkeys[i]} {
+			err := markCol(col)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for i, ref := range refs.tables {
+		tree := ref.tree
+		if len(cols[i]) == 0 && len(tree.Branches()) > 0 {
+			// queries such as 'SELECT COUNT(*) FROM tree' still need to
+			// iterate over the entries of the tree.
+			markBranch(i, tree.Branches()[0].Name())
+		}
+
+		for _, name := range cols[i] {
+			branch := tree.Branch(name)
+			if branch == nil {
+				return nil, fmt.Errorf("rsqldrv: could not find branch/leaf %q in tree %q", name, tree.Name())
+			}
+			leaf := branch.Leaves()[0] // FIXME(sbinet): handle sub-leaves
+			etyp := leaf.Type()
+			switch etyp.Kind() {
+			case reflect.Int8:
+				if leaf.IsUnsigned() {
+					etyp = reflect.TypeOf(uint8(0))
+				}
+			case reflect.Int16:
+				if leaf.IsUnsigned() {
+					etyp = reflect.TypeOf(uint16(0))
+				}
+			case reflect.Int32:
+				if leaf.IsUnsigned() {
+					etyp = reflect.TypeOf(uint32(0))
+				}
+			case reflect.Int64:
+				if leaf.IsUnsigned() {
+					etyp = reflect.TypeOf(uint64(0))
+				}
+			}
+			switch {
+			case leaf.LeafCount() != nil:
+				etyp = reflect.SliceOf(etyp)
+			case leaf.Len() > 1 && leaf.Kind() != reflect.String:
+				etyp = reflect.ArrayOf(leaf.Len(), etyp)
+			}
+			vars[i] = append(vars[i], rtree.ReadVar{
+				Name:  branch.Name(),
+				Leaf:  leaf.Name(),
+				Value: reflect.New(etyp).Interface(),
+			})
+		}
 	}
 
 	return vars, nil
 }
 
-func (rows *driverRows) extractColsFromSelect(tree rtree.Tree, stmt *sqlparser.Select, args []driver.Value) ([]string, error) {
+func (rows *driverRows) extractColsFromSelect(refs *tableRefs, stmt *sqlparser.Select, args []driver.Value) ([]string, error) {
 	var cols []string
 
 	collect := func(node sqlparser.SQLNode) (bool, error) {
@@ -487,25 +776,44 @@ func (rows *driverRows) extractColsFromSelect(tree rtree.Tree, stmt *sqlparser.S
 			// add a dummy column name and stop recursion
 			cols = append(cols, "")
 			return false, nil
+		case *sqlparser.FuncExpr:
+			// not a simple select query.
+			// add a dummy column name and stop recursion
+			cols = append(cols, "")
+			return false, nil
 		}
 		return false, nil
 	}
 
-	switch expr := stmt.SelectExprs[0].(type) { // FIXME(sbinet): handle multiple select-expressions
-	case *sqlparser.AliasedExpr:
-		err := sqlparser.Walk(collect, expr.Expr)
-		return cols, err
+	for _, expr := range stmt.SelectExprs {
+		switch expr := expr.(type) {
+		case *sqlparser.AliasedExpr:
+			if _, ok := unparen(expr.Expr).(sqlparser.ValTuple); !ok && !expr.As.IsEmpty() {
+				cols = append(cols, expr.As.CompliantName())
+				continue
+			}
+			err := sqlparser.Walk(collect, expr.Expr)
+			if err != nil {
+				return nil, err
+			}
 
-	case *sqlparser.StarExpr:
-		branches := make([]string, len(tree.Branches()))
-		for i, b := range tree.Branches() {
-			branches[i] = b.Name()
-		}
-		return branches, nil
+		case *sqlparser.StarExpr:
+			tables, err := refs.starTables(expr)
+			if err != nil {
+				return nil, err
+			}
+			for _, i := range tables {
+				for _, b := range refs.tables[i].tree.Branches() {
+					cols = append(cols, b.Name())
+				}
+			}
 
-	default:
-		panic(fmt.Errorf("rsqldrv: invalid select-expr type %#v", expr))
+		default:
+			panic(fmt.Errorf("rsqldrv: invalid select-expr type %#v", expr))
+		}
 	}
+
+	return cols, nil
 }
 
 // Columns returns the names of the columns. The number of columns of the
@@ -545,7 +853,7 @@ func (r *driverRows) ColumnTypeNullable(i int) (nullable, ok bool) {
 
 // Close closes the rows iterator.
 func (r *driverRows) Close() error {
-	return r.cursor.Close()
+	return r.src.close()
 }
 
 // Next is called to populate the next row of data into
@@ -558,78 +866,302 @@ func (r *driverRows) Close() error {
 // should be taken when closing Rows not to modify
 // a buffer held in dest.
 func (r *driverRows) Next(dest []driver.Value) error {
-	if !r.cursor.Next() {
+	if r.limit >= 0 && r.nrows >= r.limit {
 		return io.EOF
 	}
-	err := r.cursor.Scan(r.vars...)
-	if err != nil {
-		return err
+
+	var vs []interface{}
+	switch {
+	case r.buffered:
+		if !r.loaded {
+			err := r.load()
+			if err != nil {
+				return err
+			}
+		}
+		if len(r.results) == 0 {
+			return io.EOF
+		}
+		vs = r.results[0]
+		r.results = r.results[1:]
+
+	default:
+		for {
+			ectx := newExecCtx(r.conn, r.args)
+			vctx, err := r.next(ectx)
+			if err != nil {
+				return err
+			}
+			if r.nskip < r.offset {
+				r.nskip++
+				continue
+			}
+			vs, err = r.values(ectx, vctx)
+			if err != nil {
+				return err
+			}
+			break
+		}
 	}
+	r.nrows++
 
-	ectx := newExecCtx(r.conn, r.args)
-	vctx := make(map[interface{}]interface{})
-	for i, v := range r.vars {
-		vctx[r.deps[i]] = reflect.Indirect(reflect.ValueOf(v)).Interface()
+	for i, v := range vs {
+		switch v := v.(type) {
+		case string:
+			dest[i] = []byte(v)
+		default:
+			dest[i] = v
+		}
 	}
 
-	switch r.filter {
-	case nil:
-		// no filter
-	default:
-		ok, err := r.filter.eval(ectx, vctx)
+	return nil
+}
+
+// next advances to the next row passing the WHERE filter,
+// and returns the values of the columns of that row.
+func (r *driverRows) next(ectx *execCtx) (map[interface{}]interface{}, error) {
+	for {
+		vctx, err := r.src.next()
 		if err != nil {
-			//log.Printf("filter.eval: ok=%#v err=%v", ok, err)
-			return err
+			return nil, err
 		}
-		if !ok.(bool) {
-			return r.Next(dest)
+
+		if r.filter != nil {
+			ok, err := r.filter.eval(ectx, vctx)
+			if err != nil {
+				return nil, err
+			}
+			if ok, _ := ok.(bool); !ok {
+				continue
+			}
 		}
+
+		return vctx, nil
 	}
+}
 
+// values evaluates the result columns.
+func (r *driverRows) values(ectx *execCtx, vctx map[interface{}]interface{}) ([]interface{}, error) {
 	vs, err := r.eval.eval(ectx, vctx)
-	// log.Printf("row.eval: v=%#v, err=%v n=%d", vs, err, len(dest))
 	if err != nil {
-		return fmt.Errorf("could not evaluate row values: %w", err)
+		return nil, fmt.Errorf("could not evaluate row values: %w", err)
 	}
+	return vs.([]interface{}), nil
+}
+
+// group holds the state of a GROUP BY group.
+type group struct {
+	vctx map[interface{}]interface{} // column values of the first row of the group
+	aggs []*aggregator
+}
+
+// load computes all the result rows of a query with aggregate functions,
+// GROUP BY or ORDER BY clauses.
+//
+// Rows are aggregated as they are read, so only the state of each group
+// (and not all the rows of each group) is kept in memory.
+func (r *driverRows) load() error {
+	r.loaded = true
 
-	switch vs := vs.(type) {
-	case []interface{}:
-		for i, v := range vs {
-			switch v := v.(type) {
-			case string:
-				dest[i] = []byte(v)
+	var (
+		ectx   = newExecCtx(r.conn, r.args)
+		rows   []map[interface{}]interface{}
+		groups []*group
+		index  = make(map[string]*group)
+		aggr   = len(r.aggs) > 0 || len(r.groups) > 0
+	)
+
+	newGroup := func(vctx map[interface{}]interface{}) *group {
+		grp := &group{
+			vctx: vctx,
+			aggs: make([]*aggregator, len(r.aggs)),
+		}
+		for i, agg := range r.aggs {
+			grp.aggs[i] = newAggregator(agg)
+		}
+		groups = append(groups, grp)
+		return grp
+	}
+
+	if aggr && len(r.groups) == 0 {
+		// aggregates over the whole table yield exactly one row.
+		newGroup(make(map[interface{}]interface{}))
+	}
+
+	for {
+		vctx, err := r.next(ectx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if !aggr {
+			rows = append(rows, copyValues(vctx))
+			continue
+		}
+
+		var grp *group
+		switch len(r.groups) {
+		case 0:
+			grp = groups[0]
+		default:
+			keys := make([]interface{}, len(r.groups))
+			for i, expr := range r.groups {
+				keys[i], err = expr.eval(ectx, vctx)
+				if err != nil {
+					return fmt.Errorf("could not evaluate GROUP BY expression: %w", err)
+				}
+			}
+			key := fmt.Sprintf("%#v", keys)
+			grp = index[key]
+			if grp == nil {
+				grp = newGroup(copyValues(vctx))
+				index[key] = grp
+			}
+		}
+
+		for _, agg := range grp.aggs {
+			err = agg.accumulate(ectx, vctx)
+			if err != nil {
+				return fmt.Errorf("could not evaluate aggregate: %w", err)
+			}
+		}
+	}
+
+	if aggr {
+		rows = make([]map[interface{}]interface{}, 0, len(groups))
+		for _, grp := range groups {
+			for _, agg := range grp.aggs {
+				grp.vctx[agg.expr] = agg.value()
+			}
+			rows = append(rows, grp.vctx)
+		}
+	}
+
+	type result struct {
+		vals []interface{}
+		keys []interface{}
+	}
+
+	results := make([]result, 0, len(rows))
+	for _, vctx := range rows {
+		vals, err := r.values(ectx, vctx)
+		if err != nil {
+			return err
+		}
+
+		// make result columns available by alias.
+		for i, alias := range r.alias {
+			if _, dup := vctx[alias]; alias != "" && !dup {
+				vctx[alias] = vals[i]
+			}
+		}
+
+		if r.having != nil {
+			ok, err := r.having.eval(ectx, vctx)
+			if err != nil {
+				return fmt.Errorf("could not evaluate HAVING expression: %w", err)
+			}
+			if ok, _ := ok.(bool); !ok {
+				continue
+			}
+		}
+
+		keys := make([]interface{}, len(r.order))
+		for i, o := range r.order {
+			switch o.col {
+			case -1:
+				keys[i], err = o.expr.eval(ectx, vctx)
+				if err != nil {
+					return fmt.Errorf("could not evaluate ORDER BY expression: %w", err)
+				}
 			default:
-				dest[i] = v
+				keys[i] = vals[o.col]
 			}
 		}
-	case string:
-		dest[0] = []byte(vs)
-	default:
-		dest[0] = vs
+		results = append(results, result{vals: vals, keys: keys})
+	}
+
+	if len(r.order) > 0 {
+		var err error
+		sort.SliceStable(results, func(i, j int) bool {
+			for k, o := range r.order {
+				a, b := results[i].keys[k], results[j].keys[k]
+				if o.desc {
+					a, b = b, a
+				}
+				less, e := lessThan(a, b)
+				if e != nil && err == nil {
+					err = e
+				}
+				if less {
+					return true
+				}
+				more, _ := lessThan(b, a)
+				if more {
+					return false
+				}
+			}
+			return false
+		})
+		if err != nil {
+			return fmt.Errorf("could not sort rows: %w", err)
+		}
+	}
+
+	if r.offset > 0 {
+		if r.offset > int64(len(results)) {
+			r.offset = int64(len(results))
+		}
+		results = results[r.offset:]
+	}
+
+	r.results = make([][]interface{}, len(results))
+	for i, res := range results {
+		r.results[i] = res.vals
 	}
 
 	return nil
 }
 
+// copyValues returns a copy of the column values, so they are not
+// modified when the next entry is read.
+func copyValues(vctx map[interface{}]interface{}) map[interface{}]interface{} {
+	o := make(map[interface{}]interface{}, len(vctx))
+	for k, v := range vctx {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Slice {
+			sli := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+			reflect.Copy(sli, rv)
+			v = sli.Interface()
+		}
+		o[k] = v
+	}
+	return o
+}
+
 type driverStmt struct {
 	conn *driverConn
 	stmt sqlparser.Statement
 }
 
 func (stmt *driverStmt) Close() error {
-	panic("not implemented")
+	delete(stmt.conn.stop, stmt)
+	return nil
 }
 
 func (stmt *driverStmt) NumInput() int {
-	panic("not implemented")
+	return -1
 }
 
 func (stmt *driverStmt) Exec(args []driver.Value) (driver.Result, error) {
-	panic("not implemented")
+	return stmt.conn.exec(stmt.stmt, args)
 }
 
 func (stmt *driverStmt) Query(args []driver.Value) (driver.Rows, error) {
-	panic("not implemented")
+	return stmt.conn.query(stmt.stmt, args)
 }
 
 func newExprFrom(expr sqlparser.Expr, args []driver.Value) (expression, error) {
@@ -676,10 +1208,14 @@ func newExprFrom(expr sqlparser.Expr, args []driver.Value) (expression, error) {
 		return newBinExpr(expr, opOrOr, l, r)
 
 	case *sqlparser.ColName:
-		return &identExpr{
-			expr: expr,
-			name: expr.Name.CompliantName(),
-		}, nil
+		return newIdentExpr(expr), nil
+
+	case *sqlparser.UnaryExpr:
+		v, err := newExprFrom(expr.Expr, args)
+		if err != nil {
+			return nil, err
+		}
+		return newUnaryExpr(expr, v)
 
 	case *sqlparser.SQLVal:
 		return newValueExpr(expr, args)
@@ -687,6 +1223,9 @@ func newExprFrom(expr sqlparser.Expr, args []driver.Value) (expression, error) {
 	case sqlparser.BoolVal:
 		return &valueExpr{expr: expr, v: bool(expr)}, nil
 
+	case *sqlparser.NullVal:
+		return &valueExpr{expr: expr, v: nil}, nil
+
 	case *sqlparser.BinaryExpr:
 		l, err := newExprFrom(expr.Left, args)
 		if err != nil {
@@ -702,6 +1241,18 @@ func newExprFrom(expr sqlparser.Expr, args []driver.Value) (expression, error) {
 		}
 		return newBinExpr(expr, op, l, r)
 
+	case *sqlparser.FuncExpr:
+		if expr.Name.Lowered() == "id" {
+			if len(expr.Exprs) != 0 {
+				return nil, fmt.Errorf("rsqldrv: invalid number of arguments to id (got=%d, want=0)", len(expr.Exprs))
+			}
+			return &idExpr{expr: expr}, nil
+		}
+		if !expr.IsAggregate() {
+			return nil, fmt.Errorf("rsqldrv: unknown function %q", expr.Name.String())
+		}
+		return newAggExpr(expr, args)
+
 	case sqlparser.ValTuple:
 		vs := make([]expression, len(expr))
 		for i, e := range expr {
@@ -7,8 +7,12 @@ package rsqldrv_test
 import (
 	"database/sql"
 	"fmt"
+	"io/ioutil"
 	"math"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/xwb1989/sqlparser"
@@ -370,6 +374,229 @@ func TestQuery(t *testing.T) {
 	}
 }
 
+func TestQueryAggregates(t *testing.T) {
+	db, err := sql.Open("root", "../../testdata/small-flat-tree.root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type eface = interface{}
+	for _, tc := range []struct {
+		query string
+		args  []interface{}
+		cols  []string
+		want  [][]eface
+	}{
+		{
+			query: `SELECT COUNT(*) FROM tree`,
+			cols:  []string{""},
+			want:  [][]eface{{int64(100)}},
+		},
+		{
+			query: `SELECT COUNT(*), SUM(Int32), MIN(Float64), MAX(Float64), AVG(Int32) FROM tree`,
+			cols:  []string{"", "", "", "", ""},
+			want:  [][]eface{{int64(100), int64(4950), 0.0, 99.0, 49.5}},
+		},
+		{
+			query: `SELECT (STDDEV(Int32), STDDEV_SAMP(Int32), VARIANCE(Float64)) FROM tree WHERE Int32 < 2`,
+			cols:  []string{"", "", ""},
+			want:  [][]eface{{0.5, math.Sqrt(0.5), 0.25}},
+		},
+		{
+			query: `SELECT COUNT(DISTINCT N) AS n, MIN(Str), MAX(Str) FROM tree`,
+			cols:  []string{"n", "", ""},
+			want:  [][]eface{{int64(10), "evt-000", "evt-099"}},
+		},
+		{
+			query: `SELECT SUM(Int32)/COUNT(*) FROM tree`,
+			cols:  []string{""},
+			want:  [][]eface{{int64(49)}},
+		},
+		{
+			query: `SELECT COUNT(*), SUM(Int32) FROM tree WHERE Int32 < 0`,
+			cols:  []string{"", ""},
+			want:  [][]eface{{int64(0), nil}},
+		},
+		{
+			query: `SELECT (N, COUNT(*), SUM(Int32)) FROM tree WHERE Int32 < 30 GROUP BY N ORDER BY N DESC LIMIT 3`,
+			cols:  []string{"N", "", ""},
+			want: [][]eface{
+				{int32(9), int64(3), int64(57)},
+				{int32(8), int64(3), int64(54)},
+				{int32(7), int64(3), int64(51)},
+			},
+		},
+		{
+			query: `SELECT N, COUNT(*) AS cnt FROM tree WHERE Int32 < 25 GROUP BY 1 HAVING cnt > 2 ORDER BY 1`,
+			cols:  []string{"N", "cnt"},
+			want: [][]eface{
+				{int32(0), int64(3)},
+				{int32(1), int64(3)},
+				{int32(2), int64(3)},
+				{int32(3), int64(3)},
+				{int32(4), int64(3)},
+			},
+		},
+		{
+			query: `SELECT Int32/25 AS k, MAX(Int32) FROM tree GROUP BY k ORDER BY MAX(Int32) DESC`,
+			cols:  []string{"k", ""},
+			want: [][]eface{
+				{int32(3), int32(99)},
+				{int32(2), int32(74)},
+				{int32(1), int32(49)},
+				{int32(0), int32(24)},
+			},
+		},
+		{
+			query: `SELECT Int32 FROM tree WHERE N = 3 ORDER BY Float64 DESC LIMIT 2, 3`,
+			cols:  []string{"Int32"},
+			want:  [][]eface{{int32(73)}, {int32(63)}, {int32(53)}},
+		},
+		{
+			query: `SELECT Str, Int32 FROM tree ORDER BY N, Int32 DESC LIMIT 3`,
+			cols:  []string{"Str", "Int32"},
+			want: [][]eface{
+				{"evt-090", int32(90)},
+				{"evt-080", int32(80)},
+				{"evt-070", int32(70)},
+			},
+		},
+		{
+			query: `SELECT Str FROM tree LIMIT 2`,
+			cols:  []string{"Str"},
+			want:  [][]eface{{"evt-000"}, {"evt-001"}},
+		},
+		{
+			query: `SELECT Int32 FROM tree WHERE Int32 > ? LIMIT ?, ?`,
+			args:  []interface{}{90, 2, 3},
+			cols:  []string{"Int32"},
+			want:  [][]eface{{int32(93)}, {int32(94)}, {int32(95)}},
+		},
+		{
+			query: `SELECT Int32 FROM tree LIMIT 0`,
+			cols:  []string{"Int32"},
+		},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			rows, err := db.Query(tc.query, tc.args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rows.Close()
+
+			cols, err := rows.Columns()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := cols, tc.cols; !reflect.DeepEqual(got, want) {
+				t.Fatalf("invalid columns\ngot= %q\nwant=%q", got, want)
+			}
+
+			var got [][]eface
+			for rows.Next() {
+				var (
+					vs   = make([]eface, len(cols))
+					ptrs = make([]eface, len(cols))
+				)
+				for i := range vs {
+					ptrs[i] = &vs[i]
+				}
+				err = rows.Scan(ptrs...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for i, v := range vs {
+					if v, ok := v.([]byte); ok {
+						vs[i] = string(v)
+					}
+				}
+				got = append(got, vs)
+			}
+
+			err = rows.Err()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("invalid select\ngot = %#v\nwant= %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestQueryAggregatesErrors(t *testing.T) {
+	db, err := sql.Open("root", "../../testdata/simple.root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, tc := range []struct {
+		query string
+		err   error
+	}{
+		{
+			query: `SELECT one FROM tree WHERE COUNT(*) > 1`,
+			err:   fmt.Errorf("rsqldrv: invalid use of aggregate function in WHERE clause"),
+		},
+		{
+			query: `SELECT one FROM tree GROUP BY SUM(one)`,
+			err:   fmt.Errorf("rsqldrv: invalid use of aggregate function in GROUP BY clause"),
+		},
+		{
+			query: `SELECT SUM(COUNT(one)) FROM tree`,
+			err:   fmt.Errorf("could not generate row expression: rsqldrv: nested aggregate functions in SUM(COUNT(one))"),
+		},
+		{
+			query: `SELECT SUM(*) FROM tree`,
+			err:   fmt.Errorf("could not generate row expression: rsqldrv: invalid argument '*' to sum"),
+		},
+		{
+			query: `SELECT BIT_AND(one) FROM tree`,
+			err:   fmt.Errorf(`could not generate row expression: rsqldrv: aggregate function "bit_and" not supported`),
+		},
+		{
+			query: `SELECT ABS(one) FROM tree`,
+			err:   fmt.Errorf(`could not generate row expression: rsqldrv: unknown function "ABS"`),
+		},
+		{
+			query: `SELECT id(one) FROM tree`,
+			err:   fmt.Errorf(`could not generate row expression: rsqldrv: invalid number of arguments to id (got=1, want=0)`),
+		},
+		{
+			query: `SELECT one FROM tree ORDER BY 3`,
+			err:   fmt.Errorf("rsqldrv: invalid ORDER BY column position 3"),
+		},
+		{
+			query: `SELECT one FROM tree HAVING one > 2`,
+			err:   fmt.Errorf("rsqldrv: HAVING clause without aggregation"),
+		},
+		{
+			query: `SELECT SUM(three) FROM tree`,
+			err:   fmt.Errorf(`could not evaluate aggregate: rsqldrv: could not sum values: non-numerical value "uno" (string)`),
+		},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			rows, err := db.Query(tc.query)
+			if err == nil {
+				defer rows.Close()
+				for rows.Next() {
+				}
+				err = rows.Err()
+			}
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if got, want := err.Error(), tc.err.Error(); got != want {
+				t.Fatalf("invalid error\ngot= %v\nwant=%v", got, want)
+			}
+		})
+	}
+}
+
 func TestFlatTree(t *testing.T) {
 	type event struct {
 		I32    int32       `groot:"Int32"`
@@ -527,3 +754,470 @@ func TestFlatTree(t *testing.T) {
 		i++
 	}
 }
+
+func TestCreate(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rsqldrv-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	fname := filepath.Join(tmp, "create.root")
+
+	db, err := rsqldrv.Create(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE evts (run INT, evt BIGINT UNSIGNED, px FLOAT, e DOUBLE, sel BIT, tag VARCHAR(32))`)
+	if err != nil {
+		t.Fatalf("could not create table: %+v", err)
+	}
+
+	for _, tc := range []struct {
+		query string
+		args  []interface{}
+		id    int64
+		rows  int64
+	}{
+		{
+			query: `INSERT INTO evts VALUES (1, 10, 1.5, 2.5, true, 'evt-10'), (1, 11, -1.5, 3, false, 'evt-11')`,
+			id:    1,
+			rows:  2,
+		},
+		{
+			query: `INSERT INTO evts (tag, run, evt) VALUES (?, ?, ?)`,
+			args:  []interface{}{"evt-20", 2, 20},
+			id:    2,
+			rows:  1,
+		},
+		{
+			query: `INSERT INTO evts VALUES (?, ?, ?, ?, ?, ?)`,
+			args:  []interface{}{2, uint64(21), 0.5, float32(1), true, []byte("evt-21")},
+			id:    3,
+			rows:  1,
+		},
+	} {
+		res, err := db.Exec(tc.query, tc.args...)
+		if err != nil {
+			t.Fatalf("could not insert rows with %q: %+v", tc.query, err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != tc.id {
+			t.Fatalf("invalid last-insert-id for %q: got=%d, want=%d", tc.query, id, tc.id)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != tc.rows {
+			t.Fatalf("invalid rows-affected for %q: got=%d, want=%d", tc.query, n, tc.rows)
+		}
+	}
+
+	type data struct {
+		run int32
+		evt uint64
+		px  float32
+		e   float64
+		sel bool
+		tag string
+	}
+
+	want := []data{
+		{1, 10, 1.5, 2.5, true, "evt-10"},
+		{1, 11, -1.5, 3, false, "evt-11"},
+		{2, 20, 0, 0, false, "evt-20"},
+		{2, 21, 0.5, 1, true, "evt-21"},
+	}
+
+	read := func(db *sql.DB) []data {
+		rows, err := db.Query(`SELECT * FROM evts`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		cols, err := rows.ColumnTypes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, want := range []interface{}{int32(0), uint64(0), float32(0), float64(0), false, ""} {
+			if got, want := cols[i].ScanType(), reflect.TypeOf(want); got != want {
+				t.Fatalf("invalid type for column %q: got=%v, want=%v", cols[i].Name(), got, want)
+			}
+		}
+
+		var vs []data
+		for rows.Next() {
+			var v data
+			err = rows.Scan(&v.run, &v.evt, &v.px, &v.e, &v.sel, &v.tag)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vs = append(vs, v)
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		return vs
+	}
+
+	// tables can be read back while they are being filled.
+	if got := read(db); !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid rows:\ngot= %#v\nwant=%#v", got, want)
+	}
+
+	_, err = db.Exec(`INSERT INTO evts VALUES (3, 30, 0, 0, false, 'evt-30')`)
+	if err != nil {
+		t.Fatalf("could not insert row: %+v", err)
+	}
+	want = append(want, data{3, 30, 0, 0, false, "evt-30"})
+
+	err = db.Close()
+	if err != nil {
+		t.Fatalf("could not close db: %+v", err)
+	}
+
+	db, err = rsqldrv.Open(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if got := read(db); !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid rows:\ngot= %#v\nwant=%#v", got, want)
+	}
+}
+
+func TestInsertSelect(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rsqldrv-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	db, err := rsqldrv.Create(filepath.Join(tmp, "insert-select.root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, query := range []string{
+		`CREATE TABLE evts (run INT, px DOUBLE)`,
+		`INSERT INTO evts VALUES (1, 1), (1, 2), (2, 3), (3, 4), (3, 5)`,
+		`CREATE TABLE runs (run BIGINT, n INT, px DOUBLE)`,
+	} {
+		_, err = db.Exec(query)
+		if err != nil {
+			t.Fatalf("could not execute %q: %+v", query, err)
+		}
+	}
+
+	res, err := db.Exec(`INSERT INTO runs (run, n, px) SELECT run, COUNT(*), SUM(px) FROM evts WHERE px > 1 GROUP BY run`)
+	if err != nil {
+		t.Fatalf("could not insert rows: %+v", err)
+	}
+	if n, _ := res.RowsAffected(); n != 3 {
+		t.Fatalf("invalid rows-affected: got=%d, want=3", n)
+	}
+
+	rows, err := db.Query(`SELECT run, n, px FROM runs`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	type data struct {
+		run int64
+		n   int32
+		px  float64
+	}
+
+	var got []data
+	for rows.Next() {
+		var v data
+		err = rows.Scan(&v.run, &v.n, &v.px)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, v)
+	}
+
+	want := []data{{1, 1, 2}, {2, 1, 3}, {3, 2, 9}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid rows:\ngot= %#v\nwant=%#v", got, want)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rsqldrv-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	db, err := rsqldrv.Create(filepath.Join(tmp, "join.root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, query := range []string{
+		`CREATE TABLE evts (run INT, evt INT, px DOUBLE)`,
+		`INSERT INTO evts VALUES (1, 1, 10), (1, 2, 11), (2, 1, 12), (2, 2, 13), (3, 1, 14)`,
+		`CREATE TABLE weights (run BIGINT, evt BIGINT, w FLOAT)`,
+		`INSERT INTO weights VALUES (2, 2, 0.5), (1, 1, 1.5), (2, 1, 2.5), (2, 1, 3.5)`,
+		`CREATE TABLE runs (run INT, lumi DOUBLE)`,
+		`INSERT INTO runs VALUES (1, 0.5), (2, 2), (4, 3)`,
+	} {
+		_, err = db.Exec(query)
+		if err != nil {
+			t.Fatalf("could not execute %q: %+v", query, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		query string
+		want  [][]interface{}
+	}{
+		{
+			query: `SELECT evts.evt, evts.px, w FROM evts JOIN weights ON evts.run = weights.run AND evts.evt = weights.evt`,
+			want: [][]interface{}{
+				{int32(1), 10.0, float32(1.5)},
+				{int32(1), 12.0, float32(2.5)},
+				{int32(1), 12.0, float32(3.5)},
+				{int32(2), 13.0, float32(0.5)},
+			},
+		},
+		{
+			query: `SELECT run, evt, px, w FROM evts JOIN weights USING (run, evt) WHERE w > 1`,
+			want: [][]interface{}{
+				{int32(1), int32(1), 10.0, float32(1.5)},
+				{int32(2), int32(1), 12.0, float32(2.5)},
+				{int32(2), int32(1), 12.0, float32(3.5)},
+			},
+		},
+		{
+			query: `SELECT e.evt, r.lumi FROM evts AS e JOIN runs AS r ON (r.run = e.run) WHERE r.lumi > 1`,
+			want: [][]interface{}{
+				{int32(1), 2.0},
+				{int32(2), 2.0},
+			},
+		},
+		{
+			query: `SELECT run, evt, w FROM evts LEFT JOIN weights USING (run, evt)`,
+			want: [][]interface{}{
+				{int32(1), int32(1), float32(1.5)},
+				{int32(1), int32(2), nil},
+				{int32(2), int32(1), float32(2.5)},
+				{int32(2), int32(1), float32(3.5)},
+				{int32(2), int32(2), float32(0.5)},
+				{int32(3), int32(1), nil},
+			},
+		},
+		{
+			query: `SELECT r.run, COUNT(*), SUM(e.px * r.lumi) FROM runs AS r JOIN evts AS e ON r.run = e.run GROUP BY r.run ORDER BY r.run DESC`,
+			want: [][]interface{}{
+				{int32(2), int64(2), 50.0},
+				{int32(1), int64(2), 10.5},
+			},
+		},
+		{
+			query: `SELECT * FROM evts JOIN runs USING (run) WHERE evt = 2`,
+			want: [][]interface{}{
+				{int32(1), int32(2), 11.0, int32(1), 0.5},
+				{int32(2), int32(2), 13.0, int32(2), 2.0},
+			},
+		},
+		{
+			query: `SELECT runs.* FROM runs JOIN weights ON runs.run = weights.run WHERE weights.evt = 2`,
+			want: [][]interface{}{
+				{int32(2), 2.0},
+			},
+		},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			rows, err := db.Query(tc.query)
+			if err != nil {
+				t.Fatalf("could not run query: %+v", err)
+			}
+			defer rows.Close()
+
+			cols, err := rows.Columns()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got [][]interface{}
+			for rows.Next() {
+				vs := make([]interface{}, len(cols))
+				ps := make([]interface{}, len(cols))
+				for i := range vs {
+					ps[i] = &vs[i]
+				}
+				err = rows.Scan(ps...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, vs)
+			}
+			if err := rows.Err(); err != nil {
+				t.Fatalf("could not iterate over rows: %+v", err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("invalid rows:\ngot= %v\nwant=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExecErrors(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rsqldrv-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	db, err := rsqldrv.Create(filepath.Join(tmp, "errors.root"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, query := range []string{
+		`CREATE TABLE evts (run INT, evt TINYINT, px DOUBLE)`,
+		`CREATE TABLE runs (run INT, px DOUBLE)`,
+	} {
+		_, err = db.Exec(query)
+		if err != nil {
+			t.Fatalf("could not execute %q: %+v", query, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		query string
+		err   error
+	}{
+		{
+			query: `CREATE TABLE evts (x INT)`,
+			err:   fmt.Errorf(`rsqldrv: table "evts" already exists`),
+		},
+		{
+			query: `CREATE TABLE dup (x INT, x DOUBLE)`,
+			err:   fmt.Errorf(`rsqldrv: duplicate column "x" in table "dup"`),
+		},
+		{
+			query: `CREATE TABLE decimals (x DECIMAL)`,
+			err:   fmt.Errorf(`rsqldrv: invalid type for column "x": SQL type "decimal" not supported`),
+		},
+		{
+			query: `DROP TABLE evts`,
+			err:   fmt.Errorf(`rsqldrv: DDL statement "drop" not supported`),
+		},
+		{
+			query: `INSERT INTO nope VALUES (1)`,
+			err:   fmt.Errorf(`rsqldrv: no such table "nope"`),
+		},
+		{
+			query: `REPLACE INTO evts VALUES (1, 2, 3)`,
+			err:   fmt.Errorf(`rsqldrv: REPLACE statement not supported`),
+		},
+		{
+			query: `INSERT INTO evts VALUES (1, 2)`,
+			err:   fmt.Errorf(`rsqldrv: invalid number of values in row 1 (got=2, want=3)`),
+		},
+		{
+			query: `INSERT INTO evts (run, nope) VALUES (1, 2)`,
+			err:   fmt.Errorf(`rsqldrv: unknown column "nope" in table "evts"`),
+		},
+		{
+			query: `INSERT INTO evts VALUES (1, 300, 3)`,
+			err:   fmt.Errorf(`rsqldrv: could not set column "evt": value 300 overflows int8`),
+		},
+		{
+			query: `INSERT INTO evts VALUES (1.5, 2, 3)`,
+			err:   fmt.Errorf(`rsqldrv: could not set column "run": can not assign 1.5 (float64) to int32`),
+		},
+		{
+			query: `INSERT INTO evts VALUES (NULL, 2, 3)`,
+			err:   fmt.Errorf(`rsqldrv: could not set column "run": invalid NULL value`),
+		},
+		{
+			query: `INSERT INTO evts SELECT run, evt, px FROM evts`,
+			err:   fmt.Errorf(`rsqldrv: INSERT INTO "evts" from a SELECT on the same table not supported`),
+		},
+		{
+			query: `INSERT INTO runs SELECT run FROM evts`,
+			err:   fmt.Errorf(`rsqldrv: invalid number of columns in SELECT (got=1, want=2)`),
+		},
+		{
+			query: `SELECT px FROM evts JOIN runs USING (run)`,
+			err:   fmt.Errorf(`could not extract read-vars: rsqldrv: ambiguous column name "px"`),
+		},
+		{
+			query: `SELECT evts.px FROM evts JOIN runs ON evts.run < runs.run`,
+			err:   fmt.Errorf(`rsqldrv: only equi-joins are supported (got evts.run < runs.run)`),
+		},
+		{
+			query: `SELECT evts.px FROM evts JOIN runs ON evts.run = evts.evt`,
+			err:   fmt.Errorf(`rsqldrv: join condition evts.run = evts.evt does not relate both tables`),
+		},
+		{
+			query: `SELECT evts.px FROM evts RIGHT JOIN runs USING (run)`,
+			err:   fmt.Errorf(`rsqldrv: right join not supported`),
+		},
+		{
+			query: `SELECT nope.px FROM evts JOIN runs USING (run)`,
+			err:   fmt.Errorf(`could not extract read-vars: rsqldrv: unknown table "nope" in column nope.px`),
+		},
+		{
+			query: `SELECT px FROM evts JOIN evts USING (run)`,
+			err:   fmt.Errorf(`rsqldrv: duplicate table name "evts" (use an alias)`),
+		},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			var err error
+			switch {
+			case strings.HasPrefix(tc.query, "SELECT"):
+				var rows *sql.Rows
+				rows, err = db.Query(tc.query)
+				if err == nil {
+					defer rows.Close()
+					for rows.Next() {
+					}
+					err = rows.Err()
+				}
+			default:
+				_, err = db.Exec(tc.query)
+			}
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if got, want := err.Error(), tc.err.Error(); got != want {
+				t.Fatalf("invalid error\ngot= %v\nwant=%v", got, want)
+			}
+		})
+	}
+
+	t.Run("read-only", func(t *testing.T) {
+		db, err := rsqldrv.Open("../../testdata/simple.root")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+
+		_, err = db.Exec(`INSERT INTO tree VALUES (1, 2, 'three')`)
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+		if got, want := err.Error(), `rsqldrv: table "tree" is read-only`; got != want {
+			t.Fatalf("invalid error\ngot= %v\nwant=%v", got, want)
+		}
+	})
+}
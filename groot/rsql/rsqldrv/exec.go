@@ -0,0 +1,441 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rsqldrv // import "go-hep.org/x/hep/groot/rsql/rsqldrv"
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/xwb1989/sqlparser"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/rtree"
+	"go-hep.org/x/hep/groot/rtypes"
+)
+
+// table is a table created with a CREATE TABLE statement.
+// Tables are backed by a tree writer, whose entries are filled with INSERT
+// statements.
+type table struct {
+	mu    sync.Mutex
+	name  string
+	cols  []string
+	vals  []reflect.Value // values of the columns of the current entry
+	w     rtree.Writer
+	dirty bool // whether entries were written since the last flush
+}
+
+// tree returns a read-only snapshot of the tree backing the table, with all
+// the entries written so far committed to storage.
+func (t *table) tree(f *riofs.File) (rtree.Tree, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.dirty {
+		err := t.w.Flush()
+		if err != nil {
+			return nil, fmt.Errorf("rsqldrv: could not flush table %q: %w", t.name, err)
+		}
+		t.dirty = false
+	}
+
+	// the tree writer can not be read from: create a new tree from the
+	// current state of the writer, as if it were read back from file.
+	wbuf := rbytes.NewWBuffer(nil, nil, 0, f)
+	_, err := t.w.(rbytes.Marshaler).MarshalROOT(wbuf)
+	if err != nil {
+		return nil, fmt.Errorf("rsqldrv: could not marshal table %q: %w", t.name, err)
+	}
+
+	obj := rtypes.Factory.Get(t.w.Class())().Interface()
+	err = obj.(rbytes.Unmarshaler).UnmarshalROOT(rbytes.NewRBuffer(wbuf.Bytes(), nil, 0, f))
+	if err != nil {
+		return nil, fmt.Errorf("rsqldrv: could not unmarshal table %q: %w", t.name, err)
+	}
+	obj.(riofs.SetFiler).SetFile(f)
+
+	return obj.(rtree.Tree), nil
+}
+
+func (t *table) close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.w.Close()
+}
+
+// table returns the table created with CREATE TABLE under that name, if any.
+func (conn *driverConn) table(name string) *table {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	for _, t := range conn.tables {
+		if t.name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// tree returns the tree backing the named table.
+func (conn *driverConn) tree(name string) (rtree.Tree, error) {
+	if t := conn.table(name); t != nil {
+		return t.tree(conn.f)
+	}
+
+	obj, err := riofs.Dir(conn.f).Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, ok := obj.(rtree.Tree)
+	if !ok {
+		return nil, fmt.Errorf("rsqldrv: object %q is not a Tree", name)
+	}
+	return tree, nil
+}
+
+func (conn *driverConn) execDDL(stmt *sqlparser.DDL) (driver.Result, error) {
+	if stmt.Action != sqlparser.CreateStr {
+		return nil, fmt.Errorf("rsqldrv: DDL statement %q not supported", stmt.Action)
+	}
+	if stmt.TableSpec == nil {
+		return nil, fmt.Errorf("rsqldrv: missing table definition in CREATE TABLE")
+	}
+
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	name := stmt.NewName.Name.CompliantName()
+	for _, t := range conn.tables {
+		if t.name == name {
+			return nil, fmt.Errorf("rsqldrv: table %q already exists", name)
+		}
+	}
+	if _, err := riofs.Dir(conn.f).Get(name); err == nil {
+		return nil, fmt.Errorf("rsqldrv: table %q already exists", name)
+	}
+
+	if len(stmt.TableSpec.Columns) == 0 {
+		return nil, fmt.Errorf("rsqldrv: table %q has no column", name)
+	}
+
+	t := &table{
+		name: name,
+		cols: make([]string, len(stmt.TableSpec.Columns)),
+		vals: make([]reflect.Value, len(stmt.TableSpec.Columns)),
+	}
+	wvars := make([]rtree.WriteVar, len(stmt.TableSpec.Columns))
+	for i, col := range stmt.TableSpec.Columns {
+		t.cols[i] = col.Name.CompliantName()
+		for _, prev := range t.cols[:i] {
+			if prev == t.cols[i] {
+				return nil, fmt.Errorf("rsqldrv: duplicate column %q in table %q", prev, name)
+			}
+		}
+
+		rt, err := typeFromSQL(col.Type)
+		if err != nil {
+			return nil, fmt.Errorf("rsqldrv: invalid type for column %q: %w", t.cols[i], err)
+		}
+
+		ptr := reflect.New(rt)
+		t.vals[i] = ptr.Elem()
+		wvars[i] = rtree.WriteVar{Name: t.cols[i], Value: ptr.Interface()}
+	}
+
+	w, err := rtree.NewWriter(conn.f, name, wvars)
+	if err != nil {
+		return nil, fmt.Errorf("rsqldrv: could not create table %q: %w", name, err)
+	}
+	t.w = w
+
+	conn.tables = append(conn.tables, t)
+
+	return &driverResult{id: -1}, nil
+}
+
+func (conn *driverConn) execInsert(stmt *sqlparser.Insert, args []driver.Value) (driver.Result, error) {
+	if stmt.Action != sqlparser.InsertStr {
+		return nil, fmt.Errorf("rsqldrv: %s statement not supported", strings.ToUpper(stmt.Action))
+	}
+	if len(stmt.OnDup) != 0 {
+		return nil, fmt.Errorf("rsqldrv: INSERT ... ON DUPLICATE KEY UPDATE not supported")
+	}
+
+	name := stmt.Table.Name.CompliantName()
+	t := conn.table(name)
+	if t == nil {
+		if _, err := riofs.Dir(conn.f).Get(name); err == nil {
+			return nil, fmt.Errorf("rsqldrv: table %q is read-only", name)
+		}
+		return nil, fmt.Errorf("rsqldrv: no such table %q", name)
+	}
+
+	cols := make([]int, 0, len(t.cols))
+	switch len(stmt.Columns) {
+	case 0:
+		for i := range t.cols {
+			cols = append(cols, i)
+		}
+	default:
+		seen := make(map[int]bool, len(stmt.Columns))
+		for _, col := range stmt.Columns {
+			i := indexOf(t.cols, col.CompliantName())
+			if i < 0 {
+				return nil, fmt.Errorf("rsqldrv: unknown column %q in table %q", col.CompliantName(), name)
+			}
+			if seen[i] {
+				return nil, fmt.Errorf("rsqldrv: duplicate column %q in INSERT", col.CompliantName())
+			}
+			seen[i] = true
+			cols = append(cols, i)
+		}
+	}
+
+	var (
+		res  = &driverResult{id: -1}
+		vals = make([]interface{}, len(cols))
+	)
+
+	write := func() error {
+		for i := range t.vals {
+			t.vals[i].Set(reflect.Zero(t.vals[i].Type()))
+		}
+		for i, icol := range cols {
+			err := assign(t.vals[icol], vals[i])
+			if err != nil {
+				return fmt.Errorf("rsqldrv: could not set column %q: %w", t.cols[icol], err)
+			}
+		}
+		_, err := t.w.Write()
+		if err != nil {
+			return fmt.Errorf("rsqldrv: could not insert row into %q: %w", name, err)
+		}
+		t.dirty = true
+		res.id = t.w.Entries() - 1
+		res.rows++
+		return nil
+	}
+
+	switch rows := stmt.Rows.(type) {
+	case sqlparser.Values:
+		// evaluate all the rows before writing any of them.
+		rvals := make([][]interface{}, len(rows))
+		for i, row := range rows {
+			if len(row) != len(cols) {
+				return nil, fmt.Errorf(
+					"rsqldrv: invalid number of values in row %d (got=%d, want=%d)",
+					i+1, len(row), len(cols),
+				)
+			}
+			rvals[i] = make([]interface{}, len(row))
+			for j, expr := range row {
+				v, err := newExprFrom(expr, args)
+				if err != nil {
+					return nil, err
+				}
+				if !v.isStatic() {
+					return nil, fmt.Errorf("rsqldrv: invalid non-constant value %s", sqlparser.String(expr))
+				}
+				rvals[i][j], err = v.eval(nil, nil)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		for _, row := range rvals {
+			copy(vals, row)
+			err := write()
+			if err != nil {
+				return res, err
+			}
+		}
+
+	case *sqlparser.Select:
+		for _, tbl := range tablesOf(rows.From) {
+			if tbl == name {
+				return nil, fmt.Errorf("rsqldrv: INSERT INTO %q from a SELECT on the same table not supported", name)
+			}
+		}
+
+		src, err := newDriverRows(conn, rows, args)
+		if err != nil {
+			return nil, err
+		}
+		defer src.Close()
+
+		if n := len(src.cols); n != len(cols) {
+			return nil, fmt.Errorf("rsqldrv: invalid number of columns in SELECT (got=%d, want=%d)", n, len(cols))
+		}
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		dest := make([]driver.Value, len(cols))
+		for {
+			err := src.Next(dest)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return res, fmt.Errorf("rsqldrv: could not read row: %w", err)
+			}
+			for i, v := range dest {
+				vals[i] = v
+			}
+			err = write()
+			if err != nil {
+				return res, err
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("rsqldrv: invalid INSERT rows %s", sqlparser.String(rows))
+	}
+
+	return res, nil
+}
+
+// tablesOf returns the names of all the tables referenced in a FROM clause.
+func tablesOf(from sqlparser.TableExprs) []string {
+	var names []string
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if node, ok := node.(sqlparser.TableName); ok {
+			names = append(names, node.Name.CompliantName())
+			return false, nil
+		}
+		return true, nil
+	}, from)
+	return names
+}
+
+func indexOf(names []string, name string) int {
+	for i, v := range names {
+		if v == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// typeFromSQL returns the Go type used to store values of the provided
+// SQL column type.
+func typeFromSQL(ct sqlparser.ColumnType) (reflect.Type, error) {
+	unsigned := bool(ct.Unsigned)
+	switch typ := strings.ToLower(ct.Type); typ {
+	case "bit":
+		// sqlparser does not handle BOOL columns: use BIT(1) columns for
+		// boolean values.
+		if ct.Length != nil && string(ct.Length.Val) != "1" {
+			return nil, fmt.Errorf("SQL type BIT(%s) not supported", ct.Length.Val)
+		}
+		return reflect.TypeOf(false), nil
+	case "tinyint":
+		if unsigned {
+			return reflect.TypeOf(uint8(0)), nil
+		}
+		return reflect.TypeOf(int8(0)), nil
+	case "smallint":
+		if unsigned {
+			return reflect.TypeOf(uint16(0)), nil
+		}
+		return reflect.TypeOf(int16(0)), nil
+	case "mediumint", "int", "integer":
+		if unsigned {
+			return reflect.TypeOf(uint32(0)), nil
+		}
+		return reflect.TypeOf(int32(0)), nil
+	case "bigint":
+		if unsigned {
+			return reflect.TypeOf(uint64(0)), nil
+		}
+		return reflect.TypeOf(int64(0)), nil
+	case "float":
+		return reflect.TypeOf(float32(0)), nil
+	case "double", "real":
+		return reflect.TypeOf(float64(0)), nil
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext":
+		return reflect.TypeOf(""), nil
+	default:
+		return nil, fmt.Errorf("SQL type %q not supported", typ)
+	}
+}
+
+// assign stores the value v into dst, converting it to the type of dst.
+// Conversions that would lose information are rejected.
+func assign(dst reflect.Value, v interface{}) error {
+	switch vv := v.(type) {
+	case nil:
+		return fmt.Errorf("invalid NULL value")
+	case idealInt:
+		v = int64(vv)
+	case idealUint:
+		v = uint64(vv)
+	case idealFloat:
+		v = float64(vv)
+	case []byte:
+		v = string(vv)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch dst.Kind() {
+	case reflect.Bool:
+		if rv.Kind() == reflect.Bool {
+			dst.SetBool(rv.Bool())
+			return nil
+		}
+
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch {
+		case isInt(rv):
+			if !dst.OverflowInt(rv.Int()) {
+				dst.SetInt(rv.Int())
+				return nil
+			}
+			return fmt.Errorf("value %v overflows %v", v, dst.Type())
+		case isUint(rv):
+			if u := rv.Uint(); int64(u) >= 0 && !dst.OverflowInt(int64(u)) {
+				dst.SetInt(int64(u))
+				return nil
+			}
+			return fmt.Errorf("value %v overflows %v", v, dst.Type())
+		}
+
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch {
+		case isInt(rv):
+			if i := rv.Int(); i >= 0 && !dst.OverflowUint(uint64(i)) {
+				dst.SetUint(uint64(i))
+				return nil
+			}
+			return fmt.Errorf("value %v overflows %v", v, dst.Type())
+		case isUint(rv):
+			if !dst.OverflowUint(rv.Uint()) {
+				dst.SetUint(rv.Uint())
+				return nil
+			}
+			return fmt.Errorf("value %v overflows %v", v, dst.Type())
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if x, err := toFloat64(v); err == nil {
+			dst.SetFloat(x)
+			return nil
+		}
+
+	case reflect.String:
+		if rv.Kind() == reflect.String {
+			dst.SetString(rv.String())
+			return nil
+		}
+	}
+
+	return fmt.Errorf("can not assign %v (%T) to %v", v, v, dst.Type())
+}
@@ -1296,12 +1296,27 @@ func (op operator) String() string {
 type identExpr struct {
 	expr sqlparser.Expr
 	name string
+	qual string // table qualifier, if any
+}
+
+func newIdentExpr(expr *sqlparser.ColName) *identExpr {
+	return &identExpr{
+		expr: expr,
+		name: expr.Name.CompliantName(),
+		qual: expr.Qualifier.Name.CompliantName(),
+	}
 }
 
 func (expr *identExpr) sql() sqlparser.Expr { return expr.expr }
 func (expr *identExpr) isStatic() bool      { return false }
 
 func (expr *identExpr) eval(ectx *execCtx, vctx map[interface{}]interface{}) (r interface{}, err error) {
+	if expr.qual != "" {
+		// columns of joined tables are available under their qualified name.
+		if r, ok := vctx[expr.qual+"."+expr.name]; ok {
+			return r, nil
+		}
+	}
 	r, ok := vctx[expr.name]
 	if !ok {
 		err = fmt.Errorf("unknown field %q", expr.name)
@@ -1309,6 +1324,77 @@ func (expr *identExpr) eval(ectx *execCtx, vctx map[interface{}]interface{}) (r
 	return r, err
 }
 
+// entryKey is the evaluation context key of the entry number of a row.
+type entryKey struct{}
+
+// idExpr is the id() function, returning the entry number of a row.
+type idExpr struct {
+	expr sqlparser.Expr
+}
+
+func (expr *idExpr) sql() sqlparser.Expr { return expr.expr }
+func (expr *idExpr) isStatic() bool      { return false }
+
+func (expr *idExpr) eval(ectx *execCtx, vctx map[interface{}]interface{}) (interface{}, error) {
+	v, ok := vctx[entryKey{}]
+	if !ok {
+		return nil, fmt.Errorf("rsqldrv: id() used outside of a row")
+	}
+	return v, nil
+}
+
+type unaryExpr struct {
+	expr sqlparser.Expr
+	op   string
+	v    expression
+}
+
+func newUnaryExpr(expr *sqlparser.UnaryExpr, v expression) (expression, error) {
+	switch expr.Operator {
+	case sqlparser.UPlusStr, sqlparser.UMinusStr:
+	default:
+		return nil, fmt.Errorf("rsqldrv: invalid unary-expression operator %q", expr.Operator)
+	}
+	return &unaryExpr{expr: expr, op: expr.Operator, v: v}, nil
+}
+
+func (expr *unaryExpr) sql() sqlparser.Expr { return expr.expr }
+func (expr *unaryExpr) isStatic() bool      { return expr.v.isStatic() }
+
+func (expr *unaryExpr) eval(ectx *execCtx, vctx map[interface{}]interface{}) (interface{}, error) {
+	v, err := expr.v.eval(ectx, vctx)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil || expr.op == sqlparser.UPlusStr {
+		return v, nil
+	}
+
+	switch v := v.(type) {
+	case idealInt:
+		return -v, nil
+	case idealUint:
+		return -idealInt(v), nil
+	case idealFloat:
+		return -v, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Float32, reflect.Float64:
+		o := reflect.New(rv.Type()).Elem()
+		switch {
+		case isInt(rv):
+			o.SetInt(-rv.Int())
+		default:
+			o.SetFloat(-rv.Float())
+		}
+		return o.Interface(), nil
+	}
+	return nil, fmt.Errorf("rsqldrv: invalid operand %v (%T) to unary %s", v, v, expr.op)
+}
+
 type valueExpr struct {
 	expr sqlparser.Expr
 	v    interface{}
@@ -1380,6 +1466,12 @@ func idealValArgFrom(v interface{}) interface{} {
 		return idealFloat(rv.Float())
 	case reflect.String:
 		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return string(rv.Bytes())
+		}
 	}
 	panic(fmt.Errorf("rsqldrv: invalid ValArg type %#v", v))
 }
@@ -1421,6 +1513,8 @@ func (expr *tupleExpr) eval(ectx *execCtx, vctx map[interface{}]interface{}) (in
 var (
 	_ expression = (*binExpr)(nil)
 	_ expression = (*identExpr)(nil)
+	_ expression = (*idExpr)(nil)
+	_ expression = (*unaryExpr)(nil)
 	_ expression = (*valueExpr)(nil)
 	_ expression = (*tupleExpr)(nil)
 )
@@ -0,0 +1,466 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rsqldrv // import "go-hep.org/x/hep/groot/rsql/rsqldrv"
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+
+	"github.com/xwb1989/sqlparser"
+	"go-hep.org/x/hep/groot/rtree"
+)
+
+// tableRef is a table referenced in the FROM clause of a query.
+type tableRef struct {
+	name string // name of the table, or its alias
+	tree rtree.Tree
+}
+
+// tableRefs are the tables referenced in the FROM clause of a query.
+// Queries reading from 2 tables join them.
+type tableRefs struct {
+	tables []tableRef
+
+	kind   string   // type of join (sqlparser.JoinStr or sqlparser.LeftJoinStr)
+	on     []string // names of the columns listed in a JOIN ... USING clause
+	lkeys  []*sqlparser.ColName
+	rkeys  []*sqlparser.ColName
+	joined bool
+}
+
+func (conn *driverConn) tablesFrom(from sqlparser.TableExprs) (*tableRefs, error) {
+	if len(from) != 1 {
+		return nil, fmt.Errorf("rsqldrv: invalid number of tables (got=%d, want=1)", len(from))
+	}
+
+	refs := &tableRefs{}
+	add := func(expr sqlparser.TableExpr) error {
+		aliased, ok := expr.(*sqlparser.AliasedTableExpr)
+		if !ok {
+			return fmt.Errorf("rsqldrv: table expression %s not supported", sqlparser.String(expr))
+		}
+		tname, ok := aliased.Expr.(sqlparser.TableName)
+		if !ok {
+			return fmt.Errorf("rsqldrv: table expression %s not supported", sqlparser.String(expr))
+		}
+
+		var (
+			name  = tname.Name.CompliantName()
+			alias = name
+		)
+		if !aliased.As.IsEmpty() {
+			alias = aliased.As.CompliantName()
+		}
+		for _, ref := range refs.tables {
+			if ref.name == alias {
+				return fmt.Errorf("rsqldrv: duplicate table name %q (use an alias)", alias)
+			}
+		}
+
+		tree, err := conn.tree(name)
+		if err != nil {
+			return err
+		}
+		refs.tables = append(refs.tables, tableRef{name: alias, tree: tree})
+		return nil
+	}
+
+	switch expr := from[0].(type) {
+	case *sqlparser.AliasedTableExpr:
+		err := add(expr)
+		if err != nil {
+			return nil, err
+		}
+
+	case *sqlparser.JoinTableExpr:
+		switch expr.Join {
+		case sqlparser.JoinStr, sqlparser.LeftJoinStr:
+			refs.kind = expr.Join
+		default:
+			return nil, fmt.Errorf("rsqldrv: %s not supported", expr.Join)
+		}
+
+		for _, e := range []sqlparser.TableExpr{expr.LeftExpr, expr.RightExpr} {
+			err := add(e)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err := refs.joinOn(expr.Condition)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("rsqldrv: table expression %s not supported", sqlparser.String(expr))
+	}
+
+	return refs, nil
+}
+
+// joinOn extracts the key columns of both joined tables from the join
+// condition.
+// Only equi-joins are supported: the condition must be a conjunction of
+// equalities between columns of the left and right tables.
+func (refs *tableRefs) joinOn(cond sqlparser.JoinCondition) error {
+	refs.joined = true
+
+	switch {
+	case len(cond.Using) != 0:
+		for _, col := range cond.Using {
+			name := col.CompliantName()
+			refs.on = append(refs.on, name)
+			refs.lkeys = append(refs.lkeys, refs.qualified(0, name))
+			refs.rkeys = append(refs.rkeys, refs.qualified(1, name))
+		}
+		return nil
+
+	case cond.On != nil:
+		var split func(expr sqlparser.Expr) error
+		split = func(expr sqlparser.Expr) error {
+			switch expr := unparen(expr).(type) {
+			case *sqlparser.AndExpr:
+				err := split(expr.Left)
+				if err != nil {
+					return err
+				}
+				return split(expr.Right)
+
+			case *sqlparser.ComparisonExpr:
+				l, lok := unparen(expr.Left).(*sqlparser.ColName)
+				r, rok := unparen(expr.Right).(*sqlparser.ColName)
+				if expr.Operator != sqlparser.EqualStr || !lok || !rok {
+					break
+				}
+				il, err := refs.lookup(l)
+				if err != nil {
+					return err
+				}
+				ir, err := refs.lookup(r)
+				if err != nil {
+					return err
+				}
+				switch {
+				case il == 0 && ir == 1:
+				case il == 1 && ir == 0:
+					l, r = r, l
+				default:
+					return fmt.Errorf("rsqldrv: join condition %s does not relate both tables", sqlparser.String(expr))
+				}
+				refs.lkeys = append(refs.lkeys, refs.qualified(0, l.Name.CompliantName()))
+				refs.rkeys = append(refs.rkeys, refs.qualified(1, r.Name.CompliantName()))
+				return nil
+			}
+			return fmt.Errorf("rsqldrv: only equi-joins are supported (got %s)", sqlparser.String(expr))
+		}
+		return split(cond.On)
+	}
+
+	return fmt.Errorf("rsqldrv: missing join condition")
+}
+
+// qualified returns the column name, qualified by the name of the i-th table.
+func (refs *tableRefs) qualified(i int, name string) *sqlparser.ColName {
+	return &sqlparser.ColName{
+		Name:      sqlparser.NewColIdent(name),
+		Qualifier: sqlparser.TableName{Name: sqlparser.NewTableIdent(refs.tables[i].name)},
+	}
+}
+
+// starTables returns the indices of the tables selected by a star-expression.
+func (refs *tableRefs) starTables(expr *sqlparser.StarExpr) ([]int, error) {
+	qual := expr.TableName.Name.CompliantName()
+	if qual == "" {
+		tables := make([]int, len(refs.tables))
+		for i := range tables {
+			tables[i] = i
+		}
+		return tables, nil
+	}
+	for i, ref := range refs.tables {
+		if ref.name == qual {
+			return []int{i}, nil
+		}
+	}
+	return nil, fmt.Errorf("rsqldrv: unknown table %q in %s", qual, sqlparser.String(expr))
+}
+
+// has returns whether one of the tables has a branch with the provided name.
+func (refs *tableRefs) has(name string) bool {
+	for _, ref := range refs.tables {
+		if ref.tree.Branch(name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// branch returns the first branch with the provided name.
+func (refs *tableRefs) branch(name string) rtree.Branch {
+	for _, ref := range refs.tables {
+		if b := ref.tree.Branch(name); b != nil {
+			return b
+		}
+	}
+	return nil
+}
+
+// lookup returns the index of the table the column refers to.
+// lookup returns -1 when the column could not be found in any of the tables
+// of a join.
+func (refs *tableRefs) lookup(col *sqlparser.ColName) (int, error) {
+	var (
+		name = col.Name.CompliantName()
+		qual = col.Qualifier.Name.CompliantName()
+	)
+
+	if qual != "" {
+		for i, ref := range refs.tables {
+			if ref.name == qual {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("rsqldrv: unknown table %q in column %s", qual, sqlparser.String(col))
+	}
+
+	if len(refs.tables) == 1 {
+		return 0, nil
+	}
+
+	idx := -1
+	for i, ref := range refs.tables {
+		if ref.tree.Branch(name) == nil {
+			continue
+		}
+		if idx >= 0 && indexOf(refs.on, name) < 0 {
+			return -1, fmt.Errorf("rsqldrv: ambiguous column name %q", name)
+		}
+		if idx < 0 {
+			idx = i
+		}
+	}
+	return idx, nil
+}
+
+// rowSource is an iterator over the rows a query reads from its tables.
+type rowSource interface {
+	// next returns the values of the columns of the next row,
+	// or io.EOF when there are no more rows.
+	next() (map[interface{}]interface{}, error)
+	close() error
+}
+
+// treeSource iterates over the entries of a tree.
+type treeSource struct {
+	cursor *rtree.TreeScanner
+	vars   []interface{}
+	keys   [][]string // names under which each variable is made available
+}
+
+func newTreeSource(tree rtree.Tree, vars []rtree.ReadVar, qual string) (*treeSource, error) {
+	cursor, err := rtree.NewTreeScannerVars(tree, vars...)
+	if err != nil {
+		return nil, err
+	}
+
+	src := &treeSource{
+		cursor: cursor,
+		vars:   varsFrom(vars),
+		keys:   make([][]string, len(vars)),
+	}
+	for i, v := range vars {
+		src.keys[i] = []string{v.Name}
+		if qual != "" {
+			src.keys[i] = append(src.keys[i], qual+"."+v.Name)
+		}
+	}
+	return src, nil
+}
+
+func (src *treeSource) next() (map[interface{}]interface{}, error) {
+	if !src.cursor.Next() {
+		err := src.cursor.Err()
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	err := src.cursor.Scan(src.vars...)
+	if err != nil {
+		return nil, err
+	}
+
+	vctx := make(map[interface{}]interface{}, len(src.vars)+1)
+	vctx[entryKey{}] = src.cursor.Entry()
+	for i, v := range src.vars {
+		v = reflect.Indirect(reflect.ValueOf(v)).Interface()
+		for _, k := range src.keys[i] {
+			vctx[k] = v
+		}
+	}
+	return vctx, nil
+}
+
+func (src *treeSource) close() error {
+	return src.cursor.Close()
+}
+
+// joinSource iterates over the rows of an equi-join between 2 trees.
+//
+// The rows of the right tree are loaded in memory and indexed by the
+// values of their key columns, the rows of the left tree are then streamed
+// and matched against that index.
+type joinSource struct {
+	left  *treeSource
+	right *treeSource
+	lkeys []expression
+	rkeys []expression
+	outer bool // whether unmatched rows of the left tree are kept (LEFT JOIN)
+
+	index map[string][]map[interface{}]interface{}
+	nulls map[interface{}]interface{} // right-side values of unmatched left rows
+
+	row  map[interface{}]interface{}   // current left row
+	rows []map[interface{}]interface{} // pending matching right rows
+}
+
+func (src *joinSource) load() error {
+	src.index = make(map[string][]map[interface{}]interface{})
+	for {
+		row, err := src.right.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		key, ok, err := joinKey(src.rkeys, row)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		src.index[key] = append(src.index[key], copyValues(row))
+	}
+
+	src.nulls = make(map[interface{}]interface{})
+	for _, keys := range src.right.keys {
+		for _, k := range keys {
+			src.nulls[k] = nil
+		}
+	}
+
+	return nil
+}
+
+func (src *joinSource) next() (map[interface{}]interface{}, error) {
+	if src.index == nil {
+		err := src.load()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		if len(src.rows) > 0 {
+			row := src.merge(src.row, src.rows[0])
+			src.rows = src.rows[1:]
+			return row, nil
+		}
+
+		row, err := src.left.next()
+		if err != nil {
+			return nil, err
+		}
+		key, ok, err := joinKey(src.lkeys, row)
+		if err != nil {
+			return nil, err
+		}
+
+		var rows []map[interface{}]interface{}
+		if ok {
+			rows = src.index[key]
+		}
+		if len(rows) == 0 {
+			if src.outer {
+				return src.merge(row, src.nulls), nil
+			}
+			continue
+		}
+		src.row = row
+		src.rows = rows
+	}
+}
+
+// merge combines the left and right rows of a join.
+// Unqualified column names shared by both tables refer to the left table.
+func (src *joinSource) merge(left, right map[interface{}]interface{}) map[interface{}]interface{} {
+	row := make(map[interface{}]interface{}, len(left)+len(right))
+	for k, v := range right {
+		row[k] = v
+	}
+	for k, v := range left {
+		row[k] = v
+	}
+	return row
+}
+
+func (src *joinSource) close() error {
+	errl := src.left.close()
+	errr := src.right.close()
+	if errl != nil {
+		return errl
+	}
+	return errr
+}
+
+// joinKey returns the key of a row, computed from the values of its join
+// columns.
+// joinKey returns false if any of the join columns is NULL, as NULL values
+// never match.
+func joinKey(keys []expression, row map[interface{}]interface{}) (string, bool, error) {
+	vs := make([]interface{}, len(keys))
+	for i, k := range keys {
+		v, err := k.eval(nil, row)
+		if err != nil {
+			return "", false, fmt.Errorf("could not evaluate join key: %w", err)
+		}
+		if v == nil {
+			return "", false, nil
+		}
+
+		// normalize numerical values so that, e.g., int32 and int64
+		// values can be matched.
+		rv := reflect.ValueOf(v)
+		switch {
+		case isInt(rv):
+			v = rv.Int()
+		case isUint(rv):
+			if u := rv.Uint(); u <= math.MaxInt64 {
+				v = int64(u)
+			} else {
+				v = u
+			}
+		case rv.Kind() == reflect.Float32 || rv.Kind() == reflect.Float64:
+			if x := rv.Float(); x == math.Trunc(x) && math.Abs(x) < math.MaxInt64 {
+				v = int64(x)
+			} else {
+				v = x
+			}
+		}
+		vs[i] = v
+	}
+	return fmt.Sprintf("%#v", vs), true, nil
+}
+
+var (
+	_ rowSource = (*treeSource)(nil)
+	_ rowSource = (*joinSource)(nil)
+)
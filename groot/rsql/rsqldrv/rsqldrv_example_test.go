@@ -8,6 +8,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"os"
 
 	"go-hep.org/x/hep/groot"
 	"go-hep.org/x/hep/groot/rsql/rsqldrv"
@@ -170,3 +171,54 @@ func ExampleOpen_connector() {
 	// row[2]: (3, 3.3, "tres")
 	// row[3]: (4, 4.4, "quatro")
 }
+
+func ExampleCreate() {
+	const fname = "../../testdata/rsqldrv-create.root"
+	defer os.Remove(fname)
+
+	db, err := rsqldrv.Create(fname)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, query := range []string{
+		"CREATE TABLE evts (run INT, evt INT, px DOUBLE)",
+		"INSERT INTO evts VALUES (1, 1, 10.5), (1, 2, 11.5), (2, 1, 12.5)",
+		"CREATE TABLE runs (run INT, lumi DOUBLE)",
+		"INSERT INTO runs VALUES (1, 0.5), (2, 1.5)",
+	} {
+		_, err = db.Exec(query)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	rows, err := db.Query("SELECT run, evt, px * lumi FROM evts JOIN runs USING (run)")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			run, evt int32
+			v        float64
+		)
+		err := rows.Scan(&run, &evt, &v)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("run=%d, evt=%d, v=%v\n", run, evt, v)
+	}
+
+	err = db.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Output:
+	// run=1, evt=1, v=5.25
+	// run=1, evt=2, v=5.75
+	// run=2, evt=1, v=18.75
+}
@@ -6,11 +6,13 @@
 package rsql // import "go-hep.org/x/hep/groot/rsql"
 
 import (
+	"database/sql"
 	"fmt"
 	"io"
 	"math"
 	"reflect"
 
+	"github.com/xwb1989/sqlparser"
 	"go-hep.org/x/hep/groot/rsql/rsqldrv"
 	"go-hep.org/x/hep/groot/rtree"
 	"go-hep.org/x/hep/hbook"
@@ -38,6 +40,18 @@ func Scan(tree rtree.Tree, query string, f interface{}) error {
 		vargs[i] = ptr.Elem()
 	}
 
+	return scan(tree, query, args, func() error {
+		out := rv.Call(vargs)[0].Interface()
+		if out != nil {
+			return out.(error)
+		}
+		return nil
+	})
+}
+
+// scan executes a query against the given tree, scans each row of the
+// result into args and runs the function f for each row.
+func scan(tree rtree.Tree, query string, args []interface{}, f func() error) error {
 	db := rsqldrv.OpenDB(rtree.FileOf(tree))
 	defer db.Close()
 
@@ -53,9 +67,9 @@ func Scan(tree rtree.Tree, query string, f interface{}) error {
 			return err
 		}
 
-		out := rv.Call(vargs)[0].Interface()
-		if out != nil {
-			return out.(error)
+		err = f()
+		if err != nil {
+			return err
 		}
 	}
 
@@ -72,20 +86,11 @@ func Scan(tree rtree.Tree, query string, f interface{}) error {
 // where xmin and xmax are inferred from the content of the underlying database.
 func ScanH1D(tree rtree.Tree, query string, h *hbook.H1D) (*hbook.H1D, error) {
 	if h == nil {
-		var (
-			xmin = +math.MaxFloat64
-			xmax = -math.MaxFloat64
-		)
-		// FIXME(sbinet) leverage the underlying db min/max functions,
-		// instead of crawling through the whole data set.
-		err := Scan(tree, query, func(x float64) error {
-			xmin = math.Min(xmin, x)
-			xmax = math.Max(xmax, x)
-			return nil
-		})
+		mins, maxs, err := rangeOf(tree, query, 1)
 		if err != nil {
 			return nil, err
 		}
+		xmin, xmax := mins[0], maxs[0]
 
 		h = hbook.NewH1D(100, xmin, nextULP(xmax))
 	}
@@ -106,24 +111,12 @@ func ScanH1D(tree rtree.Tree, query string, h *hbook.H1D) (*hbook.H1D, error) {
 // underlying database.
 func ScanH2D(tree rtree.Tree, query string, h *hbook.H2D) (*hbook.H2D, error) {
 	if h == nil {
-		var (
-			xmin = +math.MaxFloat64
-			xmax = -math.MaxFloat64
-			ymin = +math.MaxFloat64
-			ymax = -math.MaxFloat64
-		)
-		// FIXME(sbinet) leverage the underlying db min/max functions,
-		// instead of crawling through the whole data set.
-		err := Scan(tree, query, func(x, y float64) error {
-			xmin = math.Min(xmin, x)
-			xmax = math.Max(xmax, x)
-			ymin = math.Min(ymin, y)
-			ymax = math.Max(ymax, y)
-			return nil
-		})
+		mins, maxs, err := rangeOf(tree, query, 2)
 		if err != nil {
 			return nil, err
 		}
+		xmin, xmax := mins[0], maxs[0]
+		ymin, ymax := mins[1], maxs[1]
 
 		h = hbook.NewH2D(100, xmin, nextULP(xmax), 100, ymin, nextULP(ymax))
 	}
@@ -136,6 +129,121 @@ func ScanH2D(tree rtree.Tree, query string, h *hbook.H2D) (*hbook.H2D, error) {
 	return h, err
 }
 
+// rangeOf returns the minimum and maximum values of the n columns
+// selected by the query.
+//
+// The query is rewritten to leverage the MIN and MAX aggregate functions
+// of the ROOT/SQL driver, unless the query already involves aggregations
+// or limits, in which case the whole query result is crawled through.
+func rangeOf(tree rtree.Tree, query string, n int) (mins, maxs []float64, err error) {
+	mins = make([]float64, n)
+	maxs = make([]float64, n)
+	for i := range mins {
+		mins[i] = +math.MaxFloat64
+		maxs[i] = -math.MaxFloat64
+	}
+
+	q, ok := minmaxQuery(query, n)
+	if !ok {
+		vs := make([]float64, n)
+		args := make([]interface{}, n)
+		for i := range vs {
+			args[i] = &vs[i]
+		}
+		err = scan(tree, query, args, func() error {
+			for i, v := range vs {
+				mins[i] = math.Min(mins[i], v)
+				maxs[i] = math.Max(maxs[i], v)
+			}
+			return nil
+		})
+		return mins, maxs, err
+	}
+
+	vs := make([]sql.NullFloat64, 2*n)
+	args := make([]interface{}, 2*n)
+	for i := range vs {
+		args[i] = &vs[i]
+	}
+	err = scan(tree, q, args, func() error {
+		for i := 0; i < n; i++ {
+			if vs[2*i].Valid {
+				mins[i] = vs[2*i].Float64
+			}
+			if vs[2*i+1].Valid {
+				maxs[i] = vs[2*i+1].Float64
+			}
+		}
+		return nil
+	})
+	return mins, maxs, err
+}
+
+// minmaxQuery rewrites the query selecting n columns into a query
+// selecting the minimum and maximum values of these n columns.
+func minmaxQuery(query string, n int) (string, bool) {
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return "", false
+	}
+
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok || sel.Distinct != "" || len(sel.GroupBy) != 0 || sel.Having != nil || sel.Limit != nil {
+		return "", false
+	}
+
+	var cols sqlparser.Exprs
+	for _, expr := range sel.SelectExprs {
+		expr, ok := expr.(*sqlparser.AliasedExpr)
+		if !ok {
+			return "", false
+		}
+		cols = append(cols, columnsOf(expr.Expr)...)
+	}
+	if len(cols) != n {
+		return "", false
+	}
+
+	aggregates := false
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if node, ok := node.(*sqlparser.FuncExpr); ok && node.IsAggregate() {
+			aggregates = true
+		}
+		return !aggregates, nil
+	}, sel.SelectExprs)
+	if aggregates {
+		return "", false
+	}
+
+	exprs := make(sqlparser.SelectExprs, 0, 2*n)
+	for _, col := range cols {
+		for _, name := range []string{"min", "max"} {
+			exprs = append(exprs, &sqlparser.AliasedExpr{
+				Expr: &sqlparser.FuncExpr{
+					Name:  sqlparser.NewColIdent(name),
+					Exprs: sqlparser.SelectExprs{&sqlparser.AliasedExpr{Expr: col}},
+				},
+			})
+		}
+	}
+	sel.SelectExprs = exprs
+	sel.OrderBy = nil
+
+	return sqlparser.String(sel), true
+}
+
+// columnsOf returns the column expressions of a (possibly parenthesized)
+// tuple expression.
+func columnsOf(expr sqlparser.Expr) sqlparser.Exprs {
+	switch e := expr.(type) {
+	case *sqlparser.ParenExpr:
+		return columnsOf(e.Expr)
+	case sqlparser.ValTuple:
+		return sqlparser.Exprs(e)
+	}
+	return sqlparser.Exprs{expr}
+}
+
 func nextULP(v float64) float64 {
 	return math.Nextafter(v, v+1)
 }
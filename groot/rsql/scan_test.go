@@ -7,6 +7,8 @@ package rsql_test
 import (
 	"fmt"
 	"log"
+	"math"
+	"testing"
 
 	"go-hep.org/x/hep/groot"
 	"go-hep.org/x/hep/groot/rsql"
@@ -227,3 +229,62 @@ func ExampleScanH2D_withH2D() {
 	// y-std-dev: 1.4200938936093859
 	// y-std-err: 0.7100469468046929
 }
+
+func TestScanH1DRange(t *testing.T) {
+	f, err := groot.Open("../testdata/simple.root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	o, err := f.Get("tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree := o.(rtree.Tree)
+
+	for _, tc := range []struct {
+		query      string
+		entries    int64
+		xmin, xmax float64
+	}{
+		{
+			query:   "SELECT two FROM tree",
+			entries: 4,
+			xmin:    1.1,
+			xmax:    4.4,
+		},
+		{
+			query:   "SELECT two FROM tree WHERE one > 1 ORDER BY one",
+			entries: 3,
+			xmin:    2.2,
+			xmax:    4.4,
+		},
+		{
+			query:   "SELECT one FROM tree LIMIT 2",
+			entries: 2,
+			xmin:    1,
+			xmax:    2,
+		},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			h, err := rsql.ScanH1D(tree, tc.query, nil)
+			if err != nil {
+				t.Fatalf("could not scan tree: %+v", err)
+			}
+
+			if got, want := h.Entries(), tc.entries; got != want {
+				t.Fatalf("invalid number of entries: got=%d, want=%d", got, want)
+			}
+
+			if got, want := h.XMin(), tc.xmin; got != want {
+				t.Fatalf("invalid x-min: got=%v, want=%v", got, want)
+			}
+
+			if got, want := h.XMax(), math.Nextafter(tc.xmax, tc.xmax+1); got != want {
+				t.Fatalf("invalid x-max: got=%v, want=%v", got, want)
+			}
+		})
+	}
+}
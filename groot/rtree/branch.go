@@ -14,6 +14,7 @@ import (
 	"go-hep.org/x/hep/groot/rcont"
 	"go-hep.org/x/hep/groot/rdict"
 	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/rmeta"
 	"go-hep.org/x/hep/groot/root"
 	"go-hep.org/x/hep/groot/rtypes"
 	"go-hep.org/x/hep/groot/rvers"
@@ -23,6 +24,9 @@ const (
 	defaultBasketSize = 32 * 1024 // default basket size in bytes
 	defaultSplitLevel = 99        // default split-level for branches
 	defaultMaxBaskets = 10        // default number of baskets
+
+	minBasketSize = 512     // minimum basket size in bytes, when optimizing baskets
+	maxBasketSize = 2560000 // maximum basket size in bytes, when optimizing baskets
 )
 
 type tbranch struct {
@@ -85,6 +89,10 @@ func newBranchFromWVar(w *wtree, name string, wvar WriteVar, parent Branch, lvl
 		rt    = reflect.TypeOf(wvar.Value).Elem()
 	)
 
+	if isObjectValue(wvar.Value) {
+		return newBranchObjectFromWVar(w, base, wvar)
+	}
+
 	title.WriteString(wvar.Name)
 	switch k := rt.Kind(); k {
 	case reflect.Array:
@@ -100,10 +108,10 @@ func newBranchFromWVar(w *wtree, name string, wvar WriteVar, parent Branch, lvl
 		}
 		fmt.Fprintf(title, "[%s]", wvar.Count)
 		rt = rt.Elem()
-		base.entryOffsetLen = 1000 // slice, so we need an offset array
+		base.entryOffsetLen = int(w.ttree.defaultEntryOffsetLen) // slice, so we need an offset array
 
 	case reflect.String:
-		base.entryOffsetLen = 1000 // string, so we need an offset array
+		base.entryOffsetLen = int(w.ttree.defaultEntryOffsetLen) // string, so we need an offset array
 
 	case reflect.Struct:
 		return newBranchElementFromWVar(w, base, wvar, parent, lvl, cfg)
@@ -125,7 +133,7 @@ func newBranchFromWVar(w *wtree, name string, wvar WriteVar, parent Branch, lvl
 
 	if !isBranchElem && rt.Kind() != reflect.Struct {
 		code := gotypeToROOTTypeCode(rt)
-		fmt.Fprintf(title, "/%s", code)
+		fmt.Fprintf(title, "/%s%s", code, wvar.Range)
 	}
 
 	_, err := newLeafFromWVar(w, b, wvar, lvl, cfg)
@@ -618,25 +626,21 @@ func (b *tbranch) loadBasket(entry int64) error {
 		seek  = b.basketSeek[ib]
 		f     = b.tree.getFile()
 	)
+	prev := b.ctx.id
 	b.ctx.id = ib
 	b.ctx.entry = entry
 	b.ctx.next = b.basketEntry[ib+1]
 	b.ctx.first = b.basketEntry[ib]
-	if ib < len(b.baskets) {
-		b.ctx.bk = &b.baskets[ib]
-		if b.ctx.bk.rbuf == nil {
-			err = b.ctx.inflate(bufsz, seek, f)
-			if err != nil {
-				return fmt.Errorf("rtree: could not inflate basket: %w", err)
-			}
-
-			return b.setupBasket(&b.ctx, entry)
-		}
+	if ib >= len(b.baskets) {
+		b.baskets = append(b.baskets, make([]Basket, ib+1-len(b.baskets))...)
+	}
+	b.ctx.bk = &b.baskets[ib]
+	// baskets may share their decompression buffer: only re-use the
+	// buffer of the basket we are currently reading from.
+	if b.ctx.bk.rbuf != nil && ib == prev {
 		return nil
 	}
 
-	b.baskets = append(b.baskets, Basket{})
-	b.ctx.bk = &b.baskets[len(b.baskets)-1]
 	err = b.ctx.inflate(bufsz, seek, f)
 	if err != nil {
 		return fmt.Errorf("rtree: could not inflate basket: %w", err)
@@ -662,7 +666,11 @@ func (b *tbranch) findBasketIndex(entry int64) int {
 			return i
 	*/
 
-	for i := b.ctx.id; i < len(b.basketEntry); i++ {
+	beg := b.ctx.id
+	if entry < b.ctx.first {
+		beg = 0
+	}
+	for i := beg; i < len(b.basketEntry); i++ {
 		v := b.basketEntry[i]
 		if v > entry && v > 0 {
 			return i - 1
@@ -836,6 +844,41 @@ func (b *tbranch) flush() error {
 		}
 	}
 
+	if b.ctx.bk.nevbuf == 0 && b.writeBasket > 0 {
+		// do not write out an empty trailing basket.
+		b.baskets = b.baskets[:b.writeBasket]
+		b.ctx.bk = nil
+		return nil
+	}
+
+	return b.flushBasket()
+}
+
+// flushCluster writes the current basket of this branch and of its
+// sub-branches to file, if it holds any entry, and creates a new basket.
+func (b *tbranch) flushCluster() error {
+	for i, sub := range b.branches {
+		err := sub.flushCluster()
+		if err != nil {
+			return fmt.Errorf("could not flush subbranch[%d]=%q of branch %q: %w", i, sub.Name(), b.Name(), err)
+		}
+	}
+
+	if b.ctx.bk == nil || b.ctx.bk.nevbuf == 0 {
+		return nil
+	}
+
+	err := b.flushBasket()
+	if err != nil {
+		return err
+	}
+
+	b.createNewBasket()
+	return nil
+}
+
+// flushBasket writes the current basket to file.
+func (b *tbranch) flushBasket() error {
 	f := b.tree.getFile()
 	totBytes, zipBytes, err := b.ctx.bk.writeFile(f)
 	if err != nil {
@@ -843,6 +886,7 @@ func (b *tbranch) flush() error {
 	}
 	b.totBytes += totBytes
 	b.zipBytes += zipBytes
+	b.tree.zipBytes += zipBytes
 
 	b.basketBytes = append(b.basketBytes, b.ctx.bk.key.Nbytes())
 	b.basketEntry = append(b.basketEntry, b.entryNumber)
@@ -853,6 +897,30 @@ func (b *tbranch) flush() error {
 	return nil
 }
 
+// optimizeBasketSize adapts the basket size of this branch to hold a
+// cluster of entries, given the number of bytes written for the provided
+// number of entries.
+func (b *tbranch) optimizeBasketSize(entries, cluster int64) {
+	if len(b.leaves) == 0 || entries <= 0 {
+		return
+	}
+
+	// reserve room for one more entry, so a cluster fits in a single basket.
+	size := b.totBytes * (cluster + 1) / entries
+	size = (size/minBasketSize + 1) * minBasketSize
+	switch {
+	case size < minBasketSize:
+		size = minBasketSize
+	case size > maxBasketSize:
+		size = maxBasketSize
+	}
+	b.basketSize = int(size)
+
+	if b.entryOffsetLen > 0 && int64(b.entryOffsetLen) <= cluster {
+		b.entryOffsetLen = int(cluster) + 1
+	}
+}
+
 // tbranchElement is a Branch for objects.
 type tbranchElement struct {
 	tbranch
@@ -913,6 +981,120 @@ func newBranchElementFromWVar(w *wtree, base *tbranch, wvar WriteVar, parent Bra
 	return b, nil
 }
 
+// newBranchObjectFromWVar creates a top-level, never split, branch for a
+// value that streams its own members.
+// Each entry of the branch holds the members of the value, as written by
+// its WStreamROOT method or as described by its StreamerInfo, without any
+// version header.
+func newBranchObjectFromWVar(w *wtree, base *tbranch, wvar WriteVar) (Branch, error) {
+	obj, ok := wvar.Value.(root.Object)
+	if !ok {
+		return nil, fmt.Errorf("rtree: write-var %q (type=%T) does not implement root.Object", wvar.Name, wvar.Value)
+	}
+
+	var (
+		class  = obj.Class()
+		chksum uint32
+		clsver uint16
+	)
+
+	streamer, err := rdict.StreamerInfos.StreamerInfo(class, -1)
+	switch {
+	case err == nil:
+		chksum = uint32(streamer.CheckSum())
+		clsver = uint16(streamer.ClassVersion())
+		w.ttree.f.RegisterStreamer(streamer)
+	case strings.HasPrefix(class, "vector<") && strings.HasSuffix(class, ">"):
+		// std::vector<T> values: only the streamer of T is stored in the file.
+		streamer, err = stdvecStreamerOf(w.ttree.f, class)
+		if err != nil {
+			return nil, fmt.Errorf("rtree: could not find streamer for write-var %q (class=%q): %w", wvar.Name, class, err)
+		}
+		clsver = rvers.StreamerInfo
+	default:
+		return nil, fmt.Errorf("rtree: could not find streamer for write-var %q (class=%q): %w", wvar.Name, class, err)
+	}
+
+	base.entryOffsetLen = int(w.ttree.defaultEntryOffsetLen)
+	b := &tbranchElement{
+		tbranch:  *base,
+		class:    class,
+		chksum:   chksum,
+		clsver:   clsver,
+		id:       -1,
+		stype:    -1,
+		streamer: streamer,
+	}
+
+	ws, ok := wvar.Value.(rbytes.WStreamer)
+	if !ok {
+		ws = rdict.WStreamerOf(w.ttree.f, streamer, wvar.Value)
+	}
+
+	leaf := &tleafElement{
+		rvers:     rvers.LeafElement,
+		tleaf:     newLeaf(wvar.Name, nil, 0, 0, false, false, nil, b),
+		id:        -1,
+		ltype:     -1,
+		wstreamer: ws,
+	}
+	err = leaf.setAddress(wvar.Value)
+	if err != nil {
+		return nil, fmt.Errorf("could not set leaf address for %q: %w", wvar.Name, err)
+	}
+	b.leaves = append(b.leaves, leaf)
+	w.ttree.leaves = append(w.ttree.leaves, leaf)
+
+	b.named.SetTitle(wvar.Name)
+	b.createNewBasket()
+	return b, nil
+}
+
+// isObjectValue returns whether the value pointed at by ptr is written as
+// a single, never split, branch: values streaming their own members and
+// values of types generated from a StreamerInfo (e.g. by root-gen-type.)
+func isObjectValue(ptr interface{}) bool {
+	switch ptr.(type) {
+	case rbytes.WStreamer:
+		return true
+	case interface {
+		root.Object
+		rbytes.Marshaler
+	}:
+		return true
+	}
+	return false
+}
+
+// stdvecStreamerOf creates the streamer of the provided std::vector<T> class
+// and registers the streamer of T with the file, if T is not a builtin.
+func stdvecStreamerOf(f *riofs.File, class string) (rbytes.StreamerInfo, error) {
+	var (
+		ename = strings.TrimSpace(class[len("vector<") : len(class)-1])
+		ctype = rmeta.Object
+	)
+
+	switch etyp, ok := rmeta.CxxBuiltins[ename]; {
+	case ok:
+		ctype = rmeta.GoType2ROOTEnum[etyp]
+	default:
+		esi, err := rdict.StreamerInfos.StreamerInfo(ename, -1)
+		if err != nil {
+			return nil, err
+		}
+		f.RegisterStreamer(esi)
+	}
+
+	return rdict.NewStreamerInfo(class, int(rvers.StreamerInfo), []rbytes.StreamerElement{
+		rdict.NewCxxStreamerSTL(rdict.Element{
+			Name:  *rbase.NewNamed(class, ""),
+			Type:  rmeta.Streamer,
+			Size:  24,
+			EName: class,
+		}.New(), rmeta.STLvector, ctype),
+	}), nil
+}
+
 func (b *tbranchElement) RVersion() int16 {
 	return rvers.BranchElement
 }
@@ -1013,6 +1195,22 @@ func (b *tbranchElement) UnmarshalROOT(r *rbytes.RBuffer) error {
 }
 
 func (b *tbranchElement) loadEntry(ientry int64) error {
+	if b.isCollection() {
+		// the number of elements of the collection is needed to read
+		// the members of these elements.
+		err := b.tbranch.loadEntry(ientry)
+		if err != nil {
+			return err
+		}
+		for _, sub := range b.branches {
+			err := sub.loadEntry(ientry)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	if len(b.branches) > 0 {
 		for _, sub := range b.branches {
 			err := sub.loadEntry(ientry)
@@ -1032,6 +1230,10 @@ func (b *tbranchElement) setAddress(ptr interface{}) error {
 		return err
 	}
 
+	if b.isCollection() {
+		return b.setCollectionAddress(sictx, ptr)
+	}
+
 	b.scanfct = func(b *tbranchElement, ptr interface{}) error {
 		return b.tbranch.scan(ptr)
 	}
@@ -1103,6 +1305,170 @@ func (b *tbranchElement) scan(ptr interface{}) error {
 	return b.scanfct(b, ptr)
 }
 
+// isCollection returns whether the branch is the master branch of a split
+// collection (a TClonesArray or a STL container.)
+func (b *tbranchElement) isCollection() bool {
+	switch b.btype {
+	case 3, 4:
+		return len(b.branches) > 0
+	}
+	return false
+}
+
+// setCollectionAddress binds the slice pointed at by ptr to a split
+// collection branch.
+// The leaf of the collection branch holds the number of elements of the
+// collection and each sub-branch holds one member of all these elements.
+func (b *tbranchElement) setCollectionAddress(sictx rbytes.StreamerInfoContext, ptr interface{}) error {
+	rv := reflect.ValueOf(ptr).Elem()
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("rtree: invalid type %T for split collection branch %q", ptr, b.Name())
+	}
+	if len(b.tbranch.leaves) != 1 {
+		return fmt.Errorf("rtree: invalid number of leaves for split collection branch %q (n=%d)", b.Name(), len(b.tbranch.leaves))
+	}
+	leaf, ok := b.tbranch.leaves[0].(*tleafElement)
+	if !ok {
+		return fmt.Errorf("rtree: invalid leaf type %T for split collection branch %q", b.tbranch.leaves[0], b.Name())
+	}
+
+	cls := b.clones
+	if cls == "" && strings.HasPrefix(b.class, "vector<") {
+		if args := rmeta.CxxTemplateArgsOf(b.class); len(args) == 1 {
+			cls = args[0]
+		}
+	}
+	si, err := sictx.StreamerInfo(cls, -1)
+	if err != nil {
+		return fmt.Errorf("rtree: no StreamerInfo for elements of split collection branch %q: %w", b.Name(), err)
+	}
+
+	var (
+		n    int32
+		zero = reflect.Zero(rv.Type().Elem())
+	)
+	leaf.ptr = &n
+	leaf.src = reflect.ValueOf(&n).Elem()
+	leaf.rstreamer = &rstreamerImpl{funcs: []rstreamerFunc{
+		func(r *rbytes.RBuffer) error {
+			n = r.ReadI32()
+			if n < 0 {
+				r.SetErr(fmt.Errorf("rtree: invalid number of elements (n=%d) in split collection branch %q", n, b.Name()))
+				return r.Err()
+			}
+			if rv.IsNil() || rv.Cap() < int(n) {
+				rv.Set(reflect.MakeSlice(rv.Type(), int(n), int(n)))
+			}
+			rv.SetLen(int(n))
+			for i := 0; i < rv.Len(); i++ {
+				rv.Index(i).Set(zero)
+			}
+			return r.Err()
+		},
+	}}
+
+	for _, sub := range b.branches {
+		sub, ok := sub.(*tbranchElement)
+		if !ok {
+			return fmt.Errorf("rtree: invalid branch type %T for member of split collection branch %q", sub, b.Name())
+		}
+		err = sub.setMemberAddress(sictx, b, si, rv)
+		if err != nil {
+			return err
+		}
+	}
+
+	b.scanfct = func(b *tbranchElement, ptr interface{}) error {
+		dst := reflect.ValueOf(ptr).Elem()
+		if dst.UnsafeAddr() != rv.UnsafeAddr() {
+			dst.Set(reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len()))
+			reflect.Copy(dst, rv)
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// setMemberAddress binds the elements of the slice sli to the branch
+// holding one member of the elements of the split collection branch coll.
+// si describes the elements of the collection.
+// Members of embedded objects are held by branches named after the path
+// to the member (e.g. "coll.obj.member".)
+func (b *tbranchElement) setMemberAddress(sictx rbytes.StreamerInfoContext, coll *tbranchElement, si rbytes.StreamerInfo, sli reflect.Value) error {
+	if len(b.tbranch.leaves) != 1 {
+		return fmt.Errorf("rtree: invalid number of leaves for branch %q (n=%d)", b.Name(), len(b.tbranch.leaves))
+	}
+	leaf, ok := b.tbranch.leaves[0].(*tleafElement)
+	if !ok {
+		return fmt.Errorf("rtree: invalid leaf type %T for branch %q", b.tbranch.leaves[0], b.Name())
+	}
+
+	var (
+		rt    = sli.Type().Elem()
+		path  []int // path to the struct holding the member, from an element.
+		names = strings.Split(strings.TrimPrefix(b.Name(), coll.Name()+"."), ".")
+	)
+	for i, name := range names {
+		if idx := strings.Index(name, "["); idx > 0 {
+			name = name[:idx]
+		}
+		var se rbytes.StreamerElement
+		for _, elt := range si.Elements() {
+			if elt.Name() == name {
+				se = elt
+				break
+			}
+		}
+		if se == nil {
+			return fmt.Errorf("rtree: failed to find StreamerElement for branch %q", b.Name())
+		}
+
+		if i == len(names)-1 {
+			var (
+				field   = fieldOf(rt, name)
+				scratch = reflect.New(rt)
+				rfunc   = rstreamerFrom(se, scratch.Interface(), nil, sictx)
+			)
+			leaf.streamers = []rbytes.StreamerElement{se}
+			leaf.rstreamer = &rstreamerImpl{funcs: []rstreamerFunc{
+				func(r *rbytes.RBuffer) error {
+					for i := 0; i < sli.Len(); i++ {
+						err := rfunc(r)
+						if err != nil {
+							return err
+						}
+						if field < 0 {
+							// member removed from the Go type.
+							continue
+						}
+						src := scratch.Elem().Field(field)
+						sli.Index(i).FieldByIndex(path).Field(field).Set(src)
+						src.Set(reflect.Zero(src.Type()))
+					}
+					return r.Err()
+				},
+			}}
+			break
+		}
+
+		field := fieldOf(rt, name)
+		if field < 0 {
+			return fmt.Errorf("rtree: no field for member %q of branch %q in type %v", name, b.Name(), rt)
+		}
+		path = append(path, field)
+		rt = rt.Field(field).Type
+
+		var err error
+		si, err = sictx.StreamerInfo(strings.TrimRight(se.TypeName(), "*"), -1)
+		if err != nil {
+			return fmt.Errorf("rtree: no StreamerInfo for member %q of branch %q: %w", name, b.Name(), err)
+		}
+	}
+
+	return nil
+}
+
 func (b *tbranchElement) setupReadStreamer(sictx rbytes.StreamerInfoContext) error {
 	streamer, err := sictx.StreamerInfo(b.class, int(b.clsver))
 	if err != nil {
@@ -1165,8 +1531,19 @@ func (b *tbranchElement) setStreamerElement(se rbytes.StreamerElement, ctx rbyte
 	}
 }
 
-func (b *tbranchElement) write() (int, error)                          { panic("not implemented") }
-func (b *tbranchElement) writeToBuffer(w *rbytes.WBuffer) (int, error) { panic("not implemented") }
+func (b *tbranchElement) write() (int, error) {
+	if len(b.branches) > 0 {
+		panic("not implemented")
+	}
+	return b.tbranch.write()
+}
+
+func (b *tbranchElement) writeToBuffer(w *rbytes.WBuffer) (int, error) {
+	if len(b.branches) > 0 {
+		panic("not implemented")
+	}
+	return b.tbranch.writeToBuffer(w)
+}
 
 func btopOf(b Branch) Branch {
 	if b == nil {
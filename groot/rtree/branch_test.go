@@ -6,9 +6,18 @@ package rtree
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rdict"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/rmeta"
+	"go-hep.org/x/hep/groot/rvers"
 )
 
 func TestBranchSetAddress(t *testing.T) {
@@ -135,3 +144,254 @@ func TestBranchSetAddress(t *testing.T) {
 		})
 	}
 }
+
+type wstreamerFunc func(w *rbytes.WBuffer) error
+
+func (f wstreamerFunc) WStreamROOT(w *rbytes.WBuffer) error { return f(w) }
+
+func TestBranchSplitCollection(t *testing.T) {
+	type Vec struct {
+		X float64 `groot:"x"`
+		Y float64 `groot:"y"`
+	}
+	type Part struct {
+		Pid  int32   `groot:"pid"`
+		Mass float64 `groot:"mass"`
+		Mom  Vec     `groot:"mom"`
+	}
+	type Evt struct {
+		Num   int32  `groot:"num"`
+		Parts []Part `groot:"parts"`
+	}
+
+	const nevts = 5
+	want := func(i int) Evt {
+		evt := Evt{Num: int32(i), Parts: make([]Part, i%3)}
+		for j := range evt.Parts {
+			evt.Parts[j] = Part{
+				Pid:  int32(10*i + j),
+				Mass: float64(i) + 0.5*float64(j),
+				Mom:  Vec{X: float64(i), Y: -float64(j)},
+			}
+		}
+		return evt
+	}
+
+	tmp, err := ioutil.TempDir("", "groot-rtree-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	fname := filepath.Join(tmp, "split-collection.root")
+
+	// write a tree with the layout of a C++ split std::vector<Part>:
+	//  - "evt" holds no data,
+	//  - "parts" holds the number of elements of the collection,
+	//  - "parts.xxx" hold the member xxx of all the elements.
+	func() {
+		f, err := riofs.Create(fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		double := func(name string) rbytes.StreamerElement {
+			return &rdict.StreamerBasicType{StreamerElement: rdict.Element{
+				Name:  *rbase.NewNamed(name, ""),
+				Type:  rmeta.Double,
+				Size:  8,
+				EName: "double",
+			}.New()}
+		}
+		integer := func(name string) rbytes.StreamerElement {
+			return &rdict.StreamerBasicType{StreamerElement: rdict.Element{
+				Name:  *rbase.NewNamed(name, ""),
+				Type:  rmeta.Int,
+				Size:  4,
+				EName: "int",
+			}.New()}
+		}
+		f.RegisterStreamer(rdict.NewCxxStreamerInfo("SplitVec", 1, 0x1, []rbytes.StreamerElement{
+			double("x"), double("y"),
+		}))
+		f.RegisterStreamer(rdict.NewCxxStreamerInfo("SplitPart", 1, 0x2, []rbytes.StreamerElement{
+			integer("pid"), double("mass"),
+			&rdict.StreamerObjectAny{StreamerElement: rdict.Element{
+				Name:  *rbase.NewNamed("mom", ""),
+				Type:  rmeta.Any,
+				Size:  16,
+				EName: "SplitVec",
+			}.New()},
+		}))
+		f.RegisterStreamer(rdict.NewCxxStreamerInfo("SplitEvt", 1, 0x3, []rbytes.StreamerElement{
+			integer("num"),
+			rdict.NewCxxStreamerSTL(rdict.Element{
+				Name:  *rbase.NewNamed("parts", ""),
+				Type:  rmeta.Streamer,
+				Size:  24,
+				EName: "vector<SplitPart>",
+			}.New(), rmeta.STLvector, rmeta.Object),
+		}))
+
+		tw, err := NewWriter(f, "tree", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := tw.(*wtree)
+
+		var (
+			evt  Evt
+			subs []*tbranchElement
+		)
+		newBranch := func(name, class string, id, btype int32, parent *tbranchElement, count leafCount, ws wstreamerFunc) *tbranchElement {
+			b := &tbranchElement{
+				tbranch: tbranch{
+					named:          *rbase.NewNamed(name, name),
+					attfill:        *rbase.NewAttFill(),
+					basketSize:     defaultBasketSize,
+					entryOffsetLen: int(w.ttree.defaultEntryOffsetLen),
+					maxBaskets:     defaultMaxBaskets,
+					basketBytes:    make([]int32, 0, defaultMaxBaskets),
+					basketEntry:    make([]int64, 1, defaultMaxBaskets),
+					basketSeek:     make([]int64, 0, defaultMaxBaskets),
+					tree:           &w.ttree,
+					dir:            f,
+				},
+				class:  class,
+				parent: "SplitEvt",
+				clsver: 1,
+				id:     id,
+				btype:  btype,
+				stype:  -1,
+			}
+			lname := name
+			if btype == 4 {
+				lname += "_"
+			}
+			leaf := &tleafElement{
+				rvers: rvers.LeafElement,
+				tleaf: newLeaf(lname, nil, 0, 0, false, false, count, b),
+				id:    id,
+				ltype: -1,
+			}
+			b.leaves = []Leaf{leaf}
+			w.ttree.leaves = append(w.ttree.leaves, leaf)
+			if parent != nil {
+				b.bup = parent
+				b.btop = btopOf(parent)
+				parent.branches = append(parent.branches, b)
+			}
+			if ws != nil {
+				leaf.wstreamer = ws
+				b.createNewBasket()
+				subs = append(subs, b)
+			}
+			return b
+		}
+
+		top := newBranch("evt", "SplitEvt", -2, 0, nil, nil, nil)
+		top.parent = ""
+		w.ttree.branches = append(w.ttree.branches, top)
+
+		newBranch("num", "SplitEvt", 0, 0, top, nil, func(w *rbytes.WBuffer) error {
+			w.WriteI32(evt.Num)
+			return w.Err()
+		})
+		parts := newBranch("parts", "SplitEvt", 1, 4, top, nil, func(w *rbytes.WBuffer) error {
+			w.WriteI32(int32(len(evt.Parts)))
+			return w.Err()
+		})
+		parts.clones = "SplitPart"
+		count := parts.leaves[0].(leafCount)
+		newBranch("parts.pid", "SplitPart", 0, 41, parts, count, func(w *rbytes.WBuffer) error {
+			for _, p := range evt.Parts {
+				w.WriteI32(p.Pid)
+			}
+			return w.Err()
+		})
+		newBranch("parts.mass", "SplitPart", 1, 41, parts, count, func(w *rbytes.WBuffer) error {
+			for _, p := range evt.Parts {
+				w.WriteF64(p.Mass)
+			}
+			return w.Err()
+		})
+		newBranch("parts.mom.x", "SplitVec", 0, 41, parts, count, func(w *rbytes.WBuffer) error {
+			for _, p := range evt.Parts {
+				w.WriteF64(p.Mom.X)
+			}
+			return w.Err()
+		})
+		newBranch("parts.mom.y", "SplitVec", 1, 41, parts, count, func(w *rbytes.WBuffer) error {
+			for _, p := range evt.Parts {
+				w.WriteF64(p.Mom.Y)
+			}
+			return w.Err()
+		})
+
+		for i := 0; i < nevts; i++ {
+			evt = want(i)
+			for _, b := range subs {
+				_, err := b.tbranch.write()
+				if err != nil {
+					t.Fatalf("could not write branch %q: %+v", b.Name(), err)
+				}
+			}
+			w.ttree.entries++
+		}
+		top.entries = nevts
+
+		for _, b := range top.branches {
+			err := b.flush()
+			if err != nil {
+				t.Fatalf("could not flush branch %q: %+v", b.Name(), err)
+			}
+		}
+		err = w.save()
+		if err != nil {
+			t.Fatalf("could not save tree: %+v", err)
+		}
+		w.closed = true
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+	}()
+
+	f, err := riofs.Open(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	obj, err := f.Get("tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := obj.(Tree)
+
+	var evt Evt
+	sc, err := NewScannerVars(tree, ReadVar{Name: "evt", Value: &evt})
+	if err != nil {
+		t.Fatalf("could not create scanner: %+v", err)
+	}
+	defer sc.Close()
+
+	n := 0
+	for sc.Next() {
+		err := sc.Scan()
+		if err != nil {
+			t.Fatalf("could not scan entry %d: %+v", sc.Entry(), err)
+		}
+		if got, want := evt, want(int(sc.Entry())); !reflect.DeepEqual(got, want) {
+			t.Fatalf("invalid entry %d:\ngot= %+v\nwant=%+v", sc.Entry(), got, want)
+		}
+		n++
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatalf("could not scan tree: %+v", err)
+	}
+	if n != nevts {
+		t.Fatalf("invalid number of entries: got=%d, want=%d", n, nevts)
+	}
+}
@@ -0,0 +1,515 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rcont"
+	"go-hep.org/x/hep/groot/root"
+	"go-hep.org/x/hep/groot/rtypes"
+	"go-hep.org/x/hep/groot/rvers"
+)
+
+const (
+	entryListBlockSize = 64000 // number of entries held by a TEntryListBlock
+	entryListBlockBits = 4000  // number of 16-bits words of a TEntryListBlock in bits mode
+)
+
+// EntryList is a list of selected entries of a Tree.
+//
+// EntryList can be filled from a Reader loop (see EntryList.Enter),
+// saved to and read back from a ROOT file (as a TEntryList), and applied
+// to a Reader or a Scanner so that only the selected entries are read.
+//
+// The EntryList of a chain of trees holds a sub-list of entries for each
+// tree of the chain.
+type EntryList struct {
+	rvers int16
+	named rbase.Named
+
+	lists   []*EntryList // sub-lists for each tree of a chain
+	entries []int64      // sorted selected entries of the tree
+	n       int64        // number of entries in the list
+	nproc   int64        // number of entries to process
+	tname   string       // name of the tree
+	fname   string       // name of the file holding the tree
+	reapply bool         // whether the original cut should be re-applied
+
+	tree Tree // tree (or chain) the list was created for
+}
+
+// NewEntryList creates a new, empty, list of selected entries for the
+// provided tree.
+// Entries of a chain of trees are recorded into a sub-list for each tree.
+func NewEntryList(name, title string, t Tree) *EntryList {
+	l := &EntryList{
+		rvers: rvers.EntryList,
+		named: *rbase.NewNamed(name, title),
+		tree:  t,
+	}
+	if t != nil {
+		l.tname = t.Name()
+		if _, ok := t.(*tchain); !ok {
+			if f := t.getFile(); f != nil {
+				l.fname = f.Name()
+			}
+		}
+	}
+	return l
+}
+
+func (*EntryList) RVersion() int16 {
+	return rvers.EntryList
+}
+
+func (*EntryList) Class() string {
+	return "TEntryList"
+}
+
+func (l *EntryList) Name() string {
+	return l.named.Name()
+}
+
+func (l *EntryList) Title() string {
+	return l.named.Title()
+}
+
+// TreeName returns the name of the tree this list of entries applies to.
+func (l *EntryList) TreeName() string { return l.tname }
+
+// FileName returns the name of the file holding the tree this list of
+// entries applies to.
+func (l *EntryList) FileName() string { return l.fname }
+
+// Len returns the number of entries in the list, including the entries of
+// all its sub-lists.
+func (l *EntryList) Len() int64 { return l.n }
+
+// Lists returns the sub-lists of entries, one for each tree of a chain.
+func (l *EntryList) Lists() []*EntryList { return l.lists }
+
+// Entries returns the sorted list of entries held by this list, excluding
+// the entries of its sub-lists.
+func (l *EntryList) Entries() []int64 { return l.entries }
+
+// Contains returns whether the provided entry is part of the list.
+// For the list of a chain, entry is the global entry number in the chain.
+func (l *EntryList) Contains(entry int64) bool {
+	if ch, ok := l.tree.(*tchain); ok && len(l.lists) > 0 {
+		j := ch.findTree(entry)
+		if j < 0 {
+			return false
+		}
+		sub := l.listFor(ch.trees[j])
+		if sub == nil {
+			return false
+		}
+		return sub.Contains(entry - ch.offs[j])
+	}
+
+	i := sort.Search(len(l.entries), func(i int) bool { return l.entries[i] >= entry })
+	return i < len(l.entries) && l.entries[i] == entry
+}
+
+// Enter adds the provided entry to the list.
+// For the list of a chain, entry is the global entry number in the chain.
+// Entering an entry already in the list is a no-op.
+func (l *EntryList) Enter(entry int64) error {
+	if entry < 0 {
+		return fmt.Errorf("rtree: invalid negative entry %d", entry)
+	}
+
+	ch, ok := l.tree.(*tchain)
+	if !ok {
+		if l.tree != nil && entry >= l.tree.Entries() {
+			return fmt.Errorf("rtree: entry %d out of tree %q range [0, %d)", entry, l.tname, l.tree.Entries())
+		}
+		if l.enter(entry) {
+			l.n++
+		}
+		return nil
+	}
+
+	j := ch.findTree(entry)
+	if j < 0 {
+		return fmt.Errorf("rtree: entry %d out of chain %q range [0, %d)", entry, l.tname, ch.Entries())
+	}
+
+	t := ch.trees[j]
+	sub := l.listFor(t)
+	if sub == nil {
+		sub = NewEntryList(l.Name(), l.Title(), t)
+		l.lists = append(l.lists, sub)
+	}
+	if sub.enter(entry - ch.offs[j]) {
+		sub.n++
+		l.n++
+	}
+	return nil
+}
+
+// enter inserts the provided entry into the sorted list of entries.
+// enter returns whether the entry was not already in the list.
+func (l *EntryList) enter(entry int64) bool {
+	n := len(l.entries)
+	if n == 0 || l.entries[n-1] < entry {
+		l.entries = append(l.entries, entry)
+		return true
+	}
+
+	i := sort.Search(n, func(i int) bool { return l.entries[i] >= entry })
+	if l.entries[i] == entry {
+		return false
+	}
+	l.entries = append(l.entries, 0)
+	copy(l.entries[i+1:], l.entries[i:])
+	l.entries[i] = entry
+	return true
+}
+
+// listFor returns the sub-list associated with the tree t.
+// Sub-lists are matched by tree and file names, and then by tree name and
+// file base name (to allow for files that were moved around.)
+func (l *EntryList) listFor(t Tree) *EntryList {
+	fname := ""
+	if f := t.getFile(); f != nil {
+		fname = f.Name()
+	}
+	for _, sub := range l.lists {
+		if sub.tname == t.Name() && sub.fname == fname {
+			return sub
+		}
+	}
+	for _, sub := range l.lists {
+		if sub.tname == t.Name() && filepath.Base(sub.fname) == filepath.Base(fname) {
+			return sub
+		}
+	}
+	return nil
+}
+
+// selection returns the sorted list of selected entries of the provided
+// tree (or chain of trees.)
+func (l *EntryList) selection(t Tree) ([]int64, error) {
+	sel := make([]int64, 0, l.n)
+
+	ch, ok := t.(*tchain)
+	if !ok {
+		sub := l
+		if len(l.lists) > 0 {
+			sub = l.listFor(t)
+			if sub == nil {
+				return nil, fmt.Errorf("rtree: entry list %q has no sub-list for tree %q", l.Name(), t.Name())
+			}
+		}
+		for _, entry := range sub.entries {
+			if entry >= t.Entries() {
+				break
+			}
+			sel = append(sel, entry)
+		}
+		return sel, nil
+	}
+
+	if len(l.lists) == 0 && len(l.entries) > 0 {
+		return nil, fmt.Errorf("rtree: entry list %q has no sub-lists for chain %q", l.Name(), t.Name())
+	}
+
+	for j, tree := range ch.trees {
+		sub := l.listFor(tree)
+		if sub == nil {
+			continue
+		}
+		for _, entry := range sub.entries {
+			if entry >= tree.Entries() {
+				break
+			}
+			sel = append(sel, ch.offs[j]+entry)
+		}
+	}
+	return sel, nil
+}
+
+// blocks returns the entries of this list, organized in blocks of
+// entryListBlockSize entries.
+func (l *EntryList) blocks() []root.Object {
+	if len(l.entries) == 0 {
+		return nil
+	}
+
+	last := l.entries[len(l.entries)-1] / entryListBlockSize
+	blocks := make([]root.Object, last+1)
+	beg := 0
+	for ib := range blocks {
+		end := beg
+		for end < len(l.entries) && l.entries[end]/entryListBlockSize == int64(ib) {
+			end++
+		}
+		blocks[ib] = newEntryListBlock(l.entries[beg:end], int64(ib)*entryListBlockSize)
+		beg = end
+	}
+	return blocks
+}
+
+func (l *EntryList) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(l.RVersion())
+	if n, err := l.named.MarshalROOT(w); err != nil {
+		return n, err
+	}
+
+	{
+		var obj root.Object
+		if len(l.lists) > 0 {
+			elems := make([]root.Object, len(l.lists))
+			for i, sub := range l.lists {
+				elems[i] = sub
+			}
+			obj = rcont.NewList("", elems)
+		}
+		if err := w.WriteObjectAny(obj); err != nil {
+			return int(w.Pos() - pos), err
+		}
+	}
+
+	blocks := l.blocks()
+	w.WriteI32(int32(len(blocks)))
+	{
+		var obj root.Object
+		if len(blocks) > 0 {
+			arr := rcont.NewObjArray()
+			arr.SetElems(blocks)
+			obj = arr
+		}
+		if err := w.WriteObjectAny(obj); err != nil {
+			return int(w.Pos() - pos), err
+		}
+	}
+
+	w.WriteI64(l.n)
+	w.WriteI64(l.nproc)
+	w.WriteString(l.tname)
+	w.WriteString(l.fname)
+	w.WriteBool(l.reapply)
+
+	return w.SetByteCount(pos, l.Class())
+}
+
+func (l *EntryList) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	beg := r.Pos()
+	vers, pos, bcnt := r.ReadVersion(l.Class())
+	l.rvers = vers
+
+	if err := l.named.UnmarshalROOT(r); err != nil {
+		return err
+	}
+
+	l.lists = nil
+	if v := r.ReadObjectAny(); v != nil {
+		lists := v.(*rcont.List)
+		l.lists = make([]*EntryList, 0, lists.Len())
+		for i := 0; i < lists.Len(); i++ {
+			sub, ok := lists.At(i).(*EntryList)
+			if !ok {
+				return fmt.Errorf("rtree: invalid entry list element type %T", lists.At(i))
+			}
+			l.lists = append(l.lists, sub)
+		}
+	}
+
+	_ = r.ReadI32() // fNBlocks
+	l.entries = nil
+	if v := r.ReadObjectAny(); v != nil {
+		blocks := v.(*rcont.ObjArray)
+		for i := 0; i < blocks.Len(); i++ {
+			blk, ok := blocks.At(i).(*entryListBlock)
+			if !ok || blk == nil {
+				continue
+			}
+			l.entries = blk.appendEntries(l.entries, int64(i)*entryListBlockSize)
+		}
+	}
+
+	l.n = r.ReadI64()
+	l.nproc = r.ReadI64()
+	l.tname = r.ReadString()
+	l.fname = r.ReadString()
+	if vers > 1 {
+		l.reapply = r.ReadBool()
+	}
+
+	r.CheckByteCount(pos, bcnt, beg, l.Class())
+	return r.Err()
+}
+
+// entryListBlock holds the selected entries of a range of
+// entryListBlockSize entries.
+// Entries are either stored as a list of indices or as a bit field.
+type entryListBlock struct {
+	obj     rbase.Object
+	npassed int32    // number of entries in the block (or not in the block, if passing is false)
+	indices []uint16 // indices or bits of the entries
+	typ     int32    // 0: bits, 1: list
+	passing bool     // whether indices are the ones of the selected entries
+}
+
+func newEntryListBlock(entries []int64, offset int64) *entryListBlock {
+	blk := &entryListBlock{
+		obj:     *rbase.NewObject(),
+		npassed: int32(len(entries)),
+		passing: true,
+	}
+	switch {
+	case len(entries) < entryListBlockBits:
+		blk.typ = 1
+		blk.indices = make([]uint16, len(entries))
+		for i, entry := range entries {
+			blk.indices[i] = uint16(entry - offset)
+		}
+	default:
+		blk.typ = 0
+		blk.indices = make([]uint16, entryListBlockBits)
+		for _, entry := range entries {
+			i := entry - offset
+			blk.indices[i>>4] |= 1 << uint(i&15)
+		}
+	}
+	return blk
+}
+
+// appendEntries appends the entries of this block to the provided slice,
+// adding offset to each of them.
+func (blk *entryListBlock) appendEntries(entries []int64, offset int64) []int64 {
+	switch blk.typ {
+	case 0:
+		for i, bits := range blk.indices {
+			for j := 0; j < 16; j++ {
+				if bits&(1<<uint(j)) != 0 {
+					entries = append(entries, offset+int64(16*i+j))
+				}
+			}
+		}
+	case 1:
+		if blk.passing {
+			for _, i := range blk.indices {
+				entries = append(entries, offset+int64(i))
+			}
+			return entries
+		}
+		// indices are the ones of the entries not in the list.
+		var (
+			n    = int32(0)
+			skip = blk.indices
+		)
+		for i := 0; i < entryListBlockSize && n < blk.npassed; i++ {
+			if len(skip) > 0 && int(skip[0]) == i {
+				skip = skip[1:]
+				continue
+			}
+			entries = append(entries, offset+int64(i))
+			n++
+		}
+	}
+	return entries
+}
+
+func (*entryListBlock) RVersion() int16 {
+	return rvers.EntryListBlock
+}
+
+func (*entryListBlock) Class() string {
+	return "TEntryListBlock"
+}
+
+func (blk *entryListBlock) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(blk.RVersion())
+	if n, err := blk.obj.MarshalROOT(w); err != nil {
+		return n, err
+	}
+	w.WriteI32(blk.npassed)
+	w.WriteI32(int32(len(blk.indices)))
+	switch len(blk.indices) {
+	case 0:
+		w.WriteI8(0)
+	default:
+		w.WriteI8(1)
+		w.WriteFastArrayU16(blk.indices)
+	}
+	w.WriteI32(blk.typ)
+	w.WriteBool(blk.passing)
+
+	return w.SetByteCount(pos, blk.Class())
+}
+
+func (blk *entryListBlock) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	beg := r.Pos()
+	_, pos, bcnt := r.ReadVersion(blk.Class())
+
+	if err := blk.obj.UnmarshalROOT(r); err != nil {
+		return err
+	}
+
+	blk.npassed = r.ReadI32()
+	n := int(r.ReadI32())
+	blk.indices = nil
+	if r.ReadI8() != 0 {
+		blk.indices = make([]uint16, n)
+		r.ReadArrayU16(blk.indices)
+	}
+	blk.typ = r.ReadI32()
+	blk.passing = r.ReadBool()
+
+	r.CheckByteCount(pos, bcnt, beg, blk.Class())
+	return r.Err()
+}
+
+func init() {
+	{
+		f := func() reflect.Value {
+			o := &EntryList{}
+			return reflect.ValueOf(o)
+		}
+		rtypes.Factory.Add("TEntryList", f)
+	}
+	{
+		f := func() reflect.Value {
+			o := &entryListBlock{}
+			return reflect.ValueOf(o)
+		}
+		rtypes.Factory.Add("TEntryListBlock", f)
+	}
+}
+
+var (
+	_ root.Object        = (*EntryList)(nil)
+	_ root.Named         = (*EntryList)(nil)
+	_ rbytes.Marshaler   = (*EntryList)(nil)
+	_ rbytes.Unmarshaler = (*EntryList)(nil)
+
+	_ root.Object        = (*entryListBlock)(nil)
+	_ rbytes.Marshaler   = (*entryListBlock)(nil)
+	_ rbytes.Unmarshaler = (*entryListBlock)(nil)
+)
@@ -0,0 +1,384 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/riofs"
+)
+
+type elistEvent struct {
+	I int64   `groot:"i"`
+	X float64 `groot:"x"`
+}
+
+func elistSelect(i int64) bool { return i%60 < 5 }
+
+// readSelected reads the provided tree with the provided entry list and
+// returns the entries that were visited.
+func readSelected(t *testing.T, tree Tree, elist *EntryList) []int64 {
+	t.Helper()
+
+	var evt elistEvent
+	r, err := NewReader(tree, ReadVarsFromStruct(&evt), WithEntryList(elist))
+	if err != nil {
+		t.Fatalf("could not create reader: %+v", err)
+	}
+	defer r.Close()
+
+	var entries []int64
+	err = r.Read(func(ctx RCtx) error {
+		if evt.I != ctx.Entry {
+			return fmt.Errorf("invalid event data: got=%d, want=%d", evt.I, ctx.Entry)
+		}
+		entries = append(entries, ctx.Entry)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not read tree: %+v", err)
+	}
+	return entries
+}
+
+func TestEntryList(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rtree-")
+	if err != nil {
+		t.Fatalf("could not create dir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	const nevts = 500
+
+	var (
+		fname = filepath.Join(tmp, "tree.root")
+		lname = filepath.Join(tmp, "elist.root")
+		evt   elistEvent
+	)
+	writeFriendTree(t, fname, "tree", &evt, nevts, func(i int) {
+		evt.I = int64(i)
+		evt.X = float64(i)
+	}, nil)
+
+	f, tree := openFriendTree(t, fname, "tree")
+	defer f.Close()
+
+	elist := NewEntryList("elist", "selected entries", tree)
+	var want []int64
+	{
+		var x float64
+		r, err := NewReader(tree, []ReadVar{{Name: "x", Value: &x}})
+		if err != nil {
+			t.Fatalf("could not create reader: %+v", err)
+		}
+		err = r.Read(func(ctx RCtx) error {
+			if !elistSelect(int64(x)) {
+				return nil
+			}
+			want = append(want, ctx.Entry)
+			return elist.Enter(ctx.Entry)
+		})
+		if err != nil {
+			t.Fatalf("could not fill entry list: %+v", err)
+		}
+		r.Close()
+	}
+
+	if got, want := elist.Len(), int64(len(want)); got != want {
+		t.Fatalf("invalid entry list length: got=%d, want=%d", got, want)
+	}
+	if got, want := elist.TreeName(), "tree"; got != want {
+		t.Fatalf("invalid tree name: got=%q, want=%q", got, want)
+	}
+	if got, want := elist.FileName(), fname; got != want {
+		t.Fatalf("invalid file name: got=%q, want=%q", got, want)
+	}
+	for _, i := range []int64{0, 4, 5, 59, 60, 61, 499} {
+		if got, want := elist.Contains(i), elistSelect(i); got != want {
+			t.Fatalf("invalid contains(%d): got=%v, want=%v", i, got, want)
+		}
+	}
+
+	// entering an entry twice is a no-op.
+	if err := elist.Enter(want[0]); err != nil {
+		t.Fatalf("could not re-enter entry: %+v", err)
+	}
+	if got, want := elist.Len(), int64(len(want)); got != want {
+		t.Fatalf("invalid entry list length: got=%d, want=%d", got, want)
+	}
+
+	for _, tc := range []struct {
+		entry int64
+		err   string
+	}{
+		{-1, "rtree: invalid negative entry -1"},
+		{nevts, `rtree: entry 500 out of tree "tree" range [0, 500)`},
+	} {
+		err := elist.Enter(tc.entry)
+		if err == nil {
+			t.Fatalf("expected an error for entry %d", tc.entry)
+		}
+		if got, want := err.Error(), tc.err; got != want {
+			t.Fatalf("invalid error:\ngot= %v\nwant=%v", got, want)
+		}
+	}
+
+	// fresh tree, to check which baskets were loaded.
+	f2, tree2 := openFriendTree(t, fname, "tree")
+	defer f2.Close()
+
+	got := readSelected(t, tree2, elist)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid selected entries:\ngot= %v\nwant=%v", got, want)
+	}
+
+	br := tree2.Branch("x").(*tbranch)
+	skipped := 0
+	for ib := 0; ib < len(br.basketEntry)-1; ib++ {
+		beg, end := br.basketEntry[ib], br.basketEntry[ib+1]
+		selected := false
+		for i := beg; i < end; i++ {
+			if elistSelect(i) {
+				selected = true
+				break
+			}
+		}
+		if selected {
+			continue
+		}
+		skipped++
+		if ib < len(br.baskets) && br.baskets[ib].rbuf != nil {
+			t.Fatalf("basket %d [%d, %d) with no selected entries was loaded", ib, beg, end)
+		}
+	}
+	if skipped == 0 {
+		t.Fatalf("no basket could be skipped")
+	}
+
+	// with a range.
+	{
+		var evt elistEvent
+		r, err := NewReader(tree2, ReadVarsFromStruct(&evt), WithEntryList(elist), WithRange(60, 130))
+		if err != nil {
+			t.Fatalf("could not create reader: %+v", err)
+		}
+		defer r.Close()
+
+		var got []int64
+		err = r.Read(func(ctx RCtx) error {
+			got = append(got, evt.I)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("could not read tree: %+v", err)
+		}
+		want := []int64{60, 61, 62, 63, 64, 120, 121, 122, 123, 124}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("invalid selected entries:\ngot= %v\nwant=%v", got, want)
+		}
+	}
+
+	// round-trip through a ROOT file.
+	func() {
+		f, err := riofs.Create(lname)
+		if err != nil {
+			t.Fatalf("could not create file: %+v", err)
+		}
+		defer f.Close()
+
+		err = f.Put(elist.Name(), elist)
+		if err != nil {
+			t.Fatalf("could not save entry list: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+	}()
+
+	f3, tree3 := openFriendTree(t, fname, "tree")
+	defer f3.Close()
+
+	fl, err := riofs.Open(lname)
+	if err != nil {
+		t.Fatalf("could not open file: %+v", err)
+	}
+	defer fl.Close()
+
+	o, err := fl.Get("elist")
+	if err != nil {
+		t.Fatalf("could not read entry list: %+v", err)
+	}
+	rlist := o.(*EntryList)
+
+	if got, want := rlist.Len(), elist.Len(); got != want {
+		t.Fatalf("invalid entry list length: got=%d, want=%d", got, want)
+	}
+	if got, want := rlist.Entries(), elist.Entries(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid entries:\ngot= %v\nwant=%v", got, want)
+	}
+	if got, want := rlist.TreeName(), elist.TreeName(); got != want {
+		t.Fatalf("invalid tree name: got=%q, want=%q", got, want)
+	}
+
+	got = readSelected(t, tree3, rlist)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid selected entries:\ngot= %v\nwant=%v", got, want)
+	}
+}
+
+func TestEntryListChain(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rtree-")
+	if err != nil {
+		t.Fatalf("could not create dir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	const nevts = 100
+
+	var (
+		trees []Tree
+		evt   elistEvent
+	)
+	for i := 0; i < 3; i++ {
+		fname := filepath.Join(tmp, fmt.Sprintf("chain-%d.root", i))
+		off := int64(i * nevts)
+		writeFriendTree(t, fname, "tree", &evt, nevts, func(i int) {
+			evt.I = off + int64(i)
+			evt.X = float64(evt.I)
+		}, nil)
+
+		f, tree := openFriendTree(t, fname, "tree")
+		defer f.Close()
+		trees = append(trees, tree)
+	}
+
+	// select entries from the first and last trees.
+	sel := func(i int64) bool {
+		return (i < nevts && i%10 == 0) || (i >= 2*nevts && i%10 == 9)
+	}
+
+	chain := Chain(trees...)
+	elist := NewEntryList("elist", "", chain)
+	var want []int64
+	for i := int64(0); i < chain.Entries(); i++ {
+		if !sel(i) {
+			continue
+		}
+		want = append(want, i)
+		err := elist.Enter(i)
+		if err != nil {
+			t.Fatalf("could not enter entry %d: %+v", i, err)
+		}
+	}
+
+	if got, want := elist.Len(), int64(len(want)); got != want {
+		t.Fatalf("invalid entry list length: got=%d, want=%d", got, want)
+	}
+	if got, want := len(elist.Lists()), 2; got != want {
+		t.Fatalf("invalid number of sub-lists: got=%d, want=%d", got, want)
+	}
+	for i, sub := range elist.Lists() {
+		if got, want := sub.FileName(), FileOf(trees[2*i]).Name(); got != want {
+			t.Fatalf("invalid sub-list %d file name: got=%q, want=%q", i, got, want)
+		}
+	}
+	if !elist.Contains(209) || elist.Contains(150) || elist.Contains(1) {
+		t.Fatalf("invalid contains")
+	}
+
+	got := readSelected(t, chain, elist)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid selected entries:\ngot= %v\nwant=%v", got, want)
+	}
+
+	// round-trip through a ROOT buffer.
+	wbuf := rbytes.NewWBuffer(nil, nil, 0, nil)
+	_, err = elist.MarshalROOT(wbuf)
+	if err != nil {
+		t.Fatalf("could not marshal entry list: %+v", err)
+	}
+
+	var rlist EntryList
+	err = rlist.UnmarshalROOT(rbytes.NewRBuffer(wbuf.Bytes(), nil, 0, nil))
+	if err != nil {
+		t.Fatalf("could not unmarshal entry list: %+v", err)
+	}
+
+	got = readSelected(t, Chain(trees...), &rlist)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid selected entries:\ngot= %v\nwant=%v", got, want)
+	}
+}
+
+func TestEntryListBlocks(t *testing.T) {
+	elist := NewEntryList("elist", "", nil)
+	var want []int64
+	for i := int64(0); i < 3*entryListBlockSize; i++ {
+		switch {
+		case i < entryListBlockSize && i%3 == 0: // bits
+			want = append(want, i)
+		case i >= 2*entryListBlockSize && i%100 == 0: // list
+			want = append(want, i)
+		}
+	}
+	// enter entries in reverse order.
+	for i := len(want) - 1; i >= 0; i-- {
+		err := elist.Enter(want[i])
+		if err != nil {
+			t.Fatalf("could not enter entry %d: %+v", want[i], err)
+		}
+	}
+	if got := elist.Entries(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid entries")
+	}
+
+	blocks := elist.blocks()
+	if got, want := len(blocks), 3; got != want {
+		t.Fatalf("invalid number of blocks: got=%d, want=%d", got, want)
+	}
+	for i, want := range []int32{0, 1, 1} {
+		if got := blocks[i].(*entryListBlock).typ; got != want {
+			t.Fatalf("invalid block %d type: got=%d, want=%d", i, got, want)
+		}
+	}
+
+	wbuf := rbytes.NewWBuffer(nil, nil, 0, nil)
+	_, err := elist.MarshalROOT(wbuf)
+	if err != nil {
+		t.Fatalf("could not marshal entry list: %+v", err)
+	}
+
+	var rlist EntryList
+	err = rlist.UnmarshalROOT(rbytes.NewRBuffer(wbuf.Bytes(), nil, 0, nil))
+	if err != nil {
+		t.Fatalf("could not unmarshal entry list: %+v", err)
+	}
+
+	if got, want := rlist.Len(), int64(len(want)); got != want {
+		t.Fatalf("invalid entry list length: got=%d, want=%d", got, want)
+	}
+	if got := rlist.Entries(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid round-tripped entries")
+	}
+
+	// block storing the entries not in the list.
+	blk := entryListBlock{
+		npassed: 5,
+		indices: []uint16{1, 3},
+		typ:     1,
+		passing: false,
+	}
+	if got, want := blk.appendEntries(nil, 10), []int64{10, 12, 14, 15, 16}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid non-passing entries: got=%v, want=%v", got, want)
+	}
+}
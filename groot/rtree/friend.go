@@ -0,0 +1,401 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rcont"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/root"
+	"go-hep.org/x/hep/groot/rtypes"
+	"go-hep.org/x/hep/groot/rvers"
+)
+
+// friendElement describes a friend of a TTree.
+//
+// The name of the friend element is the alias under which the branches of
+// the friend tree are accessible (as "alias.branch"), and its title is the
+// name of the file holding the friend tree.
+type friendElement struct {
+	rvers int16
+	named rbase.Named
+	tname string // name of the friend tree
+	owned bool   // whether the file holding the friend tree is owned by this element
+
+	parent *ttree      // tree this element is a friend of
+	tree   *ttree      // resolved friend tree
+	f      *riofs.File // file opened to resolve the friend tree, if any (closed with the parent tree file)
+	err    error       // error encountered while resolving the friend tree
+
+	idx     *TreeIndex // index used to compute the entries mapping
+	entries []int64    // friend tree entries, indexed by parent tree entry
+}
+
+func (*friendElement) RVersion() int16 {
+	return rvers.FriendElement
+}
+
+func (*friendElement) Class() string {
+	return "TFriendElement"
+}
+
+func (fe *friendElement) Name() string {
+	return fe.named.Name()
+}
+
+func (fe *friendElement) Title() string {
+	return fe.named.Title()
+}
+
+func (fe *friendElement) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(fe.RVersion())
+	if n, err := fe.named.MarshalROOT(w); err != nil {
+		return n, err
+	}
+	w.WriteString(fe.tname)
+	w.WriteBool(fe.owned)
+
+	return w.SetByteCount(pos, fe.Class())
+}
+
+func (fe *friendElement) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	beg := r.Pos()
+	vers, pos, bcnt := r.ReadVersion(fe.Class())
+	fe.rvers = vers
+
+	if err := fe.named.UnmarshalROOT(r); err != nil {
+		return err
+	}
+	fe.tname = r.ReadString()
+	fe.owned = r.ReadBool()
+
+	r.CheckByteCount(pos, bcnt, beg, fe.Class())
+	return r.Err()
+}
+
+// resolve returns the friend tree, loading it from its file if needed.
+// resolve returns nil if the friend tree could not be loaded.
+func (fe *friendElement) resolve() *ttree {
+	if fe.tree != nil || fe.err != nil {
+		return fe.tree
+	}
+	fe.tree, fe.err = fe.load()
+	return fe.tree
+}
+
+func (fe *friendElement) load() (*ttree, error) {
+	var (
+		dir   riofs.Directory
+		f     *riofs.File
+		fname = fe.Title()
+	)
+	var pf *riofs.File
+	if fe.parent != nil {
+		pf = fe.parent.getFile()
+		f = pf
+		dir = fe.parent.dir
+	}
+
+	if fname != "" && (f == nil || fname != f.Name()) {
+		var err error
+		f, err = openFriendFile(fname, f)
+		if err != nil {
+			return nil, fmt.Errorf("rtree: could not open file %q of friend tree %q: %w", fname, fe.tname, err)
+		}
+		fe.f = f
+		dir = nil
+		if pf != nil {
+			// the friend file is closed together with the file holding the parent tree.
+			pf.CloseWith(f)
+		}
+	}
+
+	if f == nil {
+		return nil, fmt.Errorf("rtree: no file holding friend tree %q", fe.tname)
+	}
+
+	var (
+		obj root.Object
+		err error
+	)
+	if dir != nil {
+		obj, err = riofs.Dir(dir).Get(fe.tname)
+	}
+	if obj == nil {
+		obj, err = riofs.Dir(f).Get(fe.tname)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not load friend tree %q: %w", fe.tname, err)
+	}
+
+	t, ok := obj.(Tree)
+	if !ok {
+		return nil, fmt.Errorf("rtree: friend %q is not a tree (type=%T)", fe.tname, obj)
+	}
+
+	tree := asTTree(t)
+	if tree == nil {
+		return nil, fmt.Errorf("rtree: friend tree %q has unsupported type %T", fe.tname, t)
+	}
+
+	return tree, nil
+}
+
+// openFriendFile opens the named file, first looking for it relative to
+// the directory of the provided file, if any.
+func openFriendFile(fname string, f *riofs.File) (*riofs.File, error) {
+	if f != nil && !filepath.IsAbs(fname) {
+		name := filepath.Join(filepath.Dir(f.Name()), fname)
+		if ff, err := riofs.Open(name); err == nil {
+			return ff, nil
+		}
+	}
+	return riofs.Open(fname)
+}
+
+// entryMap returns the entries of the friend tree matching the entries of
+// the parent tree, as dictated by the index of the friend tree.
+// entryMap returns nil if the friend tree has no index, in which case
+// both trees are read in lockstep.
+func (fe *friendElement) entryMap() ([]int64, error) {
+	tree := fe.resolve()
+	if tree == nil {
+		return nil, fe.err
+	}
+
+	idx := tree.indexOf()
+	if idx == nil {
+		return nil, nil
+	}
+	if idx == fe.idx {
+		return fe.entries, nil
+	}
+
+	vmaj, err := readIndexValues(fe.parent, idx.MajorName())
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not read major values for friend %q: %w", fe.Name(), err)
+	}
+
+	vmin, err := readIndexValues(fe.parent, idx.MinorName())
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not read minor values for friend %q: %w", fe.Name(), err)
+	}
+
+	// entries without a match in the friend tree are marked with -1.
+	entries := make([]int64, len(vmaj))
+	for i := range entries {
+		entries[i], _ = idx.Entry(vmaj[i], vmin[i])
+	}
+
+	fe.idx = idx
+	fe.entries = entries
+
+	return fe.entries, nil
+}
+
+// AddFriend adds the friend tree to the provided tree, under the given alias.
+// The branches of the friend tree are then accessible from the tree, as
+// "alias.branch" or simply as "branch" when there is no ambiguity.
+//
+// Entries of a friend tree are read in lockstep with the entries of the tree,
+// unless the friend tree has an index (see BuildIndex), in which case the
+// friend entry is the one matching the major and minor values of the tree
+// entry. Values read from a friend tree without such an entry are set to
+// their zero value.
+//
+// If alias is empty, the name of the friend tree is used.
+// Chains of trees are not supported.
+func AddFriend(t, friend Tree, alias string) error {
+	tree := asTTree(t)
+	if _, ok := t.(*tchain); ok || tree == nil {
+		return fmt.Errorf("rtree: can not add friend to tree %q of type %T", t.Name(), t)
+	}
+
+	ftree := asTTree(friend)
+	if _, ok := friend.(*tchain); ok || ftree == nil {
+		return fmt.Errorf("rtree: can not add friend tree %q of type %T", friend.Name(), friend)
+	}
+
+	if ftree == tree {
+		return fmt.Errorf("rtree: can not add tree %q as a friend of itself", t.Name())
+	}
+
+	if alias == "" {
+		alias = friend.Name()
+	}
+
+	for _, fe := range tree.friendElements() {
+		if fe.Name() == alias {
+			return fmt.Errorf("rtree: tree %q already has a friend named %q", t.Name(), alias)
+		}
+	}
+
+	fname := ""
+	if f := ftree.getFile(); f != nil {
+		fname = f.Name()
+	}
+
+	fe := &friendElement{
+		rvers:  rvers.FriendElement,
+		named:  *rbase.NewNamed(alias, fname),
+		tname:  friend.Name(),
+		parent: tree,
+		tree:   ftree,
+	}
+
+	if tree.friends == nil {
+		tree.friends = rcont.NewList("", nil)
+	}
+	tree.friends.Append(fe)
+
+	return nil
+}
+
+// friendElements returns the list of friends of this tree.
+func (tree *ttree) friendElements() []*friendElement {
+	if tree.friends == nil {
+		return nil
+	}
+	elems := make([]*friendElement, 0, tree.friends.Len())
+	for i := 0; i < tree.friends.Len(); i++ {
+		fe, ok := tree.friends.At(i).(*friendElement)
+		if !ok {
+			continue
+		}
+		elems = append(elems, fe)
+	}
+	return elems
+}
+
+// friendBranch returns the branch of a friend tree with the provided name.
+// The name may be prefixed with the alias of the friend tree.
+func (tree *ttree) friendBranch(name string) Branch {
+	friends := tree.friendElements()
+	for _, fe := range friends {
+		if !strings.HasPrefix(name, fe.Name()+".") {
+			continue
+		}
+		ft := fe.resolve()
+		if ft == nil {
+			continue
+		}
+		if br := ft.branch(name[len(fe.Name())+1:]); br != nil {
+			return br
+		}
+	}
+
+	for _, fe := range friends {
+		ft := fe.resolve()
+		if ft == nil {
+			continue
+		}
+		if br := ft.branch(name); br != nil {
+			return br
+		}
+	}
+	return nil
+}
+
+// friendLeaf returns the leaf of a friend tree with the provided name.
+// The name may be prefixed with the alias of the friend tree.
+func (tree *ttree) friendLeaf(name string) Leaf {
+	friends := tree.friendElements()
+	for _, fe := range friends {
+		if !strings.HasPrefix(name, fe.Name()+".") {
+			continue
+		}
+		ft := fe.resolve()
+		if ft == nil {
+			continue
+		}
+		if leaf := ft.leaf(name[len(fe.Name())+1:]); leaf != nil {
+			return leaf
+		}
+	}
+
+	for _, fe := range friends {
+		ft := fe.resolve()
+		if ft == nil {
+			continue
+		}
+		if leaf := ft.leaf(name); leaf != nil {
+			return leaf
+		}
+	}
+	return nil
+}
+
+// friendIndices returns the entries mappings of the friend trees of the
+// provided tree that have an index.
+func friendIndices(t Tree) (map[*ttree][]int64, error) {
+	tree := asTTree(t)
+	if tree == nil {
+		return nil, nil
+	}
+
+	var fidx map[*ttree][]int64
+	for _, fe := range tree.friendElements() {
+		entries, err := fe.entryMap()
+		if err != nil {
+			return nil, err
+		}
+		if entries == nil {
+			continue
+		}
+		if fidx == nil {
+			fidx = make(map[*ttree][]int64)
+		}
+		fidx[fe.tree] = entries
+	}
+	return fidx, nil
+}
+
+// asTTree returns the underlying ttree of the provided tree.
+// For chains, the currently loaded tree is returned.
+func asTTree(t Tree) *ttree {
+	switch t := t.(type) {
+	case *ttree:
+		return t
+	case *tntuple:
+		return &t.ttree
+	case *tchain:
+		if t.tree == nil {
+			return nil
+		}
+		return asTTree(t.tree)
+	}
+	return nil
+}
+
+func init() {
+	{
+		f := func() reflect.Value {
+			o := &friendElement{}
+			return reflect.ValueOf(o)
+		}
+		rtypes.Factory.Add("TFriendElement", f)
+	}
+}
+
+var (
+	_ root.Object        = (*friendElement)(nil)
+	_ root.Named         = (*friendElement)(nil)
+	_ rbytes.Marshaler   = (*friendElement)(nil)
+	_ rbytes.Unmarshaler = (*friendElement)(nil)
+)
@@ -0,0 +1,668 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rcont"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/root"
+	"go-hep.org/x/hep/groot/rvers"
+)
+
+type friendMain struct {
+	Run int32   `groot:"run"`
+	Evt int32   `groot:"evt"`
+	N   int32   `groot:"n"`
+	X   float64 `groot:"x"`
+}
+
+type friendAux struct {
+	Run int32     `groot:"run"`
+	Evt int32     `groot:"evt"`
+	N   int32     `groot:"n"`
+	Y   float64   `groot:"y"`
+	Sli []float64 `groot:"sli[n]"`
+}
+
+const friendEvts = 40
+
+func friendMainData(i int) friendMain {
+	return friendMain{
+		Run: int32(i / 8),
+		Evt: int32(i % 8),
+		N:   -1,
+		X:   float64(i),
+	}
+}
+
+// friendAuxData returns the auxiliary data matching the i-th main entry.
+func friendAuxData(i int) friendAux {
+	main := friendMainData(i)
+	data := friendAux{
+		Run: main.Run,
+		Evt: main.Evt,
+		N:   int32(i % 5),
+		Y:   10 * main.X,
+	}
+	for j := 0; j < int(data.N); j++ {
+		data.Sli = append(data.Sli, main.X+float64(j))
+	}
+	return data
+}
+
+// writeFriendTree writes a tree named name in the file fname.
+// gen fills the data of each entry and hook, if any, may customize the
+// tree before it is closed.
+func writeFriendTree(t *testing.T, fname, name string, ptr interface{}, n int, gen func(i int), hook func(w *wtree)) {
+	t.Helper()
+
+	f, err := riofs.Create(fname)
+	if err != nil {
+		t.Fatalf("could not create file %q: %+v", fname, err)
+	}
+	defer f.Close()
+
+	w, err := NewWriter(f, name, WriteVarsFromStruct(ptr), WithBasketSize(256))
+	if err != nil {
+		t.Fatalf("could not create tree writer: %+v", err)
+	}
+	defer w.Close()
+
+	for i := 0; i < n; i++ {
+		gen(i)
+		_, err = w.Write()
+		if err != nil {
+			t.Fatalf("could not write event %d: %+v", i, err)
+		}
+	}
+
+	if hook != nil {
+		hook(w.(*wtree))
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("could not close tree: %+v", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		t.Fatalf("could not close file: %+v", err)
+	}
+}
+
+func openFriendTree(t *testing.T, fname, name string) (*riofs.File, Tree) {
+	t.Helper()
+
+	f, err := riofs.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open file %q: %+v", fname, err)
+	}
+
+	o, err := riofs.Dir(f).Get(name)
+	if err != nil {
+		f.Close()
+		t.Fatalf("could not retrieve tree %q: %+v", name, err)
+	}
+	return f, o.(Tree)
+}
+
+// checkFriendRead reads the main tree together with its friend (under alias)
+// and checks the friend data is aligned with the main tree entries.
+func checkFriendRead(t *testing.T, tree Tree, alias string) {
+	t.Helper()
+
+	var (
+		main friendMain
+		aux  friendAux
+	)
+	rvars := []ReadVar{
+		{Name: "run", Value: &main.Run},
+		{Name: "evt", Value: &main.Evt},
+		{Name: "n", Value: &main.N},
+		{Name: "x", Value: &main.X},
+		{Name: alias + ".run", Value: &aux.Run},
+		{Name: alias + ".evt", Value: &aux.Evt},
+		{Name: alias + ".n", Value: &aux.N},
+		{Name: alias + ".y", Value: &aux.Y},
+		{Name: alias + ".sli", Value: &aux.Sli},
+	}
+
+	r, err := NewReader(tree, rvars)
+	if err != nil {
+		t.Fatalf("could not create reader: %+v", err)
+	}
+	defer r.Close()
+
+	n := 0
+	err = r.Read(func(ctx RCtx) error {
+		i := int(ctx.Entry)
+		if got, want := main, friendMainData(i); got != want {
+			t.Fatalf("entry %d: invalid main data:\ngot= %+v\nwant=%+v", i, got, want)
+		}
+		want := friendAuxData(i)
+		if len(want.Sli) == 0 {
+			want.Sli = aux.Sli[:0]
+		}
+		if !reflect.DeepEqual(aux, want) {
+			t.Fatalf("entry %d: invalid friend data:\ngot= %+v\nwant=%+v", i, aux, want)
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not read tree: %+v", err)
+	}
+
+	if n != friendEvts {
+		t.Fatalf("invalid number of entries: got=%d, want=%d", n, friendEvts)
+	}
+}
+
+func TestAddFriend(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rtree-")
+	if err != nil {
+		t.Fatalf("could not create dir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	var (
+		main = filepath.Join(tmp, "main.root")
+		aux  = filepath.Join(tmp, "aux.root")
+		mevt friendMain
+		aevt friendAux
+	)
+
+	writeFriendTree(t, main, "tree", &mevt, friendEvts, func(i int) { mevt = friendMainData(i) }, nil)
+	writeFriendTree(t, aux, "tree", &aevt, friendEvts, func(i int) { aevt = friendAuxData(i) }, nil)
+
+	fm, tm := openFriendTree(t, main, "tree")
+	defer fm.Close()
+
+	fa, ta := openFriendTree(t, aux, "tree")
+	defer fa.Close()
+
+	err = AddFriend(tm, ta, "aux")
+	if err != nil {
+		t.Fatalf("could not add friend: %+v", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		tree Tree
+	}{
+		{"x", tm},
+		{"y", ta},
+		{"aux.y", ta},
+		{"n", tm},
+		{"aux.n", ta},
+	} {
+		br := tm.Branch(tc.name)
+		if br == nil {
+			t.Fatalf("could not find branch %q", tc.name)
+		}
+		if got, want := br.getTree(), asTTree(tc.tree); got != want {
+			t.Fatalf("branch %q resolved in the wrong tree", tc.name)
+		}
+	}
+
+	if br := tm.Branch("aux.x"); br != nil {
+		t.Fatalf("unexpected branch aux.x")
+	}
+
+	if leaf := tm.Leaf("aux.sli"); leaf == nil || leaf.Branch().getTree() != asTTree(ta) {
+		t.Fatalf("could not find leaf aux.sli")
+	}
+
+	var names []string
+	for _, rvar := range NewReadVars(tm) {
+		names = append(names, rvar.Name)
+	}
+	if got, want := strings.Join(names, " "), "run evt n x aux.run aux.evt aux.n aux.y aux.sli"; got != want {
+		t.Fatalf("invalid read-vars:\ngot= %q\nwant=%q", got, want)
+	}
+
+	checkFriendRead(t, tm, "aux")
+
+	// formulas.
+	{
+		r, err := NewReader(tm, []ReadVar{{Name: "x", Value: new(float64)}})
+		if err != nil {
+			t.Fatalf("could not create reader: %+v", err)
+		}
+		defer r.Close()
+
+		form, err := r.FormulaFunc([]string{"x", "aux.y"}, func(x, y float64) float64 {
+			return y - 10*x
+		})
+		if err != nil {
+			t.Fatalf("could not create formula: %+v", err)
+		}
+		fct := form.Func().(func() float64)
+
+		err = r.Read(func(ctx RCtx) error {
+			if v := fct(); v != 0 {
+				t.Fatalf("entry %d: invalid formula value: %v", ctx.Entry, v)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("could not read tree: %+v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		tree   Tree
+		friend Tree
+		alias  string
+		err    string
+	}{
+		{
+			name:   "dup-alias",
+			tree:   tm,
+			friend: ta,
+			alias:  "aux",
+			err:    `rtree: tree "tree" already has a friend named "aux"`,
+		},
+		{
+			name:   "self",
+			tree:   tm,
+			friend: tm,
+			alias:  "self",
+			err:    `rtree: can not add tree "tree" as a friend of itself`,
+		},
+		{
+			name:   "chain",
+			tree:   Chain(tm),
+			friend: ta,
+			alias:  "chain",
+			err:    `rtree: can not add friend to tree "tree" of type *rtree.tchain`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := AddFriend(tc.tree, tc.friend, tc.alias)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if got, want := err.Error(), tc.err; got != want {
+				t.Fatalf("invalid error:\ngot= %v\nwant=%v", got, want)
+			}
+		})
+	}
+}
+
+func TestBuildIndex(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rtree-")
+	if err != nil {
+		t.Fatalf("could not create dir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	var (
+		main = filepath.Join(tmp, "main.root")
+		aux  = filepath.Join(tmp, "aux.root")
+		mevt friendMain
+		aevt friendAux
+	)
+
+	// friend entries are written in a different order than the main ones.
+	order := func(i int) int { return (7 * i) % friendEvts }
+
+	writeFriendTree(t, main, "tree", &mevt, friendEvts, func(i int) { mevt = friendMainData(i) }, nil)
+	writeFriendTree(t, aux, "aux", &aevt, friendEvts, func(i int) { aevt = friendAuxData(order(i)) }, nil)
+
+	fm, tm := openFriendTree(t, main, "tree")
+	defer fm.Close()
+
+	fa, ta := openFriendTree(t, aux, "aux")
+	defer fa.Close()
+
+	if idx := IndexOf(ta); idx != nil {
+		t.Fatalf("unexpected index")
+	}
+
+	idx, err := BuildIndex(ta, "run", "evt")
+	if err != nil {
+		t.Fatalf("could not build index: %+v", err)
+	}
+
+	if IndexOf(ta) != idx {
+		t.Fatalf("index not attached to tree")
+	}
+
+	if got, want := idx.Len(), friendEvts; got != want {
+		t.Fatalf("invalid index length: got=%d, want=%d", got, want)
+	}
+
+	if got, want := idx.MajorName(), "run"; got != want {
+		t.Fatalf("invalid major name: got=%q, want=%q", got, want)
+	}
+
+	if got, want := idx.MinorName(), "evt"; got != want {
+		t.Fatalf("invalid minor name: got=%q, want=%q", got, want)
+	}
+
+	for i := 0; i < friendEvts; i++ {
+		data := friendMainData(order(i))
+		entry, ok := idx.Entry(int64(data.Run), int64(data.Evt))
+		if !ok {
+			t.Fatalf("could not find entry for (%d,%d)", data.Run, data.Evt)
+		}
+		if entry != int64(i) {
+			t.Fatalf("invalid entry for (%d,%d): got=%d, want=%d", data.Run, data.Evt, entry, i)
+		}
+	}
+
+	if _, ok := idx.Entry(42, 0); ok {
+		t.Fatalf("unexpected entry for (42,0)")
+	}
+
+	err = AddFriend(tm, ta, "")
+	if err != nil {
+		t.Fatalf("could not add friend: %+v", err)
+	}
+
+	checkFriendRead(t, tm, "aux")
+
+	for _, tc := range []struct {
+		name  string
+		major string
+		minor string
+		err   string
+	}{
+		{
+			name:  "no-branch",
+			major: "run",
+			minor: "nope",
+			err:   `rtree: could not read minor values of index: rtree: tree "aux" has no branch named "nope"`,
+		},
+		{
+			name:  "non-integer",
+			major: "y",
+			err:   `rtree: could not read major values of index: rtree: index branch "y" has non-integer type float64`,
+		},
+		{
+			name:  "non-scalar",
+			major: "sli",
+			err:   `rtree: could not read major values of index: rtree: index branch "sli" is not a scalar`,
+		},
+		{
+			name:  "duplicates",
+			major: "run",
+			err:   `rtree: duplicate index value (0,0) for entries 0 and 1`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := BuildIndex(ta, tc.major, tc.minor)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if got, want := err.Error(), tc.err; got != want {
+				t.Fatalf("invalid error:\ngot= %v\nwant=%v", got, want)
+			}
+		})
+	}
+}
+
+func TestFriendFromFile(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rtree-")
+	if err != nil {
+		t.Fatalf("could not create dir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	var (
+		main = filepath.Join(tmp, "main.root")
+		aux  = filepath.Join(tmp, "aux.root")
+		mevt friendMain
+		aevt friendAux
+	)
+
+	// friend entries are written in reverse order, with the last main
+	// entry missing.
+	const nevts = friendEvts - 1
+	order := func(i int) int { return nevts - 1 - i }
+
+	writeFriendTree(t, aux, "aux", &aevt, nevts, func(i int) { aevt = friendAuxData(order(i)) }, func(w *wtree) {
+		idx := &TreeIndex{
+			rvers: rvers.TreeIndex,
+			named: *rbase.NewNamed("aux", ""),
+			major: "run",
+			minor: "evt",
+		}
+		for i := 0; i < nevts; i++ {
+			data := friendMainData(i)
+			idx.vmaj = append(idx.vmaj, int64(data.Run))
+			idx.vmin = append(idx.vmin, int64(data.Evt))
+			idx.index = append(idx.index, int64(order(i)))
+		}
+		w.treeIndex = idx
+	})
+
+	writeFriendTree(t, main, "tree", &mevt, friendEvts, func(i int) { mevt = friendMainData(i) }, func(w *wtree) {
+		fe := &friendElement{
+			rvers: rvers.FriendElement,
+			named: *rbase.NewNamed("aux", "aux.root"),
+			tname: "aux",
+		}
+		w.friends = rcont.NewList("", []root.Object{fe})
+	})
+
+	fm, tm := openFriendTree(t, main, "tree")
+	defer fm.Close()
+
+	tree := asTTree(tm)
+	fes := tree.friendElements()
+	if len(fes) != 1 {
+		t.Fatalf("invalid number of friends: got=%d, want=1", len(fes))
+	}
+	fe := fes[0]
+	if got, want := fe.tname, "aux"; got != want {
+		t.Fatalf("invalid friend tree name: got=%q, want=%q", got, want)
+	}
+
+	ft := fe.resolve()
+	if ft == nil {
+		t.Fatalf("could not resolve friend tree: %+v", fe.err)
+	}
+
+	idx := IndexOf(ft)
+	if idx == nil {
+		t.Fatalf("could not read index of friend tree")
+	}
+	if got, want := idx.Len(), nevts; got != want {
+		t.Fatalf("invalid index length: got=%d, want=%d", got, want)
+	}
+
+	var (
+		x float64
+		y float64
+	)
+	r, err := NewReader(tm, []ReadVar{{Name: "x", Value: &x}, {Name: "aux.y", Value: &y}}, WithRange(0, nevts))
+	if err != nil {
+		t.Fatalf("could not create reader: %+v", err)
+	}
+	defer r.Close()
+
+	err = r.Read(func(ctx RCtx) error {
+		if y != 10*x {
+			t.Fatalf("entry %d: invalid friend value: got=%v, want=%v", ctx.Entry, y, 10*x)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not read tree: %+v", err)
+	}
+
+	// last main entry has no matching friend entry.
+	r, err = NewReader(tm, []ReadVar{{Name: "x", Value: &x}, {Name: "aux.y", Value: &y}}, WithRange(nevts, friendEvts))
+	if err != nil {
+		t.Fatalf("could not create reader: %+v", err)
+	}
+	defer r.Close()
+
+	y = 42
+	err = r.Read(func(ctx RCtx) error {
+		if got, want := x, float64(nevts); got != want {
+			t.Fatalf("entry %d: invalid main value: got=%v, want=%v", ctx.Entry, got, want)
+		}
+		if y != 0 {
+			t.Fatalf("entry %d: invalid friend value: got=%v, want=0", ctx.Entry, y)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not read tree: %+v", err)
+	}
+
+	// the friend file is closed together with the main file.
+	err = fm.Close()
+	if err != nil {
+		t.Fatalf("could not close main file: %+v", err)
+	}
+	if _, err := fe.f.Get("aux"); err == nil {
+		t.Fatalf("friend file %q was not closed", fe.f.Name())
+	}
+}
+
+func TestFriendMissingEntries(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rtree-")
+	if err != nil {
+		t.Fatalf("could not create dir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	var (
+		main = filepath.Join(tmp, "main.root")
+		aux  = filepath.Join(tmp, "aux.root")
+		mevt friendMain
+		aevt friendAux
+	)
+
+	// the last 16 entries of the main and friend trees hold disjoint sets of
+	// (run, evt) values: these main entries have no matching friend entry,
+	// and the values read from the previous entries must not leak into them.
+	const nmatch = friendEvts - 16
+	match := func(i int) bool { return i < nmatch }
+
+	writeFriendTree(t, main, "tree", &mevt, friendEvts, func(i int) { mevt = friendMainData(i) }, nil)
+	writeFriendTree(t, aux, "aux", &aevt, friendEvts, func(i int) {
+		if !match(i) {
+			i += friendEvts
+		}
+		aevt = friendAuxData(i)
+	}, nil)
+
+	fm, tm := openFriendTree(t, main, "tree")
+	defer fm.Close()
+
+	fa, ta := openFriendTree(t, aux, "aux")
+	defer fa.Close()
+
+	_, err = BuildIndex(ta, "run", "evt")
+	if err != nil {
+		t.Fatalf("could not build index: %+v", err)
+	}
+
+	err = AddFriend(tm, ta, "")
+	if err != nil {
+		t.Fatalf("could not add friend: %+v", err)
+	}
+
+	wantAux := func(i int, got friendAux) friendAux {
+		if !match(i) {
+			return friendAux{}
+		}
+		want := friendAuxData(i)
+		if len(want.Sli) == 0 {
+			want.Sli = got.Sli[:0]
+		}
+		return want
+	}
+
+	t.Run("reader", func(t *testing.T) {
+		var (
+			main friendMain
+			aux  friendAux
+		)
+		rvars := []ReadVar{
+			{Name: "run", Value: &main.Run},
+			{Name: "evt", Value: &main.Evt},
+			{Name: "n", Value: &main.N},
+			{Name: "x", Value: &main.X},
+			{Name: "aux.run", Value: &aux.Run},
+			{Name: "aux.evt", Value: &aux.Evt},
+			{Name: "aux.n", Value: &aux.N},
+			{Name: "aux.y", Value: &aux.Y},
+			{Name: "aux.sli", Value: &aux.Sli},
+		}
+
+		r, err := NewReader(tm, rvars)
+		if err != nil {
+			t.Fatalf("could not create reader: %+v", err)
+		}
+		defer r.Close()
+
+		n := 0
+		err = r.Read(func(ctx RCtx) error {
+			i := int(ctx.Entry)
+			if got, want := main, friendMainData(i); got != want {
+				t.Fatalf("entry %d: invalid main data:\ngot= %+v\nwant=%+v", i, got, want)
+			}
+			if want := wantAux(i, aux); !reflect.DeepEqual(aux, want) {
+				t.Fatalf("entry %d: invalid friend data:\ngot= %+v\nwant=%+v", i, aux, want)
+			}
+			n++
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("could not read tree: %+v", err)
+		}
+		if n != friendEvts {
+			t.Fatalf("invalid number of entries: got=%d, want=%d", n, friendEvts)
+		}
+	})
+
+	t.Run("tree-scanner", func(t *testing.T) {
+		type Data struct {
+			X   float64   `groot:"x"`
+			N   int32     `groot:"aux.n"`
+			Y   float64   `groot:"aux.y"`
+			Sli []float64 `groot:"aux.sli"`
+		}
+
+		sc, err := NewTreeScanner(tm, &Data{})
+		if err != nil {
+			t.Fatalf("could not create scanner: %+v", err)
+		}
+		defer sc.Close()
+
+		for sc.Next() {
+			i := int(sc.Entry())
+			data := Data{Y: 42, Sli: []float64{42}}
+			err := sc.Scan(&data)
+			if err != nil {
+				t.Fatalf("entry %d: could not scan: %+v", i, err)
+			}
+			aux := wantAux(i, friendAux{Sli: data.Sli})
+			want := Data{X: float64(i), N: aux.N, Y: aux.Y, Sli: aux.Sli}
+			if !reflect.DeepEqual(data, want) {
+				t.Fatalf("entry %d: invalid data:\ngot= %+v\nwant=%+v", i, data, want)
+			}
+		}
+		if err := sc.Err(); err != nil {
+			t.Fatalf("could not scan tree: %+v", err)
+		}
+	})
+}
@@ -0,0 +1,310 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/root"
+	"go-hep.org/x/hep/groot/rtypes"
+	"go-hep.org/x/hep/groot/rvers"
+)
+
+// TreeIndex is an index over the entries of a Tree, built from the values
+// of a major and a minor branch (e.g. the run and event numbers.)
+//
+// TreeIndex is used to align entries of friend trees that were produced
+// separately.
+type TreeIndex struct {
+	rvers int16
+	named rbase.Named
+
+	major string  // name of the major branch
+	minor string  // name of the minor branch
+	vmaj  []int64 // sorted major values
+	vmin  []int64 // sorted minor values, for each major value
+	index []int64 // tree entry of each (major,minor) pair
+}
+
+func (*TreeIndex) RVersion() int16 {
+	return rvers.TreeIndex
+}
+
+func (*TreeIndex) Class() string {
+	return "TTreeIndex"
+}
+
+func (idx *TreeIndex) Name() string {
+	return idx.named.Name()
+}
+
+func (idx *TreeIndex) Title() string {
+	return idx.named.Title()
+}
+
+// MajorName returns the name of the branch used to build the major values
+// of the index.
+func (idx *TreeIndex) MajorName() string { return idx.major }
+
+// MinorName returns the name of the branch used to build the minor values
+// of the index.
+func (idx *TreeIndex) MinorName() string { return idx.minor }
+
+// Len returns the number of entries in the index.
+func (idx *TreeIndex) Len() int { return len(idx.index) }
+
+// Entry returns the tree entry corresponding to the provided (major,minor)
+// pair of values, and whether such an entry exists.
+func (idx *TreeIndex) Entry(major, minor int64) (int64, bool) {
+	i := sort.Search(len(idx.index), func(i int) bool {
+		switch {
+		case idx.vmaj[i] != major:
+			return idx.vmaj[i] > major
+		default:
+			return idx.vmin[i] >= minor
+		}
+	})
+	if i >= len(idx.index) || idx.vmaj[i] != major || idx.vmin[i] != minor {
+		return -1, false
+	}
+	return idx.index[i], true
+}
+
+func (idx *TreeIndex) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(idx.RVersion())
+	{
+		pos := w.WriteVersion(rvers.VirtualIndex)
+		if n, err := idx.named.MarshalROOT(w); err != nil {
+			return n, err
+		}
+		if _, err := w.SetByteCount(pos, "TVirtualIndex"); err != nil {
+			return 0, err
+		}
+	}
+	w.WriteString(idx.major)
+	w.WriteString(idx.minor)
+	w.WriteI64(int64(len(idx.index)))
+	for _, sli := range [][]int64{idx.vmaj, idx.vmin, idx.index} {
+		if len(sli) == 0 {
+			w.WriteI8(0)
+			continue
+		}
+		w.WriteI8(1)
+		w.WriteFastArrayI64(sli)
+	}
+
+	return w.SetByteCount(pos, idx.Class())
+}
+
+func (idx *TreeIndex) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	beg := r.Pos()
+	vers, pos, bcnt := r.ReadVersion(idx.Class())
+	idx.rvers = vers
+
+	{
+		beg := r.Pos()
+		_, pos, bcnt := r.ReadVersion("TVirtualIndex")
+		if err := idx.named.UnmarshalROOT(r); err != nil {
+			return err
+		}
+		r.CheckByteCount(pos, bcnt, beg, "TVirtualIndex")
+	}
+
+	idx.major = r.ReadString()
+	idx.minor = r.ReadString()
+	n := int(r.ReadI64())
+
+	read := func() []int64 {
+		if r.ReadI8() == 0 {
+			return nil
+		}
+		sli := make([]int64, n)
+		r.ReadArrayI64(sli)
+		return sli
+	}
+
+	idx.vmaj = read()
+	switch {
+	case vers < 2:
+		// major and minor values were packed together as major<<31+minor.
+		idx.vmin = make([]int64, len(idx.vmaj))
+		for i, v := range idx.vmaj {
+			idx.vmaj[i] = v >> 31
+			idx.vmin[i] = v & 0x7fffffff
+		}
+	default:
+		idx.vmin = read()
+	}
+	idx.index = read()
+
+	r.CheckByteCount(pos, bcnt, beg, idx.Class())
+	return r.Err()
+}
+
+// BuildIndex builds an index over the entries of the provided tree, from
+// the values of the major and minor branches, and attaches it to the tree.
+// The minor branch name may be empty, in which case the entries are only
+// indexed by their major value.
+// The major and minor branches must hold integer values.
+//
+// Chains of trees are not supported.
+func BuildIndex(t Tree, major, minor string) (*TreeIndex, error) {
+	var tree *ttree
+	switch t := t.(type) {
+	case *ttree:
+		tree = t
+	case *tntuple:
+		tree = &t.ttree
+	default:
+		return nil, fmt.Errorf("rtree: can not build index for tree %q of type %T", t.Name(), t)
+	}
+
+	vmaj, err := readIndexValues(tree, major)
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not read major values of index: %w", err)
+	}
+
+	vmin, err := readIndexValues(tree, minor)
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not read minor values of index: %w", err)
+	}
+
+	idx := &TreeIndex{
+		rvers: rvers.TreeIndex,
+		named: *rbase.NewNamed(tree.Name(), ""),
+		major: major,
+		minor: minor,
+		vmaj:  vmaj,
+		vmin:  vmin,
+		index: make([]int64, len(vmaj)),
+	}
+	for i := range idx.index {
+		idx.index[i] = int64(i)
+	}
+	sort.Stable(indexSorter{idx})
+
+	for i := 1; i < len(idx.index); i++ {
+		if idx.vmaj[i-1] == idx.vmaj[i] && idx.vmin[i-1] == idx.vmin[i] {
+			return nil, fmt.Errorf(
+				"rtree: duplicate index value (%d,%d) for entries %d and %d",
+				idx.vmaj[i], idx.vmin[i], idx.index[i-1], idx.index[i],
+			)
+		}
+	}
+
+	tree.treeIndex = idx
+	return idx, nil
+}
+
+// IndexOf returns the index attached to the provided tree, if any.
+func IndexOf(t Tree) *TreeIndex {
+	tree := asTTree(t)
+	if tree == nil {
+		return nil
+	}
+	return tree.indexOf()
+}
+
+// indexSorter sorts the values of a TreeIndex by (major,minor) values.
+type indexSorter struct{ idx *TreeIndex }
+
+func (s indexSorter) Len() int { return len(s.idx.index) }
+func (s indexSorter) Less(i, j int) bool {
+	if s.idx.vmaj[i] != s.idx.vmaj[j] {
+		return s.idx.vmaj[i] < s.idx.vmaj[j]
+	}
+	return s.idx.vmin[i] < s.idx.vmin[j]
+}
+func (s indexSorter) Swap(i, j int) {
+	idx := s.idx
+	idx.vmaj[i], idx.vmaj[j] = idx.vmaj[j], idx.vmaj[i]
+	idx.vmin[i], idx.vmin[j] = idx.vmin[j], idx.vmin[i]
+	idx.index[i], idx.index[j] = idx.index[j], idx.index[i]
+}
+
+// readIndexValues reads all the values of the named integer branch.
+// An empty name (or "0", as used by ROOT) yields a slice of zeros.
+func readIndexValues(t *ttree, name string) ([]int64, error) {
+	vs := make([]int64, t.Entries())
+	if name == "" || name == "0" {
+		return vs, nil
+	}
+
+	br := t.branch(name)
+	if br == nil {
+		return nil, fmt.Errorf("rtree: tree %q has no branch named %q", t.Name(), name)
+	}
+
+	leaf := br.Leaf(name)
+	if leaf == nil {
+		leaf = br.Leaves()[0]
+	}
+	if leaf.LeafCount() != nil || leaf.Len() != 1 {
+		return nil, fmt.Errorf("rtree: index branch %q is not a scalar", name)
+	}
+
+	ptr := newValue(leaf)
+	rv := reflect.ValueOf(ptr).Elem()
+	switch rv.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return nil, fmt.Errorf("rtree: index branch %q has non-integer type %T", name, rv.Interface())
+	}
+
+	// read the values through a private reader of the tree, without its
+	// friends, as the friends entries mapping may depend on these values.
+	view := *t
+	view.friends = nil
+	r, err := NewReader(&view, []ReadVar{{Name: br.Name(), Leaf: leaf.Name(), Value: ptr}})
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not create reader for index branch %q: %w", name, err)
+	}
+	defer r.Close()
+
+	err = r.Read(func(ctx RCtx) error {
+		switch rv.Kind() {
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			vs[ctx.Entry] = int64(rv.Uint())
+		default:
+			vs[ctx.Entry] = rv.Int()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not read index branch %q: %w", name, err)
+	}
+
+	return vs, nil
+}
+
+func init() {
+	{
+		f := func() reflect.Value {
+			o := &TreeIndex{}
+			return reflect.ValueOf(o)
+		}
+		rtypes.Factory.Add("TTreeIndex", f)
+	}
+}
+
+var (
+	_ root.Object        = (*TreeIndex)(nil)
+	_ root.Named         = (*TreeIndex)(nil)
+	_ rbytes.Marshaler   = (*TreeIndex)(nil)
+	_ rbytes.Unmarshaler = (*TreeIndex)(nil)
+)
@@ -13,6 +13,7 @@ import (
 
 	"go-hep.org/x/hep/groot/rbase"
 	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rdict"
 	"go-hep.org/x/hep/groot/rmeta"
 	"go-hep.org/x/hep/groot/root"
 	"go-hep.org/x/hep/groot/rtypes"
@@ -253,6 +254,7 @@ type tleafElement struct {
 	ptr       interface{}
 	src       reflect.Value
 	rstreamer rbytes.RStreamer
+	wstreamer rbytes.WStreamer
 	streamers []rbytes.StreamerElement
 }
 
@@ -366,6 +368,12 @@ func (leaf *tleafElement) setAddress(ptr interface{}) error {
 	leaf.ptr = ptr
 	leaf.src = reflect.ValueOf(leaf.ptr).Elem()
 
+	if rs, ok := ptr.(rbytes.RStreamer); ok && leaf.id < 0 {
+		// top-level, never split, object streaming its own members.
+		leaf.rstreamer = rs
+		return nil
+	}
+
 	var impl rstreamerImpl
 	sictx := leaf.branch.getTree().getFile()
 	for _, elt := range leaf.streamers {
@@ -376,7 +384,13 @@ func (leaf *tleafElement) setAddress(ptr interface{}) error {
 }
 
 func (leaf *tleafElement) writeToBuffer(w *rbytes.WBuffer) (int, error) {
-	panic("not implemented")
+	if leaf.wstreamer == nil {
+		panic("not implemented")
+	}
+
+	beg := w.Pos()
+	err := leaf.wstreamer.WStreamROOT(w)
+	return int(w.Pos() - beg), err
 }
 
 func (leaf *tleafElement) canGenerateOffsetArray() bool {
@@ -653,7 +667,9 @@ func newLeafFromWVar(w *wtree, b Branch, v WriteVar, lvl int, cfg wopt) (Leaf, e
 			addLeaf(leaf)
 
 		case reflect.TypeOf(root.Float16(0)), reflect.TypeOf([]root.Float16(nil)):
-			leaf = newLeafF16(b, v.Name, shape, signed, count, nil)
+			lf16 := newLeafF16(b, v.Name, shape, signed, count, nil)
+			lf16.elm = newLeafRange(&lf16.tleaf, "f", v.Range, rmeta.Float16)
+			leaf = lf16
 			err := leaf.setAddress(v.Value)
 			if err != nil {
 				return nil, fmt.Errorf("could not set leaf address for %q: %w", v.Name, err)
@@ -674,7 +690,9 @@ func newLeafFromWVar(w *wtree, b Branch, v WriteVar, lvl int, cfg wopt) (Leaf, e
 			addLeaf(leaf)
 
 		case reflect.TypeOf(root.Double32(0)), reflect.TypeOf([]root.Double32(nil)):
-			leaf = newLeafD32(b, v.Name, shape, signed, count, nil)
+			ld32 := newLeafD32(b, v.Name, shape, signed, count, nil)
+			ld32.elm = newLeafRange(&ld32.tleaf, "d", v.Range, rmeta.Double32)
+			leaf = ld32
 			err := leaf.setAddress(v.Value)
 			if err != nil {
 				return nil, fmt.Errorf("could not set leaf address for %q: %w", v.Name, err)
@@ -699,6 +717,22 @@ func newLeafFromWVar(w *wtree, b Branch, v WriteVar, lvl int, cfg wopt) (Leaf, e
 	return leaf, nil
 }
 
+// newLeafRange appends the range specification spec of a Float16 or
+// Double32 leaf to its title and returns the streamer element describing
+// how its values are compressed.
+// newLeafRange returns nil if spec is empty.
+func newLeafRange(leaf *tleaf, code, spec string, etype rmeta.Enum) rbytes.StreamerElement {
+	if spec == "" {
+		return nil
+	}
+	leaf.named.SetTitle(leaf.Title() + "/" + code + spec)
+	elm := rdict.Element{
+		Name: *rbase.NewNamed(fmt.Sprintf("%s_Element", leaf.Name()), leaf.Title()),
+		Type: etype,
+	}.New()
+	return &elm
+}
+
 func asLeafBase(leaf Leaf) (*tleaf, rmeta.Enum) {
 	switch leaf := leaf.(type) {
 	case *LeafO:
@@ -1939,8 +1939,8 @@ func (leaf *LeafF16) MarshalROOT(w *rbytes.WBuffer) (int, error) {
 
 	pos := w.WriteVersion(leaf.rvers)
 	leaf.tleaf.MarshalROOT(w)
-	w.WriteF16(leaf.min, leaf.elm)
-	w.WriteF16(leaf.max, leaf.elm)
+	w.WriteF16(leaf.min, nil)
+	w.WriteF16(leaf.max, nil)
 
 	return w.SetByteCount(pos, leaf.Class())
 }
@@ -2190,8 +2190,8 @@ func (leaf *LeafD32) MarshalROOT(w *rbytes.WBuffer) (int, error) {
 
 	pos := w.WriteVersion(leaf.rvers)
 	leaf.tleaf.MarshalROOT(w)
-	w.WriteD32(leaf.min, leaf.elm)
-	w.WriteD32(leaf.max, leaf.elm)
+	w.WriteD32(leaf.min, nil)
+	w.WriteD32(leaf.max, nil)
 
 	return w.SetByteCount(pos, leaf.Class())
 }
@@ -57,6 +57,10 @@ func fieldOf(rt reflect.Type, field string) int {
 		if f.Name == field {
 			return i
 		}
+		if f.Name == "ROOT_"+cxxNameSanitizer.Replace(field) {
+			// type generated from a StreamerInfo.
+			return i
+		}
 	}
 	return -1
 }
@@ -68,10 +72,20 @@ func rstreamerFrom(se rbytes.StreamerElement, ptr interface{}, lcnt leafCount, s
 	if rt.Kind() == reflect.Struct {
 		field := fieldOf(rt, se.Name())
 		if field < 0 {
-			panic(fmt.Errorf("rtree: no such field %q in type %T", se.Name(), ptr))
+			// member removed from the Go type: read and discard it.
+			return rstreamerSkip(se, lcnt, sictx)
 		}
 
 		rf = rv.Field(field)
+		if se, ok := se.(*rdict.StreamerBasicType); ok && se.Type() < rmeta.OffsetL {
+			et := gotypeFromSE(se, nil, sictx)
+			if et != rf.Type() {
+				if !et.ConvertibleTo(rf.Type()) {
+					panic(fmt.Errorf("rtree: could not convert field %q from %v to %v in type %T", se.Name(), et, rf.Type(), ptr))
+				}
+				return rstreamerConv(se, et, rf, lcnt, sictx)
+			}
+		}
 	}
 
 	switch se := se.(type) {
@@ -743,17 +757,28 @@ func rstreamerFrom(se rbytes.StreamerElement, ptr interface{}, lcnt leafCount, s
 					if err != nil {
 						panic(fmt.Errorf("rtree: could not retrieve streamer for %q: %w", etn[0], err))
 					}
-					eptr := reflect.New(rf.Type().Elem())
-					felt := rstreamerFrom(subsi.Elements()[0], eptr.Interface(), lcnt, sictx)
+					var (
+						eptr = reflect.New(rf.Type().Elem())
+						felt rstreamerFunc
+					)
+					switch {
+					case eptr.Elem().Kind() == reflect.Struct && !strings.HasPrefix(subsi.Name(), "vector<"):
+						// std::vector<T> of objects: each element is streamed
+						// with its own version header.
+						felt = rstreamerObject(subsi, eptr.Interface(), lcnt, sictx)
+					default:
+						felt = rstreamerFrom(subsi.Elements()[0], eptr.Interface(), lcnt, sictx)
+					}
 					fptr := rf.Addr()
 					typename := se.TypeName()
 					return func(r *rbytes.RBuffer) error {
 						start := r.Pos()
 						_, pos, bcnt := r.ReadVersion(typename)
 						n := int(r.ReadI32())
-						if fptr.Elem().Len() < n {
+						if fptr.Elem().Cap() < n {
 							fptr.Elem().Set(reflect.MakeSlice(rf.Type(), n, n))
 						}
+						fptr.Elem().SetLen(n)
 						sli := fptr.Elem()
 						for i := 0; i < n; i++ {
 							felt(r)
@@ -774,29 +799,90 @@ func rstreamerFrom(se rbytes.StreamerElement, ptr interface{}, lcnt leafCount, s
 		if err != nil {
 			panic(fmt.Errorf("no streamer-info for %q", se.TypeName()))
 		}
-		var funcs []func(r *rbytes.RBuffer) error
-		for i, elt := range sinfo.Elements() {
-			fptr := rf.Field(i).Addr().Interface()
-			funcs = append(funcs, rstreamerFrom(elt, fptr, lcnt, sictx))
-		}
-		typename := se.TypeName()
-		return func(r *rbytes.RBuffer) error {
-			start := r.Pos()
-			_, pos, bcnt := r.ReadVersion(typename)
-			for _, fct := range funcs {
-				err := fct(r)
-				if err != nil {
-					return err
+		if rf.Kind() == reflect.Array {
+			// fixed-size array of objects: each element is streamed
+			// with its own version header.
+			funcs := make([]rstreamerFunc, rf.Len())
+			for i := range funcs {
+				funcs[i] = rstreamerObject(sinfo, rf.Index(i).Addr().Interface(), lcnt, sictx)
+			}
+			return func(r *rbytes.RBuffer) error {
+				for _, fct := range funcs {
+					err := fct(r)
+					if err != nil {
+						return err
+					}
 				}
+				return r.Err()
 			}
-			r.CheckByteCount(pos, bcnt, start, typename)
-			return nil
 		}
+		return rstreamerObject(sinfo, rf.Addr().Interface(), lcnt, sictx)
 
 	}
 	panic(fmt.Errorf("rtree: unknown streamer element: %#v", se))
 }
 
+// rstreamerObject returns a function reading an object described by sinfo,
+// together with its version header, into the value pointed at by ptr.
+func rstreamerObject(sinfo rbytes.StreamerInfo, ptr interface{}, lcnt leafCount, sictx rbytes.StreamerInfoContext) rstreamerFunc {
+	if rt := reflect.TypeOf(ptr).Elem(); rt.Kind() != reflect.Struct {
+		panic(fmt.Errorf("rtree: could not read object %q into non-struct type %v", sinfo.Name(), rt))
+	}
+
+	var funcs []func(r *rbytes.RBuffer) error
+	for _, elt := range sinfo.Elements() {
+		// members are matched by name, to support schema evolution.
+		funcs = append(funcs, rstreamerFrom(elt, ptr, lcnt, sictx))
+	}
+	typename := sinfo.Name()
+	return func(r *rbytes.RBuffer) error {
+		start := r.Pos()
+		_, pos, bcnt := r.ReadVersion(typename)
+		for _, fct := range funcs {
+			err := fct(r)
+			if err != nil {
+				return err
+			}
+		}
+		r.CheckByteCount(pos, bcnt, start, typename)
+		return nil
+	}
+}
+
+// rstreamerSkip returns a function reading and discarding the member
+// described by se.
+func rstreamerSkip(se rbytes.StreamerElement, lcnt leafCount, sictx rbytes.StreamerInfoContext) rstreamerFunc {
+	var lcount Leaf
+	if se.Title() != "" {
+		lcount = &tleaf{}
+	}
+	ptr := reflect.New(reflect.StructOf([]reflect.StructField{{
+		Name: cxxNameSanitizer.Replace("ROOT_" + se.Name()),
+		Type: gotypeFromSE(se, lcount, sictx),
+		Tag:  reflect.StructTag(`groot:"` + se.Name() + `"`),
+	}}))
+	return rstreamerFrom(se, ptr.Interface(), lcnt, sictx)
+}
+
+// rstreamerConv returns a function reading the member described by se,
+// of type et, and converting it to the type of rf.
+func rstreamerConv(se rbytes.StreamerElement, et reflect.Type, rf reflect.Value, lcnt leafCount, sictx rbytes.StreamerInfoContext) rstreamerFunc {
+	ptr := reflect.New(reflect.StructOf([]reflect.StructField{{
+		Name: cxxNameSanitizer.Replace("ROOT_" + se.Name()),
+		Type: et,
+		Tag:  reflect.StructTag(`groot:"` + se.Name() + `"`),
+	}}))
+	rfunc := rstreamerFrom(se, ptr.Interface(), lcnt, sictx)
+	return func(r *rbytes.RBuffer) error {
+		err := rfunc(r)
+		if err != nil {
+			return err
+		}
+		rf.Set(ptr.Elem().Field(0).Convert(rf.Type()))
+		return nil
+	}
+}
+
 func gotypeFromSI(sinfo rbytes.StreamerInfo, ctx rbytes.StreamerInfoContext) reflect.Type {
 	if typ, ok := builtins[sinfo.Name()]; ok {
 		return typ
@@ -1086,6 +1172,9 @@ func gotypeFromSE(se rbytes.StreamerElement, lcount Leaf, ctx rbytes.StreamerInf
 		if err != nil {
 			panic(err)
 		}
+		if n := se.ArrayLen(); n > 0 {
+			return reflect.ArrayOf(n, gotypeFromSI(si, ctx))
+		}
 		return gotypeFromSI(si, ctx)
 
 	case *rdict.StreamerBase:
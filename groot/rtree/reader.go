@@ -21,6 +21,7 @@ type ReadVar struct {
 
 // NewReadVars returns the complete set of ReadVars to read all the data
 // contained in the provided Tree.
+// The branches of friend trees are named "alias.branch".
 func NewReadVars(t Tree) []ReadVar {
 	var vars []ReadVar
 	for _, b := range t.Branches() {
@@ -34,6 +35,27 @@ func NewReadVars(t Tree) []ReadVar {
 		}
 	}
 
+	// add variables from friend trees, as "alias.branch".
+	if tree := asTTree(t); tree != nil {
+		for _, fe := range tree.friendElements() {
+			ft := fe.resolve()
+			if ft == nil {
+				continue
+			}
+			prefix := fe.Name() + "."
+			for _, b := range ft.Branches() {
+				for _, leaf := range b.Leaves() {
+					ptr := newValue(leaf)
+					cnt := ""
+					if leaf.LeafCount() != nil {
+						cnt = prefix + leaf.LeafCount().Name()
+					}
+					vars = append(vars, ReadVar{Name: prefix + b.Name(), Leaf: leaf.Name(), Value: ptr, count: cnt})
+				}
+			}
+		}
+	}
+
 	return vars
 }
 
@@ -94,6 +116,9 @@ func ReadVarsFromStruct(ptr interface{}) []ReadVar {
 			rvar.Name = ft.Name
 		}
 
+		// range specifications are only meaningful when writing.
+		rvar.Name, _ = splitRange(rvar.Name)
+
 		if strings.Contains(rvar.Name, "[") {
 			switch ft.Type.Kind() {
 			case reflect.Slice:
@@ -135,6 +160,8 @@ type Reader struct {
 	beg   int64
 	end   int64
 
+	elist *EntryList // list of selected entries, if any
+
 	evals []formula
 	dirty bool // whether we need to re-create scanner (if formula needed new branches)
 }
@@ -152,6 +179,17 @@ func WithRange(beg, end int64) ReadOption {
 	}
 }
 
+// WithEntryList specifies the list of selected entries a Tree reader will
+// read through.
+// Entries not in the list are skipped, and so are the baskets holding only
+// non-selected entries.
+func WithEntryList(l *EntryList) ReadOption {
+	return func(r *Reader) error {
+		r.elist = l
+		return nil
+	}
+}
+
 // NewReader creates a new Tree Reader from the provided ROOT Tree and
 // the set of read-variables into which data will be read.
 func NewReader(t Tree, rvars []ReadVar, opts ...ReadOption) (*Reader, error) {
@@ -182,6 +220,13 @@ func NewReader(t Tree, rvars []ReadVar, opts ...ReadOption) (*Reader, error) {
 		r.end = r.t.Entries()
 	}
 
+	if r.elist != nil {
+		err := r.scan.SetEntryList(r.elist)
+		if err != nil {
+			return nil, fmt.Errorf("rtree: could not set entry list: %w", err)
+		}
+	}
+
 	if r.beg < 0 {
 		return nil, fmt.Errorf("rtree: invalid event reader range [%d, %d) (start=%d < 0)",
 			r.beg, r.end, r.beg,
@@ -235,6 +280,12 @@ func (r *Reader) Read(f func(ctx RCtx) error) error {
 		if err != nil {
 			return fmt.Errorf("rtree: could not re-create scanner: %w", err)
 		}
+		if r.elist != nil {
+			err = sc.SetEntryList(r.elist)
+			if err != nil {
+				return fmt.Errorf("rtree: could not set entry list: %w", err)
+			}
+		}
 		r.scan = sc
 	}
 
@@ -57,6 +57,7 @@ type Branch interface {
 	writeToBuffer(w *rbytes.WBuffer) (int, error)
 	write() (int, error)
 	flush() error
+	flushCluster() error
 }
 
 // Leaf describes branches data types
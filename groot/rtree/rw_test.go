@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"math/rand"
 	"os"
 	"path"
@@ -1707,6 +1708,159 @@ void scan(const char *fname, const char *tree, const char *oname) {
 
 }
 
+func TestTreeWriteRange(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rtree-")
+	if err != nil {
+		t.Fatalf("could not create dir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	fname := filepath.Join(tmp, "tree-range.root")
+
+	type Data struct {
+		N   int32            `groot:"n"`
+		F16 root.Float16     `groot:"f16[0,100,12]"`
+		D32 root.Double32    `groot:"d32[0,100,20]"`
+		Nbs root.Double32    `groot:"nbits[0,0,10]"`
+		Arr [3]root.Double32 `groot:"arr[3][-10,10,16]"`
+		Sli []root.Float16   `groot:"sli[n][0,10,12]"`
+	}
+
+	const nevts = 5
+	gen := func(i int) Data {
+		v := float64(i)
+		data := Data{
+			N:   int32(i),
+			F16: root.Float16(10.5 * v),
+			D32: root.Double32(30.25 * v), // values above 100 are clamped.
+			Nbs: root.Double32(1.5 + v),
+			Arr: [3]root.Double32{root.Double32(-v), 0, root.Double32(v)},
+		}
+		for j := 0; j < i; j++ {
+			data.Sli = append(data.Sli, root.Float16(v+0.5*float64(j)))
+		}
+		return data
+	}
+
+	func() {
+		f, err := riofs.Create(fname)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		defer f.Close()
+
+		var data Data
+		w, err := NewWriter(f, "tree", WriteVarsFromStruct(&data))
+		if err != nil {
+			t.Fatalf("could not create tree writer: %+v", err)
+		}
+		defer w.Close()
+
+		for i := 0; i < nevts; i++ {
+			data = gen(i)
+			_, err = w.Write()
+			if err != nil {
+				t.Fatalf("could not write event %d: %+v", i, err)
+			}
+		}
+
+		err = w.Close()
+		if err != nil {
+			t.Fatalf("could not close tree: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+	}()
+
+	f, err := riofs.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open file: %+v", err)
+	}
+	defer f.Close()
+
+	o, err := riofs.Dir(f).Get("tree")
+	if err != nil {
+		t.Fatalf("could not retrieve tree: %+v", err)
+	}
+	tree := o.(Tree)
+
+	for _, tc := range []struct {
+		name   string
+		btitle string
+		ltitle string
+	}{
+		{"f16", "f16/f[0,100,12]", "f16/f[0,100,12]"},
+		{"d32", "d32/d[0,100,20]", "d32/d[0,100,20]"},
+		{"nbits", "nbits/d[0,0,10]", "nbits/d[0,0,10]"},
+		{"arr", "arr[3]/d[-10,10,16]", "arr[3]/d[-10,10,16]"},
+		{"sli", "sli[n]/f[0,10,12]", "sli[n]/f[0,10,12]"},
+	} {
+		b := tree.Branch(tc.name)
+		if b == nil {
+			t.Fatalf("could not find branch %q", tc.name)
+		}
+		if got, want := b.Title(), tc.btitle; got != want {
+			t.Fatalf("invalid branch title: got=%q, want=%q", got, want)
+		}
+		if got, want := b.Leaves()[0].Title(), tc.ltitle; got != want {
+			t.Fatalf("invalid leaf title: got=%q, want=%q", got, want)
+		}
+	}
+
+	wvars := WriteVarsFromTree(tree)
+	if got, want := wvars[1].Range, "[0,100,12]"; got != want {
+		t.Fatalf("invalid range from tree: got=%q, want=%q", got, want)
+	}
+
+	var data Data
+	r, err := NewReader(tree, ReadVarsFromStruct(&data))
+	if err != nil {
+		t.Fatalf("could not create reader: %+v", err)
+	}
+	defer r.Close()
+
+	near := func(got, want, tol float64) bool {
+		return math.Abs(got-want) <= tol
+	}
+
+	err = r.Read(func(ctx RCtx) error {
+		i := int(ctx.Entry)
+		want := gen(i)
+		if want.D32 > 100 {
+			want.D32 = 100
+		}
+		if !near(float64(data.F16), float64(want.F16), 100.0/(1<<12)) {
+			return fmt.Errorf("entry %d: invalid f16: got=%v, want=%v", i, data.F16, want.F16)
+		}
+		if !near(float64(data.D32), float64(want.D32), 100.0/(1<<20)) {
+			return fmt.Errorf("entry %d: invalid d32: got=%v, want=%v", i, data.D32, want.D32)
+		}
+		if !near(float64(data.Nbs), float64(want.Nbs), float64(want.Nbs)/(1<<10)) {
+			return fmt.Errorf("entry %d: invalid nbits: got=%v, want=%v", i, data.Nbs, want.Nbs)
+		}
+		for j := range want.Arr {
+			if !near(float64(data.Arr[j]), float64(want.Arr[j]), 20.0/(1<<16)) {
+				return fmt.Errorf("entry %d: invalid arr: got=%v, want=%v", i, data.Arr, want.Arr)
+			}
+		}
+		if len(data.Sli) != len(want.Sli) {
+			return fmt.Errorf("entry %d: invalid sli length: got=%d, want=%d", i, len(data.Sli), len(want.Sli))
+		}
+		for j := range want.Sli {
+			if !near(float64(data.Sli[j]), float64(want.Sli[j]), 10.0/(1<<12)) {
+				return fmt.Errorf("entry %d: invalid sli: got=%v, want=%v", i, data.Sli, want.Sli)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not read tree: %+v", err)
+	}
+}
+
 var sumBenchReadTreeF64 = 0.0
 
 func BenchmarkReadTreeF64(b *testing.B) {
@@ -7,6 +7,7 @@ package rtree
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"go-hep.org/x/hep/groot/root"
@@ -26,6 +27,11 @@ type baseScanner struct {
 	cbr []Branch    // branches activated because holding slice index
 	ibr []scanField // indices of activated branches
 
+	fidx map[*ttree][]int64 // entries mapping of indexed friend trees
+
+	sel  []int64 // selected entries, if any (see EntryList)
+	isel int     // index of the next selected entry
+
 	closed bool
 }
 
@@ -60,7 +66,7 @@ func (s *baseScanner) SeekEntry(i int64) error {
 	if s.chain {
 		ch := s.tree.(*tchain)
 		if i >= ch.off+ch.tree.Entries() || i < ch.off {
-			itree := s.findTree(i)
+			itree := ch.findTree(i)
 			if itree == -1 {
 				s.err = fmt.Errorf("rtree: could not find Tree containing entry %d", i)
 				return s.err
@@ -70,20 +76,10 @@ func (s *baseScanner) SeekEntry(i int64) error {
 	}
 	s.i = i
 	s.cur = i - 1
+	s.isel = 0
 	return s.err
 }
 
-// findTree finds the tree number in the chain that contains entry i
-func (s *baseScanner) findTree(i int64) int {
-	ch := s.tree.(*tchain)
-	for j := range ch.trees {
-		if i <= ch.tots[j] {
-			return j
-		}
-	}
-	return -1
-}
-
 // Next prepares the next result row for reading with the Scan method.
 // It returns true on success, false if there is no next result row.
 // Every call to Scan, even the first one, must be preceded by a call to Next.
@@ -91,6 +87,9 @@ func (s *baseScanner) Next() bool {
 	if s.closed {
 		return false
 	}
+	if s.sel != nil {
+		return s.nextSelected()
+	}
 	next := s.i < s.n
 	s.cur++
 	s.i++
@@ -105,6 +104,53 @@ func (s *baseScanner) Next() bool {
 	return next
 }
 
+// nextSelected moves the scanner to the next selected entry.
+// Trees of a chain without any selected entry are not loaded.
+func (s *baseScanner) nextSelected() bool {
+	if s.isel < len(s.sel) && s.sel[s.isel] < s.i {
+		sel := s.sel[s.isel:]
+		s.isel += sort.Search(len(sel), func(i int) bool { return sel[i] >= s.i })
+	}
+	if s.isel >= len(s.sel) || s.sel[s.isel] >= s.n {
+		s.cur = s.n
+		s.i = s.n
+		return false
+	}
+
+	s.cur = s.sel[s.isel]
+	s.i = s.cur + 1
+	s.isel++
+
+	if s.chain && (s.cur >= s.tot || s.cur < s.off) {
+		itree := s.tree.(*tchain).findTree(s.cur)
+		if itree == -1 {
+			s.err = fmt.Errorf("rtree: could not find Tree containing entry %d", s.cur)
+			return false
+		}
+		s.loadTree(itree)
+	}
+
+	return true
+}
+
+// setEntryList restricts the entries the scanner iterates over to the ones
+// of the provided list.
+func (s *baseScanner) setEntryList(l *EntryList) error {
+	if l == nil {
+		s.sel = nil
+		s.isel = 0
+		return nil
+	}
+
+	sel, err := l.selection(s.tree)
+	if err != nil {
+		return err
+	}
+	s.sel = sel
+	s.isel = 0
+	return nil
+}
+
 func (s *baseScanner) loadTree(i int) {
 	ch := s.tree.(*tchain)
 	ch.loadTree(i)
@@ -114,22 +160,56 @@ func (s *baseScanner) loadTree(i int) {
 		// tchain exhausted.
 		return
 	}
+	s.fidx, s.err = friendIndices(ch)
+	if s.err != nil {
+		return
+	}
 	// reconnect branches
 	for i, v := range s.ibr {
-		name := v.br.Name()
-		br := ch.Branch(name)
+		br := ch.Branch(v.name)
 		br.setAddress(v.ptr)
 		s.ibr[i].br = br
 		s.mbr[i] = br
 		if v.lcnt >= 0 {
 			leaf := br.Leaves()[0]
 			lcnt := leaf.LeafCount()
-			lbr := ch.Branch(lcnt.Name())
+			lbr := br.getTree().Branch(lcnt.Name())
 			s.cbr[v.lcnt] = lbr
 		}
 	}
 }
 
+// entry returns the entry of the tree holding the provided branch that
+// matches the i-th entry of the current tree.
+// entry returns -1 if the branch belongs to an indexed friend tree without
+// an entry matching the i-th entry.
+func (s *baseScanner) entry(br Branch, i int64) int64 {
+	if entries, ok := s.fidx[br.getTree()]; ok {
+		if i >= int64(len(entries)) {
+			return -1
+		}
+		return entries[i]
+	}
+	return i
+}
+
+// loadEntry loads the i-th entry of the current tree into the provided branch.
+// Branches of indexed friend trees load the friend entry matching that entry,
+// if any.
+func (s *baseScanner) loadEntry(br Branch, i int64) error {
+	i = s.entry(br, i)
+	if i < 0 {
+		return nil
+	}
+	return br.loadEntry(i)
+}
+
+// resetValue sets the value pointed at by ptr to its zero value.
+func resetValue(ptr interface{}) {
+	rv := reflect.ValueOf(ptr).Elem()
+	rv.Set(reflect.Zero(rv.Type()))
+}
+
 func (s *baseScanner) icur() int64 {
 	return s.cur - s.off
 }
@@ -137,6 +217,7 @@ func (s *baseScanner) icur() int64 {
 // scanField associates a Branch with a struct's field index
 type scanField struct {
 	br   Branch
+	name string      // name of the branch, as requested
 	i    int         // field index
 	ptr  interface{} // field address
 	lcnt int         // index of dependant leaf-count (if any)
@@ -157,10 +238,15 @@ func NewTreeScanner(t Tree, ptr interface{}) (*TreeScanner, error) {
 	mbr := make([]Branch, 0, len(t.Branches()))
 	ibr := make([]scanField, 0, cap(mbr))
 	cbr := make([]Branch, 0)
-	cbrset := make(map[string]bool)
+	cbrset := make(map[Branch]bool)
 	lset := make(map[Leaf]struct{})
 	clset := make(map[Leaf]struct{})
 
+	fidx, err := friendIndices(t)
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not build friend trees entries: %w", err)
+	}
+
 	rt := reflect.TypeOf(ptr).Elem()
 	if rt.Kind() != reflect.Struct {
 		return nil, fmt.Errorf("rtree: NewTreeScanner expects a pointer to a struct (got: %T)", ptr)
@@ -186,15 +272,15 @@ func NewTreeScanner(t Tree, ptr interface{}) (*TreeScanner, error) {
 		lset[leaf] = struct{}{}
 		lidx := -1
 		if lcnt := leaf.LeafCount(); lcnt != nil {
-			lbr := t.Leaf(lcnt.Name())
+			lbr := br.getTree().Leaf(lcnt.Name())
 			if lbr == nil {
 				return nil, fmt.Errorf("rtree: Tree %q has no (count) branch named %q", t.Name(), lcnt.Name())
 			}
 			lidx = len(cbr)
 			bbr := lbr.Branch()
-			if !cbrset[bbr.Name()] {
+			if !cbrset[bbr] {
 				cbr = append(cbr, bbr)
-				cbrset[bbr.Name()] = true
+				cbrset[bbr] = true
 				clset[lcnt] = struct{}{}
 			}
 		}
@@ -204,7 +290,7 @@ func NewTreeScanner(t Tree, ptr interface{}) (*TreeScanner, error) {
 			return nil, err
 		}
 		mbr = append(mbr, br)
-		ibr = append(ibr, scanField{br: br, i: i, ptr: fptr, lcnt: lidx})
+		ibr = append(ibr, scanField{br: br, name: name, i: i, ptr: fptr, lcnt: lidx})
 	}
 
 	// setup addresses for leaf-count not explicitly requested by user
@@ -221,7 +307,7 @@ func NewTreeScanner(t Tree, ptr interface{}) (*TreeScanner, error) {
 
 	// remove branches already loaded via leaf-count
 	for i, ib := range ibr {
-		if _, dup := cbrset[ib.br.Name()]; dup {
+		if _, dup := cbrset[ib.br]; dup {
 			ibr[i].dup = true
 		}
 	}
@@ -236,6 +322,7 @@ func NewTreeScanner(t Tree, ptr interface{}) (*TreeScanner, error) {
 		ibr:  ibr,
 		mbr:  mbr,
 		cbr:  cbr,
+		fidx: fidx,
 	}
 	if ch, ok := t.(*tchain); ok {
 		base.chain = ok
@@ -271,17 +358,22 @@ func NewTreeScannerVars(t Tree, vars ...ReadVar) (*TreeScanner, error) {
 	mbr := make([]Branch, len(vars))
 	ibr := make([]scanField, cap(mbr))
 	cbr := make([]Branch, 0)
-	cbrset := make(map[string]bool)
+	cbrset := make(map[Branch]bool)
 	lset := make(map[Leaf]struct{})
 	clset := make(map[Leaf]struct{})
 
+	fidx, err := friendIndices(t)
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not build friend trees entries: %w", err)
+	}
+
 	for i, sv := range vars {
 		br := t.Branch(sv.Name)
 		if br == nil {
 			return nil, fmt.Errorf("rtree: Tree %q has no branch named %q", t.Name(), sv.Name)
 		}
 		mbr[i] = br
-		ibr[i] = scanField{br: br, i: 0, lcnt: -1}
+		ibr[i] = scanField{br: br, name: sv.Name, i: 0, lcnt: -1}
 		leaf := br.Leaves()[0]
 		if sv.Leaf != "" {
 			leaf = br.Leaf(sv.Leaf)
@@ -291,14 +383,14 @@ func NewTreeScannerVars(t Tree, vars ...ReadVar) (*TreeScanner, error) {
 		}
 		lset[leaf] = struct{}{}
 		if lcnt := leaf.LeafCount(); lcnt != nil {
-			lbr := t.Leaf(lcnt.Name())
+			lbr := br.getTree().Leaf(lcnt.Name())
 			if lbr == nil {
 				return nil, fmt.Errorf("rtree: Tree %q has no (count) branch named %q", t.Name(), lcnt.Name())
 			}
 			bbr := lbr.Branch()
-			if !cbrset[bbr.Name()] {
+			if !cbrset[bbr] {
 				cbr = append(cbr, bbr)
-				cbrset[bbr.Name()] = true
+				cbrset[bbr] = true
 				clset[lcnt] = struct{}{}
 			}
 		}
@@ -309,6 +401,7 @@ func NewTreeScannerVars(t Tree, vars ...ReadVar) (*TreeScanner, error) {
 		if rv := reflect.ValueOf(arg); rv.Kind() != reflect.Ptr {
 			return nil, fmt.Errorf("rtree: ReadVar %d (name=%v) has non pointer Value", i, sv.Name)
 		}
+		ibr[i].ptr = arg
 		err := br.setAddress(arg)
 		if err != nil {
 			return nil, fmt.Errorf("rtree: could not set branch address for %q: %w", br.Name(), err)
@@ -329,7 +422,7 @@ func NewTreeScannerVars(t Tree, vars ...ReadVar) (*TreeScanner, error) {
 
 	// remove branches already loaded via leaf-count
 	for i, ib := range ibr {
-		if _, dup := cbrset[ib.br.Name()]; dup {
+		if _, dup := cbrset[ib.br]; dup {
 			ibr[i].dup = true
 		}
 	}
@@ -343,6 +436,7 @@ func NewTreeScannerVars(t Tree, vars ...ReadVar) (*TreeScanner, error) {
 		ibr:  ibr,
 		mbr:  mbr,
 		cbr:  cbr,
+		fidx: fidx,
 	}
 
 	if ch, ok := t.(*tchain); ok {
@@ -378,6 +472,13 @@ func (s *TreeScanner) SeekEntry(i int64) error {
 	return s.scan.SeekEntry(i)
 }
 
+// SetEntryList restricts the entries the TreeScanner iterates over to the
+// ones held by the provided list.
+// A nil list resets the TreeScanner to iterate over all entries.
+func (s *TreeScanner) SetEntryList(l *EntryList) error {
+	return s.scan.setEntryList(l)
+}
+
 // Next prepares the next result row for reading with the Scan method.
 // It returns true on success, false if there is no next result row.
 // Every call to Scan, even the first one, must be preceded by a call to Next.
@@ -425,7 +526,7 @@ func (s *TreeScanner) scanArgs(args ...interface{}) error {
 
 	// load leaf count data
 	for _, br := range s.scan.cbr {
-		err = br.loadEntry(ientry)
+		err = s.scan.loadEntry(br, ientry)
 		if err != nil {
 			// FIXME(sbinet): properly decorate error
 			return err
@@ -435,7 +536,12 @@ func (s *TreeScanner) scanArgs(args ...interface{}) error {
 	for i, ptr := range args {
 		br := s.scan.ibr[i]
 		fv := reflect.ValueOf(ptr).Elem()
-		err = br.br.loadEntry(ientry)
+		if s.scan.entry(br.br, ientry) < 0 {
+			// no matching entry in friend tree.
+			resetValue(ptr)
+			continue
+		}
+		err = s.scan.loadEntry(br.br, ientry)
 		if err != nil {
 			// FIXME(sbinet): properly decorate error
 			return err
@@ -455,7 +561,7 @@ func (s *TreeScanner) scanStruct(data interface{}) error {
 
 	// load leaf count data
 	for _, br := range s.scan.cbr {
-		s.scan.err = br.loadEntry(ientry)
+		s.scan.err = s.scan.loadEntry(br, ientry)
 		if s.scan.err != nil {
 			// FIXME(sbinet): properly decorate error
 			return s.scan.err
@@ -469,7 +575,12 @@ func (s *TreeScanner) scanStruct(data interface{}) error {
 	}
 	for _, br := range s.scan.ibr {
 		fv := rv.Field(br.i)
-		err = br.br.loadEntry(ientry)
+		if s.scan.entry(br.br, ientry) < 0 {
+			// no matching entry in friend tree.
+			resetValue(fv.Addr().Interface())
+			continue
+		}
+		err = s.scan.loadEntry(br.br, ientry)
 		if err != nil {
 			// FIXME(sbinet): properly decorate error
 			return err
@@ -496,10 +607,15 @@ func NewScannerVars(t Tree, vars ...ReadVar) (*Scanner, error) {
 	mbr := make([]Branch, len(vars))
 	ibr := make([]scanField, cap(mbr))
 	cbr := make([]Branch, 0)
-	cbrset := make(map[string]bool)
+	cbrset := make(map[Branch]bool)
 	lset := make(map[Leaf]struct{})
 	clset := make(map[Leaf]struct{})
 
+	fidx, err := friendIndices(t)
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not build friend trees entries: %w", err)
+	}
+
 	args := make([]interface{}, len(vars))
 	for i, sv := range vars {
 		br := t.Branch(sv.Name)
@@ -507,7 +623,7 @@ func NewScannerVars(t Tree, vars ...ReadVar) (*Scanner, error) {
 			return nil, fmt.Errorf("rtree: Tree %q has no branch named %q", t.Name(), sv.Name)
 		}
 		mbr[i] = br
-		ibr[i] = scanField{br: br, i: 0, lcnt: -1}
+		ibr[i] = scanField{br: br, name: sv.Name, i: 0, lcnt: -1}
 
 		leaf := br.Leaves()[0]
 		if sv.Leaf != "" {
@@ -518,14 +634,14 @@ func NewScannerVars(t Tree, vars ...ReadVar) (*Scanner, error) {
 		}
 		lset[leaf] = struct{}{}
 		if lcnt := leaf.LeafCount(); lcnt != nil {
-			lbr := t.Leaf(lcnt.Name())
+			lbr := br.getTree().Leaf(lcnt.Name())
 			if lbr == nil {
 				return nil, fmt.Errorf("rtree: Tree %q has no (count) branch named %q", t.Name(), lcnt.Name())
 			}
 			bbr := lbr.Branch()
-			if !cbrset[bbr.Name()] {
+			if !cbrset[bbr] {
 				cbr = append(cbr, bbr)
-				cbrset[bbr.Name()] = true
+				cbrset[bbr] = true
 				clset[lcnt] = struct{}{}
 			}
 		}
@@ -566,7 +682,7 @@ func NewScannerVars(t Tree, vars ...ReadVar) (*Scanner, error) {
 
 	// remove branches already loaded via leaf-count
 	for i, ib := range ibr {
-		if _, dup := cbrset[ib.br.Name()]; dup {
+		if _, dup := cbrset[ib.br]; dup {
 			ibr[i].dup = true
 		}
 	}
@@ -580,6 +696,7 @@ func NewScannerVars(t Tree, vars ...ReadVar) (*Scanner, error) {
 		ibr:  ibr,
 		mbr:  mbr,
 		cbr:  cbr,
+		fidx: fidx,
 	}
 
 	if ch, ok := t.(*tchain); ok {
@@ -600,10 +717,15 @@ func NewScanner(t Tree, ptr interface{}) (*Scanner, error) {
 	mbr := make([]Branch, 0, len(t.Branches()))
 	ibr := make([]scanField, 0, cap(mbr))
 	cbr := make([]Branch, 0)
-	cbrset := make(map[string]bool)
+	cbrset := make(map[Branch]bool)
 	lset := make(map[Leaf]struct{})
 	clset := make(map[Leaf]struct{})
 
+	fidx, err := friendIndices(t)
+	if err != nil {
+		return nil, fmt.Errorf("rtree: could not build friend trees entries: %w", err)
+	}
+
 	args := make([]interface{}, 0, cap(mbr))
 	rt := reflect.TypeOf(ptr).Elem()
 	if rt.Kind() != reflect.Struct {
@@ -629,14 +751,14 @@ func NewScanner(t Tree, ptr interface{}) (*Scanner, error) {
 		leaf := br.Leaves()[0]
 		lset[leaf] = struct{}{}
 		if lcnt := leaf.LeafCount(); lcnt != nil {
-			lbr := t.Leaf(lcnt.Name())
+			lbr := br.getTree().Leaf(lcnt.Name())
 			if lbr == nil {
 				return nil, fmt.Errorf("rtree: Tree %q has no (count) branch named %q", t.Name(), lcnt.Name())
 			}
 			bbr := lbr.Branch()
-			if !cbrset[bbr.Name()] {
+			if !cbrset[bbr] {
 				cbr = append(cbr, bbr)
-				cbrset[bbr.Name()] = true
+				cbrset[bbr] = true
 				clset[lcnt] = struct{}{}
 			}
 		}
@@ -647,7 +769,7 @@ func NewScanner(t Tree, ptr interface{}) (*Scanner, error) {
 		}
 		args = append(args, fptr)
 		mbr = append(mbr, br)
-		ibr = append(ibr, scanField{br: br, i: i, ptr: fptr, lcnt: -1})
+		ibr = append(ibr, scanField{br: br, name: name, i: i, ptr: fptr, lcnt: -1})
 	}
 
 	// setup addresses for leaf-count not explicitly requested by user
@@ -664,7 +786,7 @@ func NewScanner(t Tree, ptr interface{}) (*Scanner, error) {
 
 	// remove branches already loaded via leaf-count
 	for i, ib := range ibr {
-		if _, dup := cbrset[ib.br.Name()]; dup {
+		if _, dup := cbrset[ib.br]; dup {
 			ibr[i].dup = true
 		}
 	}
@@ -678,6 +800,7 @@ func NewScanner(t Tree, ptr interface{}) (*Scanner, error) {
 		ibr:  ibr,
 		mbr:  mbr,
 		cbr:  cbr,
+		fidx: fidx,
 	}
 
 	if ch, ok := t.(*tchain); ok {
@@ -713,6 +836,13 @@ func (s *Scanner) SeekEntry(i int64) error {
 	return s.scan.SeekEntry(i)
 }
 
+// SetEntryList restricts the entries the Scanner iterates over to the ones
+// held by the provided list.
+// A nil list resets the Scanner to iterate over all entries.
+func (s *Scanner) SetEntryList(l *EntryList) error {
+	return s.scan.setEntryList(l)
+}
+
 // Next prepares the next result row for reading with the Scan method.
 // It returns true on success, false if there is no next result row.
 // Every call to Scan, even the first one, must be preceded by a call to Next.
@@ -731,7 +861,7 @@ func (s *Scanner) Scan() error {
 
 	// load leaf count data
 	for _, br := range s.scan.cbr {
-		s.scan.err = br.loadEntry(ientry)
+		s.scan.err = s.scan.loadEntry(br, ientry)
 		if s.scan.err != nil {
 			// FIXME(sbinet): properly decorate error
 			return s.scan.err
@@ -740,10 +870,15 @@ func (s *Scanner) Scan() error {
 
 	for i := range s.scan.ibr {
 		br := &s.scan.ibr[i]
+		if s.scan.entry(br.br, ientry) < 0 {
+			// no matching entry in friend tree.
+			resetValue(br.ptr)
+			continue
+		}
 		if br.dup {
 			continue
 		}
-		s.scan.err = br.br.loadEntry(ientry)
+		s.scan.err = s.scan.loadEntry(br.br, ientry)
 		if s.scan.err != nil {
 			// FIXME(sbinet): properly decorate error
 			return s.scan.err
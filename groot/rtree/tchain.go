@@ -6,6 +6,7 @@ package rtree
 
 import (
 	"fmt"
+	"sort"
 
 	"go-hep.org/x/hep/groot/riofs"
 	"go-hep.org/x/hep/groot/root"
@@ -119,6 +120,19 @@ func (ch *tchain) loadTree(i int) {
 	return
 }
 
+// findTree returns the index of the tree of the chain holding the
+// provided global entry, or -1.
+func (ch *tchain) findTree(entry int64) int {
+	if entry < 0 {
+		return -1
+	}
+	j := sort.Search(len(ch.tots), func(j int) bool { return entry < ch.tots[j] })
+	if j >= len(ch.tots) {
+		return -1
+	}
+	return j
+}
+
 // Class returns the ROOT class of the argument.
 func (*tchain) Class() string {
 	return "TChain"
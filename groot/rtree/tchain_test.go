@@ -7,6 +7,9 @@ package rtree_test
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -673,3 +676,89 @@ func TestChainSeekEntryScannerVars(t *testing.T) {
 		}
 	}
 }
+
+func TestChainSeekEntryEmptyTree(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rtree-")
+	if err != nil {
+		t.Fatalf("could not create dir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	var (
+		data  struct{ I64 int64 }
+		sizes = []int{5, 0, 5}
+		trees = make([]rtree.Tree, len(sizes))
+		beg   = 0
+	)
+	for i, n := range sizes {
+		fname := filepath.Join(tmp, fmt.Sprintf("chain-%d.root", i))
+		func() {
+			f, err := riofs.Create(fname)
+			if err != nil {
+				t.Fatalf("could not create file: %+v", err)
+			}
+			defer f.Close()
+
+			w, err := rtree.NewWriter(f, "tree", rtree.WriteVarsFromStruct(&data))
+			if err != nil {
+				t.Fatalf("could not create tree writer: %+v", err)
+			}
+			for j := 0; j < n; j++ {
+				data.I64 = int64(beg + j)
+				_, err = w.Write()
+				if err != nil {
+					t.Fatalf("could not write entry %d: %+v", j, err)
+				}
+			}
+			err = w.Close()
+			if err != nil {
+				t.Fatalf("could not close tree writer: %+v", err)
+			}
+			err = f.Close()
+			if err != nil {
+				t.Fatalf("could not close file: %+v", err)
+			}
+		}()
+		beg += n
+
+		f, err := riofs.Open(fname)
+		if err != nil {
+			t.Fatalf("could not open file: %+v", err)
+		}
+		defer f.Close()
+
+		obj, err := f.Get("tree")
+		if err != nil {
+			t.Fatal(err)
+		}
+		trees[i] = obj.(rtree.Tree)
+	}
+	chain := rtree.Chain(trees...)
+
+	sc, err := rtree.NewScannerVars(chain, rtree.ReadVar{Name: "I64", Value: &data.I64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sc.Close()
+
+	// entry 5 is the first entry of the last tree, right after the empty one.
+	for _, entry := range []int64{0, 5, 2, 9, 5, 4, 5} {
+		err := sc.SeekEntry(entry)
+		if err != nil {
+			t.Fatalf("could not seek to entry %d: %+v", entry, err)
+		}
+
+		if !sc.Next() {
+			t.Fatalf("could not read entry %d", entry)
+		}
+
+		err = sc.Scan()
+		if err != nil {
+			t.Fatalf("could not scan entry %d: %+v", entry, err)
+		}
+
+		if got, want := data.I64, entry; got != want {
+			t.Fatalf("invalid value for entry %d: got=%d, want=%d", entry, got, want)
+		}
+	}
+}
@@ -65,7 +65,7 @@ type ttree struct {
 	aliases     *rcont.List   // list of aliases for expressions based on the tree branches
 	indexValues *rcont.ArrayD // sorted index values
 	index       *rcont.ArrayI // index of sorted values
-	treeIndex   root.Object   // pointer to the tree index (if any) // FIXME(sbinet): impl TVirtualIndex?
+	treeIndex   root.Object   // pointer to the tree index (if any)
 	friends     *rcont.List   // pointer to the list of firend elements
 	userInfo    *rcont.List   // pointer to a list of user objects associated with this tree
 	branchRef   root.Object   // branch supporting the reftable (if any) // FIXME(sbinet): impl TBranchRef?
@@ -109,6 +109,14 @@ func (tree *ttree) Branches() []Branch {
 }
 
 func (tree *ttree) Branch(name string) Branch {
+	if br := tree.branch(name); br != nil {
+		return br
+	}
+	return tree.friendBranch(name)
+}
+
+// branch returns the branch with the provided name, ignoring friend trees.
+func (tree *ttree) branch(name string) Branch {
 	for _, br := range tree.branches {
 		if br.Name() == name {
 			return br
@@ -142,6 +150,14 @@ func (tree *ttree) Leaves() []Leaf {
 }
 
 func (tree *ttree) Leaf(name string) Leaf {
+	if leaf := tree.leaf(name); leaf != nil {
+		return leaf
+	}
+	return tree.friendLeaf(name)
+}
+
+// leaf returns the leaf with the provided name, ignoring friend trees.
+func (tree *ttree) leaf(name string) Leaf {
 	for _, leaf := range tree.leaves {
 		if leaf.Name() == name {
 			return leaf
@@ -158,6 +174,12 @@ func (tree *ttree) getFile() *riofs.File {
 	return tree.f
 }
 
+// indexOf returns the index attached to this tree, if any.
+func (tree *ttree) indexOf() *TreeIndex {
+	idx, _ := tree.treeIndex.(*TreeIndex)
+	return idx
+}
+
 func (tree *ttree) loadEntry(entry int64) error {
 	for _, b := range tree.branches {
 		err := b.loadEntry(entry)
@@ -460,6 +482,9 @@ func (tree *ttree) UnmarshalROOT(r *rbytes.RBuffer) error {
 			}
 			if v := r.ReadObjectAny(); v != nil {
 				tree.friends = v.(*rcont.List)
+				for _, fe := range tree.friendElements() {
+					fe.parent = tree
+				}
 			}
 			if v := r.ReadObjectAny(); v != nil {
 				tree.userInfo = v.(*rcont.List)
@@ -572,9 +597,8 @@ func (tree *ttree) attachStreamerElement(br Branch, se rbytes.StreamerElement, c
 		// FIXME(sbinet): this string manipulation only works for one-parameter templates
 		if strings.Contains(typename, "<") {
 			typename = typename[strings.Index(typename, "<")+1 : strings.LastIndex(typename, ">")]
-			typename = strings.TrimRight(typename, "*")
+			typename = strings.TrimSpace(strings.TrimRight(typename, "*"))
 		}
-		typename = strings.TrimSpace(se.TypeName())
 		typevers := -1
 		// FIXME(sbinet): always load latest version?
 		info, err := ctx.StreamerInfo(typename, typevers)
@@ -622,7 +646,7 @@ func (tree *ttree) attachStreamerElement(br Branch, se rbytes.StreamerElement, c
 			name = name[:idx]
 		}
 		var subse rbytes.StreamerElement
-		for _, elmt := range members {
+		for _, elmt := range submembers {
 			if elmt.Name() == name {
 				subse = elmt
 				break
@@ -36,12 +36,20 @@ type Writer interface {
 type WriteOption func(opt *wopt) error
 
 type wopt struct {
-	title    string // title of the writer tree
-	bufsize  int32  // buffer size for branches
-	splitlvl int32  // maximum split-level for branches
-	compress int32  // compression algorithm name and compression level
+	title     string // title of the writer tree
+	bufsize   int32  // buffer size for branches
+	splitlvl  int32  // maximum split-level for branches
+	compress  int32  // compression algorithm name and compression level
+	autoFlush int64  // number of entries (or bytes if negative) between two auto-flushes
+	autoSave  int64  // number of entries (or bytes if negative) between two auto-saves
+	optimize  bool   // whether to optimize basket sizes after the first auto-flush
 }
 
+const (
+	defaultAutoFlush = -30000000  // default auto-flush threshold (30MB of compressed data)
+	defaultAutoSave  = -300000000 // default auto-save threshold (300MB of compressed data)
+)
+
 // WithLZ4 configures a ROOT tree to use LZ4 as a compression mechanism.
 func WithLZ4(level int) WriteOption {
 	return func(opt *wopt) error {
@@ -86,6 +94,46 @@ func WithBasketSize(size int) WriteOption {
 	}
 }
 
+// WithAutoFlush configures a ROOT tree to flush the baskets of all its
+// branches every n entries, so the baskets of all branches are aligned on
+// clusters of n entries.
+// If n is negative, baskets are flushed each time -n bytes of compressed
+// data have been written, and the number of entries of the first cluster is
+// then used for all the following ones.
+// If n is zero, baskets are only flushed when full.
+// The default is to flush baskets every 30MB of compressed data.
+func WithAutoFlush(n int64) WriteOption {
+	return func(opt *wopt) error {
+		opt.autoFlush = n
+		return nil
+	}
+}
+
+// WithAutoSave configures a ROOT tree to save its header to the file every
+// n entries, or each time -n bytes of compressed data have been written if n
+// is negative, so a file that was not properly closed can be read back up to
+// the last save.
+// The tree header is saved at the next auto-flush, so saves are aligned
+// on clusters.
+// If n is zero, the tree header is only saved when the tree is closed.
+// The default is to save the tree header every 300MB of compressed data.
+func WithAutoSave(n int64) WriteOption {
+	return func(opt *wopt) error {
+		opt.autoSave = n
+		return nil
+	}
+}
+
+// WithoutBasketOptimization configures a ROOT tree to keep the basket size
+// of all its branches, instead of adapting the basket size of each branch
+// to hold one cluster of entries after the first auto-flush.
+func WithoutBasketOptimization() WriteOption {
+	return func(opt *wopt) error {
+		opt.optimize = false
+		return nil
+	}
+}
+
 // WithTitle sets the title of the tree writer.
 func WithTitle(title string) WriteOption {
 	return func(opt *wopt) error {
@@ -98,19 +146,38 @@ type wtree struct {
 	ttree
 	wvars []WriteVar
 
+	optimize bool  // whether to optimize basket sizes after the first auto-flush
+	cluster  int64 // first entry of the current cluster
+	saved    int64 // number of entries at the last auto-save
+	cycle    int   // cycle of the key written at the last auto-save
+
 	closed bool
 }
 
 // WriteVar describes a variable to be written out to a tree.
+//
+// Values implementing rbytes.WStreamer and root.Object are written out as
+// a single, never split, branch holding the members of the value, as
+// described by the StreamerInfo registered for its class.
+// Values whose class is a std::vector<T> are written out as the whole
+// collection (version header, size and elements), and only need the
+// StreamerInfo of T to be registered, if T is not a builtin type.
+// Such branches are read back through the rbytes.RStreamer interface.
 type WriteVar struct {
 	Name  string      // name of the variable
 	Value interface{} // pointer to the value to write
 	Count string      // name of the branch holding the count-leaf value for slices
+	Range string      // range specification "[xmin,xmax,nbits]" of Float16/Double32 values
 }
 
 // WriteVarsFromStruct creates a slice of WriteVars from the ptr value.
 // WriteVarsFromStruct panics if ptr is not a pointer to a struct value.
 // WriteVarsFromStruct ignores fields that are not exported.
+//
+// The range and number of bits used to compress root.Float16 and
+// root.Double32 fields can be specified with a struct tag:
+//
+//	E root.Double32 `groot:"E[0,100,12]"`
 func WriteVarsFromStruct(ptr interface{}) []WriteVar {
 	rv := reflect.ValueOf(ptr)
 	if rv.Kind() != reflect.Ptr {
@@ -165,6 +232,11 @@ func WriteVarsFromStruct(ptr interface{}) []WriteVar {
 			wvar.Name = ft.Name
 		}
 
+		wvar.Name, wvar.Range = splitRange(wvar.Name)
+		if wvar.Range != "" && !hasRange(ft.Type) {
+			panic(fmt.Errorf("rtree: invalid field type for %q with range specification %q: %T", ft.Name, wvar.Range, fv.Interface()))
+		}
+
 		if strings.Contains(wvar.Name, "[") {
 			switch ft.Type.Kind() {
 			case reflect.Slice:
@@ -208,10 +280,37 @@ func WriteVarsFromTree(t Tree) []WriteVar {
 			Value: reflect.New(reflect.TypeOf(rvar.Value).Elem()).Interface(),
 			Count: rvar.count,
 		}
+		switch leaf := t.Leaf(rvar.Leaf).(type) {
+		case *LeafF16, *LeafD32:
+			_, wvars[i].Range = splitRange(leaf.Title())
+		}
 	}
 	return wvars
 }
 
+// splitRange splits a name of the form "name[xmin,xmax,nbits]" into the
+// name and the range specification of Float16 and Double32 values.
+func splitRange(s string) (name, spec string) {
+	beg := strings.LastIndex(s, "[")
+	if beg < 0 || !strings.HasSuffix(s, "]") || !strings.Contains(s[beg:], ",") {
+		return s, ""
+	}
+	return s[:beg], s[beg:]
+}
+
+// hasRange returns whether values of type rt may be written with a range
+// specification.
+func hasRange(rt reflect.Type) bool {
+	for rt.Kind() == reflect.Array || rt.Kind() == reflect.Slice {
+		rt = rt.Elem()
+	}
+	switch rt {
+	case reflect.TypeOf(root.Float16(0)), reflect.TypeOf(root.Double32(0)):
+		return true
+	}
+	return false
+}
+
 // NewWriter creates a new Tree with the given name and under the given
 // directory dir, ready to be filled with data.
 func NewWriter(dir riofs.Directory, name string, vars []WriteVar, opts ...WriteOption) (Writer, error) {
@@ -234,17 +333,18 @@ func NewWriter(dir riofs.Directory, name string, vars []WriteVar, opts ...WriteO
 			defaultEntryOffsetLen: 1000,
 			maxEntries:            1000000000000,
 			maxEntryLoop:          1000000000000,
-			autoSave:              -300000000,
-			autoFlush:             -30000000,
 			estimate:              1000000,
 		},
 		wvars: vars,
 	}
 
 	cfg := wopt{
-		bufsize:  defaultBasketSize,
-		splitlvl: defaultSplitLevel,
-		compress: w.ttree.f.Compression(),
+		bufsize:   defaultBasketSize,
+		splitlvl:  defaultSplitLevel,
+		compress:  w.ttree.f.Compression(),
+		autoFlush: defaultAutoFlush,
+		autoSave:  defaultAutoSave,
+		optimize:  true,
 	}
 
 	for _, opt := range opts {
@@ -255,6 +355,9 @@ func NewWriter(dir riofs.Directory, name string, vars []WriteVar, opts ...WriteO
 	}
 
 	w.ttree.named.SetTitle(cfg.title)
+	w.ttree.autoFlush = cfg.autoFlush
+	w.ttree.autoSave = cfg.autoSave
+	w.optimize = cfg.optimize
 
 	for _, v := range vars {
 		b, err := newBranchFromWVar(w, v.Name, v, nil, 0, cfg)
@@ -295,22 +398,195 @@ func (w *wtree) Write() (int, error) {
 	}
 	w.ttree.entries++
 	w.ttree.totBytes += int64(tot)
-	// FIXME(sbinet): autoflush
+
+	if w.mustFlush() {
+		err := w.autoFlush()
+		if err != nil {
+			return tot, err
+		}
+	}
 
 	return tot, nil
 }
 
 // Flush commits the current contents of the tree to stable storage.
 func (w *wtree) Flush() error {
+	err := w.flushCluster()
+	if err != nil {
+		return err
+	}
+	w.markCluster()
+	return nil
+}
+
+// mustFlush returns whether the current cluster of entries is complete.
+func (w *wtree) mustFlush() bool {
+	switch n := w.ttree.autoFlush; {
+	case n > 0:
+		return w.ttree.entries-w.cluster >= n
+	case n < 0:
+		return w.ttree.zipBytes-w.ttree.flushedBytes >= -n
+	}
+	return false
+}
+
+// mustSave returns whether the tree header should be saved.
+func (w *wtree) mustSave() bool {
+	switch n := w.ttree.autoSave; {
+	case n > 0:
+		return w.ttree.entries-w.saved >= n
+	case n < 0:
+		return w.ttree.zipBytes-w.ttree.savedBytes >= -n
+	}
+	return false
+}
+
+// autoFlush flushes the baskets of all branches, closing the current
+// cluster of entries.
+// After the first auto-flush, autoFlush converts a size-based auto-flush
+// threshold into a number of entries and optimizes the basket sizes.
+func (w *wtree) autoFlush() error {
+	first := w.cluster == 0
+
+	err := w.flushCluster()
+	if err != nil {
+		return err
+	}
+
+	if first && w.ttree.autoFlush < 0 {
+		w.ttree.autoFlush = w.ttree.entries
+	}
+	w.markCluster()
+
+	if first && w.optimize {
+		w.optimizeBaskets()
+	}
+
+	if w.mustSave() {
+		err = w.autoSave()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// autoSave writes the tree header and the file metadata to storage.
+func (w *wtree) autoSave() error {
+	w.ttree.savedBytes = w.ttree.zipBytes
+	w.saved = w.ttree.entries
+
+	err := w.save()
+	if err != nil {
+		return fmt.Errorf("rtree: could not auto-save tree %q: %w", w.Name(), err)
+	}
+
+	err = w.ttree.f.Flush()
+	if err != nil {
+		return fmt.Errorf("rtree: could not flush file after auto-save of tree %q: %w", w.Name(), err)
+	}
+
+	return nil
+}
+
+// save writes the tree header to its directory.
+// Like ROOT's kOverwrite, save then deletes the key written by the previous
+// auto-save (if any) and releases its space on file.
+func (w *wtree) save() error {
+	err := w.ttree.dir.Put(w.Name(), w)
+	if err != nil {
+		return err
+	}
+
+	prev := w.cycle
+	for _, k := range w.ttree.dir.Keys() {
+		if k.Name() == w.Name() && k.Cycle() > w.cycle {
+			w.cycle = k.Cycle()
+		}
+	}
+
+	if prev == 0 {
+		return nil
+	}
+
+	dir, ok := w.ttree.dir.(riofs.Deleter)
+	if !ok {
+		return nil
+	}
+	return dir.Delete(fmt.Sprintf("%s;%d", w.Name(), prev))
+}
+
+// flushCluster writes the current baskets of all branches to file.
+func (w *wtree) flushCluster() error {
 	for _, b := range w.ttree.branches {
-		err := b.flush()
+		err := b.flushCluster()
 		if err != nil {
 			return fmt.Errorf("rtree: could not flush branch %q: %w", b.Name(), err)
 		}
 	}
+	w.ttree.flushedBytes = w.ttree.zipBytes
 	return nil
 }
 
+// markCluster closes the current cluster of entries.
+// Clusters that do not hold the number of entries of the auto-flush
+// threshold are recorded as cluster ranges, together with the range of
+// regular clusters that precede them.
+func (w *wtree) markCluster() {
+	var (
+		tree = &w.ttree
+		beg  = w.cluster
+		end  = tree.entries
+		size = end - beg
+	)
+	if size == 0 {
+		return
+	}
+	w.cluster = end
+
+	if size == tree.autoFlush {
+		return
+	}
+
+	last := int64(-1)
+	if n := len(tree.clusters.ranges); n > 0 {
+		last = tree.clusters.ranges[n-1]
+	}
+	if beg-1 > last && tree.autoFlush > 0 {
+		tree.clusters.ranges = append(tree.clusters.ranges, beg-1)
+		tree.clusters.sizes = append(tree.clusters.sizes, tree.autoFlush)
+	}
+	tree.clusters.ranges = append(tree.clusters.ranges, end-1)
+	tree.clusters.sizes = append(tree.clusters.sizes, size)
+}
+
+// optimizeBaskets adapts the basket size of all branches so that a basket
+// holds one cluster of entries, based on the data written so far.
+func (w *wtree) optimizeBaskets() {
+	var (
+		entries = w.ttree.entries
+		cluster = w.ttree.autoFlush
+	)
+	if entries <= 0 || cluster <= 0 {
+		return
+	}
+
+	var optimize func(bs []Branch)
+	optimize = func(bs []Branch) {
+		for _, b := range bs {
+			optimize(b.Branches())
+			switch b := b.(type) {
+			case *tbranch:
+				b.optimizeBasketSize(entries, cluster)
+			case *tbranchElement:
+				b.optimizeBasketSize(entries, cluster)
+			}
+		}
+	}
+	optimize(w.ttree.branches)
+}
+
 // Close writes metadata and closes the tree.
 func (w *wtree) Close() error {
 	if w.closed {
@@ -320,11 +596,14 @@ func (w *wtree) Close() error {
 		w.closed = true
 	}()
 
-	if err := w.Flush(); err != nil {
-		return fmt.Errorf("rtree: could not flush tree %q: %w", w.Name(), err)
+	for _, b := range w.ttree.branches {
+		err := b.flush()
+		if err != nil {
+			return fmt.Errorf("rtree: could not flush branch %q of tree %q: %w", b.Name(), w.Name(), err)
+		}
 	}
 
-	if err := w.ttree.dir.Put(w.Name(), w); err != nil {
+	if err := w.save(); err != nil {
 		return fmt.Errorf("rtree: could not save tree %q: %w", w.Name(), err)
 	}
 
@@ -6,10 +6,18 @@ package rtree
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
 	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rdict"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/root"
+	"go-hep.org/x/hep/groot/rvers"
 )
 
 func TestWriteVarsFromStruct(t *testing.T) {
@@ -162,6 +170,30 @@ func TestWriteVarsFromStruct(t *testing.T) {
 				{Name: "F2"},
 			},
 		},
+		{
+			name: "ranges",
+			ptr: &struct {
+				N   int32
+				F16 root.Float16     `groot:"f16[0,100,12]"`
+				D32 root.Double32    `groot:"d32"`
+				Arr [3]root.Double32 `groot:"arr[3][-pi,pi,16]"`
+				Sli []root.Float16   `groot:"sli[N][0,10]"`
+			}{},
+			want: []WriteVar{
+				{Name: "N"},
+				{Name: "f16", Range: "[0,100,12]"},
+				{Name: "d32"},
+				{Name: "arr", Range: "[-pi,pi,16]"},
+				{Name: "sli", Count: "N", Range: "[0,10]"},
+			},
+		},
+		{
+			name: "invalid-range-tag",
+			ptr: &struct {
+				F64 float64 `groot:"f64[0,10,12]"`
+			}{},
+			panics: "rtree: invalid field type for \"F64\" with range specification \"[0,10,12]\": float64",
+		},
 		{
 			name: "invalid-slice-tag",
 			ptr: &struct {
@@ -202,6 +234,9 @@ func TestWriteVarsFromStruct(t *testing.T) {
 				if got, want := got[i].Count, tc.want[i].Count; got != want {
 					t.Fatalf("invalid count for wvar[%d]: got=%q, want=%q", i, got, want)
 				}
+				if got, want := got[i].Range, tc.want[i].Range; got != want {
+					t.Fatalf("invalid range for wvar[%d]: got=%q, want=%q", i, got, want)
+				}
 			}
 		})
 	}
@@ -293,3 +328,501 @@ func TestInvalidTreeMerger(t *testing.T) {
 		t.Fatalf("invalid ROOTMerge error. got=%q, want=%q", got, want)
 	}
 }
+
+type clusterData struct {
+	I32 int32     `groot:"i32"`
+	F64 float64   `groot:"f64"`
+	N   int32     `groot:"n"`
+	Sli []float32 `groot:"sli[n]"`
+}
+
+func genClusterData(i int) clusterData {
+	data := clusterData{
+		I32: int32(i),
+		F64: float64(i),
+		N:   int32(i % 10),
+	}
+	for j := 0; j < int(data.N); j++ {
+		data.Sli = append(data.Sli, float32(i+j))
+	}
+	return data
+}
+
+func TestWriterAutoFlush(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rtree-")
+	if err != nil {
+		t.Fatalf("could not create dir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	for _, tc := range []struct {
+		name    string
+		opts    []WriteOption
+		nevts   int
+		flush   int // entry after which Flush is explicitly called
+		auto    int64
+		ranges  []int64
+		sizes   []int64
+		baskets []int64 // expected basket entries of the i32 branch
+	}{
+		{
+			name:    "entries",
+			opts:    []WriteOption{WithAutoFlush(100)},
+			nevts:   350,
+			auto:    100,
+			baskets: []int64{0, 100, 200, 300, 350},
+		},
+		{
+			name:    "entries-no-optimize",
+			opts:    []WriteOption{WithAutoFlush(100), WithoutBasketOptimization(), WithBasketSize(1024)},
+			nevts:   350,
+			auto:    100,
+			baskets: []int64{0, 100, 200, 300, 350},
+		},
+		{
+			name:    "explicit-flush",
+			opts:    []WriteOption{WithAutoFlush(100)},
+			nevts:   350,
+			flush:   250,
+			auto:    100,
+			ranges:  []int64{199, 249},
+			sizes:   []int64{100, 50},
+			baskets: []int64{0, 100, 200, 250, 350},
+		},
+		{
+			name:  "bytes",
+			opts:  []WriteOption{WithAutoFlush(-4000), WithoutCompression(), WithBasketSize(1024)},
+			nevts: 2000,
+			auto:  -1, // computed from the first cluster.
+		},
+		{
+			name:    "disabled",
+			opts:    []WriteOption{WithAutoFlush(0)},
+			nevts:   350,
+			auto:    0,
+			baskets: []int64{0, 350},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fname := filepath.Join(tmp, tc.name+".root")
+
+			func() {
+				f, err := riofs.Create(fname)
+				if err != nil {
+					t.Fatalf("could not create file: %+v", err)
+				}
+				defer f.Close()
+
+				var data clusterData
+				w, err := NewWriter(f, "tree", WriteVarsFromStruct(&data), tc.opts...)
+				if err != nil {
+					t.Fatalf("could not create tree writer: %+v", err)
+				}
+				defer w.Close()
+
+				for i := 0; i < tc.nevts; i++ {
+					data = genClusterData(i)
+					_, err = w.Write()
+					if err != nil {
+						t.Fatalf("could not write event %d: %+v", i, err)
+					}
+					if i+1 == tc.flush {
+						err = w.Flush()
+						if err != nil {
+							t.Fatalf("could not flush tree: %+v", err)
+						}
+					}
+				}
+
+				err = w.Close()
+				if err != nil {
+					t.Fatalf("could not close tree: %+v", err)
+				}
+
+				err = f.Close()
+				if err != nil {
+					t.Fatalf("could not close file: %+v", err)
+				}
+			}()
+
+			f, err := riofs.Open(fname)
+			if err != nil {
+				t.Fatalf("could not open file: %+v", err)
+			}
+			defer f.Close()
+
+			o, err := riofs.Dir(f).Get("tree")
+			if err != nil {
+				t.Fatalf("could not retrieve tree: %+v", err)
+			}
+			tree := o.(*ttree)
+
+			if got, want := tree.Entries(), int64(tc.nevts); got != want {
+				t.Fatalf("invalid number of entries: got=%d, want=%d", got, want)
+			}
+
+			auto := tc.auto
+			if auto < 0 {
+				auto = tree.autoFlush
+				if auto <= 0 || auto >= int64(tc.nevts) {
+					t.Fatalf("invalid first cluster size: %d", auto)
+				}
+			}
+			if got, want := tree.autoFlush, auto; got != want {
+				t.Fatalf("invalid auto-flush: got=%d, want=%d", got, want)
+			}
+			if got, want := tree.clusters.ranges, tc.ranges; len(got)+len(want) != 0 && !reflect.DeepEqual(got, want) {
+				t.Fatalf("invalid cluster ranges: got=%v, want=%v", got, want)
+			}
+			if got, want := tree.clusters.sizes, tc.sizes; len(got)+len(want) != 0 && !reflect.DeepEqual(got, want) {
+				t.Fatalf("invalid cluster sizes: got=%v, want=%v", got, want)
+			}
+
+			ref := tree.branches[0].(*tbranch)
+			if tc.baskets != nil {
+				got := ref.basketEntry[:ref.writeBasket+1]
+				if want := tc.baskets; !reflect.DeepEqual(got, want) {
+					t.Fatalf("invalid basket entries: got=%v, want=%v", got, want)
+				}
+			}
+
+			// all cluster boundaries should be basket boundaries of all branches.
+			for _, b := range tree.branches {
+				b := b.(*tbranch)
+				entries := make(map[int64]bool)
+				for _, v := range b.basketEntry[:b.writeBasket+1] {
+					entries[v] = true
+				}
+				for _, i := range ref.basketEntry[:ref.writeBasket+1] {
+					if auto > 0 && i%auto != 0 && i != int64(tc.nevts) && i != int64(tc.flush) {
+						// not a cluster boundary.
+						continue
+					}
+					if !entries[i] {
+						t.Fatalf("branch %q: cluster boundary %d is not a basket boundary (baskets=%v)",
+							b.Name(), i, b.basketEntry[:b.writeBasket+1],
+						)
+					}
+				}
+			}
+
+			var data clusterData
+			r, err := NewReader(tree, ReadVarsFromStruct(&data))
+			if err != nil {
+				t.Fatalf("could not create reader: %+v", err)
+			}
+			defer r.Close()
+
+			err = r.Read(func(ctx RCtx) error {
+				want := genClusterData(int(ctx.Entry))
+				if !reflect.DeepEqual(data, want) {
+					if len(data.Sli) == 0 && len(want.Sli) == 0 {
+						data.Sli = want.Sli
+					}
+					if !reflect.DeepEqual(data, want) {
+						return fmt.Errorf("entry %d: got=%+v, want=%+v", ctx.Entry, data, want)
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("could not read tree: %+v", err)
+			}
+		})
+	}
+}
+
+func TestWriterOptimizeBaskets(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rtree-")
+	if err != nil {
+		t.Fatalf("could not create dir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	f, err := riofs.Create(filepath.Join(tmp, "optimize.root"))
+	if err != nil {
+		t.Fatalf("could not create file: %+v", err)
+	}
+	defer f.Close()
+
+	var data clusterData
+	w, err := NewWriter(f, "tree", WriteVarsFromStruct(&data), WithAutoFlush(1000), WithBasketSize(1024))
+	if err != nil {
+		t.Fatalf("could not create tree writer: %+v", err)
+	}
+	defer w.Close()
+
+	for i := 0; i < 1000; i++ {
+		data = genClusterData(i)
+		_, err = w.Write()
+		if err != nil {
+			t.Fatalf("could not write event %d: %+v", i, err)
+		}
+	}
+
+	wt := w.(*wtree)
+	for _, b := range wt.ttree.branches {
+		b := b.(*tbranch)
+		if b.basketSize == 1024 {
+			t.Fatalf("branch %q: basket size was not optimized", b.Name())
+		}
+		if b.basketSize%minBasketSize != 0 {
+			t.Fatalf("branch %q: invalid basket size %d", b.Name(), b.basketSize)
+		}
+		if int64(b.basketSize) < b.totBytes/1000*1000 {
+			t.Fatalf("branch %q: basket size %d too small for a cluster (%d bytes)", b.Name(), b.basketSize, b.totBytes)
+		}
+	}
+	if got, want := wt.ttree.branches[1].(*tbranch).basketSize, wt.ttree.branches[0].(*tbranch).basketSize; got <= want {
+		t.Fatalf("invalid basket sizes: f64=%d, i32=%d", got, want)
+	}
+
+	// the second cluster should fit in a single basket per branch.
+	for i := 1000; i < 2000; i++ {
+		data = genClusterData(i)
+		_, err = w.Write()
+		if err != nil {
+			t.Fatalf("could not write event %d: %+v", i, err)
+		}
+	}
+	for _, b := range wt.ttree.branches {
+		b := b.(*tbranch)
+		if got, want := b.basketEntry[len(b.basketEntry)-2:], []int64{1000, 2000}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("branch %q: invalid basket entries: got=%v, want=%v", b.Name(), got, want)
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("could not close tree: %+v", err)
+	}
+}
+
+func TestWriterAutoSave(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rtree-")
+	if err != nil {
+		t.Fatalf("could not create dir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	fname := filepath.Join(tmp, "autosave.root")
+	f, err := riofs.Create(fname)
+	if err != nil {
+		t.Fatalf("could not create file: %+v", err)
+	}
+	defer f.Close()
+
+	var data clusterData
+	w, err := NewWriter(f, "tree", WriteVarsFromStruct(&data), WithAutoFlush(100), WithAutoSave(200))
+	if err != nil {
+		t.Fatalf("could not create tree writer: %+v", err)
+	}
+	defer w.Close()
+
+	for i := 0; i < 450; i++ {
+		data = genClusterData(i)
+		_, err = w.Write()
+		if err != nil {
+			t.Fatalf("could not write event %d: %+v", i, err)
+		}
+	}
+
+	// read back the file, as if the writing job had crashed.
+	r, err := riofs.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open auto-saved file: %+v", err)
+	}
+	defer r.Close()
+
+	if got, want := treeCycles(r, "tree"), []int{2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid auto-saved tree cycles: got=%v, want=%v", got, want)
+	}
+
+	o, err := riofs.Dir(r).Get("tree")
+	if err != nil {
+		t.Fatalf("could not retrieve auto-saved tree: %+v", err)
+	}
+	tree := o.(Tree)
+	if got, want := tree.Entries(), int64(400); got != want {
+		t.Fatalf("invalid number of auto-saved entries: got=%d, want=%d", got, want)
+	}
+
+	rvars := ReadVarsFromStruct(&data)
+	rd, err := NewReader(tree, rvars)
+	if err != nil {
+		t.Fatalf("could not create reader: %+v", err)
+	}
+	defer rd.Close()
+
+	n := 0
+	err = rd.Read(func(ctx RCtx) error {
+		n++
+		if got, want := data.I32, int32(ctx.Entry); got != want {
+			return fmt.Errorf("entry %d: invalid i32: got=%d, want=%d", ctx.Entry, got, want)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not read auto-saved tree: %+v", err)
+	}
+	if n != 400 {
+		t.Fatalf("invalid number of entries read: got=%d, want=%d", n, 400)
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("could not close tree: %+v", err)
+	}
+	err = f.Close()
+	if err != nil {
+		t.Fatalf("could not close file: %+v", err)
+	}
+
+	r, err = riofs.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open file: %+v", err)
+	}
+	defer r.Close()
+
+	if got, want := treeCycles(r, "tree"), []int{3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid tree cycles: got=%v, want=%v", got, want)
+	}
+
+	o, err = r.Get("tree")
+	if err != nil {
+		t.Fatalf("could not retrieve tree: %+v", err)
+	}
+	if got, want := o.(Tree).Entries(), int64(450); got != want {
+		t.Fatalf("invalid number of entries: got=%d, want=%d", got, want)
+	}
+}
+
+// treeCycles returns the cycles of the keys named name in dir.
+func treeCycles(dir riofs.Directory, name string) []int {
+	var cycles []int
+	for _, k := range dir.Keys() {
+		if k.Name() == name {
+			cycles = append(cycles, k.Cycle())
+		}
+	}
+	return cycles
+}
+
+type stdvecElem struct {
+	I32 int32   `groot:"i32"`
+	F64 float64 `groot:"f64"`
+}
+
+func (*stdvecElem) Class() string { return "stdvecElem" }
+
+// stdvecElems is a std::vector<stdvecElem>, streamed as a whole.
+type stdvecElems []stdvecElem
+
+func (*stdvecElems) Class() string { return "vector<stdvecElem>" }
+
+func (vs *stdvecElems) WStreamROOT(w *rbytes.WBuffer) error {
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*vs)))
+	for i := range *vs {
+		v := &(*vs)[i]
+		beg := w.WriteVersion(1)
+		w.WriteI32(v.I32)
+		w.WriteF64(v.F64)
+		if _, err := w.SetByteCount(beg, v.Class()); err != nil {
+			return err
+		}
+	}
+	_, err := w.SetByteCount(pos, vs.Class())
+	return err
+}
+
+func TestWriterStdVectorObject(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "groot-rtree-")
+	if err != nil {
+		t.Fatalf("could not create dir: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	fname := filepath.Join(tmp, "stdvec.root")
+
+	rdict.StreamerInfos.Add(rdict.StreamerOf(
+		rdict.StreamerInfos,
+		reflect.TypeOf((*stdvecElem)(nil)).Elem(),
+	))
+
+	const nevts = 5
+	func() {
+		f, err := riofs.Create(fname)
+		if err != nil {
+			t.Fatalf("could not create file: %+v", err)
+		}
+		defer f.Close()
+
+		var data stdvecElems
+		w, err := NewWriter(f, "tree", []WriteVar{{Name: "elems", Value: &data}})
+		if err != nil {
+			t.Fatalf("could not create tree writer: %+v", err)
+		}
+		defer w.Close()
+
+		for i := 0; i < nevts; i++ {
+			data = data[:0]
+			for j := 0; j < i; j++ {
+				data = append(data, stdvecElem{I32: int32(i), F64: float64(j)})
+			}
+			_, err = w.Write()
+			if err != nil {
+				t.Fatalf("could not write event %d: %+v", i, err)
+			}
+		}
+
+		err = w.Close()
+		if err != nil {
+			t.Fatalf("could not close tree writer: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+	}()
+
+	f, err := riofs.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open file: %+v", err)
+	}
+	defer f.Close()
+
+	o, err := riofs.Dir(f).Get("tree")
+	if err != nil {
+		t.Fatalf("could not get tree: %+v", err)
+	}
+	tree := o.(Tree)
+
+	rvars := NewReadVars(tree)
+	r, err := NewReader(tree, rvars)
+	if err != nil {
+		t.Fatalf("could not create reader: %+v", err)
+	}
+	defer r.Close()
+
+	err = r.Read(func(ctx RCtx) error {
+		elems := reflect.ValueOf(rvars[0].Value).Elem().Field(0)
+		if got, want := elems.Len(), int(ctx.Entry); got != want {
+			return fmt.Errorf("invalid number of elements: got=%d, want=%d", got, want)
+		}
+		for j := 0; j < elems.Len(); j++ {
+			elem := elems.Index(j)
+			if got, want := elem.Field(0).Int(), ctx.Entry; got != want {
+				return fmt.Errorf("invalid i32[%d]: got=%d, want=%d", j, got, want)
+			}
+			if got, want := elem.Field(1).Float(), float64(j); got != want {
+				return fmt.Errorf("invalid f64[%d]: got=%v, want=%v", j, got, want)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not read tree: %+v", err)
+	}
+}
@@ -54,6 +54,7 @@ const (
 	StreamerSTLstring        = 2  // ROOT version for TStreamerSTLstring
 	StreamerArtificial       = 0  // ROOT version for TStreamerArtificial
 	Axis                     = 10 // ROOT version for TAxis
+	Efficiency               = 2  // ROOT version for TEfficiency
 	Graph                    = 4  // ROOT version for TGraph
 	GraphErrors              = 3  // ROOT version for TGraphErrors
 	GraphAsymmErrors         = 3  // ROOT version for TGraphAsymmErrors
@@ -86,6 +87,9 @@ const (
 	BranchElement            = 10 // ROOT version for TBranchElement
 	BranchRef                = 1  // ROOT version for TBranchRef
 	Chain                    = 5  // ROOT version for TChain
+	EntryList                = 2  // ROOT version for TEntryList
+	EntryListBlock           = 1  // ROOT version for TEntryListBlock
+	FriendElement            = 2  // ROOT version for TFriendElement
 	Leaf                     = 2  // ROOT version for TLeaf
 	LeafElement              = 1  // ROOT version for TLeafElement
 	LeafO                    = 1  // ROOT version for TLeafO
@@ -101,4 +105,6 @@ const (
 	Ntuple                   = 2  // ROOT version for TNtuple
 	RefTable                 = 3  // ROOT version for TRefTable
 	Tree                     = 20 // ROOT version for TTree
+	TreeIndex                = 2  // ROOT version for TTreeIndex
+	VirtualIndex             = 1  // ROOT version for TVirtualIndex
 )
@@ -11,9 +11,21 @@ type Bin2D struct {
 	Dist   Dist2D
 }
 
+func (b Bin2D) clone() Bin2D {
+	return Bin2D{
+		XRange: b.XRange.clone(),
+		YRange: b.YRange.clone(),
+		Dist:   b.Dist.clone(),
+	}
+}
+
 // Rank returns the number of dimensions for this bin.
 func (Bin2D) Rank() int { return 2 }
 
+func (b *Bin2D) addScaled(a, a2 float64, o Bin2D) {
+	b.Dist.addScaled(a, a2, o.Dist)
+}
+
 func (b *Bin2D) scaleW(f float64) {
 	b.Dist.scaleW(f)
 }
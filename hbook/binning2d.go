@@ -133,6 +133,34 @@ func newBinning2DFromEdges(xedges, yedges []float64) Binning2D {
 	return bng
 }
 
+func (bng *Binning2D) clone() Binning2D {
+	o := Binning2D{
+		Bins:   make([]Bin2D, len(bng.Bins)),
+		Dist:   bng.Dist.clone(),
+		XRange: bng.XRange.clone(),
+		YRange: bng.YRange.clone(),
+		Nx:     bng.Nx,
+		Ny:     bng.Ny,
+		XEdges: make([]Bin1D, len(bng.XEdges)),
+		YEdges: make([]Bin1D, len(bng.YEdges)),
+	}
+
+	for i, bin := range bng.Bins {
+		o.Bins[i] = bin.clone()
+	}
+	for i, v := range bng.Outflows {
+		o.Outflows[i] = v.clone()
+	}
+	for i, bin := range bng.XEdges {
+		o.XEdges[i] = bin.clone()
+	}
+	for i, bin := range bng.YEdges {
+		o.YEdges[i] = bin.clone()
+	}
+
+	return o
+}
+
 func (bng *Binning2D) entries() int64 {
 	return bng.Dist.Entries()
 }
@@ -175,6 +203,17 @@ func (bng *Binning2D) fill(x, y, w float64) {
 	bng.Bins[idx].fill(x, y, w)
 }
 
+func (bng *Binning2D) scaleW(f float64) {
+	bng.Dist.scaleW(f)
+	for i := range bng.Outflows {
+		bng.Outflows[i].scaleW(f)
+	}
+	for i := range bng.Bins {
+		bin := &bng.Bins[i]
+		bin.scaleW(f)
+	}
+}
+
 func (bng *Binning2D) coordToIndex(x, y float64) int {
 	ix := Bin1Ds(bng.XEdges).IndexOf(x)
 	iy := Bin1Ds(bng.YEdges).IndexOf(y)
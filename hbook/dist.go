@@ -195,6 +195,23 @@ type Dist2D struct {
 	}
 }
 
+func (d Dist2D) clone() Dist2D {
+	return Dist2D{
+		X:     d.X.clone(),
+		Y:     d.Y.clone(),
+		Stats: d.Stats,
+	}
+}
+
+// transpose returns the distribution with the x and y moments swapped.
+func (d Dist2D) transpose() Dist2D {
+	return Dist2D{
+		X:     d.Y.clone(),
+		Y:     d.X.clone(),
+		Stats: d.Stats,
+	}
+}
+
 // Rank returns the number of dimensions of the distribution.
 func (*Dist2D) Rank() int {
 	return 2
@@ -311,6 +328,12 @@ func (d *Dist2D) fill(x, y, w float64) {
 	d.Stats.SumWXY += w * x * y
 }
 
+func (d *Dist2D) addScaled(a, a2 float64, o Dist2D) {
+	d.X.addScaled(a, a2, o.X)
+	d.Y.addScaled(a, a2, o.Y)
+	d.Stats.SumWXY += a * o.Stats.SumWXY
+}
+
 func (d *Dist2D) scaleW(f float64) {
 	d.X.scaleW(f)
 	d.Y.scaleW(f)
@@ -0,0 +1,635 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hbook
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// EffStat describes the statistical method used to compute the
+// confidence interval of an efficiency.
+//
+// The efficiency is computed as the ratio of passed over total events,
+// except for the Bayesian method where the mean of the posterior
+// distribution is used.
+//
+// The values of EffStat match the ones of ROOT's TEfficiency::EStatOption.
+type EffStat int
+
+const (
+	EffClopperPearson EffStat = 0 // Clopper-Pearson interval
+	EffNormal         EffStat = 1 // normal approximation
+	EffWilson         EffStat = 2 // Wilson interval
+	EffAgrestiCoull   EffStat = 3 // Agresti-Coull interval
+	EffFeldmanCousins EffStat = 4 // Feldman-Cousins interval
+	EffBayesian       EffStat = 7 // Bayesian central interval, with a Beta(alpha, beta) prior
+)
+
+func (st EffStat) String() string {
+	switch st {
+	case EffClopperPearson:
+		return "Clopper-Pearson"
+	case EffNormal:
+		return "Normal"
+	case EffWilson:
+		return "Wilson"
+	case EffAgrestiCoull:
+		return "Agresti-Coull"
+	case EffFeldmanCousins:
+		return "Feldman-Cousins"
+	case EffBayesian:
+		return "Bayesian"
+	}
+	return fmt.Sprintf("EffStat(%d)", int(st))
+}
+
+const (
+	// EffDefaultCL is the default confidence level used to compute
+	// efficiency intervals (1 sigma).
+	EffDefaultCL = 0.682689492137
+)
+
+// EffConfig holds the configuration used to compute efficiencies
+// confidence intervals.
+type EffConfig struct {
+	Stat  EffStat // statistical method (default: Clopper-Pearson)
+	CL    float64 // confidence level (default: EffDefaultCL)
+	Alpha float64 // alpha parameter of the Beta prior for the Bayesian method (default: 1)
+	Beta  float64 // beta parameter of the Beta prior for the Bayesian method (default: 1)
+}
+
+func newEffConfig() EffConfig {
+	return EffConfig{
+		Stat:  EffClopperPearson,
+		CL:    EffDefaultCL,
+		Alpha: 1,
+		Beta:  1,
+	}
+}
+
+// Efficiency1D is a 1-dim efficiency, built from a histogram of passed
+// events and a histogram of total events.
+//
+// Efficiency1D handles weighted events.
+// When events are weighted, the confidence intervals of the frequentist
+// methods are computed using the normal approximation, and the ones of the
+// Bayesian method are computed with the effective number of entries.
+type Efficiency1D struct {
+	Passed *H1D
+	Total  *H1D
+	Config EffConfig
+	Ann    Annotation
+}
+
+// NewEfficiency1D returns a 1-dim efficiency with n bins between
+// [xmin, xmax).
+func NewEfficiency1D(n int, xmin, xmax float64) *Efficiency1D {
+	return &Efficiency1D{
+		Passed: NewH1D(n, xmin, xmax),
+		Total:  NewH1D(n, xmin, xmax),
+		Config: newEffConfig(),
+		Ann:    make(Annotation),
+	}
+}
+
+// NewEfficiency1DFromEdges returns a 1-dim efficiency given a slice of
+// edges.
+// The number of bins is thus len(edges)-1.
+func NewEfficiency1DFromEdges(edges []float64) *Efficiency1D {
+	return &Efficiency1D{
+		Passed: NewH1DFromEdges(edges),
+		Total:  NewH1DFromEdges(edges),
+		Config: newEffConfig(),
+		Ann:    make(Annotation),
+	}
+}
+
+// NewEfficiency1DFromH1D returns a 1-dim efficiency from the provided
+// histograms of passed and total events.
+// The histograms are cloned.
+//
+// NewEfficiency1DFromH1D returns an error if the binnings of the histograms
+// are not compatible or if a bin of the passed histogram contains more
+// events than the one of the total histogram.
+func NewEfficiency1DFromH1D(passed, total *H1D) (*Efficiency1D, error) {
+	var (
+		bp = passed.Binning.Bins
+		bt = total.Binning.Bins
+	)
+	if len(bp) != len(bt) {
+		return nil, fmt.Errorf("hbook: passed and total histograms have different number of bins")
+	}
+	for i := range bp {
+		p := &bp[i]
+		t := &bt[i]
+		if !fuzzyEq(p.XMin(), t.XMin()) || !fuzzyEq(p.XMax(), t.XMax()) {
+			return nil, fmt.Errorf("hbook: passed and total histograms have different binnings")
+		}
+		if p.SumW() > t.SumW() {
+			return nil, fmt.Errorf("hbook: bin %d has more passed events (%v) than total events (%v)", i, p.SumW(), t.SumW())
+		}
+	}
+
+	eff := &Efficiency1D{
+		Passed: passed.Clone(),
+		Total:  total.Clone(),
+		Config: newEffConfig(),
+		Ann:    total.Ann.clone(),
+	}
+	return eff, nil
+}
+
+// Name returns the name of this efficiency, if any.
+func (eff *Efficiency1D) Name() string {
+	v, ok := eff.Ann["name"]
+	if !ok {
+		return ""
+	}
+	n, ok := v.(string)
+	if !ok {
+		return ""
+	}
+	return n
+}
+
+// Annotation returns the annotations attached to this efficiency.
+func (eff *Efficiency1D) Annotation() Annotation {
+	return eff.Ann
+}
+
+// Rank returns the number of dimensions for this efficiency.
+func (*Efficiency1D) Rank() int { return 1 }
+
+// Len returns the number of bins of this efficiency.
+func (eff *Efficiency1D) Len() int {
+	return len(eff.Total.Binning.Bins)
+}
+
+// Fill fills this efficiency with the event at x, with weight w.
+// pass indicates whether the event passed the selection.
+func (eff *Efficiency1D) Fill(x float64, pass bool, w float64) {
+	eff.Total.Fill(x, w)
+	if pass {
+		eff.Passed.Fill(x, w)
+	}
+}
+
+// Eff returns the efficiency of the i-th bin.
+func (eff *Efficiency1D) Eff(i int) float64 {
+	var (
+		p = &eff.Passed.Binning.Bins[i].Dist.Dist
+		t = &eff.Total.Binning.Bins[i].Dist.Dist
+	)
+	return eff.Config.value(p, t)
+}
+
+// Interval returns the confidence interval of the efficiency of the i-th bin.
+func (eff *Efficiency1D) Interval(i int) (lo, hi float64) {
+	var (
+		p = &eff.Passed.Binning.Bins[i].Dist.Dist
+		t = &eff.Total.Binning.Bins[i].Dist.Dist
+	)
+	return eff.Config.interval(p, t)
+}
+
+// S2D returns the scatter of the efficiencies, with their asymmetric
+// confidence intervals as y-errors.
+// Bins without any entry are skipped.
+func (eff *Efficiency1D) S2D() *S2D {
+	var (
+		bins = eff.Total.Binning.Bins
+		pts  = make([]Point2D, 0, len(bins))
+	)
+
+	for i := range bins {
+		bin := &bins[i]
+		if bin.SumW() == 0 {
+			continue
+		}
+		var (
+			x      = bin.XMid()
+			y      = eff.Eff(i)
+			lo, hi = eff.Interval(i)
+		)
+		pts = append(pts, Point2D{
+			X:    x,
+			Y:    y,
+			ErrX: Range{Min: x - bin.XMin(), Max: bin.XMax() - x},
+			ErrY: Range{Min: y - lo, Max: hi - y},
+		})
+	}
+
+	s := NewS2D(pts...)
+	s.Annotation()["name"] = eff.Name()
+	if v, ok := eff.Ann["title"]; ok {
+		s.Annotation()["title"] = v
+	}
+	return s
+}
+
+// Efficiency2D is a 2-dim efficiency, built from a histogram of passed
+// events and a histogram of total events.
+//
+// Efficiency2D handles weighted events the same way than Efficiency1D.
+type Efficiency2D struct {
+	Passed *H2D
+	Total  *H2D
+	Config EffConfig
+	Ann    Annotation
+}
+
+// NewEfficiency2D returns a 2-dim efficiency with nx bins between
+// [xlow, xhigh) and ny bins between [ylow, yhigh).
+func NewEfficiency2D(nx int, xlow, xhigh float64, ny int, ylow, yhigh float64) *Efficiency2D {
+	return &Efficiency2D{
+		Passed: NewH2D(nx, xlow, xhigh, ny, ylow, yhigh),
+		Total:  NewH2D(nx, xlow, xhigh, ny, ylow, yhigh),
+		Config: newEffConfig(),
+		Ann:    make(Annotation),
+	}
+}
+
+// NewEfficiency2DFromEdges returns a 2-dim efficiency given slices of
+// edges in x and y.
+func NewEfficiency2DFromEdges(xedges, yedges []float64) *Efficiency2D {
+	return &Efficiency2D{
+		Passed: NewH2DFromEdges(xedges, yedges),
+		Total:  NewH2DFromEdges(xedges, yedges),
+		Config: newEffConfig(),
+		Ann:    make(Annotation),
+	}
+}
+
+// NewEfficiency2DFromH2D returns a 2-dim efficiency from the provided
+// histograms of passed and total events.
+// The histograms are cloned.
+//
+// NewEfficiency2DFromH2D returns an error if the binnings of the histograms
+// are not compatible or if a bin of the passed histogram contains more
+// events than the one of the total histogram.
+func NewEfficiency2DFromH2D(passed, total *H2D) (*Efficiency2D, error) {
+	var (
+		bp = &passed.Binning
+		bt = &total.Binning
+	)
+	if bp.Nx != bt.Nx || bp.Ny != bt.Ny {
+		return nil, fmt.Errorf("hbook: passed and total histograms have different number of bins")
+	}
+	for i := range bp.Bins {
+		p := &bp.Bins[i]
+		t := &bt.Bins[i]
+		if !fuzzyEq(p.XMin(), t.XMin()) || !fuzzyEq(p.XMax(), t.XMax()) ||
+			!fuzzyEq(p.YMin(), t.YMin()) || !fuzzyEq(p.YMax(), t.YMax()) {
+			return nil, fmt.Errorf("hbook: passed and total histograms have different binnings")
+		}
+		if p.SumW() > t.SumW() {
+			return nil, fmt.Errorf("hbook: bin %d has more passed events (%v) than total events (%v)", i, p.SumW(), t.SumW())
+		}
+	}
+
+	eff := &Efficiency2D{
+		Passed: passed.Clone(),
+		Total:  total.Clone(),
+		Config: newEffConfig(),
+		Ann:    total.Ann.clone(),
+	}
+	return eff, nil
+}
+
+// Name returns the name of this efficiency, if any.
+func (eff *Efficiency2D) Name() string {
+	v, ok := eff.Ann["name"]
+	if !ok {
+		return ""
+	}
+	n, ok := v.(string)
+	if !ok {
+		return ""
+	}
+	return n
+}
+
+// Annotation returns the annotations attached to this efficiency.
+func (eff *Efficiency2D) Annotation() Annotation {
+	return eff.Ann
+}
+
+// Rank returns the number of dimensions for this efficiency.
+func (*Efficiency2D) Rank() int { return 2 }
+
+// Fill fills this efficiency with the event at (x,y), with weight w.
+// pass indicates whether the event passed the selection.
+func (eff *Efficiency2D) Fill(x, y float64, pass bool, w float64) {
+	eff.Total.Fill(x, y, w)
+	if pass {
+		eff.Passed.Fill(x, y, w)
+	}
+}
+
+// Eff returns the efficiency of the (ix,iy) bin.
+func (eff *Efficiency2D) Eff(ix, iy int) float64 {
+	var (
+		i = iy*eff.Total.Binning.Nx + ix
+		p = &eff.Passed.Binning.Bins[i].Dist.X.Dist
+		t = &eff.Total.Binning.Bins[i].Dist.X.Dist
+	)
+	return eff.Config.value(p, t)
+}
+
+// Interval returns the confidence interval of the efficiency of
+// the (ix,iy) bin.
+func (eff *Efficiency2D) Interval(ix, iy int) (lo, hi float64) {
+	var (
+		i = iy*eff.Total.Binning.Nx + ix
+		p = &eff.Passed.Binning.Bins[i].Dist.X.Dist
+		t = &eff.Total.Binning.Bins[i].Dist.X.Dist
+	)
+	return eff.Config.interval(p, t)
+}
+
+// value returns the efficiency for the provided passed and total
+// distributions.
+func (cfg EffConfig) value(p, t *Dist0D) float64 {
+	if cfg.Stat == EffBayesian {
+		a, b := cfg.posterior(p, t)
+		return a / (a + b)
+	}
+	if t.SumW == 0 {
+		return 0
+	}
+	return p.SumW / t.SumW
+}
+
+// posterior returns the parameters of the Beta posterior distribution
+// of the efficiency for the provided passed and total distributions.
+func (cfg EffConfig) posterior(p, t *Dist0D) (a, b float64) {
+	var (
+		pw   = p.SumW
+		tw   = t.SumW
+		norm = 1.0
+	)
+	if t.SumW2 != t.SumW || p.SumW2 != p.SumW {
+		// weighted events: use the effective number of entries.
+		norm = 0
+		if t.SumW2 > 0 {
+			norm = tw / t.SumW2
+		}
+	}
+	return pw*norm + cfg.Alpha, (tw-pw)*norm + cfg.Beta
+}
+
+// interval returns the confidence interval of the efficiency for the
+// provided passed and total distributions.
+func (cfg EffConfig) interval(p, t *Dist0D) (lo, hi float64) {
+	var (
+		pw  = p.SumW
+		pw2 = p.SumW2
+		tw  = t.SumW
+		tw2 = t.SumW2
+	)
+	if cfg.Stat == EffBayesian {
+		a, b := cfg.posterior(p, t)
+		return betaCentralInterval(cfg.CL, a, b)
+	}
+
+	if tw == 0 {
+		return 0, 1
+	}
+
+	if tw2 == tw && pw2 == pw {
+		// un-weighted events.
+		return EffInterval(cfg.Stat, tw, pw, cfg.CL, cfg.Alpha, cfg.Beta)
+	}
+
+	if tw2 <= 0 {
+		return 0, 1
+	}
+
+	// normal approximation for weighted events.
+	var (
+		eff      = pw / tw
+		variance = (pw2*(1-2*eff) + tw2*eff*eff) / (tw * tw)
+		delta    = distuv.UnitNormal.Quantile(0.5*(1+cfg.CL)) * math.Sqrt(variance)
+	)
+	return math.Max(0, eff-delta), math.Min(1, eff+delta)
+}
+
+// EffInterval returns the confidence interval, at the cl confidence level,
+// of the efficiency passed/total computed with the st statistical method.
+// alpha and beta are the parameters of the Beta prior used by the Bayesian
+// method.
+func EffInterval(st EffStat, total, passed, cl, alpha, beta float64) (lo, hi float64) {
+	switch st {
+	case EffClopperPearson:
+		return clopperPearson(total, passed, cl)
+	case EffNormal:
+		return normalInterval(total, passed, cl)
+	case EffWilson:
+		return wilsonInterval(total, passed, cl)
+	case EffAgrestiCoull:
+		return agrestiCoull(total, passed, cl)
+	case EffFeldmanCousins:
+		return feldmanCousins(total, passed, cl)
+	case EffBayesian:
+		return betaCentralInterval(cl, passed+alpha, total-passed+beta)
+	default:
+		panic(fmt.Errorf("hbook: invalid efficiency statistic %v", st))
+	}
+}
+
+func clopperPearson(total, passed, cl float64) (lo, hi float64) {
+	alpha := 0.5 * (1 - cl)
+	lo = 0
+	if passed > 0 {
+		lo = distuv.Beta{Alpha: passed, Beta: total - passed + 1}.Quantile(alpha)
+	}
+	hi = 1
+	if passed < total {
+		hi = distuv.Beta{Alpha: passed + 1, Beta: total - passed}.Quantile(1 - alpha)
+	}
+	return lo, hi
+}
+
+func normalInterval(total, passed, cl float64) (lo, hi float64) {
+	if total == 0 {
+		return 0, 1
+	}
+	var (
+		eff   = passed / total
+		sigma = math.Sqrt(eff * (1 - eff) / total)
+		delta = distuv.UnitNormal.Quantile(0.5*(1+cl)) * sigma
+	)
+	return math.Max(0, eff-delta), math.Min(1, eff+delta)
+}
+
+func wilsonInterval(total, passed, cl float64) (lo, hi float64) {
+	if total == 0 {
+		return 0, 1
+	}
+	var (
+		eff   = passed / total
+		kappa = distuv.UnitNormal.Quantile(0.5 * (1 + cl))
+		k2    = kappa * kappa
+		mode  = (passed + 0.5*k2) / (total + k2)
+		delta = kappa / (total + k2) * math.Sqrt(total*eff*(1-eff)+0.25*k2)
+	)
+	return math.Max(0, mode-delta), math.Min(1, mode+delta)
+}
+
+func agrestiCoull(total, passed, cl float64) (lo, hi float64) {
+	var (
+		kappa = distuv.UnitNormal.Quantile(0.5 * (1 + cl))
+		k2    = kappa * kappa
+		mode  = (passed + 0.5*k2) / (total + k2)
+		delta = kappa * math.Sqrt(mode*(1-mode)/(total+k2))
+	)
+	return math.Max(0, mode-delta), math.Min(1, mode+delta)
+}
+
+// betaCentralInterval returns the central interval of a Beta(a,b)
+// distribution, at the cl confidence level.
+func betaCentralInterval(cl, a, b float64) (lo, hi float64) {
+	switch {
+	case a > 0 && b > 0:
+		beta := distuv.Beta{Alpha: a, Beta: b}
+		return beta.Quantile(0.5 * (1 - cl)), beta.Quantile(0.5 * (1 + cl))
+	case a <= 0:
+		return 0, 0
+	default:
+		return 1, 1
+	}
+}
+
+// feldmanCousins returns the Feldman-Cousins interval for a binomial
+// proportion, computed with a Neyman construction using a likelihood-ratio
+// ordering principle.
+func feldmanCousins(total, passed, cl float64) (lo, hi float64) {
+	var (
+		n = int(math.Round(total))
+		k = int(math.Round(passed))
+	)
+	if n <= 0 {
+		return 0, 1
+	}
+
+	// ratio returns the log of the likelihood ratio of x for the
+	// proportion p, with respect to the best-fit proportion x/n.
+	ratio := func(x int, p float64) float64 {
+		return logBinomial(n, x, p) - logBinomial(n, x, float64(x)/float64(n))
+	}
+
+	// accept returns whether k is inside the acceptance region of p.
+	// The likelihood ratio is unimodal in x, with its maximum around n*p:
+	// the acceptance region is thus a contiguous range of x, built
+	// incrementally from that maximum, in decreasing ratio order.
+	accept := func(p float64) bool {
+		mode := int(math.Floor(p * float64(n)))
+		if mode < n && ratio(mode+1, p) > ratio(mode, p) {
+			mode++
+		}
+
+		var (
+			beg = mode
+			end = mode
+			sum = 0.0
+			x   = mode
+		)
+		for {
+			if x == k {
+				return true
+			}
+			sum += math.Exp(logBinomial(n, x, p))
+			if sum >= cl {
+				return false
+			}
+			switch {
+			case beg > 0 && end < n:
+				if ratio(beg-1, p) >= ratio(end+1, p) {
+					beg--
+					x = beg
+				} else {
+					end++
+					x = end
+				}
+			case beg > 0:
+				beg--
+				x = beg
+			case end < n:
+				end++
+				x = end
+			default:
+				return false
+			}
+		}
+	}
+
+	const (
+		ngrid = 1000
+		niter = 30
+	)
+
+	// refine returns the boundary between an accepted and a rejected
+	// proportion, via bisection.
+	refine := func(in, out float64) float64 {
+		for i := 0; i < niter; i++ {
+			mid := 0.5 * (in + out)
+			if accept(mid) {
+				in = mid
+			} else {
+				out = mid
+			}
+		}
+		return in
+	}
+
+	lo, hi = 0, 1
+	if k > 0 {
+		prev := 0.0
+		for i := 1; i <= ngrid; i++ {
+			p := float64(i) / ngrid
+			if accept(p) {
+				lo = refine(p, prev)
+				break
+			}
+			prev = p
+		}
+	}
+	if k < n {
+		prev := 1.0
+		for i := ngrid - 1; i >= 0; i-- {
+			p := float64(i) / ngrid
+			if accept(p) {
+				hi = refine(p, prev)
+				break
+			}
+			prev = p
+		}
+	}
+	return lo, hi
+}
+
+// logBinomial returns the log of the probability to get x successes out of
+// n trials, with a success probability p.
+func logBinomial(n, x int, p float64) float64 {
+	switch {
+	case p <= 0:
+		if x == 0 {
+			return 0
+		}
+		return math.Inf(-1)
+	case p >= 1:
+		if x == n {
+			return 0
+		}
+		return math.Inf(-1)
+	}
+	lgn, _ := math.Lgamma(float64(n + 1))
+	lgx, _ := math.Lgamma(float64(x + 1))
+	lgnx, _ := math.Lgamma(float64(n - x + 1))
+	return lgn - lgx - lgnx + float64(x)*math.Log(p) + float64(n-x)*math.Log1p(-p)
+}
@@ -0,0 +1,278 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hbook
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestEffInterval(t *testing.T) {
+	const (
+		total  = 10.0
+		passed = 3.0
+		cl     = EffDefaultCL
+		tol    = 1e-6
+	)
+
+	// binomial probability to get at least k successes out of n trials.
+	survival := func(n, k int, p float64) float64 {
+		sum := 0.0
+		for x := k; x <= n; x++ {
+			sum += math.Exp(logBinomial(n, x, p))
+		}
+		return sum
+	}
+
+	t.Run("clopper-pearson", func(t *testing.T) {
+		lo, hi := EffInterval(EffClopperPearson, total, passed, cl, 1, 1)
+		alpha := 0.5 * (1 - cl)
+		if got := survival(10, 3, lo); math.Abs(got-alpha) > tol {
+			t.Fatalf("invalid lower bound %v: P(X>=3)=%v, want=%v", lo, got, alpha)
+		}
+		if got := 1 - survival(10, 4, hi); math.Abs(got-alpha) > tol {
+			t.Fatalf("invalid upper bound %v: P(X<=3)=%v, want=%v", hi, got, alpha)
+		}
+
+		lo, _ = EffInterval(EffClopperPearson, total, 0, cl, 1, 1)
+		if lo != 0 {
+			t.Fatalf("invalid lower bound for 0 passed events: %v", lo)
+		}
+		_, hi = EffInterval(EffClopperPearson, total, total, cl, 1, 1)
+		if hi != 1 {
+			t.Fatalf("invalid upper bound for all passed events: %v", hi)
+		}
+	})
+
+	t.Run("wilson", func(t *testing.T) {
+		var (
+			lo, hi = EffInterval(EffWilson, total, passed, cl, 1, 1)
+			z      = distuv.UnitNormal.Quantile(0.5 * (1 + cl))
+			p      = passed / total
+			center = (p + z*z/(2*total)) / (1 + z*z/total)
+			width  = z / (1 + z*z/total) * math.Sqrt(p*(1-p)/total+z*z/(4*total*total))
+		)
+		if math.Abs(lo-(center-width)) > tol || math.Abs(hi-(center+width)) > tol {
+			t.Fatalf("invalid interval: got=[%v, %v], want=[%v, %v]", lo, hi, center-width, center+width)
+		}
+	})
+
+	t.Run("agresti-coull", func(t *testing.T) {
+		var (
+			lo, hi = EffInterval(EffAgrestiCoull, total, passed, cl, 1, 1)
+			z      = distuv.UnitNormal.Quantile(0.5 * (1 + cl))
+			n      = total + z*z
+			p      = (passed + z*z/2) / n
+			width  = z * math.Sqrt(p*(1-p)/n)
+		)
+		if math.Abs(lo-(p-width)) > tol || math.Abs(hi-(p+width)) > tol {
+			t.Fatalf("invalid interval: got=[%v, %v], want=[%v, %v]", lo, hi, p-width, p+width)
+		}
+	})
+
+	t.Run("bayesian", func(t *testing.T) {
+		lo, hi := EffInterval(EffBayesian, total, passed, cl, 1, 1)
+		beta := distuv.Beta{Alpha: passed + 1, Beta: total - passed + 1}
+		if got := beta.CDF(hi) - beta.CDF(lo); math.Abs(got-cl) > tol {
+			t.Fatalf("invalid coverage: got=%v, want=%v", got, cl)
+		}
+		if got, want := beta.CDF(lo), 0.5*(1-cl); math.Abs(got-want) > tol {
+			t.Fatalf("invalid lower tail: got=%v, want=%v", got, want)
+		}
+	})
+
+	t.Run("feldman-cousins", func(t *testing.T) {
+		lo, hi := EffInterval(EffFeldmanCousins, total, passed, cl, 1, 1)
+		if !(lo < passed/total && passed/total < hi) {
+			t.Fatalf("interval [%v, %v] does not contain %v", lo, hi, passed/total)
+		}
+		if lo <= 0 || hi >= 1 {
+			t.Fatalf("invalid interval [%v, %v]", lo, hi)
+		}
+
+		lo, hi = EffInterval(EffFeldmanCousins, total, 0, cl, 1, 1)
+		if lo != 0 || hi <= 0 || hi >= 1 {
+			t.Fatalf("invalid interval for 0 passed events: [%v, %v]", lo, hi)
+		}
+		lo, hi = EffInterval(EffFeldmanCousins, total, total, cl, 1, 1)
+		if hi != 1 || lo <= 0 || lo >= 1 {
+			t.Fatalf("invalid interval for all passed events: [%v, %v]", lo, hi)
+		}
+
+		for _, tc := range []struct {
+			total, passed float64
+			lo, hi        float64
+		}{
+			{10, 3, 0.11545277, 0.5},
+			{10, 0, 0, 0.11545277},
+			{100, 37, 0.31930482, 0.41972456},
+			{20, 20, 0.93961336, 1},
+		} {
+			lo, hi := EffInterval(EffFeldmanCousins, tc.total, tc.passed, 0.6827, 1, 1)
+			if math.Abs(lo-tc.lo) > 1e-6 || math.Abs(hi-tc.hi) > 1e-6 {
+				t.Fatalf(
+					"invalid interval for (%v, %v): got=[%v, %v], want=[%v, %v]",
+					tc.total, tc.passed, lo, hi, tc.lo, tc.hi,
+				)
+			}
+		}
+
+		// large totals: compare with the Wilson interval.
+		lo, hi = EffInterval(EffFeldmanCousins, 1e6, 3e5, cl, 1, 1)
+		wlo, whi := EffInterval(EffWilson, 1e6, 3e5, cl, 1, 1)
+		if math.Abs(lo-wlo) > 1e-5 || math.Abs(hi-whi) > 1e-5 {
+			t.Fatalf("invalid interval for large totals: got=[%v, %v], want=[%v, %v]", lo, hi, wlo, whi)
+		}
+	})
+}
+
+func TestEfficiency1D(t *testing.T) {
+	eff := NewEfficiency1D(4, 0, 4)
+	eff.Ann["name"] = "eff"
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 10; j++ {
+			eff.Fill(float64(i)+0.5, j < 3*i, 1)
+		}
+	}
+
+	if got, want := eff.Len(), 4; got != want {
+		t.Fatalf("invalid number of bins: got=%d, want=%d", got, want)
+	}
+
+	for _, st := range []EffStat{
+		EffClopperPearson, EffNormal, EffWilson,
+		EffAgrestiCoull, EffFeldmanCousins, EffBayesian,
+	} {
+		t.Run(st.String(), func(t *testing.T) {
+			eff.Config.Stat = st
+			s := eff.S2D()
+			if got, want := s.Len(), 4; got != want {
+				t.Fatalf("invalid number of points: got=%d, want=%d", got, want)
+			}
+			if got, want := s.Name(), "eff"; got != want {
+				t.Fatalf("invalid name: got=%q, want=%q", got, want)
+			}
+			for i, pt := range s.Points() {
+				if got, want := pt.X, float64(i)+0.5; got != want {
+					t.Fatalf("point[%d]: invalid x: got=%v, want=%v", i, got, want)
+				}
+				want := 0.3 * float64(i)
+				if st == EffBayesian {
+					// mean of the Beta(k+1, n-k+1) posterior.
+					want = (3*float64(i) + 1) / 12
+				}
+				if got := pt.Y; math.Abs(got-want) > 1e-12 {
+					t.Fatalf("point[%d]: invalid y: got=%v, want=%v", i, got, want)
+				}
+				lo, hi := EffInterval(st, 10, 3*float64(i), EffDefaultCL, 1, 1)
+				if got, want := pt.ErrY, (Range{Min: pt.Y - lo, Max: hi - pt.Y}); got != want {
+					t.Fatalf("point[%d]: invalid y-errors: got=%v, want=%v", i, got, want)
+				}
+				if pt.ErrY.Min < 0 || pt.ErrY.Max < 0 {
+					t.Fatalf("point[%d]: negative y-errors: %v", i, pt.ErrY)
+				}
+			}
+		})
+	}
+}
+
+func TestEfficiency1DWeighted(t *testing.T) {
+	eff := NewEfficiency1D(1, 0, 1)
+	eff.Fill(0.5, true, 2)
+	eff.Fill(0.5, true, 1)
+	eff.Fill(0.5, false, 1)
+	eff.Fill(0.5, false, 0.5)
+
+	if got, want := eff.Eff(0), 3/4.5; math.Abs(got-want) > 1e-12 {
+		t.Fatalf("invalid efficiency: got=%v, want=%v", got, want)
+	}
+
+	var (
+		e     = 3 / 4.5
+		pw2   = 5.0
+		tw2   = 6.25
+		tw    = 4.5
+		sigma = math.Sqrt((pw2*(1-2*e) + tw2*e*e) / (tw * tw))
+		delta = distuv.UnitNormal.Quantile(0.5*(1+EffDefaultCL)) * sigma
+	)
+	lo, hi := eff.Interval(0)
+	if math.Abs(lo-(e-delta)) > 1e-12 || math.Abs(hi-(e+delta)) > 1e-12 {
+		t.Fatalf("invalid interval: got=[%v, %v], want=[%v, %v]", lo, hi, e-delta, e+delta)
+	}
+
+	eff.Config.Stat = EffBayesian
+	lo, hi = eff.Interval(0)
+	if e := eff.Eff(0); !(lo < e && e < hi) {
+		t.Fatalf("invalid bayesian interval: [%v, %v]", lo, hi)
+	}
+}
+
+func TestEfficiency1DFromH1D(t *testing.T) {
+	passed := NewH1D(2, 0, 2)
+	total := NewH1D(2, 0, 2)
+	total.Fill(0.5, 1)
+	total.Fill(1.5, 1)
+	passed.Fill(1.5, 1)
+
+	eff, err := NewEfficiency1DFromH1D(passed, total)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := eff.Eff(1), 1.0; got != want {
+		t.Fatalf("invalid efficiency: got=%v, want=%v", got, want)
+	}
+
+	passed.Fill(1.5, 1)
+	_, err = NewEfficiency1DFromH1D(passed, total)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	_, err = NewEfficiency1DFromH1D(NewH1D(3, 0, 2), total)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestEfficiency2D(t *testing.T) {
+	eff := NewEfficiency2D(2, 0, 2, 2, 0, 2)
+	for j := 0; j < 10; j++ {
+		eff.Fill(0.5, 0.5, j < 2, 1)
+		eff.Fill(1.5, 0.5, j < 4, 1)
+		eff.Fill(0.5, 1.5, j < 6, 1)
+	}
+
+	for _, tc := range []struct {
+		ix, iy int
+		want   float64
+	}{
+		{0, 0, 0.2},
+		{1, 0, 0.4},
+		{0, 1, 0.6},
+		{1, 1, 0},
+	} {
+		if got := eff.Eff(tc.ix, tc.iy); math.Abs(got-tc.want) > 1e-12 {
+			t.Fatalf("bin(%d,%d): invalid efficiency: got=%v, want=%v", tc.ix, tc.iy, got, tc.want)
+		}
+		lo, hi := eff.Interval(tc.ix, tc.iy)
+		if tc.want == 0 {
+			if lo != 0 || hi != 1 {
+				t.Fatalf("bin(%d,%d): invalid interval for empty bin: [%v, %v]", tc.ix, tc.iy, lo, hi)
+			}
+			continue
+		}
+		wlo, whi := EffInterval(EffClopperPearson, 10, 10*tc.want, EffDefaultCL, 1, 1)
+		if math.Abs(lo-wlo) > 1e-12 || math.Abs(hi-whi) > 1e-12 {
+			t.Fatalf("bin(%d,%d): invalid interval: got=[%v, %v], want=[%v, %v]", tc.ix, tc.iy, lo, hi, wlo, whi)
+		}
+	}
+
+	_, err := NewEfficiency2DFromH2D(eff.Total, eff.Passed)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
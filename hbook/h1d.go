@@ -195,6 +195,65 @@ func (h *H1D) Scale(factor float64) {
 	h.Binning.scaleW(factor)
 }
 
+// Rebin returns a new histogram where each group of n consecutive bins
+// of this histogram has been merged into a single bin.
+// If the number of bins is not a multiple of n, the last bin of the new
+// histogram merges the remaining bins.
+// Rebin panics if n is not strictly positive.
+func (h *H1D) Rebin(n int) *H1D {
+	return h.merge(rebinGroups(len(h.Binning.Bins), n))
+}
+
+// RebinEdges returns a new histogram with the provided bin edges.
+// The new edges must be a sorted subset of the edges of this histogram.
+// Bins of this histogram below (resp. above) the first (resp. last)
+// new edge are merged into the underflow (resp. overflow) bin.
+// RebinEdges panics if an edge is not aligned with a bin edge of this
+// histogram.
+func (h *H1D) RebinEdges(edges []float64) *H1D {
+	return h.merge(edgeGroups(edgeRanges(h.Binning.Bins), edges, xAxisErrors))
+}
+
+// Slice returns a new histogram with the bins of this histogram which
+// are fully contained in the [xlo, xhi) range.
+// Bins of this histogram below (resp. above) that range are merged
+// into the underflow (resp. overflow) bin of the new histogram.
+// Slice panics if no bin is contained in the [xlo, xhi) range.
+func (h *H1D) Slice(xlo, xhi float64) *H1D {
+	return h.merge(sliceGroups(edgeRanges(h.Binning.Bins), xlo, xhi, xAxisErrors))
+}
+
+// merge returns a new histogram whose bins are the union of the bins of h
+// in each of the [beg, end) bin index ranges of grps.
+// grps must be sorted and must not overlap.
+// Bins of h before the first group (resp. after the last group) are
+// merged into the underflow (resp. overflow) bin.
+func (h *H1D) merge(grps [][2]int) *H1D {
+	src := h.Binning.Bins
+
+	o := NewH1DFromBins(groupRanges(edgeRanges(src), grps)...)
+	o.Ann = h.Ann.clone()
+	o.Binning.Dist = h.Binning.Dist.clone()
+	o.Binning.Outflows[0] = h.Binning.Outflows[0].clone()
+	o.Binning.Outflows[1] = h.Binning.Outflows[1].clone()
+
+	for i, grp := range grps {
+		dst := &o.Binning.Bins[i]
+		for _, bin := range src[grp[0]:grp[1]] {
+			dst.addScaled(1, 1, bin)
+		}
+	}
+
+	for _, bin := range src[:grps[0][0]] {
+		o.Binning.Outflows[0].addScaled(1, 1, bin.Dist)
+	}
+	for _, bin := range src[grps[len(grps)-1][1]:] {
+		o.Binning.Outflows[1].addScaled(1, 1, bin.Dist)
+	}
+
+	return o
+}
+
 // Integral computes the integral of the histogram.
 //
 // The number of parameters can be 0 or 2.
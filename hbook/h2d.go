@@ -39,6 +39,14 @@ func NewH2DFromEdges(xedges []float64, yedges []float64) *H2D {
 	}
 }
 
+// Clone returns a deep copy of this 2-dim histogram.
+func (h *H2D) Clone() *H2D {
+	return &H2D{
+		Binning: h.Binning.clone(),
+		Ann:     h.Ann.clone(),
+	}
+}
+
 // Name returns the name of this histogram, if any
 func (h *H2D) Name() string {
 	v, ok := h.Ann["name"]
@@ -239,6 +247,256 @@ func (h *H2D) YMax() float64 {
 	return h.Binning.yMax()
 }
 
+// Scale scales the content of each bin by the given factor.
+func (h *H2D) Scale(factor float64) {
+	h.Binning.scaleW(factor)
+}
+
+// Rebin returns a new histogram where each group of nx (resp. ny)
+// consecutive bins along the X (resp. Y) axis of this histogram has been
+// merged into a single bin.
+// If the number of bins along an axis is not a multiple of the rebinning
+// factor, the last bin of the new histogram along that axis merges the
+// remaining bins.
+// Rebin panics if nx or ny is not strictly positive.
+func (h *H2D) Rebin(nx, ny int) *H2D {
+	bng := &h.Binning
+	return h.merge(rebinGroups(bng.Nx, nx), rebinGroups(bng.Ny, ny))
+}
+
+// RebinEdges returns a new histogram with the provided X and Y bin edges.
+// The new edges must be a sorted subset of the edges of this histogram
+// along each axis.
+// A nil slice of edges leaves the binning of the corresponding axis
+// unchanged.
+// Bins of this histogram outside the new edges are merged into the
+// corresponding outflow bins.
+// RebinEdges panics if an edge is not aligned with a bin edge of this
+// histogram.
+func (h *H2D) RebinEdges(xedges, yedges []float64) *H2D {
+	var (
+		bng = &h.Binning
+		gx  = rebinGroups(bng.Nx, 1)
+		gy  = rebinGroups(bng.Ny, 1)
+	)
+	if xedges != nil {
+		gx = edgeGroups(edgeRanges(bng.XEdges), xedges, xAxisErrors)
+	}
+	if yedges != nil {
+		gy = edgeGroups(edgeRanges(bng.YEdges), yedges, yAxisErrors)
+	}
+	return h.merge(gx, gy)
+}
+
+// Slice returns a new histogram with the bins of this histogram which
+// are fully contained in the [xlo, xhi) x [ylo, yhi) range.
+// Bins of this histogram outside that range are merged into the
+// corresponding outflow bins of the new histogram.
+// Slice panics if no bin is contained in the [xlo, xhi) or [ylo, yhi) ranges.
+func (h *H2D) Slice(xlo, xhi, ylo, yhi float64) *H2D {
+	bng := &h.Binning
+	return h.merge(
+		sliceGroups(edgeRanges(bng.XEdges), xlo, xhi, xAxisErrors),
+		sliceGroups(edgeRanges(bng.YEdges), ylo, yhi, yAxisErrors),
+	)
+}
+
+// merge returns a new histogram whose bins are the union of the bins of h
+// in each of the [beg, end) X-bin index ranges of gx and [beg, end)
+// Y-bin index ranges of gy.
+// gx and gy must be sorted and must not overlap.
+// Bins of h outside of these ranges are merged into the corresponding
+// outflow bins.
+// Outflow bins of h are kept as outflow bins of the same kind.
+func (h *H2D) merge(gx, gy [][2]int) *H2D {
+	var (
+		src = &h.Binning
+		o   = NewH2DFromEdges(
+			rangeEdges(groupRanges(edgeRanges(src.XEdges), gx)),
+			rangeEdges(groupRanges(edgeRanges(src.YEdges), gy)),
+		)
+		dst = &o.Binning
+		ix  = groupIndices(src.Nx, gx)
+		iy  = groupIndices(src.Ny, gy)
+	)
+	o.Ann = h.Ann.clone()
+	dst.Dist = src.Dist.clone()
+	for i, v := range src.Outflows {
+		dst.Outflows[i] = v.clone()
+	}
+
+	for j := 0; j < src.Ny; j++ {
+		for i := 0; i < src.Nx; i++ {
+			bin := src.Bins[j*src.Nx+i]
+			idx := outflowIndex2D(ix[i], iy[j])
+			if idx < 0 {
+				dst.Outflows[-idx-1].addScaled(1, 1, bin.Dist)
+				continue
+			}
+			dst.Bins[iy[j]*dst.Nx+ix[i]].addScaled(1, 1, bin)
+		}
+	}
+
+	return o
+}
+
+// outflowIndex2D returns the (negative) outflow index corresponding to the
+// provided X and Y bin indices, or 0 if both indices are in range.
+func outflowIndex2D(ix, iy int) int {
+	switch {
+	case ix == OverflowBin1D && iy == OverflowBin1D:
+		return -BngNE
+	case ix == OverflowBin1D && iy == UnderflowBin1D:
+		return -BngSE
+	case ix == UnderflowBin1D && iy == UnderflowBin1D:
+		return -BngSW
+	case ix == UnderflowBin1D && iy == OverflowBin1D:
+		return -BngNW
+	case ix == OverflowBin1D:
+		return -BngE
+	case ix == UnderflowBin1D:
+		return -BngW
+	case iy == OverflowBin1D:
+		return -BngN
+	case iy == UnderflowBin1D:
+		return -BngS
+	}
+	return 0
+}
+
+// ProjectionX returns the 1-dim histogram of the x-distribution of this
+// 2-dim histogram, summed over all its y bins.
+// Entries whose y-coordinate is outside the y-range of this histogram
+// are not included in the projection.
+func (h *H2D) ProjectionX() *H1D {
+	var (
+		bng = &h.Binning
+		o   = NewH1DFromBins(edgeRanges(bng.XEdges)...)
+	)
+	o.Ann = h.Ann.clone()
+
+	for iy := 0; iy < bng.Ny; iy++ {
+		for ix := 0; ix < bng.Nx; ix++ {
+			o.Binning.Bins[ix].Dist.addScaled(1, 1, bng.Bins[iy*bng.Nx+ix].Dist.X)
+		}
+	}
+	o.Binning.Outflows[0] = bng.Outflows[BngW-1].X.clone()
+	o.Binning.Outflows[1] = bng.Outflows[BngE-1].X.clone()
+	o.Binning.Dist = projDist(o.Binning.Bins, o.Binning.Outflows)
+
+	return o
+}
+
+// ProjectionY returns the 1-dim histogram of the y-distribution of this
+// 2-dim histogram, summed over all its x bins.
+// Entries whose x-coordinate is outside the x-range of this histogram
+// are not included in the projection.
+func (h *H2D) ProjectionY() *H1D {
+	var (
+		bng = &h.Binning
+		o   = NewH1DFromBins(edgeRanges(bng.YEdges)...)
+	)
+	o.Ann = h.Ann.clone()
+
+	for iy := 0; iy < bng.Ny; iy++ {
+		for ix := 0; ix < bng.Nx; ix++ {
+			o.Binning.Bins[iy].Dist.addScaled(1, 1, bng.Bins[iy*bng.Nx+ix].Dist.Y)
+		}
+	}
+	o.Binning.Outflows[0] = bng.Outflows[BngS-1].Y.clone()
+	o.Binning.Outflows[1] = bng.Outflows[BngN-1].Y.clone()
+	o.Binning.Dist = projDist(o.Binning.Bins, o.Binning.Outflows)
+
+	return o
+}
+
+// ProfileX returns the 1-dim profile histogram of the mean y-value
+// as a function of x.
+// Entries whose y-coordinate is outside the y-range of this histogram
+// are not included in the profile.
+func (h *H2D) ProfileX() *P1D {
+	var (
+		bng = &h.Binning
+		o   = NewP1DFromEdges(binEdges(bng.XEdges))
+	)
+	o.ann = h.Ann.clone()
+
+	for iy := 0; iy < bng.Ny; iy++ {
+		for ix := 0; ix < bng.Nx; ix++ {
+			o.bng.bins[ix].dist.addScaled(1, 1, bng.Bins[iy*bng.Nx+ix].Dist)
+		}
+	}
+	o.bng.outflows[0] = bng.Outflows[BngW-1].clone()
+	o.bng.outflows[1] = bng.Outflows[BngE-1].clone()
+	o.bng.dist = profDist(o.bng.bins, o.bng.outflows)
+
+	return o
+}
+
+// ProfileY returns the 1-dim profile histogram of the mean x-value
+// as a function of y.
+// Entries whose x-coordinate is outside the x-range of this histogram
+// are not included in the profile.
+func (h *H2D) ProfileY() *P1D {
+	var (
+		bng = &h.Binning
+		o   = NewP1DFromEdges(binEdges(bng.YEdges))
+	)
+	o.ann = h.Ann.clone()
+
+	for iy := 0; iy < bng.Ny; iy++ {
+		for ix := 0; ix < bng.Nx; ix++ {
+			o.bng.bins[iy].dist.addScaled(1, 1, bng.Bins[iy*bng.Nx+ix].Dist.transpose())
+		}
+	}
+	o.bng.outflows[0] = bng.Outflows[BngS-1].transpose()
+	o.bng.outflows[1] = bng.Outflows[BngN-1].transpose()
+	o.bng.dist = profDist(o.bng.bins, o.bng.outflows)
+
+	return o
+}
+
+// edgeRanges returns the ranges of the provided edge bins.
+func edgeRanges(bins []Bin1D) []Range {
+	o := make([]Range, len(bins))
+	for i, bin := range bins {
+		o[i] = bin.Range
+	}
+	return o
+}
+
+// binEdges returns the edges of the provided (contiguous) edge bins.
+func binEdges(bins []Bin1D) []float64 {
+	o := make([]float64, len(bins)+1)
+	for i, bin := range bins {
+		o[i] = bin.XMin()
+	}
+	o[len(bins)] = bins[len(bins)-1].XMax()
+	return o
+}
+
+// projDist returns the total distribution of the provided bins and outflows.
+func projDist(bins []Bin1D, outflows [2]Dist1D) Dist1D {
+	var o Dist1D
+	for _, bin := range bins {
+		o.addScaled(1, 1, bin.Dist)
+	}
+	o.addScaled(1, 1, outflows[0])
+	o.addScaled(1, 1, outflows[1])
+	return o
+}
+
+// profDist returns the total distribution of the provided bins and outflows.
+func profDist(bins []BinP1D, outflows [2]Dist2D) Dist2D {
+	var o Dist2D
+	for _, bin := range bins {
+		o.addScaled(1, 1, bin.dist)
+	}
+	o.addScaled(1, 1, outflows[0])
+	o.addScaled(1, 1, outflows[1])
+	return o
+}
+
 // Integral computes the integral of the histogram.
 //
 // Overflows are included in the computation.
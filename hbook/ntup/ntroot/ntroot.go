@@ -12,10 +12,22 @@
 //      log.Fatalf("%+v", err)
 //  }
 //  defer nt.DB().Close()
+//
+// N-tuples can also be created and filled:
+//
+//  nt, err := ntroot.Create("out.root", "mytree", int64(0), float64(0))
+//  if err != nil {
+//      log.Fatalf("%+v", err)
+//  }
+//  defer nt.DB().Close()
+//
+//  _, err = nt.DB().Exec("INSERT INTO mytree VALUES (?, ?)", 42, 66.6)
 package ntroot // import "go-hep.org/x/hep/hbook/ntup/ntroot"
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
 	"go-hep.org/x/hep/groot"
 	"go-hep.org/x/hep/groot/riofs"
@@ -53,3 +65,71 @@ func Open(name, tree string) (*ntup.Ntuple, error) {
 	}
 	return nt, nil
 }
+
+// Create creates the named ROOT file and returns an n-tuple backed by a new
+// tree, whose columns are described by cols.
+// See ntup.Create for the description of cols.
+//
+// The n-tuple is filled with INSERT statements executed on nt.DB().
+// The tree is written out when the database is closed.
+func Create(name, tree string, cols ...interface{}) (*ntup.Ntuple, error) {
+	db, err := rsqldrv.Create(name)
+	if err != nil {
+		return nil, fmt.Errorf("could not create ROOT db: %w", err)
+	}
+
+	nt, err := ntup.Create(db, tree, cols...)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("could not create n-tuple %q: %w", tree, err)
+	}
+
+	defs := make([]string, len(nt.Cols()))
+	for i, col := range nt.Cols() {
+		typ, err := sqlTypeOf(col.Type())
+		if err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("could not create column %q: %w", col.Name(), err)
+		}
+		defs[i] = col.Name() + " " + typ
+	}
+
+	_, err = db.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", tree, strings.Join(defs, ", ")))
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("could not create ROOT tree %q: %w", tree, err)
+	}
+
+	return nt, nil
+}
+
+// sqlTypeOf returns the SQL column type of the provided Go type.
+func sqlTypeOf(rt reflect.Type) (string, error) {
+	switch rt.Kind() {
+	case reflect.Bool:
+		return "BIT", nil
+	case reflect.Int8:
+		return "TINYINT", nil
+	case reflect.Int16:
+		return "SMALLINT", nil
+	case reflect.Int32:
+		return "INT", nil
+	case reflect.Int64:
+		return "BIGINT", nil
+	case reflect.Uint8:
+		return "TINYINT UNSIGNED", nil
+	case reflect.Uint16:
+		return "SMALLINT UNSIGNED", nil
+	case reflect.Uint32:
+		return "INT UNSIGNED", nil
+	case reflect.Uint64:
+		return "BIGINT UNSIGNED", nil
+	case reflect.Float32:
+		return "FLOAT", nil
+	case reflect.Float64:
+		return "DOUBLE", nil
+	case reflect.String:
+		return "TEXT", nil
+	}
+	return "", fmt.Errorf("type %v not supported", rt)
+}
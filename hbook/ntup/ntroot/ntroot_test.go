@@ -6,6 +6,10 @@ package ntroot_test
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"go-hep.org/x/hep/hbook/ntup/ntroot"
@@ -58,3 +62,79 @@ func TestOpen(t *testing.T) {
 		})
 	}
 }
+
+func TestCreate(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "ntroot-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	fname := filepath.Join(tmp, "create.root")
+
+	type data struct {
+		I32 int32   `hbook:"i32"`
+		U64 uint64  `hbook:"u64"`
+		F64 float64 `hbook:"f64"`
+		Str string  `hbook:"str"`
+	}
+
+	nt, err := ntroot.Create(fname, "tree", data{})
+	if err != nil {
+		t.Fatalf("could not create n-tuple: %+v", err)
+	}
+	defer nt.DB().Close()
+
+	want := []data{
+		{1, 10, 1.5, "one"},
+		{2, 20, 2.5, "two"},
+		{3, 30, 3.5, "three"},
+	}
+
+	for _, v := range want {
+		_, err = nt.DB().Exec("INSERT INTO tree VALUES (?, ?, ?, ?)", v.I32, v.U64, v.F64, v.Str)
+		if err != nil {
+			t.Fatalf("could not insert row: %+v", err)
+		}
+	}
+
+	err = nt.DB().Close()
+	if err != nil {
+		t.Fatalf("could not close n-tuple: %+v", err)
+	}
+
+	nt, err = ntroot.Open(fname, "tree")
+	if err != nil {
+		t.Fatalf("could not open n-tuple: %+v", err)
+	}
+	defer nt.DB().Close()
+
+	var got []data
+	err = nt.Scan("i32, u64, f64, str", func(i32 int32, u64 uint64, f64 float64, str string) error {
+		got = append(got, data{i32, u64, f64, str})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not scan n-tuple: %+v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid n-tuple content:\ngot= %#v\nwant=%#v", got, want)
+	}
+}
+
+func TestCreateErrors(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "ntroot-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	_, err = ntroot.Create(filepath.Join(tmp, "create.root"), "tree", complex(1, 2))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if got, want := err.Error(), `could not create column "var1": type complex128 not supported`; got != want {
+		t.Fatalf("invalid error:\ngot= %v\nwant=%v", got, want)
+	}
+}
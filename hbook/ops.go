@@ -157,3 +157,128 @@ func AddH1D(h1, h2 *H1D) *H1D {
 func SubH1D(h1, h2 *H1D) *H1D {
 	return AddScaledH1D(h1, -1, h2)
 }
+
+// AddScaledH2D returns the histogram with the bin-by-bin h1+alpha*h2
+// operation, assuming statistical uncertainties are uncorrelated.
+func AddScaledH2D(h1 *H2D, alpha float64, h2 *H2D) *H2D {
+	b1 := &h1.Binning
+	b2 := &h2.Binning
+	if b1.Nx != b2.Nx || b1.Ny != b2.Ny {
+		panic(fmt.Errorf("hbook: h1 and h2 have different number of bins"))
+	}
+
+	if h1.XMin() != h2.XMin() || h1.XMax() != h2.XMax() ||
+		h1.YMin() != h2.YMin() || h1.YMax() != h2.YMax() {
+		panic(fmt.Errorf("hbook: h1 and h2 have different range"))
+	}
+
+	var (
+		o  = h1.Clone()
+		a2 = alpha * alpha
+	)
+
+	for i := range o.Binning.Bins {
+		o := &o.Binning.Bins[i]
+		o.addScaled(alpha, a2, b2.Bins[i])
+	}
+
+	o.Binning.Dist.addScaled(alpha, a2, b2.Dist)
+	for i := range o.Binning.Outflows {
+		o.Binning.Outflows[i].addScaled(alpha, a2, b2.Outflows[i])
+	}
+	return o
+}
+
+// AddH2D returns the bin-by-bin summed histogram of h1 and h2
+// assuming their statistical uncertainties are uncorrelated.
+func AddH2D(h1, h2 *H2D) *H2D {
+	return AddScaledH2D(h1, 1, h2)
+}
+
+// SubH2D returns the bin-by-bin subtracted histogram of h1 and h2
+// assuming their statistical uncertainties are uncorrelated.
+func SubH2D(h1, h2 *H2D) *H2D {
+	return AddScaledH2D(h1, -1, h2)
+}
+
+// DivideH2D divides 2 2D-histograms and returns a 2D-histogram holding
+// the bin-by-bin ratio.
+// Each bin of the returned histogram contains a single entry at the center
+// of the bin, with the ratio as its sum of weights and the squared
+// uncertainty of the ratio as its sum of squared weights.
+// Outflows are not divided.
+// DivideH2D returns an error if the binning of the 2D histograms are not compatible.
+// If no DivOptions is passed, NaN raised during division are kept.
+// Bins with NaNs are left empty when DivIgnoreNaNs is passed.
+func DivideH2D(num, den *H2D, opts ...DivOptions) (*H2D, error) {
+	cfg := newDivConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	bng1 := &num.Binning
+	bng2 := &den.Binning
+	if bng1.Nx != bng2.Nx || bng1.Ny != bng2.Ny {
+		return nil, fmt.Errorf("hbook: binnings are not equivalent in %v / %v", num.Name(), den.Name())
+	}
+
+	o := NewH2DFromEdges(binEdges(bng1.XEdges), binEdges(bng1.YEdges))
+	o.Ann = num.Ann.clone()
+
+	for i := range bng1.Bins {
+		b1 := &bng1.Bins[i]
+		b2 := &bng2.Bins[i]
+
+		if !fuzzyEq(b1.XMin(), b2.XMin()) || !fuzzyEq(b1.XMax(), b2.XMax()) ||
+			!fuzzyEq(b1.YMin(), b2.YMin()) || !fuzzyEq(b1.YMax(), b2.YMax()) {
+			return nil, fmt.Errorf("hbook: binnings are not equivalent in %v / %v", num.Name(), den.Name())
+		}
+
+		var (
+			z, ez float64
+			w1    = b1.SumW()
+			w2    = b2.SumW()
+			e1    = math.Sqrt(b1.SumW2())
+			e2    = math.Sqrt(b2.SumW2())
+		)
+
+		switch {
+		case w2 == 0 || (w1 == 0 && e1 != 0):
+			if cfg.ignoreNaN {
+				continue
+			}
+			z = cfg.replaceNaN
+		default:
+			z = w1 / w2
+			relerr1 := 0.0
+			if e1 != 0 {
+				relerr1 = e1 / w1
+			}
+			relerr2 := 0.0
+			if e2 != 0 {
+				relerr2 = e2 / w2
+			}
+			ez = z * math.Sqrt(relerr1*relerr1+relerr2*relerr2)
+		}
+
+		bin := &o.Binning.Bins[i]
+		bin.Dist = ratioDist2D(bin.XMid(), bin.YMid(), z, ez)
+		o.Binning.Dist.addScaled(1, 1, bin.Dist)
+	}
+
+	return o, nil
+}
+
+// ratioDist2D returns a distribution with a single entry at (x,y),
+// with sum of weights z and sum of squared weights ez^2.
+func ratioDist2D(x, y, z, ez float64) Dist2D {
+	var d Dist2D
+	d.X.Dist = Dist0D{N: 1, SumW: z, SumW2: ez * ez}
+	d.X.Stats.SumWX = z * x
+	d.X.Stats.SumWX2 = z * x * x
+	d.Y.Dist = d.X.Dist
+	d.Y.Stats.SumWX = z * y
+	d.Y.Stats.SumWX2 = z * y * y
+	d.Stats.SumWXY = z * x * y
+	return d
+}
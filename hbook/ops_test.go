@@ -7,6 +7,7 @@ package hbook
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"reflect"
 	"testing"
 
@@ -327,3 +328,508 @@ END YODA_HISTO1D_V2
 		)
 	}
 }
+
+func TestH1DRebin(t *testing.T) {
+	h := NewH1D(10, 0, 10)
+	h.Fill(-1, 1)
+	for i := 0; i < 10; i++ {
+		h.Fill(float64(i)+0.5, float64(i+1))
+	}
+	h.Fill(11, 2)
+
+	for _, tc := range []struct {
+		name  string
+		h     *H1D
+		edges []float64
+		sumw  []float64
+		uflow float64
+		oflow float64
+	}{
+		{
+			name:  "rebin-2",
+			h:     h.Rebin(2),
+			edges: []float64{0, 2, 4, 6, 8, 10},
+			sumw:  []float64{3, 7, 11, 15, 19},
+			uflow: 1,
+			oflow: 2,
+		},
+		{
+			name:  "rebin-3",
+			h:     h.Rebin(3),
+			edges: []float64{0, 3, 6, 9, 10},
+			sumw:  []float64{6, 15, 24, 10},
+			uflow: 1,
+			oflow: 2,
+		},
+		{
+			name:  "rebin-edges",
+			h:     h.RebinEdges([]float64{1, 2, 5, 9}),
+			edges: []float64{1, 2, 5, 9},
+			sumw:  []float64{2, 12, 30},
+			uflow: 2,
+			oflow: 12,
+		},
+		{
+			name:  "slice",
+			h:     h.Slice(2, 4.5),
+			edges: []float64{2, 3, 4},
+			sumw:  []float64{3, 4},
+			uflow: 4,
+			oflow: 47,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bins := tc.h.Binning.Bins
+			if got, want := len(bins), len(tc.sumw); got != want {
+				t.Fatalf("invalid number of bins: got=%d, want=%d", got, want)
+			}
+			for i, bin := range bins {
+				if got, want := bin.XMin(), tc.edges[i]; got != want {
+					t.Fatalf("bin[%d]: invalid xmin: got=%v, want=%v", i, got, want)
+				}
+				if got, want := bin.XMax(), tc.edges[i+1]; got != want {
+					t.Fatalf("bin[%d]: invalid xmax: got=%v, want=%v", i, got, want)
+				}
+				if got, want := bin.SumW(), tc.sumw[i]; got != want {
+					t.Fatalf("bin[%d]: invalid sumw: got=%v, want=%v", i, got, want)
+				}
+			}
+			if got, want := tc.h.Binning.Outflows[0].SumW(), tc.uflow; got != want {
+				t.Fatalf("invalid underflow: got=%v, want=%v", got, want)
+			}
+			if got, want := tc.h.Binning.Outflows[1].SumW(), tc.oflow; got != want {
+				t.Fatalf("invalid overflow: got=%v, want=%v", got, want)
+			}
+			if got, want := tc.h.Entries(), h.Entries(); got != want {
+				t.Fatalf("invalid entries: got=%d, want=%d", got, want)
+			}
+			if got, want := tc.h.SumW(), h.SumW(); got != want {
+				t.Fatalf("invalid sumw: got=%v, want=%v", got, want)
+			}
+			if got, want := tc.h.XMean(), h.XMean(); got != want {
+				t.Fatalf("invalid mean: got=%v, want=%v", got, want)
+			}
+		})
+	}
+}
+
+func TestH1DRebinPanics(t *testing.T) {
+	h := NewH1D(10, 0, 10)
+	for _, tc := range []struct {
+		name string
+		f    func()
+	}{
+		{"rebin-0", func() { h.Rebin(0) }},
+		{"edges-short", func() { h.RebinEdges([]float64{1}) }},
+		{"edges-unaligned", func() { h.RebinEdges([]float64{1, 2.5}) }},
+		{"edges-unsorted", func() { h.RebinEdges([]float64{2, 1}) }},
+		{"slice-empty", func() { h.Slice(2.2, 2.8) }},
+		{"slice-invalid", func() { h.Slice(3, 2) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if e := recover(); e == nil {
+					t.Fatalf("expected a panic")
+				}
+			}()
+			tc.f()
+		})
+	}
+}
+
+func TestP1DRebin(t *testing.T) {
+	p := NewP1D(10, 0, 10)
+	p.Fill(-1, 10, 1)
+	for i := 0; i < 10; i++ {
+		p.Fill(float64(i)+0.5, float64(i), float64(i+1))
+	}
+	p.Fill(11, 20, 2)
+
+	for _, tc := range []struct {
+		name  string
+		p     *P1D
+		edges []float64
+		sumw  []float64
+		ymean []float64
+		uflow float64
+		oflow float64
+	}{
+		{
+			name:  "rebin-3",
+			p:     p.Rebin(3),
+			edges: []float64{0, 3, 6, 9, 10},
+			sumw:  []float64{6, 15, 24, 10},
+			ymean: []float64{8. / 6, 62. / 15, 170. / 24, 9},
+			uflow: 1,
+			oflow: 2,
+		},
+		{
+			name:  "rebin-edges",
+			p:     p.RebinEdges([]float64{1, 2, 5, 9}),
+			edges: []float64{1, 2, 5, 9},
+			sumw:  []float64{2, 12, 30},
+			ymean: []float64{1, 38. / 12, 200. / 30},
+			uflow: 2,
+			oflow: 12,
+		},
+		{
+			name:  "slice",
+			p:     p.Slice(2, 4.5),
+			edges: []float64{2, 3, 4},
+			sumw:  []float64{3, 4},
+			ymean: []float64{2, 3},
+			uflow: 4,
+			oflow: 47,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bins := tc.p.Binning().Bins()
+			if got, want := len(bins), len(tc.sumw); got != want {
+				t.Fatalf("invalid number of bins: got=%d, want=%d", got, want)
+			}
+			for i, bin := range bins {
+				if got, want := bin.XMin(), tc.edges[i]; got != want {
+					t.Fatalf("bin[%d]: invalid xmin: got=%v, want=%v", i, got, want)
+				}
+				if got, want := bin.XMax(), tc.edges[i+1]; got != want {
+					t.Fatalf("bin[%d]: invalid xmax: got=%v, want=%v", i, got, want)
+				}
+				if got, want := bin.SumW(), tc.sumw[i]; got != want {
+					t.Fatalf("bin[%d]: invalid sumw: got=%v, want=%v", i, got, want)
+				}
+				if got, want := bin.YMean(), tc.ymean[i]; !fuzzyEq(got, want) {
+					t.Fatalf("bin[%d]: invalid y-mean: got=%v, want=%v", i, got, want)
+				}
+			}
+			if got, want := tc.p.bng.outflows[0].SumW(), tc.uflow; got != want {
+				t.Fatalf("invalid underflow: got=%v, want=%v", got, want)
+			}
+			if got, want := tc.p.bng.outflows[1].SumW(), tc.oflow; got != want {
+				t.Fatalf("invalid overflow: got=%v, want=%v", got, want)
+			}
+			if got, want := tc.p.Entries(), p.Entries(); got != want {
+				t.Fatalf("invalid entries: got=%d, want=%d", got, want)
+			}
+			if got, want := tc.p.SumW(), p.SumW(); got != want {
+				t.Fatalf("invalid sumw: got=%v, want=%v", got, want)
+			}
+		})
+	}
+}
+
+func TestH2DRebin(t *testing.T) {
+	h := NewH2D(4, 0, 4, 3, 0, 3)
+	for ix := 0; ix < 4; ix++ {
+		for iy := 0; iy < 3; iy++ {
+			h.Fill(float64(ix)+0.5, float64(iy)+0.5, float64(1+ix+4*iy))
+		}
+	}
+	h.Fill(-1, 1.5, 100)
+	h.Fill(5, -1, 200)
+
+	for _, tc := range []struct {
+		name   string
+		h      *H2D
+		xedges []float64
+		yedges []float64
+		sumw   []float64
+		oflows map[int]float64
+	}{
+		{
+			name:   "rebin-2x2",
+			h:      h.Rebin(2, 2),
+			xedges: []float64{0, 2, 4},
+			yedges: []float64{0, 2, 3},
+			sumw:   []float64{1 + 2 + 5 + 6, 3 + 4 + 7 + 8, 9 + 10, 11 + 12},
+			oflows: map[int]float64{BngW: 100, BngSE: 200},
+		},
+		{
+			name:   "rebin-x",
+			h:      h.Rebin(4, 1),
+			xedges: []float64{0, 4},
+			yedges: []float64{0, 1, 2, 3},
+			sumw:   []float64{10, 26, 42},
+			oflows: map[int]float64{BngW: 100, BngSE: 200},
+		},
+		{
+			name:   "rebin-edges",
+			h:      h.RebinEdges([]float64{1, 3}, nil),
+			xedges: []float64{1, 3},
+			yedges: []float64{0, 1, 2, 3},
+			sumw:   []float64{2 + 3, 6 + 7, 10 + 11},
+			oflows: map[int]float64{BngW: 100 + 1 + 5 + 9, BngE: 4 + 8 + 12, BngSE: 200},
+		},
+		{
+			name:   "slice",
+			h:      h.Slice(1, 3, 1, 2),
+			xedges: []float64{1, 2, 3},
+			yedges: []float64{1, 2},
+			sumw:   []float64{6, 7},
+			oflows: map[int]float64{
+				BngW: 100 + 5, BngE: 8,
+				BngSW: 1, BngS: 2 + 3, BngSE: 200 + 4,
+				BngNW: 9, BngN: 10 + 11, BngNE: 12,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bng := &tc.h.Binning
+			if got, want := binEdges(bng.XEdges), tc.xedges; !reflect.DeepEqual(got, want) {
+				t.Fatalf("invalid x-edges: got=%v, want=%v", got, want)
+			}
+			if got, want := binEdges(bng.YEdges), tc.yedges; !reflect.DeepEqual(got, want) {
+				t.Fatalf("invalid y-edges: got=%v, want=%v", got, want)
+			}
+			if got, want := len(bng.Bins), len(tc.sumw); got != want {
+				t.Fatalf("invalid number of bins: got=%d, want=%d", got, want)
+			}
+			for i, bin := range bng.Bins {
+				if got, want := bin.SumW(), tc.sumw[i]; got != want {
+					t.Fatalf("bin[%d]: invalid sumw: got=%v, want=%v", i, got, want)
+				}
+			}
+			for i, oflow := range bng.Outflows {
+				if got, want := oflow.SumW(), tc.oflows[i+1]; got != want {
+					t.Fatalf("outflow[%d]: invalid sumw: got=%v, want=%v", i+1, got, want)
+				}
+			}
+			if got, want := tc.h.Entries(), h.Entries(); got != want {
+				t.Fatalf("invalid entries: got=%d, want=%d", got, want)
+			}
+			if got, want := tc.h.SumW(), h.SumW(); got != want {
+				t.Fatalf("invalid sumw: got=%v, want=%v", got, want)
+			}
+		})
+	}
+}
+
+func TestH2DRebinPanics(t *testing.T) {
+	h := NewH2D(4, 0, 4, 3, 0, 3)
+	for _, tc := range []struct {
+		name string
+		f    func()
+	}{
+		{"rebin-0", func() { h.Rebin(1, 0) }},
+		{"edges-unaligned", func() { h.RebinEdges(nil, []float64{1, 2.5}) }},
+		{"edges-unsorted", func() { h.RebinEdges([]float64{2, 1}, nil) }},
+		{"slice-empty", func() { h.Slice(0, 4, 2.2, 2.8) }},
+		{"slice-invalid", func() { h.Slice(3, 2, 0, 3) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if e := recover(); e == nil {
+					t.Fatalf("expected a panic")
+				}
+			}()
+			tc.f()
+		})
+	}
+}
+
+func TestAddH2D(t *testing.T) {
+	h1 := NewH2D(2, 0, 2, 2, 0, 2)
+	h1.Fill(0.5, 0.5, 1)
+	h1.Fill(1.5, 0.5, 2)
+	h1.Fill(0.5, 1.5, 3)
+	h1.Fill(-1, 0.5, 1)
+
+	h2 := NewH2D(2, 0, 2, 2, 0, 2)
+	h2.Fill(0.5, 0.5, 2)
+	h2.Fill(1.5, 1.5, 4)
+	h2.Fill(3, 3, 1)
+
+	for _, tc := range []struct {
+		name  string
+		h     *H2D
+		sumw  []float64
+		sumw2 []float64
+	}{
+		{
+			name:  "add",
+			h:     AddH2D(h1, h2),
+			sumw:  []float64{3, 2, 3, 4},
+			sumw2: []float64{5, 4, 9, 16},
+		},
+		{
+			name:  "sub",
+			h:     SubH2D(h1, h2),
+			sumw:  []float64{-1, 2, 3, -4},
+			sumw2: []float64{5, 4, 9, 16},
+		},
+		{
+			name:  "add-scaled",
+			h:     AddScaledH2D(h1, 2, h2),
+			sumw:  []float64{5, 2, 3, 8},
+			sumw2: []float64{17, 4, 9, 64},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for i, bin := range tc.h.Binning.Bins {
+				if got, want := bin.SumW(), tc.sumw[i]; got != want {
+					t.Fatalf("bin[%d]: invalid sumw: got=%v, want=%v", i, got, want)
+				}
+				if got, want := bin.SumW2(), tc.sumw2[i]; got != want {
+					t.Fatalf("bin[%d]: invalid sumw2: got=%v, want=%v", i, got, want)
+				}
+			}
+			if got, want := tc.h.Entries(), h1.Entries()+h2.Entries(); got != want {
+				t.Fatalf("invalid entries: got=%d, want=%d", got, want)
+			}
+		})
+	}
+
+	if got, want := h1.SumW(), 7.0; got != want {
+		t.Fatalf("h1 was modified: got=%v, want=%v", got, want)
+	}
+
+	h3 := h1.Clone()
+	h3.Scale(2)
+	if got, want := h3.SumW(), 14.0; got != want {
+		t.Fatalf("invalid scaled sumw: got=%v, want=%v", got, want)
+	}
+	if got, want := h3.Binning.Bins[2].SumW2(), 36.0; got != want {
+		t.Fatalf("invalid scaled sumw2: got=%v, want=%v", got, want)
+	}
+
+	func() {
+		defer func() {
+			if e := recover(); e == nil {
+				t.Fatalf("expected a panic")
+			}
+		}()
+		AddH2D(h1, NewH2D(2, 0, 2, 3, 0, 2))
+	}()
+}
+
+func TestDivideH2D(t *testing.T) {
+	num := NewH2D(2, 0, 2, 2, 0, 2)
+	num.Fill(0.5, 0.5, 2)
+	num.Fill(1.5, 0.5, 3)
+	num.Fill(1.5, 1.5, 1)
+
+	den := NewH2D(2, 0, 2, 2, 0, 2)
+	den.Fill(0.5, 0.5, 4)
+	den.Fill(1.5, 0.5, 3)
+	den.Fill(0.5, 1.5, 1)
+
+	h, err := DivideH2D(num, den)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []float64{0.5, 1, 0, math.NaN()}
+	for i, bin := range h.Binning.Bins {
+		got := bin.SumW()
+		if math.IsNaN(want[i]) {
+			if !math.IsNaN(got) {
+				t.Fatalf("bin[%d]: invalid ratio: got=%v, want=NaN", i, got)
+			}
+			continue
+		}
+		if got != want[i] {
+			t.Fatalf("bin[%d]: invalid ratio: got=%v, want=%v", i, got, want[i])
+		}
+	}
+	if got, want := h.Binning.Bins[0].SumW2(), 0.5*0.5*2; math.Abs(got-want) > 1e-12 {
+		t.Fatalf("invalid error: got=%v, want=%v", got, want)
+	}
+
+	h, err = DivideH2D(num, den, DivReplaceNaNs(-1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h.Binning.Bins[3].SumW(), -1.0; got != want {
+		t.Fatalf("invalid replaced NaN: got=%v, want=%v", got, want)
+	}
+
+	h, err = DivideH2D(num, den, DivIgnoreNaNs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h.Binning.Bins[3].Entries(), int64(0); got != want {
+		t.Fatalf("invalid ignored NaN: got=%v, want=%v", got, want)
+	}
+
+	_, err = DivideH2D(num, NewH2D(2, 0, 3, 2, 0, 2))
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestH2DProjections(t *testing.T) {
+	h := NewH2DFromEdges([]float64{0, 1, 3}, []float64{0, 2, 3, 4})
+	h.Fill(0.5, 0.5, 1)
+	h.Fill(2.0, 0.5, 2)
+	h.Fill(0.5, 2.5, 3)
+	h.Fill(2.0, 3.5, 4)
+	h.Fill(-1, 1, 5)
+	h.Fill(5, 1, 6)
+	h.Fill(1, -1, 7)
+	h.Fill(1, 5, 8)
+
+	px := h.ProjectionX()
+	if got, want := len(px.Binning.Bins), 2; got != want {
+		t.Fatalf("px: invalid number of bins: got=%d, want=%d", got, want)
+	}
+	for i, want := range []float64{4, 6} {
+		if got := px.Binning.Bins[i].SumW(); got != want {
+			t.Fatalf("px: bin[%d]: invalid sumw: got=%v, want=%v", i, got, want)
+		}
+	}
+	if got, want := px.Binning.Bins[1].XMax(), 3.0; got != want {
+		t.Fatalf("px: invalid xmax: got=%v, want=%v", got, want)
+	}
+	if got, want := px.Binning.Outflows[0].SumW(), 5.0; got != want {
+		t.Fatalf("px: invalid underflow: got=%v, want=%v", got, want)
+	}
+	if got, want := px.Binning.Outflows[1].SumW(), 6.0; got != want {
+		t.Fatalf("px: invalid overflow: got=%v, want=%v", got, want)
+	}
+	if got, want := px.SumW(), 21.0; got != want {
+		t.Fatalf("px: invalid sumw: got=%v, want=%v", got, want)
+	}
+
+	py := h.ProjectionY()
+	for i, want := range []float64{3, 3, 4} {
+		if got := py.Binning.Bins[i].SumW(); got != want {
+			t.Fatalf("py: bin[%d]: invalid sumw: got=%v, want=%v", i, got, want)
+		}
+	}
+	if got, want := py.Binning.Outflows[0].SumW(), 7.0; got != want {
+		t.Fatalf("py: invalid underflow: got=%v, want=%v", got, want)
+	}
+	if got, want := py.Binning.Outflows[1].SumW(), 8.0; got != want {
+		t.Fatalf("py: invalid overflow: got=%v, want=%v", got, want)
+	}
+
+	prx := h.ProfileX()
+	bins := prx.Binning().Bins()
+	if got, want := len(bins), 2; got != want {
+		t.Fatalf("prx: invalid number of bins: got=%d, want=%d", got, want)
+	}
+	for i, want := range []float64{(0.5*1 + 2.5*3) / 4, (0.5*2 + 3.5*4) / 6} {
+		if got := bins[i].YMean(); math.Abs(got-want) > 1e-12 {
+			t.Fatalf("prx: bin[%d]: invalid y-mean: got=%v, want=%v", i, got, want)
+		}
+	}
+	if got, want := prx.SumW(), 21.0; got != want {
+		t.Fatalf("prx: invalid sumw: got=%v, want=%v", got, want)
+	}
+
+	pry := h.ProfileY()
+	bins = pry.Binning().Bins()
+	for i, want := range []float64{(0.5*1 + 2*2) / 3, 0.5, 2} {
+		if got := bins[i].YMean(); math.Abs(got-want) > 1e-12 {
+			t.Fatalf("pry: bin[%d]: invalid x-mean: got=%v, want=%v", i, got, want)
+		}
+	}
+	if got, want := bins[0].XMean(), 0.5; math.Abs(got-want) > 1e-12 {
+		t.Fatalf("pry: invalid y-mean: got=%v, want=%v", got, want)
+	}
+
+	// variable-size bins lookup.
+	pry.Fill(2.5, 1, 1)
+	if got, want := bins[1].Entries(), int64(2); got != want {
+		t.Fatalf("pry: invalid entries: got=%d, want=%d", got, want)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 )
 
@@ -26,6 +27,18 @@ func NewP1D(n int, xmin, xmax float64) *P1D {
 	}
 }
 
+// NewP1DFromEdges returns a 1-dim profile histogram given a slice of edges.
+// The number of bins is thus len(edges)-1.
+// It panics if the length of edges is <= 1.
+// It panics if the edges are not sorted.
+// It panics if there are duplicate edge values.
+func NewP1DFromEdges(edges []float64) *P1D {
+	return &P1D{
+		bng: newBinningP1DFromEdges(edges),
+		ann: make(Annotation),
+	}
+}
+
 /*
 // FIXME(sbinet): need support of variable-size bins
 //
@@ -146,6 +159,66 @@ func (p *P1D) Scale(factor float64) {
 	p.bng.scaleW(factor)
 }
 
+// Rebin returns a new profile histogram where each group of n consecutive
+// bins of this profile histogram has been merged into a single bin.
+// If the number of bins is not a multiple of n, the last bin of the new
+// profile histogram merges the remaining bins.
+// Rebin panics if n is not strictly positive.
+func (p *P1D) Rebin(n int) *P1D {
+	return p.merge(rebinGroups(len(p.bng.bins), n))
+}
+
+// RebinEdges returns a new profile histogram with the provided bin edges.
+// The new edges must be a sorted subset of the edges of this profile
+// histogram.
+// Bins of this profile histogram below (resp. above) the first (resp. last)
+// new edge are merged into the underflow (resp. overflow) bin.
+// RebinEdges panics if an edge is not aligned with a bin edge of this
+// profile histogram.
+func (p *P1D) RebinEdges(edges []float64) *P1D {
+	return p.merge(edgeGroups(rangesOf(p.bng.bins), edges, xAxisErrors))
+}
+
+// Slice returns a new profile histogram with the bins of this profile
+// histogram which are fully contained in the [xlo, xhi) range.
+// Bins of this profile histogram below (resp. above) that range are merged
+// into the underflow (resp. overflow) bin of the new profile histogram.
+// Slice panics if no bin is contained in the [xlo, xhi) range.
+func (p *P1D) Slice(xlo, xhi float64) *P1D {
+	return p.merge(sliceGroups(rangesOf(p.bng.bins), xlo, xhi, xAxisErrors))
+}
+
+// merge returns a new profile histogram whose bins are the union of the
+// bins of p in each of the [beg, end) bin index ranges of grps.
+// grps must be sorted and must not overlap.
+// Bins of p before the first group (resp. after the last group) are
+// merged into the underflow (resp. overflow) bin.
+func (p *P1D) merge(grps [][2]int) *P1D {
+	src := p.bng.bins
+
+	o := NewP1DFromEdges(rangeEdges(groupRanges(rangesOf(src), grps)))
+	o.ann = p.ann.clone()
+	o.bng.dist = p.bng.dist.clone()
+	o.bng.outflows[0] = p.bng.outflows[0].clone()
+	o.bng.outflows[1] = p.bng.outflows[1].clone()
+
+	for i, grp := range grps {
+		dst := &o.bng.bins[i]
+		for _, bin := range src[grp[0]:grp[1]] {
+			dst.dist.addScaled(1, 1, bin.dist)
+		}
+	}
+
+	for _, bin := range src[:grps[0][0]] {
+		o.bng.outflows[0].addScaled(1, 1, bin.dist)
+	}
+	for _, bin := range src[grps[len(grps)-1][1]:] {
+		o.bng.outflows[1].addScaled(1, 1, bin.dist)
+	}
+
+	return o
+}
+
 // check various interfaces
 var _ Object = (*P1D)(nil)
 var _ Histogram = (*P1D)(nil)
@@ -590,6 +663,47 @@ func newBinningP1D(n int, xmin, xmax float64) binningP1D {
 	return bng
 }
 
+// newBinningP1DFromEdges returns a 1-dim binning with variable-size bins.
+func newBinningP1DFromEdges(edges []float64) binningP1D {
+	if len(edges) <= 1 {
+		panic(errShortXAxis)
+	}
+	if !sort.IsSorted(sort.Float64Slice(edges)) {
+		panic(errNotSortedXAxis)
+	}
+	n := len(edges) - 1
+	bng := binningP1D{
+		bins:   make([]BinP1D, n),
+		xrange: Range{Min: edges[0], Max: edges[n]},
+	}
+	for i := range bng.bins {
+		bin := &bng.bins[i]
+		xmin := edges[i]
+		xmax := edges[i+1]
+		if xmin == xmax {
+			panic(errDupEdgesXAxis)
+		}
+		bin.xrange.Min = xmin
+		bin.xrange.Max = xmax
+	}
+	if isUniform(edges) {
+		bng.xstep = float64(n) / bng.xrange.Width()
+	}
+
+	return bng
+}
+
+// isUniform returns whether the provided edges define bins of equal width.
+func isUniform(edges []float64) bool {
+	w := edges[1] - edges[0]
+	for i := 1; i < len(edges)-1; i++ {
+		if !fuzzyEq(edges[i+1]-edges[i], w) {
+			return false
+		}
+	}
+	return true
+}
+
 func (bng *binningP1D) entries() int64 {
 	return bng.dist.Entries()
 }
@@ -622,6 +736,12 @@ func (bng *binningP1D) fill(x, y, w float64) {
 func (bng *binningP1D) coordToIndex(x float64) int {
 	switch {
 	default:
+		if bng.xstep == 0 {
+			// variable-size bins.
+			return sort.Search(len(bng.bins), func(i int) bool {
+				return x < bng.bins[i].xrange.Max
+			})
+		}
 		i := int((x - bng.xrange.Min) * bng.xstep)
 		return i
 	case x < bng.xrange.Min:
@@ -742,3 +862,28 @@ func (b *BinP1D) XStdErr() float64 {
 func (b *BinP1D) XRMS() float64 {
 	return b.dist.xRMS()
 }
+
+// YMean returns the mean Y.
+func (b *BinP1D) YMean() float64 {
+	return b.dist.yMean()
+}
+
+// YVariance returns the variance in Y.
+func (b *BinP1D) YVariance() float64 {
+	return b.dist.yVariance()
+}
+
+// YStdDev returns the standard deviation in Y.
+func (b *BinP1D) YStdDev() float64 {
+	return b.dist.yStdDev()
+}
+
+// YStdErr returns the standard error in Y.
+func (b *BinP1D) YStdErr() float64 {
+	return b.dist.yStdErr()
+}
+
+// YRMS returns the RMS in Y.
+func (b *BinP1D) YRMS() float64 {
+	return b.dist.yRMS()
+}
@@ -0,0 +1,182 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hbook
+
+import (
+	"fmt"
+	"sort"
+)
+
+// axisErrors holds the errors reported when rebinning an axis.
+type axisErrors struct {
+	invalid   error
+	short     error
+	notSorted error
+	dupEdges  error
+}
+
+var (
+	xAxisErrors = axisErrors{
+		invalid:   errInvalidXAxis,
+		short:     errShortXAxis,
+		notSorted: errNotSortedXAxis,
+		dupEdges:  errDupEdgesXAxis,
+	}
+	yAxisErrors = axisErrors{
+		invalid:   errInvalidYAxis,
+		short:     errShortYAxis,
+		notSorted: errNotSortedYAxis,
+		dupEdges:  errDupEdgesYAxis,
+	}
+)
+
+// rebinGroups returns the [beg, end) bin index ranges merging each group
+// of n consecutive bins out of nbins bins.
+// If nbins is not a multiple of n, the last group holds the remaining bins.
+func rebinGroups(nbins, n int) [][2]int {
+	if n <= 0 {
+		panic(fmt.Errorf("hbook: invalid rebinning factor %d", n))
+	}
+
+	grps := make([][2]int, 0, (nbins+n-1)/n)
+	for i := 0; i < nbins; i += n {
+		j := i + n
+		if j > nbins {
+			j = nbins
+		}
+		grps = append(grps, [2]int{i, j})
+	}
+	return grps
+}
+
+// edgeGroups returns the [beg, end) bin index ranges of the bins (with the
+// provided ranges) delimited by each pair of consecutive edges.
+// edgeGroups panics if an edge is not aligned with a bin edge.
+func edgeGroups(bins []Range, edges []float64, errs axisErrors) [][2]int {
+	if len(edges) <= 1 {
+		panic(errs.short)
+	}
+	if !sort.IsSorted(sort.Float64Slice(edges)) {
+		panic(errs.notSorted)
+	}
+
+	idx := make([]int, len(edges))
+	for i, edge := range edges {
+		j := edgeIndex(bins, edge)
+		if j < 0 {
+			panic(fmt.Errorf("hbook: edge %v is not aligned with a bin edge", edge))
+		}
+		if i > 0 && j == idx[i-1] {
+			panic(errs.dupEdges)
+		}
+		idx[i] = j
+	}
+
+	grps := make([][2]int, len(edges)-1)
+	for i := range grps {
+		grps[i] = [2]int{idx[i], idx[i+1]}
+	}
+	return grps
+}
+
+// sliceGroups returns the single-bin index ranges of the bins (with the
+// provided ranges) fully contained in the [lo, hi) range.
+// sliceGroups panics if no bin is contained in that range.
+func sliceGroups(bins []Range, lo, hi float64, errs axisErrors) [][2]int {
+	if lo >= hi {
+		panic(errs.invalid)
+	}
+
+	var (
+		beg = len(bins)
+		end = 0
+	)
+	for i, bin := range bins {
+		if bin.Min < lo && !fuzzyEq(bin.Min, lo) {
+			continue
+		}
+		if bin.Max > hi && !fuzzyEq(bin.Max, hi) {
+			continue
+		}
+		if i < beg {
+			beg = i
+		}
+		end = i + 1
+	}
+	if beg >= end {
+		panic(fmt.Errorf("hbook: no bin within slice [%v, %v)", lo, hi))
+	}
+
+	grps := make([][2]int, end-beg)
+	for i := range grps {
+		grps[i] = [2]int{beg + i, beg + i + 1}
+	}
+	return grps
+}
+
+// edgeIndex returns the index of the bin whose lower edge is x,
+// len(bins) if x is the upper edge of the last bin or -1 if x is not
+// a bin edge.
+func edgeIndex(bins []Range, x float64) int {
+	for i := range bins {
+		if fuzzyEq(bins[i].Min, x) {
+			return i
+		}
+	}
+	if n := len(bins); fuzzyEq(bins[n-1].Max, x) {
+		return n
+	}
+	return -1
+}
+
+// groupRanges returns the ranges spanned by each [beg, end) bin index range.
+func groupRanges(bins []Range, grps [][2]int) []Range {
+	o := make([]Range, len(grps))
+	for i, grp := range grps {
+		o[i] = Range{Min: bins[grp[0]].Min, Max: bins[grp[1]-1].Max}
+	}
+	return o
+}
+
+// rangeEdges returns the edges of the provided contiguous ranges.
+func rangeEdges(rngs []Range) []float64 {
+	o := make([]float64, len(rngs)+1)
+	for i, rng := range rngs {
+		o[i] = rng.Min
+	}
+	o[len(rngs)] = rngs[len(rngs)-1].Max
+	return o
+}
+
+// groupIndices returns, for each of the nbins bins, the index of the
+// group holding that bin, UnderflowBin1D if the bin is before the first
+// group or OverflowBin1D if it is after the last group.
+// grps must be sorted and must not overlap.
+func groupIndices(nbins int, grps [][2]int) []int {
+	o := make([]int, nbins)
+	for i := range o {
+		switch {
+		case i < grps[0][0]:
+			o[i] = UnderflowBin1D
+		default:
+			o[i] = OverflowBin1D
+		}
+	}
+	for i, grp := range grps {
+		for j := grp[0]; j < grp[1]; j++ {
+			o[j] = i
+		}
+	}
+	return o
+}
+
+// rangesOf returns the ranges of the provided bins.
+func rangesOf(bins []BinP1D) []Range {
+	o := make([]Range, len(bins))
+	for i, bin := range bins {
+		o[i] = bin.xrange
+	}
+	return o
+}
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package rootcnv provides tools to convert ROOT histograms and graphs to go-hep/hbook ones.
+// Package rootcnv provides tools to convert ROOT histograms, graphs and efficiencies to go-hep/hbook ones.
 package rootcnv
 
 import (
@@ -66,3 +66,23 @@ func FromH2D(h2 *hbook.H2D) *rhist.H2D {
 func FromS2D(s2 *hbook.S2D) rhist.GraphErrors {
 	return rhist.NewGraphAsymmErrorsFrom(s2)
 }
+
+// Efficiency1D creates a new 1-dim efficiency from a TEfficiency.
+func Efficiency1D(eff *rhist.Efficiency) (*hbook.Efficiency1D, error) {
+	return eff.AsEfficiency1D()
+}
+
+// Efficiency2D creates a new 2-dim efficiency from a TEfficiency.
+func Efficiency2D(eff *rhist.Efficiency) (*hbook.Efficiency2D, error) {
+	return eff.AsEfficiency2D()
+}
+
+// FromEfficiency1D creates a new ROOT TEfficiency from a 1-dim hbook efficiency.
+func FromEfficiency1D(eff *hbook.Efficiency1D) *rhist.Efficiency {
+	return rhist.NewEfficiency1DFrom(eff)
+}
+
+// FromEfficiency2D creates a new ROOT TEfficiency from a 2-dim hbook efficiency.
+func FromEfficiency2D(eff *hbook.Efficiency2D) *rhist.Efficiency {
+	return rhist.NewEfficiency2DFrom(eff)
+}
@@ -7,7 +7,10 @@ package rootcnv_test
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -586,3 +589,127 @@ func TestFromS2D(t *testing.T) {
 		)
 	}
 }
+
+func TestEfficiencyRoundTrip(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "hbook-rootcnv-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	fname := filepath.Join(tmp, "eff.root")
+
+	rnd := rand.New(rand.NewSource(1234))
+	e1 := hbook.NewEfficiency1D(10, 0, 10)
+	e1.Ann["name"] = "eff1"
+	e1.Ann["title"] = "my title"
+	e1.Config.Stat = hbook.EffWilson
+	e1.Config.CL = 0.95
+	e2 := hbook.NewEfficiency2D(4, 0, 4, 5, 0, 5)
+	e2.Ann["name"] = "eff2"
+	e2.Config.Stat = hbook.EffBayesian
+	e2.Config.Alpha = 0.5
+	e2.Config.Beta = 0.5
+	for i := 0; i < 1000; i++ {
+		var (
+			x    = 10 * rnd.Float64()
+			y    = 5 * rnd.Float64()
+			pass = rnd.Float64() < x/10
+		)
+		e1.Fill(x, pass, 1)
+		e2.Fill(x/2.5, y, pass, 1)
+	}
+
+	{
+		f, err := groot.Create(fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		err = f.Put("eff1", rootcnv.FromEfficiency1D(e1))
+		if err != nil {
+			t.Fatalf("could not write 1-dim efficiency: %+v", err)
+		}
+		err = f.Put("eff2", rootcnv.FromEfficiency2D(e2))
+		if err != nil {
+			t.Fatalf("could not write 2-dim efficiency: %+v", err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatalf("could not close file: %+v", err)
+		}
+	}
+
+	f, err := groot.Open(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	{
+		obj, err := f.Get("eff1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		reff := obj.(*rhist.Efficiency)
+		if got, want := reff.Passed().(rhist.H1).Name(), "eff1_passed"; got != want {
+			t.Fatalf("invalid passed histogram name: got=%q, want=%q", got, want)
+		}
+
+		got, err := rootcnv.Efficiency1D(reff)
+		if err != nil {
+			t.Fatalf("could not convert 1-dim efficiency: %+v", err)
+		}
+		if got, want := got.Config, e1.Config; got != want {
+			t.Fatalf("invalid config: got=%#v, want=%#v", got, want)
+		}
+		if got, want := got.Name(), "eff1"; got != want {
+			t.Fatalf("invalid name: got=%q, want=%q", got, want)
+		}
+
+		want, err := e1.S2D().MarshalYODA()
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := got.S2D().MarshalYODA()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(raw, want) {
+			t.Fatalf("invalid efficiency:\n%s", cmp.Diff(string(want), string(raw)))
+		}
+
+		_, err = rootcnv.Efficiency2D(reff)
+		if err == nil {
+			t.Fatalf("expected an error converting a 1-dim efficiency to 2-dim")
+		}
+	}
+
+	{
+		obj, err := f.Get("eff2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := rootcnv.Efficiency2D(obj.(*rhist.Efficiency))
+		if err != nil {
+			t.Fatalf("could not convert 2-dim efficiency: %+v", err)
+		}
+		if got, want := got.Config, e2.Config; got != want {
+			t.Fatalf("invalid config: got=%#v, want=%#v", got, want)
+		}
+		for ix := 0; ix < 4; ix++ {
+			for iy := 0; iy < 5; iy++ {
+				if got, want := got.Eff(ix, iy), e2.Eff(ix, iy); got != want {
+					t.Fatalf("bin(%d,%d): invalid efficiency: got=%v, want=%v", ix, iy, got, want)
+				}
+				glo, ghi := got.Interval(ix, iy)
+				wlo, whi := e2.Interval(ix, iy)
+				if glo != wlo || ghi != whi {
+					t.Fatalf("bin(%d,%d): invalid interval: got=[%v, %v], want=[%v, %v]", ix, iy, glo, ghi, wlo, whi)
+				}
+			}
+		}
+	}
+}
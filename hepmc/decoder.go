@@ -18,12 +18,20 @@ type rstream struct {
 	err    error
 }
 
-// Decoder decodes a hepmc Event from a stream
+// Decoder decodes a hepmc Event from a stream.
+//
+// The format of the stream (HepMC2 IO_GenEvent or HepMC3 Asciiv3) is
+// automatically detected from the header of the stream.
 type Decoder struct {
 	stream     chan rstream
+	err        error   // first error (or io.EOF) reported by the stream
+	unread     *tokens // line pushed back to the stream
 	seenEvtHdr bool
 	ftype      hepmcFileType
 
+	run     *RunInfo // current HepMC3 run information
+	runOpen bool     // whether the run information is being read
+
 	sigProcBc int // barcode of signal vertex
 	bp1       int // barcode of beam1
 	bp2       int // barcode of beam2
@@ -59,7 +67,19 @@ func (dec *Decoder) readlines(r *bufio.Reader) {
 }
 
 func (dec *Decoder) readline() (tokens, error) {
+	if dec.unread != nil {
+		tokens := *dec.unread
+		tokens.pos = 0
+		dec.unread = nil
+		return tokens, nil
+	}
+	if dec.err != nil {
+		return tokens{}, dec.err
+	}
 	state := <-dec.stream
+	if state.err != nil {
+		dec.err = state.err
+	}
 	return state.tokens, state.err
 }
 
@@ -76,6 +96,10 @@ func (dec *Decoder) Decode(evt *Event) error {
 		dec.seenEvtHdr = true
 	}
 
+	if dec.ftype == hepmcASCIIv3 {
+		return dec.decodeASCIIv3(evt)
+	}
+
 	dec.sigProcBc = 0
 	dec.bp1 = 0
 	dec.bp2 = 0
@@ -282,6 +306,10 @@ func (dec *Decoder) findFileType() error {
 		case startExtendedASCIIPdt:
 			dec.ftype = hepmcExtendedASCIIPdt
 			return nil
+
+		case startASCIIv3:
+			dec.ftype = hepmcASCIIv3
+			return nil
 		}
 	}
 }
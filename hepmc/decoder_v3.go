@@ -0,0 +1,757 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hepmc
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// v3event holds the content of a HepMC3 event, as read from the stream.
+// Particles and vertices are connected once the whole event has been read.
+type v3event struct {
+	parts []v3particle
+	verts []v3vertex
+	attrs []v3attr
+}
+
+type v3particle struct {
+	p      *Particle
+	parent int // id of the production vertex (<0) or of the mother particle (>0)
+}
+
+type v3vertex struct {
+	v  *Vertex
+	in []int // ids of the incoming particles
+}
+
+type v3attr struct {
+	id    int // id of the object holding the attribute (0 for the event)
+	name  string
+	value string
+}
+
+// decodeASCIIv3 decodes the next event of a HepMC3 Asciiv3 stream.
+func (dec *Decoder) decodeASCIIv3(evt *Event) error {
+	var (
+		ev   *v3event // current event, nil until its 'E' line has been read
+		body bool     // whether the particles and vertices of the event are being read
+	)
+
+	for {
+		tokens, err := dec.readline()
+		if err != nil {
+			if err == io.EOF && ev != nil {
+				return buildV3(evt, ev, dec.run)
+			}
+			return err
+		}
+
+		key := tokens.at(0)
+		switch {
+		case key == "":
+			continue
+		case strings.HasPrefix(key, "HepMC::"):
+			if ev != nil {
+				dec.unread = &tokens
+				return buildV3(evt, ev, dec.run)
+			}
+			if key == endASCIIv3 {
+				return io.EOF
+			}
+			continue
+		}
+
+		switch key {
+		case "E":
+			if ev != nil {
+				dec.unread = &tokens
+				return buildV3(evt, ev, dec.run)
+			}
+			dec.runOpen = false
+			ev = new(v3event)
+			err = dec.decodeEventV3(evt, tokens)
+
+		case "U":
+			if ev == nil {
+				return fmt.Errorf("hepmc.decode: units outside of an event (line=%q)", tokens)
+			}
+			err = dec.decodeUnits(evt, tokens)
+
+		case "W":
+			switch {
+			case ev == nil || body:
+				err = dec.decodeWeightNames(tokens)
+			default:
+				err = dec.decodeWeightsV3(evt, tokens)
+			}
+
+		case "T":
+			dec.decodeTool(tokens)
+
+		case "A":
+			switch {
+			case ev == nil || body:
+				err = dec.decodeRunAttribute(tokens)
+			default:
+				var attr v3attr
+				attr, err = dec.decodeAttribute(tokens)
+				ev.attrs = append(ev.attrs, attr)
+			}
+
+		case "P":
+			if ev == nil {
+				return fmt.Errorf("hepmc.decode: particle outside of an event (line=%q)", tokens)
+			}
+			body = true
+			var p v3particle
+			p, err = dec.decodeParticleV3(tokens)
+			ev.parts = append(ev.parts, p)
+
+		case "V":
+			if ev == nil {
+				return fmt.Errorf("hepmc.decode: vertex outside of an event (line=%q)", tokens)
+			}
+			body = true
+			var v v3vertex
+			v, err = dec.decodeVertexV3(tokens)
+			ev.verts = append(ev.verts, v)
+
+		default:
+			return fmt.Errorf("hepmc.decode: invalid Asciiv3 line (line=%q)", tokens)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// runInfo returns the run information being read, creating a new one when
+// a new run information block starts.
+func (dec *Decoder) runInfo() *RunInfo {
+	if !dec.runOpen {
+		dec.run = &RunInfo{}
+		dec.runOpen = true
+	}
+	return dec.run
+}
+
+func (dec *Decoder) decodeWeightNames(tokens tokens) error {
+	run := dec.runInfo()
+	line := tokens.String()
+	if len(line) < 2 {
+		run.WeightNames = nil
+		return nil
+	}
+	run.WeightNames = strings.Split(unescape(line[2:]), "\n")
+	return nil
+}
+
+func (dec *Decoder) decodeTool(tokens tokens) {
+	run := dec.runInfo()
+	var (
+		line = tokens.String()
+		tool Tool
+	)
+	if len(line) > 2 {
+		toks := strings.SplitN(unescape(line[2:]), "\n", 3)
+		tool.Name = toks[0]
+		if len(toks) > 1 {
+			tool.Version = toks[1]
+		}
+		if len(toks) > 2 {
+			tool.Description = toks[2]
+		}
+	}
+	run.Tools = append(run.Tools, tool)
+}
+
+func (dec *Decoder) decodeRunAttribute(tokens tokens) error {
+	if len(tokens.toks) < 2 {
+		return fmt.Errorf("hepmc.decode: invalid run attribute (line=%q)", tokens)
+	}
+	run := dec.runInfo()
+	if run.Attributes == nil {
+		run.Attributes = make(map[string]string)
+	}
+	run.Attributes[tokens.at(1)] = unescape(strings.Join(tokens.toks[2:], " "))
+	return nil
+}
+
+func (dec *Decoder) decodeAttribute(tokens tokens) (v3attr, error) {
+	var (
+		attr v3attr
+		err  error
+	)
+	if len(tokens.toks) < 3 {
+		return attr, fmt.Errorf("hepmc.decode: invalid attribute (line=%q)", tokens)
+	}
+	_ = tokens.next() // header 'A'
+	attr.id, err = tokens.int()
+	if err != nil {
+		return attr, fmt.Errorf("hepmc.decode: invalid attribute id (line=%q): %w", tokens, err)
+	}
+	attr.name = tokens.next()
+	attr.value = unescape(strings.Join(tokens.toks[3:], " "))
+	return attr, nil
+}
+
+func (dec *Decoder) decodeEventV3(evt *Event, tokens tokens) error {
+	_ = tokens.next() // header 'E'
+
+	var err error
+	evt.EventNumber, err = tokens.int()
+	if err != nil {
+		return err
+	}
+
+	nVtx, err := tokens.int()
+	if err != nil {
+		return err
+	}
+
+	nParts, err := tokens.int()
+	if err != nil {
+		return err
+	}
+
+	// the (optional) position of the event is not supported by
+	// the HepMC2 event model and is thus ignored.
+
+	evt.Weights = NewWeights()
+	evt.Vertices = make(map[int]*Vertex, nVtx)
+	evt.Particles = make(map[int]*Particle, nParts)
+	return nil
+}
+
+func (dec *Decoder) decodeWeightsV3(evt *Event, tokens tokens) error {
+	_ = tokens.next() // header 'W'
+	weights := make([]float64, 0, len(tokens.toks)-1)
+	for tokens.pos < len(tokens.toks) {
+		if tokens.toks[tokens.pos] == "" {
+			tokens.pos++
+			continue
+		}
+		w, err := tokens.float64()
+		if err != nil {
+			return err
+		}
+		weights = append(weights, w)
+	}
+	evt.Weights.Slice = weights
+	return nil
+}
+
+func (dec *Decoder) decodeParticleV3(tokens tokens) (v3particle, error) {
+	var (
+		err error
+		p   = &Particle{}
+		v   = v3particle{p: p}
+	)
+	_ = tokens.next() // header 'P'
+
+	p.Barcode, err = tokens.int()
+	if err != nil {
+		return v, err
+	}
+
+	v.parent, err = tokens.int()
+	if err != nil {
+		return v, err
+	}
+
+	p.PdgID, err = tokens.int64()
+	if err != nil {
+		return v, err
+	}
+
+	p.Momentum.P4.X, err = tokens.float64()
+	if err != nil {
+		return v, err
+	}
+
+	p.Momentum.P4.Y, err = tokens.float64()
+	if err != nil {
+		return v, err
+	}
+
+	p.Momentum.P4.Z, err = tokens.float64()
+	if err != nil {
+		return v, err
+	}
+
+	p.Momentum.P4.T, err = tokens.float64()
+	if err != nil {
+		return v, err
+	}
+
+	p.GeneratedMass, err = tokens.float64()
+	if err != nil {
+		return v, err
+	}
+
+	p.Status, err = tokens.int()
+	if err != nil {
+		return v, err
+	}
+
+	p.Flow = Flow{Particle: p, Icode: make(map[int]int)}
+	return v, nil
+}
+
+func (dec *Decoder) decodeVertexV3(tokens tokens) (v3vertex, error) {
+	var (
+		err error
+		vtx = &Vertex{}
+		v   = v3vertex{v: vtx}
+	)
+	_ = tokens.next() // header 'V'
+
+	vtx.Barcode, err = tokens.int()
+	if err != nil {
+		return v, err
+	}
+
+	tok := tokens.next()
+	if !strings.HasPrefix(tok, "[") {
+		vtx.ID, err = strconv.Atoi(tok)
+		if err != nil {
+			return v, err
+		}
+		tok = tokens.next()
+	}
+
+	if !strings.HasPrefix(tok, "[") || !strings.HasSuffix(tok, "]") {
+		return v, fmt.Errorf("hepmc.decode: invalid vertex incoming particles (line=%q)", tokens)
+	}
+	if tok = tok[1 : len(tok)-1]; tok != "" {
+		for _, id := range strings.Split(tok, ",") {
+			i, err := strconv.Atoi(id)
+			if err != nil {
+				return v, fmt.Errorf("hepmc.decode: invalid vertex incoming particle (line=%q): %w", tokens, err)
+			}
+			v.in = append(v.in, i)
+		}
+	}
+
+	switch tok := tokens.next(); tok {
+	case "":
+		// no position.
+	case "@":
+		vtx.Position.P4.X, err = tokens.float64()
+		if err != nil {
+			return v, err
+		}
+
+		vtx.Position.P4.Y, err = tokens.float64()
+		if err != nil {
+			return v, err
+		}
+
+		vtx.Position.P4.Z, err = tokens.float64()
+		if err != nil {
+			return v, err
+		}
+
+		vtx.Position.P4.T, err = tokens.float64()
+		if err != nil {
+			return v, err
+		}
+	default:
+		return v, fmt.Errorf("hepmc.decode: invalid vertex position (line=%q)", tokens)
+	}
+
+	vtx.Weights.Slice = make([]float64, 0)
+	return v, nil
+}
+
+// buildV3 connects the particles and vertices of the decoded event,
+// and fills the event with its attributes.
+func buildV3(evt *Event, ev *v3event, run *RunInfo) error {
+	evt.RunInfo = run
+	if run != nil {
+		for i, n := range run.WeightNames {
+			evt.Weights.Map[n] = i
+		}
+	}
+
+	for _, v := range ev.verts {
+		bc := v.v.Barcode
+		if _, dup := evt.Vertices[bc]; dup || bc >= 0 {
+			return fmt.Errorf("hepmc.decode: invalid vertex id %d in event %d", bc, evt.EventNumber)
+		}
+		evt.Vertices[bc] = v.v
+	}
+
+	for _, p := range ev.parts {
+		bc := p.p.Barcode
+		if _, dup := evt.Particles[bc]; dup || bc <= 0 {
+			return fmt.Errorf("hepmc.decode: invalid particle id %d in event %d", bc, evt.EventNumber)
+		}
+		evt.Particles[bc] = p.p
+	}
+
+	for _, v := range ev.verts {
+		for _, id := range v.in {
+			p, ok := evt.Particles[id]
+			if !ok {
+				return fmt.Errorf("hepmc.decode: could not find incoming particle %d of vertex %d", id, v.v.Barcode)
+			}
+			p.EndVertex = v.v
+			v.v.ParticlesIn = append(v.v.ParticlesIn, p)
+		}
+	}
+
+	var implicit []*Vertex
+	for _, p := range ev.parts {
+		var vtx *Vertex
+		switch {
+		case p.parent < 0:
+			v, ok := evt.Vertices[p.parent]
+			if !ok {
+				return fmt.Errorf("hepmc.decode: could not find production vertex %d of particle %d", p.parent, p.p.Barcode)
+			}
+			vtx = v
+		case p.parent > 0:
+			mother, ok := evt.Particles[p.parent]
+			if !ok {
+				return fmt.Errorf("hepmc.decode: could not find mother particle %d of particle %d", p.parent, p.p.Barcode)
+			}
+			if mother.EndVertex == nil {
+				v := &Vertex{
+					ParticlesIn: []*Particle{mother},
+					Weights:     Weights{Slice: make([]float64, 0)},
+				}
+				mother.EndVertex = v
+				implicit = append(implicit, v)
+			}
+			vtx = mother.EndVertex
+		default:
+			continue
+		}
+		p.p.ProdVertex = vtx
+		vtx.ParticlesOut = append(vtx.ParticlesOut, p.p)
+	}
+
+	// vertices that were not explicitly written out are given the first
+	// unused ids.
+	bc := -1
+	for _, v := range implicit {
+		for evt.Vertices[bc] != nil {
+			bc--
+		}
+		v.Barcode = bc
+		evt.Vertices[bc] = v
+	}
+
+	for _, v := range evt.Vertices {
+		v.Event = evt
+		sort.Sort(Particles(v.ParticlesIn))
+		sort.Sort(Particles(v.ParticlesOut))
+	}
+
+	var rndm map[int]int64
+	for _, attr := range ev.attrs {
+		var err error
+		switch {
+		case attr.id == 0:
+			err = setEventAttr(evt, attr, &rndm)
+		case attr.id > 0:
+			p, ok := evt.Particles[attr.id]
+			if !ok {
+				return fmt.Errorf("hepmc.decode: could not find particle %d of attribute %q", attr.id, attr.name)
+			}
+			err = setParticleAttr(p, attr)
+		default:
+			v, ok := evt.Vertices[attr.id]
+			if !ok {
+				return fmt.Errorf("hepmc.decode: could not find vertex %d of attribute %q", attr.id, attr.name)
+			}
+			err = setVertexAttr(v, attr)
+		}
+		if err != nil {
+			return fmt.Errorf("hepmc.decode: invalid attribute %q (id=%d, value=%q): %w", attr.name, attr.id, attr.value, err)
+		}
+	}
+
+	if len(rndm) > 0 {
+		n := 0
+		for i := range rndm {
+			if i+1 > n {
+				n = i + 1
+			}
+		}
+		evt.RandomStates = make([]int64, n)
+		for i, v := range rndm {
+			evt.RandomStates[i] = v
+		}
+	}
+
+	// beam particles are the incoming particles with status 4.
+	ids := make([]int, 0, len(evt.Particles))
+	for id, p := range evt.Particles {
+		if p.Status == 4 && p.ProdVertex == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	for i := 0; i < len(ids) && i < len(evt.Beams); i++ {
+		evt.Beams[i] = evt.Particles[ids[i]]
+	}
+
+	return nil
+}
+
+func setEventAttr(evt *Event, attr v3attr, rndm *map[int]int64) error {
+	var (
+		err  error
+		toks = newtokens(strings.Fields(attr.value))
+	)
+
+	switch attr.name {
+	case "signal_process_id":
+		evt.SignalProcessID, err = toks.int()
+	case "mpi":
+		evt.Mpi, err = toks.int()
+	case "event_scale":
+		evt.Scale, err = toks.float64()
+	case "alphaQCD":
+		evt.AlphaQCD, err = toks.float64()
+	case "alphaQED":
+		evt.AlphaQED, err = toks.float64()
+
+	case "signal_process_vertex":
+		var id int
+		id, err = toks.int()
+		if err != nil {
+			return err
+		}
+		vtx, ok := evt.Vertices[id]
+		if !ok {
+			return fmt.Errorf("could not find signal vertex %d", id)
+		}
+		evt.SignalVertex = vtx
+
+	case "GenCrossSection":
+		var x CrossSection
+		x.Value, err = toks.float64()
+		if err != nil {
+			return err
+		}
+		x.Error, err = toks.float64()
+		evt.CrossSection = &x
+
+	case "GenPdfInfo":
+		var pdf PdfInfo
+		err = decodePdfInfoV3(&pdf, &toks)
+		evt.PdfInfo = &pdf
+
+	case "GenHeavyIon":
+		if strings.HasPrefix(attr.value, "v") {
+			// versioned heavy-ion informations are not supported by
+			// the HepMC2 event model.
+			evt.setAttr(attr.name, attr.value)
+			break
+		}
+		var hi HeavyIon
+		err = decodeHeavyIonV3(&hi, &toks)
+		evt.HeavyIon = &hi
+
+	default:
+		if strings.HasPrefix(attr.name, "random_states") {
+			i, ierr := strconv.Atoi(strings.TrimPrefix(attr.name, "random_states"))
+			if ierr == nil && i >= 0 {
+				if *rndm == nil {
+					*rndm = make(map[int]int64)
+				}
+				(*rndm)[i], err = toks.int64()
+				break
+			}
+		}
+		evt.setAttr(attr.name, attr.value)
+	}
+	return err
+}
+
+func setParticleAttr(p *Particle, attr v3attr) error {
+	var (
+		err  error
+		toks = newtokens(strings.Fields(attr.value))
+	)
+
+	switch attr.name {
+	case "theta":
+		p.Polarization.Theta, err = toks.float64()
+	case "phi":
+		p.Polarization.Phi, err = toks.float64()
+	default:
+		if strings.HasPrefix(attr.name, "flow") {
+			i, ierr := strconv.Atoi(strings.TrimPrefix(attr.name, "flow"))
+			if ierr == nil {
+				p.Flow.Icode[i], err = toks.int()
+				break
+			}
+		}
+		if p.Attributes == nil {
+			p.Attributes = make(map[string]string)
+		}
+		p.Attributes[attr.name] = attr.value
+	}
+	return err
+}
+
+func setVertexAttr(v *Vertex, attr v3attr) error {
+	switch attr.name {
+	case "weights":
+		toks := newtokens(strings.Fields(attr.value))
+		v.Weights.Slice = make([]float64, len(toks.toks))
+		for i := range v.Weights.Slice {
+			var err error
+			v.Weights.Slice[i], err = toks.float64()
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		if v.Attributes == nil {
+			v.Attributes = make(map[string]string)
+		}
+		v.Attributes[attr.name] = attr.value
+	}
+	return nil
+}
+
+func decodePdfInfoV3(pdf *PdfInfo, tokens *tokens) error {
+	var err error
+
+	pdf.ID1, err = tokens.int()
+	if err != nil {
+		return err
+	}
+
+	pdf.ID2, err = tokens.int()
+	if err != nil {
+		return err
+	}
+
+	pdf.X1, err = tokens.float64()
+	if err != nil {
+		return err
+	}
+
+	pdf.X2, err = tokens.float64()
+	if err != nil {
+		return err
+	}
+
+	pdf.ScalePDF, err = tokens.float64()
+	if err != nil {
+		return err
+	}
+
+	pdf.Pdf1, err = tokens.float64()
+	if err != nil {
+		return err
+	}
+
+	pdf.Pdf2, err = tokens.float64()
+	if err != nil {
+		return err
+	}
+
+	pdf.LHAPdf1, err = tokens.int()
+	if err != nil {
+		return err
+	}
+
+	pdf.LHAPdf2, err = tokens.int()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func decodeHeavyIonV3(hi *HeavyIon, tokens *tokens) error {
+	var err error
+
+	hi.NCollHard, err = tokens.int()
+	if err != nil {
+		return err
+	}
+
+	hi.NPartProj, err = tokens.int()
+	if err != nil {
+		return err
+	}
+
+	hi.NPartTarg, err = tokens.int()
+	if err != nil {
+		return err
+	}
+
+	hi.NColl, err = tokens.int()
+	if err != nil {
+		return err
+	}
+
+	hi.SpectatorNeutrons, err = tokens.int()
+	if err != nil {
+		return err
+	}
+
+	hi.SpectatorProtons, err = tokens.int()
+	if err != nil {
+		return err
+	}
+
+	hi.NNwColl, err = tokens.int()
+	if err != nil {
+		return err
+	}
+
+	hi.NwNColl, err = tokens.int()
+	if err != nil {
+		return err
+	}
+
+	hi.NwNwColl, err = tokens.int()
+	if err != nil {
+		return err
+	}
+
+	hi.ImpactParameter, err = tokens.float32()
+	if err != nil {
+		return err
+	}
+
+	hi.EventPlaneAngle, err = tokens.float32()
+	if err != nil {
+		return err
+	}
+
+	hi.Eccentricity, err = tokens.float32()
+	if err != nil {
+		return err
+	}
+
+	hi.SigmaInelNN, err = tokens.float32()
+	if err != nil {
+		return err
+	}
+
+	// the centrality is not supported by the HepMC2 event model.
+	return nil
+}
+
+func (evt *Event) setAttr(name, value string) {
+	if evt.Attributes == nil {
+		evt.Attributes = make(map[string]string)
+	}
+	evt.Attributes[name] = value
+}
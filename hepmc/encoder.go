@@ -14,21 +14,35 @@ import (
 type Encoder struct {
 	w          io.Writer
 	seenEvtHdr bool
+
+	v3  bool     // whether to write HepMC3 Asciiv3 events
+	run *RunInfo // last written HepMC3 run information
 }
 
-// NewEncoder returns a new hepmc Encoder that writes into the io.Writer.
+// NewEncoder returns a new hepmc Encoder that writes HepMC2 IO_GenEvent
+// events into the io.Writer.
 func NewEncoder(w io.Writer) *Encoder {
 	return &Encoder{w: w}
 }
 
+// NewEncoderV3 returns a new hepmc Encoder that writes HepMC3 Asciiv3
+// events into the io.Writer.
+func NewEncoderV3(w io.Writer) *Encoder {
+	return &Encoder{w: w, v3: true}
+}
+
 // Close closes the encoder and adds a footer to the stream.
 func (enc *Encoder) Close() error {
 	var err error
 	if enc.seenEvtHdr {
+		end := endGenEvent
+		if enc.v3 {
+			end = endASCIIv3
+		}
 		_, err = fmt.Fprintf(
 			enc.w,
 			"%s\n",
-			endGenEvent,
+			end,
 		)
 		if err != nil {
 			return err
@@ -39,6 +53,10 @@ func (enc *Encoder) Close() error {
 
 // Encode writes evt into the stream.
 func (enc *Encoder) Encode(evt *Event) error {
+	if enc.v3 {
+		return enc.encodeASCIIv3(evt)
+	}
+
 	var err error
 
 	if !enc.seenEvtHdr {
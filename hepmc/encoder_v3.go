@@ -0,0 +1,373 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hepmc
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-hep.org/x/hep/fmom"
+)
+
+// versionV3 is the version of the HepMC3 Asciiv3 format written out by
+// the Encoder.
+const versionV3 = "3.02.02"
+
+// encodeASCIIv3 writes evt into the stream, using the HepMC3 Asciiv3 format.
+//
+// Particles and vertices are identified by their position in the event:
+// particles, sorted by increasing barcodes, are given the ids 1, 2, ...
+// and vertices, sorted by decreasing barcodes, the ids -1, -2, ...
+// HepMC2 informations that have no HepMC3 counterpart are written out as
+// attributes, following the HepMC3 conventions.
+func (enc *Encoder) encodeASCIIv3(evt *Event) error {
+	var err error
+
+	if !enc.seenEvtHdr {
+		_, err = fmt.Fprintf(
+			enc.w,
+			"HepMC::Version %s\n%s\n",
+			versionV3, startASCIIv3,
+		)
+		if err != nil {
+			return err
+		}
+		enc.seenEvtHdr = true
+	}
+
+	run := runInfoOf(evt)
+	if run != nil && !reflect.DeepEqual(run, enc.run) {
+		err = enc.encodeRunInfo(run)
+		if err != nil {
+			return err
+		}
+		enc.run = run
+	}
+
+	parts, verts, pids, vids := v3ids(evt)
+
+	_, err = fmt.Fprintf(
+		enc.w,
+		"E %d %d %d\nU %s %s\n",
+		evt.EventNumber, len(verts), len(parts),
+		evt.MomentumUnit, evt.LengthUnit,
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(evt.Weights.Slice) > 0 {
+		_, err = fmt.Fprintf(enc.w, "W")
+		if err != nil {
+			return err
+		}
+		for _, w := range evt.Weights.Slice {
+			_, err = fmt.Fprintf(enc.w, " %1.16e", w)
+			if err != nil {
+				return err
+			}
+		}
+		_, err = fmt.Fprintf(enc.w, "\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	attrs, err := attributesOf(evt, pids, vids)
+	if err != nil {
+		return err
+	}
+	for _, attr := range attrs {
+		_, err = fmt.Fprintf(enc.w, "A %d %s %s\n", attr.id, attr.name, escape(attr.value))
+		if err != nil {
+			return err
+		}
+	}
+
+	written := make(map[*Vertex]bool, len(verts))
+	for _, p := range parts {
+		parent := 0
+		if vtx := p.ProdVertex; vtx != nil {
+			id, ok := vids[vtx]
+			if !ok {
+				return fmt.Errorf("hepmc.encode: production vertex of particle %d not in event", p.Barcode)
+			}
+			if !written[vtx] {
+				err = enc.encodeVertexV3(vtx, id, pids)
+				if err != nil {
+					return err
+				}
+				written[vtx] = true
+			}
+			parent = id
+		}
+
+		_, err = fmt.Fprintf(
+			enc.w,
+			"P %d %d %d %1.16e %1.16e %1.16e %1.16e %1.16e %d\n",
+			pids[p], parent, p.PdgID,
+			p.Momentum.Px(), p.Momentum.Py(), p.Momentum.Pz(), p.Momentum.E(),
+			p.GeneratedMass,
+			p.Status,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// write out the vertices without outgoing particles.
+	for _, vtx := range verts {
+		if written[vtx] {
+			continue
+		}
+		err = enc.encodeVertexV3(vtx, vids[vtx], pids)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (enc *Encoder) encodeRunInfo(run *RunInfo) error {
+	var err error
+	if len(run.WeightNames) > 0 {
+		_, err = fmt.Fprintf(enc.w, "W %s\n", escape(strings.Join(run.WeightNames, "\n")))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, tool := range run.Tools {
+		_, err = fmt.Fprintf(
+			enc.w, "T %s\n",
+			escape(tool.Name+"\n"+tool.Version+"\n"+tool.Description),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, 0, len(run.Attributes))
+	for name := range run.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, err = fmt.Fprintf(enc.w, "A %s %s\n", name, escape(run.Attributes[name]))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (enc *Encoder) encodeVertexV3(vtx *Vertex, id int, pids map[*Particle]int) error {
+	in := make([]string, 0, len(vtx.ParticlesIn))
+	for _, p := range vtx.ParticlesIn {
+		pid, ok := pids[p]
+		if !ok {
+			return fmt.Errorf("hepmc.encode: incoming particle %d of vertex %d not in event", p.Barcode, vtx.Barcode)
+		}
+		in = append(in, strconv.Itoa(pid))
+	}
+
+	_, err := fmt.Fprintf(enc.w, "V %d %d [%s]", id, vtx.ID, strings.Join(in, ","))
+	if err != nil {
+		return err
+	}
+
+	var zero fmom.PxPyPzE
+	if vtx.Position != zero {
+		_, err = fmt.Fprintf(
+			enc.w, " @ %1.16e %1.16e %1.16e %1.16e",
+			vtx.Position.X(), vtx.Position.Y(), vtx.Position.Z(), vtx.Position.T(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(enc.w, "\n")
+	return err
+}
+
+// v3ids returns the particles and vertices of the provided event, in the
+// order they are written out, together with their HepMC3 ids.
+func v3ids(evt *Event) ([]*Particle, []*Vertex, map[*Particle]int, map[*Vertex]int) {
+	var (
+		parts = make([]*Particle, 0, len(evt.Particles))
+		verts = make([]*Vertex, 0, len(evt.Vertices))
+		pids  = make(map[*Particle]int, len(evt.Particles))
+		vids  = make(map[*Vertex]int, len(evt.Vertices))
+	)
+	for _, p := range evt.Particles {
+		parts = append(parts, p)
+	}
+	sort.Sort(Particles(parts))
+	for i, p := range parts {
+		pids[p] = i + 1
+	}
+
+	for _, v := range evt.Vertices {
+		verts = append(verts, v)
+	}
+	sort.Sort(sort.Reverse(Vertices(verts)))
+	for i, v := range verts {
+		vids[v] = -(i + 1)
+	}
+
+	return parts, verts, pids, vids
+}
+
+// runInfoOf returns the run information of the provided event.
+// If the event has no run information, the run information is built from
+// the names of the event weights.
+func runInfoOf(evt *Event) *RunInfo {
+	if evt.RunInfo != nil {
+		return evt.RunInfo
+	}
+
+	if len(evt.Weights.Map) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(evt.Weights.Slice))
+	for name, i := range evt.Weights.Map {
+		if i >= len(names) {
+			names = append(names, make([]string, i+1-len(names))...)
+		}
+		names[i] = name
+	}
+	return &RunInfo{WeightNames: names}
+}
+
+// attributesOf returns the attributes of the event, its particles and
+// its vertices, sorted by name and id.
+func attributesOf(evt *Event, pids map[*Particle]int, vids map[*Vertex]int) ([]v3attr, error) {
+	var (
+		attrs []v3attr
+		seen  = make(map[v3attr]bool)
+		add   = func(id int, name, value string) {
+			key := v3attr{id: id, name: name}
+			if seen[key] {
+				return
+			}
+			seen[key] = true
+			attrs = append(attrs, v3attr{id: id, name: name, value: value})
+		}
+		f64 = func(v float64) string {
+			return strconv.FormatFloat(v, 'g', -1, 64)
+		}
+		f32 = func(v float32) string {
+			return strconv.FormatFloat(float64(v), 'g', -1, 32)
+		}
+	)
+
+	if evt.SignalProcessID != 0 {
+		add(0, "signal_process_id", strconv.Itoa(evt.SignalProcessID))
+	}
+	if evt.Mpi != 0 {
+		add(0, "mpi", strconv.Itoa(evt.Mpi))
+	}
+	if evt.Scale != 0 {
+		add(0, "event_scale", f64(evt.Scale))
+	}
+	if evt.AlphaQCD != 0 {
+		add(0, "alphaQCD", f64(evt.AlphaQCD))
+	}
+	if evt.AlphaQED != 0 {
+		add(0, "alphaQED", f64(evt.AlphaQED))
+	}
+	if evt.SignalVertex != nil {
+		id, ok := vids[evt.SignalVertex]
+		if !ok {
+			return nil, fmt.Errorf("hepmc.encode: signal vertex %d not in event", evt.SignalVertex.Barcode)
+		}
+		add(0, "signal_process_vertex", strconv.Itoa(id))
+	}
+	for i, v := range evt.RandomStates {
+		add(0, "random_states"+strconv.Itoa(i), strconv.FormatInt(v, 10))
+	}
+
+	if x := evt.CrossSection; x != nil {
+		add(0, "GenCrossSection", fmt.Sprintf("%1.16e %1.16e -1 -1", x.Value, x.Error))
+	}
+
+	if pdf := evt.PdfInfo; pdf != nil {
+		add(0, "GenPdfInfo", strings.Join([]string{
+			strconv.Itoa(pdf.ID1), strconv.Itoa(pdf.ID2),
+			f64(pdf.X1), f64(pdf.X2),
+			f64(pdf.ScalePDF),
+			f64(pdf.Pdf1), f64(pdf.Pdf2),
+			strconv.Itoa(pdf.LHAPdf1), strconv.Itoa(pdf.LHAPdf2),
+		}, " "))
+	}
+
+	if hi := evt.HeavyIon; hi != nil {
+		add(0, "GenHeavyIon", strings.Join([]string{
+			strconv.Itoa(hi.NCollHard),
+			strconv.Itoa(hi.NPartProj),
+			strconv.Itoa(hi.NPartTarg),
+			strconv.Itoa(hi.NColl),
+			strconv.Itoa(hi.SpectatorNeutrons),
+			strconv.Itoa(hi.SpectatorProtons),
+			strconv.Itoa(hi.NNwColl),
+			strconv.Itoa(hi.NwNColl),
+			strconv.Itoa(hi.NwNwColl),
+			f32(hi.ImpactParameter),
+			f32(hi.EventPlaneAngle),
+			f32(hi.Eccentricity),
+			f32(hi.SigmaInelNN),
+			"0", // centrality
+		}, " "))
+	}
+
+	for name, value := range evt.Attributes {
+		add(0, name, value)
+	}
+
+	for p, id := range pids {
+		for k, v := range p.Flow.Icode {
+			add(id, "flow"+strconv.Itoa(k), strconv.Itoa(v))
+		}
+		if v := p.Polarization.Theta; v != 0 {
+			add(id, "theta", f64(v))
+		}
+		if v := p.Polarization.Phi; v != 0 {
+			add(id, "phi", f64(v))
+		}
+		for name, value := range p.Attributes {
+			add(id, name, value)
+		}
+	}
+
+	for vtx, id := range vids {
+		if len(vtx.Weights.Slice) > 0 {
+			ws := make([]string, len(vtx.Weights.Slice))
+			for i, w := range vtx.Weights.Slice {
+				ws[i] = f64(w)
+			}
+			add(id, "weights", strings.Join(ws, " "))
+		}
+		for name, value := range vtx.Attributes {
+			add(id, name, value)
+		}
+	}
+
+	sort.Slice(attrs, func(i, j int) bool {
+		ai, aj := attrs[i], attrs[j]
+		if ai.name != aj.name {
+			return ai.name < aj.name
+		}
+		return ai.id < aj.id
+	})
+
+	return attrs, nil
+}
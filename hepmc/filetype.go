@@ -8,10 +8,12 @@ const (
 	startGenEvent      = "HepMC::IO_GenEvent-START_EVENT_LISTING"
 	startASCII         = "HepMC::IO_Ascii-START_EVENT_LISTING"
 	startExtendedASCII = "HepMC::IO_ExtendedAscii-START_EVENT_LISTING"
+	startASCIIv3       = "HepMC::Asciiv3-START_EVENT_LISTING"
 
 	endGenEvent      = "HepMC::IO_GenEvent-END_EVENT_LISTING"
 	endASCII         = "HepMC::IO_Ascii-END_EVENT_LISTING"
 	endExtendedASCII = "HepMC::IO_ExtendedAscii-END_EVENT_LISTING"
+	endASCIIv3       = "HepMC::Asciiv3-END_EVENT_LISTING"
 
 	startPdt              = "HepMC::IO_Ascii-START_PARTICLE_DATA"
 	startExtendedASCIIPdt = "HepMC::IO_ExtendedAscii-START_PARTICLE_DATA"
@@ -28,4 +30,5 @@ const (
 	hepmcExtendedASCII
 	hepmcASCIIPdt
 	hepmcExtendedASCIIPdt
+	hepmcASCIIv3
 )
@@ -0,0 +1,73 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build ignore
+
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"go-hep.org/x/hep/groot"
+	"go-hep.org/x/hep/hepmc"
+)
+
+var (
+	oname = flag.String("o", "testdata/small.hepmc3.root", "output ROOT file")
+)
+
+func main() {
+	flag.Parse()
+
+	fname := "testdata/small.hepmc3"
+	if flag.NArg() > 0 {
+		fname = flag.Arg(0)
+	}
+
+	r, err := os.Open(fname)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer r.Close()
+
+	f, err := groot.Create(*oname)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	enc, err := hepmc.NewRootEncoder(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dec := hepmc.NewDecoder(r)
+	for {
+		var evt hepmc.Event
+		err := dec.Decode(&evt)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("could not decode event: %+v", err)
+		}
+		err = enc.Encode(&evt)
+		if err != nil {
+			log.Fatalf("could not encode event: %+v", err)
+		}
+	}
+
+	err = enc.Close()
+	if err != nil {
+		log.Fatalf("could not close encoder: %+v", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		log.Fatalf("could not close ROOT file: %+v", err)
+	}
+}
@@ -4,6 +4,7 @@
 
 // go-hepmc-dump is a simple command to dump in an almost human-friendly format
 // the content of a hepmc file.
+// HepMC3 ROOT files (with a ".root" extension) are also supported.
 // ex:
 //  $ go-hepmc-dump foo.hepmc | head -n20
 // ________________________________________________________________________________
@@ -33,7 +34,9 @@ import (
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 
+	"go-hep.org/x/hep/groot"
 	"go-hep.org/x/hep/hepmc"
 )
 
@@ -43,31 +46,59 @@ func main() {
 
 	var (
 		err error
-		r   io.ReadCloser
 		w   io.Writer = os.Stdout
 	)
 
 	switch len(os.Args) {
 	case 1:
-		r = os.Stdin
+		err = dump(w, os.Stdin)
 	case 2:
-		r, err = os.Open(os.Args[1])
-		if err != nil {
-			log.Fatal(err)
-		}
-		defer r.Close()
+		err = dumpFile(w, os.Args[1])
 	default:
+		log.Fatalf("invalid number of arguments")
 	}
 
-	err = dump(w, r)
 	if err != nil {
 		log.Fatal(err)
 	}
 }
 
+func dumpFile(w io.Writer, fname string) error {
+	if filepath.Ext(fname) == ".root" {
+		f, err := groot.Open(fname)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		dec, err := hepmc.NewRootDecoder(f)
+		if err != nil {
+			return err
+		}
+		defer dec.Close()
+
+		return dumpEvents(w, dec)
+	}
+
+	f, err := os.Open(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return dump(w, f)
+}
+
 func dump(w io.Writer, r io.Reader) error {
+	return dumpEvents(w, hepmc.NewDecoder(r))
+}
+
+type decoder interface {
+	Decode(evt *hepmc.Event) error
+}
+
+func dumpEvents(w io.Writer, dec decoder) error {
 	var err error
-	dec := hepmc.NewDecoder(r)
 	for {
 		var evt hepmc.Event
 		err = dec.Decode(&evt)
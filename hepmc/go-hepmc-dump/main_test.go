@@ -10,7 +10,11 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"testing"
+
+	"go-hep.org/x/hep/groot"
+	"go-hep.org/x/hep/hepmc"
 )
 
 func TestDump(t *testing.T) {
@@ -36,6 +40,84 @@ func TestDump(t *testing.T) {
 	}
 }
 
+func TestDumpROOT(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "hepmc-dump-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	raw, err := ioutil.ReadFile("../testdata/small.hepmc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		fname = filepath.Join(tmp, "small.root")
+		v3    = new(bytes.Buffer)
+	)
+	func() {
+		f, err := groot.Create(fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		enc, err := hepmc.NewRootEncoder(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var (
+			dec = hepmc.NewDecoder(bytes.NewReader(raw))
+			txt = hepmc.NewEncoderV3(v3)
+			evt hepmc.Event
+		)
+		err = dec.Decode(&evt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = enc.Encode(&evt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = txt.Encode(&evt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = enc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = txt.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	got := new(bytes.Buffer)
+	err = dumpFile(got, fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := new(bytes.Buffer)
+	err = dump(want, v3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t.Fatalf("dump error.\ngot:\n%s\nwant:\n%s\n", got.Bytes(), want.Bytes())
+	}
+}
+
 func TestDumpFail(t *testing.T) {
 	ref, err := ioutil.ReadFile("../testdata/small.hepmc")
 	if err != nil {
@@ -3,6 +3,22 @@
 // license that can be found in the LICENSE file.
 
 // Package hepmc is a pure Go implementation of the C++ HepMC-2 library.
+//
+// Package hepmc can read and write events in the HepMC2 IO_GenEvent
+// ASCII format (NewEncoder) and in the HepMC3 Asciiv3 ASCII format
+// (NewEncoderV3).
+// The Decoder automatically detects the format of its input stream.
+//
+// HepMC3 events are mapped onto the HepMC2 event model: HepMC3 particle and
+// vertex ids are used as barcodes, HepMC3 standard attributes (cross-section,
+// PDF and heavy-ion informations, signal process id, ...) are mapped onto
+// the corresponding Event fields and other attributes are stored in the
+// Attributes maps of events, particles and vertices.
+//
+// HepMC3 events stored in ROOT trees ("hepmc3_tree", as written by the
+// HepMC3 WriterRootTree) can be read with NewRootDecoder and written with
+// NewRootEncoder.
+// Only trees whose event branch is not split are supported.
 package hepmc // import "go-hep.org/x/hep/hepmc"
 
 import (
@@ -78,6 +94,9 @@ type Event struct {
 	MomentumUnit MomentumUnit
 	LengthUnit   LengthUnit
 
+	RunInfo    *RunInfo          // run information shared by the events of a run (HepMC3)
+	Attributes map[string]string // additional event attributes (HepMC3)
+
 	bcparts int // barcode suggestions for particles
 	bcverts int // barcode suggestions for vertices
 }
@@ -295,6 +314,8 @@ type Particle struct {
 	EndVertex     *Vertex      // pointer to decay vertex (nil if not-decayed)
 	Barcode       int          // unique identifier in the event
 	GeneratedMass float64      // mass of this particle when it was generated
+
+	Attributes map[string]string // additional particle attributes (HepMC3)
 }
 
 func (p *Particle) dump(w io.Writer) error {
@@ -361,6 +382,8 @@ type Vertex struct {
 	Weights      Weights      // weights for this vertex
 	Event        *Event       // pointer to event owning this vertex
 	Barcode      int          // unique identifier in the event
+
+	Attributes map[string]string // additional vertex attributes (HepMC3)
 }
 
 func (vtx *Vertex) setParentEvent(evt *Event) error {
@@ -717,7 +740,7 @@ type Weights struct {
 }
 
 // Add adds a new weight with name n and value v.
-func (w Weights) Add(n string, v float64) error {
+func (w *Weights) Add(n string, v float64) error {
 	_, ok := w.Map[n]
 	if ok {
 		return fmt.Errorf("hepmc.Weights.Add: name [%s] already in container", n)
@@ -737,6 +760,22 @@ func (w Weights) At(n string) float64 {
 	panic("hepmc.Weights.At: invalid name [" + n + "]")
 }
 
+// RunInfo holds the run-level informations of a HepMC3 stream, shared
+// by all the events of a run.
+type RunInfo struct {
+	Tools       []Tool            // tools used to generate the events
+	WeightNames []string          // names of the event weights
+	Attributes  map[string]string // run attributes
+}
+
+// Tool describes a tool (generator, detector simulation, ...) used to
+// produce events.
+type Tool struct {
+	Name        string
+	Version     string
+	Description string
+}
+
 // NewWeights creates a new set of weights.
 func NewWeights() Weights {
 	return Weights{
@@ -0,0 +1,250 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hepmc_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"reflect"
+	"testing"
+
+	"go-hep.org/x/hep/fmom"
+	"go-hep.org/x/hep/hepmc"
+)
+
+func TestDecodeV3(t *testing.T) {
+	f, err := os.Open("testdata/small.hepmc3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	dec := hepmc.NewDecoder(f)
+
+	var evt hepmc.Event
+	err = dec.Decode(&evt)
+	if err != nil {
+		t.Fatalf("could not decode event: %+v", err)
+	}
+	defer evt.Delete()
+
+	if got, want := evt.RunInfo, (&hepmc.RunInfo{
+		Tools:       []hepmc.Tool{{Name: "Pythia8", Version: "8.301", Description: "hand-written test event"}},
+		WeightNames: []string{"nominal", "scale_up"},
+		Attributes:  map[string]string{"ebeam": "6500"},
+	}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid run info:\ngot= %#v\nwant=%#v", got, want)
+	}
+
+	if got, want := evt.Weights.At("scale_up"), 2.0; got != want {
+		t.Fatalf("invalid weight: got=%v, want=%v", got, want)
+	}
+
+	if got, want := evt.Attributes, map[string]string{"note": "first line\nsecond line"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid event attributes:\ngot= %#v\nwant=%#v", got, want)
+	}
+
+	if got, want := *evt.CrossSection, (hepmc.CrossSection{Value: 12, Error: 0.1}); got != want {
+		t.Fatalf("invalid cross-section: got=%#v, want=%#v", got, want)
+	}
+
+	if got, want := evt.SignalProcessID, 20; got != want {
+		t.Fatalf("invalid signal process id: got=%d, want=%d", got, want)
+	}
+
+	if got, want := len(evt.Vertices), 4; got != want {
+		t.Fatalf("invalid number of vertices: got=%d, want=%d", got, want)
+	}
+
+	if got, want := len(evt.Particles), 8; got != want {
+		t.Fatalf("invalid number of particles: got=%d, want=%d", got, want)
+	}
+
+	if evt.Beams[0] != evt.Particles[1] || evt.Beams[1] != evt.Particles[2] {
+		t.Fatalf("invalid beam particles: %v", evt.Beams)
+	}
+
+	if evt.SignalVertex != evt.Vertices[-3] {
+		t.Fatalf("invalid signal vertex: got=%v", evt.SignalVertex.Barcode)
+	}
+
+	for i, tc := range []struct {
+		p    int
+		prod int
+		end  int
+	}{
+		{1, 0, -1},
+		{2, 0, -2},
+		{3, -1, -3},
+		{4, -2, -3},
+		{5, -3, 0},
+		{6, -3, -4},
+		{7, -4, 0},
+		{8, -4, 0},
+	} {
+		p := evt.Particles[tc.p]
+		prod, end := 0, 0
+		if p.ProdVertex != nil {
+			prod = p.ProdVertex.Barcode
+		}
+		if p.EndVertex != nil {
+			end = p.EndVertex.Barcode
+		}
+		if prod != tc.prod || end != tc.end {
+			t.Fatalf("particle #%d: invalid vertices: got=(%d, %d), want=(%d, %d)", i, prod, end, tc.prod, tc.end)
+		}
+	}
+
+	if got, want := evt.Particles[3].Flow.Icode, map[int]int{1: 501}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid flow: got=%v, want=%v", got, want)
+	}
+
+	if got, want := evt.Particles[5].Polarization.Theta, 0.5; got != want {
+		t.Fatalf("invalid polarization: got=%v, want=%v", got, want)
+	}
+
+	vtx := evt.Vertices[-4]
+	if got, want := vtx.Position, fmom.NewPxPyPzE(0.12, -0.3, 0.05, 0.004); got != want {
+		t.Fatalf("invalid vertex position: got=%v, want=%v", got, want)
+	}
+	if got, want := vtx.Attributes, map[string]string{"vtx_note": "displaced"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid vertex attributes:\ngot= %#v\nwant=%#v", got, want)
+	}
+
+	err = dec.Decode(&evt)
+	if err != io.EOF {
+		t.Fatalf("expected EOF, got %+v", err)
+	}
+}
+
+func TestEventRWV3(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		fname string
+		nevts int
+	}{
+		{"v2-small", "testdata/small.hepmc", 1},
+		{"v2-test", "testdata/test.hepmc", 6},
+		{"v3-small", "testdata/small.hepmc3", 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := ioutil.ReadFile(tc.fname)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			v3 := convert(t, raw, hepmc.NewEncoderV3, tc.nevts)
+
+			// round-trip through HepMC3.
+			if got, want := convert(t, v3, hepmc.NewEncoderV3, tc.nevts), v3; !bytes.Equal(got, want) {
+				t.Fatalf("HepMC3 round-trip failed:\ngot:\n%s\nwant:\n%s", got, want)
+			}
+
+			// round-trip through HepMC2.
+			v2 := convert(t, raw, hepmc.NewEncoder, tc.nevts)
+			if got, want := convert(t, v3, hepmc.NewEncoder, tc.nevts), v2; !bytes.Equal(got, want) {
+				t.Fatalf("HepMC3 to HepMC2 conversion failed:\ngot:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+// convert decodes all the events from raw and re-encodes them with the
+// encoder created by newEncoder.
+func convert(t *testing.T, raw []byte, newEncoder func(w io.Writer) *hepmc.Encoder, nevts int) []byte {
+	t.Helper()
+
+	var (
+		out = new(bytes.Buffer)
+		dec = hepmc.NewDecoder(bytes.NewReader(raw))
+		enc = newEncoder(out)
+		n   = 0
+	)
+
+	for {
+		var evt hepmc.Event
+		err := dec.Decode(&evt)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("could not decode event %d: %+v", n, err)
+		}
+		err = enc.Encode(&evt)
+		if err != nil {
+			t.Fatalf("could not encode event %d: %+v", n, err)
+		}
+		n++
+	}
+
+	err := enc.Close()
+	if err != nil {
+		t.Fatalf("could not close encoder: %+v", err)
+	}
+
+	if n != nevts {
+		t.Fatalf("invalid number of events: got=%d, want=%d", n, nevts)
+	}
+
+	return out.Bytes()
+}
+
+func ExampleNewEncoderV3() {
+	f, err := os.Open("testdata/small.hepmc")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	// the decoder automatically detects the format of the input stream.
+	dec := hepmc.NewDecoder(f)
+	enc := hepmc.NewEncoderV3(os.Stdout)
+
+	for {
+		var evt hepmc.Event
+		err := dec.Decode(&evt)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		err = enc.Encode(&evt)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	err = enc.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Output:
+	// HepMC::Version 3.02.02
+	// HepMC::Asciiv3-START_EVENT_LISTING
+	// E 1 4 8
+	// U GEV MM
+	// A 0 GenPdfInfo 0 0 0 0 0 0 0 0 0
+	// A 0 signal_process_id 20
+	// A 0 signal_process_vertex -3
+	// P 1 0 2212 0.0000000000000000e+00 0.0000000000000000e+00 7.0000000000000000e+03 7.0000000000000000e+03 0.0000000000000000e+00 3
+	// P 2 0 2212 0.0000000000000000e+00 0.0000000000000000e+00 -7.0000000000000000e+03 7.0000000000000000e+03 0.0000000000000000e+00 3
+	// V -1 0 [1]
+	// P 3 -1 1 7.5000000000000000e-01 -1.5690000000000000e+00 3.2191000000000003e+01 3.2238000000000000e+01 0.0000000000000000e+00 3
+	// V -2 0 [2]
+	// P 4 -2 -2 -3.0470000000000002e+00 -1.9000000000000000e+01 -5.4628999999999998e+01 5.7920000000000002e+01 0.0000000000000000e+00 3
+	// V -3 0 [3,4]
+	// P 5 -3 22 -3.8130000000000002e+00 1.1300000000000000e-01 -1.8330000000000000e+00 4.2329999999999997e+00 0.0000000000000000e+00 1
+	// P 6 -3 -24 1.5169999999999999e+00 -2.0680000000000000e+01 -2.0605000000000000e+01 8.5924999999999997e+01 0.0000000000000000e+00 3
+	// V -4 0 [6] @ 1.2000000000000000e-01 -2.9999999999999999e-01 5.0000000000000003e-02 4.0000000000000001e-03
+	// P 7 -4 1 -2.4449999999999998e+00 2.8815999999999999e+01 6.0819999999999999e+00 2.9552000000000000e+01 0.0000000000000000e+00 1
+	// P 8 -4 -2 3.9620000000000002e+00 -4.9497999999999998e+01 -2.6687000000000001e+01 5.6372999999999998e+01 0.0000000000000000e+00 1
+	// HepMC::Asciiv3-END_EVENT_LISTING
+}
@@ -461,3 +461,25 @@ func ExampleEvent_buildFromScratch() {
 	// P 8 -2 3.9620000000000002e+00 -4.9497999999999998e+01 -2.6687000000000001e+01 5.6372999999999998e+01 0.0000000000000000e+00 1 0.0000000000000000e+00 0.0000000000000000e+00 0 0
 	//
 }
+
+func TestWeightsAdd(t *testing.T) {
+	w := hepmc.NewWeights()
+	for i, name := range []string{"0", "1001", "1002"} {
+		err := w.Add(name, float64(i+1))
+		if err != nil {
+			t.Fatalf("could not add weight %q: %+v", name, err)
+		}
+	}
+
+	if got, want := len(w.Slice), 3; got != want {
+		t.Fatalf("invalid number of weights: got=%d, want=%d", got, want)
+	}
+	if got, want := w.At("1002"), 3.0; got != want {
+		t.Fatalf("invalid weight: got=%v, want=%v", got, want)
+	}
+
+	err := w.Add("1001", 42)
+	if err == nil {
+		t.Fatalf("expected an error adding a duplicate weight")
+	}
+}
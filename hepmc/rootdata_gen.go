@@ -0,0 +1,1056 @@
+// DO NOT EDIT; automatically generated by root-gen-type
+
+package hepmc
+
+import (
+	"fmt"
+	"reflect"
+
+	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rdict"
+	"go-hep.org/x/hep/groot/rmeta"
+	"go-hep.org/x/hep/groot/root"
+	"go-hep.org/x/hep/groot/rtypes"
+	"go-hep.org/x/hep/groot/rvers"
+)
+
+type HepMC3__GenEventData struct {
+	ROOT_event_number     int32                     `groot:"event_number"`
+	ROOT_momentum_unit    int32                     `groot:"momentum_unit"`
+	ROOT_length_unit      int32                     `groot:"length_unit"`
+	ROOT_particles        []HepMC3__GenParticleData `groot:"particles"`
+	ROOT_vertices         []HepMC3__GenVertexData   `groot:"vertices"`
+	ROOT_weights          []float64                 `groot:"weights"`
+	ROOT_event_pos        HepMC3__FourVector        `groot:"event_pos"`
+	ROOT_links1           []int32                   `groot:"links1"`
+	ROOT_links2           []int32                   `groot:"links2"`
+	ROOT_attribute_id     []int32                   `groot:"attribute_id"`
+	ROOT_attribute_name   []string                  `groot:"attribute_name"`
+	ROOT_attribute_string []string                  `groot:"attribute_string"`
+}
+
+func (*HepMC3__GenEventData) Class() string {
+	return "HepMC3::GenEventData"
+}
+
+func (*HepMC3__GenEventData) RVersion() int16 {
+	return 1
+}
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *HepMC3__GenEventData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+
+	w.WriteI32(o.ROOT_event_number)
+	w.WriteI32(o.ROOT_momentum_unit)
+	w.WriteI32(o.ROOT_length_unit)
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.ROOT_particles)))
+		for i := range o.ROOT_particles {
+			o.ROOT_particles[i].MarshalROOT(w) // obj
+		}
+		if _, err := w.SetByteCount(pos, "vector<HepMC3::GenParticleData>"); err != nil {
+			w.SetErr(err)
+			return 0, w.Err()
+		}
+	}
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.ROOT_vertices)))
+		for i := range o.ROOT_vertices {
+			o.ROOT_vertices[i].MarshalROOT(w) // obj
+		}
+		if _, err := w.SetByteCount(pos, "vector<HepMC3::GenVertexData>"); err != nil {
+			w.SetErr(err)
+			return 0, w.Err()
+		}
+	}
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.ROOT_weights)))
+		w.WriteFastArrayF64(o.ROOT_weights)
+		if _, err := w.SetByteCount(pos, "vector<double>"); err != nil {
+			w.SetErr(err)
+			return 0, w.Err()
+		}
+	}
+	o.ROOT_event_pos.MarshalROOT(w) // obj-any
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.ROOT_links1)))
+		w.WriteFastArrayI32(o.ROOT_links1)
+		if _, err := w.SetByteCount(pos, "vector<int>"); err != nil {
+			w.SetErr(err)
+			return 0, w.Err()
+		}
+	}
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.ROOT_links2)))
+		w.WriteFastArrayI32(o.ROOT_links2)
+		if _, err := w.SetByteCount(pos, "vector<int>"); err != nil {
+			w.SetErr(err)
+			return 0, w.Err()
+		}
+	}
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.ROOT_attribute_id)))
+		w.WriteFastArrayI32(o.ROOT_attribute_id)
+		if _, err := w.SetByteCount(pos, "vector<int>"); err != nil {
+			w.SetErr(err)
+			return 0, w.Err()
+		}
+	}
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.ROOT_attribute_name)))
+		w.WriteFastArrayString(o.ROOT_attribute_name)
+		if _, err := w.SetByteCount(pos, "vector<string>"); err != nil {
+			w.SetErr(err)
+			return 0, w.Err()
+		}
+	}
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.ROOT_attribute_string)))
+		w.WriteFastArrayString(o.ROOT_attribute_string)
+		if _, err := w.SetByteCount(pos, "vector<string>"); err != nil {
+			w.SetErr(err)
+			return 0, w.Err()
+		}
+	}
+
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *HepMC3__GenEventData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	vers, pos, bcnt := r.ReadVersion(o.Class())
+	if vers != o.RVersion() {
+		err := rdict.UnmarshalEvolved(r, o, o.Class(), vers)
+		if err != nil {
+			return err
+		}
+		r.CheckByteCount(pos, bcnt, start, o.Class())
+		return r.Err()
+	}
+
+	o.ROOT_event_number = r.ReadI32()
+	o.ROOT_momentum_unit = r.ReadI32()
+	o.ROOT_length_unit = r.ReadI32()
+	{
+		vers, pos, bcnt := r.ReadVersion("vector<HepMC3::GenParticleData>")
+		if vers != rvers.StreamerInfo {
+			r.SetErr(fmt.Errorf("rbytes: invalid version for \"vector<HepMC3::GenParticleData>\". got=%v, want=%v", vers, rvers.StreamerInfo))
+			return r.Err()
+		}
+		o.ROOT_particles = make([]HepMC3__GenParticleData, int(r.ReadI32()))
+		for i := range o.ROOT_particles {
+			o.ROOT_particles[i].UnmarshalROOT(r) // obj
+		}
+		r.CheckByteCount(pos, bcnt, start, "vector<HepMC3::GenParticleData>")
+	}
+	{
+		vers, pos, bcnt := r.ReadVersion("vector<HepMC3::GenVertexData>")
+		if vers != rvers.StreamerInfo {
+			r.SetErr(fmt.Errorf("rbytes: invalid version for \"vector<HepMC3::GenVertexData>\". got=%v, want=%v", vers, rvers.StreamerInfo))
+			return r.Err()
+		}
+		o.ROOT_vertices = make([]HepMC3__GenVertexData, int(r.ReadI32()))
+		for i := range o.ROOT_vertices {
+			o.ROOT_vertices[i].UnmarshalROOT(r) // obj
+		}
+		r.CheckByteCount(pos, bcnt, start, "vector<HepMC3::GenVertexData>")
+	}
+	{
+		vers, pos, bcnt := r.ReadVersion("vector<double>")
+		if vers != rvers.StreamerInfo {
+			r.SetErr(fmt.Errorf("rbytes: invalid version for \"vector<double>\". got=%v, want=%v", vers, rvers.StreamerInfo))
+			return r.Err()
+		}
+		o.ROOT_weights = rbytes.ResizeF64(nil, int(r.ReadI32()))
+		r.ReadArrayF64(o.ROOT_weights)
+		r.CheckByteCount(pos, bcnt, start, "vector<double>")
+	}
+	o.ROOT_event_pos.UnmarshalROOT(r) // obj-any
+	{
+		vers, pos, bcnt := r.ReadVersion("vector<int>")
+		if vers != rvers.StreamerInfo {
+			r.SetErr(fmt.Errorf("rbytes: invalid version for \"vector<int>\". got=%v, want=%v", vers, rvers.StreamerInfo))
+			return r.Err()
+		}
+		o.ROOT_links1 = rbytes.ResizeI32(nil, int(r.ReadI32()))
+		r.ReadArrayI32(o.ROOT_links1)
+		r.CheckByteCount(pos, bcnt, start, "vector<int>")
+	}
+	{
+		vers, pos, bcnt := r.ReadVersion("vector<int>")
+		if vers != rvers.StreamerInfo {
+			r.SetErr(fmt.Errorf("rbytes: invalid version for \"vector<int>\". got=%v, want=%v", vers, rvers.StreamerInfo))
+			return r.Err()
+		}
+		o.ROOT_links2 = rbytes.ResizeI32(nil, int(r.ReadI32()))
+		r.ReadArrayI32(o.ROOT_links2)
+		r.CheckByteCount(pos, bcnt, start, "vector<int>")
+	}
+	{
+		vers, pos, bcnt := r.ReadVersion("vector<int>")
+		if vers != rvers.StreamerInfo {
+			r.SetErr(fmt.Errorf("rbytes: invalid version for \"vector<int>\". got=%v, want=%v", vers, rvers.StreamerInfo))
+			return r.Err()
+		}
+		o.ROOT_attribute_id = rbytes.ResizeI32(nil, int(r.ReadI32()))
+		r.ReadArrayI32(o.ROOT_attribute_id)
+		r.CheckByteCount(pos, bcnt, start, "vector<int>")
+	}
+	{
+		vers, pos, bcnt := r.ReadVersion("vector<string>")
+		if vers != rvers.StreamerInfo {
+			r.SetErr(fmt.Errorf("rbytes: invalid version for \"vector<string>\". got=%v, want=%v", vers, rvers.StreamerInfo))
+			return r.Err()
+		}
+		o.ROOT_attribute_name = rbytes.ResizeStr(nil, int(r.ReadI32()))
+		r.ReadArrayString(o.ROOT_attribute_name)
+		r.CheckByteCount(pos, bcnt, start, "vector<string>")
+	}
+	{
+		vers, pos, bcnt := r.ReadVersion("vector<string>")
+		if vers != rvers.StreamerInfo {
+			r.SetErr(fmt.Errorf("rbytes: invalid version for \"vector<string>\". got=%v, want=%v", vers, rvers.StreamerInfo))
+			return r.Err()
+		}
+		o.ROOT_attribute_string = rbytes.ResizeStr(nil, int(r.ReadI32()))
+		r.ReadArrayString(o.ROOT_attribute_string)
+		r.CheckByteCount(pos, bcnt, start, "vector<string>")
+	}
+
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+func init() {
+	f := func() reflect.Value {
+		var o HepMC3__GenEventData
+		return reflect.ValueOf(&o)
+	}
+	rtypes.Factory.Add("HepMC3::GenEventData", f)
+}
+
+func init() {
+	// Streamer for HepMC3::GenEventData.
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("HepMC3::GenEventData", 1, 0x0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{
+			Name:   *rbase.NewNamed("event_number", ""),
+			Type:   rmeta.Int,
+			Size:   4,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "int",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{
+			Name:   *rbase.NewNamed("momentum_unit", ""),
+			Type:   rmeta.Int,
+			Size:   4,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "HepMC3::Units::MomentumUnit",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{
+			Name:   *rbase.NewNamed("length_unit", ""),
+			Type:   rmeta.Int,
+			Size:   4,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "HepMC3::Units::LengthUnit",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		rdict.NewCxxStreamerSTL(rdict.Element{
+			Name:   *rbase.NewNamed("particles", ""),
+			Type:   rmeta.Streamer,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "vector<HepMC3::GenParticleData>",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, 61),
+		rdict.NewCxxStreamerSTL(rdict.Element{
+			Name:   *rbase.NewNamed("vertices", ""),
+			Type:   rmeta.Streamer,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "vector<HepMC3::GenVertexData>",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, 61),
+		rdict.NewCxxStreamerSTL(rdict.Element{
+			Name:   *rbase.NewNamed("weights", ""),
+			Type:   rmeta.Streamer,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "vector<double>",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, 8),
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{
+			Name:   *rbase.NewNamed("event_pos", ""),
+			Type:   rmeta.Any,
+			Size:   32,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "HepMC3::FourVector",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		rdict.NewCxxStreamerSTL(rdict.Element{
+			Name:   *rbase.NewNamed("links1", ""),
+			Type:   rmeta.Streamer,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "vector<int>",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, 3),
+		rdict.NewCxxStreamerSTL(rdict.Element{
+			Name:   *rbase.NewNamed("links2", ""),
+			Type:   rmeta.Streamer,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "vector<int>",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, 3),
+		rdict.NewCxxStreamerSTL(rdict.Element{
+			Name:   *rbase.NewNamed("attribute_id", ""),
+			Type:   rmeta.Streamer,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "vector<int>",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, 3),
+		rdict.NewCxxStreamerSTL(rdict.Element{
+			Name:   *rbase.NewNamed("attribute_name", ""),
+			Type:   rmeta.Streamer,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "vector<string>",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, 61),
+		rdict.NewCxxStreamerSTL(rdict.Element{
+			Name:   *rbase.NewNamed("attribute_string", ""),
+			Type:   rmeta.Streamer,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "vector<string>",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, 61),
+	}))
+}
+
+var (
+	_ root.Object        = (*HepMC3__GenEventData)(nil)
+	_ rbytes.RVersioner  = (*HepMC3__GenEventData)(nil)
+	_ rbytes.Marshaler   = (*HepMC3__GenEventData)(nil)
+	_ rbytes.Unmarshaler = (*HepMC3__GenEventData)(nil)
+)
+
+type HepMC3__GenRunInfoData struct {
+	ROOT_weight_names     []string `groot:"weight_names"`
+	ROOT_tool_name        []string `groot:"tool_name"`
+	ROOT_tool_version     []string `groot:"tool_version"`
+	ROOT_tool_description []string `groot:"tool_description"`
+	ROOT_attribute_name   []string `groot:"attribute_name"`
+	ROOT_attribute_string []string `groot:"attribute_string"`
+}
+
+func (*HepMC3__GenRunInfoData) Class() string {
+	return "HepMC3::GenRunInfoData"
+}
+
+func (*HepMC3__GenRunInfoData) RVersion() int16 {
+	return 1
+}
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *HepMC3__GenRunInfoData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.ROOT_weight_names)))
+		w.WriteFastArrayString(o.ROOT_weight_names)
+		if _, err := w.SetByteCount(pos, "vector<string>"); err != nil {
+			w.SetErr(err)
+			return 0, w.Err()
+		}
+	}
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.ROOT_tool_name)))
+		w.WriteFastArrayString(o.ROOT_tool_name)
+		if _, err := w.SetByteCount(pos, "vector<string>"); err != nil {
+			w.SetErr(err)
+			return 0, w.Err()
+		}
+	}
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.ROOT_tool_version)))
+		w.WriteFastArrayString(o.ROOT_tool_version)
+		if _, err := w.SetByteCount(pos, "vector<string>"); err != nil {
+			w.SetErr(err)
+			return 0, w.Err()
+		}
+	}
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.ROOT_tool_description)))
+		w.WriteFastArrayString(o.ROOT_tool_description)
+		if _, err := w.SetByteCount(pos, "vector<string>"); err != nil {
+			w.SetErr(err)
+			return 0, w.Err()
+		}
+	}
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.ROOT_attribute_name)))
+		w.WriteFastArrayString(o.ROOT_attribute_name)
+		if _, err := w.SetByteCount(pos, "vector<string>"); err != nil {
+			w.SetErr(err)
+			return 0, w.Err()
+		}
+	}
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.ROOT_attribute_string)))
+		w.WriteFastArrayString(o.ROOT_attribute_string)
+		if _, err := w.SetByteCount(pos, "vector<string>"); err != nil {
+			w.SetErr(err)
+			return 0, w.Err()
+		}
+	}
+
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *HepMC3__GenRunInfoData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	vers, pos, bcnt := r.ReadVersion(o.Class())
+	if vers != o.RVersion() {
+		err := rdict.UnmarshalEvolved(r, o, o.Class(), vers)
+		if err != nil {
+			return err
+		}
+		r.CheckByteCount(pos, bcnt, start, o.Class())
+		return r.Err()
+	}
+
+	{
+		vers, pos, bcnt := r.ReadVersion("vector<string>")
+		if vers != rvers.StreamerInfo {
+			r.SetErr(fmt.Errorf("rbytes: invalid version for \"vector<string>\". got=%v, want=%v", vers, rvers.StreamerInfo))
+			return r.Err()
+		}
+		o.ROOT_weight_names = rbytes.ResizeStr(nil, int(r.ReadI32()))
+		r.ReadArrayString(o.ROOT_weight_names)
+		r.CheckByteCount(pos, bcnt, start, "vector<string>")
+	}
+	{
+		vers, pos, bcnt := r.ReadVersion("vector<string>")
+		if vers != rvers.StreamerInfo {
+			r.SetErr(fmt.Errorf("rbytes: invalid version for \"vector<string>\". got=%v, want=%v", vers, rvers.StreamerInfo))
+			return r.Err()
+		}
+		o.ROOT_tool_name = rbytes.ResizeStr(nil, int(r.ReadI32()))
+		r.ReadArrayString(o.ROOT_tool_name)
+		r.CheckByteCount(pos, bcnt, start, "vector<string>")
+	}
+	{
+		vers, pos, bcnt := r.ReadVersion("vector<string>")
+		if vers != rvers.StreamerInfo {
+			r.SetErr(fmt.Errorf("rbytes: invalid version for \"vector<string>\". got=%v, want=%v", vers, rvers.StreamerInfo))
+			return r.Err()
+		}
+		o.ROOT_tool_version = rbytes.ResizeStr(nil, int(r.ReadI32()))
+		r.ReadArrayString(o.ROOT_tool_version)
+		r.CheckByteCount(pos, bcnt, start, "vector<string>")
+	}
+	{
+		vers, pos, bcnt := r.ReadVersion("vector<string>")
+		if vers != rvers.StreamerInfo {
+			r.SetErr(fmt.Errorf("rbytes: invalid version for \"vector<string>\". got=%v, want=%v", vers, rvers.StreamerInfo))
+			return r.Err()
+		}
+		o.ROOT_tool_description = rbytes.ResizeStr(nil, int(r.ReadI32()))
+		r.ReadArrayString(o.ROOT_tool_description)
+		r.CheckByteCount(pos, bcnt, start, "vector<string>")
+	}
+	{
+		vers, pos, bcnt := r.ReadVersion("vector<string>")
+		if vers != rvers.StreamerInfo {
+			r.SetErr(fmt.Errorf("rbytes: invalid version for \"vector<string>\". got=%v, want=%v", vers, rvers.StreamerInfo))
+			return r.Err()
+		}
+		o.ROOT_attribute_name = rbytes.ResizeStr(nil, int(r.ReadI32()))
+		r.ReadArrayString(o.ROOT_attribute_name)
+		r.CheckByteCount(pos, bcnt, start, "vector<string>")
+	}
+	{
+		vers, pos, bcnt := r.ReadVersion("vector<string>")
+		if vers != rvers.StreamerInfo {
+			r.SetErr(fmt.Errorf("rbytes: invalid version for \"vector<string>\". got=%v, want=%v", vers, rvers.StreamerInfo))
+			return r.Err()
+		}
+		o.ROOT_attribute_string = rbytes.ResizeStr(nil, int(r.ReadI32()))
+		r.ReadArrayString(o.ROOT_attribute_string)
+		r.CheckByteCount(pos, bcnt, start, "vector<string>")
+	}
+
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+func init() {
+	f := func() reflect.Value {
+		var o HepMC3__GenRunInfoData
+		return reflect.ValueOf(&o)
+	}
+	rtypes.Factory.Add("HepMC3::GenRunInfoData", f)
+}
+
+func init() {
+	// Streamer for HepMC3::GenRunInfoData.
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("HepMC3::GenRunInfoData", 1, 0x0, []rbytes.StreamerElement{
+		rdict.NewCxxStreamerSTL(rdict.Element{
+			Name:   *rbase.NewNamed("weight_names", ""),
+			Type:   rmeta.Streamer,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "vector<string>",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, 61),
+		rdict.NewCxxStreamerSTL(rdict.Element{
+			Name:   *rbase.NewNamed("tool_name", ""),
+			Type:   rmeta.Streamer,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "vector<string>",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, 61),
+		rdict.NewCxxStreamerSTL(rdict.Element{
+			Name:   *rbase.NewNamed("tool_version", ""),
+			Type:   rmeta.Streamer,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "vector<string>",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, 61),
+		rdict.NewCxxStreamerSTL(rdict.Element{
+			Name:   *rbase.NewNamed("tool_description", ""),
+			Type:   rmeta.Streamer,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "vector<string>",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, 61),
+		rdict.NewCxxStreamerSTL(rdict.Element{
+			Name:   *rbase.NewNamed("attribute_name", ""),
+			Type:   rmeta.Streamer,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "vector<string>",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, 61),
+		rdict.NewCxxStreamerSTL(rdict.Element{
+			Name:   *rbase.NewNamed("attribute_string", ""),
+			Type:   rmeta.Streamer,
+			Size:   24,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "vector<string>",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New(), 1, 61),
+	}))
+}
+
+var (
+	_ root.Object        = (*HepMC3__GenRunInfoData)(nil)
+	_ rbytes.RVersioner  = (*HepMC3__GenRunInfoData)(nil)
+	_ rbytes.Marshaler   = (*HepMC3__GenRunInfoData)(nil)
+	_ rbytes.Unmarshaler = (*HepMC3__GenRunInfoData)(nil)
+)
+
+type HepMC3__GenParticleData struct {
+	ROOT_pid         int32              `groot:"pid"`
+	ROOT_status      int32              `groot:"status"`
+	ROOT_is_mass_set bool               `groot:"is_mass_set"`
+	ROOT_mass        float64            `groot:"mass"`
+	ROOT_momentum    HepMC3__FourVector `groot:"momentum"`
+}
+
+func (*HepMC3__GenParticleData) Class() string {
+	return "HepMC3::GenParticleData"
+}
+
+func (*HepMC3__GenParticleData) RVersion() int16 {
+	return 1
+}
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *HepMC3__GenParticleData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+
+	w.WriteI32(o.ROOT_pid)
+	w.WriteI32(o.ROOT_status)
+	w.WriteBool(o.ROOT_is_mass_set)
+	w.WriteF64(o.ROOT_mass)
+	o.ROOT_momentum.MarshalROOT(w) // obj-any
+
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *HepMC3__GenParticleData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	vers, pos, bcnt := r.ReadVersion(o.Class())
+	if vers != o.RVersion() {
+		err := rdict.UnmarshalEvolved(r, o, o.Class(), vers)
+		if err != nil {
+			return err
+		}
+		r.CheckByteCount(pos, bcnt, start, o.Class())
+		return r.Err()
+	}
+
+	o.ROOT_pid = r.ReadI32()
+	o.ROOT_status = r.ReadI32()
+	o.ROOT_is_mass_set = r.ReadBool()
+	o.ROOT_mass = r.ReadF64()
+	o.ROOT_momentum.UnmarshalROOT(r) // obj-any
+
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+func init() {
+	f := func() reflect.Value {
+		var o HepMC3__GenParticleData
+		return reflect.ValueOf(&o)
+	}
+	rtypes.Factory.Add("HepMC3::GenParticleData", f)
+}
+
+func init() {
+	// Streamer for HepMC3::GenParticleData.
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("HepMC3::GenParticleData", 1, 0x0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{
+			Name:   *rbase.NewNamed("pid", ""),
+			Type:   rmeta.Int,
+			Size:   4,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "int",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{
+			Name:   *rbase.NewNamed("status", ""),
+			Type:   rmeta.Int,
+			Size:   4,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "int",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{
+			Name:   *rbase.NewNamed("is_mass_set", ""),
+			Type:   rmeta.Bool,
+			Size:   1,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "bool",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{
+			Name:   *rbase.NewNamed("mass", ""),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{
+			Name:   *rbase.NewNamed("momentum", ""),
+			Type:   rmeta.Any,
+			Size:   32,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "HepMC3::FourVector",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+	}))
+}
+
+var (
+	_ root.Object        = (*HepMC3__GenParticleData)(nil)
+	_ rbytes.RVersioner  = (*HepMC3__GenParticleData)(nil)
+	_ rbytes.Marshaler   = (*HepMC3__GenParticleData)(nil)
+	_ rbytes.Unmarshaler = (*HepMC3__GenParticleData)(nil)
+)
+
+type HepMC3__FourVector struct {
+	ROOT_m_v1 float64 `groot:"m_v1"`
+	ROOT_m_v2 float64 `groot:"m_v2"`
+	ROOT_m_v3 float64 `groot:"m_v3"`
+	ROOT_m_v4 float64 `groot:"m_v4"`
+}
+
+func (*HepMC3__FourVector) Class() string {
+	return "HepMC3::FourVector"
+}
+
+func (*HepMC3__FourVector) RVersion() int16 {
+	return 1
+}
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *HepMC3__FourVector) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+
+	w.WriteF64(o.ROOT_m_v1)
+	w.WriteF64(o.ROOT_m_v2)
+	w.WriteF64(o.ROOT_m_v3)
+	w.WriteF64(o.ROOT_m_v4)
+
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *HepMC3__FourVector) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	vers, pos, bcnt := r.ReadVersion(o.Class())
+	if vers != o.RVersion() {
+		err := rdict.UnmarshalEvolved(r, o, o.Class(), vers)
+		if err != nil {
+			return err
+		}
+		r.CheckByteCount(pos, bcnt, start, o.Class())
+		return r.Err()
+	}
+
+	o.ROOT_m_v1 = r.ReadF64()
+	o.ROOT_m_v2 = r.ReadF64()
+	o.ROOT_m_v3 = r.ReadF64()
+	o.ROOT_m_v4 = r.ReadF64()
+
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+func init() {
+	f := func() reflect.Value {
+		var o HepMC3__FourVector
+		return reflect.ValueOf(&o)
+	}
+	rtypes.Factory.Add("HepMC3::FourVector", f)
+}
+
+func init() {
+	// Streamer for HepMC3::FourVector.
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("HepMC3::FourVector", 1, 0x0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{
+			Name:   *rbase.NewNamed("m_v1", ""),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{
+			Name:   *rbase.NewNamed("m_v2", ""),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{
+			Name:   *rbase.NewNamed("m_v3", ""),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{
+			Name:   *rbase.NewNamed("m_v4", ""),
+			Type:   rmeta.Double,
+			Size:   8,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "double",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+	}))
+}
+
+var (
+	_ root.Object        = (*HepMC3__FourVector)(nil)
+	_ rbytes.RVersioner  = (*HepMC3__FourVector)(nil)
+	_ rbytes.Marshaler   = (*HepMC3__FourVector)(nil)
+	_ rbytes.Unmarshaler = (*HepMC3__FourVector)(nil)
+)
+
+type HepMC3__GenVertexData struct {
+	ROOT_status   int32              `groot:"status"`
+	ROOT_position HepMC3__FourVector `groot:"position"`
+}
+
+func (*HepMC3__GenVertexData) Class() string {
+	return "HepMC3::GenVertexData"
+}
+
+func (*HepMC3__GenVertexData) RVersion() int16 {
+	return 1
+}
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *HepMC3__GenVertexData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+
+	w.WriteI32(o.ROOT_status)
+	o.ROOT_position.MarshalROOT(w) // obj-any
+
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *HepMC3__GenVertexData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	vers, pos, bcnt := r.ReadVersion(o.Class())
+	if vers != o.RVersion() {
+		err := rdict.UnmarshalEvolved(r, o, o.Class(), vers)
+		if err != nil {
+			return err
+		}
+		r.CheckByteCount(pos, bcnt, start, o.Class())
+		return r.Err()
+	}
+
+	o.ROOT_status = r.ReadI32()
+	o.ROOT_position.UnmarshalROOT(r) // obj-any
+
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+func init() {
+	f := func() reflect.Value {
+		var o HepMC3__GenVertexData
+		return reflect.ValueOf(&o)
+	}
+	rtypes.Factory.Add("HepMC3::GenVertexData", f)
+}
+
+func init() {
+	// Streamer for HepMC3::GenVertexData.
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("HepMC3::GenVertexData", 1, 0x0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{
+			Name:   *rbase.NewNamed("status", ""),
+			Type:   rmeta.Int,
+			Size:   4,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "int",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{
+			Name:   *rbase.NewNamed("position", ""),
+			Type:   rmeta.Any,
+			Size:   32,
+			ArrLen: 0,
+			ArrDim: 0,
+			MaxIdx: [5]int32{0, 0, 0, 0, 0},
+			Offset: 0,
+			EName:  "HepMC3::FourVector",
+			XMin:   0.000000,
+			XMax:   0.000000,
+			Factor: 0.000000,
+		}.New()},
+	}))
+}
+
+var (
+	_ root.Object        = (*HepMC3__GenVertexData)(nil)
+	_ rbytes.RVersioner  = (*HepMC3__GenVertexData)(nil)
+	_ rbytes.Marshaler   = (*HepMC3__GenVertexData)(nil)
+	_ rbytes.Unmarshaler = (*HepMC3__GenVertexData)(nil)
+)
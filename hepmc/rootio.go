@@ -0,0 +1,351 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:generate go run ./gen-rootdata.go -o ./testdata/small.hepmc3.root ./testdata/small.hepmc3
+//go:generate root-gen-type -p hepmc -t HepMC3::.* -export -o rootdata_gen.go ./testdata/small.hepmc3.root
+
+package hepmc
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"go-hep.org/x/hep/fmom"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/rtree"
+)
+
+const (
+	rootTreeName = "hepmc3_tree"  // default name of HepMC3 ROOT trees
+	rootEvtName  = "hepmc3_event" // name of the branch holding the events
+	rootRunName  = "GenRunInfo"   // name of the branch holding the run information
+)
+
+// RootDecoder decodes hepmc.Event objects from a HepMC3 ROOT tree,
+// as written by HepMC3's WriterRootTree.
+//
+// Event branches may be split (HepMC3's default) or not.
+type RootDecoder struct {
+	sc *rtree.Scanner
+
+	evt HepMC3__GenEventData
+	run HepMC3__GenRunInfoData
+
+	rdata HepMC3__GenRunInfoData // run information data of the previous event
+	info  *RunInfo               // run information of the previous event
+}
+
+// NewRootDecoder returns a new decoder reading events from the HepMC3
+// ROOT tree "hepmc3_tree" stored in dir.
+func NewRootDecoder(dir riofs.Directory) (*RootDecoder, error) {
+	obj, err := dir.Get(rootTreeName)
+	if err != nil {
+		return nil, fmt.Errorf("hepmc: could not find HepMC3 tree: %w", err)
+	}
+
+	tree, ok := obj.(rtree.Tree)
+	if !ok {
+		return nil, fmt.Errorf("hepmc: object %q is not a tree (type=%s)", rootTreeName, obj.Class())
+	}
+
+	if tree.Branch(rootEvtName) == nil {
+		return nil, fmt.Errorf("hepmc: could not find branch %q in tree %q", rootEvtName, rootTreeName)
+	}
+
+	dec := &RootDecoder{}
+	vars := []rtree.ReadVar{{Name: rootEvtName, Value: &dec.evt}}
+	if tree.Branch(rootRunName) != nil {
+		vars = append(vars, rtree.ReadVar{Name: rootRunName, Value: &dec.run})
+	}
+
+	dec.sc, err = rtree.NewScannerVars(tree, vars...)
+	if err != nil {
+		return nil, fmt.Errorf("hepmc: could not create scanner: %w", err)
+	}
+
+	return dec, nil
+}
+
+// Decode reads the next event from the tree.
+// Decode returns io.EOF when no more events are available.
+func (dec *RootDecoder) Decode(evt *Event) error {
+	if !dec.sc.Next() {
+		err := dec.sc.Err()
+		if err == nil {
+			err = io.EOF
+		}
+		return err
+	}
+
+	err := dec.sc.Scan()
+	if err != nil {
+		return fmt.Errorf("hepmc: could not read entry %d: %w", dec.sc.Entry(), err)
+	}
+
+	data := &dec.evt
+	evt.EventNumber = int(data.ROOT_event_number)
+	evt.MomentumUnit = MomentumUnit(data.ROOT_momentum_unit)
+	evt.LengthUnit = LengthUnit(data.ROOT_length_unit)
+	evt.Weights = NewWeights()
+	evt.Weights.Slice = append([]float64(nil), data.ROOT_weights...)
+	evt.Vertices = make(map[int]*Vertex, len(data.ROOT_vertices))
+	evt.Particles = make(map[int]*Particle, len(data.ROOT_particles))
+
+	ev := v3event{
+		parts: make([]v3particle, len(data.ROOT_particles)),
+		verts: make([]v3vertex, len(data.ROOT_vertices)),
+		attrs: make([]v3attr, len(data.ROOT_attribute_id)),
+	}
+
+	for i, v := range data.ROOT_particles {
+		p := &Particle{
+			Momentum: fmom.NewPxPyPzE(v.ROOT_momentum.ROOT_m_v1, v.ROOT_momentum.ROOT_m_v2, v.ROOT_momentum.ROOT_m_v3, v.ROOT_momentum.ROOT_m_v4),
+			PdgID:    int64(v.ROOT_pid),
+			Status:   int(v.ROOT_status),
+			Barcode:  i + 1,
+		}
+		p.GeneratedMass = v.ROOT_mass
+		if !v.ROOT_is_mass_set {
+			p.GeneratedMass = p.Momentum.M()
+		}
+		p.Flow = Flow{Particle: p, Icode: make(map[int]int)}
+		ev.parts[i].p = p
+	}
+
+	for i, v := range data.ROOT_vertices {
+		ev.verts[i].v = &Vertex{
+			Position: fmom.NewPxPyPzE(v.ROOT_position.ROOT_m_v1, v.ROOT_position.ROOT_m_v2, v.ROOT_position.ROOT_m_v3, v.ROOT_position.ROOT_m_v4),
+			ID:       int(v.ROOT_status),
+			Weights:  Weights{Slice: make([]float64, 0)},
+			Barcode:  -(i + 1),
+		}
+	}
+
+	if len(data.ROOT_links1) != len(data.ROOT_links2) {
+		return fmt.Errorf("hepmc: invalid links in event %d (len1=%d, len2=%d)", evt.EventNumber, len(data.ROOT_links1), len(data.ROOT_links2))
+	}
+	for i, id1 := range data.ROOT_links1 {
+		var (
+			id1 = int(id1)
+			id2 = int(data.ROOT_links2[i])
+		)
+		switch {
+		case id1 > 0 && id1 <= len(ev.parts) && id2 < 0 && -id2 <= len(ev.verts):
+			v := &ev.verts[-id2-1]
+			v.in = append(v.in, id1)
+		case id1 < 0 && -id1 <= len(ev.verts) && id2 > 0 && id2 <= len(ev.parts):
+			ev.parts[id2-1].parent = id1
+		default:
+			return fmt.Errorf("hepmc: invalid link (%d, %d) in event %d", id1, id2, evt.EventNumber)
+		}
+	}
+
+	if n := len(data.ROOT_attribute_id); n != len(data.ROOT_attribute_name) || n != len(data.ROOT_attribute_string) {
+		return fmt.Errorf("hepmc: invalid attributes in event %d", evt.EventNumber)
+	}
+	for i, id := range data.ROOT_attribute_id {
+		ev.attrs[i] = v3attr{
+			id:    int(id),
+			name:  data.ROOT_attribute_name[i],
+			value: data.ROOT_attribute_string[i],
+		}
+	}
+
+	run, err := dec.runInfo()
+	if err != nil {
+		return fmt.Errorf("hepmc: invalid run information in event %d: %w", evt.EventNumber, err)
+	}
+
+	return buildV3(evt, &ev, run)
+}
+
+// runInfo returns the run information of the current event.
+// Events sharing the same run information share the same RunInfo value.
+func (dec *RootDecoder) runInfo() (*RunInfo, error) {
+	data := &dec.run
+	if dec.info != nil && reflect.DeepEqual(*data, dec.rdata) {
+		return dec.info, nil
+	}
+
+	if n := len(data.ROOT_tool_name); n != len(data.ROOT_tool_version) || n != len(data.ROOT_tool_description) {
+		return nil, fmt.Errorf("invalid tools")
+	}
+	if n := len(data.ROOT_attribute_name); n != len(data.ROOT_attribute_string) {
+		return nil, fmt.Errorf("invalid attributes")
+	}
+
+	dec.rdata = HepMC3__GenRunInfoData{
+		ROOT_weight_names:     append([]string(nil), data.ROOT_weight_names...),
+		ROOT_tool_name:        append([]string(nil), data.ROOT_tool_name...),
+		ROOT_tool_version:     append([]string(nil), data.ROOT_tool_version...),
+		ROOT_tool_description: append([]string(nil), data.ROOT_tool_description...),
+		ROOT_attribute_name:   append([]string(nil), data.ROOT_attribute_name...),
+		ROOT_attribute_string: append([]string(nil), data.ROOT_attribute_string...),
+	}
+	dec.info = nil
+
+	if len(data.ROOT_weight_names)+len(data.ROOT_tool_name)+len(data.ROOT_attribute_name) == 0 {
+		return nil, nil
+	}
+
+	run := &RunInfo{WeightNames: dec.rdata.ROOT_weight_names}
+	for i, name := range data.ROOT_tool_name {
+		run.Tools = append(run.Tools, Tool{
+			Name:        name,
+			Version:     data.ROOT_tool_version[i],
+			Description: data.ROOT_tool_description[i],
+		})
+	}
+	if len(data.ROOT_attribute_name) > 0 {
+		run.Attributes = make(map[string]string, len(data.ROOT_attribute_name))
+		for i, name := range data.ROOT_attribute_name {
+			run.Attributes[name] = data.ROOT_attribute_string[i]
+		}
+	}
+	dec.info = run
+
+	return run, nil
+}
+
+// Close closes the decoder and releases its resources.
+// Close does not close the underlying ROOT file.
+func (dec *RootDecoder) Close() error {
+	return dec.sc.Close()
+}
+
+// RootEncoder encodes hepmc.Event objects into a HepMC3 ROOT tree,
+// readable by HepMC3's ReaderRootTree.
+//
+// Events are written out in a single, not split, branch.
+type RootEncoder struct {
+	w   rtree.Writer
+	evt HepMC3__GenEventData
+	run HepMC3__GenRunInfoData
+}
+
+// NewRootEncoder returns a new encoder writing events into a HepMC3 ROOT
+// tree "hepmc3_tree" created in dir.
+func NewRootEncoder(dir riofs.Directory, opts ...rtree.WriteOption) (*RootEncoder, error) {
+	enc := &RootEncoder{}
+	w, err := rtree.NewWriter(dir, rootTreeName, []rtree.WriteVar{
+		{Name: rootEvtName, Value: &enc.evt},
+		{Name: rootRunName, Value: &enc.run},
+	}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("hepmc: could not create HepMC3 tree: %w", err)
+	}
+	enc.w = w
+	return enc, nil
+}
+
+// Encode writes evt into the tree.
+func (enc *RootEncoder) Encode(evt *Event) error {
+	parts, verts, pids, vids := v3ids(evt)
+
+	attrs, err := attributesOf(evt, pids, vids)
+	if err != nil {
+		return err
+	}
+
+	data := &enc.evt
+	data.ROOT_event_number = int32(evt.EventNumber)
+	data.ROOT_momentum_unit = int32(evt.MomentumUnit)
+	data.ROOT_length_unit = int32(evt.LengthUnit)
+	data.ROOT_weights = append(data.ROOT_weights[:0], evt.Weights.Slice...)
+
+	data.ROOT_particles = data.ROOT_particles[:0]
+	for _, p := range parts {
+		data.ROOT_particles = append(data.ROOT_particles, HepMC3__GenParticleData{
+			ROOT_pid:         int32(p.PdgID),
+			ROOT_status:      int32(p.Status),
+			ROOT_is_mass_set: true,
+			ROOT_mass:        p.GeneratedMass,
+			ROOT_momentum: HepMC3__FourVector{
+				ROOT_m_v1: p.Momentum.Px(),
+				ROOT_m_v2: p.Momentum.Py(),
+				ROOT_m_v3: p.Momentum.Pz(),
+				ROOT_m_v4: p.Momentum.E(),
+			},
+		})
+	}
+
+	data.ROOT_vertices = data.ROOT_vertices[:0]
+	data.ROOT_links1 = data.ROOT_links1[:0]
+	data.ROOT_links2 = data.ROOT_links2[:0]
+	for _, v := range verts {
+		vid := int32(vids[v])
+		data.ROOT_vertices = append(data.ROOT_vertices, HepMC3__GenVertexData{
+			ROOT_status: int32(v.ID),
+			ROOT_position: HepMC3__FourVector{
+				ROOT_m_v1: v.Position.X(),
+				ROOT_m_v2: v.Position.Y(),
+				ROOT_m_v3: v.Position.Z(),
+				ROOT_m_v4: v.Position.T(),
+			},
+		})
+		for _, p := range v.ParticlesIn {
+			pid, ok := pids[p]
+			if !ok {
+				return fmt.Errorf("hepmc: incoming particle %d of vertex %d not in event", p.Barcode, v.Barcode)
+			}
+			data.ROOT_links1 = append(data.ROOT_links1, int32(pid))
+			data.ROOT_links2 = append(data.ROOT_links2, vid)
+		}
+		for _, p := range v.ParticlesOut {
+			pid, ok := pids[p]
+			if !ok {
+				return fmt.Errorf("hepmc: outgoing particle %d of vertex %d not in event", p.Barcode, v.Barcode)
+			}
+			data.ROOT_links1 = append(data.ROOT_links1, vid)
+			data.ROOT_links2 = append(data.ROOT_links2, int32(pid))
+		}
+	}
+
+	data.ROOT_attribute_id = data.ROOT_attribute_id[:0]
+	data.ROOT_attribute_name = data.ROOT_attribute_name[:0]
+	data.ROOT_attribute_string = data.ROOT_attribute_string[:0]
+	for _, attr := range attrs {
+		data.ROOT_attribute_id = append(data.ROOT_attribute_id, int32(attr.id))
+		data.ROOT_attribute_name = append(data.ROOT_attribute_name, attr.name)
+		data.ROOT_attribute_string = append(data.ROOT_attribute_string, attr.value)
+	}
+
+	enc.run = HepMC3__GenRunInfoData{}
+	if run := runInfoOf(evt); run != nil {
+		enc.run.ROOT_weight_names = run.WeightNames
+		for _, tool := range run.Tools {
+			enc.run.ROOT_tool_name = append(enc.run.ROOT_tool_name, tool.Name)
+			enc.run.ROOT_tool_version = append(enc.run.ROOT_tool_version, tool.Version)
+			enc.run.ROOT_tool_description = append(enc.run.ROOT_tool_description, tool.Description)
+		}
+		names := make([]string, 0, len(run.Attributes))
+		for name := range run.Attributes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			enc.run.ROOT_attribute_name = append(enc.run.ROOT_attribute_name, name)
+			enc.run.ROOT_attribute_string = append(enc.run.ROOT_attribute_string, run.Attributes[name])
+		}
+	}
+
+	_, err = enc.w.Write()
+	if err != nil {
+		return fmt.Errorf("hepmc: could not write event %d: %w", evt.EventNumber, err)
+	}
+
+	return nil
+}
+
+// Close closes the encoder and flushes the tree to its directory.
+// Close does not close the underlying ROOT file.
+func (enc *RootEncoder) Close() error {
+	err := enc.w.Close()
+	if err != nil {
+		return fmt.Errorf("hepmc: could not close HepMC3 tree: %w", err)
+	}
+	return nil
+}
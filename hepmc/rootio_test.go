@@ -0,0 +1,128 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hepmc_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-hep.org/x/hep/groot"
+	"go-hep.org/x/hep/hepmc"
+)
+
+func TestRootRW(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "hepmc-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	for _, tc := range []struct {
+		name  string
+		fname string
+		nevts int
+	}{
+		{"v2-small", "testdata/small.hepmc", 1},
+		{"v2-test", "testdata/test.hepmc", 6},
+		{"v3-small", "testdata/small.hepmc3", 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := ioutil.ReadFile(tc.fname)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := convert(t, raw, hepmc.NewEncoderV3, tc.nevts)
+
+			oname := filepath.Join(tmp, tc.name+".root")
+			func() {
+				f, err := groot.Create(oname)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer f.Close()
+
+				enc, err := hepmc.NewRootEncoder(f)
+				if err != nil {
+					t.Fatalf("could not create encoder: %+v", err)
+				}
+
+				dec := hepmc.NewDecoder(bytes.NewReader(raw))
+				for i := 0; ; i++ {
+					var evt hepmc.Event
+					err := dec.Decode(&evt)
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						t.Fatalf("could not decode event %d: %+v", i, err)
+					}
+					err = enc.Encode(&evt)
+					if err != nil {
+						t.Fatalf("could not encode event %d: %+v", i, err)
+					}
+				}
+
+				err = enc.Close()
+				if err != nil {
+					t.Fatalf("could not close encoder: %+v", err)
+				}
+
+				err = f.Close()
+				if err != nil {
+					t.Fatalf("could not close file: %+v", err)
+				}
+			}()
+
+			f, err := groot.Open(oname)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			dec, err := hepmc.NewRootDecoder(f)
+			if err != nil {
+				t.Fatalf("could not create decoder: %+v", err)
+			}
+			defer dec.Close()
+
+			var (
+				out = new(bytes.Buffer)
+				enc = hepmc.NewEncoderV3(out)
+				n   = 0
+			)
+			for {
+				var evt hepmc.Event
+				err := dec.Decode(&evt)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("could not decode event %d: %+v", n, err)
+				}
+				err = enc.Encode(&evt)
+				if err != nil {
+					t.Fatalf("could not encode event %d: %+v", n, err)
+				}
+				n++
+			}
+			err = enc.Close()
+			if err != nil {
+				t.Fatalf("could not close encoder: %+v", err)
+			}
+
+			if n != tc.nevts {
+				t.Fatalf("invalid number of events: got=%d, want=%d", n, tc.nevts)
+			}
+
+			if got := out.Bytes(); !bytes.Equal(got, want) {
+				t.Fatalf("ROOT round-trip failed:\ngot:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
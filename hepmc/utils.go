@@ -58,3 +58,49 @@ func (t *tokens) int64() (int64, error) {
 func (t *tokens) String() string {
 	return strings.Join(t.toks, " ")
 }
+
+// escape escapes a HepMC3 string value: backslashes are doubled and
+// new lines are replaced with `\|`.
+func escape(s string) string {
+	if !strings.ContainsAny(s, "\\\n") {
+		return s
+	}
+	var o strings.Builder
+	o.Grow(len(s) + 8)
+	for _, c := range s {
+		switch c {
+		case '\\':
+			o.WriteString(`\\`)
+		case '\n':
+			o.WriteString(`\|`)
+		default:
+			o.WriteRune(c)
+		}
+	}
+	return o.String()
+}
+
+// unescape reverses the escaping of a HepMC3 string value.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var o strings.Builder
+	o.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			switch s[i+1] {
+			case '\\':
+				o.WriteByte('\\')
+				i++
+				continue
+			case '|':
+				o.WriteByte('\n')
+				i++
+				continue
+			}
+		}
+		o.WriteByte(s[i])
+	}
+	return o.String()
+}
@@ -0,0 +1,105 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heppdt
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Decay describes a decay channel of a particle.
+type Decay struct {
+	BR        float64 // branching ratio
+	Daughters []PID   // decay products
+}
+
+// ReadDecays reads decay tables from r, in the Pythia8 XML particle data
+// format (e.g. ParticleData.xml), and attaches them to the particles of
+// the table.
+//
+// Decays of antiparticles are derived from the ones of their particle,
+// by charge-conjugating the decay products.
+// Decays of particles that are not in the table are ignored.
+func (t *Table) ReadDecays(r io.Reader) error {
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return fmt.Errorf("heppdt: could not read XML token: %w", err)
+		}
+
+		elmt, ok := tok.(xml.StartElement)
+		if !ok || elmt.Name.Local != "particle" {
+			continue
+		}
+
+		var p xmlParticle
+		err = dec.DecodeElement(&p, &elmt)
+		if err != nil {
+			return fmt.Errorf("heppdt: could not decode XML particle: %w", err)
+		}
+
+		decays := make([]Decay, len(p.Channels))
+		for i, ch := range p.Channels {
+			decays[i].BR = ch.BR
+			for _, v := range strings.Fields(ch.Products) {
+				id, err := strconv.Atoi(v)
+				if err != nil {
+					return fmt.Errorf(
+						"heppdt: invalid decay product %q for particle %d: %w",
+						v, p.ID, err,
+					)
+				}
+				decays[i].Daughters = append(decays[i].Daughters, PID(id))
+			}
+		}
+
+		pid := PID(p.ID)
+		if part := t.ParticleByID(pid); part != nil {
+			part.Decays = decays
+		}
+
+		if p.AntiName == "" {
+			continue
+		}
+
+		part := t.ParticleByID(-pid)
+		if part == nil {
+			continue
+		}
+		part.Decays = make([]Decay, len(decays))
+		for i, d := range decays {
+			part.Decays[i] = Decay{
+				BR:        d.BR,
+				Daughters: make([]PID, len(d.Daughters)),
+			}
+			for j, id := range d.Daughters {
+				if !selfConjugate(id) {
+					id = -id
+				}
+				part.Decays[i].Daughters[j] = id
+			}
+		}
+	}
+
+	return nil
+}
+
+type xmlParticle struct {
+	ID       int          `xml:"id,attr"`
+	AntiName string       `xml:"antiName,attr"`
+	Channels []xmlChannel `xml:"channel"`
+}
+
+type xmlChannel struct {
+	BR       float64 `xml:"bRatio,attr"`
+	Products string  `xml:"products,attr"`
+}
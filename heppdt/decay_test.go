@@ -0,0 +1,134 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heppdt
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadDecays(t *testing.T) {
+	f, err := os.Open("testdata/mass_width_2020.mcd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	tbl, err := NewFromPDG(f, "mass_width_2020.mcd")
+	if err != nil {
+		t.Fatalf("could not parse PDG table: %+v", err)
+	}
+
+	xml, err := os.Open("testdata/ParticleData.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xml.Close()
+
+	err = tbl.ReadDecays(xml)
+	if err != nil {
+		t.Fatalf("could not read decays: %+v", err)
+	}
+
+	for _, tc := range []struct {
+		pid  PID
+		want []Decay
+	}{
+		{
+			pid: 111,
+			want: []Decay{
+				{BR: 0.98799, Daughters: []PID{22, 22}},
+				{BR: 0.01198, Daughters: []PID{22, 11, -11}},
+				{BR: 0.000033, Daughters: []PID{11, -11, 11, -11}},
+			},
+		},
+		{
+			pid: -15,
+			want: []Decay{
+				{BR: 0.1783, Daughters: []PID{-16, 12, -11}},
+				{BR: 0.1741, Daughters: []PID{-16, 14, -13}},
+				{BR: 0.1090, Daughters: []PID{-16, 211}},
+				{BR: 0.2520, Daughters: []PID{-16, 111, 211}},
+			},
+		},
+		{
+			pid: -24,
+			want: []Decay{
+				{BR: 0.321369, Daughters: []PID{1, -2}},
+				{BR: 0.320605, Daughters: []PID{3, -4}},
+				{BR: 0.10813, Daughters: []PID{11, -12}},
+				{BR: 0.10813, Daughters: []PID{13, -14}},
+			},
+		},
+		{
+			pid: 310,
+			want: []Decay{
+				{BR: 0.692, Daughters: []PID{211, -211}},
+				{BR: 0.3069, Daughters: []PID{111, 111}},
+			},
+		},
+		{
+			pid: -421,
+			want: []Decay{
+				{BR: 0.0389, Daughters: []PID{321, -211}},
+				{BR: 0.144, Daughters: []PID{321, -211, 111}},
+			},
+		},
+		{
+			pid: 22,
+		},
+		{
+			pid: 2212,
+		},
+	} {
+		t.Run(tbl.ParticleByID(tc.pid).Name, func(t *testing.T) {
+			p := tbl.ParticleByID(tc.pid)
+			if got, want := p.Decays, tc.want; !reflect.DeepEqual(got, want) {
+				t.Fatalf("invalid decays:\ngot= %v\nwant=%v", got, want)
+			}
+		})
+	}
+
+	if p := tbl.ParticleByID(3122); p != nil {
+		t.Fatalf("unexpected particle %d", p.ID)
+	}
+}
+
+func TestReadDecaysErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "invalid-xml",
+			data: `<particle id="111"><channel bRatio="1" products="22 22"/>`,
+			want: "heppdt: could not decode XML particle",
+		},
+		{
+			name: "invalid-id",
+			data: `<particle id="x111"></particle>`,
+			want: "heppdt: could not decode XML particle",
+		},
+		{
+			name: "invalid-product",
+			data: `<particle id="111"><channel bRatio="1" products="22 2x2"/></particle>`,
+			want: `heppdt: invalid decay product "2x2" for particle 111`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var tbl Table
+			err := tbl.ReadDecays(strings.NewReader(tc.data))
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if got, want := err.Error(), tc.want; !strings.HasPrefix(got, want) {
+				t.Fatalf("invalid error:\ngot= %v\nwant=%v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heppdt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// NewFromPDG returns a new particle data table, initialized from the
+// content of r, in the PDG machine-readable mass and width format
+// (e.g. mass_width_2020.mcd).
+//
+// Antiparticles are created for all the particles that are not
+// their own antiparticle.
+// Particle names follow the HepPDT convention (e.g. "pi^+", "K~^0").
+func NewFromPDG(r io.Reader, n string) (Table, error) {
+	t := Table{
+		name: n,
+		pdt:  make(map[PID]*Particle),
+		pid:  make(map[string]PID),
+	}
+	err := parsePDG(r, &t)
+	return t, err
+}
+
+// parsePDG fills a Table from the content of r, in the PDG
+// mass and width format.
+//
+// Data lines have the following fixed-column format:
+//
+//  Columns   1 -   8: Monte Carlo particle ID
+//  Columns   9 -  32: up to 3 more Monte Carlo particle IDs, or blank
+//  Columns  34 -  51: particle mass in GeV
+//  Columns  53 -  60: + error on mass
+//  Columns  62 -  69: - error on mass
+//  Columns  71 -  88: particle width in GeV
+//  Columns  90 -  97: + error on width
+//  Columns  99 - 106: - error on width
+//  Columns 108 - 128: particle name and comma-separated list of charges,
+//                     one for each Monte Carlo particle ID.
+func parsePDG(r io.Reader, table *Table) error {
+	s := bufio.NewScanner(r)
+	lineno := 0
+	for s.Scan() {
+		lineno++
+		line := strings.TrimRight(s.Text(), " \t\r\n")
+		if len(line) == 0 || line[0] == '*' {
+			continue
+		}
+
+		var ids []PID
+		for i := 0; i < 4; i++ {
+			v := column(line, 8*i+1, 8*i+8)
+			if v == "" {
+				continue
+			}
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("heppdt: line:%d: invalid particle ID: %w", lineno, err)
+			}
+			ids = append(ids, PID(id))
+		}
+		if len(ids) == 0 {
+			return fmt.Errorf("heppdt: malformed line:%d: %v", lineno, line)
+		}
+
+		mass, err := parseMeasurement(line, 34, 51, 53, 60, 62, 69)
+		if err != nil {
+			return fmt.Errorf("heppdt: line:%d: invalid mass: %w", lineno, err)
+		}
+
+		width, err := parseMeasurement(line, 71, 88, 90, 97, 99, 106)
+		if err != nil {
+			return fmt.Errorf("heppdt: line:%d: invalid width: %w", lineno, err)
+		}
+
+		toks := strings.Fields(column(line, 108, len(line)))
+		if len(toks) != 2 {
+			return fmt.Errorf("heppdt: malformed line:%d: %v", lineno, line)
+		}
+		name := toks[0]
+		charges := strings.Split(toks[1], ",")
+		if len(charges) != len(ids) {
+			return fmt.Errorf(
+				"heppdt: line:%d: mismatch number of IDs (%d) and charges (%d)",
+				lineno, len(ids), len(charges),
+			)
+		}
+
+		for i, pid := range ids {
+			q3, err := parseThreeCharge(charges[i])
+			if err != nil {
+				return fmt.Errorf("heppdt: line:%d: %w", lineno, err)
+			}
+
+			part := Particle{
+				ID:     pid,
+				Name:   pdgName(pid, name, charges[i], false),
+				PDG:    int(pid),
+				Mass:   mass.Value,
+				Charge: float64(q3) * onethird,
+				Resonance: Resonance{
+					Mass:  mass,
+					Width: width,
+				},
+			}
+			table.add(&part)
+
+			if selfConjugate(pid) {
+				continue
+			}
+
+			anti := part
+			anti.ID = -pid
+			anti.PDG = -part.PDG
+			anti.Name = pdgName(pid, name, charges[i], true)
+			anti.Charge = -part.Charge
+			table.add(&anti)
+		}
+	}
+
+	err := s.Err()
+	if err != nil {
+		return fmt.Errorf("heppdt: could not scan PDG table: %w", err)
+	}
+
+	return nil
+}
+
+// column returns the trimmed content of the 1-based, inclusive,
+// [beg, end] columns range of line.
+func column(line string, beg, end int) string {
+	if beg > len(line) {
+		return ""
+	}
+	if end > len(line) {
+		end = len(line)
+	}
+	return strings.TrimSpace(line[beg-1 : end])
+}
+
+func parseMeasurement(line string, beg, end, pbeg, pend, nbeg, nend int) (Measurement, error) {
+	var (
+		m   Measurement
+		err error
+	)
+
+	v := column(line, beg, end)
+	if v == "" {
+		return m, nil
+	}
+
+	m.Value, err = strconv.ParseFloat(v, 64)
+	if err != nil {
+		return m, err
+	}
+
+	var errs [2]float64
+	for i, cols := range [][2]int{{pbeg, pend}, {nbeg, nend}} {
+		v := column(line, cols[0], cols[1])
+		if v == "" {
+			continue
+		}
+		errs[i], err = strconv.ParseFloat(v, 64)
+		if err != nil {
+			return m, err
+		}
+	}
+
+	// symmetrize errors.
+	m.Sigma = 0.5 * (errs[0] - errs[1])
+	return m, nil
+}
+
+// parseThreeCharge returns 3 times the charge described by the
+// provided PDG charge string (e.g. "+", "--", "0", "-1/3").
+func parseThreeCharge(v string) (int, error) {
+	switch v {
+	case "0":
+		return 0, nil
+	case "+", "++", "+++":
+		return 3 * len(v), nil
+	case "-", "--", "---":
+		return -3 * len(v), nil
+	}
+
+	i := strings.Index(v, "/")
+	if i < 0 {
+		return 0, fmt.Errorf("invalid charge %q", v)
+	}
+
+	num, err := strconv.Atoi(strings.TrimPrefix(v[:i], "+"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid charge %q: %w", v, err)
+	}
+	den, err := strconv.Atoi(v[i+1:])
+	if err != nil || den == 0 || (3*num)%den != 0 {
+		return 0, fmt.Errorf("invalid charge %q", v)
+	}
+	return 3 * num / den, nil
+}
+
+// pdgName returns the HepPDT-like name of a particle (or of its antiparticle),
+// from its PDG name and charge.
+func pdgName(pid PID, name, charge string, anti bool) string {
+	if anti {
+		if pid.IsBaryon() || !strings.ContainsAny(charge, "+-") || strings.Contains(charge, "/") {
+			name += "~"
+		}
+		charge = strings.NewReplacer("+", "-", "-", "+").Replace(charge)
+	}
+
+	ida := pid.AbsPID()
+	switch {
+	case strings.Contains(charge, "/"):
+		// quarks.
+		return name
+	case charge == "0" && (ida == PDG_g || ida == PDG_gamma || pid.IsLepton()):
+		// gluon, photon and neutrinos.
+		return name
+	}
+	return name + "^" + charge
+}
+
+// selfConjugate returns whether the particle is its own antiparticle.
+func selfConjugate(pid PID) bool {
+	if pid.threeCharge() != 0 {
+		return false
+	}
+
+	ida := pid.AbsPID()
+	switch {
+	case ida <= 8, pid.IsLepton(), pid.IsBaryon(), pid.IsDiQuark(), pid.IsNucleus():
+		return false
+	case ida == 130 || ida == 310:
+		// K_L^0, K_S^0
+		return true
+	case pid.IsMeson():
+		return pid.Digit(Nq2) == pid.Digit(Nq3)
+	}
+	return true
+}
@@ -0,0 +1,143 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heppdt
+
+import (
+	"math"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNewFromPDG(t *testing.T) {
+	f, err := os.Open("testdata/mass_width_2020.mcd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	tbl, err := NewFromPDG(f, "mass_width_2020.mcd")
+	if err != nil {
+		t.Fatalf("could not parse PDG table: %+v", err)
+	}
+
+	if got, want := tbl.Name(), "mass_width_2020.mcd"; got != want {
+		t.Fatalf("invalid table name: got=%q, want=%q", got, want)
+	}
+
+	if got, want := tbl.Len(), 47; got != want {
+		t.Fatalf("invalid table length: got=%d, want=%d", got, want)
+	}
+
+	for _, tc := range []struct {
+		pid    PID
+		name   string
+		mass   float64
+		width  float64
+		charge float64
+	}{
+		{pid: 1, name: "d", mass: 4.67e-3, charge: -1. / 3.},
+		{pid: -2, name: "u~", mass: 2.16e-3, charge: -2. / 3.},
+		{pid: 11, name: "e^-", mass: 5.10998950e-4, charge: -1},
+		{pid: -11, name: "e^+", mass: 5.10998950e-4, charge: +1},
+		{pid: 22, name: "gamma"},
+		{pid: 23, name: "Z^0", mass: 91.1876, width: 2.4952},
+		{pid: -24, name: "W^-", mass: 80.379, width: 2.085, charge: -1},
+		{pid: 111, name: "pi^0", mass: 0.1349768, width: 7.81e-9},
+		{pid: -211, name: "pi^-", mass: 0.13957039, width: 2.5284e-17, charge: -1},
+		{pid: 113, name: "rho(770)^0", mass: 0.77526, width: 0.1491},
+		{pid: 213, name: "rho(770)^+", mass: 0.77526, width: 0.1491, charge: +1},
+		{pid: -311, name: "K~^0", mass: 0.497611},
+		{pid: 310, name: "K(S)^0", mass: 0.497611, width: 7.3508e-15},
+		{pid: -421, name: "D~^0", mass: 1.86483, width: 1.605e-12},
+		{pid: -2212, name: "p~^-", mass: 0.93827208816, charge: -1},
+		{pid: 2224, name: "Delta(1232)^++", mass: 1.232, width: 0.117, charge: +2},
+		{pid: -1114, name: "Delta(1232)~^+", mass: 1.232, width: 0.117, charge: +1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := tbl.ParticleByID(tc.pid)
+			if p == nil {
+				t.Fatalf("could not find particle %d", tc.pid)
+			}
+			if got, want := p.Name, tc.name; got != want {
+				t.Fatalf("invalid name: got=%q, want=%q", got, want)
+			}
+			if got := tbl.ParticleByName(tc.name); got != p {
+				t.Fatalf("invalid particle by name: got=%v, want=%v", got, p)
+			}
+			if got, want := p.PDG, int(tc.pid); got != want {
+				t.Fatalf("invalid PDG code: got=%d, want=%d", got, want)
+			}
+			if got, want := p.Mass, tc.mass; got != want {
+				t.Fatalf("invalid mass: got=%v, want=%v", got, want)
+			}
+			if got, want := p.Resonance.Width.Value, tc.width; got != want {
+				t.Fatalf("invalid width: got=%v, want=%v", got, want)
+			}
+			if got, want := p.Charge, tc.charge; math.Abs(got-want) > 1e-15 {
+				t.Fatalf("invalid charge: got=%v, want=%v", got, want)
+			}
+		})
+	}
+
+	for _, pid := range []PID{-22, -23, -111, -113, -130, -310, -443} {
+		if p := tbl.ParticleByID(pid); p != nil {
+			t.Fatalf("unexpected antiparticle %d: %v", pid, p.Name)
+		}
+	}
+
+	p := tbl.ParticleByID(6)
+	if got, want := p.Resonance.Mass.Sigma, 0.3; math.Abs(got-want) > 1e-15 {
+		t.Fatalf("invalid top mass error: got=%v, want=%v", got, want)
+	}
+	if got, want := p.Resonance.Width.Sigma, 0.17; math.Abs(got-want) > 1e-15 {
+		t.Fatalf("invalid top width error: got=%v, want=%v", got, want)
+	}
+}
+
+func TestNewFromPDGErrors(t *testing.T) {
+	const line = "     211                         1.3957039E-01      +1.8E-07 -1.8E-07 2.5284E-17         +5.0E-21 -5.0E-21 pi               +"
+	for _, tc := range []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "invalid-id",
+			data: strings.Replace(line, "211", "2x1", 1),
+			want: "heppdt: line:1: invalid particle ID",
+		},
+		{
+			name: "invalid-mass",
+			data: strings.Replace(line, "1.3957039E-01", "1.3957039X-01", 1),
+			want: "heppdt: line:1: invalid mass",
+		},
+		{
+			name: "invalid-charge",
+			data: strings.Replace(line, "pi               +", "pi               x", 1),
+			want: `heppdt: line:1: invalid charge "x"`,
+		},
+		{
+			name: "mismatch-charges",
+			data: strings.Replace(line, "pi               +", "pi               +,0", 1),
+			want: "heppdt: line:1: mismatch number of IDs (1) and charges (2)",
+		},
+		{
+			name: "missing-name",
+			data: line[:100],
+			want: "heppdt: malformed line:1",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewFromPDG(strings.NewReader(tc.data), tc.name)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if got, want := err.Error(), tc.want; !strings.HasPrefix(got, want) {
+				t.Fatalf("invalid error:\ngot= %v\nwant=%v", got, want)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ type Particle struct {
 	Spin        SpinState     // spin state
 	Quarks      []Constituent // constituents
 	Resonance   Resonance     // resonance
+	Decays      []Decay       // decay channels
 }
 
 // IsStable returns whether this particle is stable
@@ -67,6 +67,31 @@ func (t *Table) ParticleByName(n string) *Particle {
 	return p
 }
 
+// Merge overlays the particles of o on top of the ones of t.
+// Particles of o with the same ID than a particle of t replace it.
+func (t *Table) Merge(o Table) {
+	if t.pdt == nil {
+		t.pdt = make(map[PID]*Particle, len(o.pdt))
+	}
+	if t.pid == nil {
+		t.pid = make(map[string]PID, len(o.pid))
+	}
+
+	for _, p := range o.pdt {
+		p := *p
+		t.add(&p)
+	}
+}
+
+// add adds p to the table, replacing any particle with the same ID.
+func (t *Table) add(p *Particle) {
+	if old, ok := t.pdt[p.ID]; ok && t.pid[old.Name] == old.ID {
+		delete(t.pid, old.Name)
+	}
+	t.pdt[p.ID] = p
+	t.pid[p.Name] = p.ID
+}
+
 func init() {
 	var err error
 	defaultTable, err = New(bytes.NewBufferString(tabledata), "particle.tbl")
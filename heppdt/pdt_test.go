@@ -5,7 +5,9 @@
 package heppdt
 
 import (
+	"bytes"
 	"math"
+	"strings"
 	"testing"
 )
 
@@ -94,3 +96,55 @@ func TestLocation(t *testing.T) {
 	}
 
 }
+
+func TestTableMerge(t *testing.T) {
+	tbl, err := New(bytes.NewBufferString(tabledata), "particle.tbl")
+	if err != nil {
+		t.Fatalf("could not create table: %+v", err)
+	}
+
+	custom, err := New(strings.NewReader(`
+//       ID name                  chg       mass    total width   lifetime
+          6 t                       2    172.76000     1.42000   0.00000E+00
+    9000006 X                       0   1000.00000    10.00000   0.00000E+00
+`), "custom.tbl")
+	if err != nil {
+		t.Fatalf("could not create custom table: %+v", err)
+	}
+
+	tbl.Merge(custom)
+
+	if got, want := tbl.Len(), defaultTable.Len()+1; got != want {
+		t.Fatalf("invalid table length: got=%d, want=%d", got, want)
+	}
+
+	p := tbl.ParticleByID(6)
+	if got, want := p.Mass, 172.76; got != want {
+		t.Fatalf("invalid top mass: got=%v, want=%v", got, want)
+	}
+	if got, want := tbl.ParticleByName("t"), p; got != want {
+		t.Fatalf("invalid top particle by name: got=%v, want=%v", got, want)
+	}
+	if got, want := defaultTable.ParticleByID(6).Mass, 175.0; got != want {
+		t.Fatalf("merge modified the default table: got=%v, want=%v", got, want)
+	}
+
+	p = tbl.ParticleByName("X")
+	if p == nil {
+		t.Fatalf("could not find custom particle")
+	}
+	if got, want := p.ID, PID(9000006); got != want {
+		t.Fatalf("invalid custom particle ID: got=%d, want=%d", got, want)
+	}
+
+	p.Mass = 2000
+	if got, want := custom.ParticleByID(9000006).Mass, 1000.0; got != want {
+		t.Fatalf("merge shared particles between tables: got=%v, want=%v", got, want)
+	}
+
+	var empty Table
+	empty.Merge(custom)
+	if got, want := empty.Len(), 2; got != want {
+		t.Fatalf("invalid table length: got=%d, want=%d", got, want)
+	}
+}
@@ -6,6 +6,13 @@
 //
 // The default behaviour is to only display RunHeaders and EventHeaders.
 // Events' contents can be printed out with the --print-event flag.
+//
+// A single event can be displayed with the --run and --evt flags, using the
+// random access records of the LCIO file to directly jump to that event.
+//
+// Example:
+//
+//  $> lcio-ls -run=42 -evt=52 -print-event ./file.slcio
 package main
 
 import (
@@ -27,6 +34,8 @@ func main() {
 		fname      = ""
 		nevts      = flag.Int64("n", -1, "number of events to inspect")
 		printEvent = flag.Bool("print-event", false, "enable event(s) printout")
+		run        = flag.Int("run", -1, "run number of the event to display")
+		evt        = flag.Int("evt", -1, "event number of the event to display")
 	)
 
 	flag.Parse()
@@ -39,7 +48,15 @@ func main() {
 		flag.Usage()
 		os.Exit(1)
 	}
-	inspect(os.Stdout, fname, *nevts, *printEvent)
+
+	switch {
+	case *run >= 0 && *evt >= 0:
+		inspectEvent(os.Stdout, fname, int32(*run), int32(*evt), *printEvent)
+	case *run >= 0 || *evt >= 0:
+		log.Fatalf("-run and -evt must be both provided")
+	default:
+		inspect(os.Stdout, fname, *nevts, *printEvent)
+	}
 }
 
 func inspect(w io.Writer, fname string, nevts int64, printEvent bool) {
@@ -84,3 +101,27 @@ func inspect(w io.Writer, fname string, nevts int64, printEvent bool) {
 		log.Fatal(err)
 	}
 }
+
+func inspectEvent(w io.Writer, fname string, run, evt int32, printEvent bool) {
+	log.Printf("inspecting file [%s] (run=%d, evt=%d)...", fname, run, evt)
+	r, err := lcio.Open(fname)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer r.Close()
+
+	err = r.SeekEvent(run, evt)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rhdr := r.RunHeader()
+	fmt.Fprintf(w, "%v\n", &rhdr)
+	if printEvent {
+		evt := r.Event()
+		fmt.Fprintf(w, "%v\n", &evt)
+		return
+	}
+	ehdr := r.EventHeader()
+	fmt.Fprintf(w, "%v\n", &ehdr)
+}
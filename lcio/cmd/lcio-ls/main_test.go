@@ -31,3 +31,18 @@ func TestInspect(t *testing.T) {
 		})
 	}
 }
+
+func TestInspectEvent(t *testing.T) {
+	const fname = "../../testdata/event_golden.slcio"
+	buf := new(bytes.Buffer)
+	inspectEvent(buf, fname, 42, 52, false)
+
+	got := buf.Bytes()
+	want, err := ioutil.ReadFile(fname + ".txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("error.\ngot = %q\nwant= %q\n", string(got), string(want))
+	}
+}
@@ -0,0 +1,500 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"go-hep.org/x/hep/lcio"
+)
+
+// association describes how a LCIO LCRelation collection is converted to an
+// EDM4hep association collection.
+type association struct {
+	rec  string // LCIO type of the reconstructed objects
+	swap bool   // whether the LCIO relation goes from sim to rec
+}
+
+// converter converts LCIO events into EDM4hep events.
+//
+// The list of converted collections is fixed by the first event:
+// collections missing from later events are written out empty and
+// new collections are ignored.
+type converter struct {
+	names []string // names of the converted LCIO collections
+	assoc map[string]association
+
+	objs map[interface{}]interface{} // EDM4hep objects of the LCIO elements
+}
+
+func newConverter(evt *lcio.Event) *converter {
+	cnv := &converter{
+		assoc: make(map[string]association),
+		objs:  make(map[interface{}]interface{}),
+	}
+
+	for _, name := range evt.Names() {
+		switch src := evt.Get(name).(type) {
+		case *lcio.McParticleContainer,
+			*lcio.SimTrackerHitContainer,
+			*lcio.SimCalorimeterHitContainer,
+			*lcio.TrackContainer,
+			*lcio.ClusterContainer,
+			*lcio.RecParticleContainer:
+			cnv.names = append(cnv.names, name)
+
+		case *lcio.RelationContainer:
+			var (
+				from = relType(src.Params, "FromType")
+				to   = relType(src.Params, "ToType")
+				swap = from == "MCParticle"
+				rec  = from
+			)
+			if swap {
+				rec = to
+			}
+			if from != "MCParticle" && to != "MCParticle" || !isRecType(rec) {
+				// no EDM4hep association for these relations.
+				log.Printf("skipping LCRelation collection %q (from=%q, to=%q)", name, from, to)
+				continue
+			}
+			cnv.names = append(cnv.names, name)
+			cnv.assoc[name] = association{rec: rec, swap: swap}
+
+		default:
+			log.Printf("skipping collection %q (type=%T)", name, src)
+		}
+	}
+
+	return cnv
+}
+
+// isRecType returns whether the provided LCIO type can be the rec end of an
+// EDM4hep MC association.
+func isRecType(typ string) bool {
+	switch typ {
+	case "ReconstructedParticle", "Track", "Cluster":
+		return true
+	}
+	return false
+}
+
+func relType(params lcio.Params, key string) string {
+	if v := params.Strings[key]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// convert converts the provided LCIO event.
+func (cnv *converter) convert(evt *lcio.Event) (*Event, error) {
+	for k := range cnv.objs {
+		delete(cnv.objs, k)
+	}
+
+	out := new(Event)
+	out.Add("EventHeader", &EventHeaderCollection{
+		Elems: []*EventHeader{{
+			EventNumber: evt.EventNumber,
+			RunNumber:   evt.RunNumber,
+			TimeStamp:   uint64(evt.TimeStamp),
+			Weight:      float32(evt.Weight()),
+		}},
+	})
+
+	// first, create the EDM4hep objects of all the converted collections,
+	// so relations between objects can be resolved.
+	for _, name := range cnv.names {
+		if !evt.Has(name) {
+			continue
+		}
+		var err error
+		switch src := evt.Get(name).(type) {
+		case *lcio.McParticleContainer:
+			cnv.convertMCParticles(out, name, src)
+		case *lcio.SimTrackerHitContainer:
+			cnv.convertSimTrackerHits(out, name, src)
+		case *lcio.SimCalorimeterHitContainer:
+			cnv.convertSimCaloHits(out, name, src)
+		case *lcio.TrackContainer:
+			cnv.convertTracks(out, name, src)
+		case *lcio.ClusterContainer:
+			cnv.convertClusters(out, name, src)
+		case *lcio.RecParticleContainer:
+			cnv.convertRecParticles(out, name, src)
+		case *lcio.RelationContainer:
+			err = cnv.convertRelations(out, name, src)
+		default:
+			err = fmt.Errorf("invalid type %T for collection %q", src, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// then, resolve the relations between objects.
+	for _, name := range cnv.names {
+		if !evt.Has(name) {
+			continue
+		}
+		switch src := evt.Get(name).(type) {
+		case *lcio.McParticleContainer:
+			cnv.linkMCParticles(src)
+		case *lcio.SimTrackerHitContainer:
+			cnv.linkSimTrackerHits(src)
+		case *lcio.SimCalorimeterHitContainer:
+			cnv.linkSimCaloHits(src)
+		case *lcio.TrackContainer:
+			cnv.linkTracks(src)
+		case *lcio.ClusterContainer:
+			cnv.linkClusters(src)
+		case *lcio.RecParticleContainer:
+			cnv.linkRecParticles(src)
+		case *lcio.RelationContainer:
+			cnv.linkRelations(out, name, src)
+		}
+	}
+
+	return out, nil
+}
+
+func (cnv *converter) convertMCParticles(out *Event, name string, src *lcio.McParticleContainer) {
+	coll := &MCParticleCollection{Elems: make([]*MCParticle, len(src.Particles))}
+	for i := range src.Particles {
+		p := &src.Particles[i]
+		end := p.EndPoint()
+		o := &MCParticle{
+			PDG:                p.PDG,
+			GeneratorStatus:    p.GenStatus,
+			SimulatorStatus:    int32(p.SimStatus),
+			Charge:             p.Charge,
+			Time:               p.Time,
+			Mass:               p.Mass,
+			Vertex:             Vector3d{p.Vertex[0], p.Vertex[1], p.Vertex[2]},
+			Endpoint:           Vector3d{end[0], end[1], end[2]},
+			Momentum:           Vector3f{float32(p.P[0]), float32(p.P[1]), float32(p.P[2])},
+			MomentumAtEndpoint: Vector3f{float32(p.PEndPoint[0]), float32(p.PEndPoint[1]), float32(p.PEndPoint[2])},
+			Spin:               Vector3f{p.Spin[0], p.Spin[1], p.Spin[2]},
+			ColorFlow:          Vector2i{p.ColorFlow[0], p.ColorFlow[1]},
+		}
+		cnv.objs[p] = o
+		coll.Elems[i] = o
+	}
+	out.Add(name, coll)
+}
+
+func (cnv *converter) linkMCParticles(src *lcio.McParticleContainer) {
+	for i := range src.Particles {
+		p := &src.Particles[i]
+		o := cnv.objs[p].(*MCParticle)
+		for _, mom := range p.Parents {
+			if v, ok := cnv.objs[mom].(*MCParticle); ok {
+				o.Parents = append(o.Parents, v)
+			}
+		}
+		for _, child := range p.Children {
+			if v, ok := cnv.objs[child].(*MCParticle); ok {
+				o.Daughters = append(o.Daughters, v)
+			}
+		}
+	}
+}
+
+func (cnv *converter) convertSimTrackerHits(out *Event, name string, src *lcio.SimTrackerHitContainer) {
+	coll := &SimTrackerHitCollection{Elems: make([]*SimTrackerHit, len(src.Hits))}
+	for i := range src.Hits {
+		hit := &src.Hits[i]
+		o := &SimTrackerHit{
+			CellID:     cellID(hit.CellID0, hit.CellID1),
+			EDep:       hit.EDep,
+			Time:       hit.Time,
+			PathLength: hit.PathLength,
+			Quality:    hit.Quality,
+			Position:   Vector3d{hit.Pos[0], hit.Pos[1], hit.Pos[2]},
+			Momentum:   Vector3f{hit.Momentum[0], hit.Momentum[1], hit.Momentum[2]},
+		}
+		cnv.objs[hit] = o
+		coll.Elems[i] = o
+	}
+	out.Add(name, coll)
+}
+
+func (cnv *converter) linkSimTrackerHits(src *lcio.SimTrackerHitContainer) {
+	for i := range src.Hits {
+		hit := &src.Hits[i]
+		o := cnv.objs[hit].(*SimTrackerHit)
+		o.MCParticle, _ = cnv.objs[hit.Mc].(*MCParticle)
+	}
+}
+
+// convertSimCaloHits converts the provided LCIO hits and stores their
+// contributions in the name+"Contributions" collection.
+func (cnv *converter) convertSimCaloHits(out *Event, name string, src *lcio.SimCalorimeterHitContainer) {
+	var (
+		coll     = &SimCalorimeterHitCollection{Elems: make([]*SimCalorimeterHit, len(src.Hits))}
+		contribs = &CaloHitContributionCollection{Elems: []*CaloHitContribution{}}
+	)
+	for i := range src.Hits {
+		hit := &src.Hits[i]
+		o := &SimCalorimeterHit{
+			CellID:   cellID(hit.CellID0, hit.CellID1),
+			Energy:   hit.Energy,
+			Position: Vector3f{hit.Pos[0], hit.Pos[1], hit.Pos[2]},
+		}
+		for j := range hit.Contributions {
+			c := &hit.Contributions[j]
+			v := &CaloHitContribution{
+				PDG:          c.PDG,
+				Energy:       c.Energy,
+				Time:         c.Time,
+				StepPosition: Vector3f{c.StepPos[0], c.StepPos[1], c.StepPos[2]},
+			}
+			cnv.objs[c] = v
+			o.Contributions = append(o.Contributions, v)
+			contribs.Elems = append(contribs.Elems, v)
+		}
+		cnv.objs[hit] = o
+		coll.Elems[i] = o
+	}
+	out.Add(name, coll)
+	out.Add(name+"Contributions", contribs)
+}
+
+func (cnv *converter) linkSimCaloHits(src *lcio.SimCalorimeterHitContainer) {
+	for i := range src.Hits {
+		hit := &src.Hits[i]
+		for j := range hit.Contributions {
+			c := &hit.Contributions[j]
+			o := cnv.objs[c].(*CaloHitContribution)
+			o.Particle, _ = cnv.objs[c.Mc].(*MCParticle)
+		}
+	}
+}
+
+func (cnv *converter) convertTracks(out *Event, name string, src *lcio.TrackContainer) {
+	coll := &TrackCollection{Elems: make([]*Track, len(src.Tracks))}
+	for i := range src.Tracks {
+		trk := &src.Tracks[i]
+		o := &Track{
+			Type:                  trk.Type,
+			Chi2:                  trk.Chi2,
+			Ndf:                   trk.NdF,
+			DEdx:                  trk.DEdx,
+			DEdxError:             trk.DEdxErr,
+			RadiusOfInnermostHit:  trk.Radius,
+			SubDetectorHitNumbers: append([]int32(nil), trk.SubDetHits...),
+		}
+		for _, s := range trk.States {
+			o.TrackStates = append(o.TrackStates, TrackState{
+				Location:       s.Loc,
+				D0:             s.D0,
+				Phi:            s.Phi,
+				Omega:          s.Omega,
+				Z0:             s.Z0,
+				TanLambda:      s.TanL,
+				ReferencePoint: Vector3f{s.Ref[0], s.Ref[1], s.Ref[2]},
+				CovMatrix:      s.Cov,
+			})
+		}
+		cnv.objs[trk] = o
+		coll.Elems[i] = o
+	}
+	out.Add(name, coll)
+}
+
+func (cnv *converter) linkTracks(src *lcio.TrackContainer) {
+	for i := range src.Tracks {
+		trk := &src.Tracks[i]
+		o := cnv.objs[trk].(*Track)
+		for _, hit := range trk.Hits {
+			if v, ok := cnv.objs[hit].(*TrackerHit); ok {
+				o.TrackerHits = append(o.TrackerHits, v)
+			}
+		}
+		for _, sub := range trk.Tracks {
+			if v, ok := cnv.objs[sub].(*Track); ok {
+				o.Tracks = append(o.Tracks, v)
+			}
+		}
+	}
+}
+
+// convertClusters converts the provided LCIO clusters and stores their
+// particle IDs in the name+"ParticleIDs" collection.
+func (cnv *converter) convertClusters(out *Event, name string, src *lcio.ClusterContainer) {
+	var (
+		coll = &ClusterCollection{Elems: make([]*Cluster, len(src.Clusters))}
+		pids = &ParticleIDCollection{Elems: []*ParticleID{}}
+	)
+	for i := range src.Clusters {
+		clu := &src.Clusters[i]
+		o := &Cluster{
+			Type:                clu.Type,
+			Energy:              clu.Energy,
+			EnergyError:         clu.EnergyErr,
+			Position:            Vector3f{clu.Pos[0], clu.Pos[1], clu.Pos[2]},
+			PositionError:       clu.PosErr,
+			ITheta:              clu.Theta,
+			Phi:                 clu.Phi,
+			DirectionError:      Vector3f{clu.DirErr[0], clu.DirErr[1], clu.DirErr[2]},
+			ShapeParameters:     append([]float32(nil), clu.Shape...),
+			SubdetectorEnergies: append([]float32(nil), clu.SubDetEnes...),
+		}
+		for j := range clu.PIDs {
+			pid := cnv.convertPID(&clu.PIDs[j])
+			o.ParticleIDs = append(o.ParticleIDs, pid)
+			pids.Elems = append(pids.Elems, pid)
+		}
+		cnv.objs[clu] = o
+		coll.Elems[i] = o
+	}
+	out.Add(name, coll)
+	out.Add(name+"ParticleIDs", pids)
+}
+
+func (cnv *converter) linkClusters(src *lcio.ClusterContainer) {
+	for i := range src.Clusters {
+		clu := &src.Clusters[i]
+		o := cnv.objs[clu].(*Cluster)
+		for _, sub := range clu.Clusters {
+			if v, ok := cnv.objs[sub].(*Cluster); ok {
+				o.Clusters = append(o.Clusters, v)
+			}
+		}
+		for _, hit := range clu.Hits {
+			if v, ok := cnv.objs[hit].(*CalorimeterHit); ok {
+				o.Hits = append(o.Hits, v)
+			}
+		}
+	}
+}
+
+// convertRecParticles converts the provided LCIO reconstructed particles
+// and stores their particle IDs in the name+"ParticleIDs" collection.
+func (cnv *converter) convertRecParticles(out *Event, name string, src *lcio.RecParticleContainer) {
+	var (
+		coll = &ReconstructedParticleCollection{Elems: make([]*ReconstructedParticle, len(src.Parts))}
+		pids = &ParticleIDCollection{Elems: []*ParticleID{}}
+	)
+	for i := range src.Parts {
+		rp := &src.Parts[i]
+		o := &ReconstructedParticle{
+			Type:           rp.Type,
+			Energy:         rp.Energy,
+			Momentum:       Vector3f{rp.P[0], rp.P[1], rp.P[2]},
+			ReferencePoint: Vector3f{rp.Ref[0], rp.Ref[1], rp.Ref[2]},
+			Charge:         rp.Charge,
+			Mass:           rp.Mass,
+			GoodnessOfPID:  rp.GoodnessOfPID,
+			CovMatrix:      rp.Cov,
+		}
+		for j := range rp.PIDs {
+			pid := cnv.convertPID(&rp.PIDs[j])
+			o.ParticleIDs = append(o.ParticleIDs, pid)
+			pids.Elems = append(pids.Elems, pid)
+		}
+		cnv.objs[rp] = o
+		coll.Elems[i] = o
+	}
+	out.Add(name, coll)
+	out.Add(name+"ParticleIDs", pids)
+}
+
+func (cnv *converter) linkRecParticles(src *lcio.RecParticleContainer) {
+	for i := range src.Parts {
+		rp := &src.Parts[i]
+		o := cnv.objs[rp].(*ReconstructedParticle)
+		for _, clu := range rp.Clusters {
+			if v, ok := cnv.objs[clu].(*Cluster); ok {
+				o.Clusters = append(o.Clusters, v)
+			}
+		}
+		for _, trk := range rp.Tracks {
+			if v, ok := cnv.objs[trk].(*Track); ok {
+				o.Tracks = append(o.Tracks, v)
+			}
+		}
+		for _, sub := range rp.Recs {
+			if v, ok := cnv.objs[sub].(*ReconstructedParticle); ok {
+				o.Particles = append(o.Particles, v)
+			}
+		}
+		o.StartVertex, _ = cnv.objs[rp.StartVtx].(*Vertex)
+		o.ParticleIDUsed, _ = cnv.objs[rp.PIDUsed].(*ParticleID)
+	}
+}
+
+// convertPID converts the provided LCIO particle ID.
+func (cnv *converter) convertPID(pid *lcio.ParticleID) *ParticleID {
+	o := &ParticleID{
+		Type:          pid.Type,
+		PDG:           pid.PDG,
+		AlgorithmType: pid.AlgType,
+		Likelihood:    pid.Likelihood,
+		Parameters:    append([]float32(nil), pid.Params...),
+	}
+	cnv.objs[pid] = o
+	return o
+}
+
+// convertRelations creates the EDM4hep associations of the provided
+// LCIO relations. Their rec and sim ends are set by linkRelations.
+func (cnv *converter) convertRelations(out *Event, name string, src *lcio.RelationContainer) error {
+	switch assoc := cnv.assoc[name]; assoc.rec {
+	case "ReconstructedParticle":
+		coll := &MCRecoParticleAssociationCollection{Elems: make([]*MCRecoParticleAssociation, len(src.Rels))}
+		for i, rel := range src.Rels {
+			coll.Elems[i] = &MCRecoParticleAssociation{Weight: rel.Weight}
+		}
+		out.Add(name, coll)
+	case "Track":
+		coll := &MCRecoTrackParticleAssociationCollection{Elems: make([]*MCRecoTrackParticleAssociation, len(src.Rels))}
+		for i, rel := range src.Rels {
+			coll.Elems[i] = &MCRecoTrackParticleAssociation{Weight: rel.Weight}
+		}
+		out.Add(name, coll)
+	case "Cluster":
+		coll := &MCRecoClusterParticleAssociationCollection{Elems: make([]*MCRecoClusterParticleAssociation, len(src.Rels))}
+		for i, rel := range src.Rels {
+			coll.Elems[i] = &MCRecoClusterParticleAssociation{Weight: rel.Weight}
+		}
+		out.Add(name, coll)
+	default:
+		return fmt.Errorf("invalid association type %q for collection %q", assoc.rec, name)
+	}
+	return nil
+}
+
+func (cnv *converter) linkRelations(out *Event, name string, src *lcio.RelationContainer) {
+	assoc := cnv.assoc[name]
+	for i := range src.Rels {
+		rel := &src.Rels[i]
+		rec, sim := rel.From, rel.To
+		if assoc.swap {
+			rec, sim = sim, rec
+		}
+		mc, _ := cnv.objs[sim].(*MCParticle)
+		switch coll := out.Get(name).(type) {
+		case *MCRecoParticleAssociationCollection:
+			o := coll.Elems[i]
+			o.Rec, _ = cnv.objs[rec].(*ReconstructedParticle)
+			o.Sim = mc
+		case *MCRecoTrackParticleAssociationCollection:
+			o := coll.Elems[i]
+			o.Rec, _ = cnv.objs[rec].(*Track)
+			o.Sim = mc
+		case *MCRecoClusterParticleAssociationCollection:
+			o := coll.Elems[i]
+			o.Rec, _ = cnv.objs[rec].(*Cluster)
+			o.Sim = mc
+		}
+	}
+}
+
+// cellID returns the 64b cell ID made of the two LCIO 32b cell IDs.
+func cellID(id0, id1 int32) uint64 {
+	return uint64(uint32(id0)) | uint64(uint32(id1))<<32
+}
@@ -0,0 +1,4689 @@
+// Automatically generated. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"go-hep.org/x/hep/groot/rbase"
+	"go-hep.org/x/hep/groot/rbytes"
+	"go-hep.org/x/hep/groot/rdict"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/rmeta"
+	"go-hep.org/x/hep/groot/rtree"
+	"go-hep.org/x/hep/groot/rvers"
+)
+
+// edm4hep::Vector3f
+type Vector3f struct {
+	X float32
+	Y float32
+	Z float32
+}
+
+func (*Vector3f) Class() string   { return "edm4hep::Vector3f" }
+func (*Vector3f) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *Vector3f) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteF32(o.X)
+	w.WriteF32(o.Y)
+	w.WriteF32(o.Z)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *Vector3f) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.X = r.ReadF32()
+	o.Y = r.ReadF32()
+	o.Z = r.ReadF32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// edm4hep::Vector3d
+type Vector3d struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+func (*Vector3d) Class() string   { return "edm4hep::Vector3d" }
+func (*Vector3d) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *Vector3d) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteF64(o.X)
+	w.WriteF64(o.Y)
+	w.WriteF64(o.Z)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *Vector3d) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.X = r.ReadF64()
+	o.Y = r.ReadF64()
+	o.Z = r.ReadF64()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// edm4hep::Vector2i
+type Vector2i struct {
+	A int32
+	B int32
+}
+
+func (*Vector2i) Class() string   { return "edm4hep::Vector2i" }
+func (*Vector2i) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *Vector2i) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.A)
+	w.WriteI32(o.B)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *Vector2i) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.A = r.ReadI32()
+	o.B = r.ReadI32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// edm4hep::TrackState
+// Parametrized description of a particle track
+type TrackState struct {
+	Location       int32       // location of the track state (AtOther, AtIP, AtFirstHit, AtLastHit, AtCalorimeter or AtVertex)
+	D0             float32     // transverse impact parameter
+	Phi            float32     // azimuthal angle
+	Omega          float32     // is the signed curvature of the track in [1/mm].
+	Z0             float32     // longitudinal impact parameter
+	TanLambda      float32     // lambda is the dip angle of the track in r-z
+	ReferencePoint Vector3f    // Reference point of the track parameters, e.g. the origin at the IP, or the position  of the first/last hits or the entry point into the calorimeter. [mm]
+	CovMatrix      [15]float32 // lower triangular covariance matrix of the track parameters.
+}
+
+func (*TrackState) Class() string   { return "edm4hep::TrackState" }
+func (*TrackState) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *TrackState) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.Location)
+	w.WriteF32(o.D0)
+	w.WriteF32(o.Phi)
+	w.WriteF32(o.Omega)
+	w.WriteF32(o.Z0)
+	w.WriteF32(o.TanLambda)
+	o.ReferencePoint.MarshalROOT(w)
+	w.WriteFastArrayF32(o.CovMatrix[:])
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *TrackState) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Location = r.ReadI32()
+	o.D0 = r.ReadF32()
+	o.Phi = r.ReadF32()
+	o.Omega = r.ReadF32()
+	o.Z0 = r.ReadF32()
+	o.TanLambda = r.ReadF32()
+	o.ReferencePoint.UnmarshalROOT(r)
+	r.ReadArrayF32(o.CovMatrix[:])
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// edm4hep::Quantity
+type Quantity struct {
+	Type  int16   // flag identifying how to interpret the quantity
+	Value float32 // value of the quantity
+	Error float32 // error on the value of the quantity
+}
+
+func (*Quantity) Class() string   { return "edm4hep::Quantity" }
+func (*Quantity) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *Quantity) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI16(o.Type)
+	w.WriteF32(o.Value)
+	w.WriteF32(o.Error)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *Quantity) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Type = r.ReadI16()
+	o.Value = r.ReadF32()
+	o.Error = r.ReadF32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// edm4hep::EventHeader
+// Event Header. Additional parameters are assumed to go into the metadata tree.
+type EventHeader struct {
+	EventNumber int32   // event number
+	RunNumber   int32   // run number
+	TimeStamp   uint64  // time stamp
+	Weight      float32 // event weight
+}
+
+// EventHeaderCollection is a collection of EventHeader.
+type EventHeaderCollection struct {
+	Elems  []*EventHeader
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*EventHeaderCollection) podioType() string { return "edm4hep::EventHeader" }
+func (c *EventHeaderCollection) isSubset() bool  { return c.Subset }
+
+// EventHeaderData is the POD data part of EventHeader, as stored in PODIO files.
+type EventHeaderData struct {
+	EventNumber int32   `groot:"eventNumber"`
+	RunNumber   int32   `groot:"runNumber"`
+	TimeStamp   uint64  `groot:"timeStamp"`
+	Weight      float32 `groot:"weight"`
+}
+
+func (*EventHeaderData) Class() string   { return "edm4hep::EventHeaderData" }
+func (*EventHeaderData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *EventHeaderData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.EventNumber)
+	w.WriteI32(o.RunNumber)
+	w.WriteU64(o.TimeStamp)
+	w.WriteF32(o.Weight)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *EventHeaderData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.EventNumber = r.ReadI32()
+	o.RunNumber = r.ReadI32()
+	o.TimeStamp = r.ReadU64()
+	o.Weight = r.ReadF32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// eventHeaderBuffers holds the ROOT I/O buffers of EventHeaderCollection values.
+type eventHeaderBuffers struct {
+	data eventHeaderDataVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *eventHeaderBuffers) reset() {
+	b.data = b.data[:0]
+	b.objs = b.objs[:0]
+}
+
+func (b *eventHeaderBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+	}
+}
+
+func (b *eventHeaderBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*EventHeaderCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *eventHeaderBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*EventHeaderCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := EventHeaderData{
+			EventNumber: o.EventNumber,
+			RunNumber:   o.RunNumber,
+			TimeStamp:   o.TimeStamp,
+			Weight:      o.Weight,
+		}
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *eventHeaderBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &EventHeaderCollection{Elems: make([]*EventHeader, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]EventHeader, len(b.data))
+		coll = &EventHeaderCollection{Elems: make([]*EventHeader, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.EventNumber = d.EventNumber
+		o.RunNumber = d.RunNumber
+		o.TimeStamp = d.TimeStamp
+		o.Weight = d.Weight
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *eventHeaderBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*EventHeaderCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*EventHeader)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (b *eventHeaderBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*EventHeaderCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// edm4hep::MCParticle
+// The Monte Carlo particle - based on the lcio::MCParticle.
+type MCParticle struct {
+	PDG                int32         // PDG code of the particle
+	GeneratorStatus    int32         // status of the particle as defined by the generator
+	SimulatorStatus    int32         // status of the particle from the simulation program - use BIT constants below
+	Charge             float32       // particle charge
+	Time               float32       // creation time of the particle in [ns] wrt. the event, e.g. for preassigned decays or decays in flight from the simulator.
+	Mass               float64       // mass of the particle in [GeV]
+	Vertex             Vector3d      // production vertex of the particle in [mm].
+	Endpoint           Vector3d      // endpoint of the particle in [mm]
+	Momentum           Vector3f      // particle 3-momentum at the production vertex in [GeV]
+	MomentumAtEndpoint Vector3f      // particle 3-momentum at the endpoint in [GeV]
+	Spin               Vector3f      // spin (helicity) vector of the particle.
+	ColorFlow          Vector2i      // color flow as defined by the generator
+	Parents            []*MCParticle // The parents of this particle.
+	Daughters          []*MCParticle // The daughters this particle.
+}
+
+// MCParticleCollection is a collection of MCParticle.
+type MCParticleCollection struct {
+	Elems  []*MCParticle
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*MCParticleCollection) podioType() string { return "edm4hep::MCParticle" }
+func (c *MCParticleCollection) isSubset() bool  { return c.Subset }
+
+// MCParticleData is the POD data part of MCParticle, as stored in PODIO files.
+type MCParticleData struct {
+	PDG                int32    `groot:"PDG"`
+	GeneratorStatus    int32    `groot:"generatorStatus"`
+	SimulatorStatus    int32    `groot:"simulatorStatus"`
+	Charge             float32  `groot:"charge"`
+	Time               float32  `groot:"time"`
+	Mass               float64  `groot:"mass"`
+	Vertex             Vector3d `groot:"vertex"`
+	Endpoint           Vector3d `groot:"endpoint"`
+	Momentum           Vector3f `groot:"momentum"`
+	MomentumAtEndpoint Vector3f `groot:"momentumAtEndpoint"`
+	Spin               Vector3f `groot:"spin"`
+	ColorFlow          Vector2i `groot:"colorFlow"`
+	ParentsBegin       uint32   `groot:"parents_begin"`
+	ParentsEnd         uint32   `groot:"parents_end"`
+	DaughtersBegin     uint32   `groot:"daughters_begin"`
+	DaughtersEnd       uint32   `groot:"daughters_end"`
+}
+
+func (*MCParticleData) Class() string   { return "edm4hep::MCParticleData" }
+func (*MCParticleData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *MCParticleData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.PDG)
+	w.WriteI32(o.GeneratorStatus)
+	w.WriteI32(o.SimulatorStatus)
+	w.WriteF32(o.Charge)
+	w.WriteF32(o.Time)
+	w.WriteF64(o.Mass)
+	o.Vertex.MarshalROOT(w)
+	o.Endpoint.MarshalROOT(w)
+	o.Momentum.MarshalROOT(w)
+	o.MomentumAtEndpoint.MarshalROOT(w)
+	o.Spin.MarshalROOT(w)
+	o.ColorFlow.MarshalROOT(w)
+	w.WriteU32(o.ParentsBegin)
+	w.WriteU32(o.ParentsEnd)
+	w.WriteU32(o.DaughtersBegin)
+	w.WriteU32(o.DaughtersEnd)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *MCParticleData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.PDG = r.ReadI32()
+	o.GeneratorStatus = r.ReadI32()
+	o.SimulatorStatus = r.ReadI32()
+	o.Charge = r.ReadF32()
+	o.Time = r.ReadF32()
+	o.Mass = r.ReadF64()
+	o.Vertex.UnmarshalROOT(r)
+	o.Endpoint.UnmarshalROOT(r)
+	o.Momentum.UnmarshalROOT(r)
+	o.MomentumAtEndpoint.UnmarshalROOT(r)
+	o.Spin.UnmarshalROOT(r)
+	o.ColorFlow.UnmarshalROOT(r)
+	o.ParentsBegin = r.ReadU32()
+	o.ParentsEnd = r.ReadU32()
+	o.DaughtersBegin = r.ReadU32()
+	o.DaughtersEnd = r.ReadU32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// mCParticleBuffers holds the ROOT I/O buffers of MCParticleCollection values.
+type mCParticleBuffers struct {
+	data mCParticleDataVec
+	refs [2]objectIDVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *mCParticleBuffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.objs = b.objs[:0]
+}
+
+func (b *mCParticleBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+		{name + "#1", &b.refs[1]},
+	}
+}
+
+func (b *mCParticleBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*MCParticleCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *mCParticleBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*MCParticleCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := MCParticleData{
+			PDG:                o.PDG,
+			GeneratorStatus:    o.GeneratorStatus,
+			SimulatorStatus:    o.SimulatorStatus,
+			Charge:             o.Charge,
+			Time:               o.Time,
+			Mass:               o.Mass,
+			Vertex:             o.Vertex,
+			Endpoint:           o.Endpoint,
+			Momentum:           o.Momentum,
+			MomentumAtEndpoint: o.MomentumAtEndpoint,
+			Spin:               o.Spin,
+			ColorFlow:          o.ColorFlow,
+		}
+		d.ParentsBegin = uint32(len(b.refs[0]))
+		for _, v := range o.Parents {
+			b.refs[0] = append(b.refs[0], ids.get(v))
+		}
+		d.ParentsEnd = uint32(len(b.refs[0]))
+		d.DaughtersBegin = uint32(len(b.refs[1]))
+		for _, v := range o.Daughters {
+			b.refs[1] = append(b.refs[1], ids.get(v))
+		}
+		d.DaughtersEnd = uint32(len(b.refs[1]))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *mCParticleBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &MCParticleCollection{Elems: make([]*MCParticle, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]MCParticle, len(b.data))
+		coll = &MCParticleCollection{Elems: make([]*MCParticle, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.PDG = d.PDG
+		o.GeneratorStatus = d.GeneratorStatus
+		o.SimulatorStatus = d.SimulatorStatus
+		o.Charge = d.Charge
+		o.Time = d.Time
+		o.Mass = d.Mass
+		o.Vertex = d.Vertex
+		o.Endpoint = d.Endpoint
+		o.Momentum = d.Momentum
+		o.MomentumAtEndpoint = d.MomentumAtEndpoint
+		o.Spin = d.Spin
+		o.ColorFlow = d.ColorFlow
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *mCParticleBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*MCParticleCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*MCParticle)
+		}
+		return nil
+	}
+	for i := range b.data {
+		d := &b.data[i]
+		o := c.Elems[i]
+		if !podioRange(d.ParentsBegin, d.ParentsEnd, len(b.refs[0])) {
+			return fmt.Errorf("podio: invalid parents range [%d, %d) (n=%d)", d.ParentsBegin, d.ParentsEnd, len(b.refs[0]))
+		}
+		for _, id := range b.refs[0][d.ParentsBegin:d.ParentsEnd] {
+			if v, ok := get(id).(*MCParticle); ok {
+				o.Parents = append(o.Parents, v)
+			}
+		}
+		if !podioRange(d.DaughtersBegin, d.DaughtersEnd, len(b.refs[1])) {
+			return fmt.Errorf("podio: invalid daughters range [%d, %d) (n=%d)", d.DaughtersBegin, d.DaughtersEnd, len(b.refs[1]))
+		}
+		for _, id := range b.refs[1][d.DaughtersBegin:d.DaughtersEnd] {
+			if v, ok := get(id).(*MCParticle); ok {
+				o.Daughters = append(o.Daughters, v)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *mCParticleBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*MCParticleCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// edm4hep::SimTrackerHit
+// Simulated tracker hit
+type SimTrackerHit struct {
+	CellID     uint64      // ID of the sensor that created this hit
+	EDep       float32     // energy deposited in the hit [GeV].
+	Time       float32     // proper time of the hit in the lab frame in [ns].
+	PathLength float32     // path length of the particle in the sensitive material that resulted in this hit.
+	Quality    int32       // quality bit flag.
+	Position   Vector3d    // the hit position in [mm].
+	Momentum   Vector3f    // the 3-momentum of the particle at the hits position in [GeV]
+	MCParticle *MCParticle // MCParticle that caused the hit.
+}
+
+// SimTrackerHitCollection is a collection of SimTrackerHit.
+type SimTrackerHitCollection struct {
+	Elems  []*SimTrackerHit
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*SimTrackerHitCollection) podioType() string { return "edm4hep::SimTrackerHit" }
+func (c *SimTrackerHitCollection) isSubset() bool  { return c.Subset }
+
+// SimTrackerHitData is the POD data part of SimTrackerHit, as stored in PODIO files.
+type SimTrackerHitData struct {
+	CellID     uint64   `groot:"cellID"`
+	EDep       float32  `groot:"EDep"`
+	Time       float32  `groot:"time"`
+	PathLength float32  `groot:"pathLength"`
+	Quality    int32    `groot:"quality"`
+	Position   Vector3d `groot:"position"`
+	Momentum   Vector3f `groot:"momentum"`
+}
+
+func (*SimTrackerHitData) Class() string   { return "edm4hep::SimTrackerHitData" }
+func (*SimTrackerHitData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *SimTrackerHitData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteU64(o.CellID)
+	w.WriteF32(o.EDep)
+	w.WriteF32(o.Time)
+	w.WriteF32(o.PathLength)
+	w.WriteI32(o.Quality)
+	o.Position.MarshalROOT(w)
+	o.Momentum.MarshalROOT(w)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *SimTrackerHitData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.CellID = r.ReadU64()
+	o.EDep = r.ReadF32()
+	o.Time = r.ReadF32()
+	o.PathLength = r.ReadF32()
+	o.Quality = r.ReadI32()
+	o.Position.UnmarshalROOT(r)
+	o.Momentum.UnmarshalROOT(r)
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// simTrackerHitBuffers holds the ROOT I/O buffers of SimTrackerHitCollection values.
+type simTrackerHitBuffers struct {
+	data simTrackerHitDataVec
+	refs [1]objectIDVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *simTrackerHitBuffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.objs = b.objs[:0]
+}
+
+func (b *simTrackerHitBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+	}
+}
+
+func (b *simTrackerHitBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*SimTrackerHitCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *simTrackerHitBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*SimTrackerHitCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := SimTrackerHitData{
+			CellID:     o.CellID,
+			EDep:       o.EDep,
+			Time:       o.Time,
+			PathLength: o.PathLength,
+			Quality:    o.Quality,
+			Position:   o.Position,
+			Momentum:   o.Momentum,
+		}
+		b.refs[0] = append(b.refs[0], ids.get(o.MCParticle))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *simTrackerHitBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &SimTrackerHitCollection{Elems: make([]*SimTrackerHit, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]SimTrackerHit, len(b.data))
+		coll = &SimTrackerHitCollection{Elems: make([]*SimTrackerHit, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.CellID = d.CellID
+		o.EDep = d.EDep
+		o.Time = d.Time
+		o.PathLength = d.PathLength
+		o.Quality = d.Quality
+		o.Position = d.Position
+		o.Momentum = d.Momentum
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *simTrackerHitBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*SimTrackerHitCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*SimTrackerHit)
+		}
+		return nil
+	}
+	if len(b.refs[0]) != len(b.data) {
+		return fmt.Errorf("podio: invalid number of MCParticle relations (got=%d, want=%d)", len(b.refs[0]), len(b.data))
+	}
+	for i := range b.data {
+		o := c.Elems[i]
+		o.MCParticle, _ = get(b.refs[0][i]).(*MCParticle)
+	}
+	return nil
+}
+
+func (b *simTrackerHitBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*SimTrackerHitCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// edm4hep::CaloHitContribution
+// Monte Carlo contribution to SimCalorimeterHit
+type CaloHitContribution struct {
+	PDG          int32       // PDG code of the shower particle that caused this contribution.
+	Energy       float32     // energy in [GeV] of the this contribution
+	Time         float32     // time in [ns] of this contribution
+	StepPosition Vector3f    // position of this energy deposition (step) [mm]
+	Particle     *MCParticle // primary MCParticle that caused the shower responsible for this contribution to the hit.
+}
+
+// CaloHitContributionCollection is a collection of CaloHitContribution.
+type CaloHitContributionCollection struct {
+	Elems  []*CaloHitContribution
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*CaloHitContributionCollection) podioType() string { return "edm4hep::CaloHitContribution" }
+func (c *CaloHitContributionCollection) isSubset() bool  { return c.Subset }
+
+// CaloHitContributionData is the POD data part of CaloHitContribution, as stored in PODIO files.
+type CaloHitContributionData struct {
+	PDG          int32    `groot:"PDG"`
+	Energy       float32  `groot:"energy"`
+	Time         float32  `groot:"time"`
+	StepPosition Vector3f `groot:"stepPosition"`
+}
+
+func (*CaloHitContributionData) Class() string   { return "edm4hep::CaloHitContributionData" }
+func (*CaloHitContributionData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *CaloHitContributionData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.PDG)
+	w.WriteF32(o.Energy)
+	w.WriteF32(o.Time)
+	o.StepPosition.MarshalROOT(w)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *CaloHitContributionData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.PDG = r.ReadI32()
+	o.Energy = r.ReadF32()
+	o.Time = r.ReadF32()
+	o.StepPosition.UnmarshalROOT(r)
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// caloHitContributionBuffers holds the ROOT I/O buffers of CaloHitContributionCollection values.
+type caloHitContributionBuffers struct {
+	data caloHitContributionDataVec
+	refs [1]objectIDVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *caloHitContributionBuffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.objs = b.objs[:0]
+}
+
+func (b *caloHitContributionBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+	}
+}
+
+func (b *caloHitContributionBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*CaloHitContributionCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *caloHitContributionBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*CaloHitContributionCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := CaloHitContributionData{
+			PDG:          o.PDG,
+			Energy:       o.Energy,
+			Time:         o.Time,
+			StepPosition: o.StepPosition,
+		}
+		b.refs[0] = append(b.refs[0], ids.get(o.Particle))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *caloHitContributionBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &CaloHitContributionCollection{Elems: make([]*CaloHitContribution, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]CaloHitContribution, len(b.data))
+		coll = &CaloHitContributionCollection{Elems: make([]*CaloHitContribution, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.PDG = d.PDG
+		o.Energy = d.Energy
+		o.Time = d.Time
+		o.StepPosition = d.StepPosition
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *caloHitContributionBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*CaloHitContributionCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*CaloHitContribution)
+		}
+		return nil
+	}
+	if len(b.refs[0]) != len(b.data) {
+		return fmt.Errorf("podio: invalid number of particle relations (got=%d, want=%d)", len(b.refs[0]), len(b.data))
+	}
+	for i := range b.data {
+		o := c.Elems[i]
+		o.Particle, _ = get(b.refs[0][i]).(*MCParticle)
+	}
+	return nil
+}
+
+func (b *caloHitContributionBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*CaloHitContributionCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// edm4hep::SimCalorimeterHit
+// Simulated calorimeter hit
+type SimCalorimeterHit struct {
+	CellID        uint64                 // ID of the sensor that created this hit
+	Energy        float32                // energy of the hit in [GeV].
+	Position      Vector3f               // position of the hit in world coordinates in [mm].
+	Contributions []*CaloHitContribution // Monte Carlo step contribution - parallel to particle
+}
+
+// SimCalorimeterHitCollection is a collection of SimCalorimeterHit.
+type SimCalorimeterHitCollection struct {
+	Elems  []*SimCalorimeterHit
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*SimCalorimeterHitCollection) podioType() string { return "edm4hep::SimCalorimeterHit" }
+func (c *SimCalorimeterHitCollection) isSubset() bool  { return c.Subset }
+
+// SimCalorimeterHitData is the POD data part of SimCalorimeterHit, as stored in PODIO files.
+type SimCalorimeterHitData struct {
+	CellID             uint64   `groot:"cellID"`
+	Energy             float32  `groot:"energy"`
+	Position           Vector3f `groot:"position"`
+	ContributionsBegin uint32   `groot:"contributions_begin"`
+	ContributionsEnd   uint32   `groot:"contributions_end"`
+}
+
+func (*SimCalorimeterHitData) Class() string   { return "edm4hep::SimCalorimeterHitData" }
+func (*SimCalorimeterHitData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *SimCalorimeterHitData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteU64(o.CellID)
+	w.WriteF32(o.Energy)
+	o.Position.MarshalROOT(w)
+	w.WriteU32(o.ContributionsBegin)
+	w.WriteU32(o.ContributionsEnd)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *SimCalorimeterHitData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.CellID = r.ReadU64()
+	o.Energy = r.ReadF32()
+	o.Position.UnmarshalROOT(r)
+	o.ContributionsBegin = r.ReadU32()
+	o.ContributionsEnd = r.ReadU32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// simCalorimeterHitBuffers holds the ROOT I/O buffers of SimCalorimeterHitCollection values.
+type simCalorimeterHitBuffers struct {
+	data simCalorimeterHitDataVec
+	refs [1]objectIDVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *simCalorimeterHitBuffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.objs = b.objs[:0]
+}
+
+func (b *simCalorimeterHitBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+	}
+}
+
+func (b *simCalorimeterHitBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*SimCalorimeterHitCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *simCalorimeterHitBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*SimCalorimeterHitCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := SimCalorimeterHitData{
+			CellID:   o.CellID,
+			Energy:   o.Energy,
+			Position: o.Position,
+		}
+		d.ContributionsBegin = uint32(len(b.refs[0]))
+		for _, v := range o.Contributions {
+			b.refs[0] = append(b.refs[0], ids.get(v))
+		}
+		d.ContributionsEnd = uint32(len(b.refs[0]))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *simCalorimeterHitBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &SimCalorimeterHitCollection{Elems: make([]*SimCalorimeterHit, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]SimCalorimeterHit, len(b.data))
+		coll = &SimCalorimeterHitCollection{Elems: make([]*SimCalorimeterHit, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.CellID = d.CellID
+		o.Energy = d.Energy
+		o.Position = d.Position
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *simCalorimeterHitBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*SimCalorimeterHitCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*SimCalorimeterHit)
+		}
+		return nil
+	}
+	for i := range b.data {
+		d := &b.data[i]
+		o := c.Elems[i]
+		if !podioRange(d.ContributionsBegin, d.ContributionsEnd, len(b.refs[0])) {
+			return fmt.Errorf("podio: invalid contributions range [%d, %d) (n=%d)", d.ContributionsBegin, d.ContributionsEnd, len(b.refs[0]))
+		}
+		for _, id := range b.refs[0][d.ContributionsBegin:d.ContributionsEnd] {
+			if v, ok := get(id).(*CaloHitContribution); ok {
+				o.Contributions = append(o.Contributions, v)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *simCalorimeterHitBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*SimCalorimeterHitCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// edm4hep::CalorimeterHit
+// Calorimeter hit
+type CalorimeterHit struct {
+	CellID      uint64   // detector specific (geometrical) cell id.
+	Energy      float32  // energy of the hit in [GeV].
+	EnergyError float32  // error of the hit energy in [GeV].
+	Time        float32  // time of the hit in [ns].
+	Position    Vector3f // position of the hit in world coordinates in [mm].
+	Type        int32    // type of hit. Mapping of integer types to names via collection parameters "CalorimeterHitTypeNames" and "CalorimeterHitTypeValues".
+}
+
+// CalorimeterHitCollection is a collection of CalorimeterHit.
+type CalorimeterHitCollection struct {
+	Elems  []*CalorimeterHit
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*CalorimeterHitCollection) podioType() string { return "edm4hep::CalorimeterHit" }
+func (c *CalorimeterHitCollection) isSubset() bool  { return c.Subset }
+
+// CalorimeterHitData is the POD data part of CalorimeterHit, as stored in PODIO files.
+type CalorimeterHitData struct {
+	CellID      uint64   `groot:"cellID"`
+	Energy      float32  `groot:"energy"`
+	EnergyError float32  `groot:"energyError"`
+	Time        float32  `groot:"time"`
+	Position    Vector3f `groot:"position"`
+	Type        int32    `groot:"type"`
+}
+
+func (*CalorimeterHitData) Class() string   { return "edm4hep::CalorimeterHitData" }
+func (*CalorimeterHitData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *CalorimeterHitData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteU64(o.CellID)
+	w.WriteF32(o.Energy)
+	w.WriteF32(o.EnergyError)
+	w.WriteF32(o.Time)
+	o.Position.MarshalROOT(w)
+	w.WriteI32(o.Type)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *CalorimeterHitData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.CellID = r.ReadU64()
+	o.Energy = r.ReadF32()
+	o.EnergyError = r.ReadF32()
+	o.Time = r.ReadF32()
+	o.Position.UnmarshalROOT(r)
+	o.Type = r.ReadI32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// calorimeterHitBuffers holds the ROOT I/O buffers of CalorimeterHitCollection values.
+type calorimeterHitBuffers struct {
+	data calorimeterHitDataVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *calorimeterHitBuffers) reset() {
+	b.data = b.data[:0]
+	b.objs = b.objs[:0]
+}
+
+func (b *calorimeterHitBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+	}
+}
+
+func (b *calorimeterHitBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*CalorimeterHitCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *calorimeterHitBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*CalorimeterHitCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := CalorimeterHitData{
+			CellID:      o.CellID,
+			Energy:      o.Energy,
+			EnergyError: o.EnergyError,
+			Time:        o.Time,
+			Position:    o.Position,
+			Type:        o.Type,
+		}
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *calorimeterHitBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &CalorimeterHitCollection{Elems: make([]*CalorimeterHit, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]CalorimeterHit, len(b.data))
+		coll = &CalorimeterHitCollection{Elems: make([]*CalorimeterHit, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.CellID = d.CellID
+		o.Energy = d.Energy
+		o.EnergyError = d.EnergyError
+		o.Time = d.Time
+		o.Position = d.Position
+		o.Type = d.Type
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *calorimeterHitBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*CalorimeterHitCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*CalorimeterHit)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (b *calorimeterHitBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*CalorimeterHitCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// edm4hep::ParticleID
+// ParticleID
+type ParticleID struct {
+	Type          int32     // userdefined type
+	PDG           int32     // PDG code of this id - ( 999999 ) if unknown.
+	AlgorithmType int32     // type of the algorithm/module that created this hypothesis
+	Likelihood    float32   // likelihood of this hypothesis - in a user defined normalization.
+	Parameters    []float32 // parameters associated with this hypothesis. Check/set collection parameters ParameterNames_PIDAlgorithmTypeName for decoding the indices.
+}
+
+// ParticleIDCollection is a collection of ParticleID.
+type ParticleIDCollection struct {
+	Elems  []*ParticleID
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*ParticleIDCollection) podioType() string { return "edm4hep::ParticleID" }
+func (c *ParticleIDCollection) isSubset() bool  { return c.Subset }
+
+// ParticleIDData is the POD data part of ParticleID, as stored in PODIO files.
+type ParticleIDData struct {
+	Type            int32   `groot:"type"`
+	PDG             int32   `groot:"PDG"`
+	AlgorithmType   int32   `groot:"algorithmType"`
+	Likelihood      float32 `groot:"likelihood"`
+	ParametersBegin uint32  `groot:"parameters_begin"`
+	ParametersEnd   uint32  `groot:"parameters_end"`
+}
+
+func (*ParticleIDData) Class() string   { return "edm4hep::ParticleIDData" }
+func (*ParticleIDData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ParticleIDData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.Type)
+	w.WriteI32(o.PDG)
+	w.WriteI32(o.AlgorithmType)
+	w.WriteF32(o.Likelihood)
+	w.WriteU32(o.ParametersBegin)
+	w.WriteU32(o.ParametersEnd)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ParticleIDData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Type = r.ReadI32()
+	o.PDG = r.ReadI32()
+	o.AlgorithmType = r.ReadI32()
+	o.Likelihood = r.ReadF32()
+	o.ParametersBegin = r.ReadU32()
+	o.ParametersEnd = r.ReadU32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// particleIDBuffers holds the ROOT I/O buffers of ParticleIDCollection values.
+type particleIDBuffers struct {
+	data particleIDDataVec
+	vec0 float32Vec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *particleIDBuffers) reset() {
+	b.data = b.data[:0]
+	b.vec0 = b.vec0[:0]
+	b.objs = b.objs[:0]
+}
+
+func (b *particleIDBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "_0", &b.vec0},
+	}
+}
+
+func (b *particleIDBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*ParticleIDCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *particleIDBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*ParticleIDCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := ParticleIDData{
+			Type:          o.Type,
+			PDG:           o.PDG,
+			AlgorithmType: o.AlgorithmType,
+			Likelihood:    o.Likelihood,
+		}
+		d.ParametersBegin = uint32(len(b.vec0))
+		b.vec0 = append(b.vec0, o.Parameters...)
+		d.ParametersEnd = uint32(len(b.vec0))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *particleIDBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &ParticleIDCollection{Elems: make([]*ParticleID, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]ParticleID, len(b.data))
+		coll = &ParticleIDCollection{Elems: make([]*ParticleID, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.Type = d.Type
+		o.PDG = d.PDG
+		o.AlgorithmType = d.AlgorithmType
+		o.Likelihood = d.Likelihood
+		if !podioRange(d.ParametersBegin, d.ParametersEnd, len(b.vec0)) {
+			return nil, fmt.Errorf("podio: invalid parameters range [%d, %d) (n=%d)", d.ParametersBegin, d.ParametersEnd, len(b.vec0))
+		}
+		o.Parameters = append([]float32(nil), b.vec0[d.ParametersBegin:d.ParametersEnd]...)
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *particleIDBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*ParticleIDCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*ParticleID)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (b *particleIDBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*ParticleIDCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// edm4hep::Cluster
+// Calorimeter Hit Cluster
+type Cluster struct {
+	Type                int32             // flagword that defines the type of cluster. Bits 16-31 are used internally.
+	Energy              float32           // energy of the cluster [GeV]
+	EnergyError         float32           // error on the energy
+	Position            Vector3f          // position of the cluster [mm]
+	PositionError       [6]float32        // covariance matrix of the position (6 Parameters)
+	ITheta              float32           // intrinsic direction of cluster at position  Theta. Not to be confused with direction cluster is seen from IP.
+	Phi                 float32           // intrinsic direction of cluster at position - Phi. Not to be confused with direction cluster is seen from IP.
+	DirectionError      Vector3f          // covariance matrix of the direction (3 Parameters) [mm^2]
+	Clusters            []*Cluster        // clusters that have been combined to this cluster.
+	Hits                []*CalorimeterHit // hits that have been combined to this cluster.
+	ParticleIDs         []*ParticleID     // particle IDs (sorted by their likelihood)
+	ShapeParameters     []float32         // shape parameters - check/set collection parameter ClusterShapeParameters for size and names of parameters.
+	SubdetectorEnergies []float32         // energy observed in a particular subdetector. Check/set collection parameter ClusterSubdetectorNames for decoding the indices of the array.
+}
+
+// ClusterCollection is a collection of Cluster.
+type ClusterCollection struct {
+	Elems  []*Cluster
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*ClusterCollection) podioType() string { return "edm4hep::Cluster" }
+func (c *ClusterCollection) isSubset() bool  { return c.Subset }
+
+// ClusterData is the POD data part of Cluster, as stored in PODIO files.
+type ClusterData struct {
+	Type                     int32      `groot:"type"`
+	Energy                   float32    `groot:"energy"`
+	EnergyError              float32    `groot:"energyError"`
+	Position                 Vector3f   `groot:"position"`
+	PositionError            [6]float32 `groot:"positionError"`
+	ITheta                   float32    `groot:"iTheta"`
+	Phi                      float32    `groot:"phi"`
+	DirectionError           Vector3f   `groot:"directionError"`
+	ClustersBegin            uint32     `groot:"clusters_begin"`
+	ClustersEnd              uint32     `groot:"clusters_end"`
+	HitsBegin                uint32     `groot:"hits_begin"`
+	HitsEnd                  uint32     `groot:"hits_end"`
+	ParticleIDsBegin         uint32     `groot:"particleIDs_begin"`
+	ParticleIDsEnd           uint32     `groot:"particleIDs_end"`
+	ShapeParametersBegin     uint32     `groot:"shapeParameters_begin"`
+	ShapeParametersEnd       uint32     `groot:"shapeParameters_end"`
+	SubdetectorEnergiesBegin uint32     `groot:"subdetectorEnergies_begin"`
+	SubdetectorEnergiesEnd   uint32     `groot:"subdetectorEnergies_end"`
+}
+
+func (*ClusterData) Class() string   { return "edm4hep::ClusterData" }
+func (*ClusterData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ClusterData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.Type)
+	w.WriteF32(o.Energy)
+	w.WriteF32(o.EnergyError)
+	o.Position.MarshalROOT(w)
+	w.WriteFastArrayF32(o.PositionError[:])
+	w.WriteF32(o.ITheta)
+	w.WriteF32(o.Phi)
+	o.DirectionError.MarshalROOT(w)
+	w.WriteU32(o.ClustersBegin)
+	w.WriteU32(o.ClustersEnd)
+	w.WriteU32(o.HitsBegin)
+	w.WriteU32(o.HitsEnd)
+	w.WriteU32(o.ParticleIDsBegin)
+	w.WriteU32(o.ParticleIDsEnd)
+	w.WriteU32(o.ShapeParametersBegin)
+	w.WriteU32(o.ShapeParametersEnd)
+	w.WriteU32(o.SubdetectorEnergiesBegin)
+	w.WriteU32(o.SubdetectorEnergiesEnd)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ClusterData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Type = r.ReadI32()
+	o.Energy = r.ReadF32()
+	o.EnergyError = r.ReadF32()
+	o.Position.UnmarshalROOT(r)
+	r.ReadArrayF32(o.PositionError[:])
+	o.ITheta = r.ReadF32()
+	o.Phi = r.ReadF32()
+	o.DirectionError.UnmarshalROOT(r)
+	o.ClustersBegin = r.ReadU32()
+	o.ClustersEnd = r.ReadU32()
+	o.HitsBegin = r.ReadU32()
+	o.HitsEnd = r.ReadU32()
+	o.ParticleIDsBegin = r.ReadU32()
+	o.ParticleIDsEnd = r.ReadU32()
+	o.ShapeParametersBegin = r.ReadU32()
+	o.ShapeParametersEnd = r.ReadU32()
+	o.SubdetectorEnergiesBegin = r.ReadU32()
+	o.SubdetectorEnergiesEnd = r.ReadU32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// clusterBuffers holds the ROOT I/O buffers of ClusterCollection values.
+type clusterBuffers struct {
+	data clusterDataVec
+	refs [3]objectIDVec
+	vec0 float32Vec
+	vec1 float32Vec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *clusterBuffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.vec0 = b.vec0[:0]
+	b.vec1 = b.vec1[:0]
+	b.objs = b.objs[:0]
+}
+
+func (b *clusterBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+		{name + "#1", &b.refs[1]},
+		{name + "#2", &b.refs[2]},
+		{name + "_0", &b.vec0},
+		{name + "_1", &b.vec1},
+	}
+}
+
+func (b *clusterBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*ClusterCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *clusterBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*ClusterCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := ClusterData{
+			Type:           o.Type,
+			Energy:         o.Energy,
+			EnergyError:    o.EnergyError,
+			Position:       o.Position,
+			PositionError:  o.PositionError,
+			ITheta:         o.ITheta,
+			Phi:            o.Phi,
+			DirectionError: o.DirectionError,
+		}
+		d.ClustersBegin = uint32(len(b.refs[0]))
+		for _, v := range o.Clusters {
+			b.refs[0] = append(b.refs[0], ids.get(v))
+		}
+		d.ClustersEnd = uint32(len(b.refs[0]))
+		d.HitsBegin = uint32(len(b.refs[1]))
+		for _, v := range o.Hits {
+			b.refs[1] = append(b.refs[1], ids.get(v))
+		}
+		d.HitsEnd = uint32(len(b.refs[1]))
+		d.ParticleIDsBegin = uint32(len(b.refs[2]))
+		for _, v := range o.ParticleIDs {
+			b.refs[2] = append(b.refs[2], ids.get(v))
+		}
+		d.ParticleIDsEnd = uint32(len(b.refs[2]))
+		d.ShapeParametersBegin = uint32(len(b.vec0))
+		b.vec0 = append(b.vec0, o.ShapeParameters...)
+		d.ShapeParametersEnd = uint32(len(b.vec0))
+		d.SubdetectorEnergiesBegin = uint32(len(b.vec1))
+		b.vec1 = append(b.vec1, o.SubdetectorEnergies...)
+		d.SubdetectorEnergiesEnd = uint32(len(b.vec1))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *clusterBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &ClusterCollection{Elems: make([]*Cluster, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]Cluster, len(b.data))
+		coll = &ClusterCollection{Elems: make([]*Cluster, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.Type = d.Type
+		o.Energy = d.Energy
+		o.EnergyError = d.EnergyError
+		o.Position = d.Position
+		o.PositionError = d.PositionError
+		o.ITheta = d.ITheta
+		o.Phi = d.Phi
+		o.DirectionError = d.DirectionError
+		if !podioRange(d.ShapeParametersBegin, d.ShapeParametersEnd, len(b.vec0)) {
+			return nil, fmt.Errorf("podio: invalid shapeParameters range [%d, %d) (n=%d)", d.ShapeParametersBegin, d.ShapeParametersEnd, len(b.vec0))
+		}
+		o.ShapeParameters = append([]float32(nil), b.vec0[d.ShapeParametersBegin:d.ShapeParametersEnd]...)
+		if !podioRange(d.SubdetectorEnergiesBegin, d.SubdetectorEnergiesEnd, len(b.vec1)) {
+			return nil, fmt.Errorf("podio: invalid subdetectorEnergies range [%d, %d) (n=%d)", d.SubdetectorEnergiesBegin, d.SubdetectorEnergiesEnd, len(b.vec1))
+		}
+		o.SubdetectorEnergies = append([]float32(nil), b.vec1[d.SubdetectorEnergiesBegin:d.SubdetectorEnergiesEnd]...)
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *clusterBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*ClusterCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*Cluster)
+		}
+		return nil
+	}
+	for i := range b.data {
+		d := &b.data[i]
+		o := c.Elems[i]
+		if !podioRange(d.ClustersBegin, d.ClustersEnd, len(b.refs[0])) {
+			return fmt.Errorf("podio: invalid clusters range [%d, %d) (n=%d)", d.ClustersBegin, d.ClustersEnd, len(b.refs[0]))
+		}
+		for _, id := range b.refs[0][d.ClustersBegin:d.ClustersEnd] {
+			if v, ok := get(id).(*Cluster); ok {
+				o.Clusters = append(o.Clusters, v)
+			}
+		}
+		if !podioRange(d.HitsBegin, d.HitsEnd, len(b.refs[1])) {
+			return fmt.Errorf("podio: invalid hits range [%d, %d) (n=%d)", d.HitsBegin, d.HitsEnd, len(b.refs[1]))
+		}
+		for _, id := range b.refs[1][d.HitsBegin:d.HitsEnd] {
+			if v, ok := get(id).(*CalorimeterHit); ok {
+				o.Hits = append(o.Hits, v)
+			}
+		}
+		if !podioRange(d.ParticleIDsBegin, d.ParticleIDsEnd, len(b.refs[2])) {
+			return fmt.Errorf("podio: invalid particleIDs range [%d, %d) (n=%d)", d.ParticleIDsBegin, d.ParticleIDsEnd, len(b.refs[2]))
+		}
+		for _, id := range b.refs[2][d.ParticleIDsBegin:d.ParticleIDsEnd] {
+			if v, ok := get(id).(*ParticleID); ok {
+				o.ParticleIDs = append(o.ParticleIDs, v)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *clusterBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*ClusterCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// edm4hep::TrackerHit
+// Tracker hit
+type TrackerHit struct {
+	CellID    uint64     // ID of the sensor that created this hit
+	Type      int32      // type of raw data hit, either one of edm4hep::RawTimeSeries, edm4hep::SIMTRACKERHIT - see collection parameters "TrackerHitTypeNames" and "TrackerHitTypeValues".
+	Quality   int32      // quality bit flag of the hit.
+	Time      float32    // time of the hit [ns].
+	EDep      float32    // energy deposited on the hit [GeV].
+	EDepError float32    // error measured on EDep [GeV].
+	Edx       float32    // dE/dx of the hit in [GeV].
+	Position  Vector3d   // hit position in [mm].
+	CovMatrix [6]float32 // covariance of the position (x,y,z), stored as lower triangle matrix. i.e. cov(x,x) , cov(y,x) , cov(y,y) , cov(z,x) , cov(z,y) , cov(z,z)
+}
+
+// TrackerHitCollection is a collection of TrackerHit.
+type TrackerHitCollection struct {
+	Elems  []*TrackerHit
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*TrackerHitCollection) podioType() string { return "edm4hep::TrackerHit" }
+func (c *TrackerHitCollection) isSubset() bool  { return c.Subset }
+
+// TrackerHitData is the POD data part of TrackerHit, as stored in PODIO files.
+type TrackerHitData struct {
+	CellID    uint64     `groot:"cellID"`
+	Type      int32      `groot:"type"`
+	Quality   int32      `groot:"quality"`
+	Time      float32    `groot:"time"`
+	EDep      float32    `groot:"eDep"`
+	EDepError float32    `groot:"eDepError"`
+	Edx       float32    `groot:"edx"`
+	Position  Vector3d   `groot:"position"`
+	CovMatrix [6]float32 `groot:"covMatrix"`
+}
+
+func (*TrackerHitData) Class() string   { return "edm4hep::TrackerHitData" }
+func (*TrackerHitData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *TrackerHitData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteU64(o.CellID)
+	w.WriteI32(o.Type)
+	w.WriteI32(o.Quality)
+	w.WriteF32(o.Time)
+	w.WriteF32(o.EDep)
+	w.WriteF32(o.EDepError)
+	w.WriteF32(o.Edx)
+	o.Position.MarshalROOT(w)
+	w.WriteFastArrayF32(o.CovMatrix[:])
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *TrackerHitData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.CellID = r.ReadU64()
+	o.Type = r.ReadI32()
+	o.Quality = r.ReadI32()
+	o.Time = r.ReadF32()
+	o.EDep = r.ReadF32()
+	o.EDepError = r.ReadF32()
+	o.Edx = r.ReadF32()
+	o.Position.UnmarshalROOT(r)
+	r.ReadArrayF32(o.CovMatrix[:])
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// trackerHitBuffers holds the ROOT I/O buffers of TrackerHitCollection values.
+type trackerHitBuffers struct {
+	data trackerHitDataVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *trackerHitBuffers) reset() {
+	b.data = b.data[:0]
+	b.objs = b.objs[:0]
+}
+
+func (b *trackerHitBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+	}
+}
+
+func (b *trackerHitBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*TrackerHitCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *trackerHitBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*TrackerHitCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := TrackerHitData{
+			CellID:    o.CellID,
+			Type:      o.Type,
+			Quality:   o.Quality,
+			Time:      o.Time,
+			EDep:      o.EDep,
+			EDepError: o.EDepError,
+			Edx:       o.Edx,
+			Position:  o.Position,
+			CovMatrix: o.CovMatrix,
+		}
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *trackerHitBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &TrackerHitCollection{Elems: make([]*TrackerHit, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]TrackerHit, len(b.data))
+		coll = &TrackerHitCollection{Elems: make([]*TrackerHit, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.CellID = d.CellID
+		o.Type = d.Type
+		o.Quality = d.Quality
+		o.Time = d.Time
+		o.EDep = d.EDep
+		o.EDepError = d.EDepError
+		o.Edx = d.Edx
+		o.Position = d.Position
+		o.CovMatrix = d.CovMatrix
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *trackerHitBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*TrackerHitCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*TrackerHit)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (b *trackerHitBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*TrackerHitCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// edm4hep::Track
+// Reconstructed track
+type Track struct {
+	Type                  int32         // flagword that defines the type of track.Bits 16-31 are used internally
+	Chi2                  float32       // Chi^2 of the track fit
+	Ndf                   int32         // number of degrees of freedom of the track fit
+	DEdx                  float32       // dEdx of the track.
+	DEdxError             float32       // error of dEdx.
+	RadiusOfInnermostHit  float32       // radius of the innermost hit that has been used in the track fit
+	TrackerHits           []*TrackerHit // hits that have been used to create this track
+	Tracks                []*Track      // tracks (segments) that have been combined to create this track
+	SubDetectorHitNumbers []int32       // number of hits in particular subdetectors.Check/set collection variable TrackSubdetectorNames for decoding the indices
+	TrackStates           []TrackState  // track states
+	DxQuantities          []Quantity    // different measurements of dx quantities
+}
+
+// TrackCollection is a collection of Track.
+type TrackCollection struct {
+	Elems  []*Track
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*TrackCollection) podioType() string { return "edm4hep::Track" }
+func (c *TrackCollection) isSubset() bool  { return c.Subset }
+
+// TrackData is the POD data part of Track, as stored in PODIO files.
+type TrackData struct {
+	Type                       int32   `groot:"type"`
+	Chi2                       float32 `groot:"chi2"`
+	Ndf                        int32   `groot:"ndf"`
+	DEdx                       float32 `groot:"dEdx"`
+	DEdxError                  float32 `groot:"dEdxError"`
+	RadiusOfInnermostHit       float32 `groot:"radiusOfInnermostHit"`
+	TrackerHitsBegin           uint32  `groot:"trackerHits_begin"`
+	TrackerHitsEnd             uint32  `groot:"trackerHits_end"`
+	TracksBegin                uint32  `groot:"tracks_begin"`
+	TracksEnd                  uint32  `groot:"tracks_end"`
+	SubDetectorHitNumbersBegin uint32  `groot:"subDetectorHitNumbers_begin"`
+	SubDetectorHitNumbersEnd   uint32  `groot:"subDetectorHitNumbers_end"`
+	TrackStatesBegin           uint32  `groot:"trackStates_begin"`
+	TrackStatesEnd             uint32  `groot:"trackStates_end"`
+	DxQuantitiesBegin          uint32  `groot:"dxQuantities_begin"`
+	DxQuantitiesEnd            uint32  `groot:"dxQuantities_end"`
+}
+
+func (*TrackData) Class() string   { return "edm4hep::TrackData" }
+func (*TrackData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *TrackData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.Type)
+	w.WriteF32(o.Chi2)
+	w.WriteI32(o.Ndf)
+	w.WriteF32(o.DEdx)
+	w.WriteF32(o.DEdxError)
+	w.WriteF32(o.RadiusOfInnermostHit)
+	w.WriteU32(o.TrackerHitsBegin)
+	w.WriteU32(o.TrackerHitsEnd)
+	w.WriteU32(o.TracksBegin)
+	w.WriteU32(o.TracksEnd)
+	w.WriteU32(o.SubDetectorHitNumbersBegin)
+	w.WriteU32(o.SubDetectorHitNumbersEnd)
+	w.WriteU32(o.TrackStatesBegin)
+	w.WriteU32(o.TrackStatesEnd)
+	w.WriteU32(o.DxQuantitiesBegin)
+	w.WriteU32(o.DxQuantitiesEnd)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *TrackData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Type = r.ReadI32()
+	o.Chi2 = r.ReadF32()
+	o.Ndf = r.ReadI32()
+	o.DEdx = r.ReadF32()
+	o.DEdxError = r.ReadF32()
+	o.RadiusOfInnermostHit = r.ReadF32()
+	o.TrackerHitsBegin = r.ReadU32()
+	o.TrackerHitsEnd = r.ReadU32()
+	o.TracksBegin = r.ReadU32()
+	o.TracksEnd = r.ReadU32()
+	o.SubDetectorHitNumbersBegin = r.ReadU32()
+	o.SubDetectorHitNumbersEnd = r.ReadU32()
+	o.TrackStatesBegin = r.ReadU32()
+	o.TrackStatesEnd = r.ReadU32()
+	o.DxQuantitiesBegin = r.ReadU32()
+	o.DxQuantitiesEnd = r.ReadU32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// trackBuffers holds the ROOT I/O buffers of TrackCollection values.
+type trackBuffers struct {
+	data trackDataVec
+	refs [2]objectIDVec
+	vec0 int32Vec
+	vec1 trackStateVec
+	vec2 quantityVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *trackBuffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.vec0 = b.vec0[:0]
+	b.vec1 = b.vec1[:0]
+	b.vec2 = b.vec2[:0]
+	b.objs = b.objs[:0]
+}
+
+func (b *trackBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+		{name + "#1", &b.refs[1]},
+		{name + "_0", &b.vec0},
+		{name + "_1", &b.vec1},
+		{name + "_2", &b.vec2},
+	}
+}
+
+func (b *trackBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*TrackCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *trackBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*TrackCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := TrackData{
+			Type:                 o.Type,
+			Chi2:                 o.Chi2,
+			Ndf:                  o.Ndf,
+			DEdx:                 o.DEdx,
+			DEdxError:            o.DEdxError,
+			RadiusOfInnermostHit: o.RadiusOfInnermostHit,
+		}
+		d.TrackerHitsBegin = uint32(len(b.refs[0]))
+		for _, v := range o.TrackerHits {
+			b.refs[0] = append(b.refs[0], ids.get(v))
+		}
+		d.TrackerHitsEnd = uint32(len(b.refs[0]))
+		d.TracksBegin = uint32(len(b.refs[1]))
+		for _, v := range o.Tracks {
+			b.refs[1] = append(b.refs[1], ids.get(v))
+		}
+		d.TracksEnd = uint32(len(b.refs[1]))
+		d.SubDetectorHitNumbersBegin = uint32(len(b.vec0))
+		b.vec0 = append(b.vec0, o.SubDetectorHitNumbers...)
+		d.SubDetectorHitNumbersEnd = uint32(len(b.vec0))
+		d.TrackStatesBegin = uint32(len(b.vec1))
+		b.vec1 = append(b.vec1, o.TrackStates...)
+		d.TrackStatesEnd = uint32(len(b.vec1))
+		d.DxQuantitiesBegin = uint32(len(b.vec2))
+		b.vec2 = append(b.vec2, o.DxQuantities...)
+		d.DxQuantitiesEnd = uint32(len(b.vec2))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *trackBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &TrackCollection{Elems: make([]*Track, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]Track, len(b.data))
+		coll = &TrackCollection{Elems: make([]*Track, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.Type = d.Type
+		o.Chi2 = d.Chi2
+		o.Ndf = d.Ndf
+		o.DEdx = d.DEdx
+		o.DEdxError = d.DEdxError
+		o.RadiusOfInnermostHit = d.RadiusOfInnermostHit
+		if !podioRange(d.SubDetectorHitNumbersBegin, d.SubDetectorHitNumbersEnd, len(b.vec0)) {
+			return nil, fmt.Errorf("podio: invalid subDetectorHitNumbers range [%d, %d) (n=%d)", d.SubDetectorHitNumbersBegin, d.SubDetectorHitNumbersEnd, len(b.vec0))
+		}
+		o.SubDetectorHitNumbers = append([]int32(nil), b.vec0[d.SubDetectorHitNumbersBegin:d.SubDetectorHitNumbersEnd]...)
+		if !podioRange(d.TrackStatesBegin, d.TrackStatesEnd, len(b.vec1)) {
+			return nil, fmt.Errorf("podio: invalid trackStates range [%d, %d) (n=%d)", d.TrackStatesBegin, d.TrackStatesEnd, len(b.vec1))
+		}
+		o.TrackStates = append([]TrackState(nil), b.vec1[d.TrackStatesBegin:d.TrackStatesEnd]...)
+		if !podioRange(d.DxQuantitiesBegin, d.DxQuantitiesEnd, len(b.vec2)) {
+			return nil, fmt.Errorf("podio: invalid dxQuantities range [%d, %d) (n=%d)", d.DxQuantitiesBegin, d.DxQuantitiesEnd, len(b.vec2))
+		}
+		o.DxQuantities = append([]Quantity(nil), b.vec2[d.DxQuantitiesBegin:d.DxQuantitiesEnd]...)
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *trackBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*TrackCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*Track)
+		}
+		return nil
+	}
+	for i := range b.data {
+		d := &b.data[i]
+		o := c.Elems[i]
+		if !podioRange(d.TrackerHitsBegin, d.TrackerHitsEnd, len(b.refs[0])) {
+			return fmt.Errorf("podio: invalid trackerHits range [%d, %d) (n=%d)", d.TrackerHitsBegin, d.TrackerHitsEnd, len(b.refs[0]))
+		}
+		for _, id := range b.refs[0][d.TrackerHitsBegin:d.TrackerHitsEnd] {
+			if v, ok := get(id).(*TrackerHit); ok {
+				o.TrackerHits = append(o.TrackerHits, v)
+			}
+		}
+		if !podioRange(d.TracksBegin, d.TracksEnd, len(b.refs[1])) {
+			return fmt.Errorf("podio: invalid tracks range [%d, %d) (n=%d)", d.TracksBegin, d.TracksEnd, len(b.refs[1]))
+		}
+		for _, id := range b.refs[1][d.TracksBegin:d.TracksEnd] {
+			if v, ok := get(id).(*Track); ok {
+				o.Tracks = append(o.Tracks, v)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *trackBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*TrackCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// edm4hep::Vertex
+// Vertex
+type Vertex struct {
+	Primary            int32                  // boolean flag, if vertex is the primary vertex of the event
+	Chi2               float32                // chi-squared of the vertex fit
+	Probability        float32                // probability of the vertex fit
+	Position           Vector3f               // [mm] position of the vertex.
+	CovMatrix          [6]float32             // covariance matrix of the position (stored as lower triangle matrix, i.e. cov(xx),cov(y,x),cov(z,x),cov(y,y),... )
+	AlgorithmType      int32                  // type code for the algorithm that has been used to create the vertex - check/set the collection parameters AlgorithmName and AlgorithmType.
+	AssociatedParticle *ReconstructedParticle // reconstructed particle associated to this vertex.
+	Parameters         []float32              // additional parameters related to this vertex - check/set the collection parameter "VertexParameterNames" for the parameters meaning.
+}
+
+// VertexCollection is a collection of Vertex.
+type VertexCollection struct {
+	Elems  []*Vertex
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*VertexCollection) podioType() string { return "edm4hep::Vertex" }
+func (c *VertexCollection) isSubset() bool  { return c.Subset }
+
+// VertexData is the POD data part of Vertex, as stored in PODIO files.
+type VertexData struct {
+	Primary         int32      `groot:"primary"`
+	Chi2            float32    `groot:"chi2"`
+	Probability     float32    `groot:"probability"`
+	Position        Vector3f   `groot:"position"`
+	CovMatrix       [6]float32 `groot:"covMatrix"`
+	AlgorithmType   int32      `groot:"algorithmType"`
+	ParametersBegin uint32     `groot:"parameters_begin"`
+	ParametersEnd   uint32     `groot:"parameters_end"`
+}
+
+func (*VertexData) Class() string   { return "edm4hep::VertexData" }
+func (*VertexData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *VertexData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.Primary)
+	w.WriteF32(o.Chi2)
+	w.WriteF32(o.Probability)
+	o.Position.MarshalROOT(w)
+	w.WriteFastArrayF32(o.CovMatrix[:])
+	w.WriteI32(o.AlgorithmType)
+	w.WriteU32(o.ParametersBegin)
+	w.WriteU32(o.ParametersEnd)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *VertexData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Primary = r.ReadI32()
+	o.Chi2 = r.ReadF32()
+	o.Probability = r.ReadF32()
+	o.Position.UnmarshalROOT(r)
+	r.ReadArrayF32(o.CovMatrix[:])
+	o.AlgorithmType = r.ReadI32()
+	o.ParametersBegin = r.ReadU32()
+	o.ParametersEnd = r.ReadU32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// vertexBuffers holds the ROOT I/O buffers of VertexCollection values.
+type vertexBuffers struct {
+	data vertexDataVec
+	refs [1]objectIDVec
+	vec0 float32Vec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *vertexBuffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.vec0 = b.vec0[:0]
+	b.objs = b.objs[:0]
+}
+
+func (b *vertexBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+		{name + "_0", &b.vec0},
+	}
+}
+
+func (b *vertexBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*VertexCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *vertexBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*VertexCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := VertexData{
+			Primary:       o.Primary,
+			Chi2:          o.Chi2,
+			Probability:   o.Probability,
+			Position:      o.Position,
+			CovMatrix:     o.CovMatrix,
+			AlgorithmType: o.AlgorithmType,
+		}
+		d.ParametersBegin = uint32(len(b.vec0))
+		b.vec0 = append(b.vec0, o.Parameters...)
+		d.ParametersEnd = uint32(len(b.vec0))
+		b.refs[0] = append(b.refs[0], ids.get(o.AssociatedParticle))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *vertexBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &VertexCollection{Elems: make([]*Vertex, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]Vertex, len(b.data))
+		coll = &VertexCollection{Elems: make([]*Vertex, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.Primary = d.Primary
+		o.Chi2 = d.Chi2
+		o.Probability = d.Probability
+		o.Position = d.Position
+		o.CovMatrix = d.CovMatrix
+		o.AlgorithmType = d.AlgorithmType
+		if !podioRange(d.ParametersBegin, d.ParametersEnd, len(b.vec0)) {
+			return nil, fmt.Errorf("podio: invalid parameters range [%d, %d) (n=%d)", d.ParametersBegin, d.ParametersEnd, len(b.vec0))
+		}
+		o.Parameters = append([]float32(nil), b.vec0[d.ParametersBegin:d.ParametersEnd]...)
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *vertexBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*VertexCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*Vertex)
+		}
+		return nil
+	}
+	if len(b.refs[0]) != len(b.data) {
+		return fmt.Errorf("podio: invalid number of associatedParticle relations (got=%d, want=%d)", len(b.refs[0]), len(b.data))
+	}
+	for i := range b.data {
+		o := c.Elems[i]
+		o.AssociatedParticle, _ = get(b.refs[0][i]).(*ReconstructedParticle)
+	}
+	return nil
+}
+
+func (b *vertexBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*VertexCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// edm4hep::ReconstructedParticle
+// Reconstructed particle
+type ReconstructedParticle struct {
+	Type           int32                    // type of reconstructed particle. Check/set collection parameters ReconstructedParticleTypeNames and ReconstructedParticleTypeValues.
+	Energy         float32                  // [GeV] energy of the reconstructed particle. Four momentum state is not kept consistent internally.
+	Momentum       Vector3f                 // [GeV] particle momentum. Four momentum state is not kept consistent internally.
+	ReferencePoint Vector3f                 // [mm] reference, i.e. where the particle has been measured
+	Charge         float32                  // charge of the reconstructed particle.
+	Mass           float32                  // [GeV] mass of the reconstructed particle, set independently from four vector. Four momentum state is not kept consistent internally.
+	GoodnessOfPID  float32                  // overall goodness of the PID on a scale of [0;1]
+	CovMatrix      [10]float32              // cvariance matrix of the reconstructed particle 4vector (10 parameters). Stored as lower triangle matrix of the four momentum (px,py,pz,E), i.e. cov(px,px), cov(py,px), cov(py,py), ...
+	StartVertex    *Vertex                  // start vertex associated to this particle
+	ParticleIDUsed *ParticleID              // particle Id used for the kinematics of this particle
+	Clusters       []*Cluster               // clusters that have been used for this particle.
+	Tracks         []*Track                 // tracks that have been used for this particle.
+	Particles      []*ReconstructedParticle // reconstructed particles that have been combined to this particle.
+	ParticleIDs    []*ParticleID            // particle Ids (not sorted by their likelihood)
+}
+
+// ReconstructedParticleCollection is a collection of ReconstructedParticle.
+type ReconstructedParticleCollection struct {
+	Elems  []*ReconstructedParticle
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*ReconstructedParticleCollection) podioType() string { return "edm4hep::ReconstructedParticle" }
+func (c *ReconstructedParticleCollection) isSubset() bool  { return c.Subset }
+
+// ReconstructedParticleData is the POD data part of ReconstructedParticle, as stored in PODIO files.
+type ReconstructedParticleData struct {
+	Type             int32       `groot:"type"`
+	Energy           float32     `groot:"energy"`
+	Momentum         Vector3f    `groot:"momentum"`
+	ReferencePoint   Vector3f    `groot:"referencePoint"`
+	Charge           float32     `groot:"charge"`
+	Mass             float32     `groot:"mass"`
+	GoodnessOfPID    float32     `groot:"goodnessOfPID"`
+	CovMatrix        [10]float32 `groot:"covMatrix"`
+	ClustersBegin    uint32      `groot:"clusters_begin"`
+	ClustersEnd      uint32      `groot:"clusters_end"`
+	TracksBegin      uint32      `groot:"tracks_begin"`
+	TracksEnd        uint32      `groot:"tracks_end"`
+	ParticlesBegin   uint32      `groot:"particles_begin"`
+	ParticlesEnd     uint32      `groot:"particles_end"`
+	ParticleIDsBegin uint32      `groot:"particleIDs_begin"`
+	ParticleIDsEnd   uint32      `groot:"particleIDs_end"`
+}
+
+func (*ReconstructedParticleData) Class() string   { return "edm4hep::ReconstructedParticleData" }
+func (*ReconstructedParticleData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ReconstructedParticleData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.Type)
+	w.WriteF32(o.Energy)
+	o.Momentum.MarshalROOT(w)
+	o.ReferencePoint.MarshalROOT(w)
+	w.WriteF32(o.Charge)
+	w.WriteF32(o.Mass)
+	w.WriteF32(o.GoodnessOfPID)
+	w.WriteFastArrayF32(o.CovMatrix[:])
+	w.WriteU32(o.ClustersBegin)
+	w.WriteU32(o.ClustersEnd)
+	w.WriteU32(o.TracksBegin)
+	w.WriteU32(o.TracksEnd)
+	w.WriteU32(o.ParticlesBegin)
+	w.WriteU32(o.ParticlesEnd)
+	w.WriteU32(o.ParticleIDsBegin)
+	w.WriteU32(o.ParticleIDsEnd)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ReconstructedParticleData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Type = r.ReadI32()
+	o.Energy = r.ReadF32()
+	o.Momentum.UnmarshalROOT(r)
+	o.ReferencePoint.UnmarshalROOT(r)
+	o.Charge = r.ReadF32()
+	o.Mass = r.ReadF32()
+	o.GoodnessOfPID = r.ReadF32()
+	r.ReadArrayF32(o.CovMatrix[:])
+	o.ClustersBegin = r.ReadU32()
+	o.ClustersEnd = r.ReadU32()
+	o.TracksBegin = r.ReadU32()
+	o.TracksEnd = r.ReadU32()
+	o.ParticlesBegin = r.ReadU32()
+	o.ParticlesEnd = r.ReadU32()
+	o.ParticleIDsBegin = r.ReadU32()
+	o.ParticleIDsEnd = r.ReadU32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// reconstructedParticleBuffers holds the ROOT I/O buffers of ReconstructedParticleCollection values.
+type reconstructedParticleBuffers struct {
+	data reconstructedParticleDataVec
+	refs [6]objectIDVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *reconstructedParticleBuffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.objs = b.objs[:0]
+}
+
+func (b *reconstructedParticleBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+		{name + "#1", &b.refs[1]},
+		{name + "#2", &b.refs[2]},
+		{name + "#3", &b.refs[3]},
+		{name + "#4", &b.refs[4]},
+		{name + "#5", &b.refs[5]},
+	}
+}
+
+func (b *reconstructedParticleBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*ReconstructedParticleCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *reconstructedParticleBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*ReconstructedParticleCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := ReconstructedParticleData{
+			Type:           o.Type,
+			Energy:         o.Energy,
+			Momentum:       o.Momentum,
+			ReferencePoint: o.ReferencePoint,
+			Charge:         o.Charge,
+			Mass:           o.Mass,
+			GoodnessOfPID:  o.GoodnessOfPID,
+			CovMatrix:      o.CovMatrix,
+		}
+		d.ClustersBegin = uint32(len(b.refs[0]))
+		for _, v := range o.Clusters {
+			b.refs[0] = append(b.refs[0], ids.get(v))
+		}
+		d.ClustersEnd = uint32(len(b.refs[0]))
+		d.TracksBegin = uint32(len(b.refs[1]))
+		for _, v := range o.Tracks {
+			b.refs[1] = append(b.refs[1], ids.get(v))
+		}
+		d.TracksEnd = uint32(len(b.refs[1]))
+		d.ParticlesBegin = uint32(len(b.refs[2]))
+		for _, v := range o.Particles {
+			b.refs[2] = append(b.refs[2], ids.get(v))
+		}
+		d.ParticlesEnd = uint32(len(b.refs[2]))
+		d.ParticleIDsBegin = uint32(len(b.refs[3]))
+		for _, v := range o.ParticleIDs {
+			b.refs[3] = append(b.refs[3], ids.get(v))
+		}
+		d.ParticleIDsEnd = uint32(len(b.refs[3]))
+		b.refs[4] = append(b.refs[4], ids.get(o.StartVertex))
+		b.refs[5] = append(b.refs[5], ids.get(o.ParticleIDUsed))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *reconstructedParticleBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &ReconstructedParticleCollection{Elems: make([]*ReconstructedParticle, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]ReconstructedParticle, len(b.data))
+		coll = &ReconstructedParticleCollection{Elems: make([]*ReconstructedParticle, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.Type = d.Type
+		o.Energy = d.Energy
+		o.Momentum = d.Momentum
+		o.ReferencePoint = d.ReferencePoint
+		o.Charge = d.Charge
+		o.Mass = d.Mass
+		o.GoodnessOfPID = d.GoodnessOfPID
+		o.CovMatrix = d.CovMatrix
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *reconstructedParticleBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*ReconstructedParticleCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*ReconstructedParticle)
+		}
+		return nil
+	}
+	if len(b.refs[4]) != len(b.data) {
+		return fmt.Errorf("podio: invalid number of startVertex relations (got=%d, want=%d)", len(b.refs[4]), len(b.data))
+	}
+	if len(b.refs[5]) != len(b.data) {
+		return fmt.Errorf("podio: invalid number of particleIDUsed relations (got=%d, want=%d)", len(b.refs[5]), len(b.data))
+	}
+	for i := range b.data {
+		d := &b.data[i]
+		o := c.Elems[i]
+		if !podioRange(d.ClustersBegin, d.ClustersEnd, len(b.refs[0])) {
+			return fmt.Errorf("podio: invalid clusters range [%d, %d) (n=%d)", d.ClustersBegin, d.ClustersEnd, len(b.refs[0]))
+		}
+		for _, id := range b.refs[0][d.ClustersBegin:d.ClustersEnd] {
+			if v, ok := get(id).(*Cluster); ok {
+				o.Clusters = append(o.Clusters, v)
+			}
+		}
+		if !podioRange(d.TracksBegin, d.TracksEnd, len(b.refs[1])) {
+			return fmt.Errorf("podio: invalid tracks range [%d, %d) (n=%d)", d.TracksBegin, d.TracksEnd, len(b.refs[1]))
+		}
+		for _, id := range b.refs[1][d.TracksBegin:d.TracksEnd] {
+			if v, ok := get(id).(*Track); ok {
+				o.Tracks = append(o.Tracks, v)
+			}
+		}
+		if !podioRange(d.ParticlesBegin, d.ParticlesEnd, len(b.refs[2])) {
+			return fmt.Errorf("podio: invalid particles range [%d, %d) (n=%d)", d.ParticlesBegin, d.ParticlesEnd, len(b.refs[2]))
+		}
+		for _, id := range b.refs[2][d.ParticlesBegin:d.ParticlesEnd] {
+			if v, ok := get(id).(*ReconstructedParticle); ok {
+				o.Particles = append(o.Particles, v)
+			}
+		}
+		if !podioRange(d.ParticleIDsBegin, d.ParticleIDsEnd, len(b.refs[3])) {
+			return fmt.Errorf("podio: invalid particleIDs range [%d, %d) (n=%d)", d.ParticleIDsBegin, d.ParticleIDsEnd, len(b.refs[3]))
+		}
+		for _, id := range b.refs[3][d.ParticleIDsBegin:d.ParticleIDsEnd] {
+			if v, ok := get(id).(*ParticleID); ok {
+				o.ParticleIDs = append(o.ParticleIDs, v)
+			}
+		}
+		o.StartVertex, _ = get(b.refs[4][i]).(*Vertex)
+		o.ParticleIDUsed, _ = get(b.refs[5][i]).(*ParticleID)
+	}
+	return nil
+}
+
+func (b *reconstructedParticleBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*ReconstructedParticleCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// edm4hep::MCRecoParticleAssociation
+// Used to keep track of the correspondence between MC and reconstructed particles
+type MCRecoParticleAssociation struct {
+	Weight float32                // weight of this association
+	Rec    *ReconstructedParticle // reference to the reconstructed particle
+	Sim    *MCParticle            // reference to the Monte-Carlo particle
+}
+
+// MCRecoParticleAssociationCollection is a collection of MCRecoParticleAssociation.
+type MCRecoParticleAssociationCollection struct {
+	Elems  []*MCRecoParticleAssociation
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*MCRecoParticleAssociationCollection) podioType() string {
+	return "edm4hep::MCRecoParticleAssociation"
+}
+func (c *MCRecoParticleAssociationCollection) isSubset() bool { return c.Subset }
+
+// MCRecoParticleAssociationData is the POD data part of MCRecoParticleAssociation, as stored in PODIO files.
+type MCRecoParticleAssociationData struct {
+	Weight float32 `groot:"weight"`
+}
+
+func (*MCRecoParticleAssociationData) Class() string   { return "edm4hep::MCRecoParticleAssociationData" }
+func (*MCRecoParticleAssociationData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *MCRecoParticleAssociationData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteF32(o.Weight)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *MCRecoParticleAssociationData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Weight = r.ReadF32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// mCRecoParticleAssociationBuffers holds the ROOT I/O buffers of MCRecoParticleAssociationCollection values.
+type mCRecoParticleAssociationBuffers struct {
+	data mCRecoParticleAssociationDataVec
+	refs [2]objectIDVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *mCRecoParticleAssociationBuffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.objs = b.objs[:0]
+}
+
+func (b *mCRecoParticleAssociationBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+		{name + "#1", &b.refs[1]},
+	}
+}
+
+func (b *mCRecoParticleAssociationBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*MCRecoParticleAssociationCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *mCRecoParticleAssociationBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*MCRecoParticleAssociationCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := MCRecoParticleAssociationData{
+			Weight: o.Weight,
+		}
+		b.refs[0] = append(b.refs[0], ids.get(o.Rec))
+		b.refs[1] = append(b.refs[1], ids.get(o.Sim))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *mCRecoParticleAssociationBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &MCRecoParticleAssociationCollection{Elems: make([]*MCRecoParticleAssociation, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]MCRecoParticleAssociation, len(b.data))
+		coll = &MCRecoParticleAssociationCollection{Elems: make([]*MCRecoParticleAssociation, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.Weight = d.Weight
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *mCRecoParticleAssociationBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*MCRecoParticleAssociationCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*MCRecoParticleAssociation)
+		}
+		return nil
+	}
+	if len(b.refs[0]) != len(b.data) {
+		return fmt.Errorf("podio: invalid number of rec relations (got=%d, want=%d)", len(b.refs[0]), len(b.data))
+	}
+	if len(b.refs[1]) != len(b.data) {
+		return fmt.Errorf("podio: invalid number of sim relations (got=%d, want=%d)", len(b.refs[1]), len(b.data))
+	}
+	for i := range b.data {
+		o := c.Elems[i]
+		o.Rec, _ = get(b.refs[0][i]).(*ReconstructedParticle)
+		o.Sim, _ = get(b.refs[1][i]).(*MCParticle)
+	}
+	return nil
+}
+
+func (b *mCRecoParticleAssociationBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*MCRecoParticleAssociationCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// edm4hep::MCRecoTrackParticleAssociation
+// Association between a Track and a MCParticle
+type MCRecoTrackParticleAssociation struct {
+	Weight float32     // weight of this association
+	Rec    *Track      // reference to the track
+	Sim    *MCParticle // reference to the Monte-Carlo particle
+}
+
+// MCRecoTrackParticleAssociationCollection is a collection of MCRecoTrackParticleAssociation.
+type MCRecoTrackParticleAssociationCollection struct {
+	Elems  []*MCRecoTrackParticleAssociation
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*MCRecoTrackParticleAssociationCollection) podioType() string {
+	return "edm4hep::MCRecoTrackParticleAssociation"
+}
+func (c *MCRecoTrackParticleAssociationCollection) isSubset() bool { return c.Subset }
+
+// MCRecoTrackParticleAssociationData is the POD data part of MCRecoTrackParticleAssociation, as stored in PODIO files.
+type MCRecoTrackParticleAssociationData struct {
+	Weight float32 `groot:"weight"`
+}
+
+func (*MCRecoTrackParticleAssociationData) Class() string {
+	return "edm4hep::MCRecoTrackParticleAssociationData"
+}
+func (*MCRecoTrackParticleAssociationData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *MCRecoTrackParticleAssociationData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteF32(o.Weight)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *MCRecoTrackParticleAssociationData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Weight = r.ReadF32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// mCRecoTrackParticleAssociationBuffers holds the ROOT I/O buffers of MCRecoTrackParticleAssociationCollection values.
+type mCRecoTrackParticleAssociationBuffers struct {
+	data mCRecoTrackParticleAssociationDataVec
+	refs [2]objectIDVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *mCRecoTrackParticleAssociationBuffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.objs = b.objs[:0]
+}
+
+func (b *mCRecoTrackParticleAssociationBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+		{name + "#1", &b.refs[1]},
+	}
+}
+
+func (b *mCRecoTrackParticleAssociationBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*MCRecoTrackParticleAssociationCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *mCRecoTrackParticleAssociationBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*MCRecoTrackParticleAssociationCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := MCRecoTrackParticleAssociationData{
+			Weight: o.Weight,
+		}
+		b.refs[0] = append(b.refs[0], ids.get(o.Rec))
+		b.refs[1] = append(b.refs[1], ids.get(o.Sim))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *mCRecoTrackParticleAssociationBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &MCRecoTrackParticleAssociationCollection{Elems: make([]*MCRecoTrackParticleAssociation, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]MCRecoTrackParticleAssociation, len(b.data))
+		coll = &MCRecoTrackParticleAssociationCollection{Elems: make([]*MCRecoTrackParticleAssociation, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.Weight = d.Weight
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *mCRecoTrackParticleAssociationBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*MCRecoTrackParticleAssociationCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*MCRecoTrackParticleAssociation)
+		}
+		return nil
+	}
+	if len(b.refs[0]) != len(b.data) {
+		return fmt.Errorf("podio: invalid number of rec relations (got=%d, want=%d)", len(b.refs[0]), len(b.data))
+	}
+	if len(b.refs[1]) != len(b.data) {
+		return fmt.Errorf("podio: invalid number of sim relations (got=%d, want=%d)", len(b.refs[1]), len(b.data))
+	}
+	for i := range b.data {
+		o := c.Elems[i]
+		o.Rec, _ = get(b.refs[0][i]).(*Track)
+		o.Sim, _ = get(b.refs[1][i]).(*MCParticle)
+	}
+	return nil
+}
+
+func (b *mCRecoTrackParticleAssociationBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*MCRecoTrackParticleAssociationCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// edm4hep::MCRecoClusterParticleAssociation
+// Association between a Cluster and a MCParticle
+type MCRecoClusterParticleAssociation struct {
+	Weight float32     // weight of this association
+	Rec    *Cluster    // reference to the cluster
+	Sim    *MCParticle // reference to the Monte-Carlo particle
+}
+
+// MCRecoClusterParticleAssociationCollection is a collection of MCRecoClusterParticleAssociation.
+type MCRecoClusterParticleAssociationCollection struct {
+	Elems  []*MCRecoClusterParticleAssociation
+	Subset bool // whether the collection only references objects owned by other collections
+}
+
+func (*MCRecoClusterParticleAssociationCollection) podioType() string {
+	return "edm4hep::MCRecoClusterParticleAssociation"
+}
+func (c *MCRecoClusterParticleAssociationCollection) isSubset() bool { return c.Subset }
+
+// MCRecoClusterParticleAssociationData is the POD data part of MCRecoClusterParticleAssociation, as stored in PODIO files.
+type MCRecoClusterParticleAssociationData struct {
+	Weight float32 `groot:"weight"`
+}
+
+func (*MCRecoClusterParticleAssociationData) Class() string {
+	return "edm4hep::MCRecoClusterParticleAssociationData"
+}
+func (*MCRecoClusterParticleAssociationData) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *MCRecoClusterParticleAssociationData) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteF32(o.Weight)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *MCRecoClusterParticleAssociationData) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Weight = r.ReadF32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// mCRecoClusterParticleAssociationBuffers holds the ROOT I/O buffers of MCRecoClusterParticleAssociationCollection values.
+type mCRecoClusterParticleAssociationBuffers struct {
+	data mCRecoClusterParticleAssociationDataVec
+	refs [2]objectIDVec
+	objs objectIDVec // ObjectIDs of the elements of a subset collection
+}
+
+func (b *mCRecoClusterParticleAssociationBuffers) reset() {
+	b.data = b.data[:0]
+	for i := range b.refs {
+		b.refs[i] = b.refs[i][:0]
+	}
+	b.objs = b.objs[:0]
+}
+
+func (b *mCRecoClusterParticleAssociationBuffers) branches(name string, subset bool) []podioBranch {
+	if subset {
+		return []podioBranch{{name + "_objIdx", &b.objs}}
+	}
+	return []podioBranch{
+		{name, &b.data},
+		{name + "#0", &b.refs[0]},
+		{name + "#1", &b.refs[1]},
+	}
+}
+
+func (b *mCRecoClusterParticleAssociationBuffers) index(coll interface{}, id int32, ids podioIDs) {
+	c := coll.(*MCRecoClusterParticleAssociationCollection)
+	if c.Subset {
+		return
+	}
+	for i, o := range c.Elems {
+		ids[o] = ObjectID{Index: int32(i), CollectionID: id}
+	}
+}
+
+func (b *mCRecoClusterParticleAssociationBuffers) fill(coll interface{}, ids podioIDs) {
+	c := coll.(*MCRecoClusterParticleAssociationCollection)
+	if c.Subset {
+		for _, o := range c.Elems {
+			b.objs = append(b.objs, ids.get(o))
+		}
+		return
+	}
+	for _, o := range c.Elems {
+		d := MCRecoClusterParticleAssociationData{
+			Weight: o.Weight,
+		}
+		b.refs[0] = append(b.refs[0], ids.get(o.Rec))
+		b.refs[1] = append(b.refs[1], ids.get(o.Sim))
+		b.data = append(b.data, d)
+	}
+}
+
+func (b *mCRecoClusterParticleAssociationBuffers) create(subset bool) (interface{}, error) {
+	if subset {
+		return &MCRecoClusterParticleAssociationCollection{Elems: make([]*MCRecoClusterParticleAssociation, len(b.objs)), Subset: true}, nil
+	}
+	var (
+		objs = make([]MCRecoClusterParticleAssociation, len(b.data))
+		coll = &MCRecoClusterParticleAssociationCollection{Elems: make([]*MCRecoClusterParticleAssociation, len(objs))}
+	)
+	for i := range b.data {
+		d := &b.data[i]
+		o := &objs[i]
+		o.Weight = d.Weight
+		coll.Elems[i] = o
+	}
+	return coll, nil
+}
+
+func (b *mCRecoClusterParticleAssociationBuffers) resolve(coll interface{}, get func(id ObjectID) interface{}) error {
+	c := coll.(*MCRecoClusterParticleAssociationCollection)
+	if c.Subset {
+		for i, id := range b.objs {
+			c.Elems[i], _ = get(id).(*MCRecoClusterParticleAssociation)
+		}
+		return nil
+	}
+	if len(b.refs[0]) != len(b.data) {
+		return fmt.Errorf("podio: invalid number of rec relations (got=%d, want=%d)", len(b.refs[0]), len(b.data))
+	}
+	if len(b.refs[1]) != len(b.data) {
+		return fmt.Errorf("podio: invalid number of sim relations (got=%d, want=%d)", len(b.refs[1]), len(b.data))
+	}
+	for i := range b.data {
+		o := c.Elems[i]
+		o.Rec, _ = get(b.refs[0][i]).(*Cluster)
+		o.Sim, _ = get(b.refs[1][i]).(*MCParticle)
+	}
+	return nil
+}
+
+func (b *mCRecoClusterParticleAssociationBuffers) at(coll interface{}, i int32) interface{} {
+	c := coll.(*MCRecoClusterParticleAssociationCollection)
+	if i < 0 || int(i) >= len(c.Elems) {
+		return nil
+	}
+	return c.Elems[i]
+}
+
+// ObjectID identifies an object stored in a PODIO collection.
+type ObjectID struct {
+	Index        int32 // index of the object in its collection
+	CollectionID int32 // ID of the collection holding the object
+}
+
+// invalidObjectID is the ObjectID of objects that are not stored in any
+// written collection.
+var invalidObjectID = ObjectID{Index: -2, CollectionID: -2}
+
+// CollectionIDTable associates the names of the collections of a PODIO file
+// with their IDs.
+type CollectionIDTable struct {
+	IDs   []int32
+	Names []string
+}
+
+func (*CollectionIDTable) Class() string   { return "podio::CollectionIDTable" }
+func (*CollectionIDTable) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *CollectionIDTable) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	o.WStreamROOT(w)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *CollectionIDTable) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.RStreamROOT(r)
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// WStreamROOT implements rbytes.WStreamer.
+// WStreamROOT writes the members of the table, without any version header,
+// as expected for top-level branches.
+func (o *CollectionIDTable) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.IDs)))
+		w.WriteFastArrayI32(o.IDs)
+		w.SetByteCount(pos, "vector<int>")
+	}
+	{
+		pos := w.WriteVersion(rvers.StreamerInfo)
+		w.WriteI32(int32(len(o.Names)))
+		for _, v := range o.Names {
+			w.WriteString(v)
+		}
+		w.SetByteCount(pos, "vector<string>")
+	}
+	return w.Err()
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (o *CollectionIDTable) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	{
+		start := r.Pos()
+		_, pos, bcnt := r.ReadVersion("vector<int>")
+		o.IDs = rbytes.ResizeI32(o.IDs, int(r.ReadI32()))
+		r.ReadArrayI32(o.IDs)
+		r.CheckByteCount(pos, bcnt, start, "vector<int>")
+	}
+	{
+		start := r.Pos()
+		_, pos, bcnt := r.ReadVersion("vector<string>")
+		o.Names = rbytes.ResizeStr(o.Names, int(r.ReadI32()))
+		for i := range o.Names {
+			o.Names[i] = r.ReadString()
+		}
+		r.CheckByteCount(pos, bcnt, start, "vector<string>")
+	}
+	return r.Err()
+}
+
+// collectionTypeInfo describes the type of a collection stored in a
+// PODIO file.
+type collectionTypeInfo struct {
+	ID     int32  // collection ID
+	Type   string // C++ type of the collection
+	Subset bool   // whether the collection is a subset collection
+}
+
+// podioBranch describes a ROOT branch holding (a part of) a collection.
+type podioBranch struct {
+	name string
+	ptr  interface{}
+}
+
+// podioIDs associates the objects of an event with their ObjectID.
+type podioIDs map[interface{}]ObjectID
+
+func (ids podioIDs) get(o interface{}) ObjectID {
+	id, ok := ids[o]
+	if !ok {
+		return invalidObjectID
+	}
+	return id
+}
+
+func podioRange(beg, end uint32, n int) bool {
+	return beg <= end && int(end) <= n
+}
+
+// podioCollection is the interface implemented by all generated collections.
+type podioCollection interface {
+	podioType() string
+	isSubset() bool
+}
+
+// podioBuffers is the interface implemented by the ROOT I/O buffers
+// of the generated collections.
+type podioBuffers interface {
+	// reset clears the buffers before an event is filled.
+	reset()
+
+	// branches returns the ROOT branches associated with the named collection.
+	branches(name string, subset bool) []podioBranch
+
+	// index records the ObjectIDs of the objects held by coll.
+	index(coll interface{}, id int32, ids podioIDs)
+
+	// fill fills the buffers with the content of coll.
+	fill(coll interface{}, ids podioIDs)
+
+	// create creates a new collection from the buffers.
+	create(subset bool) (interface{}, error)
+
+	// resolve resolves the relations of the objects held by coll.
+	resolve(coll interface{}, get func(id ObjectID) interface{}) error
+
+	// at returns the i-th object held by coll.
+	at(coll interface{}, i int32) interface{}
+}
+
+// podioColl describes a collection stored in a PODIO file.
+type podioColl struct {
+	name   string
+	id     int32
+	typ    string
+	subset bool
+	bufs   podioBuffers
+}
+
+// Event is a PODIO event: a set of named collections.
+type Event struct {
+	names []string
+	colls map[string]interface{}
+}
+
+// Names returns the names of the collections held by this event.
+func (evt *Event) Names() []string {
+	return evt.names
+}
+
+// Get returns the collection labelled name.
+func (evt *Event) Get(name string) interface{} {
+	return evt.colls[name]
+}
+
+// Has returns whether this event has a collection named name.
+func (evt *Event) Has(name string) bool {
+	_, ok := evt.colls[name]
+	return ok
+}
+
+// Add attaches the (pointer to the) collection coll to this event,
+// with the given name.
+// Add panics if there is already a collection labelled with the same name.
+func (evt *Event) Add(name string, coll interface{}) {
+	if _, dup := evt.colls[name]; dup {
+		panic(fmt.Errorf("podio: duplicate key %q", name))
+	}
+	evt.names = append(evt.names, name)
+	if evt.colls == nil {
+		evt.colls = make(map[string]interface{})
+	}
+	evt.colls[name] = coll
+}
+
+// Reader reads PODIO events from a ROOT file.
+type Reader struct {
+	tree  rtree.Tree
+	opts  []rtree.ReadOption
+	colls []*podioColl
+}
+
+// NewReader creates a new PODIO reader, reading events from the
+// "events" and "podio_metadata" trees stored in dir.
+// Collections with types unknown to this package are skipped.
+func NewReader(dir riofs.Directory, opts ...rtree.ReadOption) (*Reader, error) {
+	var (
+		table CollectionIDTable
+		infos collectionTypeInfoVec
+	)
+
+	meta, err := podioTree(dir, "podio_metadata")
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := rtree.NewReader(meta, []rtree.ReadVar{
+		{Name: "CollectionIDs", Value: &table},
+		{Name: "CollectionTypeInfo", Value: &infos},
+	}, rtree.WithRange(0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("podio: could not create metadata reader: %w", err)
+	}
+	defer r.Close()
+
+	err = r.Read(func(rtree.RCtx) error { return nil })
+	if err != nil {
+		return nil, fmt.Errorf("podio: could not read metadata: %w", err)
+	}
+
+	if len(table.IDs) != len(table.Names) {
+		return nil, fmt.Errorf(
+			"podio: invalid collection IDs table (ids=%d, names=%d)",
+			len(table.IDs), len(table.Names),
+		)
+	}
+
+	tree, err := podioTree(dir, "events")
+	if err != nil {
+		return nil, err
+	}
+
+	types := make(map[int32]collectionTypeInfo, len(infos))
+	for _, info := range infos {
+		types[info.ID] = info
+	}
+
+	rd := &Reader{tree: tree, opts: opts}
+	for i, name := range table.Names {
+		id := table.IDs[i]
+		info, ok := types[id]
+		if !ok {
+			return nil, fmt.Errorf("podio: no type information for collection %q", name)
+		}
+		typ := strings.TrimSuffix(info.Type, "Collection")
+		newBuffers, ok := podioTypes[typ]
+		if !ok {
+			continue
+		}
+		rd.colls = append(rd.colls, &podioColl{
+			name:   name,
+			id:     id,
+			typ:    typ,
+			subset: info.Subset,
+			bufs:   newBuffers(),
+		})
+	}
+
+	return rd, nil
+}
+
+func podioTree(dir riofs.Directory, name string) (rtree.Tree, error) {
+	o, err := dir.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("podio: could not retrieve tree %q: %w", name, err)
+	}
+
+	tree, ok := o.(rtree.Tree)
+	if !ok {
+		return nil, fmt.Errorf("podio: object %q is not a tree (type=%s)", name, o.Class())
+	}
+
+	return tree, nil
+}
+
+// Entries returns the number of events stored in the file.
+func (r *Reader) Entries() int64 {
+	return r.tree.Entries()
+}
+
+// Read reads the events selected by the reader options and calls f
+// for each of them.
+func (r *Reader) Read(f func(evt *Event) error) error {
+	var rvars []rtree.ReadVar
+	for _, c := range r.colls {
+		for _, b := range c.bufs.branches(c.name, c.subset) {
+			rvars = append(rvars, rtree.ReadVar{Name: b.name, Value: b.ptr})
+		}
+	}
+
+	rr, err := rtree.NewReader(r.tree, rvars, r.opts...)
+	if err != nil {
+		return fmt.Errorf("podio: could not create events reader: %w", err)
+	}
+	defer rr.Close()
+
+	var (
+		bufs  = make(map[int32]podioBuffers, len(r.colls))
+		colls = make(map[int32]interface{}, len(r.colls))
+		get   = func(id ObjectID) interface{} {
+			b, ok := bufs[id.CollectionID]
+			if !ok {
+				return nil
+			}
+			return b.at(colls[id.CollectionID], id.Index)
+		}
+	)
+	for _, c := range r.colls {
+		bufs[c.id] = c.bufs
+	}
+
+	err = rr.Read(func(ctx rtree.RCtx) error {
+		evt := new(Event)
+		for _, c := range r.colls {
+			coll, err := c.bufs.create(c.subset)
+			if err != nil {
+				return fmt.Errorf("podio: could not create collection %q: %w", c.name, err)
+			}
+			colls[c.id] = coll
+			evt.Add(c.name, coll)
+		}
+
+		for _, c := range r.colls {
+			err := c.bufs.resolve(colls[c.id], get)
+			if err != nil {
+				return fmt.Errorf("podio: could not resolve relations of collection %q: %w", c.name, err)
+			}
+		}
+
+		return f(evt)
+	})
+	if err != nil {
+		return fmt.Errorf("podio: could not read events: %w", err)
+	}
+
+	return nil
+}
+
+// Writer writes PODIO events to a ROOT file.
+type Writer struct {
+	dir   riofs.Directory
+	opts  []rtree.WriteOption
+	tree  rtree.Writer
+	colls []*podioColl
+	ids   podioIDs
+}
+
+// NewWriter creates a new PODIO writer, writing events to the
+// "events" and "podio_metadata" trees of dir.
+// The set of collections written to the file is defined by the first
+// written event.
+func NewWriter(dir riofs.Directory, opts ...rtree.WriteOption) *Writer {
+	return &Writer{
+		dir:  dir,
+		opts: opts,
+		ids:  make(podioIDs),
+	}
+}
+
+func (w *Writer) init(evt *Event) error {
+	var wvars []rtree.WriteVar
+	for i, name := range evt.Names() {
+		coll, ok := evt.Get(name).(podioCollection)
+		if !ok {
+			return fmt.Errorf("podio: invalid collection %q (type=%T)", name, evt.Get(name))
+		}
+		c := &podioColl{
+			name:   name,
+			id:     int32(i + 1),
+			typ:    coll.podioType(),
+			subset: coll.isSubset(),
+		}
+		c.bufs = podioTypes[c.typ]()
+		w.colls = append(w.colls, c)
+		for _, b := range c.bufs.branches(c.name, c.subset) {
+			wvars = append(wvars, rtree.WriteVar{Name: b.name, Value: b.ptr})
+		}
+	}
+
+	tree, err := rtree.NewWriter(w.dir, "events", wvars, w.opts...)
+	if err != nil {
+		return fmt.Errorf("podio: could not create events tree: %w", err)
+	}
+	w.tree = tree
+
+	return nil
+}
+
+// Write writes the provided event to the file.
+// Collections of the first event that are missing from evt are written
+// as empty collections.
+func (w *Writer) Write(evt *Event) error {
+	if w.tree == nil {
+		err := w.init(evt)
+		if err != nil {
+			return err
+		}
+	}
+
+	for k := range w.ids {
+		delete(w.ids, k)
+	}
+
+	known := make(map[string]struct{}, len(w.colls))
+	colls := make([]interface{}, len(w.colls))
+	for i, c := range w.colls {
+		c.bufs.reset()
+		known[c.name] = struct{}{}
+		if !evt.Has(c.name) {
+			continue
+		}
+		coll, ok := evt.Get(c.name).(podioCollection)
+		if !ok || coll.podioType() != c.typ || coll.isSubset() != c.subset {
+			return fmt.Errorf("podio: invalid collection %q (type=%T)", c.name, evt.Get(c.name))
+		}
+		colls[i] = coll
+		c.bufs.index(coll, c.id, w.ids)
+	}
+
+	for _, name := range evt.Names() {
+		if _, ok := known[name]; !ok {
+			return fmt.Errorf("podio: unknown collection %q", name)
+		}
+	}
+
+	for i, c := range w.colls {
+		if colls[i] == nil {
+			continue
+		}
+		c.bufs.fill(colls[i], w.ids)
+	}
+
+	_, err := w.tree.Write()
+	if err != nil {
+		return fmt.Errorf("podio: could not write event: %w", err)
+	}
+
+	return nil
+}
+
+// Close writes the PODIO metadata and closes the writer.
+// Close does not close the underlying ROOT file.
+func (w *Writer) Close() error {
+	if w.tree != nil {
+		err := w.tree.Close()
+		if err != nil {
+			return fmt.Errorf("podio: could not close events tree: %w", err)
+		}
+	}
+
+	var (
+		table CollectionIDTable
+		infos = make(collectionTypeInfoVec, 0, len(w.colls))
+	)
+	for _, c := range w.colls {
+		table.IDs = append(table.IDs, c.id)
+		table.Names = append(table.Names, c.name)
+		infos = append(infos, collectionTypeInfo{
+			ID:     c.id,
+			Type:   c.typ + "Collection",
+			Subset: c.subset,
+		})
+	}
+
+	meta, err := rtree.NewWriter(w.dir, "podio_metadata", []rtree.WriteVar{
+		{Name: "CollectionIDs", Value: &table},
+		{Name: "CollectionTypeInfo", Value: &infos},
+	})
+	if err != nil {
+		return fmt.Errorf("podio: could not create metadata tree: %w", err)
+	}
+	defer meta.Close()
+
+	_, err = meta.Write()
+	if err != nil {
+		return fmt.Errorf("podio: could not write metadata: %w", err)
+	}
+
+	err = meta.Close()
+	if err != nil {
+		return fmt.Errorf("podio: could not close metadata tree: %w", err)
+	}
+
+	return nil
+}
+
+func (*ObjectID) Class() string   { return "podio::ObjectID" }
+func (*ObjectID) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *ObjectID) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.Index)
+	w.WriteI32(o.CollectionID)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *ObjectID) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.Index = r.ReadI32()
+	o.CollectionID = r.ReadI32()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+func (*collectionTypeInfo) Class() string   { return "tuple<int,string,bool>" }
+func (*collectionTypeInfo) RVersion() int16 { return 1 }
+
+// MarshalROOT implements rbytes.Marshaler
+func (o *collectionTypeInfo) MarshalROOT(w *rbytes.WBuffer) (int, error) {
+	if w.Err() != nil {
+		return 0, w.Err()
+	}
+
+	pos := w.WriteVersion(o.RVersion())
+	w.WriteI32(o.ID)
+	w.WriteSTLString(o.Type)
+	w.WriteBool(o.Subset)
+	return w.SetByteCount(pos, o.Class())
+}
+
+// UnmarshalROOT implements rbytes.Unmarshaler
+func (o *collectionTypeInfo) UnmarshalROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(o.Class())
+	o.ID = r.ReadI32()
+	o.Type = r.ReadSTLString()
+	o.Subset = r.ReadBool()
+	r.CheckByteCount(pos, bcnt, start, o.Class())
+	return r.Err()
+}
+
+// eventHeaderDataVec is a vector<edm4hep::EventHeaderData>.
+type eventHeaderDataVec []EventHeaderData
+
+func (*eventHeaderDataVec) Class() string { return "vector<edm4hep::EventHeaderData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *eventHeaderDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *eventHeaderDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]EventHeaderData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// mCParticleDataVec is a vector<edm4hep::MCParticleData>.
+type mCParticleDataVec []MCParticleData
+
+func (*mCParticleDataVec) Class() string { return "vector<edm4hep::MCParticleData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *mCParticleDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *mCParticleDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]MCParticleData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// simTrackerHitDataVec is a vector<edm4hep::SimTrackerHitData>.
+type simTrackerHitDataVec []SimTrackerHitData
+
+func (*simTrackerHitDataVec) Class() string { return "vector<edm4hep::SimTrackerHitData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *simTrackerHitDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *simTrackerHitDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]SimTrackerHitData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// caloHitContributionDataVec is a vector<edm4hep::CaloHitContributionData>.
+type caloHitContributionDataVec []CaloHitContributionData
+
+func (*caloHitContributionDataVec) Class() string { return "vector<edm4hep::CaloHitContributionData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *caloHitContributionDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *caloHitContributionDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]CaloHitContributionData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// simCalorimeterHitDataVec is a vector<edm4hep::SimCalorimeterHitData>.
+type simCalorimeterHitDataVec []SimCalorimeterHitData
+
+func (*simCalorimeterHitDataVec) Class() string { return "vector<edm4hep::SimCalorimeterHitData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *simCalorimeterHitDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *simCalorimeterHitDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]SimCalorimeterHitData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// calorimeterHitDataVec is a vector<edm4hep::CalorimeterHitData>.
+type calorimeterHitDataVec []CalorimeterHitData
+
+func (*calorimeterHitDataVec) Class() string { return "vector<edm4hep::CalorimeterHitData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *calorimeterHitDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *calorimeterHitDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]CalorimeterHitData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// particleIDDataVec is a vector<edm4hep::ParticleIDData>.
+type particleIDDataVec []ParticleIDData
+
+func (*particleIDDataVec) Class() string { return "vector<edm4hep::ParticleIDData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *particleIDDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *particleIDDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ParticleIDData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// float32Vec is a vector<float>.
+type float32Vec []float32
+
+func (*float32Vec) Class() string { return "vector<float>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *float32Vec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	w.WriteFastArrayF32(*v)
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *float32Vec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	*v = rbytes.ResizeF32(*v, int(r.ReadI32()))
+	r.ReadArrayF32(*v)
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// clusterDataVec is a vector<edm4hep::ClusterData>.
+type clusterDataVec []ClusterData
+
+func (*clusterDataVec) Class() string { return "vector<edm4hep::ClusterData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *clusterDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *clusterDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ClusterData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// trackerHitDataVec is a vector<edm4hep::TrackerHitData>.
+type trackerHitDataVec []TrackerHitData
+
+func (*trackerHitDataVec) Class() string { return "vector<edm4hep::TrackerHitData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *trackerHitDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *trackerHitDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]TrackerHitData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// trackDataVec is a vector<edm4hep::TrackData>.
+type trackDataVec []TrackData
+
+func (*trackDataVec) Class() string { return "vector<edm4hep::TrackData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *trackDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *trackDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]TrackData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// int32Vec is a vector<int>.
+type int32Vec []int32
+
+func (*int32Vec) Class() string { return "vector<int>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *int32Vec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	w.WriteFastArrayI32(*v)
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *int32Vec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	*v = rbytes.ResizeI32(*v, int(r.ReadI32()))
+	r.ReadArrayI32(*v)
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// trackStateVec is a vector<edm4hep::TrackState>.
+type trackStateVec []TrackState
+
+func (*trackStateVec) Class() string { return "vector<edm4hep::TrackState>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *trackStateVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *trackStateVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]TrackState, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// quantityVec is a vector<edm4hep::Quantity>.
+type quantityVec []Quantity
+
+func (*quantityVec) Class() string { return "vector<edm4hep::Quantity>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *quantityVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *quantityVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]Quantity, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// vertexDataVec is a vector<edm4hep::VertexData>.
+type vertexDataVec []VertexData
+
+func (*vertexDataVec) Class() string { return "vector<edm4hep::VertexData>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *vertexDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *vertexDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]VertexData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// reconstructedParticleDataVec is a vector<edm4hep::ReconstructedParticleData>.
+type reconstructedParticleDataVec []ReconstructedParticleData
+
+func (*reconstructedParticleDataVec) Class() string {
+	return "vector<edm4hep::ReconstructedParticleData>"
+}
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *reconstructedParticleDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *reconstructedParticleDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ReconstructedParticleData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// mCRecoParticleAssociationDataVec is a vector<edm4hep::MCRecoParticleAssociationData>.
+type mCRecoParticleAssociationDataVec []MCRecoParticleAssociationData
+
+func (*mCRecoParticleAssociationDataVec) Class() string {
+	return "vector<edm4hep::MCRecoParticleAssociationData>"
+}
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *mCRecoParticleAssociationDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *mCRecoParticleAssociationDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]MCRecoParticleAssociationData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// mCRecoTrackParticleAssociationDataVec is a vector<edm4hep::MCRecoTrackParticleAssociationData>.
+type mCRecoTrackParticleAssociationDataVec []MCRecoTrackParticleAssociationData
+
+func (*mCRecoTrackParticleAssociationDataVec) Class() string {
+	return "vector<edm4hep::MCRecoTrackParticleAssociationData>"
+}
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *mCRecoTrackParticleAssociationDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *mCRecoTrackParticleAssociationDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]MCRecoTrackParticleAssociationData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// mCRecoClusterParticleAssociationDataVec is a vector<edm4hep::MCRecoClusterParticleAssociationData>.
+type mCRecoClusterParticleAssociationDataVec []MCRecoClusterParticleAssociationData
+
+func (*mCRecoClusterParticleAssociationDataVec) Class() string {
+	return "vector<edm4hep::MCRecoClusterParticleAssociationData>"
+}
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *mCRecoClusterParticleAssociationDataVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *mCRecoClusterParticleAssociationDataVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]MCRecoClusterParticleAssociationData, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// objectIDVec is a vector<podio::ObjectID>.
+type objectIDVec []ObjectID
+
+func (*objectIDVec) Class() string { return "vector<podio::ObjectID>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *objectIDVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *objectIDVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]ObjectID, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// collectionTypeInfoVec is a vector<tuple<int,string,bool>>.
+type collectionTypeInfoVec []collectionTypeInfo
+
+func (*collectionTypeInfoVec) Class() string { return "vector<tuple<int,string,bool>>" }
+
+// WStreamROOT implements rbytes.WStreamer
+func (v *collectionTypeInfoVec) WStreamROOT(w *rbytes.WBuffer) error {
+	if w.Err() != nil {
+		return w.Err()
+	}
+
+	pos := w.WriteVersion(rvers.StreamerInfo)
+	w.WriteI32(int32(len(*v)))
+	for i := range *v {
+		(*v)[i].MarshalROOT(w)
+	}
+	_, err := w.SetByteCount(pos, v.Class())
+	return err
+}
+
+// RStreamROOT implements rbytes.RStreamer
+func (v *collectionTypeInfoVec) RStreamROOT(r *rbytes.RBuffer) error {
+	if r.Err() != nil {
+		return r.Err()
+	}
+
+	start := r.Pos()
+	_, pos, bcnt := r.ReadVersion(v.Class())
+	n := int(r.ReadI32())
+	if n > cap(*v) {
+		*v = make([]collectionTypeInfo, n)
+	}
+	*v = (*v)[:n]
+	for i := range *v {
+		(*v)[i].UnmarshalROOT(r)
+	}
+	r.CheckByteCount(pos, bcnt, start, v.Class())
+	return r.Err()
+}
+
+// podioTypes associates the C++ name of each data type with a function
+// creating its ROOT I/O buffers.
+var podioTypes = map[string]func() podioBuffers{
+	"edm4hep::EventHeader":                      func() podioBuffers { return new(eventHeaderBuffers) },
+	"edm4hep::MCParticle":                       func() podioBuffers { return new(mCParticleBuffers) },
+	"edm4hep::SimTrackerHit":                    func() podioBuffers { return new(simTrackerHitBuffers) },
+	"edm4hep::CaloHitContribution":              func() podioBuffers { return new(caloHitContributionBuffers) },
+	"edm4hep::SimCalorimeterHit":                func() podioBuffers { return new(simCalorimeterHitBuffers) },
+	"edm4hep::CalorimeterHit":                   func() podioBuffers { return new(calorimeterHitBuffers) },
+	"edm4hep::ParticleID":                       func() podioBuffers { return new(particleIDBuffers) },
+	"edm4hep::Cluster":                          func() podioBuffers { return new(clusterBuffers) },
+	"edm4hep::TrackerHit":                       func() podioBuffers { return new(trackerHitBuffers) },
+	"edm4hep::Track":                            func() podioBuffers { return new(trackBuffers) },
+	"edm4hep::Vertex":                           func() podioBuffers { return new(vertexBuffers) },
+	"edm4hep::ReconstructedParticle":            func() podioBuffers { return new(reconstructedParticleBuffers) },
+	"edm4hep::MCRecoParticleAssociation":        func() podioBuffers { return new(mCRecoParticleAssociationBuffers) },
+	"edm4hep::MCRecoTrackParticleAssociation":   func() podioBuffers { return new(mCRecoTrackParticleAssociationBuffers) },
+	"edm4hep::MCRecoClusterParticleAssociation": func() podioBuffers { return new(mCRecoClusterParticleAssociationBuffers) },
+}
+
+func init() {
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("podio::CollectionIDTable", 1, 0, []rbytes.StreamerElement{
+		rdict.NewCxxStreamerSTL(rdict.Element{Name: *rbase.NewNamed("m_collectionIDs", ""), Type: rmeta.Streamer, Size: 24, EName: "vector<int>"}.New(), rmeta.STLvector, rmeta.Int),
+		rdict.NewCxxStreamerSTL(rdict.Element{Name: *rbase.NewNamed("m_names", ""), Type: rmeta.Streamer, Size: 24, EName: "vector<string>"}.New(), rmeta.STLvector, rmeta.Object),
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::Vector3f", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("x", ""), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("y", ""), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("z", ""), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::Vector3d", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("x", ""), Type: rmeta.Double, Size: 8, EName: "double"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("y", ""), Type: rmeta.Double, Size: 8, EName: "double"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("z", ""), Type: rmeta.Double, Size: 8, EName: "double"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::Vector2i", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("a", ""), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("b", ""), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::TrackState", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("location", "location of the track state (AtOther, AtIP, AtFirstHit, AtLastHit, AtCalorimeter or AtVertex)"), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("D0", "transverse impact parameter"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("phi", "azimuthal angle"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("omega", "is the signed curvature of the track in [1/mm]."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("Z0", "longitudinal impact parameter"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("tanLambda", "lambda is the dip angle of the track in r-z"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("referencePoint", "Reference point of the track parameters, e.g. the origin at the IP, or the position  of the first/last hits or the entry point into the calorimeter. [mm]"), Type: rmeta.Any, Size: 12, EName: "edm4hep::Vector3f"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("covMatrix", "lower triangular covariance matrix of the track parameters."), Type: rmeta.OffsetL + rmeta.Float, Size: 60, ArrLen: 15, ArrDim: 1, MaxIdx: [5]int32{15, 0, 0, 0, 0}, EName: "float"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::Quantity", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("type", "flag identifying how to interpret the quantity"), Type: rmeta.Short, Size: 2, EName: "short"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("value", "value of the quantity"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("error", "error on the value of the quantity"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::EventHeaderData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("eventNumber", "event number"), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("runNumber", "run number"), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("timeStamp", "time stamp"), Type: rmeta.ULong64, Size: 8, EName: "ULong64_t"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("weight", "event weight"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::MCParticleData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("PDG", "PDG code of the particle"), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("generatorStatus", "status of the particle as defined by the generator"), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("simulatorStatus", "status of the particle from the simulation program - use BIT constants below"), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("charge", "particle charge"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("time", "creation time of the particle in [ns] wrt. the event, e.g. for preassigned decays or decays in flight from the simulator."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("mass", "mass of the particle in [GeV]"), Type: rmeta.Double, Size: 8, EName: "double"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("vertex", "production vertex of the particle in [mm]."), Type: rmeta.Any, Size: 24, EName: "edm4hep::Vector3d"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("endpoint", "endpoint of the particle in [mm]"), Type: rmeta.Any, Size: 24, EName: "edm4hep::Vector3d"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("momentum", "particle 3-momentum at the production vertex in [GeV]"), Type: rmeta.Any, Size: 12, EName: "edm4hep::Vector3f"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("momentumAtEndpoint", "particle 3-momentum at the endpoint in [GeV]"), Type: rmeta.Any, Size: 12, EName: "edm4hep::Vector3f"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("spin", "spin (helicity) vector of the particle."), Type: rmeta.Any, Size: 12, EName: "edm4hep::Vector3f"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("colorFlow", "color flow as defined by the generator"), Type: rmeta.Any, Size: 8, EName: "edm4hep::Vector2i"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("parents_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("parents_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("daughters_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("daughters_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::SimTrackerHitData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("cellID", "ID of the sensor that created this hit"), Type: rmeta.ULong64, Size: 8, EName: "ULong64_t"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("EDep", "energy deposited in the hit [GeV]."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("time", "proper time of the hit in the lab frame in [ns]."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("pathLength", "path length of the particle in the sensitive material that resulted in this hit."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("quality", "quality bit flag."), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("position", "the hit position in [mm]."), Type: rmeta.Any, Size: 24, EName: "edm4hep::Vector3d"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("momentum", "the 3-momentum of the particle at the hits position in [GeV]"), Type: rmeta.Any, Size: 12, EName: "edm4hep::Vector3f"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::CaloHitContributionData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("PDG", "PDG code of the shower particle that caused this contribution."), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("energy", "energy in [GeV] of the this contribution"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("time", "time in [ns] of this contribution"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("stepPosition", "position of this energy deposition (step) [mm]"), Type: rmeta.Any, Size: 12, EName: "edm4hep::Vector3f"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::SimCalorimeterHitData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("cellID", "ID of the sensor that created this hit"), Type: rmeta.ULong64, Size: 8, EName: "ULong64_t"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("energy", "energy of the hit in [GeV]."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("position", "position of the hit in world coordinates in [mm]."), Type: rmeta.Any, Size: 12, EName: "edm4hep::Vector3f"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("contributions_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("contributions_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::CalorimeterHitData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("cellID", "detector specific (geometrical) cell id."), Type: rmeta.ULong64, Size: 8, EName: "ULong64_t"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("energy", "energy of the hit in [GeV]."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("energyError", "error of the hit energy in [GeV]."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("time", "time of the hit in [ns]."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("position", "position of the hit in world coordinates in [mm]."), Type: rmeta.Any, Size: 12, EName: "edm4hep::Vector3f"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("type", "type of hit. Mapping of integer types to names via collection parameters \"CalorimeterHitTypeNames\" and \"CalorimeterHitTypeValues\"."), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::ParticleIDData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("type", "userdefined type"), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("PDG", "PDG code of this id - ( 999999 ) if unknown."), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("algorithmType", "type of the algorithm/module that created this hypothesis"), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("likelihood", "likelihood of this hypothesis - in a user defined normalization."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("parameters_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("parameters_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::ClusterData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("type", "flagword that defines the type of cluster. Bits 16-31 are used internally."), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("energy", "energy of the cluster [GeV]"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("energyError", "error on the energy"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("position", "position of the cluster [mm]"), Type: rmeta.Any, Size: 12, EName: "edm4hep::Vector3f"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("positionError", "covariance matrix of the position (6 Parameters)"), Type: rmeta.OffsetL + rmeta.Float, Size: 24, ArrLen: 6, ArrDim: 1, MaxIdx: [5]int32{6, 0, 0, 0, 0}, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("iTheta", "intrinsic direction of cluster at position  Theta. Not to be confused with direction cluster is seen from IP."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("phi", "intrinsic direction of cluster at position - Phi. Not to be confused with direction cluster is seen from IP."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("directionError", "covariance matrix of the direction (3 Parameters) [mm^2]"), Type: rmeta.Any, Size: 12, EName: "edm4hep::Vector3f"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("clusters_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("clusters_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("hits_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("hits_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("particleIDs_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("particleIDs_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("shapeParameters_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("shapeParameters_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("subdetectorEnergies_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("subdetectorEnergies_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::TrackerHitData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("cellID", "ID of the sensor that created this hit"), Type: rmeta.ULong64, Size: 8, EName: "ULong64_t"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("type", "type of raw data hit, either one of edm4hep::RawTimeSeries, edm4hep::SIMTRACKERHIT - see collection parameters \"TrackerHitTypeNames\" and \"TrackerHitTypeValues\"."), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("quality", "quality bit flag of the hit."), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("time", "time of the hit [ns]."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("eDep", "energy deposited on the hit [GeV]."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("eDepError", "error measured on EDep [GeV]."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("edx", "dE/dx of the hit in [GeV]."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("position", "hit position in [mm]."), Type: rmeta.Any, Size: 24, EName: "edm4hep::Vector3d"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("covMatrix", "covariance of the position (x,y,z), stored as lower triangle matrix. i.e. cov(x,x) , cov(y,x) , cov(y,y) , cov(z,x) , cov(z,y) , cov(z,z)"), Type: rmeta.OffsetL + rmeta.Float, Size: 24, ArrLen: 6, ArrDim: 1, MaxIdx: [5]int32{6, 0, 0, 0, 0}, EName: "float"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::TrackData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("type", "flagword that defines the type of track.Bits 16-31 are used internally"), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("chi2", "Chi^2 of the track fit"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("ndf", "number of degrees of freedom of the track fit"), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("dEdx", "dEdx of the track."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("dEdxError", "error of dEdx."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("radiusOfInnermostHit", "radius of the innermost hit that has been used in the track fit"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("trackerHits_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("trackerHits_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("tracks_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("tracks_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("subDetectorHitNumbers_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("subDetectorHitNumbers_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("trackStates_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("trackStates_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("dxQuantities_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("dxQuantities_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::VertexData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("primary", "boolean flag, if vertex is the primary vertex of the event"), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("chi2", "chi-squared of the vertex fit"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("probability", "probability of the vertex fit"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("position", "[mm] position of the vertex."), Type: rmeta.Any, Size: 12, EName: "edm4hep::Vector3f"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("covMatrix", "covariance matrix of the position (stored as lower triangle matrix, i.e. cov(xx),cov(y,x),cov(z,x),cov(y,y),... )"), Type: rmeta.OffsetL + rmeta.Float, Size: 24, ArrLen: 6, ArrDim: 1, MaxIdx: [5]int32{6, 0, 0, 0, 0}, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("algorithmType", "type code for the algorithm that has been used to create the vertex - check/set the collection parameters AlgorithmName and AlgorithmType."), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("parameters_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("parameters_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::ReconstructedParticleData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("type", "type of reconstructed particle. Check/set collection parameters ReconstructedParticleTypeNames and ReconstructedParticleTypeValues."), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("energy", "[GeV] energy of the reconstructed particle. Four momentum state is not kept consistent internally."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("momentum", "[GeV] particle momentum. Four momentum state is not kept consistent internally."), Type: rmeta.Any, Size: 12, EName: "edm4hep::Vector3f"}.New()},
+		&rdict.StreamerObjectAny{StreamerElement: rdict.Element{Name: *rbase.NewNamed("referencePoint", "[mm] reference, i.e. where the particle has been measured"), Type: rmeta.Any, Size: 12, EName: "edm4hep::Vector3f"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("charge", "charge of the reconstructed particle."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("mass", "[GeV] mass of the reconstructed particle, set independently from four vector. Four momentum state is not kept consistent internally."), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("goodnessOfPID", "overall goodness of the PID on a scale of [0;1]"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("covMatrix", "cvariance matrix of the reconstructed particle 4vector (10 parameters). Stored as lower triangle matrix of the four momentum (px,py,pz,E), i.e. cov(px,px), cov(py,px), cov(py,py), ..."), Type: rmeta.OffsetL + rmeta.Float, Size: 40, ArrLen: 10, ArrDim: 1, MaxIdx: [5]int32{10, 0, 0, 0, 0}, EName: "float"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("clusters_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("clusters_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("tracks_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("tracks_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("particles_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("particles_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("particleIDs_begin", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("particleIDs_end", ""), Type: rmeta.UInt, Size: 4, EName: "unsigned int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::MCRecoParticleAssociationData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("weight", "weight of this association"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::MCRecoTrackParticleAssociationData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("weight", "weight of this association"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("edm4hep::MCRecoClusterParticleAssociationData", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("weight", "weight of this association"), Type: rmeta.Float, Size: 4, EName: "float"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("podio::ObjectID", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("index", ""), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("collectionID", ""), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+	}))
+	rdict.StreamerInfos.Add(rdict.NewCxxStreamerInfo("tuple<int,string,bool>", 1, 0, []rbytes.StreamerElement{
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("_0", ""), Type: rmeta.Int, Size: 4, EName: "int"}.New()},
+		&rdict.StreamerSTLstring{StreamerSTL: *rdict.NewCxxStreamerSTL(rdict.Element{Name: *rbase.NewNamed("_1", ""), Type: rmeta.STLstring, Size: 32, EName: "string"}.New(), rmeta.STLany, rmeta.STLstring)},
+		&rdict.StreamerBasicType{StreamerElement: rdict.Element{Name: *rbase.NewNamed("_2", ""), Type: rmeta.Bool, Size: 1, EName: "bool"}.New()},
+	}))
+}
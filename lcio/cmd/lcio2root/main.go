@@ -0,0 +1,170 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command lcio2root converts the content of a LCIO file to a ROOT file
+// following the EDM4hep data model, as laid out by PODIO.
+//
+// Usage: lcio2root [OPTIONS] input.slcio
+//
+// The following LCIO collections are converted:
+//  - MCParticle            -> edm4hep::MCParticle
+//  - SimTrackerHit         -> edm4hep::SimTrackerHit
+//  - SimCalorimeterHit     -> edm4hep::SimCalorimeterHit (+ edm4hep::CaloHitContribution)
+//  - Track                 -> edm4hep::Track
+//  - Cluster               -> edm4hep::Cluster (+ edm4hep::ParticleID)
+//  - ReconstructedParticle -> edm4hep::ReconstructedParticle (+ edm4hep::ParticleID)
+//  - LCRelation            -> edm4hep::MCReco{,Track,Cluster}ParticleAssociation
+//
+// Other collections are skipped.
+//
+// The output ROOT file follows the PODIO layout: an "events" tree, with one
+// set of branches per collection, and a "podio_metadata" tree holding the
+// table of collection IDs and the types of the collections.
+// Relations between objects are stored as (index, collection ID) pairs.
+//
+// The EDM4hep types and their PODIO I/O are generated by podio-gen from
+// the edm4hep.yaml subset of the EDM4hep data model.
+//
+// Example:
+//
+//  $> lcio2root -o out.root ./input.slcio
+//  $> root-ls -t ./out.root
+//  === [./out.root] ===
+//  version: 62200
+//    TTree  events   events   (entries=10)
+//      EventHeader    "EventHeader"    TBranchElement
+//      MCParticle     "MCParticle"     TBranchElement
+//      MCParticle#0   "MCParticle#0"   TBranchElement
+//      MCParticle#1   "MCParticle#1"   TBranchElement
+//    TTree  podio_metadata  (entries=1)
+//      CollectionIDs      "CollectionIDs"      TBranchElement
+//      CollectionTypeInfo "CollectionTypeInfo" TBranchElement
+//
+// Options:
+//   -n int
+//     	number of events to convert (default -1)
+//   -o string
+//     	path to output ROOT file (default "out.root")
+package main
+
+//go:generate podio-gen -p main -o edm4hep_gen.go -r "edm4hep::->" ./edm4hep.yaml
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+
+	"go-hep.org/x/hep/groot"
+	"go-hep.org/x/hep/groot/rtree"
+	"go-hep.org/x/hep/lcio"
+)
+
+func main() {
+	log.SetPrefix("lcio2root: ")
+	log.SetFlags(0)
+
+	oname := flag.String("o", "out.root", "path to output ROOT file")
+	nevts := flag.Int64("n", -1, "number of events to convert")
+
+	flag.Usage = func() {
+		fmt.Printf(`lcio2root converts the content of a LCIO file to a ROOT file
+following the EDM4hep data model, as laid out by PODIO.
+
+Usage: lcio2root [OPTIONS] input.slcio
+
+The following LCIO collections are converted:
+ - MCParticle            -> edm4hep::MCParticle
+ - SimTrackerHit         -> edm4hep::SimTrackerHit
+ - SimCalorimeterHit     -> edm4hep::SimCalorimeterHit (+ edm4hep::CaloHitContribution)
+ - Track                 -> edm4hep::Track
+ - Cluster               -> edm4hep::Cluster (+ edm4hep::ParticleID)
+ - ReconstructedParticle -> edm4hep::ReconstructedParticle (+ edm4hep::ParticleID)
+ - LCRelation            -> edm4hep::MCReco{,Track,Cluster}ParticleAssociation
+
+Other collections are skipped.
+
+Example:
+
+ $> lcio2root -o out.root ./input.slcio
+
+Options:
+`)
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		log.Fatalf("missing input LCIO file")
+	}
+
+	fname := flag.Arg(0)
+	err := process(*oname, fname, *nevts)
+	if err != nil {
+		log.Fatalf("could not convert %q: %+v", fname, err)
+	}
+}
+
+func process(oname, fname string, nevts int64) error {
+	src, err := lcio.Open(fname)
+	if err != nil {
+		return fmt.Errorf("could not open LCIO file %q: %w", fname, err)
+	}
+	defer src.Close()
+
+	dst, err := groot.Create(oname)
+	if err != nil {
+		return fmt.Errorf("could not create output ROOT file %q: %w", oname, err)
+	}
+	defer dst.Close()
+
+	var (
+		cnv *converter
+		out = NewWriter(dst, rtree.WithTitle("events"))
+	)
+	defer out.Close()
+
+	for i := int64(0); nevts < 0 || i < nevts; i++ {
+		if !src.Next() {
+			break
+		}
+		evt := src.Event()
+		if cnv == nil {
+			cnv = newConverter(&evt)
+		}
+
+		podio, err := cnv.convert(&evt)
+		if err != nil {
+			return fmt.Errorf("could not convert event #%d: %w", i, err)
+		}
+
+		err = out.Write(podio)
+		if err != nil {
+			return fmt.Errorf("could not write event #%d: %w", i, err)
+		}
+	}
+
+	err = src.Err()
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("could not read LCIO file %q: %w", fname, err)
+	}
+
+	if cnv == nil {
+		return fmt.Errorf("no event in LCIO file %q", fname)
+	}
+
+	err = out.Close()
+	if err != nil {
+		return fmt.Errorf("could not close PODIO writer: %w", err)
+	}
+
+	err = dst.Close()
+	if err != nil {
+		return fmt.Errorf("could not close output ROOT file %q: %w", oname, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,290 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go-hep.org/x/hep/groot"
+	"go-hep.org/x/hep/groot/riofs"
+	"go-hep.org/x/hep/groot/rtree"
+	"go-hep.org/x/hep/lcio"
+)
+
+func TestConvert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lcio2root-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		iname = filepath.Join(dir, "input.slcio")
+		oname = filepath.Join(dir, "out.root")
+	)
+
+	const nevts = 3
+	genLCIO(t, iname, nevts)
+
+	err = process(oname, iname, -1)
+	if err != nil {
+		t.Fatalf("could not convert LCIO file: %+v", err)
+	}
+
+	f, err := groot.Open(oname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	{
+		o, err := riofs.Dir(f).Get("podio_metadata")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var table CollectionIDTable
+		r, err := rtree.NewReader(o.(rtree.Tree), []rtree.ReadVar{{Name: "CollectionIDs", Value: &table}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+
+		err = r.Read(func(ctx rtree.RCtx) error { return nil })
+		if err != nil {
+			t.Fatalf("could not read metadata: %+v", err)
+		}
+
+		want := CollectionIDTable{
+			IDs: []int32{1, 2, 3, 4, 5, 6, 7, 8},
+			Names: []string{
+				"EventHeader", "MCParticles", "Tracks",
+				"Clusters", "ClustersParticleIDs",
+				"RecoParticles", "RecoParticlesParticleIDs",
+				"RecoMCTruthLink",
+			},
+		}
+		if !reflect.DeepEqual(table, want) {
+			t.Fatalf("invalid collection IDs:\ngot= %+v\nwant=%+v", table, want)
+		}
+	}
+
+	r, err := NewReader(f)
+	if err != nil {
+		t.Fatalf("could not create PODIO reader: %+v", err)
+	}
+
+	if got, want := r.Entries(), int64(nevts); got != want {
+		t.Fatalf("invalid number of events: got=%d, want=%d", got, want)
+	}
+
+	i := int32(0)
+	err = r.Read(func(evt *Event) error {
+		defer func() { i++ }()
+
+		if got, want := evt.Names(), []string{
+			"EventHeader", "MCParticles", "Tracks",
+			"Clusters", "ClustersParticleIDs",
+			"RecoParticles", "RecoParticlesParticleIDs",
+			"RecoMCTruthLink",
+		}; !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("evt=%d: invalid collection names:\ngot= %q\nwant=%q", i, got, want)
+		}
+
+		var (
+			hdrs   = evt.Get("EventHeader").(*EventHeaderCollection).Elems
+			mcs    = evt.Get("MCParticles").(*MCParticleCollection).Elems
+			trks   = evt.Get("Tracks").(*TrackCollection).Elems
+			clus   = evt.Get("Clusters").(*ClusterCollection).Elems
+			cpids  = evt.Get("ClustersParticleIDs").(*ParticleIDCollection).Elems
+			recs   = evt.Get("RecoParticles").(*ReconstructedParticleCollection).Elems
+			pids   = evt.Get("RecoParticlesParticleIDs").(*ParticleIDCollection).Elems
+			assocs = evt.Get("RecoMCTruthLink").(*MCRecoParticleAssociationCollection).Elems
+		)
+
+		if got, want := hdrs, []*EventHeader{{EventNumber: i, RunNumber: 42, TimeStamp: 1234, Weight: 1}}; !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("evt=%d: invalid event header:\ngot= %+v\nwant=%+v", i, got, want)
+		}
+
+		if got, want := len(mcs), 3; got != want {
+			return fmt.Errorf("evt=%d: invalid number of MC particles: got=%d, want=%d", i, got, want)
+		}
+		if got, want := mcs[2].PDG, int32(13); got != want {
+			return fmt.Errorf("evt=%d: invalid MC particle PDG: got=%d, want=%d", i, got, want)
+		}
+		if got, want := mcs[1].Momentum, (Vector3f{1, 2, float32(i)}); got != want {
+			return fmt.Errorf("evt=%d: invalid MC particle momentum: got=%v, want=%v", i, got, want)
+		}
+		if got := mcs[1].Parents; len(got) != 1 || got[0] != mcs[0] {
+			return fmt.Errorf("evt=%d: invalid MC parents: got=%v", i, got)
+		}
+		if got := mcs[0].Daughters; len(got) != 2 || got[0] != mcs[1] || got[1] != mcs[2] {
+			return fmt.Errorf("evt=%d: invalid MC daughters: got=%v", i, got)
+		}
+
+		if got, want := len(trks), 1; got != want {
+			return fmt.Errorf("evt=%d: invalid number of tracks: got=%d, want=%d", i, got, want)
+		}
+		if got, want := trks[0].TrackStates, []TrackState{
+			{Location: 1, D0: 1, Phi: 2, Omega: 3, Z0: 4, TanLambda: 5, ReferencePoint: Vector3f{1, 2, 3}},
+		}; !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("evt=%d: invalid track states:\ngot= %+v\nwant=%+v", i, got, want)
+		}
+		if got, want := trks[0].SubDetectorHitNumbers, []int32{1, 2, 3}; !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("evt=%d: invalid sub-detector hit numbers: got=%v, want=%v", i, got, want)
+		}
+
+		if got := clus[0].ParticleIDs; len(got) != 1 || got[0] != cpids[0] || got[0].PDG != 22 {
+			return fmt.Errorf("evt=%d: invalid cluster particle IDs: got=%v", i, got)
+		}
+
+		if got, want := len(recs), 2; got != want {
+			return fmt.Errorf("evt=%d: invalid number of reco particles: got=%d, want=%d", i, got, want)
+		}
+		if got := recs[0].Tracks; len(got) != 1 || got[0] != trks[0] {
+			return fmt.Errorf("evt=%d: invalid reco tracks: got=%v", i, got)
+		}
+		if got := recs[1].Clusters; len(got) != 1 || got[0] != clus[0] {
+			return fmt.Errorf("evt=%d: invalid reco clusters: got=%v", i, got)
+		}
+		if got := recs[0].ParticleIDs; len(got) != 2 || got[0] != pids[0] || got[1] != pids[1] {
+			return fmt.Errorf("evt=%d: invalid reco particle IDs: got=%v", i, got)
+		}
+		if recs[0].ParticleIDUsed != pids[1] || recs[1].ParticleIDUsed != nil {
+			return fmt.Errorf("evt=%d: invalid reco particle ID used: got=[%v, %v]", i, recs[0].ParticleIDUsed, recs[1].ParticleIDUsed)
+		}
+		if recs[0].StartVertex != nil || recs[1].StartVertex != nil {
+			return fmt.Errorf("evt=%d: invalid reco start vertices", i)
+		}
+		if got, want := pids[1].PDG, int32(13); got != want {
+			return fmt.Errorf("evt=%d: invalid particle ID PDG: got=%d, want=%d", i, got, want)
+		}
+		if got, want := pids[1].Parameters, []float32{1, 2}; !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("evt=%d: invalid particle ID parameters: got=%v, want=%v", i, got, want)
+		}
+
+		if got, want := len(assocs), 2; got != want {
+			return fmt.Errorf("evt=%d: invalid number of associations: got=%d, want=%d", i, got, want)
+		}
+		for j, want := range []struct {
+			w   float32
+			rec *ReconstructedParticle
+			sim *MCParticle
+		}{
+			{0.5, recs[0], mcs[2]},
+			{1, recs[1], mcs[1]},
+		} {
+			got := assocs[j]
+			if got.Weight != want.w || got.Rec != want.rec || got.Sim != want.sim {
+				return fmt.Errorf("evt=%d: invalid association #%d: got=%+v", i, j, got)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("could not read events: %+v", err)
+	}
+
+	if i != nevts {
+		t.Fatalf("invalid number of read events: got=%d, want=%d", i, nevts)
+	}
+}
+
+func genLCIO(t *testing.T, fname string, nevts int) {
+	t.Helper()
+
+	w, err := lcio.Create(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	for i := 0; i < nevts; i++ {
+		var (
+			mcs = lcio.McParticleContainer{
+				Particles: make([]lcio.McParticle, 3),
+			}
+			trks = lcio.TrackContainer{
+				Tracks: []lcio.Track{{
+					Type:       1,
+					States:     []lcio.TrackState{{Loc: 1, D0: 1, Phi: 2, Omega: 3, Z0: 4, TanL: 5, Ref: [3]float32{1, 2, 3}}},
+					Chi2:       2,
+					NdF:        3,
+					SubDetHits: []int32{1, 2, 3},
+				}},
+			}
+			clus = lcio.ClusterContainer{
+				Clusters: []lcio.Cluster{{
+					Energy: 10,
+					PIDs:   []lcio.ParticleID{{PDG: 22, Likelihood: 0.9}},
+				}},
+			}
+			recs = lcio.RecParticleContainer{
+				Parts: []lcio.RecParticle{
+					{
+						Energy: 5,
+						PIDs: []lcio.ParticleID{
+							{PDG: 11},
+							{PDG: 13, Params: []float32{1, 2}},
+						},
+					},
+					{Energy: 10},
+				},
+			}
+		)
+
+		mcs.Particles[0].PDG = 23
+		mcs.Particles[1].PDG = -13
+		mcs.Particles[2].PDG = 13
+		mcs.Particles[1].P = [3]float64{1, 2, float64(i)}
+		mcs.Particles[0].Children = []*lcio.McParticle{&mcs.Particles[1], &mcs.Particles[2]}
+		mcs.Particles[1].Parents = []*lcio.McParticle{&mcs.Particles[0]}
+		mcs.Particles[2].Parents = []*lcio.McParticle{&mcs.Particles[0]}
+
+		recs.Parts[0].Tracks = []*lcio.Track{&trks.Tracks[0]}
+		recs.Parts[0].PIDUsed = &recs.Parts[0].PIDs[1]
+		recs.Parts[1].Clusters = []*lcio.Cluster{&clus.Clusters[0]}
+
+		links := lcio.RelationContainer{
+			Flags: lcio.BitsRelWeighted,
+			Params: lcio.Params{
+				Strings: map[string][]string{
+					"FromType": {"ReconstructedParticle"},
+					"ToType":   {"MCParticle"},
+				},
+			},
+			Rels: []lcio.Relation{
+				{From: &recs.Parts[0], To: &mcs.Particles[2], Weight: 0.5},
+				{From: &recs.Parts[1], To: &mcs.Particles[1], Weight: 1},
+			},
+		}
+
+		evt := lcio.Event{
+			RunNumber:   42,
+			EventNumber: int32(i),
+			TimeStamp:   1234,
+			Detector:    "test",
+		}
+		evt.Add("MCParticles", &mcs)
+		evt.Add("Tracks", &trks)
+		evt.Add("Clusters", &clus)
+		evt.Add("RecoParticles", &recs)
+		evt.Add("RecoMCTruthLink", &links)
+
+		err = w.WriteEvent(&evt)
+		if err != nil {
+			t.Fatalf("could not write event %d: %+v", i, err)
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("could not close LCIO file: %+v", err)
+	}
+}
@@ -34,12 +34,20 @@ var Blocks = struct {
 	RunHeader    string
 	EventHeader  string
 }{
-	Index:        "LCIOndex",
+	Index:        "LCIOIndex",
 	RandomAccess: "LCIORandomAccess",
 	RunHeader:    "RunHeader",
 	EventHeader:  "EventHeader",
 }
 
+// randomAccessSize is the size in bytes of an (uncompressed) LCIORandomAccess
+// record, as stored on disk.
+// The last record of a LCIO file with random access records is a
+// LCIORandomAccess record that can be read back from the end of the file.
+const randomAccessSize = 136
+
+// RandomAccess describes the range of runs and events indexed by a LCIOIndex
+// record and where that record is located in the file.
 type RandomAccess struct {
 	RunMin         int32
 	EventMin       int32
@@ -66,7 +74,31 @@ type Index struct {
 }
 
 func (idx *Index) MarshalSio(w sio.Writer) error {
-	panic("not implemented")
+	enc := sio.NewEncoder(w)
+	enc.Encode(&idx.ControlWord)
+	enc.Encode(&idx.RunMin)
+	enc.Encode(&idx.BaseOffset)
+	enc.Encode(int32(len(idx.Offsets)))
+	for i := range idx.Offsets {
+		v := &idx.Offsets[i]
+		if idx.ControlWord&1 == 0 {
+			enc.Encode(&v.RunOffset)
+		}
+
+		enc.Encode(&v.EventNumber)
+		switch {
+		case idx.ControlWord&2 != 0:
+			enc.Encode(&v.Location)
+		default:
+			enc.Encode(int32(v.Location))
+		}
+		if idx.ControlWord&4 != 0 {
+			enc.Encode(&v.Ints)
+			enc.Encode(&v.Floats)
+			enc.Encode(&v.Strings)
+		}
+	}
+	return enc.Err()
 }
 
 func (idx *Index) UnmarshalSio(r sio.Reader) error {
@@ -85,14 +117,14 @@ func (idx *Index) UnmarshalSio(r sio.Reader) error {
 
 		dec.Decode(&v.EventNumber)
 		switch {
-		case idx.ControlWord&2 == 1:
+		case idx.ControlWord&2 != 0:
 			dec.Decode(&v.Location)
 		default:
 			var loc int32
 			dec.Decode(&loc)
 			v.Location = int64(loc)
 		}
-		if idx.ControlWord&4 == 1 {
+		if idx.ControlWord&4 != 0 {
 			dec.Decode(&v.Ints)
 			dec.Decode(&v.Floats)
 			dec.Decode(&v.Strings)
@@ -101,6 +133,19 @@ func (idx *Index) UnmarshalSio(r sio.Reader) error {
 	return dec.Err()
 }
 
+// runEvent identifies a run header (evt=-1) or an event record.
+type runEvent struct {
+	run int32
+	evt int32
+}
+
+func (re runEvent) less(o runEvent) bool {
+	if re.run != o.run {
+		return re.run < o.run
+	}
+	return re.evt < o.evt
+}
+
 type Offset struct {
 	RunOffset   int32 // run offset relative to Index.RunMin
 	EventNumber int32 // event number or -1 for run header records
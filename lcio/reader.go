@@ -6,6 +6,7 @@ package lcio
 
 import (
 	"fmt"
+	"io"
 
 	"go-hep.org/x/hep/sio"
 )
@@ -27,9 +28,13 @@ type Reader struct {
 	idx  Index
 	rnd  RandomAccess
 	rhdr RunHeader
+	rrun bool // whether a run header has been loaded into rhdr
 	ehdr EventHeader
 	evt  Event
 	err  error
+
+	toc   map[runEvent]int64 // locations of run headers and events
+	nevts int64              // number of events in file
 }
 
 func (r *Reader) Close() error {
@@ -116,6 +121,7 @@ func (r *Reader) Next() bool {
 			r.err = fmt.Errorf("lcio: expected record %q to unpack", rec.Name())
 			return false
 		}
+		r.rrun = true
 		return r.Next()
 
 	case Records.EventHeader:
@@ -183,3 +189,178 @@ func (r *Reader) Event() Event {
 func (r *Reader) Err() error {
 	return r.err
 }
+
+// Entries returns the number of events stored in the file.
+//
+// Entries uses the LCIOIndex and LCIORandomAccess records of the file, if
+// any, or builds the index by scanning the whole file otherwise.
+func (r *Reader) Entries() int64 {
+	err := r.loadIndex()
+	if err != nil {
+		r.err = err
+		return 0
+	}
+	return r.nevts
+}
+
+// SeekEvent loads the event with the provided run and event numbers, and the
+// run header of that run.
+// Subsequent calls to Next will read the events following that event.
+//
+// SeekEvent uses the LCIOIndex and LCIORandomAccess records of the file, if
+// any, or builds the index by scanning the whole file otherwise.
+func (r *Reader) SeekEvent(run, evt int32) error {
+	err := r.loadIndex()
+	if err != nil {
+		r.err = err
+		return err
+	}
+
+	pos, ok := r.toc[runEvent{run: run, evt: evt}]
+	if !ok {
+		return fmt.Errorf("lcio: could not find event (run=%d, evt=%d)", run, evt)
+	}
+
+	if rpos, ok := r.toc[runEvent{run: run, evt: -1}]; ok && (!r.rrun || r.rhdr.RunNumber != run) {
+		err = r.readRecordAt(rpos, Records.RunHeader)
+		if err != nil {
+			r.err = err
+			return err
+		}
+		r.rrun = true
+	}
+
+	_, err = r.f.Seek(pos, io.SeekStart)
+	if err != nil {
+		r.err = err
+		return err
+	}
+
+	r.err = nil
+	if !r.Next() {
+		return r.err
+	}
+	return nil
+}
+
+// loadIndex loads the locations of run headers and events from the file.
+func (r *Reader) loadIndex() error {
+	if r.toc != nil {
+		return nil
+	}
+
+	pos := r.f.CurPos()
+	defer r.f.Seek(pos, io.SeekStart)
+
+	r.toc = make(map[runEvent]int64)
+	r.nevts = 0
+
+	err := r.readIndex()
+	if err != nil {
+		r.toc = make(map[runEvent]int64)
+		r.nevts = 0
+		err = r.scanIndex()
+	}
+	if err != nil {
+		r.toc = nil
+		return fmt.Errorf("lcio: could not build events index: %w", err)
+	}
+	return nil
+}
+
+// readIndex reads the index of the file from the LCIORandomAccess record
+// located at the end of the file and the chain of LCIORandomAccess and
+// LCIOIndex records it points at.
+func (r *Reader) readIndex() error {
+	err := r.readRecordAt(-randomAccessSize, Records.RandomAccess)
+	if err != nil {
+		return err
+	}
+
+	for loc := r.rnd.NextLoc; loc > 0; loc = r.rnd.PrevLoc {
+		err = r.readRecordAt(loc, Records.RandomAccess)
+		if err != nil {
+			return err
+		}
+		err = r.readRecordAt(r.rnd.IndexLoc, Records.Index)
+		if err != nil {
+			return err
+		}
+		for _, v := range r.idx.Offsets {
+			r.addEntry(
+				runEvent{run: r.idx.RunMin + v.RunOffset, evt: v.EventNumber},
+				r.idx.BaseOffset+v.Location,
+			)
+		}
+	}
+	return nil
+}
+
+// scanIndex builds the index of the file by reading all its records.
+func (r *Reader) scanIndex() error {
+	var (
+		rhdr = r.rhdr
+		ehdr = r.ehdr
+		erec = r.f.Record(Records.Event)
+	)
+
+	// do not decode the content of events while scanning.
+	erec.Disconnect()
+	defer func() {
+		r.rhdr = rhdr
+		r.ehdr = ehdr
+		for _, name := range r.evt.names {
+			_ = erec.Connect(name, r.evt.colls[name])
+		}
+	}()
+
+	_, err := r.f.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	for {
+		pos := r.f.CurPos()
+		rec, err := r.f.ReadRecord()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		switch rec.Name() {
+		case Records.RunHeader:
+			r.addEntry(runEvent{run: r.rhdr.RunNumber, evt: -1}, pos)
+		case Records.EventHeader:
+			r.addEntry(runEvent{run: r.ehdr.RunNumber, evt: r.ehdr.EventNumber}, pos)
+		}
+	}
+}
+
+func (r *Reader) addEntry(re runEvent, pos int64) {
+	if _, dup := r.toc[re]; !dup && re.evt != -1 {
+		r.nevts++
+	}
+	r.toc[re] = pos
+}
+
+// readRecordAt reads the record located at pos and checks it is named name.
+// A negative pos is interpreted relative to the end of the file.
+func (r *Reader) readRecordAt(pos int64, name string) error {
+	whence := io.SeekStart
+	if pos < 0 {
+		whence = io.SeekEnd
+	}
+	_, err := r.f.Seek(pos, whence)
+	if err != nil {
+		return err
+	}
+	rec, err := r.f.ReadRecord()
+	if err != nil {
+		return err
+	}
+	if rec.Name() != name {
+		return fmt.Errorf("lcio: expected record %q at %d, got %q", name, pos, rec.Name())
+	}
+	return nil
+}
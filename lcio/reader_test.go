@@ -5,9 +5,13 @@
 package lcio_test
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -107,3 +111,190 @@ func TestOpen(t *testing.T) {
 		})
 	}
 }
+
+func TestSeekEvent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lcio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const (
+		nruns = 3
+		nevts = 5
+	)
+
+	fname := filepath.Join(dir, "seek.slcio")
+	w, err := lcio.Create(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	for irun := int32(0); irun < nruns; irun++ {
+		run := lcio.RunHeader{
+			RunNumber: 10 + irun,
+			Descr:     fmt.Sprintf("run-%d", irun),
+		}
+		err = w.WriteRunHeader(&run)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for ievt := int32(0); ievt < nevts; ievt++ {
+			evt := lcio.Event{
+				RunNumber:   run.RunNumber,
+				EventNumber: 100*irun + ievt,
+			}
+			evt.Add("Hits", &lcio.CalorimeterHitContainer{
+				Hits: []lcio.CalorimeterHit{{Energy: float32(evt.EventNumber)}},
+			})
+			err = w.WriteEvent(&evt)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := ioutil.ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate a file without random access records.
+	legacy := filepath.Join(dir, "legacy.slcio")
+	{
+		i := bytes.Index(raw, []byte("LCIOIndex"))
+		if i < 0 {
+			t.Fatalf("could not find LCIOIndex record")
+		}
+		// 24: size of the record header preceding the record name.
+		err = ioutil.WriteFile(legacy, raw[:i-24], 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, fname := range []string{fname, legacy} {
+		t.Run(filepath.Base(fname), func(t *testing.T) {
+			r, err := lcio.Open(fname)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			if got, want := r.Entries(), int64(nruns*nevts); got != want {
+				t.Fatalf("invalid number of entries: got=%d, want=%d", got, want)
+			}
+
+			for _, tc := range []struct {
+				run, evt int32
+			}{
+				{12, 203},
+				{10, 0},
+				{11, 104},
+				{11, 100},
+				{12, 200},
+			} {
+				err = r.SeekEvent(tc.run, tc.evt)
+				if err != nil {
+					t.Fatalf("could not seek to (run=%d, evt=%d): %+v", tc.run, tc.evt, err)
+				}
+				evt := r.Event()
+				if evt.RunNumber != tc.run || evt.EventNumber != tc.evt {
+					t.Fatalf("invalid event: got=(%d, %d), want=(%d, %d)",
+						evt.RunNumber, evt.EventNumber, tc.run, tc.evt,
+					)
+				}
+				if got, want := r.RunHeader().RunNumber, tc.run; got != want {
+					t.Fatalf("invalid run header: got=%d, want=%d", got, want)
+				}
+				hits := evt.Get("Hits").(*lcio.CalorimeterHitContainer)
+				if got, want := hits.Hits[0].Energy, float32(tc.evt); got != want {
+					t.Fatalf("invalid event content: got=%v, want=%v", got, want)
+				}
+			}
+
+			// sequential reading resumes after the sought event.
+			err = r.SeekEvent(11, 103)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var evts []int32
+			for r.Next() {
+				evts = append(evts, r.Event().EventNumber)
+			}
+			if err := r.Err(); err != io.EOF {
+				t.Fatalf("error: %+v", err)
+			}
+			if got, want := evts, []int32{104, 200, 201, 202, 203, 204}; !reflect.DeepEqual(got, want) {
+				t.Fatalf("invalid events: got=%v, want=%v", got, want)
+			}
+
+			err = r.SeekEvent(13, 0)
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+		})
+	}
+}
+
+func TestSeekEventRunZero(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lcio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fname := filepath.Join(dir, "seek-run0.slcio")
+	w, err := lcio.Create(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	for irun := int32(0); irun < 2; irun++ {
+		err = w.WriteRunHeader(&lcio.RunHeader{
+			RunNumber: irun,
+			Descr:     fmt.Sprintf("run-%d", irun),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for ievt := int32(0); ievt < 3; ievt++ {
+			err = w.WriteEvent(&lcio.Event{
+				RunNumber:   irun,
+				EventNumber: ievt,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := lcio.Open(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	for _, run := range []int32{0, 1, 0} {
+		err = r.SeekEvent(run, 2)
+		if err != nil {
+			t.Fatalf("could not seek to (run=%d, evt=2): %+v", run, err)
+		}
+		rhdr := r.RunHeader()
+		if got, want := rhdr.Descr, fmt.Sprintf("run-%d", run); got != want {
+			t.Fatalf("invalid run header: got=%q, want=%q", got, want)
+		}
+	}
+}
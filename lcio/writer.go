@@ -6,6 +6,8 @@ package lcio
 
 import (
 	"compress/flate"
+	"math"
+	"sort"
 
 	"go-hep.org/x/hep/sio"
 )
@@ -42,18 +44,32 @@ type Writer struct {
 		rhdr RunHeader
 		ehdr EventHeader
 	}
+	toc    []tocEntry // locations of run headers and events
 	closed bool
 	err    error
 }
 
+// tocEntry is the location of a run header or event header record.
+type tocEntry struct {
+	runEvent
+	loc int64
+}
+
 // Close closes the underlying output stream and makes it unavailable for
 // further I/O operations.
-// Close will synchronize and commit to disk any lingering data before closing
-// the output stream.
+// Close will write the LCIOIndex and LCIORandomAccess records describing the
+// run headers and events written so far, and synchronize and commit to disk
+// any lingering data before closing the output stream.
 func (w *Writer) Close() error {
 	if w.closed {
 		return w.err
 	}
+	if w.err == nil {
+		w.err = w.writeIndex()
+	}
+	if w.err != nil {
+		return w.err
+	}
 	w.err = w.f.Sync()
 	if w.err != nil {
 		return w.err
@@ -131,6 +147,12 @@ func (w *Writer) SetCompressionLevel(lvl int) {
 		}
 		rec.SetCompress(compress)
 	}
+
+	// random access records are always stored uncompressed, so they
+	// can be located from the end of the file.
+	if w.recs.rnd != nil {
+		w.recs.rnd.SetCompress(false)
+	}
 }
 
 func (w *Writer) WriteRunHeader(run *RunHeader) error {
@@ -141,6 +163,10 @@ func (w *Writer) WriteRunHeader(run *RunHeader) error {
 	w.data.rhdr.SubDetectors = make([]string, len(run.SubDetectors))
 	copy(w.data.rhdr.SubDetectors, run.SubDetectors)
 
+	w.toc = append(w.toc, tocEntry{
+		runEvent: runEvent{run: run.RunNumber, evt: -1},
+		loc:      w.f.CurPos(),
+	})
 	w.err = w.f.WriteRecord(w.recs.rhdr)
 	return w.err
 }
@@ -159,6 +185,10 @@ func (w *Writer) WriteEvent(evt *Event) error {
 	}
 	w.data.ehdr.Params = evt.Params
 
+	w.toc = append(w.toc, tocEntry{
+		runEvent: runEvent{run: evt.RunNumber, evt: evt.EventNumber},
+		loc:      w.f.CurPos(),
+	})
 	w.err = w.f.WriteRecord(w.recs.ehdr)
 	if w.err != nil {
 		return w.err
@@ -181,3 +211,79 @@ func (w *Writer) WriteEvent(evt *Event) error {
 
 	return w.err
 }
+
+// writeIndex writes the LCIOIndex record holding the locations of all the
+// run headers and events written so far, followed by its LCIORandomAccess
+// record and by the file LCIORandomAccess record that points at the latter.
+func (w *Writer) writeIndex() error {
+	if len(w.toc) == 0 {
+		return nil
+	}
+
+	toc := make([]tocEntry, len(w.toc))
+	copy(toc, w.toc)
+	less := func(i, j int) bool { return toc[i].less(toc[j].runEvent) }
+	inOrder := sort.SliceIsSorted(toc, less)
+	sort.SliceStable(toc, less)
+
+	var (
+		beg = toc[0]
+		end = toc[len(toc)-1]
+		idx = &w.data.idx
+		rnd = &w.data.rnd
+	)
+
+	*idx = Index{
+		RunMin:     beg.run,
+		BaseOffset: beg.loc,
+		Offsets:    make([]Offset, len(toc)),
+	}
+	*rnd = RandomAccess{
+		RunMin:     beg.run,
+		EventMin:   beg.evt,
+		RunMax:     end.run,
+		EventMax:   end.evt,
+		RecordSize: randomAccessSize,
+	}
+	if beg.run == end.run {
+		idx.ControlWord |= 1
+	}
+	if inOrder {
+		rnd.RecordsInOrder = 1
+	}
+
+	for i, e := range toc {
+		loc := e.loc - beg.loc
+		if loc > math.MaxInt32 || loc < math.MinInt32 {
+			idx.ControlWord |= 2
+		}
+		idx.Offsets[i] = Offset{
+			RunOffset:   e.run - beg.run,
+			EventNumber: e.evt,
+			Location:    loc,
+		}
+		switch e.evt {
+		case -1:
+			rnd.RunHeaders++
+		default:
+			rnd.Events++
+		}
+	}
+
+	rnd.IndexLoc = w.f.CurPos()
+	err := w.f.WriteRecord(w.recs.idx)
+	if err != nil {
+		return err
+	}
+
+	pos := w.f.CurPos()
+	err = w.f.WriteRecord(w.recs.rnd)
+	if err != nil {
+		return err
+	}
+
+	// the file record points at the last LCIORandomAccess record.
+	rnd.IndexLoc = 0
+	rnd.NextLoc = pos
+	return w.f.WriteRecord(w.recs.rnd)
+}
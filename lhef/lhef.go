@@ -3,6 +3,10 @@
 // license that can be found in the LICENSE file.
 
 // Package lhef implements the "Les Houches Event File" data format.
+//
+// The LHEF-3.0 extensions describing the generators (generator tag) and
+// the named weights (initrwgt, rwgt and weights tags) as well as the
+// event scales (scales tag) are also supported.
 package lhef // import "go-hep.org/x/hep/lhef"
 
 // XSecInfo contains information given in the xsecinfo tag.
@@ -54,6 +58,34 @@ type Weight struct {
 	Weights []float64 // the weights of this event.
 }
 
+// Generator represents the information in a generator tag.
+type Generator struct {
+	Name     string // the name of the generator.
+	Version  string // the version of the generator.
+	Contents string // additional information about the generator.
+}
+
+// WeightGroup represents the information in a weightgroup tag.
+type WeightGroup struct {
+	Name    string // the name of the group of weights.
+	Combine string // how the weights of the group should be combined (e.g. "envelope", "hessian".)
+}
+
+// WeightInfo represents the information in a weight tag of the initrwgt block.
+type WeightInfo struct {
+	ID       string // the identifier of the weight.
+	Group    int    // index of the weight group (in HEPRUP.WeightGroups) this weight belongs to, -1 if none.
+	Contents string // the description of the weight.
+}
+
+// Scales represents the information in a scales tag.
+type Scales struct {
+	MuF    float64            // the factorization scale in GeV.
+	MuR    float64            // the renormalization scale in GeV.
+	MuPS   float64            // the starting scale for the parton shower in GeV.
+	Others map[string]float64 // other named scales in GeV.
+}
+
 // Clus represents a clustering of two particle entries into one as
 // defined in a clustering tag.
 type Clus struct {
@@ -98,6 +130,21 @@ type HEPRUP struct {
 	MergeInfo  map[int64]MergeInfo // contents of the mergeinfo tags
 	GenName    string              // name of the generator which produced the file.
 	GenVersion string              // version of the generator which produced the file.
+
+	Generators   []Generator   // contents of the generator tags.
+	WeightGroups []WeightGroup // contents of the weightgroup tags of the initrwgt block.
+	WeightInfos  []WeightInfo  // contents of the weight tags of the initrwgt block.
+}
+
+// WeightIndex returns the index of the weight with the provided id in
+// the list of weights defined in the initrwgt block, or -1 if not found.
+func (run *HEPRUP) WeightIndex(id string) int {
+	for i, w := range run.WeightInfos {
+		if w.ID == id {
+			return i
+		}
+	}
+	return -1
 }
 
 // EventGroup represents a set of events which are to be considered together.
@@ -129,7 +176,19 @@ type HEPEUP struct {
 	Weights    []Weight     // weights associated with this event.
 	Clustering []Clus       // contents of the clustering tag.
 	PdfInfo    PDFInfo      // contents of the pdfinfo tag.
+	Scales     Scales       // contents of the scales tag.
 	SubEvents  EventGroup   // events included in the group if this is not a single event.
 }
 
+// Weight returns the value of the named weight with the provided id,
+// as given in the rwgt or weights tags of the event.
+func (evt *HEPEUP) Weight(id string) (float64, bool) {
+	for _, w := range evt.Weights {
+		if w.Name == id && len(w.Weights) > 0 {
+			return w.Weights[0], true
+		}
+	}
+	return 0, false
+}
+
 // EOF
@@ -0,0 +1,179 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lhef_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+
+	"go-hep.org/x/hep/lhef"
+)
+
+func TestDecodeLHEF3(t *testing.T) {
+	f, err := os.Open("testdata/lhef3.lhe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	dec, err := lhef.NewDecoder(f)
+	if err != nil {
+		t.Fatalf("could not create decoder: %+v", err)
+	}
+
+	if got, want := dec.Version, 3; got != want {
+		t.Fatalf("invalid version: got=%d, want=%d", got, want)
+	}
+
+	if got, want := dec.Run.Generators, []lhef.Generator{
+		{Name: "MadGraph5_aMC@NLO", Version: "2.6.7", Contents: "please cite 1405.0301"},
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid generators:\ngot= %#v\nwant=%#v", got, want)
+	}
+
+	if got, want := dec.Run.GenName, "MadGraph5_aMC@NLO"; got != want {
+		t.Fatalf("invalid generator name: got=%q, want=%q", got, want)
+	}
+
+	if got, want := dec.Run.WeightGroups, []lhef.WeightGroup{
+		{Name: "scale_variation", Combine: "envelope"},
+		{Name: "NNPDF31_nnlo_as_0118", Combine: "hessian"},
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid weight groups:\ngot= %#v\nwant=%#v", got, want)
+	}
+
+	if got, want := dec.Run.WeightInfos, []lhef.WeightInfo{
+		{ID: "1001", Group: 0, Contents: "dyn_scale_choice=sum pt  mur=1 muf=1"},
+		{ID: "1002", Group: 0, Contents: "dyn_scale_choice=sum pt  mur=2 muf=1"},
+		{ID: "1003", Group: 0, Contents: "dyn_scale_choice=sum pt  mur=0.5 muf=1"},
+		{ID: "1004", Group: 1, Contents: "PDF=303600 NNPDF31_nnlo_as_0118"},
+		{ID: "1005", Group: 1, Contents: "PDF=303601 NNPDF31_nnlo_as_0118 & member 1"},
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid weight infos:\ngot= %#v\nwant=%#v", got, want)
+	}
+
+	if got, want := dec.Run.WeightIndex("1003"), 2; got != want {
+		t.Fatalf("invalid weight index: got=%d, want=%d", got, want)
+	}
+	if got, want := dec.Run.WeightIndex("9999"), -1; got != want {
+		t.Fatalf("invalid weight index: got=%d, want=%d", got, want)
+	}
+
+	for _, tc := range []struct {
+		weights map[string]float64
+		scales  lhef.Scales
+	}{
+		{
+			weights: map[string]float64{
+				"1001": 503.4, "1002": 441.5, "1003": 586.5,
+				"1004": 503.4, "1005": 510.1,
+			},
+			scales: lhef.Scales{
+				MuF: 91.188, MuR: 91.188, MuPS: 173,
+				Others: map[string]float64{"pt_clust_1": 25},
+			},
+		},
+		{
+			weights: map[string]float64{
+				"1001": 503.4, "1002": 420.1, "1003": 601.2,
+				"1004": 503.4, "1005": 498.7,
+			},
+		},
+	} {
+		evt, err := dec.Decode()
+		if err != nil {
+			t.Fatalf("could not decode event: %+v", err)
+		}
+
+		if got, want := len(evt.Weights), len(tc.weights); got != want {
+			t.Fatalf("invalid number of weights: got=%d, want=%d", got, want)
+		}
+		for id, want := range tc.weights {
+			got, ok := evt.Weight(id)
+			if !ok {
+				t.Fatalf("could not find weight %q", id)
+			}
+			if got != want {
+				t.Fatalf("invalid weight %q: got=%v, want=%v", id, got, want)
+			}
+		}
+
+		if got, want := evt.Scales, tc.scales; !reflect.DeepEqual(got, want) {
+			t.Fatalf("invalid scales:\ngot= %#v\nwant=%#v", got, want)
+		}
+	}
+
+	_, err = dec.Decode()
+	if err != io.EOF {
+		t.Fatalf("expected EOF, got %+v", err)
+	}
+}
+
+func TestRWLHEF3(t *testing.T) {
+	decode := func(r io.Reader) (lhef.HEPRUP, []*lhef.HEPEUP) {
+		t.Helper()
+		dec, err := lhef.NewDecoder(r)
+		if err != nil {
+			t.Fatalf("could not create decoder: %+v", err)
+		}
+		var evts []*lhef.HEPEUP
+		for {
+			evt, err := dec.Decode()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("could not decode event: %+v", err)
+			}
+			evts = append(evts, evt)
+		}
+		return dec.Run, evts
+	}
+
+	f, err := os.Open("testdata/lhef3.lhe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	run, evts := decode(f)
+
+	out := new(bytes.Buffer)
+	enc, err := lhef.NewEncoder(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc.Run = run
+	for i, evt := range evts {
+		err = enc.Encode(evt)
+		if err != nil {
+			t.Fatalf("could not encode event %d: %+v", i, err)
+		}
+	}
+	err = enc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, gevts := decode(out)
+	if !reflect.DeepEqual(got, run) {
+		t.Fatalf("run round-trip failed:\ngot= %#v\nwant=%#v", got, run)
+	}
+
+	if got, want := len(gevts), len(evts); got != want {
+		t.Fatalf("invalid number of events: got=%d, want=%d", got, want)
+	}
+	for i := range gevts {
+		if got, want := gevts[i].Weights, evts[i].Weights; !reflect.DeepEqual(got, want) {
+			t.Fatalf("event %d: invalid weights:\ngot= %#v\nwant=%#v", i, got, want)
+		}
+		if got, want := gevts[i].Scales, evts[i].Scales; !reflect.DeepEqual(got, want) {
+			t.Fatalf("event %d: invalid scales:\ngot= %#v\nwant=%#v", i, got, want)
+		}
+	}
+}
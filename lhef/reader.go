@@ -9,12 +9,14 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 )
 
 // Decoder represents an LHEF parser reading a particular input stream.
 //
 // A Decoder is initialized with an input io.Reader from which to read a version 1.0
-// Les Houches Accord event file.
+// (or 3.0) Les Houches Accord event file.
 type Decoder struct {
 	r       io.Reader
 	dec     *xml.Decoder
@@ -59,6 +61,8 @@ func NewDecoder(r io.Reader) (*Decoder, error) {
 		d.Version = 1
 	case "2.0":
 		d.Version = 2
+	case "3.0":
+		d.Version = 3
 	}
 
 	var (
@@ -86,6 +90,12 @@ Loop:
 				// FIXME(sbinet): do something about header's content.
 				//		header = tok //FIXME
 				//panic(fmt.Errorf("header not implemented: %v", header))
+			case "initrwgt":
+				// LHEF-3 weights definitions, usually stored in the header.
+				err = d.decodeInitRwgt(tok)
+				if err != nil {
+					return nil, fmt.Errorf("lhef: could not decode 'initrwgt': %w", err)
+				}
 			}
 		}
 	}
@@ -141,18 +151,104 @@ Loop:
 		}
 	}
 
-	if d.Version >= 2 {
-		// do version-2 specific stuff
-	}
-
-	err = d.seek(init.End())
+	err = d.decodeInitTags(init)
 	if err != nil {
 		return nil, fmt.Errorf("lhef: could not find 'init' end tag: %w", err)
 	}
 
+	if len(d.Run.Generators) > 0 && d.Run.GenName == "" {
+		d.Run.GenName = d.Run.Generators[0].Name
+		d.Run.GenVersion = d.Run.Generators[0].Version
+	}
+
 	return d, nil
 }
 
+// decodeInitTags decodes the optional tags of the init block, up to its
+// end tag.
+func (d *Decoder) decodeInitTags(init xml.StartElement) error {
+	for {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok := tok.(type) {
+		case xml.EndElement:
+			if tok.Name == init.Name {
+				return nil
+			}
+		case xml.StartElement:
+			switch tok.Name.Local {
+			case "generator":
+				var node xmlNode
+				err = d.dec.DecodeElement(&node, &tok)
+				if err != nil {
+					return fmt.Errorf("could not decode 'generator': %w", err)
+				}
+				d.Run.Generators = append(d.Run.Generators, Generator{
+					Name:     node.attr("name"),
+					Version:  node.attr("version"),
+					Contents: strings.TrimSpace(node.Content),
+				})
+			case "initrwgt":
+				err = d.decodeInitRwgt(tok)
+				if err != nil {
+					return fmt.Errorf("could not decode 'initrwgt': %w", err)
+				}
+			default:
+				err = d.dec.Skip()
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// decodeInitRwgt decodes the definitions of the weights held in the
+// initrwgt block.
+func (d *Decoder) decodeInitRwgt(start xml.StartElement) error {
+	var root xmlNode
+	err := d.dec.DecodeElement(&root, &start)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range root.Nodes {
+		switch node.XMLName.Local {
+		case "weight":
+			d.Run.WeightInfos = append(d.Run.WeightInfos, WeightInfo{
+				ID:       node.attr("id"),
+				Group:    -1,
+				Contents: strings.TrimSpace(node.Content),
+			})
+		case "weightgroup":
+			name := node.attr("name")
+			if name == "" {
+				// older MadGraph versions use 'type' instead of 'name'.
+				name = node.attr("type")
+			}
+			grp := len(d.Run.WeightGroups)
+			d.Run.WeightGroups = append(d.Run.WeightGroups, WeightGroup{
+				Name:    name,
+				Combine: node.attr("combine"),
+			})
+			for _, w := range node.Nodes {
+				if w.XMLName.Local != "weight" {
+					continue
+				}
+				d.Run.WeightInfos = append(d.Run.WeightInfos, WeightInfo{
+					ID:       w.attr("id"),
+					Group:    grp,
+					Contents: strings.TrimSpace(w.Content),
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
 // advance to next event
 func (d *Decoder) next() error {
 LoopEvt:
@@ -178,21 +274,6 @@ LoopEvt:
 	return nil
 }
 
-func (d *Decoder) seek(tok xml.Token) error {
-seek:
-	for {
-		tk, err := d.dec.Token()
-		if err != nil {
-			return err
-		}
-		if tk == tok {
-			break seek
-		}
-	}
-
-	return nil
-}
-
 // Read an event from the file
 func (d *Decoder) Decode() (*HEPEUP, error) {
 
@@ -275,11 +356,115 @@ func (d *Decoder) Decode() (*HEPEUP, error) {
 
 	// do
 
-	// put "cursor" to next event...
-	err = d.seek(d.evt.End())
+	// decode optional tags and put "cursor" to next event...
+	err = d.decodeEventTags(evt)
 	if err != nil {
 		return nil, fmt.Errorf("lhef: could not find 'event' end tag: %w", err)
 	}
 
 	return evt, nil
 }
+
+// decodeEventTags decodes the optional tags of the current event, up to
+// its end tag.
+func (d *Decoder) decodeEventTags(evt *HEPEUP) error {
+	for {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok := tok.(type) {
+		case xml.EndElement:
+			if tok.Name == d.evt.Name {
+				return nil
+			}
+		case xml.StartElement:
+			var node xmlNode
+			switch tok.Name.Local {
+			case "rwgt", "weights", "scales":
+				err = d.dec.DecodeElement(&node, &tok)
+			default:
+				err = d.dec.Skip()
+			}
+			if err != nil {
+				return err
+			}
+
+			switch tok.Name.Local {
+			case "rwgt":
+				for _, w := range node.Nodes {
+					if w.XMLName.Local != "wgt" {
+						continue
+					}
+					vs, err := parseFloats(w.Content)
+					if err != nil {
+						return fmt.Errorf("could not decode weight %q: %w", w.attr("id"), err)
+					}
+					evt.Weights = append(evt.Weights, Weight{Name: w.attr("id"), Weights: vs})
+				}
+			case "weights":
+				vs, err := parseFloats(node.Content)
+				if err != nil {
+					return fmt.Errorf("could not decode weights: %w", err)
+				}
+				for i, v := range vs {
+					var id string
+					if i < len(d.Run.WeightInfos) {
+						id = d.Run.WeightInfos[i].ID
+					}
+					evt.Weights = append(evt.Weights, Weight{Name: id, Weights: []float64{v}})
+				}
+			case "scales":
+				for _, attr := range node.Attrs {
+					v, err := strconv.ParseFloat(strings.TrimSpace(attr.Value), 64)
+					if err != nil {
+						return fmt.Errorf("could not decode scale %q: %w", attr.Name.Local, err)
+					}
+					switch attr.Name.Local {
+					case "muf":
+						evt.Scales.MuF = v
+					case "mur":
+						evt.Scales.MuR = v
+					case "mups":
+						evt.Scales.MuPS = v
+					default:
+						if evt.Scales.Others == nil {
+							evt.Scales.Others = make(map[string]float64)
+						}
+						evt.Scales.Others[attr.Name.Local] = v
+					}
+				}
+			}
+		}
+	}
+}
+
+// xmlNode is a generic XML element.
+type xmlNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Content string     `xml:",chardata"`
+	Nodes   []xmlNode  `xml:",any"`
+}
+
+func (node *xmlNode) attr(name string) string {
+	for _, attr := range node.Attrs {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func parseFloats(s string) ([]float64, error) {
+	toks := strings.Fields(s)
+	vs := make([]float64, len(toks))
+	for i, tok := range toks {
+		v, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			return nil, err
+		}
+		vs[i] = v
+	}
+	return vs, nil
+}
@@ -5,8 +5,11 @@
 package lhef
 
 import (
+	"encoding/xml"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 	"sync"
 )
 
@@ -34,9 +37,12 @@ func (e *Encoder) init() error {
 	run := &e.Run
 
 	version := 1.0
-	if run.XSecInfo.Neve > 0 {
+	switch {
+	case run.XSecInfo.Neve > 0:
 		version = 2.0
 		panic("not implemented")
+	case len(run.Generators) > 0 || len(run.WeightInfos) > 0:
+		version = 3.0
 	}
 	_, err = fmt.Fprintf(
 		e.w,
@@ -90,6 +96,24 @@ func (e *Encoder) init() error {
 		}
 	}
 
+	for _, gen := range run.Generators {
+		_, err = fmt.Fprintf(
+			e.w,
+			"<generator name=\"%s\" version=\"%s\">%s</generator>\n",
+			escape(gen.Name), escape(gen.Version), escape(gen.Contents),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(run.WeightInfos) > 0 {
+		err = e.encodeInitRwgt()
+		if err != nil {
+			return err
+		}
+	}
+
 	if run.XSecInfo.Neve <= 0 {
 		_, err = fmt.Fprintf(
 			e.w,
@@ -102,6 +126,60 @@ func (e *Encoder) init() error {
 	return err
 }
 
+// encodeInitRwgt writes the definitions of the weights in an initrwgt block.
+func (e *Encoder) encodeInitRwgt() error {
+	_, err := fmt.Fprintf(e.w, "<initrwgt>\n")
+	if err != nil {
+		return err
+	}
+
+	grp := -1
+	for _, w := range e.Run.WeightInfos {
+		if w.Group != grp {
+			if grp >= 0 {
+				_, err = fmt.Fprintf(e.w, "</weightgroup>\n")
+				if err != nil {
+					return err
+				}
+			}
+			grp = w.Group
+			if grp >= 0 {
+				if grp >= len(e.Run.WeightGroups) {
+					return fmt.Errorf("lhef: invalid weight group %d for weight %q", grp, w.ID)
+				}
+				wg := e.Run.WeightGroups[grp]
+				_, err = fmt.Fprintf(e.w, "<weightgroup name=\"%s\"", escape(wg.Name))
+				if err != nil {
+					return err
+				}
+				if wg.Combine != "" {
+					_, err = fmt.Fprintf(e.w, " combine=\"%s\"", escape(wg.Combine))
+					if err != nil {
+						return err
+					}
+				}
+				_, err = fmt.Fprintf(e.w, ">\n")
+				if err != nil {
+					return err
+				}
+			}
+		}
+		_, err = fmt.Fprintf(e.w, "<weight id=\"%s\">%s</weight>\n", escape(w.ID), escape(w.Contents))
+		if err != nil {
+			return err
+		}
+	}
+	if grp >= 0 {
+		_, err = fmt.Fprintf(e.w, "</weightgroup>\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(e.w, "</initrwgt>\n")
+	return err
+}
+
 func (e *Encoder) Encode(evt *HEPEUP) error {
 	var err error
 	e.once.Do(func() { err = e.init() })
@@ -169,13 +247,86 @@ func (e *Encoder) Encode(evt *HEPEUP) error {
 
 	_, err = fmt.Fprintf(
 		e.w,
-		"#%s\n</event>\n",
+		"#%s\n",
 		"",
 	)
+	if err != nil {
+		return err
+	}
 
+	err = e.encodeScales(&evt.Scales)
+	if err != nil {
+		return err
+	}
+
+	if len(evt.Weights) > 0 {
+		_, err = fmt.Fprintf(e.w, "<rwgt>\n")
+		if err != nil {
+			return err
+		}
+		for _, w := range evt.Weights {
+			_, err = fmt.Fprintf(e.w, "<wgt id=\"%s\">", escape(w.Name))
+			if err != nil {
+				return err
+			}
+			for _, v := range w.Weights {
+				_, err = fmt.Fprintf(e.w, " %17.10E", v)
+				if err != nil {
+					return err
+				}
+			}
+			_, err = fmt.Fprintf(e.w, " </wgt>\n")
+			if err != nil {
+				return err
+			}
+		}
+		_, err = fmt.Fprintf(e.w, "</rwgt>\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(e.w, "</event>\n")
 	return err
 }
 
+// encodeScales writes the scales tag of an event, if any.
+func (e *Encoder) encodeScales(scales *Scales) error {
+	if scales.MuF == 0 && scales.MuR == 0 && scales.MuPS == 0 && len(scales.Others) == 0 {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(
+		e.w,
+		"<scales muf=\"%.10E\" mur=\"%.10E\" mups=\"%.10E\"",
+		scales.MuF, scales.MuR, scales.MuPS,
+	)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(scales.Others))
+	for name := range scales.Others {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, err = fmt.Fprintf(e.w, " %s=\"%.10E\"", name, scales.Others[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(e.w, "/>\n")
+	return err
+}
+
+func escape(s string) string {
+	o := new(strings.Builder)
+	_ = xml.EscapeText(o, []byte(s))
+	return o.String()
+}
+
 func (e *Encoder) Close() error {
 
 	_, err := fmt.Fprintf(
@@ -176,10 +176,16 @@ func addBlockEntry(line []byte, blk *Block) error {
 		tokens = append(tokens, tok)
 	}
 
-	// switch blk.Name {
-	// case "DCINFO":
-	// 	tokens
-	// }
+	switch blk.Name {
+	case "SPINFO", "DCINFO", "FCINFO",
+		"FMASS", "FOBS", "FOBSERR", "FOBSSM":
+		// messages (e.g. warnings and errors) may contain spaces.
+		// FLHA multi-column entries are indexed by the PDG ID of the
+		// particle and the remaining columns are kept as a single string.
+		if len(tokens) > 2 {
+			tokens = [][]byte{tokens[0], bytes.Join(tokens[1:], []byte(" "))}
+		}
+	}
 
 	ntokens := len(tokens) - 1
 	index := make([]int, ntokens)
@@ -193,14 +199,15 @@ func addBlockEntry(line []byte, blk *Block) error {
 
 	sval := string(tokens[len(index)])
 	switch blk.Name {
-	case "MODSEL":
+	case "MODSEL", "FMODSEL":
 		v, err := strconv.Atoi(sval)
 		if err != nil {
 			return err
 		}
 		val.v = reflect.ValueOf(v)
 
-	case "SPINFO", "DCINFO":
+	case "SPINFO", "DCINFO", "FCINFO",
+		"FMASS", "FOBS", "FOBSERR", "FOBSSM":
 		val.v = reflect.ValueOf(sval)
 
 	default:
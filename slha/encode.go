@@ -48,10 +48,13 @@ func blockFormat(name string, nargs int) string {
 	case "MINPAR", "EXTPAR", "QEXTPAR":
 		return " %5d   %16.8E   # %s\n"
 
-	case "SPINFO", "DCINFO":
+	case "SPINFO", "DCINFO", "FCINFO":
 		return " %5d   %-8s    # %s\n"
 
-	case "MODSEL":
+	case "FMASS", "FOBS", "FOBSERR", "FOBSSM":
+		return " %9d   %s   # %s\n"
+
+	case "MODSEL", "FMODSEL":
 		return " %5d %5d  # %s\n"
 
 	}
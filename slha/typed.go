@@ -0,0 +1,537 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slha
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// SPInfo holds the spectrum calculator informations of a SPINFO block.
+type SPInfo struct {
+	Name     string   // name of the spectrum calculator
+	Version  string   // version of the spectrum calculator
+	Warnings []string // warning messages
+	Errors   []string // error messages
+}
+
+// SPInfo returns the content of the SPINFO block.
+func (s *SLHA) SPInfo() (SPInfo, error) {
+	return s.info("SPINFO")
+}
+
+func (s *SLHA) info(name string) (SPInfo, error) {
+	var info SPInfo
+	blk, err := s.block(name)
+	if err != nil {
+		return info, err
+	}
+
+	for _, item := range blk.Data {
+		idx := item.Index.Index()
+		if len(idx) != 1 {
+			return info, fmt.Errorf("slha: invalid index %v in block %q", idx, blk.Name)
+		}
+		v := fmt.Sprintf("%v", item.Value.Interface())
+		switch idx[0] {
+		case 1:
+			info.Name = v
+		case 2:
+			info.Version = v
+		case 3:
+			info.Warnings = append(info.Warnings, v)
+		case 4:
+			info.Errors = append(info.Errors, v)
+		}
+	}
+
+	return info, nil
+}
+
+// Mass returns the mass of the particle with the provided PDG ID,
+// from the MASS block.
+func (s *SLHA) Mass(pdgid int) (float64, error) {
+	blk, err := s.block("MASS")
+	if err != nil {
+		return 0, err
+	}
+	return blk.float(pdgid)
+}
+
+// MinPar returns the content of the MINPAR block, the input parameters
+// of minimal models, indexed by their SLHA index.
+func (s *SLHA) MinPar() (map[int]float64, error) {
+	return s.params("MINPAR")
+}
+
+// ExtPar returns the content of the EXTPAR block, the optional input
+// parameters for non-minimal models, indexed by their SLHA index.
+func (s *SLHA) ExtPar() (map[int]float64, error) {
+	return s.params("EXTPAR")
+}
+
+func (s *SLHA) params(name string) (map[int]float64, error) {
+	blk, err := s.block(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ps := make(map[int]float64, len(blk.Data))
+	for _, item := range blk.Data {
+		idx := item.Index.Index()
+		if len(idx) != 1 {
+			return nil, fmt.Errorf("slha: invalid index %v in block %q", idx, name)
+		}
+		v, err := item.Value.float()
+		if err != nil {
+			return nil, fmt.Errorf("slha: invalid value at index %d in block %q: %w", idx[0], name, err)
+		}
+		ps[idx[0]] = v
+	}
+	return ps, nil
+}
+
+// Matrix reconstructs the rows x cols matrix held by the named block.
+// All the elements of the matrix must be present in the block.
+func (s *SLHA) Matrix(name string, rows, cols int) (*mat.Dense, error) {
+	blk, err := s.block(name)
+	if err != nil {
+		return nil, err
+	}
+
+	m := mat.NewDense(rows, cols, nil)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			v, err := blk.float(i+1, j+1)
+			if err != nil {
+				return nil, err
+			}
+			m.Set(i, j, v)
+		}
+	}
+	return m, nil
+}
+
+// NMix returns the 4x4 neutralino mixing matrix N, from the NMIX block.
+func (s *SLHA) NMix() (*mat.Dense, error) {
+	return s.Matrix("NMIX", 4, 4)
+}
+
+// UMix returns the 2x2 chargino mixing matrix U, from the UMIX block.
+func (s *SLHA) UMix() (*mat.Dense, error) {
+	return s.Matrix("UMIX", 2, 2)
+}
+
+// VMix returns the 2x2 chargino mixing matrix V, from the VMIX block.
+func (s *SLHA) VMix() (*mat.Dense, error) {
+	return s.Matrix("VMIX", 2, 2)
+}
+
+// StopMix returns the 2x2 stop mixing matrix, from the STOPMIX block.
+func (s *SLHA) StopMix() (*mat.Dense, error) {
+	return s.Matrix("STOPMIX", 2, 2)
+}
+
+// SbotMix returns the 2x2 sbottom mixing matrix, from the SBOTMIX block.
+func (s *SLHA) SbotMix() (*mat.Dense, error) {
+	return s.Matrix("SBOTMIX", 2, 2)
+}
+
+// StauMix returns the 2x2 stau mixing matrix, from the STAUMIX block.
+func (s *SLHA) StauMix() (*mat.Dense, error) {
+	return s.Matrix("STAUMIX", 2, 2)
+}
+
+// Wolfenstein holds the Wolfenstein parameterization of the CKM matrix,
+// as defined in the VCKMIN block of the SLHA2 and FLHA.
+type Wolfenstein struct {
+	Lambda float64
+	A      float64
+	RhoBar float64
+	EtaBar float64
+}
+
+// VCKMIN returns the Wolfenstein parameters of the VCKMIN block.
+func (s *SLHA) VCKMIN() (Wolfenstein, error) {
+	var w Wolfenstein
+	blk, err := s.block("VCKMIN")
+	if err != nil {
+		return w, err
+	}
+
+	for i, ptr := range []*float64{&w.Lambda, &w.A, &w.RhoBar, &w.EtaBar} {
+		*ptr, err = blk.float(i + 1)
+		if err != nil {
+			return w, err
+		}
+	}
+	return w, nil
+}
+
+// VCKM returns the real and imaginary parts of the 3x3 CKM matrix,
+// from the VCKM and IMVCKM blocks.
+// The imaginary part is zero if the IMVCKM block is not present.
+func (s *SLHA) VCKM() (re, im *mat.Dense, err error) {
+	re, err = s.Matrix("VCKM", 3, 3)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if s.Blocks.Get("IMVCKM") == nil {
+		return re, mat.NewDense(3, 3, nil), nil
+	}
+
+	im, err = s.Matrix("IMVCKM", 3, 3)
+	if err != nil {
+		return nil, nil, err
+	}
+	return re, im, nil
+}
+
+// UPMNS returns the 3x3 PMNS matrix, from the UPMNS block.
+func (s *SLHA) UPMNS() (*mat.Dense, error) {
+	return s.Matrix("UPMNS", 3, 3)
+}
+
+// FCInfo returns the flavour calculator informations of the FCINFO block.
+func (s *SLHA) FCInfo() (SPInfo, error) {
+	return s.info("FCINFO")
+}
+
+// FModSel returns the content of the FMODSEL block, the FLHA model
+// selection switches, indexed by their FLHA index.
+func (s *SLHA) FModSel() (map[int]int, error) {
+	blk, err := s.block("FMODSEL")
+	if err != nil {
+		return nil, err
+	}
+
+	sel := make(map[int]int, len(blk.Data))
+	for _, item := range blk.Data {
+		idx := item.Index.Index()
+		if len(idx) != 1 {
+			return nil, fmt.Errorf("slha: invalid index %v in block %q", idx, blk.Name)
+		}
+		if item.Value.Kind() != reflect.Int {
+			return nil, fmt.Errorf("slha: invalid value at index %d in block %q", idx[0], blk.Name)
+		}
+		sel[idx[0]] = int(item.Value.Int())
+	}
+	return sel, nil
+}
+
+// FMass is a mass entry of the FMASS block.
+type FMass struct {
+	Mass   float64 // mass in GeV
+	Scheme int     // FLHA identifier of the mass (renormalization) scheme
+	Scale  float64 // renormalization scale in GeV
+}
+
+// FMass returns the masses of the particle with the provided PDG ID,
+// from the FMASS block.
+// A particle may have many masses, each defined in a different scheme.
+func (s *SLHA) FMass(pdgid int) ([]FMass, error) {
+	blk, err := s.block("FMASS")
+	if err != nil {
+		return nil, err
+	}
+
+	var ms []FMass
+	for _, item := range blk.Data {
+		idx := item.Index.Index()
+		if len(idx) != 1 || idx[0] != pdgid {
+			continue
+		}
+		var (
+			m    FMass
+			cols = strings.Fields(fmt.Sprintf("%v", item.Value.Interface()))
+		)
+		if len(cols) != 3 {
+			return nil, fmt.Errorf("slha: invalid FMASS entry for particle %d: %q", pdgid, cols)
+		}
+		m.Mass, err = strconv.ParseFloat(cols[0], 64)
+		if err == nil {
+			m.Scheme, err = strconv.Atoi(cols[1])
+		}
+		if err == nil {
+			m.Scale, err = strconv.ParseFloat(cols[2], 64)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("slha: invalid FMASS entry for particle %d: %w", pdgid, err)
+		}
+		ms = append(ms, m)
+	}
+	if len(ms) == 0 {
+		return nil, fmt.Errorf("slha: no index (%d) in block %q", pdgid, blk.Name)
+	}
+	return ms, nil
+}
+
+// FLife returns the lifetime (in seconds) of the particle with the
+// provided PDG ID, from the FLIFE block.
+func (s *SLHA) FLife(pdgid int) (float64, error) {
+	blk, err := s.block("FLIFE")
+	if err != nil {
+		return 0, err
+	}
+	return blk.float(pdgid)
+}
+
+// FConst returns the n-th hadronic constant (e.g. decay constant or bag
+// parameter) of the particle with the provided PDG ID, from the FCONST block.
+func (s *SLHA) FConst(pdgid, n int) (float64, error) {
+	blk, err := s.block("FCONST")
+	if err != nil {
+		return 0, err
+	}
+	return blk.float(pdgid, n)
+}
+
+// FObs is a flavour observable of the FOBS block.
+type FObs struct {
+	Parent  int     // PDG ID of the parent particle
+	Type    int     // FLHA type of the observable (e.g. 1 for a branching ratio)
+	Value   float64 // value of the observable
+	Q       float64 // momentum transfer or scale of the observable, if applicable
+	IDs     []int   // PDG IDs of the daughter particles
+	Comment string  // description of the observable
+}
+
+// FObs returns the content of the FOBS block.
+func (s *SLHA) FObs() ([]FObs, error) {
+	blk, err := s.block("FOBS")
+	if err != nil {
+		return nil, err
+	}
+
+	obs := make([]FObs, 0, len(blk.Data))
+	for _, item := range blk.Data {
+		idx := item.Index.Index()
+		if len(idx) != 1 {
+			return nil, fmt.Errorf("slha: invalid index %v in block %q", idx, blk.Name)
+		}
+		o, err := newFObs(idx[0], fmt.Sprintf("%v", item.Value.Interface()))
+		if err != nil {
+			return nil, fmt.Errorf("slha: invalid FOBS entry for particle %d: %w", idx[0], err)
+		}
+		o.Comment = item.Value.Comment()
+		obs = append(obs, o)
+	}
+	return obs, nil
+}
+
+// newFObs decodes the "type value q NDA ID1 ... IDn" columns of an observable.
+func newFObs(parent int, line string) (FObs, error) {
+	var (
+		err  error
+		nda  int
+		o    = FObs{Parent: parent}
+		cols = strings.Fields(line)
+	)
+	if len(cols) < 4 {
+		return o, fmt.Errorf("missing columns in %q", line)
+	}
+	o.Type, err = strconv.Atoi(cols[0])
+	if err != nil {
+		return o, err
+	}
+	o.Value, err = strconv.ParseFloat(cols[1], 64)
+	if err != nil {
+		return o, err
+	}
+	o.Q, err = strconv.ParseFloat(cols[2], 64)
+	if err != nil {
+		return o, err
+	}
+	nda, err = strconv.Atoi(cols[3])
+	if err != nil {
+		return o, err
+	}
+	if len(cols) != 4+nda {
+		return o, fmt.Errorf("invalid number of daughters in %q (NDA=%d)", line, nda)
+	}
+	o.IDs = make([]int, nda)
+	for i := range o.IDs {
+		o.IDs[i], err = strconv.Atoi(cols[4+i])
+		if err != nil {
+			return o, err
+		}
+	}
+	return o, nil
+}
+
+// CheckUnitarity checks that the complex square matrix re+i*im is unitary,
+// ie: that all the elements of M^† M - 1 are smaller than tol in magnitude.
+// A nil im matrix is interpreted as a real matrix.
+func CheckUnitarity(re, im mat.Matrix, tol float64) error {
+	r, c := re.Dims()
+	if r != c {
+		return fmt.Errorf("slha: matrix is not square (%dx%d)", r, c)
+	}
+	if im == nil {
+		im = mat.NewDense(r, c, nil)
+	}
+	if ri, ci := im.Dims(); ri != r || ci != c {
+		return fmt.Errorf("slha: dimensions mismatch between real (%dx%d) and imaginary (%dx%d) parts", r, c, ri, ci)
+	}
+
+	// M^† M = (Re^T Re + Im^T Im) + i (Re^T Im - Im^T Re)
+	var (
+		mre, mim mat.Dense
+		tmp      mat.Dense
+	)
+	mre.Mul(re.T(), re)
+	tmp.Mul(im.T(), im)
+	mre.Add(&mre, &tmp)
+
+	mim.Mul(re.T(), im)
+	tmp.Mul(im.T(), re)
+	mim.Sub(&mim, &tmp)
+
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			want := 0.0
+			if i == j {
+				want = 1
+			}
+			if d := math.Hypot(mre.At(i, j)-want, mim.At(i, j)); d > tol {
+				return fmt.Errorf(
+					"slha: matrix is not unitary: |(M^† M - 1)[%d,%d]| = %g > %g",
+					i+1, j+1, d, tol,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the consistency of the SLHA data:
+//  - the required MODSEL and SMINPUTS blocks are present,
+//  - the mixing matrices (NMIX, UMIX, VMIX, STOPMIX, SBOTMIX, STAUMIX,
+//    VCKM/IMVCKM and UPMNS) that are present are complete and unitary,
+//  - the branching ratios of all decay tables sum up to 1.
+// As SLHA decay tables only hold branching ratios, the partial widths are
+// derived from the total width and the decay check is equivalent to
+// comparing the total width with the sum of the partial widths.
+// tol is the tolerance used for the unitarity and branching ratios checks.
+func (s *SLHA) Validate(tol float64) error {
+	var missing []string
+	for _, name := range []string{"MODSEL", "SMINPUTS"} {
+		if s.Blocks.Get(name) == nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("slha: missing required block(s) %s", strings.Join(missing, ", "))
+	}
+
+	for _, mix := range []struct {
+		name string
+		n    int
+	}{
+		{"NMIX", 4},
+		{"UMIX", 2},
+		{"VMIX", 2},
+		{"STOPMIX", 2},
+		{"SBOTMIX", 2},
+		{"STAUMIX", 2},
+		{"UPMNS", 3},
+	} {
+		if s.Blocks.Get(mix.name) == nil {
+			continue
+		}
+		m, err := s.Matrix(mix.name, mix.n, mix.n)
+		if err != nil {
+			return err
+		}
+		err = CheckUnitarity(m, nil, tol)
+		if err != nil {
+			return fmt.Errorf("slha: invalid block %q: %w", mix.name, err)
+		}
+	}
+
+	if s.Blocks.Get("VCKM") != nil {
+		re, im, err := s.VCKM()
+		if err != nil {
+			return err
+		}
+		err = CheckUnitarity(re, im, tol)
+		if err != nil {
+			return fmt.Errorf("slha: invalid block %q: %w", "VCKM", err)
+		}
+	}
+
+	for i := range s.Particles {
+		p := &s.Particles[i]
+		if len(p.Decays) == 0 {
+			continue
+		}
+		if sum := p.BRSum(); math.Abs(sum-1) > tol {
+			return fmt.Errorf(
+				"slha: branching ratios of particle %d sum up to %g",
+				p.PdgID, sum,
+			)
+		}
+	}
+
+	return nil
+}
+
+// BRSum returns the sum of the branching ratios of all the decays of the particle.
+func (p *Particle) BRSum() float64 {
+	sum := 0.0
+	for _, d := range p.Decays {
+		sum += d.Br
+	}
+	return sum
+}
+
+// PartialWidths returns the partial width of each decay channel of the particle.
+func (p *Particle) PartialWidths() []float64 {
+	ws := make([]float64, len(p.Decays))
+	for i, d := range p.Decays {
+		ws[i] = d.Br * p.Width
+	}
+	return ws
+}
+
+func (s *SLHA) block(name string) (*Block, error) {
+	blk := s.Blocks.Get(name)
+	if blk == nil {
+		return nil, fmt.Errorf("slha: no block %q", name)
+	}
+	return blk, nil
+}
+
+func (b *Block) float(args ...int) (float64, error) {
+	v, err := b.Get(args...)
+	if err != nil {
+		return 0, err
+	}
+	f, err := v.float()
+	if err != nil {
+		return 0, fmt.Errorf(
+			"slha: invalid value at index (%s) in block %q: %w",
+			strings.Join(strindex(args...), ", "), b.Name, err,
+		)
+	}
+	return f, nil
+}
+
+func (v *Value) float() (float64, error) {
+	switch v.Kind() {
+	case reflect.Float64:
+		return v.Float(), nil
+	case reflect.Int64:
+		return float64(v.Int()), nil
+	}
+	return 0, fmt.Errorf("value %v is not a number", v.Interface())
+}
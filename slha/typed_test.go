@@ -0,0 +1,410 @@
+// Copyright ©2020 The go-hep Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slha_test
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-hep.org/x/hep/slha"
+	"gonum.org/v1/gonum/mat"
+)
+
+func decodeFile(t *testing.T, fname string) *slha.SLHA {
+	t.Helper()
+
+	f, err := os.Open(fname)
+	if err != nil {
+		t.Fatalf("could not open file %q: %+v", fname, err)
+	}
+	defer f.Close()
+
+	data, err := slha.Decode(f)
+	if err != nil {
+		t.Fatalf("could not decode file %q: %+v", fname, err)
+	}
+	return data
+}
+
+func TestTypedBlocks(t *testing.T) {
+	data := decodeFile(t, "testdata/sps1a.spc")
+
+	info, err := data.SPInfo()
+	if err != nil {
+		t.Fatalf("could not read SPINFO: %+v", err)
+	}
+	if got, want := info, (slha.SPInfo{Name: "SOFTSUSY", Version: "2.0.5"}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid SPINFO:\ngot= %#v\nwant=%#v", got, want)
+	}
+
+	mass, err := data.Mass(1000022)
+	if err != nil {
+		t.Fatalf("could not read mass: %+v", err)
+	}
+	if got, want := mass, 96.6880686; got != want {
+		t.Fatalf("invalid mass: got=%v, want=%v", got, want)
+	}
+
+	_, err = data.Mass(42)
+	if err == nil {
+		t.Fatalf("expected an error for a missing mass")
+	}
+
+	minpar, err := data.MinPar()
+	if err != nil {
+		t.Fatalf("could not read MINPAR: %+v", err)
+	}
+	if got, want := minpar, map[int]float64{1: 100, 2: 250, 3: 10, 4: 1, 5: -100}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid MINPAR:\ngot= %v\nwant=%v", got, want)
+	}
+
+	_, err = data.ExtPar()
+	if err == nil {
+		t.Fatalf("expected an error for a missing EXTPAR block")
+	}
+
+	nmix, err := data.NMix()
+	if err != nil {
+		t.Fatalf("could not read NMIX: %+v", err)
+	}
+	if r, c := nmix.Dims(); r != 4 || c != 4 {
+		t.Fatalf("invalid NMIX dims: (%d,%d)", r, c)
+	}
+	if got, want := nmix.At(0, 1), -5.31103553e-02; got != want {
+		t.Fatalf("invalid NMIX[1,2]: got=%v, want=%v", got, want)
+	}
+	if got, want := nmix.At(3, 2), 6.49225960e-01; got != want {
+		t.Fatalf("invalid NMIX[4,3]: got=%v, want=%v", got, want)
+	}
+
+	for _, tc := range []struct {
+		name string
+		fct  func() (*mat.Dense, error)
+		want []float64
+	}{
+		{"UMIX", data.UMix, []float64{9.16834859e-01, -3.99266629e-01, 3.99266629e-01, 9.16834859e-01}},
+		{"VMIX", data.VMix, []float64{9.72557835e-01, -2.32661249e-01, 2.32661249e-01, 9.72557835e-01}},
+		{"STOPMIX", data.StopMix, []float64{5.53644960e-01, 8.32752820e-01, 8.32752820e-01, -5.53644960e-01}},
+		{"SBOTMIX", data.SbotMix, []float64{9.38737896e-01, 3.44631925e-01, -3.44631925e-01, 9.38737896e-01}},
+		{"STAUMIX", data.StauMix, []float64{2.82487190e-01, 9.59271071e-01, 9.59271071e-01, -2.82487190e-01}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := tc.fct()
+			if err != nil {
+				t.Fatalf("could not read %s: %+v", tc.name, err)
+			}
+			if got, want := m.RawMatrix().Data, tc.want; !reflect.DeepEqual(got, want) {
+				t.Fatalf("invalid matrix:\ngot= %v\nwant=%v", got, want)
+			}
+			err = slha.CheckUnitarity(m, nil, 1e-6)
+			if err != nil {
+				t.Fatalf("matrix is not unitary: %+v", err)
+			}
+		})
+	}
+
+	err = slha.CheckUnitarity(nmix, nil, 1e-6)
+	if err != nil {
+		t.Fatalf("NMIX is not unitary: %+v", err)
+	}
+
+	err = data.Validate(1e-6)
+	if err != nil {
+		t.Fatalf("could not validate SLHA data: %+v", err)
+	}
+
+	top := data.Particles.Get(6)
+	if got, want := top.BRSum(), 1.0; got != want {
+		t.Fatalf("invalid top BR sum: got=%v, want=%v", got, want)
+	}
+	pws := top.PartialWidths()
+	if got, want := len(pws), len(top.Decays); got != want {
+		t.Fatalf("invalid number of partial widths: got=%d, want=%d", got, want)
+	}
+	if got, want := pws[0], top.Decays[0].Br*top.Width; got != want {
+		t.Fatalf("invalid partial width: got=%v, want=%v", got, want)
+	}
+}
+
+func TestTypedSLHA2(t *testing.T) {
+	data := decodeFile(t, "testdata/slha2.txt")
+
+	extpar, err := data.ExtPar()
+	if err != nil {
+		t.Fatalf("could not read EXTPAR: %+v", err)
+	}
+	if got, want := len(extpar), 23; got != want {
+		t.Fatalf("invalid EXTPAR size: got=%d, want=%d", got, want)
+	}
+	if got, want := extpar[23], 3.95810334e+02; got != want {
+		t.Fatalf("invalid EXTPAR[23]: got=%v, want=%v", got, want)
+	}
+
+	stop, err := data.StopMix()
+	if err != nil {
+		t.Fatalf("could not read STOPMIX: %+v", err)
+	}
+	err = slha.CheckUnitarity(stop, nil, 1e-6)
+	if err != nil {
+		t.Fatalf("STOPMIX is not unitary: %+v", err)
+	}
+
+	err = data.Validate(1e-6)
+	if err == nil {
+		t.Fatalf("expected a validation error")
+	}
+	if got, want := err.Error(), "slha: branching ratios of particle 1000022 sum up to 0.999729105961"; !strings.HasPrefix(got, want) {
+		t.Fatalf("invalid error:\ngot= %v\nwant=%v", got, want)
+	}
+
+	err = data.Validate(1e-3)
+	if err != nil {
+		t.Fatalf("could not validate SLHA data: %+v", err)
+	}
+}
+
+func TestTypedFLHA(t *testing.T) {
+	const src = `Block SPINFO  # Program information
+     1   MyCode          # spectrum calculator
+     2   1.0             # version number
+     3   low tan(beta) may be unreliable  # warning
+     4   no convergence  # error
+Block MODSEL  # Model selection
+     1     1  # sugra
+Block SMINPUTS  # SM parameters
+     4     9.11876000E+01  # m_Z(pole)
+Block VCKMIN  # Wolfenstein parameters
+     1     2.2650000E-01  # lambda
+     2     7.9000000E-01  # A
+     3     1.4100000E-01  # rhobar
+     4     3.5700000E-01  # etabar
+Block VCKM  # Re(V_CKM)
+  1  1     0.0  # Re(V_11)
+  1  2     1.0  # Re(V_12)
+  1  3     0.0  # Re(V_13)
+  2  1     0.0  # Re(V_21)
+  2  2     0.0  # Re(V_22)
+  2  3     0.0  # Re(V_23)
+  3  1     0.0  # Re(V_31)
+  3  2     0.0  # Re(V_32)
+  3  3     1.0  # Re(V_33)
+Block IMVCKM  # Im(V_CKM)
+  2  1     1.0  # Im(V_21)
+Block FCINFO  # Program information
+     1   MyFlavourCode   # flavour calculator
+     2   2.1             # version number
+     3   B -> K* mu mu: large uncertainties  # warning
+Block FMODSEL  # Model selection
+     1     0  # SM
+     5     0  # no CP violation
+Block FMASS  # Mass spectrum in physical units
+         5     4.2          1     4.2     # b quark mass (MSbar)
+         5     4.68         3     0       # b quark mass (1S)
+       531     5.36677      0     0       # Bs mass (pole)
+Block FLIFE  # Lifetimes in seconds
+       511     1.525E-12  # B0 lifetime
+       531     1.472E-12  # Bs lifetime
+Block FCONST  # Hadronic constants
+       531     1     0.23  # f_Bs [GeV]
+       531     11    1.30  # B_1 for Bs
+Block FOBS  # Flavour observables
+         5     1     2.83E-04     0     2     3    22  # BR(b -> s gamma)
+       531     1     3.0E-09      0     2   -13    13  # BR(Bs -> mu+ mu-)
+       521     4     8.9E-02      0     2   313    22  # Delta0(B -> K* gamma)
+`
+
+	data, err := slha.Decode(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("could not decode SLHA data: %+v", err)
+	}
+
+	info, err := data.SPInfo()
+	if err != nil {
+		t.Fatalf("could not read SPINFO: %+v", err)
+	}
+	want := slha.SPInfo{
+		Name:     "MyCode",
+		Version:  "1.0",
+		Warnings: []string{"low tan(beta) may be unreliable"},
+		Errors:   []string{"no convergence"},
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Fatalf("invalid SPINFO:\ngot= %#v\nwant=%#v", info, want)
+	}
+
+	w, err := data.VCKMIN()
+	if err != nil {
+		t.Fatalf("could not read VCKMIN: %+v", err)
+	}
+	if got, want := w, (slha.Wolfenstein{Lambda: 0.2265, A: 0.79, RhoBar: 0.141, EtaBar: 0.357}); got != want {
+		t.Fatalf("invalid VCKMIN:\ngot= %#v\nwant=%#v", got, want)
+	}
+
+	_, _, err = data.VCKM()
+	if err == nil {
+		t.Fatalf("expected an error for an incomplete IMVCKM block")
+	}
+
+	blk := data.Blocks.Get("IMVCKM")
+	for i := 1; i <= 3; i++ {
+		for j := 1; j <= 3; j++ {
+			if i == 2 && j == 1 {
+				continue
+			}
+			err = blk.Set(0.0, i, j)
+			if err != nil {
+				t.Fatalf("could not set IMVCKM[%d,%d]: %+v", i, j, err)
+			}
+		}
+	}
+
+	re, im, err := data.VCKM()
+	if err != nil {
+		t.Fatalf("could not read VCKM: %+v", err)
+	}
+	err = slha.CheckUnitarity(re, im, 1e-12)
+	if err != nil {
+		t.Fatalf("VCKM is not unitary: %+v", err)
+	}
+
+	err = slha.CheckUnitarity(re, nil, 1e-12)
+	if err == nil {
+		t.Fatalf("expected Re(VCKM) not to be unitary")
+	}
+	if got, want := err.Error(), "slha: matrix is not unitary: |(M^† M - 1)[1,1]| = 1 > 1e-12"; got != want {
+		t.Fatalf("invalid error:\ngot= %v\nwant=%v", got, want)
+	}
+
+	err = data.Validate(1e-12)
+	if err != nil {
+		t.Fatalf("could not validate SLHA data: %+v", err)
+	}
+
+	err = blk.Set(0.5, 2, 1)
+	if err != nil {
+		t.Fatalf("could not set IMVCKM[2,1]: %+v", err)
+	}
+	err = data.Validate(1e-12)
+	if err == nil {
+		t.Fatalf("expected a validation error")
+	}
+	if got, want := err.Error(), `slha: invalid block "VCKM": slha: matrix is not unitary`; !strings.HasPrefix(got, want) {
+		t.Fatalf("invalid error:\ngot= %v\nwant=%v", got, want)
+	}
+
+	testFLHA(t, data)
+
+	// check the FLHA blocks survive an encode/decode round-trip.
+	buf := new(bytes.Buffer)
+	err = slha.Encode(buf, data)
+	if err != nil {
+		t.Fatalf("could not encode FLHA data: %+v", err)
+	}
+	data, err = slha.Decode(buf)
+	if err != nil {
+		t.Fatalf("could not decode FLHA data: %+v", err)
+	}
+	testFLHA(t, data)
+
+	data = decodeFile(t, "testdata/ex1-snowmass-point-1a.slha")
+	data.Blocks = data.Blocks[2:]
+	err = data.Validate(1e-6)
+	if err == nil {
+		t.Fatalf("expected a validation error")
+	}
+	if got, want := err.Error(), "slha: missing required block(s) MODSEL, SMINPUTS"; got != want {
+		t.Fatalf("invalid error:\ngot= %v\nwant=%v", got, want)
+	}
+
+	_, err = data.SPInfo()
+	if err == nil {
+		t.Fatalf("expected an error for a missing SPINFO block")
+	}
+}
+
+func testFLHA(t *testing.T, data *slha.SLHA) {
+	t.Helper()
+
+	info, err := data.FCInfo()
+	if err != nil {
+		t.Fatalf("could not read FCINFO: %+v", err)
+	}
+	want := slha.SPInfo{
+		Name:     "MyFlavourCode",
+		Version:  "2.1",
+		Warnings: []string{"B -> K* mu mu: large uncertainties"},
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Fatalf("invalid FCINFO:\ngot= %#v\nwant=%#v", info, want)
+	}
+
+	sel, err := data.FModSel()
+	if err != nil {
+		t.Fatalf("could not read FMODSEL: %+v", err)
+	}
+	if got, want := sel, map[int]int{1: 0, 5: 0}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid FMODSEL:\ngot= %v\nwant=%v", got, want)
+	}
+
+	mb, err := data.FMass(5)
+	if err != nil {
+		t.Fatalf("could not read FMASS: %+v", err)
+	}
+	if got, want := mb, []slha.FMass{
+		{Mass: 4.2, Scheme: 1, Scale: 4.2},
+		{Mass: 4.68, Scheme: 3, Scale: 0},
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid FMASS:\ngot= %#v\nwant=%#v", got, want)
+	}
+
+	_, err = data.FMass(42)
+	if err == nil {
+		t.Fatalf("expected an error for a missing FMASS entry")
+	}
+
+	tau, err := data.FLife(531)
+	if err != nil {
+		t.Fatalf("could not read FLIFE: %+v", err)
+	}
+	if got, want := tau, 1.472e-12; got != want {
+		t.Fatalf("invalid FLIFE: got=%v, want=%v", got, want)
+	}
+
+	for _, tc := range []struct {
+		n    int
+		want float64
+	}{
+		{1, 0.23},
+		{11, 1.30},
+	} {
+		got, err := data.FConst(531, tc.n)
+		if err != nil {
+			t.Fatalf("could not read FCONST[531,%d]: %+v", tc.n, err)
+		}
+		if got != tc.want {
+			t.Fatalf("invalid FCONST[531,%d]: got=%v, want=%v", tc.n, got, tc.want)
+		}
+	}
+
+	_, err = data.FConst(531, 2)
+	if err == nil {
+		t.Fatalf("expected an error for a missing FCONST entry")
+	}
+
+	obs, err := data.FObs()
+	if err != nil {
+		t.Fatalf("could not read FOBS: %+v", err)
+	}
+	if got, want := obs, []slha.FObs{
+		{Parent: 5, Type: 1, Value: 2.83e-4, IDs: []int{3, 22}, Comment: "BR(b -> s gamma)"},
+		{Parent: 531, Type: 1, Value: 3.0e-9, IDs: []int{-13, 13}, Comment: "BR(Bs -> mu+ mu-)"},
+		{Parent: 521, Type: 4, Value: 8.9e-2, IDs: []int{313, 22}, Comment: "Delta0(B -> K* gamma)"},
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("invalid FOBS:\ngot= %#v\nwant=%#v", got, want)
+	}
+}